@@ -1,15 +1,29 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log/slog"
-	"time"
 
+	adminhandler "github.com/FACorreiaa/skillsphere-api/internal/domain/admin/handler"
 	"github.com/FACorreiaa/skillsphere-api/internal/domain/auth/handler"
 	"github.com/FACorreiaa/skillsphere-api/internal/domain/auth/repository"
 	"github.com/FACorreiaa/skillsphere-api/internal/domain/auth/service"
+	flagrepo "github.com/FACorreiaa/skillsphere-api/internal/domain/featureflag/repository"
+	flagservice "github.com/FACorreiaa/skillsphere-api/internal/domain/featureflag/service"
+	matchinghandler "github.com/FACorreiaa/skillsphere-api/internal/domain/matching/handler"
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/ranking"
+	matchingrepo "github.com/FACorreiaa/skillsphere-api/internal/domain/matching/repository"
+	matchingservice "github.com/FACorreiaa/skillsphere-api/internal/domain/matching/service"
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/vectors"
+	settingsrepo "github.com/FACorreiaa/skillsphere-api/internal/domain/settings/repository"
+	settingsservice "github.com/FACorreiaa/skillsphere-api/internal/domain/settings/service"
+	taxonomyrepo "github.com/FACorreiaa/skillsphere-api/internal/domain/taxonomy/repository"
+	taxonomyservice "github.com/FACorreiaa/skillsphere-api/internal/domain/taxonomy/service"
+	"github.com/FACorreiaa/skillsphere-api/internal/embedding"
 	"github.com/FACorreiaa/skillsphere-api/internal/ontology"
+	"github.com/FACorreiaa/skillsphere-api/internal/vectorsearch"
 	"github.com/FACorreiaa/skillsphere-api/pkg/config"
 	"github.com/FACorreiaa/skillsphere-api/pkg/db"
 )
@@ -21,18 +35,46 @@ type Dependencies struct {
 	Logger *slog.Logger
 
 	OntologyEmitter ontology.Emitter
+	// OntologyIndex answers admin SPARQL queries; nil unless ontology.query_index is set.
+	OntologyIndex *ontology.IndexFeed
 
-	sqlDB *sql.DB
+	// sqlDB is the primary handle of sqlRouted, for repositories that must
+	// not read from replicas.
+	sqlDB     *sql.DB
+	sqlRouted *db.SQL
+
+	// stopBackground cancels long-running workers such as cache listeners.
+	stopBackground context.CancelFunc
 
 	// Repositories
-	AuthRepo repository.AuthRepository
+	AuthRepo     repository.AuthRepository
+	FlagRepo     flagrepo.FlagRepository
+	SettingsRepo settingsrepo.SettingsRepository
+	TaxonomyRepo taxonomyrepo.TaxonomyRepository
+	MatchingRepo matchingrepo.RecommendationRepository
 
 	// Services
 	TokenManager service.TokenManager
 	AuthService  *service.AuthService
+	FeatureFlags *flagservice.Service
+	Settings     *settingsservice.Service
+	Taxonomy     *taxonomyservice.Service
+	Matching     *matchingservice.Service
+	SkillVectors *vectors.Refresher
+	// Recommendations recomputes cached recommendations queued by profile edits.
+	Recommendations *matchingservice.RecommendationRefresher
+	Embedder        *embedding.Embedder
+	Reembedder      *embedding.Reembedder
+	UserIndex       vectorsearch.Index
+	// UserIndexLoader feeds the memory backend; nil for pgvector.
+	UserIndexLoader *vectorsearch.Loader
 
 	// Handlers
-	AuthHandler *handler.AuthHandler
+	AuthHandler          *handler.AuthHandler
+	AdminHandler         *adminhandler.AdminHandler
+	OntologyQueryHandler *adminhandler.OntologyQueryHandler
+	SkillTaxonomyHandler *adminhandler.SkillTaxonomyHandler
+	MatchingHandler      *matchinghandler.MatchingHandler
 }
 
 // InitDependencies initializes all application dependencies
@@ -54,6 +96,9 @@ func InitDependencies(cfg *config.Config, logger *slog.Logger) (*Dependencies, e
 	}
 
 	deps.initOntologyEmitter()
+	if err := deps.initOntologyIndex(); err != nil {
+		return nil, fmt.Errorf("failed to init ontology index: %w", err)
+	}
 
 	// Initialize handler
 	if err := deps.initServices(); err != nil {
@@ -74,10 +119,16 @@ func InitDependencies(cfg *config.Config, logger *slog.Logger) (*Dependencies, e
 func (d *Dependencies) initDatabase() error {
 	database, err := db.New(db.Config{
 		DSN:             d.Config.Database.DSN(),
-		MaxConns:        25,
-		MinConns:        5,
-		MaxConnLifetime: 5 * time.Minute,
-		MaxConnIdleTime: 10 * time.Minute,
+		MaxConns:        d.Config.Database.MaxConns,
+		MinConns:        d.Config.Database.MinConns,
+		MaxConnLifetime: d.Config.Database.MaxConnLifetime,
+		MaxConnIdleTime: d.Config.Database.MaxConnIdleTime,
+
+		SlowQueryThreshold: d.Config.Database.SlowQueryThreshold,
+
+		ReplicaDSNs:          d.Config.Database.ReplicaURLs,
+		MaxReplicaLag:        d.Config.Database.MaxReplicaLag,
+		ReadYourWritesWindow: d.Config.Database.ReadYourWritesWindow,
 	}, d.Logger)
 	if err != nil {
 		return err
@@ -96,16 +147,21 @@ func (d *Dependencies) initDatabase() error {
 
 // initRepositories initializes all repository layer dependencies
 func (d *Dependencies) initRepositories() error {
-	sqlDB, err := sql.Open("pgx", d.Config.Database.DSN())
-	if err != nil {
-		return fmt.Errorf("failed to open sql DB: %w", err)
-	}
+	// Share the pgx pools so repository queries are traced and counted in
+	// pool stats. Auth, flags, settings and the outbox stay on the primary;
+	// the rest read from replicas when they are configured.
+	d.sqlRouted = d.DB.SQL()
+	sqlDB := d.sqlRouted.Primary()
 	if err := sqlDB.Ping(); err != nil {
 		return fmt.Errorf("failed to ping sql DB: %w", err)
 	}
 
 	d.sqlDB = sqlDB
 	d.AuthRepo = repository.NewPostgresAuthRepository(sqlDB)
+	d.FlagRepo = flagrepo.NewPostgresFlagRepository(sqlDB)
+	d.SettingsRepo = settingsrepo.NewPostgresSettingsRepository(sqlDB)
+	d.TaxonomyRepo = taxonomyrepo.NewPostgresTaxonomyRepository(d.sqlRouted)
+	d.MatchingRepo = matchingrepo.NewPostgresRecommendationRepository(d.sqlRouted)
 
 	d.Logger.Info("repositories initialized")
 	return nil
@@ -123,6 +179,23 @@ func (d *Dependencies) initOntologyEmitter() {
 	d.OntologyEmitter = emitter
 }
 
+// initOntologyIndex builds the in-memory graph behind the admin SPARQL endpoint.
+// It is filled in the background by startBackgroundWorkers.
+func (d *Dependencies) initOntologyIndex() error {
+	if !d.Config.Ontology.QueryIndex || d.sqlDB == nil {
+		return nil
+	}
+	index, err := ontology.NewGraphIndex()
+	if err != nil {
+		return err
+	}
+	d.OntologyIndex = ontology.NewIndexFeed(d.sqlDB, index,
+		ontology.WithFeedInterval(d.Config.Ontology.QueryIndexInterval),
+		ontology.WithFeedLogger(d.Logger),
+	)
+	return nil
+}
+
 // initServices initializes all service layer dependencies
 func (d *Dependencies) initServices() error {
 	jwtSecret := []byte(d.Config.Auth.JWTSecret)
@@ -130,11 +203,91 @@ func (d *Dependencies) initServices() error {
 		return fmt.Errorf("jwt secret is required")
 	}
 
-	accessTokenTTL := 15 * time.Minute
-	refreshTokenTTL := 30 * 24 * time.Hour
+	accessTokenTTL := d.Config.Auth.AccessTokenTTL
+	refreshTokenTTL := d.Config.Auth.RefreshTokenTTL
 
 	d.TokenManager = service.NewTokenManager(jwtSecret, jwtSecret, accessTokenTTL, refreshTokenTTL)
-	emailService := service.NewEmailService()
+
+	oauth := d.Config.OAuth
+	if oauth.GoogleClientID != "" || oauth.AppleClientID != "" {
+		if err := service.InitOAuth(service.OAuthConfig{
+			GoogleClientID:     oauth.GoogleClientID,
+			GoogleClientSecret: oauth.GoogleClientSecret,
+			AppleClientID:      oauth.AppleClientID,
+			AppleSecret:        oauth.AppleSecret,
+			AppleTeamID:        oauth.AppleTeamID,
+			AppleKeyID:         oauth.AppleKeyID,
+			CallbackURL:        oauth.CallbackURL,
+			SessionSecret:      oauth.SessionSecret,
+		}); err != nil {
+			return fmt.Errorf("failed to init oauth: %w", err)
+		}
+	}
+
+	emailService := service.NewEmailService(service.SMTPConfig{
+		Host:        d.Config.Email.SMTPHost,
+		Port:        d.Config.Email.SMTPPort,
+		Username:    d.Config.Email.SMTPUsername,
+		Password:    d.Config.Email.SMTPPassword,
+		FromEmail:   d.Config.Email.FromEmail,
+		FromName:    d.Config.Email.FromName,
+		FrontendURL: d.Config.Email.FrontendURL,
+	})
+	d.FeatureFlags = flagservice.NewService(d.FlagRepo, d.Logger)
+	if err := d.FeatureFlags.Refresh(context.Background()); err != nil {
+		return err
+	}
+
+	d.Settings = settingsservice.NewService(d.SettingsRepo, d.Logger)
+	if err := d.Settings.Refresh(context.Background()); err != nil {
+		return err
+	}
+	d.Settings.OnChange(func(old, updated settingsservice.Settings) {
+		if old.MaintenanceMode != updated.MaintenanceMode {
+			d.Logger.Warn("maintenance mode changed", "enabled", updated.MaintenanceMode)
+		}
+		if old.NewSignupsEnabled != updated.NewSignupsEnabled {
+			d.Logger.Info("signup gating changed", "new_signups_enabled", updated.NewSignupsEnabled)
+		}
+	})
+
+	d.Taxonomy = taxonomyservice.NewService(d.TaxonomyRepo, d.Logger)
+	d.Matching = matchingservice.NewService(d.MatchingRepo, d.OntologyEmitter, d.Logger)
+	d.Matching.SetSkillExpander(d.Taxonomy)
+	d.Matching.SetAvailabilityPolicy(matchingservice.AvailabilityPolicy{
+		Weight:     d.Config.Matching.AvailabilityWeight,
+		Target:     d.Config.Matching.TargetOverlap,
+		MinOverlap: d.Config.Matching.MinOverlap,
+		Slots:      d.Config.Matching.SharedSlots,
+	})
+	d.SkillVectors = vectors.NewRefresher(d.sqlDB, d.Config.Matching.VectorRefreshBatchSize, d.Logger)
+	d.Matching.SetRecommendationCache(matchingrepo.NewPostgresRecommendationCache(d.sqlRouted), matchingservice.CachePolicy{
+		TTL:      d.Config.Matching.RecommendationTTL,
+		MaxStale: d.Config.Matching.RecommendationMaxStale,
+		Settle:   d.Config.Matching.RecommendationSettle,
+	})
+	d.Recommendations = matchingservice.NewRecommendationRefresher(d.Matching, d.Config.Matching.RecommendationRefreshBatchSize, d.Logger)
+	if path := d.Config.Matching.RankingModel; path != "" {
+		model, err := ranking.Load(path)
+		if err != nil {
+			return err
+		}
+		d.Matching.SetRanker(model, d.FeatureFlags)
+		d.Logger.Info("ranking model loaded", "version", model.Version, "examples", model.Examples)
+	}
+
+	provider, err := d.embeddingProvider()
+	if err != nil {
+		return err
+	}
+	d.Embedder = embedding.NewEmbedder(provider, embedding.NewMemoryCache(d.Config.Embedding.CacheSize), d.Config.Embedding.BatchSize)
+	d.Reembedder = embedding.NewReembedder(embedding.NewPostgresStore(d.sqlDB), d.Embedder, d.Config.Embedding.BatchSize, d.Logger)
+	d.Matching.SetEmbedder(d.Embedder)
+	if err := d.initUserIndex(); err != nil {
+		return err
+	}
+	d.Matching.SetUserIndex(d.UserIndex)
+
 	d.AuthService = service.NewAuthService(
 		d.AuthRepo,
 		d.TokenManager,
@@ -143,25 +296,90 @@ func (d *Dependencies) initServices() error {
 		d.OntologyEmitter,
 		refreshTokenTTL,
 	)
+	d.AuthService.SetSignupGate(d.Settings)
+
+	d.startBackgroundWorkers()
 
 	d.Logger.Info("services initialized")
 	return nil
 }
 
+// embeddingProvider builds the configured embedding backend.
+func (d *Dependencies) embeddingProvider() (embedding.Provider, error) {
+	cfg := d.Config.Embedding
+	switch cfg.Provider {
+	case "gemini":
+		return embedding.NewGemini(embedding.GeminiConfig{
+			APIKey:  cfg.GeminiAPIKey,
+			Model:   cfg.Model,
+			BaseURL: cfg.GeminiBaseURL,
+			Timeout: cfg.Timeout,
+		})
+	default:
+		return embedding.NewLocal(), nil
+	}
+}
+
+// initUserIndex builds the configured nearest-neighbour index over
+// users.embedding.
+func (d *Dependencies) initUserIndex() error {
+	cfg := d.Config.VectorSearch
+	metric, err := vectorsearch.ParseMetric(cfg.Metric)
+	if err != nil {
+		return err
+	}
+	if cfg.Backend == "memory" {
+		index := vectorsearch.NewHNSW(metric, vectorsearch.HNSWConfig{
+			M:              cfg.HNSWM,
+			EfConstruction: cfg.HNSWEfConstruction,
+			EfSearch:       cfg.HNSWEfSearch,
+		})
+		d.UserIndex = index
+		d.UserIndexLoader = vectorsearch.NewLoader(d.sqlRouted, index, embedding.TargetUsers, d.Logger)
+		return nil
+	}
+	d.UserIndex, err = vectorsearch.NewPostgres(d.sqlRouted, embedding.TargetUsers, metric, cfg.Probes)
+	return err
+}
+
+// startBackgroundWorkers launches workers that live until Cleanup.
+func (d *Dependencies) startBackgroundWorkers() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.stopBackground = cancel
+
+	go d.FeatureFlags.Run(ctx, d.DB)
+	go d.Settings.Run(ctx, d.DB)
+	go d.SkillVectors.Run(ctx, d.Config.Matching.VectorRefreshInterval)
+	go d.Recommendations.Run(ctx, d.Config.Matching.RecommendationRefreshInterval)
+	go d.Reembedder.Run(ctx, d.Config.Embedding.ReembedInterval)
+	if d.UserIndexLoader != nil {
+		go d.UserIndexLoader.Run(ctx, d.Config.VectorSearch.SyncInterval)
+	}
+	if d.OntologyIndex != nil {
+		go d.OntologyIndex.Run(ctx)
+	}
+}
+
 // initHandlers initializes all handler dependencies
 func (d *Dependencies) initHandlers() error {
 	d.AuthHandler = handler.NewAuthHandler(d.AuthService)
+	d.AdminHandler = adminhandler.NewAdminHandler(d.FeatureFlags, d.Settings)
+	d.SkillTaxonomyHandler = adminhandler.NewSkillTaxonomyHandler(d.Taxonomy)
+	d.MatchingHandler = matchinghandler.NewMatchingHandler(d.Matching)
+	if d.OntologyIndex != nil {
+		d.OntologyQueryHandler = adminhandler.NewOntologyQueryHandler(d.OntologyIndex, d.Config.Ontology.QueryTimeout)
+	}
 	d.Logger.Info("handlers initialized")
 	return nil
 }
 
 // Cleanup closes all resources
 func (d *Dependencies) Cleanup() {
+	if d.stopBackground != nil {
+		d.stopBackground()
+	}
 	if d.DB != nil {
 		d.DB.Close()
 	}
-	if d.sqlDB != nil {
-		d.sqlDB.Close()
-	}
 	d.Logger.Info("cleanup completed")
 }
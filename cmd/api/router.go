@@ -5,11 +5,15 @@ import (
 
 	"connectrpc.com/connect"
 	"connectrpc.com/validate"
+	adminv1connect "github.com/FACorreiaa/skillsphere-proto/gen/go/admin/v1/adminv1connect"
 	authv1connect "github.com/FACorreiaa/skillsphere-proto/gen/go/auth/v1/authv1connect"
+	matchingv1connect "github.com/FACorreiaa/skillsphere-proto/gen/go/matching/v1/matchingv1connect"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/otel"
 	"golang.org/x/time/rate"
 
+	adminhandler "github.com/FACorreiaa/skillsphere-api/internal/domain/admin/handler"
+	matchinghandler "github.com/FACorreiaa/skillsphere-api/internal/domain/matching/handler"
 	"github.com/FACorreiaa/skillsphere-api/pkg/interceptors"
 	"github.com/FACorreiaa/skillsphere-api/pkg/observability"
 )
@@ -18,10 +22,8 @@ import (
 func SetupRouter(deps *Dependencies) http.Handler {
 	mux := http.NewServeMux()
 
+	// config.ValidateAPI requires the secret.
 	jwtSecret := []byte(deps.Config.Auth.JWTSecret)
-	if len(jwtSecret) == 0 {
-		deps.Logger.Warn("JWT secret is empty; authentication interceptor will reject requests")
-	}
 
 	publicProcedures := []string{
 		authv1connect.AuthServiceRegisterProcedure,
@@ -45,6 +47,14 @@ func SetupRouter(deps *Dependencies) http.Handler {
 		rateLimiter = interceptors.NewRateLimitInterceptor(limiter)
 	}
 
+	// Admins must be able to sign in and reach AdminService to lift maintenance mode.
+	maintenanceInterceptor := interceptors.NewMaintenanceInterceptor(
+		deps.Settings.Maintenance,
+		"/"+adminv1connect.AdminServiceName+"/",
+		authv1connect.AuthServiceLoginProcedure,
+		authv1connect.AuthServiceRefreshTokenProcedure,
+	)
+
 	requestIDInterceptor := interceptors.NewRequestIDInterceptor("X-Request-ID")
 	tracingInterceptor := interceptors.NewTracingInterceptor(tracer)
 	validationInterceptor := validate.NewInterceptor()
@@ -57,6 +67,7 @@ func SetupRouter(deps *Dependencies) http.Handler {
 		rateLimiter,
 		interceptors.NewRecoveryInterceptor(deps.Logger),
 		interceptors.NewLoggingInterceptor(deps.Logger),
+		maintenanceInterceptor,
 		interceptors.NewAuthInterceptor(jwtSecret, publicProcedures...),
 		observability.NewMetricsInterceptor(),
 	)
@@ -79,6 +90,61 @@ func registerConnectRoutes(mux *http.ServeMux, deps *Dependencies, opts connect.
 	mux.Handle(authServicePath, authServiceHandler)
 	deps.Logger.Info("registered Connect RPC service", "path", authServicePath)
 
+	matchingServicePath, matchingServiceHandler := matchingv1connect.NewMatchingServiceHandler(
+		deps.MatchingHandler,
+		opts,
+	)
+	mux.Handle(matchingServicePath, matchingServiceHandler)
+	deps.Logger.Info("registered Connect RPC service", "path", matchingServicePath)
+
+	skipPath, skipHandler := matchinghandler.NewSkipRecommendedPartnerHandler(deps.MatchingHandler, opts)
+	mux.Handle(skipPath, skipHandler)
+	deps.Logger.Info("registered Connect RPC procedure", "path", skipPath)
+
+	viewPath, viewHandler := matchinghandler.NewRecordProfileViewHandler(deps.MatchingHandler, opts)
+	mux.Handle(viewPath, viewHandler)
+	deps.Logger.Info("registered Connect RPC procedure", "path", viewPath)
+
+	explainPath, explainHandler := matchinghandler.NewGetMatchExplanationsHandler(deps.MatchingHandler, opts)
+	mux.Handle(explainPath, explainHandler)
+	deps.Logger.Info("registered Connect RPC procedure", "path", explainPath)
+
+	adminServicePath, adminServiceHandler := adminv1connect.NewAdminServiceHandler(
+		deps.AdminHandler,
+		opts,
+		connect.WithInterceptors(interceptors.NewRoleAuthInterceptor("admin")),
+	)
+	mux.Handle(adminServicePath, adminServiceHandler)
+	deps.Logger.Info("registered Connect RPC service", "path", adminServicePath)
+
+	rolloutPath, rolloutHandler := adminhandler.NewSetFeatureFlagRolloutHandler(
+		deps.AdminHandler,
+		opts,
+		connect.WithInterceptors(interceptors.NewRoleAuthInterceptor("admin")),
+	)
+	mux.Handle(rolloutPath, rolloutHandler)
+	deps.Logger.Info("registered Connect RPC procedure", "path", rolloutPath)
+
+	if deps.OntologyQueryHandler != nil {
+		queryPath, queryHandler := adminhandler.NewQueryOntologyHandler(
+			deps.OntologyQueryHandler,
+			opts,
+			connect.WithInterceptors(interceptors.NewRoleAuthInterceptor("admin")),
+		)
+		mux.Handle(queryPath, queryHandler)
+		deps.Logger.Info("registered Connect RPC procedure", "path", queryPath)
+	}
+
+	taxonomyHandlers := adminhandler.NewSkillTaxonomyHandlers(
+		deps.SkillTaxonomyHandler,
+		opts,
+		connect.WithInterceptors(interceptors.NewRoleAuthInterceptor("admin")),
+	)
+	for path, handler := range taxonomyHandlers {
+		mux.Handle(path, handler)
+		deps.Logger.Info("registered Connect RPC procedure", "path", path)
+	}
+
 	deps.Logger.Info("Connect RPC routes configured")
 }
 
@@ -3,17 +3,43 @@ package main
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/FACorreiaa/skillsphere-api/internal/ontology"
 	"github.com/FACorreiaa/skillsphere-api/pkg/config"
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+	"github.com/FACorreiaa/skillsphere-api/pkg/kafka"
+	"github.com/FACorreiaa/skillsphere-api/pkg/observability"
 )
 
+const usage = `usage:
+  ontologyworker                                 run the outbox processor
+  ontologyworker deadletters list [-limit N]     print dead-lettered events as JSON lines
+  ontologyworker deadletters replay [-all] ID…   requeue dead-lettered events
+  ontologyworker deadletters purge [-all] ID…    delete dead-lettered events
+  ontologyworker quarantine list [-limit N]      print quarantined events and their validation reports
+  ontologyworker quarantine release [-all] ID…   revalidate quarantined events on the next batch
+  ontologyworker quarantine purge [-all] ID…     delete quarantined events
+  ontologyworker backfill [flags]                replay existing users, sessions and matches as events
+      -source users,sessions,matches             sources to replay (default all)
+      -direct                                    send to Kafka and the triple store instead of the outbox
+      -batch N -rate N                           rows per page and checkpoint, events per second (0 = unlimited)
+      -restart                                   discard checkpoints and start from the first row
+      -rebuild                                   drop the sources' graphs first; requires -source
+`
+
 func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{}))
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
@@ -25,28 +51,319 @@ func main() {
 		os.Exit(1)
 	}
 
-	db, err := sql.Open("pgx", cfg.Database.DSN())
+	database, err := db.New(db.Config{
+		DSN:                cfg.Database.DSN(),
+		MaxConns:           cfg.Database.MaxConns,
+		MinConns:           cfg.Database.MinConns,
+		MaxConnLifetime:    cfg.Database.MaxConnLifetime,
+		MaxConnIdleTime:    cfg.Database.MaxConnIdleTime,
+		SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+	}, logger)
 	if err != nil {
 		logger.Error("open database", "error", err)
 		os.Exit(1)
 	}
-	defer db.Close()
+	defer database.Close()
+	sqlDB := stdlib.OpenDBFromPool(database.Pool)
+	defer sqlDB.Close()
+
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "backfill" {
+		if err := runBackfill(ctx, cfg.Ontology, sqlDB, logger, args[1:]); err != nil {
+			logger.Error("backfill failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(args) > 0 {
+		if len(args) < 2 {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		var err error
+		switch args[0] {
+		case "deadletters":
+			store := ontology.NewDeadLetterStore(sqlDB)
+			err = runStoreCommand(ctx, args[1], args[2:], store.List, map[string]storeAction{
+				"replay": store.Replay,
+				"purge":  store.Purge,
+			})
+		case "quarantine":
+			store := ontology.NewQuarantineStore(sqlDB)
+			err = runStoreCommand(ctx, args[1], args[2:], store.List, map[string]storeAction{
+				"release": store.Release,
+				"purge":   store.Purge,
+			})
+		default:
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		if err != nil {
+			logger.Error("outbox command failed", "command", args[0]+" "+args[1], "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	kafkaProducer, closeProducer, err := newKafkaProducer(cfg.Ontology, logger)
+	if err != nil {
+		logger.Error("create kafka producer", "error", err)
+		os.Exit(1)
+	}
+	defer closeProducer()
+
+	var tripleClient ontology.TripleStoreClient
+	if store, err := newTripleStoreClient(cfg.Ontology); err != nil {
+		logger.Error("create triple store client", "error", err)
+		os.Exit(1)
+	} else if store != nil {
+		tripleClient = store
+	}
+
+	opts := []ontology.OutboxOption{
+		ontology.WithOutboxLogger(logger),
+		ontology.WithRetryPolicy(ontology.RetryPolicy{
+			MaxAttempts: cfg.Ontology.MaxAttempts,
+			BaseDelay:   cfg.Ontology.RetryBaseDelay,
+			MaxDelay:    cfg.Ontology.RetryMaxDelay,
+		}),
+		ontology.WithBatchSize(cfg.Ontology.BatchSize),
+		ontology.WithConcurrency(cfg.Ontology.Concurrency),
+		ontology.WithLease(cfg.Ontology.LeaseDuration),
+	}
+	if cfg.Ontology.ValidateShapes {
+		validator, err := ontology.DefaultValidator()
+		if err != nil {
+			logger.Error("load SHACL shapes", "error", err)
+			os.Exit(1)
+		}
+		opts = append(opts, ontology.WithValidator(validator))
+	}
+
+	if cfg.Ontology.Retention > 0 {
+		retention := ontology.NewOutboxRetention(sqlDB, cfg.Ontology.Retention, cfg.Ontology.RetentionArchive, logger)
+		go func() {
+			if err := retention.Run(ctx, cfg.Ontology.RetentionInterval); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("ontology outbox retention stopped", "error", err)
+			}
+		}()
+	}
 
-	kafkaTopic := getenv("ONTOLOGY_KAFKA_TOPIC", "skillsphere.ontology")
-	kafkaProducer := ontology.NewLogProducer(logger)
-	tripleStoreEndpoint := os.Getenv("ONTOLOGY_TRIPLESTORE_ENDPOINT")
-	tripleClient := ontology.NewHTTPTripleStoreClient(tripleStoreEndpoint)
+	if cfg.Ontology.Listen {
+		opts = append(opts, ontology.WithNotifier(database))
+	}
 
-	processor := ontology.NewOutboxProcessor(db, kafkaTopic, kafkaProducer, tripleClient)
-	if err := processor.Run(ctx, 2*time.Second); err != nil {
+	processor := ontology.NewOutboxProcessor(sqlDB, cfg.Ontology.KafkaTopic, kafkaProducer, tripleClient, opts...)
+	if cfg.Observability.MetricsEnabled {
+		if err := observability.RegisterOutboxStats(processor.Stats); err != nil {
+			logger.Error("register outbox metrics", "error", err)
+			os.Exit(1)
+		}
+		go serveMetrics(cfg.Observability.MetricsPort, logger)
+	}
+	if err := processor.Run(ctx, cfg.Ontology.PollInterval); err != nil && !errors.Is(err, context.Canceled) {
 		logger.Error("ontology worker exited", "error", err)
 		os.Exit(1)
 	}
 }
 
-func getenv(key, fallback string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// serveMetrics exposes /metrics for Prometheus on the given port.
+func serveMetrics(port int, logger *slog.Logger) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	addr := fmt.Sprintf(":%d", port)
+	logger.Info("metrics server started", "addr", addr)
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("metrics server error", "error", err)
+	}
+}
+
+// newKafkaProducer returns the wire producer when brokers are configured and the
+// logging producer otherwise, along with a func that flushes it on shutdown.
+func newKafkaProducer(cfg config.OntologyConfig, logger *slog.Logger) (ontology.KafkaProducer, func(), error) {
+	if len(cfg.KafkaBrokers) == 0 {
+		logger.Warn("ONTOLOGY_KAFKA_BROKERS not set; ontology events will only be logged")
+		return ontology.NewLogProducer(logger), func() {}, nil
+	}
+
+	acks, err := kafka.ParseAcks(cfg.KafkaAcks)
+	if err != nil {
+		return nil, nil, err
+	}
+	compression, err := kafka.ParseCompression(cfg.KafkaCompression)
+	if err != nil {
+		return nil, nil, err
+	}
+	producer, err := kafka.NewProducer(kafka.Config{
+		Brokers:        cfg.KafkaBrokers,
+		ClientID:       cfg.KafkaClientID,
+		Acks:           acks,
+		Compression:    compression,
+		Linger:         cfg.KafkaLinger,
+		BatchMaxBytes:  cfg.KafkaBatchMaxBytes,
+		RequestTimeout: cfg.KafkaRequestTimeout,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return producer, func() {
+		if err := producer.Close(); err != nil {
+			logger.Error("close kafka producer", "error", err)
+		}
+	}, nil
+}
+
+// newTripleStoreClient returns nil when no triple store endpoint is configured.
+func newTripleStoreClient(cfg config.OntologyConfig) (*ontology.SPARQLStoreClient, error) {
+	if cfg.TripleStoreUpdateEndpoint == "" {
+		return nil, nil
+	}
+	return ontology.NewSPARQLStoreClient(ontology.SPARQLStoreConfig{
+		UpdateEndpoint: cfg.TripleStoreUpdateEndpoint,
+		GraphBase:      cfg.TripleStoreGraphBase,
+		Username:       cfg.TripleStoreUsername,
+		Password:       cfg.TripleStorePassword,
+		BearerToken:    cfg.TripleStoreToken,
+		Timeout:        cfg.TripleStoreTimeout,
+	})
+}
+
+// runBackfill replays source tables as ontology events, through the outbox by
+// default or straight to the sinks with -direct.
+func runBackfill(ctx context.Context, cfg config.OntologyConfig, sqlDB *sql.DB, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	sourceList := fs.String("source", "", "comma-separated sources to replay (default all)")
+	direct := fs.Bool("direct", false, "send events to Kafka and the triple store instead of the outbox")
+	batch := fs.Int("batch", 500, "rows per page and checkpoint")
+	perSecond := fs.Float64("rate", 100, "maximum events per second; 0 means unlimited")
+	restart := fs.Bool("restart", false, "discard checkpoints and start from the first row")
+	rebuild := fs.Bool("rebuild", false, "drop each source's named graph before replaying it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sources := ontology.BackfillSources
+	if *sourceList != "" {
+		sources = nil
+		for _, name := range strings.Split(*sourceList, ",") {
+			source, ok := ontology.LookupBackfillSource(strings.TrimSpace(name))
+			if !ok {
+				return fmt.Errorf("unknown backfill source %q", name)
+			}
+			sources = append(sources, source)
+		}
+	} else if *rebuild {
+		// Dropping every graph by accident is expensive to undo.
+		return errors.New("-rebuild requires -source")
+	}
+
+	store, err := newTripleStoreClient(cfg)
+	if err != nil {
+		return fmt.Errorf("create triple store client: %w", err)
+	}
+	if *rebuild && store == nil {
+		return errors.New("-rebuild requires ONTOLOGY_TRIPLESTORE_UPDATE_ENDPOINT")
+	}
+
+	var emitter ontology.Emitter = ontology.NewOutboxEmitter(sqlDB, logger)
+	if *direct {
+		producer, closeProducer, err := newKafkaProducer(cfg, logger)
+		if err != nil {
+			return fmt.Errorf("create kafka producer: %w", err)
+		}
+		defer closeProducer()
+
+		var validator *ontology.Validator
+		if cfg.ValidateShapes {
+			if validator, err = ontology.DefaultValidator(); err != nil {
+				return fmt.Errorf("load SHACL shapes: %w", err)
+			}
+		}
+		var triple ontology.TripleStoreClient
+		if store != nil {
+			triple = store
+		}
+		emitter = ontology.NewDirectEmitter(cfg.KafkaTopic, producer, triple, validator)
+	}
+
+	backfiller := ontology.NewBackfiller(sqlDB, emitter,
+		ontology.WithBackfillBatchSize(*batch),
+		ontology.WithBackfillRate(*perSecond),
+		ontology.WithBackfillLogger(logger))
+
+	for _, source := range sources {
+		if *rebuild {
+			if err := store.DropGraph(ctx, source.Type); err != nil {
+				return fmt.Errorf("drop %s graph: %w", source.Name, err)
+			}
+			logger.Info("dropped graph", "source", source.Name, "graph", store.GraphFor(source.Type))
+		}
+		if *restart || *rebuild {
+			if err := backfiller.Reset(ctx, source.Name); err != nil {
+				return err
+			}
+		}
+
+		result, err := backfiller.Run(ctx, source)
+		if errors.Is(err, ontology.ErrBackfillComplete) {
+			fmt.Printf("%s: already complete; pass -restart to replay it\n", source.Name)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", source.Name, err)
+		}
+		fmt.Printf("%s: %d event(s) emitted, %d rejected\n", source.Name, result.Emitted, result.Rejected)
+	}
+	return nil
+}
+
+// storeAction updates the given outbox rows, or all of them when ids is empty.
+type storeAction func(ctx context.Context, ids ...string) (int64, error)
+
+// runStoreCommand runs "list" or one of actions against a set of held-back
+// outbox rows (dead letters or quarantined events).
+func runStoreCommand[T any](ctx context.Context, command string, args []string,
+	list func(ctx context.Context, limit int) ([]T, error), actions map[string]storeAction) error {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	limit := fs.Int("limit", 100, "maximum number of events to list")
+	all := fs.Bool("all", false, "apply to every matching event")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ids := fs.Args()
+
+	if command == "list" {
+		rows, err := list(ctx, *limit)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(os.Stdout)
+		for _, row := range rows {
+			if err := enc.Encode(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	apply, ok := actions[command]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", command)
+	}
+	// Refuse to touch every row by accident when the ID list is forgotten.
+	if len(ids) == 0 && !*all {
+		return errors.New("pass event IDs or -all")
+	}
+	if len(ids) > 0 && *all {
+		return errors.New("-all cannot be combined with event IDs")
+	}
+	n, err := apply(ctx, ids...)
+	if err != nil {
+		return err
 	}
-	return fallback
+	fmt.Printf("%s: %d event(s)\n", command, n)
+	return nil
 }
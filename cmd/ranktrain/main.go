@@ -0,0 +1,149 @@
+// Command ranktrain trains the learned ranking model from recorded matches
+// and the feedback attributed to them:
+//
+//	ranktrain -days 90 -settle 168h -out ranking_model.json
+//
+// Matches shown in the window, less the last -settle during which feedback
+// may still arrive, are split by time: the latest -holdout share is kept out
+// of training and used to compare the model against ranking by score alone.
+// A model that ranks the holdout worse than score is not written unless
+// -force is set. Point MATCHING_RANKING_MODEL at the output and raise the
+// matching_learned_ranking rollout to start the experiment.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/ranking"
+	"github.com/FACorreiaa/skillsphere-api/pkg/config"
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+)
+
+type options struct {
+	days    int
+	settle  time.Duration
+	holdout float64
+	out     string
+	force   bool
+	train   ranking.TrainOptions
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts, err := parseFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := run(ctx, opts); err != nil {
+		fmt.Fprintln(os.Stderr, "ranktrain:", err)
+		os.Exit(1)
+	}
+}
+
+func parseFlags(args []string) (options, error) {
+	defaults := ranking.DefaultTrainOptions("v" + time.Now().UTC().Format("20060102"))
+	fs := flag.NewFlagSet("ranktrain", flag.ContinueOnError)
+	days := fs.Int("days", 90, "days of matches to learn from")
+	settle := fs.Duration("settle", 7*24*time.Hour, "leave out matches younger than this, still collecting feedback")
+	holdout := fs.Float64("holdout", 0.2, "share of the latest matches held out for evaluation")
+	out := fs.String("out", "ranking_model.json", "file to write the model to")
+	force := fs.Bool("force", false, "write the model even if it ranks the holdout worse than score")
+	version := fs.String("version", defaults.Version, "model version recorded with matches")
+	epochs := fs.Int("epochs", defaults.Epochs, "gradient descent epochs")
+	lr := fs.Float64("lr", defaults.LearningRate, "learning rate")
+	l2 := fs.Float64("l2", defaults.L2, "L2 penalty on weights")
+	if err := fs.Parse(args); err != nil {
+		return options{}, err
+	}
+
+	opts := options{days: *days, settle: *settle, holdout: *holdout, out: *out, force: *force, train: defaults}
+	opts.train.Version, opts.train.Epochs, opts.train.LearningRate, opts.train.L2 = *version, *epochs, *lr, *l2
+	if opts.days <= 0 || opts.settle < 0 {
+		return opts, errors.New("-days must be positive and -settle not negative")
+	}
+	if opts.holdout <= 0 || opts.holdout >= 1 {
+		return opts, errors.New("-holdout must be between 0 and 1")
+	}
+	return opts, nil
+}
+
+func run(ctx context.Context, opts options) error {
+	sqlDB, closeDB, err := open()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	until := time.Now().Add(-opts.settle)
+	from := until.AddDate(0, 0, -opts.days)
+	examples, err := ranking.LoadExamples(ctx, sqlDB, from, until)
+	if err != nil {
+		return err
+	}
+	split := len(examples) - int(float64(len(examples))*opts.holdout)
+	train, holdout := examples[:split], examples[split:]
+	var positives int
+	for _, e := range examples {
+		if e.Label >= 0.5 {
+			positives++
+		}
+	}
+	fmt.Printf("%d matches from %s to %s, %d with engagement; training on %d, holding out %d\n",
+		len(examples), from.Format(time.DateOnly), until.Format(time.DateOnly), positives, len(train), len(holdout))
+
+	model, err := ranking.Train(train, opts.train)
+	if err != nil {
+		return err
+	}
+	evaluated, baseline := ranking.Evaluate(model, holdout), ranking.Baseline(ranking.FeatureScore, holdout)
+	fmt.Printf("model %s: holdout AUC %.3f, log-loss %.3f\n", model.Version, evaluated.AUC, evaluated.LogLoss)
+	fmt.Printf("score baseline: holdout AUC %.3f\n", baseline.AUC)
+	for i, name := range model.Features {
+		fmt.Printf("  %-16s %+.3f\n", name, model.Weights[i])
+	}
+
+	if evaluated.AUC < baseline.AUC && !opts.force {
+		return errors.New("model ranks the holdout worse than score; not written (use -force to write it anyway)")
+	}
+	if err := model.Save(opts.out); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s\n", opts.out)
+	return nil
+}
+
+func open() (*sql.DB, func(), error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	database, err := db.New(db.Config{
+		DSN:                cfg.Database.DSN(),
+		MaxConns:           cfg.Database.MaxConns,
+		MinConns:           cfg.Database.MinConns,
+		MaxConnLifetime:    cfg.Database.MaxConnLifetime,
+		MaxConnIdleTime:    cfg.Database.MaxConnIdleTime,
+		SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+	}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		return nil, nil, err
+	}
+	sqlDB := stdlib.OpenDBFromPool(database.Pool)
+	return sqlDB, func() {
+		sqlDB.Close()
+		database.Close()
+	}, nil
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"log/slog"
 	"net/http"
@@ -10,7 +13,6 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/joho/godotenv"
 
@@ -19,10 +21,26 @@ import (
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
+	printConfig := flag.Bool("print-config", false, "print the effective configuration with secrets redacted and exit")
+	flag.Parse()
+
+	// .env is an optional layer for local development; deployments set the environment directly.
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		slog.Warn("Error loading .env file")
 		log.Fatal(err)
 	}
+
+	if *printConfig {
+		cfg, err := config.LoadAPI()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := cfg.WriteRedacted(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Initialize logger
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
@@ -31,7 +49,7 @@ func main() {
 	logger.Info("starting skillsphere API")
 
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.LoadAPI()
 	if err != nil {
 		logger.Error("failed to load config", "error", err)
 		os.Exit(1)
@@ -91,9 +109,9 @@ func runServer(cfg *config.Config, logger *slog.Logger, handler http.Handler) er
 	srv := &http.Server{
 		Addr:         addr,
 		Handler:      handler,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
 	// Start server in goroutine
@@ -114,7 +132,7 @@ func runServer(cfg *config.Config, logger *slog.Logger, handler http.Handler) er
 		logger.Info("shutdown signal received", "signal", sig)
 
 		// Graceful shutdown with timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 		defer cancel()
 
 		if err := srv.Shutdown(ctx); err != nil {
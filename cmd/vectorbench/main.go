@@ -0,0 +1,261 @@
+// Command vectorbench compares nearest-neighbour backends on recall and
+// latency. It loads the embeddings of a table, or generates random ones,
+// takes exact search over them as the ground truth and runs the same queries
+// against the HNSW index at each -ef and pgvector at each -probes:
+//
+//	vectorbench -target users -k 10 -queries 200 -probes 1,10,40 -ef 16,64,256
+//	vectorbench -synthetic 20000 -dims 768
+//
+// Each query is a stored vector, excluded from its own results; for users the
+// block list of the query's owner applies too, as in GetSimilarUsers.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/embedding"
+	"github.com/FACorreiaa/skillsphere-api/internal/vectorsearch"
+	"github.com/FACorreiaa/skillsphere-api/pkg/config"
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+)
+
+type options struct {
+	target         embedding.Target
+	metric         vectorsearch.Metric
+	k              int
+	queries        int
+	probes         []int
+	ef             []int
+	m              int
+	efConstruction int
+	synthetic      int
+	dims           int
+	seed           uint64
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts, err := parseFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := run(ctx, opts); err != nil {
+		fmt.Fprintln(os.Stderr, "vectorbench:", err)
+		os.Exit(1)
+	}
+}
+
+func parseFlags(args []string) (options, error) {
+	fs := flag.NewFlagSet("vectorbench", flag.ContinueOnError)
+	target := fs.String("target", string(embedding.TargetUsers), "table to search: users or skills")
+	metric := fs.String("metric", string(vectorsearch.Cosine), "cosine, l2 or inner_product")
+	k := fs.Int("k", 10, "neighbours per query")
+	queries := fs.Int("queries", 200, "number of queries")
+	probes := fs.String("probes", "1,10,40", "ivfflat.probes values to try")
+	ef := fs.String("ef", "16,64,256", "HNSW ef_search values to try")
+	m := fs.Int("m", vectorsearch.DefaultHNSWConfig().M, "HNSW links per node")
+	efConstruction := fs.Int("ef-construction", vectorsearch.DefaultHNSWConfig().EfConstruction, "HNSW ef_construction")
+	synthetic := fs.Int("synthetic", 0, "generate this many random vectors instead of reading the database")
+	dims := fs.Int("dims", embedding.Dimensions, "dimensions of synthetic vectors")
+	seed := fs.Uint64("seed", 1, "seed for synthetic vectors, query sampling and the HNSW graph")
+	if err := fs.Parse(args); err != nil {
+		return options{}, err
+	}
+
+	opts := options{
+		target: embedding.Target(*target), k: *k, queries: *queries, m: *m,
+		efConstruction: *efConstruction, synthetic: *synthetic, dims: *dims, seed: *seed,
+	}
+	var err error
+	if opts.metric, err = vectorsearch.ParseMetric(*metric); err != nil {
+		return opts, err
+	}
+	if opts.probes, err = ints(*probes); err != nil {
+		return opts, fmt.Errorf("-probes: %w", err)
+	}
+	if opts.ef, err = ints(*ef); err != nil {
+		return opts, fmt.Errorf("-ef: %w", err)
+	}
+	if opts.k <= 0 || opts.queries <= 0 {
+		return opts, errors.New("-k and -queries must be positive")
+	}
+	return opts, nil
+}
+
+func run(ctx context.Context, opts options) error {
+	exact := vectorsearch.NewExact(opts.metric)
+	var sqlDB *db.SQL
+	if opts.synthetic > 0 {
+		if err := generate(exact, opts); err != nil {
+			return err
+		}
+	} else {
+		var closeDB func()
+		var err error
+		if sqlDB, closeDB, err = open(); err != nil {
+			return err
+		}
+		defer closeDB()
+		if _, err := vectorsearch.NewLoader(sqlDB, exact, opts.target, nil).Load(ctx); err != nil {
+			return err
+		}
+	}
+	ids := exact.IDs()
+	if len(ids) == 0 {
+		return errors.New("no embeddings to search")
+	}
+
+	graph := vectorsearch.NewHNSW(opts.metric, vectorsearch.HNSWConfig{M: opts.m, EfConstruction: opts.efConstruction, Seed: opts.seed})
+	start := time.Now()
+	if sqlDB != nil {
+		// Loading again rather than copying picks up the same blocks.
+		if _, err := vectorsearch.NewLoader(sqlDB, graph, opts.target, nil).Load(ctx); err != nil {
+			return err
+		}
+	} else {
+		for _, id := range ids {
+			vector, _ := exact.Vector(ctx, id)
+			if err := graph.Put(vectorsearch.Item{ID: id, Vector: vector}); err != nil {
+				return err
+			}
+		}
+	}
+	source := string(opts.target)
+	if opts.synthetic > 0 {
+		source = "synthetic"
+	}
+	fmt.Printf("%d %s vectors, %s, k=%d, %d queries; HNSW built in %v\n",
+		len(ids), source, opts.metric, opts.k, opts.queries, time.Since(start).Round(time.Millisecond))
+
+	queries, err := sample(ctx, exact, ids, opts)
+	if err != nil {
+		return err
+	}
+	truth, err := vectorsearch.GroundTruth(ctx, exact, queries, opts.k)
+	if err != nil {
+		return err
+	}
+
+	report := func(name string, index vectorsearch.Index) error {
+		r, err := vectorsearch.Evaluate(ctx, name, index, queries, truth, opts.k)
+		if err != nil {
+			return err
+		}
+		fmt.Println(r)
+		return nil
+	}
+	if err := report("exact", exact); err != nil {
+		return err
+	}
+	for _, ef := range opts.ef {
+		graph.SetEfSearch(ef)
+		if err := report(fmt.Sprintf("hnsw ef=%d", ef), graph); err != nil {
+			return err
+		}
+	}
+	if sqlDB == nil {
+		return nil
+	}
+	for _, probes := range opts.probes {
+		index, err := vectorsearch.NewPostgres(sqlDB, opts.target, opts.metric, probes)
+		if err != nil {
+			return err
+		}
+		if err := report(fmt.Sprintf("pgvector probes=%d", probes), index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sample picks stored vectors as queries, each excluding itself.
+func sample(ctx context.Context, index *vectorsearch.Memory, ids []uuid.UUID, opts options) ([]vectorsearch.Query, error) {
+	rng := rand.New(rand.NewPCG(opts.seed, opts.seed+1))
+	queries := make([]vectorsearch.Query, opts.queries)
+	for i := range queries {
+		id := ids[rng.IntN(len(ids))]
+		vector, err := index.Vector(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		queries[i] = vectorsearch.Query{Vector: vector, Filter: vectorsearch.Filter{Exclude: []uuid.UUID{id}}}
+		if opts.target == embedding.TargetUsers {
+			queries[i].Filter.Viewer = id
+		}
+	}
+	return queries, nil
+}
+
+// generate fills index with random vectors gathered around a centre per
+// hundred vectors, so neighbourhoods look more like real embeddings than
+// uniform noise does.
+func generate(index *vectorsearch.Memory, opts options) error {
+	rng := rand.New(rand.NewPCG(opts.seed, opts.seed))
+	centres := make([][]float32, max(1, opts.synthetic/100))
+	for i := range centres {
+		centres[i] = make([]float32, opts.dims)
+		for j := range centres[i] {
+			centres[i][j] = float32(rng.NormFloat64())
+		}
+	}
+	for range opts.synthetic {
+		centre := centres[rng.IntN(len(centres))]
+		vector := make([]float32, opts.dims)
+		for j := range vector {
+			vector[j] = centre[j] + float32(rng.NormFloat64())
+		}
+		if err := index.Put(vectorsearch.Item{ID: uuid.New(), Vector: vector}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func open() (*db.SQL, func(), error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	database, err := db.New(db.Config{
+		DSN:                cfg.Database.DSN(),
+		MaxConns:           cfg.Database.MaxConns,
+		MinConns:           cfg.Database.MinConns,
+		MaxConnLifetime:    cfg.Database.MaxConnLifetime,
+		MaxConnIdleTime:    cfg.Database.MaxConnIdleTime,
+		SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+	}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		return nil, nil, err
+	}
+	return database.SQL(), database.Close, nil
+}
+
+func ints(list string) ([]int, error) {
+	var out []int
+	for _, field := range strings.Split(list, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid value %q", field)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	adminv1 "github.com/FACorreiaa/skillsphere-proto/gen/go/admin/v1"
+	pb "github.com/FACorreiaa/skillsphere-proto/gen/go/admin/v1/adminv1connect"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/auth/presenter"
+	flagrepo "github.com/FACorreiaa/skillsphere-api/internal/domain/featureflag/repository"
+	flagservice "github.com/FACorreiaa/skillsphere-api/internal/domain/featureflag/service"
+	settingsservice "github.com/FACorreiaa/skillsphere-api/internal/domain/settings/service"
+	"github.com/FACorreiaa/skillsphere-api/pkg/interceptors"
+)
+
+// AdminHandler implements the AdminService Connect handlers. Callers must be
+// admins; the router enforces the role before requests reach the handler.
+type AdminHandler struct {
+	pb.UnimplementedAdminServiceHandler
+	flags    *flagservice.Service
+	settings *settingsservice.Service
+}
+
+// NewAdminHandler constructs a new handler.
+func NewAdminHandler(flags *flagservice.Service, settings *settingsservice.Service) *AdminHandler {
+	return &AdminHandler{
+		flags:    flags,
+		settings: settings,
+	}
+}
+
+// GetPlatformSettings returns the current platform settings.
+func (h *AdminHandler) GetPlatformSettings(
+	ctx context.Context,
+	req *connect.Request[adminv1.GetPlatformSettingsRequest],
+) (*connect.Response[adminv1.GetPlatformSettingsResponse], error) {
+	if _, err := adminFromContext(ctx, req.Msg.AdminId); err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&adminv1.GetPlatformSettingsResponse{
+		Settings: platformSettings(h.settings.Current()),
+	}), nil
+}
+
+// UpdatePlatformSettings replaces the platform settings and audits the changed keys.
+func (h *AdminHandler) UpdatePlatformSettings(
+	ctx context.Context,
+	req *connect.Request[adminv1.UpdatePlatformSettingsRequest],
+) (*connect.Response[adminv1.UpdatePlatformSettingsResponse], error) {
+	adminID, err := adminFromContext(ctx, req.Msg.AdminId)
+	if err != nil {
+		return nil, err
+	}
+	if req.Msg.Settings == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("settings are required"))
+	}
+
+	updated, err := h.settings.Update(ctx, settingsservice.UpdateParams{
+		AdminID:  adminID,
+		ClientIP: req.Peer().Addr,
+		Settings: settingsFromProto(req.Msg.Settings),
+	})
+	if err != nil {
+		return nil, h.toConnectError(err)
+	}
+
+	return connect.NewResponse(&adminv1.UpdatePlatformSettingsResponse{
+		Settings: platformSettings(updated),
+	}), nil
+}
+
+// ToggleFeatureFlag switches a flag globally, or for the listed users when user_ids is set.
+func (h *AdminHandler) ToggleFeatureFlag(
+	ctx context.Context,
+	req *connect.Request[adminv1.ToggleFeatureFlagRequest],
+) (*connect.Response[adminv1.ToggleFeatureFlagResponse], error) {
+	if _, err := adminFromContext(ctx, req.Msg.AdminId); err != nil {
+		return nil, err
+	}
+
+	flag, err := h.flags.Toggle(ctx, flagservice.ToggleParams{
+		Name:    req.Msg.FeatureName,
+		Enabled: req.Msg.Enabled,
+		UserIDs: req.Msg.UserIds,
+	})
+	if err != nil {
+		return nil, h.toConnectError(err)
+	}
+
+	return connect.NewResponse(&adminv1.ToggleFeatureFlagResponse{
+		Feature: featureFlag(*flag),
+	}), nil
+}
+
+// ListFeatureFlags returns every flag with its targeted users.
+func (h *AdminHandler) ListFeatureFlags(
+	ctx context.Context,
+	req *connect.Request[adminv1.ListFeatureFlagsRequest],
+) (*connect.Response[adminv1.ListFeatureFlagsResponse], error) {
+	if _, err := adminFromContext(ctx, req.Msg.AdminId); err != nil {
+		return nil, err
+	}
+
+	flags, err := h.flags.List(ctx)
+	if err != nil {
+		return nil, h.toConnectError(err)
+	}
+
+	resp := &adminv1.ListFeatureFlagsResponse{
+		Features: make([]*adminv1.FeatureFlag, 0, len(flags)),
+	}
+	for _, flag := range flags {
+		resp.Features = append(resp.Features, featureFlag(flag))
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *AdminHandler) toConnectError(err error) error {
+	switch {
+	case errors.Is(err, flagrepo.ErrFlagNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	case errors.Is(err, flagservice.ErrInvalidFlagName),
+		errors.Is(err, flagservice.ErrInvalidRollout),
+		errors.Is(err, flagservice.ErrInvalidUserID),
+		errors.Is(err, settingsservice.ErrInvalidSetting),
+		errors.Is(err, settingsservice.ErrInvalidAdminID):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	default:
+		return connect.NewError(connect.CodeInternal, err)
+	}
+}
+
+// adminFromContext returns the authenticated admin's ID. A non-empty admin_id in the
+// request must match the token so audit records cannot be attributed to someone else.
+func adminFromContext(ctx context.Context, requestAdminID string) (string, error) {
+	claims, err := interceptors.GetClaimsFromContext(ctx)
+	if err != nil {
+		return "", connect.NewError(connect.CodeUnauthenticated, errors.New("authentication required"))
+	}
+	if requestAdminID != "" && requestAdminID != claims.UserID {
+		return "", connect.NewError(connect.CodePermissionDenied, errors.New("admin_id does not match the authenticated user"))
+	}
+	return claims.UserID, nil
+}
+
+func featureFlag(flag flagrepo.Flag) *adminv1.FeatureFlag {
+	users := make([]string, 0, len(flag.UserIDs))
+	for _, id := range flag.UserIDs {
+		users = append(users, id.String())
+	}
+	return &adminv1.FeatureFlag{
+		Name:            flag.Name,
+		Enabled:         flag.Enabled,
+		Description:     flag.Description,
+		EnabledForUsers: users,
+		UpdatedAt:       presenter.Timestamp(flag.UpdatedAt),
+	}
+}
+
+func platformSettings(settings settingsservice.Settings) *adminv1.PlatformSettings {
+	custom := make(map[string]string, len(settings.Custom))
+	for key, value := range settings.Custom {
+		custom[key] = value
+	}
+	return &adminv1.PlatformSettings{
+		MaintenanceMode:           settings.MaintenanceMode,
+		MaintenanceMessage:        settings.MaintenanceMessage,
+		NewSignupsEnabled:         settings.NewSignupsEnabled,
+		MaxSessionsPerDay:         int32(settings.MaxSessionsPerDay),
+		MinSessionRating:          int32(settings.MinSessionRating),
+		AutoApproveCertifications: settings.AutoApproveCertifications,
+		PlatformFeePercentage:     settings.PlatformFeePercentage,
+		CustomSettings:            custom,
+	}
+}
+
+func settingsFromProto(msg *adminv1.PlatformSettings) settingsservice.Settings {
+	custom := make(map[string]string, len(msg.CustomSettings))
+	for key, value := range msg.CustomSettings {
+		custom[key] = value
+	}
+	return settingsservice.Settings{
+		MaintenanceMode:           msg.MaintenanceMode,
+		MaintenanceMessage:        msg.MaintenanceMessage,
+		NewSignupsEnabled:         msg.NewSignupsEnabled,
+		MaxSessionsPerDay:         int(msg.MaxSessionsPerDay),
+		MinSessionRating:          int(msg.MinSessionRating),
+		AutoApproveCertifications: msg.AutoApproveCertifications,
+		PlatformFeePercentage:     msg.PlatformFeePercentage,
+		Custom:                    custom,
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+
+	"connectrpc.com/connect"
+	pb "github.com/FACorreiaa/skillsphere-proto/gen/go/admin/v1/adminv1connect"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	flagservice "github.com/FACorreiaa/skillsphere-api/internal/domain/featureflag/service"
+)
+
+// SetFeatureFlagRolloutProcedure changes the share of users that see an
+// enabled flag. ToggleFeatureFlagRequest has no field for it, so like the
+// taxonomy procedures the request is a google.protobuf.Struct:
+// {"feature_name": "…", "rollout_percentage": 25} with an optional
+// "admin_id". The response is {"name", "enabled", "rollout_percentage"}.
+const SetFeatureFlagRolloutProcedure = "/" + pb.AdminServiceName + "/SetFeatureFlagRollout"
+
+// NewSetFeatureFlagRolloutHandler builds the HTTP handler for
+// SetFeatureFlagRolloutProcedure and returns the path to mount it on.
+func NewSetFeatureFlagRolloutHandler(h *AdminHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	return SetFeatureFlagRolloutProcedure, connect.NewUnaryHandler(SetFeatureFlagRolloutProcedure, h.SetFeatureFlagRollout, opts...)
+}
+
+// SetFeatureFlagRollout sets a flag's rollout percentage.
+func (h *AdminHandler) SetFeatureFlagRollout(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[structpb.Struct], error) {
+	if _, err := adminFromContext(ctx, stringField(req.Msg, "admin_id")); err != nil {
+		return nil, err
+	}
+	value, ok := req.Msg.GetFields()["rollout_percentage"].GetKind().(*structpb.Value_NumberValue)
+	if !ok {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("rollout_percentage is required"))
+	}
+	if value.NumberValue != math.Trunc(value.NumberValue) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, flagservice.ErrInvalidRollout)
+	}
+
+	flag, err := h.flags.SetRollout(ctx, stringField(req.Msg, "feature_name"), int(value.NumberValue))
+	if err != nil {
+		return nil, h.toConnectError(err)
+	}
+	return structResponse(map[string]any{
+		"name":               flag.Name,
+		"enabled":            flag.Enabled,
+		"rollout_percentage": flag.RolloutPercentage,
+	})
+}
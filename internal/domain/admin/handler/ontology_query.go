@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	pb "github.com/FACorreiaa/skillsphere-proto/gen/go/admin/v1/adminv1connect"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/ontology"
+)
+
+// QueryOntologyProcedure runs a read-only SPARQL SELECT query against the
+// API's ontology index. AdminService has no message for it yet, so the request
+// is a google.protobuf.Struct of the form {"query": "SELECT …"} and the
+// response is the result in the SPARQL 1.1 Query Results JSON format.
+const QueryOntologyProcedure = "/" + pb.AdminServiceName + "/QueryOntology"
+
+const defaultOntologyQueryTimeout = 10 * time.Second
+
+// GraphQuerier answers SPARQL queries; *ontology.IndexFeed implements it.
+type GraphQuerier interface {
+	Query(ctx context.Context, query string) (*ontology.QueryResult, error)
+}
+
+// OntologyQueryHandler serves QueryOntologyProcedure. Like AdminHandler it
+// relies on the router to restrict callers to admins.
+type OntologyQueryHandler struct {
+	graph   GraphQuerier
+	timeout time.Duration
+}
+
+// NewOntologyQueryHandler constructs a handler that stops queries after timeout.
+func NewOntologyQueryHandler(graph GraphQuerier, timeout time.Duration) *OntologyQueryHandler {
+	if timeout <= 0 {
+		timeout = defaultOntologyQueryTimeout
+	}
+	return &OntologyQueryHandler{graph: graph, timeout: timeout}
+}
+
+// NewQueryOntologyHandler builds the HTTP handler for QueryOntologyProcedure
+// and returns the path to mount it on, like the generated constructors.
+func NewQueryOntologyHandler(h *OntologyQueryHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	return QueryOntologyProcedure, connect.NewUnaryHandler(QueryOntologyProcedure, h.QueryOntology, opts...)
+}
+
+// QueryOntology evaluates the query and returns its solutions.
+func (h *OntologyQueryHandler) QueryOntology(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[structpb.Struct], error) {
+	query := req.Msg.GetFields()["query"].GetStringValue()
+	if strings.TrimSpace(query) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("query is required"))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	result, err := h.graph.Query(ctx, query)
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	resp := &structpb.Struct{}
+	if err := protojson.Unmarshal(body, resp); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func queryError(err error) error {
+	var syntax *ontology.QuerySyntaxError
+	switch {
+	case errors.As(err, &syntax):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, ontology.ErrIndexNotReady):
+		return connect.NewError(connect.CodeUnavailable, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return connect.NewError(connect.CodeDeadlineExceeded, errors.New("query took too long; add a LIMIT or narrow the patterns"))
+	case errors.Is(err, context.Canceled):
+		return connect.NewError(connect.CodeCanceled, err)
+	default:
+		return connect.NewError(connect.CodeInternal, err)
+	}
+}
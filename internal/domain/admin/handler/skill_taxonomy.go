@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	pb "github.com/FACorreiaa/skillsphere-proto/gen/go/admin/v1/adminv1connect"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	taxonomyrepo "github.com/FACorreiaa/skillsphere-api/internal/domain/taxonomy/repository"
+	taxonomyservice "github.com/FACorreiaa/skillsphere-api/internal/domain/taxonomy/service"
+)
+
+// Skill taxonomy procedures. Like QueryOntologyProcedure they have no
+// messages in AdminService yet, so requests and responses are
+// google.protobuf.Struct values; each accepts an optional "admin_id" that must
+// match the caller.
+const (
+	// ImportSkillTaxonomyProcedure takes {"turtle": "…"} and replaces the
+	// relations of every skill the document describes.
+	ImportSkillTaxonomyProcedure = "/" + pb.AdminServiceName + "/ImportSkillTaxonomy"
+	// ExportSkillTaxonomyProcedure returns {"turtle": "…"} for the whole catalogue.
+	ExportSkillTaxonomyProcedure = "/" + pb.AdminServiceName + "/ExportSkillTaxonomy"
+	// MergeSkillsProcedure takes {"source_skill_id", "target_skill_id"} and
+	// folds the source skill into the target.
+	MergeSkillsProcedure = "/" + pb.AdminServiceName + "/MergeSkills"
+)
+
+// SkillTaxonomyHandler serves the skill taxonomy procedures. The router
+// restricts callers to admins.
+type SkillTaxonomyHandler struct {
+	taxonomy *taxonomyservice.Service
+}
+
+// NewSkillTaxonomyHandler constructs a new handler.
+func NewSkillTaxonomyHandler(taxonomy *taxonomyservice.Service) *SkillTaxonomyHandler {
+	return &SkillTaxonomyHandler{taxonomy: taxonomy}
+}
+
+// NewSkillTaxonomyHandlers builds the HTTP handlers for the taxonomy
+// procedures, keyed by the path to mount each on.
+func NewSkillTaxonomyHandlers(h *SkillTaxonomyHandler, opts ...connect.HandlerOption) map[string]http.Handler {
+	return map[string]http.Handler{
+		ImportSkillTaxonomyProcedure: connect.NewUnaryHandler(ImportSkillTaxonomyProcedure, h.ImportSkillTaxonomy, opts...),
+		ExportSkillTaxonomyProcedure: connect.NewUnaryHandler(ExportSkillTaxonomyProcedure, h.ExportSkillTaxonomy, opts...),
+		MergeSkillsProcedure:         connect.NewUnaryHandler(MergeSkillsProcedure, h.MergeSkills, opts...),
+	}
+}
+
+// ImportSkillTaxonomy applies a Turtle taxonomy.
+func (h *SkillTaxonomyHandler) ImportSkillTaxonomy(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[structpb.Struct], error) {
+	adminID, err := adminFromContext(ctx, stringField(req.Msg, "admin_id"))
+	if err != nil {
+		return nil, err
+	}
+	turtle := stringField(req.Msg, "turtle")
+	if strings.TrimSpace(turtle) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("turtle is required"))
+	}
+
+	result, err := h.taxonomy.Import(ctx, taxonomyservice.ImportParams{AdminID: adminID, Turtle: []byte(turtle)})
+	if err != nil {
+		return nil, taxonomyError(err)
+	}
+	return structResponse(map[string]any{
+		"concepts":   result.Concepts,
+		"broader":    result.Broader,
+		"related":    result.Related,
+		"alt_labels": result.AltLabels,
+	})
+}
+
+// ExportSkillTaxonomy returns the taxonomy as Turtle.
+func (h *SkillTaxonomyHandler) ExportSkillTaxonomy(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[structpb.Struct], error) {
+	if _, err := adminFromContext(ctx, stringField(req.Msg, "admin_id")); err != nil {
+		return nil, err
+	}
+
+	var turtle strings.Builder
+	if err := h.taxonomy.Export(ctx, &turtle); err != nil {
+		return nil, taxonomyError(err)
+	}
+	return structResponse(map[string]any{"turtle": turtle.String()})
+}
+
+// MergeSkills folds a duplicate skill into another.
+func (h *SkillTaxonomyHandler) MergeSkills(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[structpb.Struct], error) {
+	adminID, err := adminFromContext(ctx, stringField(req.Msg, "admin_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.taxonomy.Merge(ctx, taxonomyservice.MergeParams{
+		AdminID:  adminID,
+		SourceID: stringField(req.Msg, "source_skill_id"),
+		TargetID: stringField(req.Msg, "target_skill_id"),
+	})
+	if err != nil {
+		return nil, taxonomyError(err)
+	}
+	return structResponse(map[string]any{
+		"user_skills": result.UserSkills,
+		"gig_skills":  result.GigSkills,
+		"reviews":     result.Reviews,
+	})
+}
+
+func taxonomyError(err error) error {
+	switch {
+	case errors.Is(err, taxonomyrepo.ErrSkillNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	case errors.Is(err, taxonomyrepo.ErrTaxonomyCycle),
+		errors.Is(err, taxonomyrepo.ErrLabelTaken):
+		return connect.NewError(connect.CodeFailedPrecondition, err)
+	case errors.Is(err, taxonomyservice.ErrInvalidTaxonomy),
+		errors.Is(err, taxonomyservice.ErrUnknownConcept),
+		errors.Is(err, taxonomyservice.ErrAmbiguousLabel),
+		errors.Is(err, taxonomyservice.ErrInvalidSkillID),
+		errors.Is(err, taxonomyservice.ErrSameSkill),
+		errors.Is(err, taxonomyservice.ErrInvalidAdminID):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	default:
+		return connect.NewError(connect.CodeInternal, err)
+	}
+}
+
+func stringField(msg *structpb.Struct, name string) string {
+	return msg.GetFields()[name].GetStringValue()
+}
+
+func structResponse(fields map[string]any) (*connect.Response[structpb.Struct], error) {
+	resp, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(resp), nil
+}
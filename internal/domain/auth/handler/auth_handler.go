@@ -141,7 +141,7 @@ func (h *AuthHandler) toConnectError(err error) error {
 		return connect.NewError(connect.CodeUnauthenticated, err)
 	case errors.Is(err, common.ErrUserNotFound):
 		return connect.NewError(connect.CodeNotFound, err)
-	case errors.Is(err, service.ErrAccountInactive):
+	case errors.Is(err, service.ErrAccountInactive), errors.Is(err, service.ErrSignupsDisabled):
 		return connect.NewError(connect.CodePermissionDenied, err)
 	case errors.Is(err, service.ErrPasswordTooShort),
 		errors.Is(err, service.ErrPasswordNoDigit),
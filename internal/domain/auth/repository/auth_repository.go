@@ -36,6 +36,7 @@ func (r *PostgresAuthRepository) CreateUser(ctx context.Context, email, username
 	}
 
 	query := `
+		-- name: CreateUser
 		INSERT INTO users (id, email, username, hashed_password, display_name, role, is_active, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, created_at, updated_at
@@ -57,6 +58,7 @@ func (r *PostgresAuthRepository) CreateUser(ctx context.Context, email, username
 func (r *PostgresAuthRepository) GetUserByEmail(ctx context.Context, email string) (*User, error) {
 	user := &User{}
 	query := `
+		-- name: GetUserByEmail
 		SELECT id, email, username, hashed_password, display_name, avatar_url, role,
 		       is_active, email_verified_at, created_at, updated_at, last_login_at
 		FROM users
@@ -83,6 +85,7 @@ func (r *PostgresAuthRepository) GetUserByEmail(ctx context.Context, email strin
 func (r *PostgresAuthRepository) GetUserByID(ctx context.Context, userID uuid.UUID) (*User, error) {
 	user := &User{}
 	query := `
+		-- name: GetUserByID
 		SELECT id, email, username, hashed_password, display_name, avatar_url, role,
 		       is_active, email_verified_at, created_at, updated_at, last_login_at
 		FROM users
@@ -107,7 +110,10 @@ func (r *PostgresAuthRepository) GetUserByID(ctx context.Context, userID uuid.UU
 
 // UpdateLastLogin updates the user's last login timestamp
 func (r *PostgresAuthRepository) UpdateLastLogin(ctx context.Context, userID uuid.UUID) error {
-	query := `UPDATE users SET last_login_at = $1 WHERE id = $2`
+	query := `
+		-- name: UpdateLastLogin
+		UPDATE users SET last_login_at = $1 WHERE id = $2
+	`
 	_, err := r.db.ExecContext(ctx, query, time.Now(), userID)
 	return err
 }
@@ -125,6 +131,7 @@ func (r *PostgresAuthRepository) CreateUserSession(ctx context.Context, userID u
 	}
 
 	query := `
+		-- name: CreateUserSession
 		INSERT INTO user_sessions (id, user_id, hashed_refresh_token, user_agent, client_ip, expires_at, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id, created_at
@@ -146,6 +153,7 @@ func (r *PostgresAuthRepository) CreateUserSession(ctx context.Context, userID u
 func (r *PostgresAuthRepository) GetUserSessionByToken(ctx context.Context, hashedToken string) (*UserSession, error) {
 	session := &UserSession{}
 	query := `
+		-- name: GetUserSessionByToken
 		SELECT id, user_id, hashed_refresh_token, user_agent, client_ip, expires_at, created_at
 		FROM user_sessions
 		WHERE hashed_refresh_token = $1 AND expires_at > $2
@@ -168,14 +176,20 @@ func (r *PostgresAuthRepository) GetUserSessionByToken(ctx context.Context, hash
 
 // DeleteUserSession deletes a session
 func (r *PostgresAuthRepository) DeleteUserSession(ctx context.Context, hashedToken string) error {
-	query := `DELETE FROM user_sessions WHERE hashed_refresh_token = $1`
+	query := `
+		-- name: DeleteUserSession
+		DELETE FROM user_sessions WHERE hashed_refresh_token = $1
+	`
 	_, err := r.db.ExecContext(ctx, query, hashedToken)
 	return err
 }
 
 // DeleteAllUserSessions deletes all sessions for a user
 func (r *PostgresAuthRepository) DeleteAllUserSessions(ctx context.Context, userID uuid.UUID) error {
-	query := `DELETE FROM user_sessions WHERE user_id = $1`
+	query := `
+		-- name: DeleteAllUserSessions
+		DELETE FROM user_sessions WHERE user_id = $1
+	`
 	_, err := r.db.ExecContext(ctx, query, userID)
 	return err
 }
@@ -183,6 +197,7 @@ func (r *PostgresAuthRepository) DeleteAllUserSessions(ctx context.Context, user
 // CreateUserToken creates a verification or reset token
 func (r *PostgresAuthRepository) CreateUserToken(ctx context.Context, userID uuid.UUID, tokenHash, tokenType string, expiresAt time.Time) error {
 	query := `
+		-- name: CreateUserToken
 		INSERT INTO user_tokens (token_hash, user_id, type, expires_at, created_at)
 		VALUES ($1, $2, $3, $4, $5)
 	`
@@ -195,6 +210,7 @@ func (r *PostgresAuthRepository) CreateUserToken(ctx context.Context, userID uui
 func (r *PostgresAuthRepository) GetUserTokenByHash(ctx context.Context, tokenHash, tokenType string) (*UserToken, error) {
 	token := &UserToken{}
 	query := `
+		-- name: GetUserTokenByHash
 		SELECT token_hash, user_id, type, expires_at, created_at
 		FROM user_tokens
 		WHERE token_hash = $1 AND type = $2 AND expires_at > $3
@@ -216,21 +232,30 @@ func (r *PostgresAuthRepository) GetUserTokenByHash(ctx context.Context, tokenHa
 
 // DeleteUserToken deletes a token
 func (r *PostgresAuthRepository) DeleteUserToken(ctx context.Context, tokenHash string) error {
-	query := `DELETE FROM user_tokens WHERE token_hash = $1`
+	query := `
+		-- name: DeleteUserToken
+		DELETE FROM user_tokens WHERE token_hash = $1
+	`
 	_, err := r.db.ExecContext(ctx, query, tokenHash)
 	return err
 }
 
 // VerifyEmail marks a user's email as verified
 func (r *PostgresAuthRepository) VerifyEmail(ctx context.Context, userID uuid.UUID) error {
-	query := `UPDATE users SET email_verified_at = $1 WHERE id = $2`
+	query := `
+		-- name: VerifyEmail
+		UPDATE users SET email_verified_at = $1 WHERE id = $2
+	`
 	_, err := r.db.ExecContext(ctx, query, time.Now(), userID)
 	return err
 }
 
 // UpdatePassword updates a user's password
 func (r *PostgresAuthRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, hashedPassword string) error {
-	query := `UPDATE users SET hashed_password = $1, updated_at = $2 WHERE id = $3`
+	query := `
+		-- name: UpdatePassword
+		UPDATE users SET hashed_password = $1, updated_at = $2 WHERE id = $3
+	`
 	_, err := r.db.ExecContext(ctx, query, hashedPassword, time.Now(), userID)
 	return err
 }
@@ -238,6 +263,7 @@ func (r *PostgresAuthRepository) UpdatePassword(ctx context.Context, userID uuid
 // CreateOrUpdateOAuthIdentity creates or updates an OAuth identity
 func (r *PostgresAuthRepository) CreateOrUpdateOAuthIdentity(ctx context.Context, providerName, providerUserID string, userID uuid.UUID, accessToken, refreshToken *string) error {
 	query := `
+		-- name: CreateOrUpdateOAuthIdentity
 		INSERT INTO user_oauth_identities (provider_name, provider_user_id, user_id, provider_access_token, provider_refresh_token, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		ON CONFLICT (provider_name, provider_user_id)
@@ -256,6 +282,7 @@ func (r *PostgresAuthRepository) CreateOrUpdateOAuthIdentity(ctx context.Context
 func (r *PostgresAuthRepository) GetUserByOAuthIdentity(ctx context.Context, providerName, providerUserID string) (*User, error) {
 	user := &User{}
 	query := `
+		-- name: GetUserByOAuthIdentity
 		SELECT u.id, u.email, u.username, u.hashed_password, u.display_name, u.avatar_url, u.role,
 		       u.is_active, u.email_verified_at, u.created_at, u.updated_at, u.last_login_at
 		FROM users u
@@ -26,8 +26,15 @@ const (
 var (
 	// ErrAccountInactive is returned when a user has been disabled.
 	ErrAccountInactive = errors.New("account is deactivated")
+	// ErrSignupsDisabled is returned when the platform is not accepting new accounts.
+	ErrSignupsDisabled = errors.New("new signups are currently disabled")
 )
 
+// SignupGate reports whether new accounts may be registered.
+type SignupGate interface {
+	SignupsEnabled() bool
+}
+
 // SessionMetadata captures client information useful for audit trails.
 type SessionMetadata struct {
 	UserAgent string
@@ -82,6 +89,7 @@ type AuthService struct {
 	sessionTTL   time.Duration
 	logger       *slog.Logger
 	ontology     ontology.Emitter
+	signupGate   SignupGate
 }
 
 // NewAuthService constructs a new AuthService.
@@ -110,8 +118,17 @@ func NewAuthService(
 	}
 }
 
+// SetSignupGate makes RegisterUser refuse new accounts while gate reports signups as disabled.
+func (s *AuthService) SetSignupGate(gate SignupGate) {
+	s.signupGate = gate
+}
+
 // RegisterUser creates a new user account, issues tokens, and sends verification email.
 func (s *AuthService) RegisterUser(ctx context.Context, params RegisterParams) (*RegisterResult, error) {
+	if s.signupGate != nil && !s.signupGate.SignupsEnabled() {
+		return nil, ErrSignupsDisabled
+	}
+
 	if err := ValidatePassword(params.Password); err != nil {
 		return nil, err
 	}
@@ -182,6 +199,8 @@ func (s *AuthService) Login(ctx context.Context, params LoginParams) (*LoginResu
 		s.logger.Warn("failed to update last login", "error", err)
 	}
 
+	s.emitOntologyEvent(ctx, ontology.NewUserLoggedInEvent(user, time.Now()))
+
 	return &LoginResult{
 		User:   user,
 		Tokens: tokens,
@@ -361,8 +380,9 @@ func (s *AuthService) VerifyEmail(ctx context.Context, verificationToken string)
 
 	_ = s.repo.DeleteUserToken(ctx, hashedToken)
 
-	if s.emailService != nil {
-		if user, err := s.repo.GetUserByID(ctx, userToken.UserID); err == nil {
+	if user, err := s.repo.GetUserByID(ctx, userToken.UserID); err == nil {
+		s.emitOntologyEvent(ctx, ontology.NewUserVerifiedEvent(user))
+		if s.emailService != nil {
 			go s.emailService.SendWelcomeEmail(user.Email, user.DisplayName)
 		}
 	}
@@ -80,6 +80,39 @@ func TestAuthService_RegisterUser_DuplicateEmail(t *testing.T) {
 	}
 }
 
+type staticSignupGate bool
+
+func (g staticSignupGate) SignupsEnabled() bool { return bool(g) }
+
+func TestAuthService_RegisterUser_SignupsDisabled(t *testing.T) {
+	svc, repo, _, _ := newTestAuthService()
+	svc.SetSignupGate(staticSignupGate(false))
+	ctx := context.Background()
+
+	_, err := svc.RegisterUser(ctx, RegisterParams{
+		Email:       "jane@example.com",
+		Username:    "jane",
+		Password:    "Str0ng!Pass",
+		DisplayName: "Jane Doe",
+	})
+	if !errors.Is(err, ErrSignupsDisabled) {
+		t.Fatalf("expected ErrSignupsDisabled, got %v", err)
+	}
+	if _, err := repo.GetUserByEmail(ctx, "jane@example.com"); !errors.Is(err, common.ErrUserNotFound) {
+		t.Fatalf("no user should be created while signups are disabled")
+	}
+
+	svc.SetSignupGate(staticSignupGate(true))
+	if _, err := svc.RegisterUser(ctx, RegisterParams{
+		Email:       "jane@example.com",
+		Username:    "jane",
+		Password:    "Str0ng!Pass",
+		DisplayName: "Jane Doe",
+	}); err != nil {
+		t.Fatalf("RegisterUser() with signups enabled error = %v", err)
+	}
+}
+
 func TestAuthService_Login_InvalidPassword(t *testing.T) {
 	svc, repo, tokens, _ := newTestAuthService()
 	ctx := context.Background()
@@ -156,6 +189,46 @@ func TestAuthService_Login_Success(t *testing.T) {
 	}
 }
 
+func TestAuthService_EmitsUserLifecycleEvents(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _, _ := newTestAuthService()
+	emitter := &recordingEmitter{}
+	svc.ontology = emitter
+
+	result, err := svc.RegisterUser(ctx, RegisterParams{
+		Email:       "jane@example.com",
+		Username:    "jane",
+		Password:    "Str0ng!Pass",
+		DisplayName: "Jane Doe",
+	})
+	if err != nil {
+		t.Fatalf("RegisterUser: %v", err)
+	}
+	if _, err := svc.Login(ctx, LoginParams{Email: "jane@example.com", Password: "Str0ng!Pass"}); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	hash := hashToken("verify-token")
+	repo.tokens[hash] = &repository.UserToken{
+		TokenHash: hash,
+		UserID:    result.User.ID,
+		Type:      tokenTypeEmailVerification,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	if _, err := svc.VerifyEmail(ctx, "verify-token"); err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+
+	want := []string{ontology.ActivityUserRegistered, ontology.ActivityUserLoggedIn, ontology.ActivityUserVerified}
+	if len(emitter.activities) != len(want) {
+		t.Fatalf("activities = %v, want %v", emitter.activities, want)
+	}
+	for i, activity := range want {
+		if emitter.activities[i] != activity {
+			t.Errorf("activity %d = %s, want %s", i, emitter.activities[i], activity)
+		}
+	}
+}
+
 func TestAuthService_Logout_RemovesSession(t *testing.T) {
 	ctx := context.Background()
 	svc, repo, _, _ := newTestAuthService()
@@ -580,6 +653,20 @@ func (m *mockAuthRepo) GetUserByOAuthIdentity(ctx context.Context, providerName,
 	return nil, common.ErrUserNotFound
 }
 
+// recordingEmitter keeps the activity type of every emitted event.
+type recordingEmitter struct {
+	activities []string
+}
+
+func (r *recordingEmitter) Emit(_ context.Context, event ontology.Event) error {
+	record, _ := event.Properties()[ontology.ProvWasGeneratedBy].(map[string]any)
+	types, _ := record["@type"].([]string)
+	if len(types) > 0 {
+		r.activities = append(r.activities, types[0])
+	}
+	return nil
+}
+
 func newTestAuthService() (*AuthService, *mockAuthRepo, *mockTokenManager, *mockEmailSender) {
 	repo := newMockAuthRepo()
 	tokenManager := &mockTokenManager{}
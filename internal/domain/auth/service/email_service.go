@@ -3,7 +3,6 @@ package service
 import (
 	"fmt"
 	"net/smtp"
-	"os"
 )
 
 // EmailSender defines the behavior required for sending auth emails.
@@ -13,9 +12,20 @@ type EmailSender interface {
 	SendWelcomeEmail(toEmail, toName string) error
 }
 
+// SMTPConfig holds the SMTP settings used by the email service.
+type SMTPConfig struct {
+	Host        string
+	Port        int
+	Username    string
+	Password    string
+	FromEmail   string
+	FromName    string
+	FrontendURL string
+}
+
 type smtpEmailService struct {
 	smtpHost     string
-	smtpPort     string
+	smtpPort     int
 	smtpUsername string
 	smtpPassword string
 	fromEmail    string
@@ -24,15 +34,15 @@ type smtpEmailService struct {
 }
 
 // NewEmailService creates a new email service
-func NewEmailService() EmailSender {
+func NewEmailService(cfg SMTPConfig) EmailSender {
 	return &smtpEmailService{
-		smtpHost:     os.Getenv("SMTP_HOST"),
-		smtpPort:     os.Getenv("SMTP_PORT"),
-		smtpUsername: os.Getenv("SMTP_USERNAME"),
-		smtpPassword: os.Getenv("SMTP_PASSWORD"),
-		fromEmail:    os.Getenv("FROM_EMAIL"),
-		fromName:     os.Getenv("FROM_NAME"),
-		frontendURL:  os.Getenv("FRONTEND_URL"),
+		smtpHost:     cfg.Host,
+		smtpPort:     cfg.Port,
+		smtpUsername: cfg.Username,
+		smtpPassword: cfg.Password,
+		fromEmail:    cfg.FromEmail,
+		fromName:     cfg.FromName,
+		frontendURL:  cfg.FrontendURL,
 	}
 }
 
@@ -138,7 +148,7 @@ func (s *smtpEmailService) SendWelcomeEmail(toEmail, toName string) error {
 // sendEmail is a helper function to send emails via SMTP
 func (s *smtpEmailService) sendEmail(to, subject, body string) error {
 	// If SMTP is not configured, log and skip (for development)
-	if s.smtpHost == "" || s.smtpPort == "" {
+	if s.smtpHost == "" || s.smtpPort == 0 {
 		fmt.Printf("Email would be sent to %s with subject: %s\n", to, subject)
 		return nil
 	}
@@ -155,6 +165,6 @@ func (s *smtpEmailService) sendEmail(to, subject, body string) error {
 		"\r\n"+
 		"%s\r\n", from, to, subject, body))
 
-	addr := fmt.Sprintf("%s:%s", s.smtpHost, s.smtpPort)
+	addr := fmt.Sprintf("%s:%d", s.smtpHost, s.smtpPort)
 	return smtp.SendMail(addr, auth, s.fromEmail, []string{to}, message)
 }
@@ -1,8 +1,6 @@
 package service
 
 import (
-	"os"
-
 	"github.com/gorilla/sessions"
 	"github.com/markbates/goth"
 	"github.com/markbates/goth/gothic"
@@ -65,17 +63,3 @@ func InitOAuth(config OAuthConfig) error {
 
 	return nil
 }
-
-// LoadOAuthConfigFromEnv loads OAuth config from environment variables
-func LoadOAuthConfigFromEnv() OAuthConfig {
-	return OAuthConfig{
-		GoogleClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
-		GoogleClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
-		AppleClientID:      os.Getenv("APPLE_CLIENT_ID"),
-		AppleSecret:        os.Getenv("APPLE_SECRET"),
-		AppleTeamID:        os.Getenv("APPLE_TEAM_ID"),
-		AppleKeyID:         os.Getenv("APPLE_KEY_ID"),
-		CallbackURL:        os.Getenv("OAUTH_CALLBACK_URL"),
-		SessionSecret:      os.Getenv("SESSION_SECRET"),
-	}
-}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrFlagNotFound is returned when a flag does not exist.
+var ErrFlagNotFound = errors.New("feature flag not found")
+
+// Flag is a feature flag together with its explicitly targeted users.
+type Flag struct {
+	Name              string
+	Description       string
+	Enabled           bool
+	RolloutPercentage int
+	UserIDs           []uuid.UUID
+	UpdatedAt         time.Time
+}
+
+// FlagRepository persists feature flags and their user targeting.
+type FlagRepository interface {
+	ListFlags(ctx context.Context) ([]Flag, error)
+	GetFlag(ctx context.Context, name string) (*Flag, error)
+	SetEnabled(ctx context.Context, name string, enabled bool) error
+	SetRollout(ctx context.Context, name string, percentage int) error
+	AddUsers(ctx context.Context, name string, userIDs []uuid.UUID) error
+	RemoveUsers(ctx context.Context, name string, userIDs []uuid.UUID) error
+}
+
+// PostgresFlagRepository stores flags in feature_flags and feature_flag_users.
+type PostgresFlagRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresFlagRepository creates a new flag repository
+func NewPostgresFlagRepository(db *sql.DB) *PostgresFlagRepository {
+	return &PostgresFlagRepository{db: db}
+}
+
+// ListFlags returns every flag with its targeted users.
+func (r *PostgresFlagRepository) ListFlags(ctx context.Context) ([]Flag, error) {
+	query := `
+		-- name: ListFeatureFlags
+		SELECT name, COALESCE(description, ''), is_enabled, rollout_percentage, updated_at
+		FROM feature_flags
+		ORDER BY name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flags []Flag
+	index := make(map[string]int)
+	for rows.Next() {
+		var flag Flag
+		if err := rows.Scan(&flag.Name, &flag.Description, &flag.Enabled, &flag.RolloutPercentage, &flag.UpdatedAt); err != nil {
+			return nil, err
+		}
+		index[flag.Name] = len(flags)
+		flags = append(flags, flag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	usersQuery := `
+		-- name: ListFeatureFlagUsers
+		SELECT feature_name, user_id
+		FROM feature_flag_users
+		ORDER BY feature_name, user_id
+	`
+
+	userRows, err := r.db.QueryContext(ctx, usersQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer userRows.Close()
+
+	for userRows.Next() {
+		var name string
+		var userID uuid.UUID
+		if err := userRows.Scan(&name, &userID); err != nil {
+			return nil, err
+		}
+		if i, ok := index[name]; ok {
+			flags[i].UserIDs = append(flags[i].UserIDs, userID)
+		}
+	}
+	return flags, userRows.Err()
+}
+
+// GetFlag returns a single flag with its targeted users.
+func (r *PostgresFlagRepository) GetFlag(ctx context.Context, name string) (*Flag, error) {
+	flag := &Flag{}
+	query := `
+		-- name: GetFeatureFlag
+		SELECT name, COALESCE(description, ''), is_enabled, rollout_percentage, updated_at
+		FROM feature_flags
+		WHERE name = $1
+	`
+
+	err := r.db.QueryRowContext(ctx, query, name).Scan(
+		&flag.Name, &flag.Description, &flag.Enabled, &flag.RolloutPercentage, &flag.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrFlagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	usersQuery := `
+		-- name: GetFeatureFlagUsers
+		SELECT user_id
+		FROM feature_flag_users
+		WHERE feature_name = $1
+		ORDER BY user_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, usersQuery, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		flag.UserIDs = append(flag.UserIDs, userID)
+	}
+	return flag, rows.Err()
+}
+
+// SetEnabled creates the flag if needed and sets its global switch.
+func (r *PostgresFlagRepository) SetEnabled(ctx context.Context, name string, enabled bool) error {
+	query := `
+		-- name: UpsertFeatureFlagEnabled
+		INSERT INTO feature_flags (name, is_enabled, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (name) DO UPDATE
+		SET is_enabled = EXCLUDED.is_enabled, updated_at = NOW()
+	`
+
+	_, err := r.db.ExecContext(ctx, query, name, enabled)
+	return err
+}
+
+// SetRollout sets the percentage of users that see the flag when it is enabled.
+func (r *PostgresFlagRepository) SetRollout(ctx context.Context, name string, percentage int) error {
+	query := `
+		-- name: UpdateFeatureFlagRollout
+		UPDATE feature_flags
+		SET rollout_percentage = $2, updated_at = NOW()
+		WHERE name = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, name, percentage)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrFlagNotFound
+	}
+	return nil
+}
+
+// AddUsers targets the flag at the given users, creating a disabled flag if needed.
+func (r *PostgresFlagRepository) AddUsers(ctx context.Context, name string, userIDs []uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	ensureQuery := `
+		-- name: EnsureFeatureFlag
+		INSERT INTO feature_flags (name, is_enabled, updated_at)
+		VALUES ($1, false, NOW())
+		ON CONFLICT (name) DO UPDATE SET updated_at = NOW()
+	`
+	if _, err := tx.ExecContext(ctx, ensureQuery, name); err != nil {
+		return err
+	}
+
+	insertQuery := `
+		-- name: AddFeatureFlagUser
+		INSERT INTO feature_flag_users (feature_name, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	for _, userID := range userIDs {
+		if _, err := tx.ExecContext(ctx, insertQuery, name, userID); err != nil {
+			return fmt.Errorf("target user %s: %w", userID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// RemoveUsers stops targeting the flag at the given users.
+func (r *PostgresFlagRepository) RemoveUsers(ctx context.Context, name string, userIDs []uuid.UUID) error {
+	query := `
+		-- name: RemoveFeatureFlagUser
+		DELETE FROM feature_flag_users
+		WHERE feature_name = $1 AND user_id = $2
+	`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, userID := range userIDs {
+		if _, err := tx.ExecContext(ctx, query, name, userID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/featureflag/repository"
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+	"github.com/FACorreiaa/skillsphere-api/pkg/interceptors"
+)
+
+// ChangeChannel is the NOTIFY channel fired by the feature_flags triggers.
+const ChangeChannel = "feature_flags_changed"
+
+// defaultRefreshInterval bounds staleness if a notification is ever missed.
+const defaultRefreshInterval = time.Minute
+
+const maxFlagNameLength = 100
+
+var (
+	ErrInvalidFlagName = errors.New("feature name is required and must be at most 100 characters")
+	ErrInvalidRollout  = errors.New("rollout percentage must be between 0 and 100")
+	ErrInvalidUserID   = errors.New("invalid user id")
+)
+
+// Notifier delivers Postgres notifications; *db.DB implements it.
+type Notifier interface {
+	Listen(ctx context.Context, channel string, onConnect func(context.Context), fn func(db.Notification)) error
+}
+
+// Service evaluates feature flags against an in-memory snapshot that is reloaded
+// whenever the flag tables change.
+type Service struct {
+	repo            repository.FlagRepository
+	logger          *slog.Logger
+	refreshInterval time.Duration
+
+	snapshot atomic.Pointer[snapshot]
+}
+
+type snapshot struct {
+	flags    map[string]flagState
+	loadedAt time.Time
+}
+
+type flagState struct {
+	enabled bool
+	rollout int
+	users   map[string]struct{}
+}
+
+// NewService constructs the feature flag service with an empty snapshot.
+func NewService(repo repository.FlagRepository, logger *slog.Logger) *Service {
+	s := &Service{
+		repo:            repo,
+		logger:          logger,
+		refreshInterval: defaultRefreshInterval,
+	}
+	s.snapshot.Store(&snapshot{flags: map[string]flagState{}})
+	return s
+}
+
+// Refresh reloads the snapshot from the repository.
+func (s *Service) Refresh(ctx context.Context) error {
+	flags, err := s.repo.ListFlags(ctx)
+	if err != nil {
+		return fmt.Errorf("load feature flags: %w", err)
+	}
+	s.snapshot.Store(newSnapshot(flags))
+	return nil
+}
+
+// Run keeps the snapshot current until ctx is cancelled. It reloads on every
+// change notification, after every listener reconnect, and on a slow timer as a
+// safety net.
+func (s *Service) Run(ctx context.Context, notifier Notifier) {
+	refresh := func(ctx context.Context) {
+		if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("failed to refresh feature flags", "error", err)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				refresh(ctx)
+			}
+		}
+	}()
+
+	if err := notifier.Listen(ctx, ChangeChannel, refresh, func(db.Notification) {
+		refresh(ctx)
+	}); err != nil {
+		s.logger.Error("feature flag listener stopped", "error", err)
+	}
+}
+
+// Enabled reports whether the flag is on for the principal in ctx. Anonymous
+// callers only see flags that are enabled for everyone.
+func (s *Service) Enabled(ctx context.Context, name string) bool {
+	userID := ""
+	if claims, err := interceptors.GetClaimsFromContext(ctx); err == nil {
+		userID = claims.UserID
+	} else if id, ok := interceptors.GetUserIDFromContext(ctx); ok {
+		userID = id
+	}
+	return s.EnabledFor(name, userID)
+}
+
+// EnabledFor reports whether the flag is on for userID. A disabled flag is off
+// for everyone, so disabling is a kill switch; an enabled flag is on for
+// explicitly targeted users and for users inside the rollout percentage.
+func (s *Service) EnabledFor(name, userID string) bool {
+	flag, ok := s.snapshot.Load().flags[name]
+	if !ok || !flag.enabled {
+		return false
+	}
+	if _, targeted := flag.users[userID]; targeted && userID != "" {
+		return true
+	}
+	if flag.rollout >= 100 {
+		return true
+	}
+	if userID == "" {
+		return false
+	}
+	return bucket(name, userID) < flag.rollout
+}
+
+// List returns the current flags straight from the repository.
+func (s *Service) List(ctx context.Context) ([]repository.Flag, error) {
+	return s.repo.ListFlags(ctx)
+}
+
+// ToggleParams describes an admin change to a flag.
+type ToggleParams struct {
+	Name    string
+	Enabled bool
+	// UserIDs, when set, adds (Enabled) or removes (!Enabled) explicit targeting
+	// instead of changing the global switch.
+	UserIDs []string
+}
+
+// Toggle applies an admin change and returns the updated flag.
+func (s *Service) Toggle(ctx context.Context, params ToggleParams) (*repository.Flag, error) {
+	if params.Name == "" || len(params.Name) > maxFlagNameLength {
+		return nil, ErrInvalidFlagName
+	}
+	userIDs := make([]uuid.UUID, 0, len(params.UserIDs))
+	for _, raw := range params.UserIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidUserID, raw)
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	var err error
+	switch {
+	case len(userIDs) > 0 && params.Enabled:
+		err = s.repo.AddUsers(ctx, params.Name, userIDs)
+	case len(userIDs) > 0:
+		err = s.repo.RemoveUsers(ctx, params.Name, userIDs)
+	default:
+		err = s.repo.SetEnabled(ctx, params.Name, params.Enabled)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.applied(ctx, params.Name)
+}
+
+// SetRollout changes the share of users that see an enabled flag, leaving
+// its switch and targeted users alone.
+func (s *Service) SetRollout(ctx context.Context, name string, percentage int) (*repository.Flag, error) {
+	if name == "" || len(name) > maxFlagNameLength {
+		return nil, ErrInvalidFlagName
+	}
+	if percentage < 0 || percentage > 100 {
+		return nil, ErrInvalidRollout
+	}
+	if err := s.repo.SetRollout(ctx, name, percentage); err != nil {
+		return nil, err
+	}
+	return s.applied(ctx, name)
+}
+
+// applied refreshes the snapshot after an admin change and returns the flag.
+func (s *Service) applied(ctx context.Context, name string) (*repository.Flag, error) {
+	// Apply locally right away; other instances pick the change up via NOTIFY.
+	if err := s.Refresh(ctx); err != nil {
+		s.logger.Warn("failed to refresh feature flags after toggle", "error", err)
+	}
+
+	return s.repo.GetFlag(ctx, name)
+}
+
+func newSnapshot(flags []repository.Flag) *snapshot {
+	snap := &snapshot{
+		flags:    make(map[string]flagState, len(flags)),
+		loadedAt: time.Now(),
+	}
+	for _, flag := range flags {
+		state := flagState{
+			enabled: flag.Enabled,
+			rollout: flag.RolloutPercentage,
+			users:   make(map[string]struct{}, len(flag.UserIDs)),
+		}
+		for _, userID := range flag.UserIDs {
+			state.users[userID.String()] = struct{}{}
+		}
+		snap.flags[flag.Name] = state
+	}
+	return snap
+}
+
+// bucket maps a user to [0, 100) for a flag. Salting with the flag name keeps the
+// cohorts of different flags independent, while a given user always lands in the
+// same bucket so raising the percentage only ever adds users.
+func bucket(name, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{':'})
+	h.Write([]byte(userID))
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/featureflag/repository"
+	"github.com/FACorreiaa/skillsphere-api/pkg/interceptors"
+)
+
+type fakeFlagRepo struct {
+	flags map[string]*repository.Flag
+}
+
+func newFakeFlagRepo(flags ...repository.Flag) *fakeFlagRepo {
+	repo := &fakeFlagRepo{flags: map[string]*repository.Flag{}}
+	for i := range flags {
+		repo.flags[flags[i].Name] = &flags[i]
+	}
+	return repo
+}
+
+func (r *fakeFlagRepo) ListFlags(context.Context) ([]repository.Flag, error) {
+	var out []repository.Flag
+	for _, flag := range r.flags {
+		out = append(out, *flag)
+	}
+	return out, nil
+}
+
+func (r *fakeFlagRepo) GetFlag(_ context.Context, name string) (*repository.Flag, error) {
+	flag, ok := r.flags[name]
+	if !ok {
+		return nil, repository.ErrFlagNotFound
+	}
+	copied := *flag
+	return &copied, nil
+}
+
+func (r *fakeFlagRepo) ensure(name string) *repository.Flag {
+	if _, ok := r.flags[name]; !ok {
+		r.flags[name] = &repository.Flag{Name: name, RolloutPercentage: 100}
+	}
+	return r.flags[name]
+}
+
+func (r *fakeFlagRepo) SetEnabled(_ context.Context, name string, enabled bool) error {
+	r.ensure(name).Enabled = enabled
+	return nil
+}
+
+func (r *fakeFlagRepo) SetRollout(_ context.Context, name string, percentage int) error {
+	flag, ok := r.flags[name]
+	if !ok {
+		return repository.ErrFlagNotFound
+	}
+	flag.RolloutPercentage = percentage
+	return nil
+}
+
+func (r *fakeFlagRepo) AddUsers(_ context.Context, name string, userIDs []uuid.UUID) error {
+	flag := r.ensure(name)
+	flag.UserIDs = append(flag.UserIDs, userIDs...)
+	return nil
+}
+
+func (r *fakeFlagRepo) RemoveUsers(_ context.Context, name string, userIDs []uuid.UUID) error {
+	flag := r.ensure(name)
+	remove := map[uuid.UUID]bool{}
+	for _, id := range userIDs {
+		remove[id] = true
+	}
+	kept := flag.UserIDs[:0]
+	for _, id := range flag.UserIDs {
+		if !remove[id] {
+			kept = append(kept, id)
+		}
+	}
+	flag.UserIDs = kept
+	return nil
+}
+
+func newTestService(t *testing.T, flags ...repository.Flag) (*Service, *fakeFlagRepo) {
+	t.Helper()
+	repo := newFakeFlagRepo(flags...)
+	svc := NewService(repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := svc.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	return svc, repo
+}
+
+func TestEnabled_UsesClaimsFromContext(t *testing.T) {
+	target := uuid.New()
+	svc, _ := newTestService(t, repository.Flag{Name: "premium_chat", Enabled: true, UserIDs: []uuid.UUID{target}})
+
+	ctx := interceptors.ContextWithClaims(context.Background(), &interceptors.Claims{UserID: target.String()})
+	if !svc.Enabled(ctx, "premium_chat") {
+		t.Error("targeted user should see the flag outside the rollout")
+	}
+	other := interceptors.ContextWithClaims(context.Background(), &interceptors.Claims{UserID: uuid.NewString()})
+	if svc.Enabled(other, "premium_chat") {
+		t.Error("untargeted user should not see a flag without rollout")
+	}
+	if svc.Enabled(context.Background(), "premium_chat") {
+		t.Error("anonymous caller should not see a flag without rollout")
+	}
+	if svc.Enabled(ctx, "unknown_flag") {
+		t.Error("unknown flags must be off")
+	}
+}
+
+func TestEnabledFor_DisabledFlagIsOffForTargetedUsers(t *testing.T) {
+	target := uuid.New()
+	svc, _ := newTestService(t, repository.Flag{Name: "premium_chat", RolloutPercentage: 100, UserIDs: []uuid.UUID{target}})
+
+	if svc.EnabledFor("premium_chat", target.String()) {
+		t.Error("a disabled flag must be off for targeted users")
+	}
+	if svc.EnabledFor("premium_chat", uuid.NewString()) {
+		t.Error("a disabled flag must be off at full rollout")
+	}
+}
+
+func TestEnabledFor_PercentageRolloutIsStableAndMonotonic(t *testing.T) {
+	svc, repo := newTestService(t, repository.Flag{Name: "new_search", Enabled: true, RolloutPercentage: 25})
+
+	users := make([]string, 2000)
+	for i := range users {
+		users[i] = uuid.NewString()
+	}
+
+	enabledAt25 := map[string]bool{}
+	for _, user := range users {
+		if svc.EnabledFor("new_search", user) {
+			enabledAt25[user] = true
+		}
+		if svc.EnabledFor("new_search", user) != enabledAt25[user] {
+			t.Fatal("evaluation must be deterministic")
+		}
+	}
+	if share := float64(len(enabledAt25)) / float64(len(users)); share < 0.2 || share > 0.3 {
+		t.Errorf("rollout share = %.2f, want about 0.25", share)
+	}
+	if svc.EnabledFor("new_search", "") {
+		t.Error("anonymous callers are excluded from partial rollouts")
+	}
+
+	repo.flags["new_search"].RolloutPercentage = 50
+	if err := svc.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for user := range enabledAt25 {
+		if !svc.EnabledFor("new_search", user) {
+			t.Fatalf("raising the rollout removed user %s", user)
+		}
+	}
+}
+
+func TestToggle(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+	user := uuid.New()
+
+	if _, err := svc.Toggle(ctx, ToggleParams{Name: "beta", Enabled: true, UserIDs: []string{user.String()}}); err != nil {
+		t.Fatalf("Toggle users: %v", err)
+	}
+	if svc.EnabledFor("beta", user.String()) {
+		t.Error("targeting must not turn on a disabled flag")
+	}
+
+	if _, err := svc.Toggle(ctx, ToggleParams{Name: "beta", Enabled: true}); err != nil {
+		t.Fatalf("Toggle global: %v", err)
+	}
+	flag, err := svc.SetRollout(ctx, "beta", 0)
+	if err != nil {
+		t.Fatalf("SetRollout: %v", err)
+	}
+	if !flag.Enabled || flag.RolloutPercentage != 0 {
+		t.Errorf("flag = %+v", flag)
+	}
+	if !svc.EnabledFor("beta", user.String()) || svc.EnabledFor("beta", uuid.NewString()) {
+		t.Error("user targeting not applied to the snapshot")
+	}
+
+	if _, err := svc.SetRollout(ctx, "beta", 101); !errors.Is(err, ErrInvalidRollout) {
+		t.Errorf("err = %v, want ErrInvalidRollout", err)
+	}
+	if _, err := svc.Toggle(ctx, ToggleParams{Name: "beta", UserIDs: []string{"nope"}}); !errors.Is(err, ErrInvalidUserID) {
+		t.Errorf("err = %v, want ErrInvalidUserID", err)
+	}
+	if _, err := svc.Toggle(ctx, ToggleParams{}); !errors.Is(err, ErrInvalidFlagName) {
+		t.Errorf("err = %v, want ErrInvalidFlagName", err)
+	}
+}
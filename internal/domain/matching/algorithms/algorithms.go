@@ -0,0 +1,265 @@
+// Package algorithms scores skill-exchange matches between two profiles.
+//
+// Every algorithm answers the same question for one direction of an exchange:
+// how well do the skills one user offers cover the skills another user wants?
+// Match asks it both ways, Similarity compares like with like.
+package algorithms
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// DefaultProficiency is assumed for user_skills rows without a proficiency.
+const DefaultProficiency = 5
+
+// MaxProficiency is the top of the user_skills proficiency scale.
+const MaxProficiency = 10
+
+// Kind names an algorithm. The values are stored in match_history.algorithm_used.
+type Kind string
+
+const (
+	Euclidean Kind = "euclidean"
+	Cosine    Kind = "cosine"
+	Embedding Kind = "embedding"
+	Hybrid    Kind = "hybrid"
+)
+
+// ErrUnknownAlgorithm is returned by New for kinds it does not implement.
+var ErrUnknownAlgorithm = errors.New("unknown matching algorithm")
+
+// Skill is one skill a user offers or wants.
+type Skill struct {
+	ID   uuid.UUID
+	Name string
+	// Proficiency is the offered level, or the level a learner wants to reach.
+	Proficiency int
+	// Embedding is the skill's vector, if one has been generated.
+	Embedding []float32
+}
+
+// Profile is a user's skills.
+type Profile struct {
+	UserID  uuid.UUID
+	Offered []Skill
+	Wanted  []Skill
+}
+
+// Link pairs a wanted skill with the offered skill that covers it.
+type Link struct {
+	Wanted  Skill
+	Offered Skill
+	// Similarity is 1 for the same skill and the embedding similarity otherwise.
+	Similarity float64
+}
+
+// Algorithm scores how well offered skills cover wanted ones.
+type Algorithm interface {
+	Kind() Kind
+	// Cover returns a score in [0, 1]; 1 means every wanted skill is offered
+	// at or above the wanted level.
+	Cover(wanted, offered []Skill) float64
+	// Links returns the pairs Cover relied on, for explanations.
+	Links(wanted, offered []Skill) []Link
+}
+
+// New returns the algorithm for kind.
+func New(kind Kind) (Algorithm, error) {
+	switch kind {
+	case Euclidean:
+		return euclidean{}, nil
+	case Cosine:
+		return cosine{}, nil
+	case Embedding:
+		return embedding{threshold: DefaultEmbeddingThreshold}, nil
+	case Hybrid:
+		return DefaultHybrid(), nil
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+}
+
+// Pair is a skill one user can teach the other.
+type Pair struct {
+	// SkillName is the offered skill, or "offered (wanted)" when the two
+	// differ and were paired by embedding similarity.
+	SkillName        string
+	UserProficiency  int
+	MatchProficiency int
+	// IsComplementary is set when the user offers what the match wants;
+	// otherwise the match offers what the user wants.
+	IsComplementary bool
+	Similarity      float64
+}
+
+// Result is the outcome of matching a user against a candidate.
+type Result struct {
+	Score float64
+	// Pairs are ordered by the strength of the link, strongest first.
+	Pairs []Pair
+	// Mutual is set when each user offers something the other wants.
+	Mutual bool
+	// Learn covers what the user wants with what the candidate offers, and
+	// Teach the other way round. Either is nil when nobody wants anything in
+	// that direction; Score is the mean of the others.
+	Learn, Teach *Direction
+}
+
+// Direction is how well one side of an exchange is covered.
+type Direction struct {
+	Cover float64
+	// Potential is the cover if every paired skill were offered at least at
+	// the wanted level, so Cover - Potential is what proficiency gaps cost.
+	Potential float64
+}
+
+// Match scores candidate for user. The score is the mean of the two
+// directions of the exchange, counting only directions where somebody wants
+// something, so a one-sided mentor match is not halved.
+func Match(alg Algorithm, user, candidate Profile) Result {
+	var result Result
+	var total float64
+	var directions int
+
+	learn := alg.Links(user.Wanted, candidate.Offered)
+	teach := alg.Links(candidate.Wanted, user.Offered)
+	if len(user.Wanted) > 0 {
+		result.Learn = direction(alg, user.Wanted, candidate.Offered, learn)
+		total += result.Learn.Cover
+		directions++
+	}
+	if len(candidate.Wanted) > 0 {
+		result.Teach = direction(alg, candidate.Wanted, user.Offered, teach)
+		total += result.Teach.Cover
+		directions++
+	}
+	if directions > 0 {
+		result.Score = clamp(total / float64(directions))
+	}
+
+	for _, link := range learn {
+		result.Pairs = append(result.Pairs, Pair{
+			SkillName:        linkName(link),
+			UserProficiency:  link.Wanted.Proficiency,
+			MatchProficiency: link.Offered.Proficiency,
+			Similarity:       link.Similarity,
+		})
+	}
+	for _, link := range teach {
+		result.Pairs = append(result.Pairs, Pair{
+			SkillName:        linkName(link),
+			UserProficiency:  link.Offered.Proficiency,
+			MatchProficiency: link.Wanted.Proficiency,
+			IsComplementary:  true,
+			Similarity:       link.Similarity,
+		})
+	}
+	sort.SliceStable(result.Pairs, func(i, j int) bool {
+		if result.Pairs[i].Similarity != result.Pairs[j].Similarity {
+			return result.Pairs[i].Similarity > result.Pairs[j].Similarity
+		}
+		return offeredLevel(result.Pairs[i]) > offeredLevel(result.Pairs[j])
+	})
+	result.Mutual = len(learn) > 0 && len(teach) > 0
+	return result
+}
+
+// direction covers wanted with offered and measures the cover they would
+// reach with the linked offered skills raised to the levels they cover.
+// Raising levels can lower a cosine cover, so Potential is never below Cover.
+func direction(alg Algorithm, wanted, offered []Skill, links []Link) *Direction {
+	d := &Direction{Cover: alg.Cover(wanted, offered)}
+	raised := slices.Clone(offered)
+	for _, link := range links {
+		if i := indexOf(raised, link.Offered.ID); i >= 0 && level(raised[i]) < level(link.Wanted) {
+			raised[i].Proficiency = link.Wanted.Proficiency
+		}
+	}
+	d.Potential = math.Max(d.Cover, alg.Cover(wanted, raised))
+	return d
+}
+
+// Similar is the outcome of comparing two users' profiles.
+type Similar struct {
+	Score float64
+	// CommonSkills are the names of skills both users list the same way.
+	CommonSkills []string
+}
+
+// Similarity compares what two users offer and what they want, in both
+// directions so the score is symmetric.
+func Similarity(alg Algorithm, a, b Profile) Similar {
+	var total float64
+	var sides int
+	for _, side := range [][2][]Skill{{a.Offered, b.Offered}, {a.Wanted, b.Wanted}} {
+		if len(side[0]) == 0 || len(side[1]) == 0 {
+			continue
+		}
+		total += (alg.Cover(side[0], side[1]) + alg.Cover(side[1], side[0])) / 2
+		sides++
+	}
+
+	var similar Similar
+	if sides > 0 {
+		similar.Score = clamp(total / float64(sides))
+	}
+	for _, side := range [][2][]Skill{{a.Offered, b.Offered}, {a.Wanted, b.Wanted}} {
+		for _, skill := range side[0] {
+			if indexOf(side[1], skill.ID) >= 0 && !slices.Contains(similar.CommonSkills, skill.Name) {
+				similar.CommonSkills = append(similar.CommonSkills, skill.Name)
+			}
+		}
+	}
+	sort.Strings(similar.CommonSkills)
+	return similar
+}
+
+// exactLinks pairs skills by id.
+func exactLinks(wanted, offered []Skill) []Link {
+	var links []Link
+	for _, want := range wanted {
+		if i := indexOf(offered, want.ID); i >= 0 {
+			links = append(links, Link{Wanted: want, Offered: offered[i], Similarity: 1})
+		}
+	}
+	return links
+}
+
+func indexOf(skills []Skill, id uuid.UUID) int {
+	return slices.IndexFunc(skills, func(s Skill) bool { return s.ID == id })
+}
+
+// level returns a skill's proficiency on a 0..1 scale.
+func level(skill Skill) float64 {
+	p := skill.Proficiency
+	if p <= 0 {
+		p = DefaultProficiency
+	}
+	return float64(min(p, MaxProficiency)) / MaxProficiency
+}
+
+func linkName(link Link) string {
+	if link.Wanted.ID == link.Offered.ID || link.Wanted.Name == link.Offered.Name {
+		return link.Offered.Name
+	}
+	return link.Offered.Name + " (" + link.Wanted.Name + ")"
+}
+
+func offeredLevel(p Pair) int {
+	if p.IsComplementary {
+		return p.UserProficiency
+	}
+	return p.MatchProficiency
+}
+
+func clamp(score float64) float64 {
+	if math.IsNaN(score) {
+		return 0
+	}
+	return math.Max(0, math.Min(1, score))
+}
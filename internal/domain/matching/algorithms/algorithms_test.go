@@ -0,0 +1,133 @@
+package algorithms
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var (
+	goSkill     = Skill{ID: uuid.New(), Name: "Go", Embedding: []float32{1, 0, 0}}
+	golangSkill = Skill{ID: uuid.New(), Name: "Golang", Embedding: []float32{0.95, 0.1, 0}}
+	spanish     = Skill{ID: uuid.New(), Name: "Spanish", Embedding: []float32{0, 1, 0}}
+	guitar      = Skill{ID: uuid.New(), Name: "Guitar", Embedding: []float32{0, 0, 1}}
+)
+
+func at(skill Skill, proficiency int) Skill {
+	skill.Proficiency = proficiency
+	return skill
+}
+
+func mustNew(t *testing.T, kind Kind) Algorithm {
+	t.Helper()
+	alg, err := New(kind)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return alg
+}
+
+func TestCover(t *testing.T) {
+	wanted := []Skill{at(goSkill, 6), at(spanish, 4)}
+	for _, tc := range []struct {
+		kind    Kind
+		offered []Skill
+		want    float64
+	}{
+		{Euclidean, []Skill{at(goSkill, 8), at(spanish, 9)}, 1},
+		{Euclidean, []Skill{at(goSkill, 8)}, 1 - math.Sqrt(0.5)},
+		{Euclidean, []Skill{at(goSkill, 4), at(spanish, 4)}, 1 - math.Sqrt(0.02)},
+		{Euclidean, nil, 0},
+		{Cosine, []Skill{at(goSkill, 3), at(spanish, 2)}, 1},
+		{Cosine, []Skill{at(goSkill, 6)}, 6 / math.Sqrt(52)},
+		{Cosine, []Skill{at(guitar, 6)}, 0},
+		{Embedding, []Skill{at(goSkill, 6), at(spanish, 2)}, 0.75},
+		{Embedding, []Skill{at(golangSkill, 9)}, skillSimilarity(goSkill, golangSkill) / 2},
+		{Embedding, []Skill{at(guitar, 9)}, 0},
+	} {
+		got := mustNew(t, tc.kind).Cover(wanted, tc.offered)
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("%s.Cover(%v) = %v, want %v", tc.kind, tc.offered, got, tc.want)
+		}
+	}
+}
+
+func TestMatch_PairsAndMutual(t *testing.T) {
+	alice := Profile{UserID: uuid.New(), Offered: []Skill{at(spanish, 7)}, Wanted: []Skill{at(goSkill, 6)}}
+	bob := Profile{UserID: uuid.New(), Offered: []Skill{at(golangSkill, 8)}, Wanted: []Skill{at(spanish, 3)}}
+
+	exact := Match(mustNew(t, Euclidean), alice, bob)
+	if exact.Mutual || len(exact.Pairs) != 1 || exact.Pairs[0].SkillName != "Spanish" || !exact.Pairs[0].IsComplementary {
+		t.Errorf("euclidean = %+v, want only alice teaching Spanish", exact)
+	}
+	if math.Abs(exact.Score-0.5) > 1e-9 {
+		t.Errorf("euclidean score = %v, want the mean of a miss and a full cover", exact.Score)
+	}
+
+	semantic := Match(mustNew(t, Hybrid), alice, bob)
+	if !semantic.Mutual {
+		t.Errorf("hybrid should pair Golang with Go: %+v", semantic)
+	}
+	want := []Pair{
+		{SkillName: "Spanish", UserProficiency: 7, MatchProficiency: 3, IsComplementary: true, Similarity: 1},
+		{SkillName: "Golang (Go)", UserProficiency: 6, MatchProficiency: 8, Similarity: skillSimilarity(goSkill, golangSkill)},
+	}
+	if !slices.Equal(semantic.Pairs, want) {
+		t.Errorf("pairs = %+v, want %+v", semantic.Pairs, want)
+	}
+	if semantic.Score <= exact.Score {
+		t.Errorf("hybrid %v should beat euclidean %v when skills differ only by name", semantic.Score, exact.Score)
+	}
+}
+
+func TestMatch_OneSidedIsNotHalved(t *testing.T) {
+	mentor := Profile{UserID: uuid.New(), Offered: []Skill{at(guitar, 9)}}
+	learner := Profile{UserID: uuid.New(), Wanted: []Skill{at(guitar, 5)}}
+
+	if got := Match(mustNew(t, Euclidean), learner, mentor); got.Score != 1 || got.Mutual {
+		t.Errorf("learner vs mentor = %+v", got)
+	}
+	if got := Match(mustNew(t, Cosine), Profile{}, Profile{}); got.Score != 0 || got.Pairs != nil {
+		t.Errorf("empty profiles = %+v", got)
+	}
+}
+
+func TestMatch_Directions(t *testing.T) {
+	mentor := Profile{UserID: uuid.New(), Offered: []Skill{at(goSkill, 4)}, Wanted: []Skill{at(guitar, 5)}}
+	learner := Profile{UserID: uuid.New(), Wanted: []Skill{at(goSkill, 8)}}
+
+	got := Match(mustNew(t, Euclidean), learner, mentor)
+	if got.Learn == nil || math.Abs(got.Learn.Cover-0.6) > 1e-9 || got.Learn.Potential != 1 {
+		t.Errorf("learn = %+v, want cover 0.6 short of 1", got.Learn)
+	}
+	if got.Teach == nil || got.Teach.Cover != 0 || got.Teach.Potential != 0 {
+		t.Errorf("teach = %+v, want an uncovered direction", got.Teach)
+	}
+	if got := Match(mustNew(t, Euclidean), mentor, Profile{UserID: uuid.New()}); got.Teach != nil {
+		t.Errorf("teach = %+v, want nil when nothing is wanted", got.Teach)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	a := Profile{Offered: []Skill{at(goSkill, 7), at(guitar, 3)}, Wanted: []Skill{at(spanish, 5)}}
+	b := Profile{Offered: []Skill{at(goSkill, 7)}, Wanted: []Skill{at(spanish, 5)}}
+
+	for _, kind := range []Kind{Euclidean, Cosine, Embedding, Hybrid} {
+		alg := mustNew(t, kind)
+		ab, ba := Similarity(alg, a, b), Similarity(alg, b, a)
+		if ab.Score != ba.Score || ab.Score <= 0 || ab.Score >= 1 {
+			t.Errorf("%s: similarity %v / %v, want symmetric and partial", kind, ab.Score, ba.Score)
+		}
+		if !slices.Equal(ab.CommonSkills, []string{"Go", "Spanish"}) {
+			t.Errorf("%s: common = %v", kind, ab.CommonSkills)
+		}
+	}
+}
+
+func TestNew_Unknown(t *testing.T) {
+	if _, err := New("random"); err != ErrUnknownAlgorithm {
+		t.Errorf("New = %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package algorithms
+
+import "math"
+
+// cosine compares the direction of the wanted proficiency vector with the
+// offered one over the wanted skills. It rewards covering the same mix of
+// skills in the same proportions and ignores absolute levels, so it favours
+// broad coverage over depth.
+type cosine struct{}
+
+func (cosine) Kind() Kind { return Cosine }
+
+func (cosine) Cover(wanted, offered []Skill) float64 {
+	var dot, wantNorm, offerNorm float64
+	for _, want := range wanted {
+		w := level(want)
+		wantNorm += w * w
+		if i := indexOf(offered, want.ID); i >= 0 {
+			o := level(offered[i])
+			dot += w * o
+			offerNorm += o * o
+		}
+	}
+	if wantNorm == 0 || offerNorm == 0 {
+		return 0
+	}
+	return clamp(dot / (math.Sqrt(wantNorm) * math.Sqrt(offerNorm)))
+}
+
+func (cosine) Links(wanted, offered []Skill) []Link {
+	return exactLinks(wanted, offered)
+}
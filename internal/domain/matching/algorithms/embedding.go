@@ -0,0 +1,66 @@
+package algorithms
+
+import "math"
+
+// DefaultEmbeddingThreshold is the cosine similarity above which two
+// different skills are treated as the same, e.g. "Golang" and "Go".
+const DefaultEmbeddingThreshold = 0.8
+
+// embedding pairs each wanted skill with the semantically closest offered
+// skill. A pair counts by its similarity, scaled down when the offered level
+// is below the wanted one. Skills without embeddings only pair with
+// themselves.
+type embedding struct {
+	threshold float64
+}
+
+func (embedding) Kind() Kind { return Embedding }
+
+func (e embedding) Cover(wanted, offered []Skill) float64 {
+	if len(wanted) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, link := range e.Links(wanted, offered) {
+		sum += link.Similarity * math.Min(1, level(link.Offered)/level(link.Wanted))
+	}
+	return clamp(sum / float64(len(wanted)))
+}
+
+func (e embedding) Links(wanted, offered []Skill) []Link {
+	var links []Link
+	for _, want := range wanted {
+		best := Link{Similarity: -1}
+		for _, offer := range offered {
+			sim := skillSimilarity(want, offer)
+			if sim > best.Similarity ||
+				(sim == best.Similarity && offer.Proficiency > best.Offered.Proficiency) {
+				best = Link{Wanted: want, Offered: offer, Similarity: sim}
+			}
+		}
+		if best.Similarity >= e.threshold {
+			links = append(links, best)
+		}
+	}
+	return links
+}
+
+func skillSimilarity(a, b Skill) float64 {
+	if a.ID == b.ID {
+		return 1
+	}
+	if len(a.Embedding) == 0 || len(a.Embedding) != len(b.Embedding) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a.Embedding {
+		x, y := float64(a.Embedding[i]), float64(b.Embedding[i])
+		dot += x * y
+		normA += x * x
+		normB += y * y
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,29 @@
+package algorithms
+
+import "math"
+
+// euclidean measures the distance between the wanted levels and what is
+// offered, counting only shortfalls: teaching above the wanted level is not
+// penalised, and a wanted skill nobody offers is a full miss.
+type euclidean struct{}
+
+func (euclidean) Kind() Kind { return Euclidean }
+
+func (euclidean) Cover(wanted, offered []Skill) float64 {
+	if len(wanted) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, want := range wanted {
+		shortfall := 1.0
+		if i := indexOf(offered, want.ID); i >= 0 {
+			shortfall = math.Max(0, level(want)-level(offered[i]))
+		}
+		sum += shortfall * shortfall
+	}
+	return clamp(1 - math.Sqrt(sum/float64(len(wanted))))
+}
+
+func (euclidean) Links(wanted, offered []Skill) []Link {
+	return exactLinks(wanted, offered)
+}
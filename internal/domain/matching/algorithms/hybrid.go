@@ -0,0 +1,42 @@
+package algorithms
+
+// HybridWeights weighs the algorithms combined by the hybrid algorithm.
+type HybridWeights struct {
+	Euclidean float64
+	Cosine    float64
+	Embedding float64
+}
+
+// hybrid is a weighted mean of the other algorithms: Euclidean for levels,
+// cosine for breadth and embeddings for skills that are named differently.
+type hybrid struct {
+	weights   HybridWeights
+	embedding embedding
+}
+
+// DefaultHybrid returns the hybrid algorithm with its default weights.
+func DefaultHybrid() Algorithm {
+	return NewHybrid(HybridWeights{Euclidean: 0.4, Cosine: 0.2, Embedding: 0.4})
+}
+
+// NewHybrid returns a hybrid algorithm with the given weights.
+func NewHybrid(weights HybridWeights) Algorithm {
+	return hybrid{weights: weights, embedding: embedding{threshold: DefaultEmbeddingThreshold}}
+}
+
+func (hybrid) Kind() Kind { return Hybrid }
+
+func (h hybrid) Cover(wanted, offered []Skill) float64 {
+	total := h.weights.Euclidean + h.weights.Cosine + h.weights.Embedding
+	if total <= 0 {
+		return 0
+	}
+	score := h.weights.Euclidean*euclidean{}.Cover(wanted, offered) +
+		h.weights.Cosine*cosine{}.Cover(wanted, offered) +
+		h.weights.Embedding*h.embedding.Cover(wanted, offered)
+	return clamp(score / total)
+}
+
+func (h hybrid) Links(wanted, offered []Skill) []Link {
+	return h.embedding.Links(wanted, offered)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	pb "github.com/FACorreiaa/skillsphere-proto/gen/go/matching/v1/matchingv1connect"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/service"
+)
+
+// GetMatchExplanationsProcedure returns the structured explanations of the
+// caller's latest matches with some candidates, as FindMatches and
+// GetMatchScore recorded them. Match has no field for them yet, so the
+// request is a google.protobuf.Struct of the form
+// {"candidate_ids": ["…"], "user_id": "…"}, where user_id defaults to the
+// caller and only admins may set it to someone else. The response is
+// {"explanations": [{"candidate_id": "…", "text": "…", "score": …,
+// "mutual": …, "factors": […]}]} in request order, with text in the
+// Accept-Language locale; candidates never matched are left out.
+const GetMatchExplanationsProcedure = "/" + pb.MatchingServiceName + "/GetMatchExplanations"
+
+// NewGetMatchExplanationsHandler builds the HTTP handler for
+// GetMatchExplanationsProcedure and returns the path to mount it on, like the
+// generated constructors.
+func NewGetMatchExplanationsHandler(h *MatchingHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	return GetMatchExplanationsProcedure, connect.NewUnaryHandler(GetMatchExplanationsProcedure, h.GetMatchExplanations, opts...)
+}
+
+// matchExplanation is an entry of the GetMatchExplanations response.
+type matchExplanation struct {
+	CandidateID string `json:"candidate_id"`
+	Text        string `json:"text"`
+	service.Explanation
+}
+
+// GetMatchExplanations looks up the explanations of recorded matches.
+func (h *MatchingHandler) GetMatchExplanations(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[structpb.Struct], error) {
+	userID, err := callerOrSelf(ctx, req.Msg.GetFields()["user_id"].GetStringValue())
+	if err != nil {
+		return nil, err
+	}
+	var candidateIDs []string
+	for _, v := range req.Msg.GetFields()["candidate_ids"].GetListValue().GetValues() {
+		candidateIDs = append(candidateIDs, v.GetStringValue())
+	}
+	if len(candidateIDs) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("candidate_ids is required"))
+	}
+
+	explanations, err := h.service.MatchExplanations(ctx, userID, candidateIDs)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	locale := service.Locale(req.Header().Get("Accept-Language"))
+	out := make([]matchExplanation, 0, len(explanations))
+	for _, raw := range candidateIDs {
+		// MatchExplanations rejected IDs that do not parse.
+		id, _ := uuid.Parse(raw)
+		explanation, ok := explanations[id]
+		if !ok {
+			continue
+		}
+		out = append(out, matchExplanation{CandidateID: id.String(), Text: explanation.Text(locale), Explanation: explanation})
+	}
+
+	body, err := json.Marshal(map[string]any{"explanations": out})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	resp := &structpb.Struct{}
+	if err := protojson.Unmarshal(body, resp); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(resp), nil
+}
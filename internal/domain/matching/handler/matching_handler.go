@@ -0,0 +1,377 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	commonv1 "github.com/FACorreiaa/skillsphere-proto/gen/go/common/v1"
+	matchingv1 "github.com/FACorreiaa/skillsphere-proto/gen/go/matching/v1"
+	pb "github.com/FACorreiaa/skillsphere-proto/gen/go/matching/v1/matchingv1connect"
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/auth/presenter"
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/algorithms"
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/repository"
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/service"
+	"github.com/FACorreiaa/skillsphere-api/internal/ontology"
+	"github.com/FACorreiaa/skillsphere-api/internal/scheduling"
+	"github.com/FACorreiaa/skillsphere-api/pkg/interceptors"
+)
+
+// MatchingHandler implements the MatchingService Connect handlers.
+type MatchingHandler struct {
+	pb.UnimplementedMatchingServiceHandler
+	service *service.Service
+}
+
+// NewMatchingHandler constructs a new handler.
+func NewMatchingHandler(svc *service.Service) *MatchingHandler {
+	return &MatchingHandler{service: svc}
+}
+
+// FindMatches returns the best skill-exchange partners for the caller.
+func (h *MatchingHandler) FindMatches(
+	ctx context.Context,
+	req *connect.Request[matchingv1.FindMatchesRequest],
+) (*connect.Response[matchingv1.FindMatchesResponse], error) {
+	userID, err := callerOrSelf(ctx, req.Msg.UserId)
+	if err != nil {
+		return nil, err
+	}
+	filters, labels, err := searchFilters(req.Msg.Filters)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	result, err := h.service.FindMatches(ctx, service.FindParams{
+		UserID:      userID,
+		Algorithm:   algorithmKind(req.Msg.Algorithm),
+		Limit:       int(req.Msg.Limit),
+		MinScore:    req.Msg.MinMatchScore,
+		Filters:     filters,
+		SkillLabels: labels,
+	})
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	locale := service.Locale(req.Header().Get("Accept-Language"))
+	matches := make([]*matchingv1.Match, 0, len(result.Matches))
+	for _, match := range result.Matches {
+		candidate := user(match.Candidate)
+		if candidate != nil && match.CandidateAvailability != nil {
+			candidate.Availability = scheduling.ToProto(*match.CandidateAvailability)
+		}
+		matches = append(matches, &matchingv1.Match{
+			User:         candidate,
+			MatchScore:   match.Score,
+			SkillMatches: skillMatches(match.SkillMatches),
+			Explanation:  match.Reasons.Text(locale),
+			IsMutual:     match.IsMutual,
+		})
+	}
+	return connect.NewResponse(&matchingv1.FindMatchesResponse{
+		Matches: matches,
+		Metadata: &matchingv1.MatchingMetadata{
+			AlgorithmUsed:   algorithmEnum(result.Algorithm),
+			TotalCandidates: int32(result.TotalCandidates),
+			FilteredCount:   int32(result.FilteredCount),
+			ComputedAt:      presenter.Timestamp(result.ComputedAt),
+		},
+	}), nil
+}
+
+// GetMatchScore scores the caller against another user. Admins may score any pair.
+func (h *MatchingHandler) GetMatchScore(
+	ctx context.Context,
+	req *connect.Request[matchingv1.GetMatchScoreRequest],
+) (*connect.Response[matchingv1.GetMatchScoreResponse], error) {
+	claims, err := interceptors.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("authentication required"))
+	}
+	if req.Msg.UserId_1 == "" || req.Msg.UserId_2 == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("user_id_1 and user_id_2 are required"))
+	}
+	if claims.Role != "admin" && req.Msg.UserId_1 != claims.UserID && req.Msg.UserId_2 != claims.UserID {
+		return nil, connect.NewError(connect.CodePermissionDenied, errors.New("can only score matches involving yourself"))
+	}
+
+	match, err := h.service.Score(ctx, service.ScoreParams{
+		UserID:    req.Msg.UserId_1,
+		OtherID:   req.Msg.UserId_2,
+		Algorithm: algorithmKind(req.Msg.Algorithm),
+	})
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&matchingv1.GetMatchScoreResponse{
+		MatchScore:   match.Score,
+		SkillMatches: skillMatches(match.SkillMatches),
+		Explanation:  match.Reasons.Text(service.Locale(req.Header().Get("Accept-Language"))),
+		IsMutual:     match.IsMutual,
+	}), nil
+}
+
+// GetRecommendations suggests partners or skills to the caller.
+func (h *MatchingHandler) GetRecommendations(
+	ctx context.Context,
+	req *connect.Request[matchingv1.GetRecommendationsRequest],
+) (*connect.Response[matchingv1.GetRecommendationsResponse], error) {
+	userID, err := callerOrSelf(ctx, req.Msg.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	var kind service.RecommendationType
+	switch req.Msg.Type {
+	case matchingv1.RecommendationType_RECOMMENDATION_TYPE_UNSPECIFIED,
+		matchingv1.RecommendationType_RECOMMENDATION_TYPE_PARTNERS:
+		kind = service.RecommendPartners
+	case matchingv1.RecommendationType_RECOMMENDATION_TYPE_SKILLS:
+		kind = service.RecommendSkills
+	case matchingv1.RecommendationType_RECOMMENDATION_TYPE_SESSIONS:
+		kind = service.RecommendSessions
+	default:
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown recommendation type %v", req.Msg.Type))
+	}
+
+	recommendations, err := h.service.Recommendations(ctx, service.RecommendParams{
+		UserID: userID,
+		Limit:  int(req.Msg.Limit),
+		Type:   kind,
+		Locale: service.Locale(req.Header().Get("Accept-Language")),
+	})
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	out := make([]*matchingv1.Recommendation, 0, len(recommendations))
+	for _, r := range recommendations {
+		out = append(out, &matchingv1.Recommendation{
+			ItemId:         r.ItemID.String(),
+			ItemType:       r.ItemType,
+			Title:          r.Title,
+			Description:    r.Description,
+			RelevanceScore: r.Score,
+			Reason:         r.Reason,
+		})
+	}
+	return connect.NewResponse(&matchingv1.GetRecommendationsResponse{Recommendations: out}), nil
+}
+
+// GetSimilarUsers returns users whose skills resemble the caller's.
+func (h *MatchingHandler) GetSimilarUsers(
+	ctx context.Context,
+	req *connect.Request[matchingv1.GetSimilarUsersRequest],
+) (*connect.Response[matchingv1.GetSimilarUsersResponse], error) {
+	userID, err := callerOrSelf(ctx, req.Msg.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	similar, err := h.service.SimilarUsers(ctx, service.SimilarParams{
+		UserID:    userID,
+		Limit:     int(req.Msg.Limit),
+		Algorithm: algorithmKind(req.Msg.Algorithm),
+	})
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	users := make([]*matchingv1.SimilarUser, 0, len(similar))
+	for _, s := range similar {
+		users = append(users, &matchingv1.SimilarUser{
+			User:            user(&s.User),
+			SimilarityScore: s.Score,
+			CommonSkills:    s.CommonSkills,
+		})
+	}
+	return connect.NewResponse(&matchingv1.GetSimilarUsersResponse{Users: users}), nil
+}
+
+// GenerateEmbeddings embeds skill names, or the caller's own skills when none
+// are given.
+func (h *MatchingHandler) GenerateEmbeddings(
+	ctx context.Context,
+	req *connect.Request[matchingv1.GenerateEmbeddingsRequest],
+) (*connect.Response[matchingv1.GenerateEmbeddingsResponse], error) {
+	userID, err := callerOrSelf(ctx, req.Msg.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	embeddings, err := h.service.GenerateEmbeddings(ctx, service.EmbedParams{
+		UserID:     userID,
+		SkillNames: req.Msg.SkillNames,
+	})
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	out := make([]*matchingv1.SkillEmbedding, 0, len(embeddings))
+	for _, e := range embeddings {
+		out = append(out, &matchingv1.SkillEmbedding{
+			SkillName:    e.SkillName,
+			Vector:       e.Vector,
+			ModelVersion: e.Model,
+		})
+	}
+	return connect.NewResponse(&matchingv1.GenerateEmbeddingsResponse{Embeddings: out}), nil
+}
+
+// callerOrSelf returns the user a request is about: the caller when
+// requestUserID is empty, or requestUserID if it is the caller or the caller
+// is an admin.
+func callerOrSelf(ctx context.Context, requestUserID string) (string, error) {
+	claims, err := interceptors.GetClaimsFromContext(ctx)
+	if err != nil {
+		return "", connect.NewError(connect.CodeUnauthenticated, errors.New("authentication required"))
+	}
+	if requestUserID == "" || requestUserID == claims.UserID {
+		return claims.UserID, nil
+	}
+	if claims.Role != "admin" {
+		return "", connect.NewError(connect.CodePermissionDenied, errors.New("user_id does not match the authenticated user"))
+	}
+	return requestUserID, nil
+}
+
+func toConnectError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	case errors.Is(err, service.ErrEmbeddingsDisabled),
+		errors.Is(err, service.ErrCacheDisabled):
+		return connect.NewError(connect.CodeUnimplemented, err)
+	case errors.Is(err, service.ErrInvalidUserID),
+		errors.Is(err, service.ErrSameUser),
+		errors.Is(err, service.ErrUnknownAlgorithm),
+		errors.Is(err, service.ErrUnknownRecommend),
+		errors.Is(err, service.ErrInvalidMatchScore),
+		errors.Is(err, service.ErrUnknownSkillLabel),
+		errors.Is(err, service.ErrNoSkillNames),
+		errors.Is(err, service.ErrTooManyCandidates),
+		errors.Is(err, service.ErrTooManySkillNames):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	default:
+		return connect.NewError(connect.CodeInternal, err)
+	}
+}
+
+var algorithmKinds = map[matchingv1.MatchingAlgorithm]algorithms.Kind{
+	matchingv1.MatchingAlgorithm_MATCHING_ALGORITHM_EUCLIDEAN: algorithms.Euclidean,
+	matchingv1.MatchingAlgorithm_MATCHING_ALGORITHM_COSINE:    algorithms.Cosine,
+	matchingv1.MatchingAlgorithm_MATCHING_ALGORITHM_EMBEDDING: algorithms.Embedding,
+	matchingv1.MatchingAlgorithm_MATCHING_ALGORITHM_HYBRID:    algorithms.Hybrid,
+}
+
+// algorithmKind maps the enum to an algorithm; unspecified selects the
+// service default and unknown values are rejected by the service.
+func algorithmKind(alg matchingv1.MatchingAlgorithm) algorithms.Kind {
+	if alg == matchingv1.MatchingAlgorithm_MATCHING_ALGORITHM_UNSPECIFIED {
+		return ""
+	}
+	if kind, ok := algorithmKinds[alg]; ok {
+		return kind
+	}
+	return algorithms.Kind(alg.String())
+}
+
+func algorithmEnum(kind algorithms.Kind) matchingv1.MatchingAlgorithm {
+	for alg, k := range algorithmKinds {
+		if k == kind {
+			return alg
+		}
+	}
+	return matchingv1.MatchingAlgorithm_MATCHING_ALGORITHM_UNSPECIFIED
+}
+
+// Lowest proficiency (1-10) of each common.v1.ProficiencyLevel.
+var proficiencyFloor = map[commonv1.ProficiencyLevel]int{
+	commonv1.ProficiencyLevel_PROFICIENCY_LEVEL_BEGINNER:     1,
+	commonv1.ProficiencyLevel_PROFICIENCY_LEVEL_INTERMEDIATE: 4,
+	commonv1.ProficiencyLevel_PROFICIENCY_LEVEL_EXPERT:       8,
+}
+
+var subscriptionTiers = map[commonv1.SubscriptionTier]string{
+	commonv1.SubscriptionTier_SUBSCRIPTION_TIER_FREE:         "free",
+	commonv1.SubscriptionTier_SUBSCRIPTION_TIER_PREMIUM:      "premium",
+	commonv1.SubscriptionTier_SUBSCRIPTION_TIER_PROFESSIONAL: "pro",
+}
+
+// searchFilters converts request filters. skill_ids entries that are not
+// UUIDs are returned as skill labels, so clients can filter by a skill's name
+// or one of its alternative labels. Categories are not applied: the
+// SkillCategory enum has no mapping to skill_categories rows.
+func searchFilters(in *commonv1.SearchFilters) (repository.Filters, []string, error) {
+	if in == nil {
+		return repository.Filters{}, nil, nil
+	}
+	filters := repository.Filters{
+		VerifiedOnly:   in.VerifiedOnly,
+		MinRating:      in.MinRating,
+		MinProficiency: proficiencyFloor[in.MinProficiency],
+		MinTier:        subscriptionTiers[in.MinSubscription],
+	}
+	var labels []string
+	for _, raw := range in.SkillIds {
+		if id, err := uuid.Parse(raw); err == nil {
+			filters.SkillIDs = append(filters.SkillIDs, id)
+		} else if strings.TrimSpace(raw) != "" {
+			labels = append(labels, raw)
+		}
+	}
+	if in.MaxDistanceKm > 0 {
+		if in.Location == nil || (in.Location.Latitude == 0 && in.Location.Longitude == 0) {
+			return repository.Filters{}, nil, errors.New("max_distance_km requires a location with coordinates")
+		}
+		filters.Near = &repository.Location{Latitude: in.Location.Latitude, Longitude: in.Location.Longitude}
+		filters.MaxDistanceKm = int(in.MaxDistanceKm)
+	}
+	return filters, labels, nil
+}
+
+var tierEnums = map[string]commonv1.SubscriptionTier{
+	"free":    commonv1.SubscriptionTier_SUBSCRIPTION_TIER_FREE,
+	"premium": commonv1.SubscriptionTier_SUBSCRIPTION_TIER_PREMIUM,
+	"pro":     commonv1.SubscriptionTier_SUBSCRIPTION_TIER_PROFESSIONAL,
+}
+
+// user renders the public profile of a match; email is left out.
+func user(u *repository.User) *commonv1.User {
+	if u == nil {
+		return nil
+	}
+	out := &commonv1.User{
+		UserId:           u.ID.String(),
+		Username:         u.Username,
+		DisplayName:      u.DisplayName,
+		Bio:              u.Bio,
+		AvatarUrl:        u.AvatarURL,
+		Status:           commonv1.UserStatus_USER_STATUS_ACTIVE,
+		SubscriptionTier: tierEnums[u.SubscriptionTier],
+		AverageRating:    u.AverageRating,
+		TotalSessions:    int32(u.TotalSessions),
+		TotalReviews:     int32(u.TotalReviews),
+		CreatedAt:        presenter.Timestamp(u.CreatedAt),
+		IsVerified:       u.IsVerified,
+	}
+	if u.LastLoginAt != nil {
+		out.LastActiveAt = presenter.Timestamp(*u.LastLoginAt)
+	}
+	return out
+}
+
+func skillMatches(in []ontology.SkillMatchEvent) []*matchingv1.SkillMatch {
+	out := make([]*matchingv1.SkillMatch, 0, len(in))
+	for _, m := range in {
+		out = append(out, &matchingv1.SkillMatch{
+			SkillName:        m.SkillName,
+			UserProficiency:  int32(m.UserProficiency),
+			MatchProficiency: int32(m.MatchProficiency),
+			IsComplementary:  m.IsComplementary,
+		})
+	}
+	return out
+}
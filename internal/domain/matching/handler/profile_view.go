@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	pb "github.com/FACorreiaa/skillsphere-proto/gen/go/matching/v1/matchingv1connect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// RecordProfileViewProcedure records that the caller opened another user's
+// profile, as feedback on the match that showed them the user. MatchingService
+// has no message for it yet, so the request is a google.protobuf.Struct of the
+// form {"viewed_user_id": "…"} and the response is {"attributed": true} when
+// the view was attributed to a recent match.
+const RecordProfileViewProcedure = "/" + pb.MatchingServiceName + "/RecordProfileView"
+
+// NewRecordProfileViewHandler builds the HTTP handler for
+// RecordProfileViewProcedure and returns the path to mount it on, like the
+// generated constructors.
+func NewRecordProfileViewHandler(h *MatchingHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	return RecordProfileViewProcedure, connect.NewUnaryHandler(RecordProfileViewProcedure, h.RecordProfileView, opts...)
+}
+
+// RecordProfileView records the caller's profile view.
+func (h *MatchingHandler) RecordProfileView(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[structpb.Struct], error) {
+	userID, err := callerOrSelf(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	viewed := req.Msg.GetFields()["viewed_user_id"].GetStringValue()
+	if viewed == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("viewed_user_id is required"))
+	}
+
+	attributed, err := h.service.RecordProfileView(ctx, userID, viewed)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	resp, err := structpb.NewStruct(map[string]any{"attributed": attributed})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(resp), nil
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	pb "github.com/FACorreiaa/skillsphere-proto/gen/go/matching/v1/matchingv1connect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// SkipRecommendedPartnerProcedure stops GetRecommendations from suggesting a
+// partner to the caller again. MatchingService has no message for it yet, so
+// the request is a google.protobuf.Struct of the form
+// {"skipped_user_id": "…", "user_id": "…"}, where user_id defaults to the
+// caller and only admins may set it to someone else. The response is an
+// empty Struct.
+const SkipRecommendedPartnerProcedure = "/" + pb.MatchingServiceName + "/SkipRecommendedPartner"
+
+// NewSkipRecommendedPartnerHandler builds the HTTP handler for
+// SkipRecommendedPartnerProcedure and returns the path to mount it on, like
+// the generated constructors.
+func NewSkipRecommendedPartnerHandler(h *MatchingHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	return SkipRecommendedPartnerProcedure, connect.NewUnaryHandler(SkipRecommendedPartnerProcedure, h.SkipRecommendedPartner, opts...)
+}
+
+// SkipRecommendedPartner records that the caller passed on a recommended partner.
+func (h *MatchingHandler) SkipRecommendedPartner(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[structpb.Struct], error) {
+	userID, err := callerOrSelf(ctx, req.Msg.GetFields()["user_id"].GetStringValue())
+	if err != nil {
+		return nil, err
+	}
+	skipped := req.Msg.GetFields()["skipped_user_id"].GetStringValue()
+	if skipped == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("skipped_user_id is required"))
+	}
+
+	if err := h.service.SkipPartner(ctx, userID, skipped); err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&structpb.Struct{}), nil
+}
@@ -0,0 +1,116 @@
+package ranking
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrInvalidModel is returned for models that cannot score features.
+var ErrInvalidModel = errors.New("invalid ranking model")
+
+// Model is a logistic regression over standardized features. It is stored
+// as JSON and never changes once trained, so it is safe for concurrent use.
+type Model struct {
+	// Version names the model in match_history and in AlgorithmIRI; it must
+	// be a single IRI path segment.
+	Version   string    `json:"version"`
+	TrainedAt time.Time `json:"trained_at"`
+	Features  []string  `json:"features"`
+	// Mean and Scale standardize each feature before it is weighted.
+	Mean    []float64 `json:"mean"`
+	Scale   []float64 `json:"scale"`
+	Weights []float64 `json:"weights"`
+	Bias    float64   `json:"bias"`
+	// Examples counts the examples the model was trained on.
+	Examples int `json:"examples"`
+}
+
+// Predict returns the modelled probability that the match leads somewhere.
+func (m *Model) Predict(features Features) float64 {
+	z := m.Bias
+	for i, name := range m.Features {
+		z += m.Weights[i] * (features[name] - m.Mean[i]) / m.Scale[i]
+	}
+	return sigmoid(z)
+}
+
+// Validate checks that the model is complete.
+func (m *Model) Validate() error {
+	n := len(m.Features)
+	switch {
+	case m.Version == "" || !validVersion(m.Version):
+		return fmt.Errorf("%w: version %q must be letters, digits, '.', '_' or '-'", ErrInvalidModel, m.Version)
+	case n == 0 || len(m.Mean) != n || len(m.Scale) != n || len(m.Weights) != n:
+		return fmt.Errorf("%w: %d features with %d means, %d scales and %d weights",
+			ErrInvalidModel, n, len(m.Mean), len(m.Scale), len(m.Weights))
+	case !finite(m.Bias):
+		return fmt.Errorf("%w: bias is %v", ErrInvalidModel, m.Bias)
+	}
+	for i := range m.Features {
+		if !finite(m.Mean[i]) || !finite(m.Weights[i]) || !finite(m.Scale[i]) || m.Scale[i] <= 0 {
+			return fmt.Errorf("%w: feature %s", ErrInvalidModel, m.Features[i])
+		}
+	}
+	return nil
+}
+
+// Load reads and validates a model saved by Save.
+func Load(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load ranking model: %w", err)
+	}
+	var m Model
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidModel, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Save writes the model as JSON, replacing path atomically.
+func (m *Model) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("save ranking model: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save ranking model: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save ranking model: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func validVersion(version string) bool {
+	for _, r := range version {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
+
+func finite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
@@ -0,0 +1,145 @@
+// Package ranking learns to order matches from what users did with them.
+//
+// Every recorded match stores the Features it was shown with. Feedback
+// attributed to the match later (a profile view, a chat, a session and its
+// rating) becomes its Label. Train fits a logistic regression over features
+// and labels offline, and the resulting Model re-ranks FindMatches results
+// for users in the treatment arm of the Flag experiment.
+package ranking
+
+import (
+	"math"
+	"time"
+)
+
+// Flag is the feature flag whose rollout puts users in the treatment arm.
+const Flag = "matching_learned_ranking"
+
+// Experiment arms, stored in match_history.variant.
+const (
+	VariantControl   = "control"
+	VariantTreatment = "treatment"
+)
+
+// Feature names. Values are scaled to roughly 0-1.
+const (
+	// FeatureScore is the match score, availability included.
+	FeatureScore = "score"
+	// FeatureSkillScore is the algorithm's score before availability.
+	FeatureSkillScore = "skill_score"
+	// FeatureMutual is 1 when each user offers something the other wants.
+	FeatureMutual = "mutual"
+	// FeaturePairs counts complementary skill pairs, saturating at five.
+	FeaturePairs = "pairs"
+	// FeatureOverlap is weekly shared free time against the policy target.
+	FeatureOverlap = "overlap"
+	// FeatureRating is the candidate's average rating out of five.
+	FeatureRating = "rating"
+	// FeatureSessions is the candidate's completed sessions, log-scaled so a
+	// hundred count as 1.
+	FeatureSessions = "sessions"
+	// FeatureVerified is 1 for verified candidates.
+	FeatureVerified = "verified"
+	// FeatureRecentlyActive is 1 when the candidate logged in within
+	// RecentActivity.
+	FeatureRecentlyActive = "recently_active"
+)
+
+// FeatureNames lists the features models are trained on by default.
+var FeatureNames = []string{
+	FeatureScore, FeatureSkillScore, FeatureMutual, FeaturePairs, FeatureOverlap,
+	FeatureRating, FeatureSessions, FeatureVerified, FeatureRecentlyActive,
+}
+
+// RecentActivity is the login window of FeatureRecentlyActive.
+const RecentActivity = 30 * 24 * time.Hour
+
+// Features maps feature names to values. Missing features count as 0.
+type Features map[string]float64
+
+// Candidate is what features are computed from.
+type Candidate struct {
+	Score       float64
+	SkillScore  float64
+	Mutual      bool
+	Pairs       int
+	Overlap     float64
+	Rating      float64
+	Sessions    int
+	Verified    bool
+	LastLoginAt *time.Time
+}
+
+// NewFeatures computes the features of a candidate at now.
+func NewFeatures(c Candidate, now time.Time) Features {
+	return Features{
+		FeatureScore:          c.Score,
+		FeatureSkillScore:     c.SkillScore,
+		FeatureMutual:         indicator(c.Mutual),
+		FeaturePairs:          math.Min(float64(c.Pairs), 5) / 5,
+		FeatureOverlap:        c.Overlap,
+		FeatureRating:         c.Rating / 5,
+		FeatureSessions:       math.Min(1, math.Log1p(float64(c.Sessions))/math.Log1p(100)),
+		FeatureVerified:       indicator(c.Verified),
+		FeatureRecentlyActive: indicator(c.LastLoginAt != nil && now.Sub(*c.LastLoginAt) <= RecentActivity),
+	}
+}
+
+func indicator(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Feedback events, stored in match_feedback.event. A shown match is its
+// match_history row, so it has no event of its own.
+const (
+	EventProfileViewed    = "profile_viewed"
+	EventChatStarted      = "chat_started"
+	EventSessionBooked    = "session_booked"
+	EventSessionCompleted = "session_completed"
+	EventSessionRated     = "session_rated"
+)
+
+// Outcome is the feedback a shown match received.
+type Outcome struct {
+	Events []string
+	// Rating is the lowest session rating, 1-5, or 0 when unrated.
+	Rating int
+}
+
+// Label turns an outcome into a training target and its weight. The deeper
+// the engagement, the more the example weighs; a session rated 1 or 2 is a
+// negative that outweighs a match nobody acted on.
+func Label(o Outcome) (label, weight float64) {
+	has := func(event string) bool {
+		for _, e := range o.Events {
+			if e == event {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case o.Rating > 0 && o.Rating <= 2:
+		return 0, 2
+	case o.Rating >= 3 || has(EventSessionCompleted):
+		return 1, 3
+	case has(EventSessionBooked):
+		return 1, 2
+	case has(EventChatStarted):
+		return 1, 1
+	case has(EventProfileViewed):
+		return 1, 0.5
+	default:
+		return 0, 1
+	}
+}
+
+// Example is a labelled match.
+type Example struct {
+	Features Features
+	Label    float64
+	Weight   float64
+}
@@ -0,0 +1,166 @@
+package ranking
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		name          string
+		outcome       Outcome
+		label, weight float64
+	}{
+		{"ignored", Outcome{}, 0, 1},
+		{"viewed", Outcome{Events: []string{EventProfileViewed}}, 1, 0.5},
+		{"chatted", Outcome{Events: []string{EventProfileViewed, EventChatStarted}}, 1, 1},
+		{"booked", Outcome{Events: []string{EventChatStarted, EventSessionBooked}}, 1, 2},
+		{"completed", Outcome{Events: []string{EventSessionBooked, EventSessionCompleted}}, 1, 3},
+		{"rated well", Outcome{Events: []string{EventSessionRated}, Rating: 4}, 1, 3},
+		{"rated badly", Outcome{Events: []string{EventSessionCompleted, EventSessionRated}, Rating: 2}, 0, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label, weight := Label(tt.outcome)
+			if label != tt.label || weight != tt.weight {
+				t.Fatalf("Label = (%v, %v), want (%v, %v)", label, weight, tt.label, tt.weight)
+			}
+		})
+	}
+}
+
+func TestNewFeatures(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-24 * time.Hour)
+	f := NewFeatures(Candidate{
+		Score: 0.8, SkillScore: 0.9, Mutual: true, Pairs: 7, Overlap: 0.5,
+		Rating: 4, Sessions: 100, Verified: true, LastLoginAt: &recent,
+	}, now)
+	want := Features{
+		FeatureScore: 0.8, FeatureSkillScore: 0.9, FeatureMutual: 1, FeaturePairs: 1, FeatureOverlap: 0.5,
+		FeatureRating: 0.8, FeatureSessions: 1, FeatureVerified: 1, FeatureRecentlyActive: 1,
+	}
+	for name, value := range want {
+		if math.Abs(f[name]-value) > 1e-9 {
+			t.Errorf("%s = %v, want %v", name, f[name], value)
+		}
+	}
+
+	stale := now.Add(-RecentActivity - time.Hour)
+	if f := NewFeatures(Candidate{LastLoginAt: &stale}, now); f[FeatureRecentlyActive] != 0 {
+		t.Fatalf("stale login counted as recent")
+	}
+}
+
+// examples are positive exactly when the candidate is verified, whatever
+// their score.
+func examples() []Example {
+	var out []Example
+	for i := range 200 {
+		verified := float64(i % 2)
+		score := float64(i%10) / 10
+		out = append(out, Example{
+			Features: Features{FeatureScore: score, FeatureVerified: verified},
+			Label:    verified,
+			Weight:   1,
+		})
+	}
+	return out
+}
+
+func TestTrain(t *testing.T) {
+	opts := DefaultTrainOptions("v1")
+	opts.Features = []string{FeatureScore, FeatureVerified}
+	m, err := Train(examples(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Weights[1] <= m.Weights[0] {
+		t.Fatalf("weights %v should favour verified", m.Weights)
+	}
+	if p, q := m.Predict(Features{FeatureVerified: 1}), m.Predict(Features{FeatureScore: 0.9}); p <= q {
+		t.Fatalf("verified scored %v, high score %v", p, q)
+	}
+
+	model, baseline := Evaluate(m, examples()), Baseline(FeatureScore, examples())
+	if model.AUC < 0.99 || model.LogLoss > 0.2 {
+		t.Fatalf("model metrics %+v", model)
+	}
+	if baseline.AUC > 0.6 {
+		t.Fatalf("baseline AUC %v, want about 0.5", baseline.AUC)
+	}
+
+	again, err := Train(examples(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Weights[0] != m.Weights[0] || again.Bias != m.Bias {
+		t.Fatal("training is not deterministic")
+	}
+}
+
+func TestTrain_NeedsBothLabels(t *testing.T) {
+	only := []Example{{Features: Features{FeatureScore: 1}, Label: 1, Weight: 1}}
+	if _, err := Train(only, DefaultTrainOptions("v1")); !errors.Is(err, ErrNoExamples) {
+		t.Fatalf("Train error = %v, want ErrNoExamples", err)
+	}
+	if _, err := Train(nil, DefaultTrainOptions("v1")); !errors.Is(err, ErrNoExamples) {
+		t.Fatalf("Train error = %v, want ErrNoExamples", err)
+	}
+}
+
+func TestAUC(t *testing.T) {
+	ex := []Example{{Label: 0, Weight: 1}, {Label: 1, Weight: 1}, {Label: 0, Weight: 1}, {Label: 1, Weight: 1}}
+	if got := AUC([]float64{0.1, 0.9, 0.2, 0.8}, ex); got != 1 {
+		t.Fatalf("AUC = %v, want 1", got)
+	}
+	if got := AUC([]float64{0.5, 0.5, 0.5, 0.5}, ex); got != 0.5 {
+		t.Fatalf("AUC of ties = %v, want 0.5", got)
+	}
+	if got := AUC([]float64{0.9, 0.1, 0.8, 0.2}, ex); got != 0 {
+		t.Fatalf("AUC = %v, want 0", got)
+	}
+}
+
+func TestModel_SaveLoad(t *testing.T) {
+	opts := DefaultTrainOptions("2026-03-v1")
+	m, err := Train(examples(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := m.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := Features{FeatureScore: 0.4, FeatureVerified: 1}
+	if loaded.Version != m.Version || loaded.Predict(f) != m.Predict(f) {
+		t.Fatalf("loaded model differs: %+v", loaded)
+	}
+}
+
+func TestModel_Validate(t *testing.T) {
+	valid := Model{Version: "v1", Features: []string{FeatureScore}, Mean: []float64{0}, Scale: []float64{1}, Weights: []float64{1}}
+	if err := valid.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for name, mutate := range map[string]func(*Model){
+		"no version":     func(m *Model) { m.Version = "" },
+		"path version":   func(m *Model) { m.Version = "v1/control" },
+		"missing weight": func(m *Model) { m.Weights = nil },
+		"zero scale":     func(m *Model) { m.Scale = []float64{0} },
+		"nan bias":       func(m *Model) { m.Bias = math.NaN() },
+	} {
+		m := valid
+		mutate(&m)
+		if err := m.Validate(); !errors.Is(err, ErrInvalidModel) {
+			t.Errorf("%s: Validate error = %v, want ErrInvalidModel", name, err)
+		}
+	}
+}
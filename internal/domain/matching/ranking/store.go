@@ -0,0 +1,51 @@
+package ranking
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LoadExamples labels the matches shown in [from, to) that recorded their
+// features with the feedback attributed to them. Feedback keeps arriving
+// for weeks, so to should leave recent matches time to collect it.
+func LoadExamples(ctx context.Context, db *sql.DB, from, to time.Time) ([]Example, error) {
+	rows, err := db.QueryContext(ctx, `
+		-- name: LoadRankingExamples
+		SELECT mh.features::text, COALESCE(string_agg(DISTINCT f.event, ','), ''), COALESCE(MIN(f.rating), 0)
+		FROM match_history mh
+		LEFT JOIN match_feedback f ON f.match_id = mh.id
+		WHERE mh.features IS NOT NULL AND mh.created_at >= $1 AND mh.created_at < $2
+		GROUP BY mh.id
+		ORDER BY mh.created_at, mh.id
+	`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("load ranking examples: %w", err)
+	}
+	defer rows.Close()
+
+	var examples []Example
+	for rows.Next() {
+		var (
+			raw     string
+			events  string
+			outcome Outcome
+			e       Example
+		)
+		if err := rows.Scan(&raw, &events, &outcome.Rating); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(raw), &e.Features); err != nil {
+			return nil, fmt.Errorf("decode match features: %w", err)
+		}
+		if events != "" {
+			outcome.Events = strings.Split(events, ",")
+		}
+		e.Label, e.Weight = Label(outcome)
+		examples = append(examples, e)
+	}
+	return examples, rows.Err()
+}
@@ -0,0 +1,192 @@
+package ranking
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ErrNoExamples is returned when the examples cannot train a model: there
+// are none, or all share one label.
+var ErrNoExamples = errors.New("not enough labelled examples")
+
+// TrainOptions controls Train.
+type TrainOptions struct {
+	// Version names the trained model.
+	Version string
+	// Features defaults to FeatureNames.
+	Features     []string
+	Epochs       int
+	LearningRate float64
+	// L2 penalizes large weights; the bias is not penalized.
+	L2 float64
+}
+
+// DefaultTrainOptions trains the default features for 500 epochs.
+func DefaultTrainOptions(version string) TrainOptions {
+	return TrainOptions{Version: version, Features: FeatureNames, Epochs: 500, LearningRate: 0.5, L2: 0.001}
+}
+
+// Train fits a logistic regression to the weighted examples by full-batch
+// gradient descent on standardized features, so the same examples always
+// give the same model.
+func Train(examples []Example, opts TrainOptions) (*Model, error) {
+	if len(opts.Features) == 0 {
+		opts.Features = FeatureNames
+	}
+	if opts.Epochs <= 0 || opts.LearningRate <= 0 || opts.L2 < 0 {
+		return nil, fmt.Errorf("train ranking model: epochs and learning rate must be positive, L2 not negative")
+	}
+	var positive, negative bool
+	var total float64
+	for _, e := range examples {
+		if e.Weight <= 0 {
+			continue
+		}
+		positive = positive || e.Label >= 0.5
+		negative = negative || e.Label < 0.5
+		total += e.Weight
+	}
+	if !positive || !negative {
+		return nil, ErrNoExamples
+	}
+
+	n := len(opts.Features)
+	m := &Model{
+		Version:   opts.Version,
+		TrainedAt: time.Now().UTC(),
+		Features:  append([]string(nil), opts.Features...),
+		Mean:      make([]float64, n),
+		Scale:     make([]float64, n),
+		Weights:   make([]float64, n),
+		Examples:  len(examples),
+	}
+
+	// Weighted mean and standard deviation; constant features keep scale 1
+	// and end up with no weight.
+	for _, e := range examples {
+		for i, name := range m.Features {
+			m.Mean[i] += e.Weight * e.Features[name]
+		}
+	}
+	for i := range m.Mean {
+		m.Mean[i] /= total
+	}
+	for _, e := range examples {
+		for i, name := range m.Features {
+			d := e.Features[name] - m.Mean[i]
+			m.Scale[i] += e.Weight * d * d
+		}
+	}
+	for i := range m.Scale {
+		m.Scale[i] = math.Sqrt(m.Scale[i] / total)
+		if m.Scale[i] < 1e-9 {
+			m.Scale[i] = 1
+		}
+	}
+
+	x := make([][]float64, len(examples))
+	for j, e := range examples {
+		x[j] = make([]float64, n)
+		for i, name := range m.Features {
+			x[j][i] = (e.Features[name] - m.Mean[i]) / m.Scale[i]
+		}
+	}
+
+	grad := make([]float64, n)
+	for range opts.Epochs {
+		clear(grad)
+		var gradBias float64
+		for j, e := range examples {
+			z := m.Bias
+			for i := range grad {
+				z += m.Weights[i] * x[j][i]
+			}
+			residual := e.Weight * (sigmoid(z) - e.Label)
+			for i := range grad {
+				grad[i] += residual * x[j][i]
+			}
+			gradBias += residual
+		}
+		for i := range grad {
+			m.Weights[i] -= opts.LearningRate * (grad[i]/total + opts.L2*m.Weights[i])
+		}
+		m.Bias -= opts.LearningRate * gradBias / total
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Metrics summarize how well scores separate labelled examples.
+type Metrics struct {
+	// AUC is the probability that a positive outscores a negative.
+	AUC float64
+	// LogLoss is the weighted cross-entropy of the model's predictions; it is
+	// 0 for baselines whose scores are not probabilities.
+	LogLoss float64
+}
+
+// Evaluate scores the examples with the model.
+func Evaluate(m *Model, examples []Example) Metrics {
+	scores := make([]float64, len(examples))
+	var loss, total float64
+	for j, e := range examples {
+		p := math.Min(math.Max(m.Predict(e.Features), 1e-12), 1-1e-12)
+		scores[j] = p
+		loss -= e.Weight * (e.Label*math.Log(p) + (1-e.Label)*math.Log(1-p))
+		total += e.Weight
+	}
+	metrics := Metrics{AUC: AUC(scores, examples)}
+	if total > 0 {
+		metrics.LogLoss = loss / total
+	}
+	return metrics
+}
+
+// Baseline measures how well a single feature ranks the examples on its
+// own, such as FeatureScore for the ranking the model replaces.
+func Baseline(feature string, examples []Example) Metrics {
+	scores := make([]float64, len(examples))
+	for j, e := range examples {
+		scores[j] = e.Features[feature]
+	}
+	return Metrics{AUC: AUC(scores, examples)}
+}
+
+// AUC is the weighted area under the ROC curve of scores against the
+// examples' labels, with ties counting half. It is 0.5 without both labels.
+func AUC(scores []float64, examples []Example) float64 {
+	order := make([]int, len(scores))
+	for j := range order {
+		order[j] = j
+	}
+	sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] < scores[order[b]] })
+
+	// Walk up the scores; each positive beats the negatives below it.
+	var below, area, positives, negatives float64
+	for start := 0; start < len(order); {
+		end := start
+		var pos, neg float64
+		for ; end < len(order) && scores[order[end]] == scores[order[start]]; end++ {
+			e := examples[order[end]]
+			if e.Label >= 0.5 {
+				pos += e.Weight
+			} else {
+				neg += e.Weight
+			}
+		}
+		area += pos * (below + neg/2)
+		below += neg
+		positives += pos
+		negatives += neg
+		start = end
+	}
+	if positives == 0 || negatives == 0 {
+		return 0.5
+	}
+	return area / (positives * negatives)
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+)
+
+// CachedRecommendation is a row of recommendation_cache.
+type CachedRecommendation struct {
+	ItemID      uuid.UUID
+	Title       string
+	Description string
+	Score       float64
+	Reason      string
+	// Explanation is the structured explanation of a partner as JSON, or
+	// nil. Reason holds it rendered in the default locale.
+	Explanation []byte
+}
+
+// CacheEntry is a user's cached recommendations of one item type.
+type CacheEntry struct {
+	Items       []CachedRecommendation
+	GeneratedAt time.Time
+	// Queued is set while a profile edit waits to be recomputed.
+	Queued bool
+}
+
+// RecommendationCache stores computed recommendations and the queue of users
+// whose recommendations are stale.
+type RecommendationCache interface {
+	// CachedRecommendations returns up to limit of the user's cached items of
+	// itemType, best first. Partners who became inactive or were contacted,
+	// blocked or skipped since are left out. ok is false if the type was never
+	// computed for the user.
+	CachedRecommendations(ctx context.Context, userID uuid.UUID, itemType string, limit int) (entry CacheEntry, ok bool, err error)
+	// StoreRecommendations replaces the user's cached items of itemType.
+	StoreRecommendations(ctx context.Context, userID uuid.UUID, itemType string, items []CachedRecommendation, generatedAt time.Time) error
+	// EnqueueRefresh queues users for a recompute; queued users stay queued once.
+	EnqueueRefresh(ctx context.Context, userIDs []uuid.UUID) error
+	// ClaimRefresh dequeues up to limit users queued at least settle ago,
+	// oldest first.
+	ClaimRefresh(ctx context.Context, limit int, settle time.Duration) ([]uuid.UUID, error)
+	// SkipPartner stops recommending skipped to the user.
+	SkipPartner(ctx context.Context, userID, skipped uuid.UUID) error
+}
+
+// PostgresRecommendationCache implements RecommendationCache on Postgres.
+type PostgresRecommendationCache struct {
+	db *db.SQL
+}
+
+// NewPostgresRecommendationCache constructs the cache. Cached entries are
+// read from replicas; see db.SQL.
+func NewPostgresRecommendationCache(db *db.SQL) *PostgresRecommendationCache {
+	return &PostgresRecommendationCache{db: db}
+}
+
+// CachedRecommendations checks each cached partner against
+// recommendation_excluded, so a block or a new conversation takes effect
+// before the entry is recomputed.
+func (c *PostgresRecommendationCache) CachedRecommendations(ctx context.Context, userID uuid.UUID, itemType string, limit int) (CacheEntry, bool, error) {
+	var entry CacheEntry
+	reader := c.db.Read(ctx)
+	err := reader.QueryRowContext(ctx, `
+		-- name: GetRecommendationCacheState
+		SELECT st.generated_at, EXISTS (SELECT 1 FROM recommendation_refresh_queue q WHERE q.user_id = st.user_id)
+		FROM recommendation_cache_state st
+		WHERE st.user_id = $1 AND st.item_type = $2
+	`, userID, itemType).Scan(&entry.GeneratedAt, &entry.Queued)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entry, false, nil
+	}
+	if err != nil {
+		return entry, false, fmt.Errorf("load recommendation cache state: %w", err)
+	}
+
+	query := `
+		-- name: GetCachedRecommendations
+		SELECT c.item_id::uuid, c.title, c.description, c.relevance_score, COALESCE(c.reason, ''), COALESCE(c.explanation::text, '')
+		FROM recommendation_cache c
+		WHERE c.user_id = $1 AND c.item_type = $2
+			AND CASE c.item_type
+				WHEN 'user' THEN EXISTS (
+						SELECT 1 FROM users u
+						WHERE u.id = c.item_id::uuid AND u.is_active AND u.deleted_at IS NULL)
+					AND NOT recommendation_excluded($1, c.item_id::uuid)
+				WHEN 'skill' THEN NOT EXISTS (
+						SELECT 1 FROM user_skills us
+						WHERE us.user_id = $1 AND us.skill_id = c.item_id::uuid)
+				ELSE true
+			END
+		ORDER BY c.relevance_score DESC, c.item_id
+		LIMIT $3
+	`
+	rows, err := reader.QueryContext(ctx, query, userID, itemType, limit)
+	if err != nil {
+		return entry, false, fmt.Errorf("load cached recommendations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			item        CachedRecommendation
+			explanation string
+		)
+		if err := rows.Scan(&item.ItemID, &item.Title, &item.Description, &item.Score, &item.Reason, &explanation); err != nil {
+			return entry, false, err
+		}
+		if explanation != "" {
+			item.Explanation = []byte(explanation)
+		}
+		entry.Items = append(entry.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return entry, false, err
+	}
+	return entry, true, nil
+}
+
+// StoreRecommendations swaps the items and the state row in one transaction,
+// so readers see either the old entry or the new one.
+func (c *PostgresRecommendationCache) StoreRecommendations(ctx context.Context, userID uuid.UUID, itemType string, items []CachedRecommendation, generatedAt time.Time) error {
+	ids := make([]string, len(items))
+	titles := make([]string, len(items))
+	descriptions := make([]string, len(items))
+	scores := make([]float64, len(items))
+	reasons := make([]string, len(items))
+	explanations := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ItemID.String()
+		titles[i] = item.Title
+		descriptions[i] = item.Description
+		// relevance_score is NUMERIC(5, 4).
+		scores[i] = math.Round(item.Score*10000) / 10000
+		reasons[i] = item.Reason
+		explanations[i] = string(item.Explanation)
+	}
+
+	tx, err := c.db.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("store recommendations: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		-- name: ClearCachedRecommendations
+		DELETE FROM recommendation_cache WHERE user_id = $1 AND item_type = $2
+	`, userID, itemType); err != nil {
+		return fmt.Errorf("clear cached recommendations: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		-- name: StoreCachedRecommendations
+		INSERT INTO recommendation_cache (user_id, item_type, item_id, title, description, relevance_score, reason, explanation, generated_at)
+		SELECT $1, $2, item_id, title, description, score, NULLIF(reason, ''), NULLIF(explanation, '')::jsonb, $3
+		FROM UNNEST($4::text[], $5::text[], $6::text[], $7::numeric[], $8::text[], $9::text[])
+			AS i(item_id, title, description, score, reason, explanation)
+		ON CONFLICT (user_id, item_type, item_id) DO NOTHING
+	`, userID, itemType, generatedAt, ids, titles, descriptions, scores, reasons, explanations); err != nil {
+		return fmt.Errorf("store cached recommendations: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		-- name: StoreRecommendationCacheState
+		INSERT INTO recommendation_cache_state (user_id, item_type, generated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, item_type) DO UPDATE SET generated_at = EXCLUDED.generated_at
+	`, userID, itemType, generatedAt); err != nil {
+		return fmt.Errorf("store recommendation cache state: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	c.db.MarkWrite(ctx)
+	return nil
+}
+
+// EnqueueRefresh ignores users that no longer exist.
+func (c *PostgresRecommendationCache) EnqueueRefresh(ctx context.Context, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	if _, err := c.db.Write(ctx).ExecContext(ctx, `
+		-- name: EnqueueRecommendationRefresh
+		INSERT INTO recommendation_refresh_queue (user_id)
+		SELECT id FROM users WHERE id = ANY($1::uuid[])
+		ON CONFLICT (user_id) DO NOTHING
+	`, uuidStrings(userIDs)); err != nil {
+		return fmt.Errorf("enqueue recommendation refresh: %w", err)
+	}
+	return nil
+}
+
+// ClaimRefresh deletes the rows it returns, so a user edited again while
+// being recomputed is queued afresh. Rows locked by writers that have not
+// committed are skipped until their edit is visible.
+func (c *PostgresRecommendationCache) ClaimRefresh(ctx context.Context, limit int, settle time.Duration) ([]uuid.UUID, error) {
+	rows, err := c.db.Write(ctx).QueryContext(ctx, `
+		-- name: ClaimRecommendationRefresh
+		DELETE FROM recommendation_refresh_queue
+		WHERE user_id IN (
+			SELECT user_id FROM recommendation_refresh_queue
+			WHERE enqueued_at <= NOW() - make_interval(secs => $2)
+			ORDER BY enqueued_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING user_id
+	`, limit, settle.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim recommendation refresh: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// SkipPartner records the skip; cached entries are filtered on read.
+func (c *PostgresRecommendationCache) SkipPartner(ctx context.Context, userID, skipped uuid.UUID) error {
+	if _, err := c.db.Write(ctx).ExecContext(ctx, `
+		-- name: SkipRecommendedPartner
+		INSERT INTO recommendation_skips (user_id, skipped_user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, skipped_user_id) DO UPDATE SET skipped_at = NOW()
+	`, userID, skipped); err != nil {
+		return fmt.Errorf("skip partner: %w", err)
+	}
+	return nil
+}
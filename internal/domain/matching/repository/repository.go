@@ -0,0 +1,620 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/algorithms"
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/vectors"
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+)
+
+// ErrUserNotFound is returned when a user does not exist or was deleted.
+var ErrUserNotFound = errors.New("user not found")
+
+// Match is a row in match_history.
+type Match struct {
+	ID          uuid.UUID
+	RequesterID uuid.UUID
+	CandidateID uuid.UUID
+	Algorithm   algorithms.Kind
+	Score       float64
+	// Features are the ranking features the match was shown with; nil leaves
+	// features NULL.
+	Features map[string]float64
+	// ModelVersion and Variant name the ranking model and experiment arm, and
+	// are empty when no model was loaded.
+	ModelVersion string
+	Variant      string
+	// Explanation is the structured explanation as JSON; nil leaves
+	// explanation NULL.
+	Explanation []byte
+	CreatedAt   time.Time
+}
+
+// Feedback is an event between two users, attributed to their latest match.
+type Feedback struct {
+	ActorID uuid.UUID
+	OtherID uuid.UUID
+	// Event is one of the match_feedback events; see the ranking package.
+	Event string
+	// SessionID is uuid.Nil for events without a session.
+	SessionID uuid.UUID
+	// Rating is 1-5 for session_rated events and 0 otherwise.
+	Rating int
+}
+
+// User is the public part of a user shown next to a match.
+type User struct {
+	ID               uuid.UUID
+	Username         string
+	DisplayName      string
+	Bio              string
+	AvatarURL        string
+	IsVerified       bool
+	SubscriptionTier string
+	AverageRating    float64
+	TotalSessions    int
+	TotalReviews     int
+	CreatedAt        time.Time
+	LastLoginAt      *time.Time
+	// Languages are the BCP 47 tags of the languages the user speaks.
+	Languages []string
+}
+
+// Availability is a row of user_availability.
+type Availability struct {
+	Timezone string
+	// WeeklySchedule is the raw weekly_schedule JSON; see scheduling.ParseWeeklySchedule.
+	WeeklySchedule []byte
+}
+
+// Location is a point to measure candidate distance from.
+type Location struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Filters narrow the candidates considered for a user.
+type Filters struct {
+	// SkillIDs keeps candidates offering at least one of the skills.
+	SkillIDs     []uuid.UUID
+	VerifiedOnly bool
+	MinRating    float64
+	// MinProficiency ignores offered skills below this level.
+	MinProficiency int
+	// MinTier is a subscription_tier value; empty means any.
+	MinTier       string
+	Near          *Location
+	MaxDistanceKm int
+}
+
+// CandidateQuery selects users worth scoring against UserID.
+type CandidateQuery struct {
+	UserID uuid.UUID
+	// Similar selects users who list the same skills the same way, for
+	// similar-user lookups, instead of users who complement UserID.
+	Similar bool
+	// ExcludeKnown drops users UserID already contacted or skipped, as
+	// recommendation_excluded defines them.
+	ExcludeKnown bool
+	Limit        int
+	Filters      Filters
+}
+
+// SkillRecommendation is a skill the user does not list yet.
+type SkillRecommendation struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	// Peers counts users who share a skill with the user and list this one.
+	Peers int
+	// Popularity counts users offering or wanting the skill.
+	Popularity int
+}
+
+// RecommendationRepository reads profiles for matching and records matches.
+type RecommendationRepository interface {
+	// SaveMatches appends matches to match_history and returns them with
+	// their ids and creation time.
+	SaveMatches(ctx context.Context, matches []Match) ([]Match, error)
+	// RecordFeedback attributes feedback to the pair's latest match of the
+	// last 30 days and returns it; ok is false when there is none.
+	RecordFeedback(ctx context.Context, feedback Feedback) (matchID uuid.UUID, ok bool, err error)
+	// GetExplanations returns the explanation JSON of the requester's latest
+	// recorded match with each candidate, leaving out candidates without one.
+	GetExplanations(ctx context.Context, requesterID uuid.UUID, candidateIDs []uuid.UUID) (map[uuid.UUID][]byte, error)
+	// GetProfiles returns the skills of each existing user in userIDs.
+	GetProfiles(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]algorithms.Profile, error)
+	GetUsers(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]User, error)
+	// GetDistances returns how many kilometres away from the user each user
+	// in userIDs is, leaving out those without a location. It is empty when
+	// the user has none.
+	GetDistances(ctx context.Context, userID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]float64, error)
+	// GetAvailability returns the schedules of the users in userIDs that set one.
+	GetAvailability(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]Availability, error)
+	// FindCandidates returns active users sharing skill categories with the
+	// user, those sharing exact skills first. Users either side blocked are
+	// never candidates.
+	FindCandidates(ctx context.Context, query CandidateQuery) ([]uuid.UUID, error)
+	RecommendSkills(ctx context.Context, userID uuid.UUID, limit int) ([]SkillRecommendation, error)
+}
+
+// PostgresRecommendationRepository implements RecommendationRepository on Postgres.
+type PostgresRecommendationRepository struct {
+	db      *db.SQL
+	vectors *vectors.Store
+}
+
+// NewPostgresRecommendationRepository constructs the repository. Reads go to
+// replicas; see db.SQL.
+func NewPostgresRecommendationRepository(db *db.SQL) *PostgresRecommendationRepository {
+	return &PostgresRecommendationRepository{db: db, vectors: vectors.NewStore(db)}
+}
+
+// SaveMatches inserts all matches in one statement.
+func (r *PostgresRecommendationRepository) SaveMatches(ctx context.Context, matches []Match) ([]Match, error) {
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	requesters := make([]string, len(matches))
+	candidates := make([]string, len(matches))
+	kinds := make([]string, len(matches))
+	scores := make([]float64, len(matches))
+	features := make([]string, len(matches))
+	versions := make([]string, len(matches))
+	variants := make([]string, len(matches))
+	explanations := make([]string, len(matches))
+	for i, match := range matches {
+		requesters[i] = match.RequesterID.String()
+		candidates[i] = match.CandidateID.String()
+		kinds[i] = string(match.Algorithm)
+		// match_score is NUMERIC(5, 4).
+		scores[i] = math.Round(match.Score*10000) / 10000
+		if match.Features != nil {
+			raw, err := json.Marshal(match.Features)
+			if err != nil {
+				return nil, fmt.Errorf("encode match features: %w", err)
+			}
+			features[i] = string(raw)
+		}
+		versions[i] = match.ModelVersion
+		variants[i] = match.Variant
+		explanations[i] = string(match.Explanation)
+	}
+
+	query := `
+		-- name: SaveMatches
+		INSERT INTO match_history (user_id_a, user_id_b, algorithm_used, match_score, features, model_version, variant, explanation)
+		SELECT a, b, algorithm, score, NULLIF(features, '')::jsonb, NULLIF(version, ''), NULLIF(variant, ''), NULLIF(explanation, '')::jsonb
+		FROM UNNEST($1::uuid[], $2::uuid[], $3::text[], $4::numeric[], $5::text[], $6::text[], $7::text[], $8::text[])
+			WITH ORDINALITY AS m(a, b, algorithm, score, features, version, variant, explanation, n)
+		ORDER BY n
+		RETURNING id, created_at
+	`
+	rows, err := r.db.Write(ctx).QueryContext(ctx, query, requesters, candidates, kinds, scores, features, versions, variants, explanations)
+	if err != nil {
+		return nil, fmt.Errorf("save matches: %w", err)
+	}
+	defer rows.Close()
+
+	saved := make([]Match, 0, len(matches))
+	for rows.Next() {
+		match := matches[len(saved)]
+		if err := rows.Scan(&match.ID, &match.CreatedAt); err != nil {
+			return nil, err
+		}
+		match.Score = scores[len(saved)]
+		saved = append(saved, match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// RecordFeedback calls record_match_feedback, which also marks the match as
+// interaction_initiated for anything past a profile view.
+func (r *PostgresRecommendationRepository) RecordFeedback(ctx context.Context, feedback Feedback) (uuid.UUID, bool, error) {
+	var session uuid.NullUUID
+	if feedback.SessionID != uuid.Nil {
+		session = uuid.NullUUID{UUID: feedback.SessionID, Valid: true}
+	}
+	var rating sql.NullInt16
+	if feedback.Rating > 0 {
+		rating = sql.NullInt16{Int16: int16(feedback.Rating), Valid: true}
+	}
+	var matchID uuid.NullUUID
+	err := r.db.Write(ctx).QueryRowContext(ctx, `
+		-- name: RecordMatchFeedback
+		SELECT record_match_feedback($1, $2, $3, $4, $5::smallint)
+	`, feedback.ActorID, feedback.OtherID, feedback.Event, session, rating).Scan(&matchID)
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("record match feedback: %w", err)
+	}
+	return matchID.UUID, matchID.Valid, nil
+}
+
+// GetExplanations reads explanations from match_history.
+func (r *PostgresRecommendationRepository) GetExplanations(ctx context.Context, requesterID uuid.UUID, candidateIDs []uuid.UUID) (map[uuid.UUID][]byte, error) {
+	explanations := make(map[uuid.UUID][]byte, len(candidateIDs))
+	if len(candidateIDs) == 0 {
+		return explanations, nil
+	}
+	query := `
+		-- name: GetMatchExplanations
+		SELECT DISTINCT ON (user_id_b) user_id_b, explanation::text
+		FROM match_history
+		WHERE user_id_a = $1 AND user_id_b = ANY($2::uuid[]) AND explanation IS NOT NULL
+		ORDER BY user_id_b, created_at DESC
+	`
+	rows, err := r.db.Read(ctx).QueryContext(ctx, query, requesterID, uuidStrings(candidateIDs))
+	if err != nil {
+		return nil, fmt.Errorf("load match explanations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			candidateID uuid.UUID
+			explanation string
+		)
+		if err := rows.Scan(&candidateID, &explanation); err != nil {
+			return nil, err
+		}
+		explanations[candidateID] = []byte(explanation)
+	}
+	return explanations, rows.Err()
+}
+
+// GetProfiles builds profiles from user_skill_vectors, which trail
+// user_skills by up to one vector refresh, and adds each skill's name and
+// embedding. Users without skills get an empty profile.
+func (r *PostgresRecommendationRepository) GetProfiles(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]algorithms.Profile, error) {
+	if len(userIDs) == 0 {
+		return map[uuid.UUID]algorithms.Profile{}, nil
+	}
+	stored, err := r.vectors.Get(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := r.db.Read(ctx)
+	query := `
+		-- name: GetMatchProfileUsers
+		SELECT id FROM users
+		WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+	`
+	rows, err := reader.QueryContext(ctx, query, uuidStrings(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("load match profiles: %w", err)
+	}
+	defer rows.Close()
+
+	profiles := make(map[uuid.UUID]algorithms.Profile, len(userIDs))
+	skillIDs := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		profile := algorithms.Profile{UserID: userID}
+		if v, ok := stored[userID]; ok {
+			profile = v.Profile()
+			for _, skill := range profile.Offered {
+				skillIDs[skill.ID] = true
+			}
+			for _, skill := range profile.Wanted {
+				skillIDs[skill.ID] = true
+			}
+		}
+		profiles[userID] = profile
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	skills, err := r.skillDetails(ctx, reader, slices.Collect(maps.Keys(skillIDs)))
+	if err != nil {
+		return nil, err
+	}
+	for userID, profile := range profiles {
+		profile.Offered = describeSkills(profile.Offered, skills)
+		profile.Wanted = describeSkills(profile.Wanted, skills)
+		profiles[userID] = profile
+	}
+	return profiles, nil
+}
+
+// skillDetails loads the names and embeddings of skills.
+func (r *PostgresRecommendationRepository) skillDetails(ctx context.Context, reader *sql.DB, ids []uuid.UUID) (map[uuid.UUID]algorithms.Skill, error) {
+	skills := make(map[uuid.UUID]algorithms.Skill, len(ids))
+	if len(ids) == 0 {
+		return skills, nil
+	}
+	query := `
+		-- name: GetMatchProfileSkills
+		SELECT id, name, embedding::text
+		FROM skills
+		WHERE id = ANY($1::uuid[])
+	`
+	rows, err := reader.QueryContext(ctx, query, uuidStrings(ids))
+	if err != nil {
+		return nil, fmt.Errorf("load profile skills: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			skill     algorithms.Skill
+			embedding sql.NullString
+		)
+		if err := rows.Scan(&skill.ID, &skill.Name, &embedding); err != nil {
+			return nil, err
+		}
+		if embedding.Valid {
+			// pgvector renders vectors as JSON arrays.
+			if err := json.Unmarshal([]byte(embedding.String), &skill.Embedding); err != nil {
+				return nil, fmt.Errorf("decode embedding of skill %s: %w", skill.ID, err)
+			}
+		}
+		skills[skill.ID] = skill
+	}
+	return skills, rows.Err()
+}
+
+// describeSkills fills in names and embeddings, drops skills deleted since
+// the vectors were built and orders the rest by name.
+func describeSkills(in []algorithms.Skill, details map[uuid.UUID]algorithms.Skill) []algorithms.Skill {
+	var out []algorithms.Skill
+	for _, skill := range in {
+		detail, ok := details[skill.ID]
+		if !ok {
+			continue
+		}
+		skill.Name, skill.Embedding = detail.Name, detail.Embedding
+		out = append(out, skill)
+	}
+	slices.SortFunc(out, func(a, b algorithms.Skill) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
+
+// GetUsers loads the users in userIDs that exist and were not deleted.
+func (r *PostgresRecommendationRepository) GetUsers(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]User, error) {
+	if len(userIDs) == 0 {
+		return map[uuid.UUID]User{}, nil
+	}
+	query := `
+		-- name: GetMatchUsers
+		SELECT u.id, u.username, u.display_name, COALESCE(u.bio, ''), COALESCE(u.avatar_url, ''),
+			u.is_verified, COALESCE(d.subscription_tier::text, 'free'), COALESCE(d.average_rating, 0),
+			COALESCE(d.total_sessions, 0), COALESCE(d.total_reviews, 0), u.created_at, u.last_login_at,
+			array_to_string(u.languages, ',')
+		FROM users u
+		LEFT JOIN user_search_documents d ON d.user_id = u.id
+		WHERE u.id = ANY($1::uuid[]) AND u.deleted_at IS NULL
+	`
+	rows, err := r.db.Read(ctx).QueryContext(ctx, query, uuidStrings(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("load match users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make(map[uuid.UUID]User, len(userIDs))
+	for rows.Next() {
+		var user User
+		var lastLogin sql.NullTime
+		var languages string
+		if err := rows.Scan(&user.ID, &user.Username, &user.DisplayName, &user.Bio, &user.AvatarURL,
+			&user.IsVerified, &user.SubscriptionTier, &user.AverageRating,
+			&user.TotalSessions, &user.TotalReviews, &user.CreatedAt, &lastLogin, &languages); err != nil {
+			return nil, err
+		}
+		if lastLogin.Valid {
+			user.LastLoginAt = &lastLogin.Time
+		}
+		if languages != "" {
+			user.Languages = strings.Split(languages, ",")
+		}
+		users[user.ID] = user
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetDistances measures from the user's location to the others' over the
+// globe, using the locations kept in user_search_documents.
+func (r *PostgresRecommendationRepository) GetDistances(ctx context.Context, userID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
+	if len(userIDs) == 0 {
+		return map[uuid.UUID]float64{}, nil
+	}
+	query := `
+		-- name: GetMatchDistances
+		SELECT d.user_id, ST_Distance(d.location_geom::geography, me.location_geom::geography) / 1000.0
+		FROM user_search_documents me
+		JOIN user_search_documents d ON d.user_id = ANY($2::uuid[])
+		WHERE me.user_id = $1 AND me.location_geom IS NOT NULL AND d.location_geom IS NOT NULL
+	`
+	rows, err := r.db.Read(ctx).QueryContext(ctx, query, userID, uuidStrings(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("load match distances: %w", err)
+	}
+	defer rows.Close()
+
+	distances := make(map[uuid.UUID]float64, len(userIDs))
+	for rows.Next() {
+		var id uuid.UUID
+		var km float64
+		if err := rows.Scan(&id, &km); err != nil {
+			return nil, err
+		}
+		distances[id] = km
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return distances, nil
+}
+
+// GetAvailability loads user_availability rows.
+func (r *PostgresRecommendationRepository) GetAvailability(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]Availability, error) {
+	if len(userIDs) == 0 {
+		return map[uuid.UUID]Availability{}, nil
+	}
+	query := `
+		-- name: GetMatchAvailability
+		SELECT user_id, timezone, weekly_schedule
+		FROM user_availability
+		WHERE user_id = ANY($1::uuid[])
+	`
+	rows, err := r.db.Read(ctx).QueryContext(ctx, query, uuidStrings(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("load availability: %w", err)
+	}
+	defer rows.Close()
+
+	availability := make(map[uuid.UUID]Availability, len(userIDs))
+	for rows.Next() {
+		var userID uuid.UUID
+		var row Availability
+		if err := rows.Scan(&userID, &row.Timezone, &row.WeeklySchedule); err != nil {
+			return nil, err
+		}
+		availability[userID] = row
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return availability, nil
+}
+
+// FindCandidates pairs the user's skills with other users' skills in the
+// same category: wanted with offered for matches, like with like for
+// similar users. Category overlap lets the embedding algorithm see
+// differently named skills; exact overlaps rank first.
+func (r *PostgresRecommendationRepository) FindCandidates(ctx context.Context, q CandidateQuery) ([]uuid.UUID, error) {
+	near := []float64{}
+	if q.Filters.Near != nil && q.Filters.MaxDistanceKm > 0 {
+		near = []float64{q.Filters.Near.Longitude, q.Filters.Near.Latitude}
+	}
+	var minTier *string
+	if q.Filters.MinTier != "" {
+		minTier = &q.Filters.MinTier
+	}
+
+	query := `
+		-- name: FindMatchCandidates
+		WITH mine AS (
+			SELECT us.skill_id, us.skill_type, s.category_id
+			FROM user_skills us JOIN skills s ON s.id = us.skill_id
+			WHERE us.user_id = $1
+		)
+		SELECT u.id
+		FROM mine
+		JOIN skills ts ON ts.category_id = mine.category_id
+		JOIN user_skills theirs ON theirs.skill_id = ts.id
+			AND (theirs.skill_type = mine.skill_type) = $2
+			AND (theirs.skill_type = 'wanted' OR COALESCE(theirs.proficiency, $5) >= $4)
+		JOIN users u ON u.id = theirs.user_id
+		LEFT JOIN user_search_documents d ON d.user_id = u.id
+		WHERE u.id <> $1 AND u.is_active AND u.deleted_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b
+				WHERE (b.blocker_id = $1 AND b.blocked_id = u.id) OR (b.blocker_id = u.id AND b.blocked_id = $1)
+			)
+			AND (NOT $12 OR NOT recommendation_excluded($1, u.id))
+			AND (NOT $6 OR u.is_verified)
+			AND COALESCE(d.average_rating, 0) >= $7
+			AND ($8::subscription_tier IS NULL OR COALESCE(d.subscription_tier, 'free') >= $8::subscription_tier)
+			AND (CARDINALITY($9::uuid[]) = 0 OR EXISTS (
+				SELECT 1 FROM user_skills f
+				WHERE f.user_id = u.id AND f.skill_type = 'offered' AND f.skill_id = ANY($9::uuid[])
+			))
+			AND (CARDINALITY($10::float8[]) = 0 OR ST_DWithin(
+				d.location_geom::geography,
+				ST_SetSRID(ST_MakePoint(($10::float8[])[1], ($10::float8[])[2]), 4326)::geography,
+				$11 * 1000.0
+			))
+		GROUP BY u.id
+		ORDER BY COUNT(*) FILTER (WHERE theirs.skill_id = mine.skill_id) DESC, COUNT(*) DESC, u.id
+		LIMIT $3
+	`
+	rows, err := r.db.Read(ctx).QueryContext(ctx, query,
+		q.UserID, q.Similar, q.Limit, q.Filters.MinProficiency, algorithms.DefaultProficiency,
+		q.Filters.VerifiedOnly, q.Filters.MinRating, minTier,
+		uuidStrings(q.Filters.SkillIDs), near, q.Filters.MaxDistanceKm, q.ExcludeKnown,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("find match candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// RecommendSkills ranks skills the user does not list by how many of the
+// users sharing a skill with them list it, then by overall popularity.
+func (r *PostgresRecommendationRepository) RecommendSkills(ctx context.Context, userID uuid.UUID, limit int) ([]SkillRecommendation, error) {
+	query := `
+		-- name: RecommendSkills
+		WITH mine AS (
+			SELECT skill_id FROM user_skills WHERE user_id = $1
+		),
+		peers AS (
+			SELECT DISTINCT us.user_id
+			FROM user_skills us JOIN mine ON mine.skill_id = us.skill_id
+			WHERE us.user_id <> $1
+		)
+		SELECT s.id, s.name, COALESCE(s.description, ''),
+			COUNT(DISTINCT us.user_id), s.users_offering_count + s.users_wanting_count
+		FROM skills s
+		LEFT JOIN user_skills us ON us.skill_id = s.id AND us.user_id IN (SELECT user_id FROM peers)
+		WHERE s.id NOT IN (SELECT skill_id FROM mine)
+		GROUP BY s.id
+		ORDER BY 4 DESC, 5 DESC, s.name
+		LIMIT $2
+	`
+	rows, err := r.db.Read(ctx).QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("recommend skills: %w", err)
+	}
+	defer rows.Close()
+
+	var skills []SkillRecommendation
+	for rows.Next() {
+		var skill SkillRecommendation
+		if err := rows.Scan(&skill.ID, &skill.Name, &skill.Description, &skill.Peers, &skill.Popularity); err != nil {
+			return nil, err
+		}
+		skills = append(skills, skill)
+	}
+	return skills, rows.Err()
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, id.String())
+	}
+	return out
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/scheduling"
+)
+
+// AvailabilityPolicy controls how weekly schedule overlap affects matches.
+// Overlap is measured over the coming week in real time, so it follows DST.
+type AvailabilityPolicy struct {
+	// Weight is the share of the score taken by overlap when both users
+	// have a schedule; 0 reports overlap without changing scores.
+	Weight float64
+	// Target is the weekly overlap that counts as fully compatible.
+	Target time.Duration
+	// MinOverlap drops candidates sharing less time, including those
+	// without a schedule. Zero disables the filter.
+	MinOverlap time.Duration
+	// Slots is how many shared slots explanations name.
+	Slots int
+}
+
+// DefaultAvailabilityPolicy boosts by overlap without filtering.
+func DefaultAvailabilityPolicy() AvailabilityPolicy {
+	return AvailabilityPolicy{Weight: 0.2, Target: 2 * time.Hour, Slots: 3}
+}
+
+// SetAvailabilityPolicy replaces the availability policy. It is not safe to
+// call while requests are served.
+func (s *Service) SetAvailabilityPolicy(policy AvailabilityPolicy) {
+	s.availability = policy
+}
+
+// SharedTime is the time a requester and a candidate are both free in the
+// coming week.
+type SharedTime struct {
+	Weekly time.Duration
+	// Best are the longest shared intervals, in UTC.
+	Best []scheduling.Interval
+	// Next is the first shared interval, if any.
+	Next *scheduling.Interval
+	// Location is the requester's time zone, for display.
+	Location *time.Location
+}
+
+// blend mixes a skill score with the availability score.
+func (p AvailabilityPolicy) blend(score float64, shared *SharedTime) float64 {
+	skill, overlap := p.split(score, shared)
+	return skill + overlap
+}
+
+// split returns the shares of a blended score taken by the skill score and
+// by overlap.
+func (p AvailabilityPolicy) split(score float64, shared *SharedTime) (skill, overlap float64) {
+	if shared == nil || p.Weight <= 0 || p.Target <= 0 || score <= 0 {
+		return score, 0
+	}
+	fit := math.Min(1, float64(shared.Weekly)/float64(p.Target))
+	return (1 - p.Weight) * score, p.Weight * fit
+}
+
+// admits reports whether a candidate passes the minimum overlap.
+func admits(minOverlap time.Duration, shared *SharedTime) bool {
+	if minOverlap <= 0 {
+		return true
+	}
+	return shared != nil && shared.Weekly >= minOverlap
+}
+
+// schedules loads and parses availability. Invalid schedules are logged and
+// treated as unknown rather than failing the whole request.
+func (s *Service) schedules(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]scheduling.Schedule, error) {
+	rows, err := s.repo.GetAvailability(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[uuid.UUID]scheduling.Schedule, len(rows))
+	for id, row := range rows {
+		schedule, err := scheduling.ParseWeeklySchedule(row.WeeklySchedule, row.Timezone)
+		if err != nil {
+			s.logger.WarnContext(ctx, "ignoring invalid availability", "user_id", id, "error", err)
+			continue
+		}
+		out[id] = schedule
+	}
+	return out, nil
+}
+
+// sharedTime compares two schedules over the week starting at from; it is nil
+// when either user has no schedule.
+func (s *Service) sharedTime(user, candidate *scheduling.Schedule, from time.Time) *SharedTime {
+	if user == nil || candidate == nil {
+		return nil
+	}
+	overlap := scheduling.WeeklyOverlap(*user, *candidate, from)
+	shared := &SharedTime{
+		Weekly:   overlap.Total,
+		Best:     overlap.Longest(s.availability.Slots),
+		Location: user.Location,
+	}
+	if next, ok := overlap.Next(from); ok {
+		shared.Next = &next
+	}
+	return shared
+}
+
+// window returns the start of the week availability is compared over.
+func (s *Service) window() time.Time {
+	return s.now().UTC().Truncate(time.Minute)
+}
+
+func scheduleOf(schedules map[uuid.UUID]scheduling.Schedule, id uuid.UUID) *scheduling.Schedule {
+	if schedule, ok := schedules[id]; ok {
+		return &schedule
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/repository"
+	"github.com/FACorreiaa/skillsphere-api/pkg/observability"
+)
+
+// ErrCacheDisabled is returned by SkipPartner when the service has no
+// recommendation cache to record skips in.
+var ErrCacheDisabled = errors.New("recommendation cache disabled")
+
+var errCacheStore = errors.New("store recommendations")
+
+// cachedTypes are the item types kept in the recommendation cache.
+var cachedTypes = []string{ItemTypeUser, ItemTypeSkill}
+
+// CachePolicy controls how long cached recommendations are served.
+type CachePolicy struct {
+	// TTL is how long computed recommendations count as fresh.
+	TTL time.Duration
+	// MaxStale is how long past TTL, or after a profile edit, an entry is
+	// still served while it is recomputed in the background. Older entries
+	// are recomputed by the request.
+	MaxStale time.Duration
+	// Settle is how long a queued user waits before being recomputed, so a
+	// burst of profile edits costs one recompute.
+	Settle time.Duration
+}
+
+// DefaultCachePolicy serves recommendations for six hours and stale ones for
+// a day after.
+func DefaultCachePolicy() CachePolicy {
+	return CachePolicy{TTL: 6 * time.Hour, MaxStale: 24 * time.Hour, Settle: 5 * time.Second}
+}
+
+// SetRecommendationCache serves partner and skill recommendations from cache
+// under policy. It is not safe to call while requests are served.
+func (s *Service) SetRecommendationCache(cache repository.RecommendationCache, policy CachePolicy) {
+	s.cache = cache
+	s.cachePolicy = policy
+}
+
+// SkipPartner stops recommending partner to the user.
+func (s *Service) SkipPartner(ctx context.Context, userID, partnerID string) error {
+	user, err := uuid.Parse(userID)
+	if err != nil {
+		return ErrInvalidUserID
+	}
+	partner, err := uuid.Parse(partnerID)
+	if err != nil {
+		return ErrInvalidUserID
+	}
+	if user == partner {
+		return ErrSameUser
+	}
+	if s.cache == nil {
+		return ErrCacheDisabled
+	}
+	users, err := s.repo.GetUsers(ctx, []uuid.UUID{user, partner})
+	if err != nil {
+		return err
+	}
+	if len(users) != 2 {
+		return repository.ErrUserNotFound
+	}
+	return s.cache.SkipPartner(ctx, user, partner)
+}
+
+// cachedRecommendations serves a fresh cache entry as is. An entry past its
+// TTL or older than a profile edit is served while the user waits in the
+// refresh queue, until it is MaxStale past due; without a usable entry the
+// request computes and stores one. A cache that cannot be read is bypassed.
+func (s *Service) cachedRecommendations(ctx context.Context, userID uuid.UUID, itemType string, limit int) ([]Recommendation, error) {
+	if s.cache == nil {
+		return s.computeRecommendations(ctx, userID, itemType, limit)
+	}
+	entry, ok, err := s.cache.CachedRecommendations(ctx, userID, itemType, limit)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to read recommendation cache", "user_id", userID, "error", err)
+		return s.computeRecommendations(ctx, userID, itemType, limit)
+	}
+
+	age := s.now().Sub(entry.GeneratedAt)
+	switch {
+	case ok && !entry.Queued && age < s.cachePolicy.TTL:
+		observability.RecommendationCacheRequestsTotal.WithLabelValues(itemType, "fresh").Inc()
+		return fromCache(itemType, entry.Items), nil
+	case ok && age < s.cachePolicy.TTL+s.cachePolicy.MaxStale:
+		observability.RecommendationCacheRequestsTotal.WithLabelValues(itemType, "stale").Inc()
+		if !entry.Queued {
+			if err := s.cache.EnqueueRefresh(ctx, []uuid.UUID{userID}); err != nil {
+				s.logger.WarnContext(ctx, "failed to queue recommendation refresh", "user_id", userID, "error", err)
+			}
+		}
+		return fromCache(itemType, entry.Items), nil
+	}
+
+	observability.RecommendationCacheRequestsTotal.WithLabelValues(itemType, "miss").Inc()
+	recommendations, err := s.refreshRecommendations(ctx, userID, itemType)
+	if errors.Is(err, errCacheStore) {
+		// Only costs a recompute on the next request.
+		s.logger.WarnContext(ctx, "failed to cache recommendations", "user_id", userID, "item_type", itemType, "error", err)
+	} else if err != nil {
+		return nil, err
+	}
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
+
+// refreshRecommendations computes as many recommendations as a request may
+// ask for and stores them. When only the store fails, the error wraps
+// errCacheStore and the recommendations are returned too.
+func (s *Service) refreshRecommendations(ctx context.Context, userID uuid.UUID, itemType string) ([]Recommendation, error) {
+	generatedAt := s.now()
+	recommendations, err := s.computeRecommendations(ctx, userID, itemType, maxLimit)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]repository.CachedRecommendation, 0, len(recommendations))
+	for _, r := range recommendations {
+		item := repository.CachedRecommendation{
+			ItemID:      r.ItemID,
+			Title:       r.Title,
+			Description: r.Description,
+			Score:       r.Score,
+			Reason:      r.Reason,
+		}
+		if r.Reasons != nil {
+			if item.Explanation, err = json.Marshal(r.Reasons); err != nil {
+				return nil, fmt.Errorf("encode explanation: %w", err)
+			}
+		}
+		items = append(items, item)
+	}
+	if err := s.cache.StoreRecommendations(ctx, userID, itemType, items, generatedAt); err != nil {
+		return recommendations, fmt.Errorf("%w: %w", errCacheStore, err)
+	}
+	return recommendations, nil
+}
+
+// fromCache converts cached items. An explanation that does not decode is
+// dropped and the stored reason served as is.
+func fromCache(itemType string, items []repository.CachedRecommendation) []Recommendation {
+	recommendations := make([]Recommendation, 0, len(items))
+	for _, item := range items {
+		recommendation := Recommendation{
+			ItemID:      item.ItemID,
+			ItemType:    itemType,
+			Title:       item.Title,
+			Description: item.Description,
+			Score:       item.Score,
+			Reason:      item.Reason,
+		}
+		if item.Explanation != nil {
+			var reasons Explanation
+			if json.Unmarshal(item.Explanation, &reasons) == nil {
+				recommendation.Reasons = &reasons
+			}
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	return recommendations
+}
+
+const defaultRecommendationRefreshBatchSize = 50
+
+// RecommendationRefresher recomputes the cached recommendations of users
+// queued by profile edits or by requests served a stale entry.
+type RecommendationRefresher struct {
+	service   *Service
+	batchSize int
+	logger    *slog.Logger
+}
+
+// NewRecommendationRefresher builds a refresher that claims up to batchSize
+// users at a time; a non-positive batchSize uses the default.
+func NewRecommendationRefresher(service *Service, batchSize int, logger *slog.Logger) *RecommendationRefresher {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	if batchSize <= 0 {
+		batchSize = defaultRecommendationRefreshBatchSize
+	}
+	return &RecommendationRefresher{service: service, batchSize: batchSize, logger: logger}
+}
+
+// Run drains the queue once per interval until ctx is cancelled.
+func (r *RecommendationRefresher) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := r.Refresh(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("recommendation refresh failed", "error", err)
+		} else if n > 0 {
+			r.logger.Debug("recommendations refreshed", "users", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh recomputes every user queued for at least the policy's Settle and
+// reports how many were recomputed. Claiming dequeues, so users whose
+// recompute fails are queued again, and the pass ends to retry them later.
+// Users deleted meanwhile are dropped.
+func (r *RecommendationRefresher) Refresh(ctx context.Context) (int, error) {
+	if r == nil || r.service == nil || r.service.cache == nil {
+		return 0, errors.New("recommendation refresher uninitialized")
+	}
+	cache := r.service.cache
+
+	var total int
+	for {
+		ids, err := cache.ClaimRefresh(ctx, r.batchSize, r.service.cachePolicy.Settle)
+		if err != nil {
+			return total, err
+		}
+		var failed []uuid.UUID
+		for i, id := range ids {
+			if ctx.Err() != nil {
+				failed = append(failed, ids[i:]...)
+				break
+			}
+			if err := r.refresh(ctx, id); err != nil {
+				if !errors.Is(err, repository.ErrUserNotFound) {
+					r.logger.Warn("failed to refresh recommendations", "user_id", id, "error", err)
+					failed = append(failed, id)
+				}
+				continue
+			}
+			total++
+			observability.RecommendationsRefreshedTotal.Inc()
+		}
+		if len(failed) > 0 {
+			if err := cache.EnqueueRefresh(context.WithoutCancel(ctx), failed); err != nil {
+				return total, err
+			}
+			return total, ctx.Err()
+		}
+		if len(ids) < r.batchSize {
+			return total, nil
+		}
+	}
+}
+
+func (r *RecommendationRefresher) refresh(ctx context.Context, userID uuid.UUID) error {
+	for _, itemType := range cachedTypes {
+		if _, err := r.service.refreshRecommendations(ctx, userID, itemType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/embedding"
+)
+
+// maxEmbedNames caps the skill names embedded per request.
+const maxEmbedNames = 100
+
+var (
+	ErrEmbeddingsDisabled = errors.New("embeddings are not configured")
+	ErrNoSkillNames       = errors.New("skill names or a user id are required")
+	ErrTooManySkillNames  = fmt.Errorf("at most %d skill names can be embedded at once", maxEmbedNames)
+)
+
+// SetEmbedder enables GenerateEmbeddings. It is not safe to call while
+// requests are served.
+func (s *Service) SetEmbedder(embedder *embedding.Embedder) {
+	s.embedder = embedder
+}
+
+// EmbedParams names the skills to embed; without names, the user's own
+// skills are embedded.
+type EmbedParams struct {
+	UserID     string
+	SkillNames []string
+}
+
+// SkillEmbedding is the vector of one skill name.
+type SkillEmbedding struct {
+	SkillName string
+	Vector    []float32
+	Model     string
+}
+
+// GenerateEmbeddings embeds skill names with the configured provider. Vectors
+// come from the cache when the same name was embedded before and are not
+// stored; the re-embedding job maintains skills.embedding.
+func (s *Service) GenerateEmbeddings(ctx context.Context, params EmbedParams) ([]SkillEmbedding, error) {
+	if s.embedder == nil {
+		return nil, ErrEmbeddingsDisabled
+	}
+	var names []string
+	for _, name := range params.SkillNames {
+		if name = strings.TrimSpace(name); name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		if params.UserID == "" {
+			return nil, ErrNoSkillNames
+		}
+		userID, err := uuid.Parse(params.UserID)
+		if err != nil {
+			return nil, ErrInvalidUserID
+		}
+		profile, err := s.profile(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, skill := range append(profile.Offered, profile.Wanted...) {
+			if !slices.Contains(names, skill.Name) {
+				names = append(names, skill.Name)
+			}
+		}
+	}
+	if len(names) > maxEmbedNames {
+		return nil, ErrTooManySkillNames
+	}
+
+	vectors, err := s.embedder.Embed(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SkillEmbedding, len(names))
+	for i, name := range names {
+		out[i] = SkillEmbedding{SkillName: name, Vector: vectors[i], Model: s.embedder.Model()}
+	}
+	return out, nil
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/language"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/algorithms"
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/repository"
+	"github.com/FACorreiaa/skillsphere-api/internal/scheduling"
+)
+
+// explainedPairs is how many skill pairs per direction an explanation names.
+const explainedPairs = 3
+
+// FactorKind names what a Factor describes. The values are part of the
+// structured explanations sent to clients and the ontology.
+type FactorKind string
+
+const (
+	// FactorComplementarity is a skill one user offers and the other wants,
+	// one per skill pair.
+	FactorComplementarity FactorKind = "complementarity"
+	// FactorProficiencyGap is what a pair loses because the skill is offered
+	// below the wanted level.
+	FactorProficiencyGap FactorKind = "proficiency_gap"
+	FactorAvailability   FactorKind = "availability"
+	FactorDistance       FactorKind = "distance"
+	FactorRating         FactorKind = "rating"
+	FactorLanguage       FactorKind = "language"
+)
+
+// Factor is one reason behind a match. The contributions of a match's
+// factors add up to its score; distance, rating and language do not affect
+// scores and contribute 0.
+type Factor struct {
+	Kind         FactorKind `json:"kind"`
+	Contribution float64    `json:"contribution"`
+
+	// Skill pairs: Teach is set when the requester offers the skill.
+	Skill   string `json:"skill,omitempty"`
+	Teach   bool   `json:"teach,omitempty"`
+	Offered int    `json:"offered,omitempty"`
+	Wanted  int    `json:"wanted,omitempty"`
+
+	// Availability: the weekly overlap and the best shared slots, in UTC,
+	// with the requester's time zone for display.
+	WeeklyMinutes int    `json:"weekly_minutes,omitempty"`
+	Slots         []Slot `json:"slots,omitempty"`
+	TimeZone      string `json:"time_zone,omitempty"`
+
+	DistanceKm float64 `json:"distance_km,omitempty"`
+	Rating     float64 `json:"rating,omitempty"`
+	Reviews    int     `json:"reviews,omitempty"`
+	// Languages are the base language tags both users speak.
+	Languages []string `json:"languages,omitempty"`
+
+	location *time.Location
+}
+
+// Slot is a shared free interval.
+type Slot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Explanation is why a candidate scored what they did, from the requester's
+// side. Complementarity factors are ordered by the strength of the pair,
+// strongest first.
+type Explanation struct {
+	Score   float64  `json:"score"`
+	Mutual  bool     `json:"mutual"`
+	Factors []Factor `json:"factors"`
+}
+
+// explain attributes a match score to the skill pairs and shared time behind
+// it. skillShare and overlap are the parts of the score taken by the skill
+// score and by availability. Each direction of the exchange takes an equal
+// part of skillShare, which its pairs divide by similarity as if offered at
+// the wanted level; proficiency gap factors take back what levels below the
+// wanted ones cost.
+func explain(result algorithms.Result, skillShare, overlap float64, shared *SharedTime) Explanation {
+	e := Explanation{Score: skillShare + overlap, Mutual: result.Mutual}
+	var directions int
+	for _, d := range []*algorithms.Direction{result.Learn, result.Teach} {
+		if d != nil {
+			directions++
+		}
+	}
+	var share float64
+	if result.Score > 0 {
+		share = skillShare / result.Score / float64(directions)
+	}
+
+	// Each direction's share is divided between its pairs.
+	var similarity, shortfall [2]float64
+	for _, pair := range result.Pairs {
+		similarity[side(pair)] += pair.Similarity
+		shortfall[side(pair)] += pair.Similarity * pairShortfall(pair)
+	}
+	var gaps []Factor
+	for _, pair := range result.Pairs {
+		d, i := result.Learn, side(pair)
+		if pair.IsComplementary {
+			d = result.Teach
+		}
+		f := pairFactor(pair)
+		if d != nil && similarity[i] > 0 {
+			f.Contribution = d.Potential * share * pair.Similarity / similarity[i]
+		}
+		e.Factors = append(e.Factors, f)
+		if gap := pair.Similarity * pairShortfall(pair); gap > 0 && d != nil {
+			f.Kind = FactorProficiencyGap
+			f.Contribution = (d.Cover - d.Potential) * share * gap / shortfall[i]
+			gaps = append(gaps, f)
+		}
+	}
+	e.Factors = append(e.Factors, gaps...)
+
+	if shared != nil {
+		loc := shared.Location
+		if loc == nil {
+			loc = time.UTC
+		}
+		f := Factor{
+			Kind:          FactorAvailability,
+			Contribution:  overlap,
+			WeeklyMinutes: int(shared.Weekly / time.Minute),
+			TimeZone:      loc.String(),
+			location:      loc,
+		}
+		for _, interval := range shared.Best {
+			f.Slots = append(f.Slots, Slot{Start: interval.Start.UTC(), End: interval.End.UTC()})
+		}
+		sort.Slice(f.Slots, func(i, j int) bool { return f.Slots[i].Start.Before(f.Slots[j].Start) })
+		e.Factors = append(e.Factors, f)
+	}
+	return e
+}
+
+func pairFactor(pair algorithms.Pair) Factor {
+	f := Factor{Kind: FactorComplementarity, Skill: pair.SkillName, Teach: pair.IsComplementary}
+	if pair.IsComplementary {
+		f.Offered, f.Wanted = pair.UserProficiency, pair.MatchProficiency
+	} else {
+		f.Offered, f.Wanted = pair.MatchProficiency, pair.UserProficiency
+	}
+	return f
+}
+
+// pairShortfall is how far below the wanted level a pair's skill is offered,
+// on a 0..1 scale.
+func pairShortfall(pair algorithms.Pair) float64 {
+	f := pairFactor(pair)
+	return math.Max(0, float64(proficiency(f.Wanted)-proficiency(f.Offered))/algorithms.MaxProficiency)
+}
+
+func proficiency(level int) int {
+	if level <= 0 {
+		return algorithms.DefaultProficiency
+	}
+	return min(level, algorithms.MaxProficiency)
+}
+
+// side indexes a pair's direction: 0 for learning, 1 for teaching.
+func side(pair algorithms.Pair) int {
+	if pair.IsComplementary {
+		return 1
+	}
+	return 0
+}
+
+// Text renders the explanation in the locale, e.g. "They can teach you Go
+// (8/10, you want 6/10). You can teach them Spanish (7/10, they want 3/10).
+// You can help each other. You are both free Tue 18:00-20:00 (Europe/Lisbon),
+// 2h a week."
+func (e Explanation) Text(locale language.Tag) string {
+	c := catalogFor(locale)
+	var learn, teach, context []string
+	var availability *Factor
+	for i, f := range e.Factors {
+		switch f.Kind {
+		case FactorComplementarity:
+			if f.Teach {
+				teach = append(teach, fmt.Sprintf(c.teachPair, f.Skill, f.Offered, f.Wanted))
+			} else {
+				learn = append(learn, fmt.Sprintf(c.learnPair, f.Skill, f.Offered, f.Wanted))
+			}
+		case FactorRating:
+			if f.Reviews == 1 {
+				context = append(context, fmt.Sprintf(c.ratingOne, c.number(f.Rating)))
+			} else {
+				context = append(context, fmt.Sprintf(c.rating, c.number(f.Rating), f.Reviews))
+			}
+		case FactorDistance:
+			context = append(context, c.distanceText(f.DistanceKm))
+		case FactorLanguage:
+			names := make([]string, 0, len(f.Languages))
+			for _, tag := range f.Languages {
+				names = append(names, c.language(tag))
+			}
+			context = append(context, fmt.Sprintf(c.speak, c.join(names, len(names))))
+		case FactorAvailability:
+			availability = &e.Factors[i]
+		}
+	}
+
+	var sentences []string
+	if len(learn) > 0 {
+		sentences = append(sentences, fmt.Sprintf(c.learn, c.join(learn, explainedPairs)))
+	}
+	if len(teach) > 0 {
+		sentences = append(sentences, fmt.Sprintf(c.teach, c.join(teach, explainedPairs)))
+	}
+	switch {
+	case e.Mutual:
+		sentences = append(sentences, c.mutual)
+	case len(sentences) == 0:
+		return c.none
+	}
+	sentences = append(sentences, context...)
+	if availability != nil {
+		sentences = append(sentences, c.sharedTime(*availability))
+	}
+	return strings.Join(sentences, " ")
+}
+
+// sharedTime names the best shared slots in the requester's time zone, e.g.
+// "You are both free Tue 18:00-20:00 and Sat 10:00-11:00 (Europe/Lisbon),
+// 3h a week."
+func (c *catalog) sharedTime(f Factor) string {
+	if f.WeeklyMinutes <= 0 || len(f.Slots) == 0 {
+		return c.noOverlap
+	}
+	loc := f.location
+	if loc == nil {
+		var err error
+		if loc, err = time.LoadLocation(f.TimeZone); err != nil {
+			loc = time.UTC
+		}
+	}
+	slots := make([]string, 0, len(f.Slots))
+	for _, slot := range f.Slots {
+		slots = append(slots, c.interval(scheduling.Interval{Start: slot.Start, End: slot.End}, loc))
+	}
+	return fmt.Sprintf(c.free, c.join(slots, len(slots)), loc, formatDuration(time.Duration(f.WeeklyMinutes)*time.Minute))
+}
+
+// addContext adds what the requester may want to know about the candidate
+// but scores do not use: their rating, how far away they are and the
+// languages both speak.
+func (e *Explanation) addContext(requester, candidate *repository.User, distanceKm *float64) {
+	if candidate != nil && candidate.TotalReviews > 0 {
+		e.Factors = append(e.Factors, Factor{Kind: FactorRating, Rating: candidate.AverageRating, Reviews: candidate.TotalReviews})
+	}
+	if distanceKm != nil {
+		e.Factors = append(e.Factors, Factor{Kind: FactorDistance, DistanceKm: math.Round(*distanceKm*10) / 10})
+	}
+	if requester != nil && candidate != nil {
+		if shared := sharedLanguages(requester.Languages, candidate.Languages); len(shared) > 0 {
+			e.Factors = append(e.Factors, Factor{Kind: FactorLanguage, Languages: shared})
+		}
+	}
+}
+
+// sharedLanguages returns the base languages of a that b speaks too, in a's
+// order, so "pt-PT" and "pt-BR" speakers share "pt".
+func sharedLanguages(a, b []string) []string {
+	theirs := make(map[string]bool, len(b))
+	for _, tag := range b {
+		theirs[baseLanguage(tag)] = true
+	}
+	var shared []string
+	for _, tag := range a {
+		if base := baseLanguage(tag); base != "" && theirs[base] {
+			shared = append(shared, base)
+			delete(theirs, base)
+		}
+	}
+	return shared
+}
+
+func baseLanguage(tag string) string {
+	parsed, err := language.Parse(tag)
+	if err != nil {
+		return ""
+	}
+	base, _ := parsed.Base()
+	return base.String()
+}
+
+// explainContext adds the candidates' ratings, distances and shared
+// languages to the matches' explanations and renders them again. Distances
+// are unknown when either user has no location.
+func (s *Service) explainContext(ctx context.Context, matches []Match) error {
+	var requesterIDs []uuid.UUID
+	candidates := map[uuid.UUID][]uuid.UUID{}
+	for _, match := range matches {
+		if _, ok := candidates[match.RequesterID]; !ok {
+			requesterIDs = append(requesterIDs, match.RequesterID)
+		}
+		candidates[match.RequesterID] = append(candidates[match.RequesterID], match.CandidateID)
+	}
+	requesters, err := s.repo.GetUsers(ctx, requesterIDs)
+	if err != nil {
+		return err
+	}
+	distances := make(map[uuid.UUID]map[uuid.UUID]float64, len(requesterIDs))
+	for _, id := range requesterIDs {
+		if distances[id], err = s.repo.GetDistances(ctx, id, candidates[id]); err != nil {
+			return err
+		}
+	}
+
+	for i := range matches {
+		match := &matches[i]
+		var requester *repository.User
+		if user, ok := requesters[match.RequesterID]; ok {
+			requester = &user
+		}
+		var distance *float64
+		if km, ok := distances[match.RequesterID][match.CandidateID]; ok {
+			distance = &km
+		}
+		match.Reasons.addContext(requester, match.Candidate, distance)
+		match.Explanation = match.Reasons.Text(DefaultLocale)
+	}
+	return nil
+}
+
+// MatchExplanations returns the explanations of the user's latest recorded
+// matches with candidateIDs, as FindMatches and GetMatchScore returned them.
+// Candidates the user was never matched with are left out.
+func (s *Service) MatchExplanations(ctx context.Context, userID string, candidateIDs []string) (map[uuid.UUID]Explanation, error) {
+	requester, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+	if len(candidateIDs) > maxLimit {
+		return nil, ErrTooManyCandidates
+	}
+	ids := make([]uuid.UUID, 0, len(candidateIDs))
+	for _, raw := range candidateIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, ErrInvalidUserID
+		}
+		ids = append(ids, id)
+	}
+
+	stored, err := s.repo.GetExplanations(ctx, requester, ids)
+	if err != nil {
+		return nil, err
+	}
+	explanations := make(map[uuid.UUID]Explanation, len(stored))
+	for id, raw := range stored {
+		var e Explanation
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil, fmt.Errorf("decode explanation of %s: %w", id, err)
+		}
+		explanations[id] = e
+	}
+	return explanations, nil
+}
+
+// explanationJSON is the structured explanation carried in sk:explanation,
+// with its text in DefaultLocale. It falls back to the text alone should
+// the explanation not encode.
+func explanationJSON(match Match) string {
+	raw, err := json.Marshal(struct {
+		Text string `json:"text"`
+		Explanation
+	}{match.Explanation, match.Reasons})
+	if err != nil {
+		return match.Explanation
+	}
+	return string(raw)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/scheduling"
+)
+
+// DefaultLocale renders explanations for requests that do not ask for a
+// supported language, and for text stored with matches and recommendations.
+var DefaultLocale = language.English
+
+// locales are the languages explanations are written in; the first is the
+// fallback.
+var locales = []language.Tag{language.English, language.Portuguese}
+
+var localeMatcher = language.NewMatcher(locales)
+
+// Locale picks the supported locale that best suits an Accept-Language
+// header, or DefaultLocale.
+func Locale(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return DefaultLocale
+	}
+	_, index, confidence := localeMatcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLocale
+	}
+	return locales[index]
+}
+
+// catalog holds the messages of one locale. Pair messages take the skill,
+// the offered level and the wanted level.
+type catalog struct {
+	learn, teach         string
+	learnPair, teachPair string
+	mutual, none         string
+	free, noOverlap      string
+	ratingOne, rating    string
+	distance, near       string
+	speak                string
+	and, more            string
+	decimal              string
+	weekdays             [7]string
+	// languages names common languages by base tag; others are shown as tags.
+	languages map[string]string
+}
+
+var catalogs = map[string]*catalog{
+	"en": {
+		learn:     "They can teach you %s.",
+		teach:     "You can teach them %s.",
+		learnPair: "%s (%d/10, you want %d/10)",
+		teachPair: "%s (%d/10, they want %d/10)",
+		mutual:    "You can help each other.",
+		none:      "Neither of you offers a skill the other wants.",
+		free:      "You are both free %s (%s), %s a week.",
+		noOverlap: "Your schedules do not overlap this week.",
+		ratingOne: "They are rated %s/5 from 1 review.",
+		rating:    "They are rated %s/5 from %d reviews.",
+		distance:  "They are %d km away.",
+		near:      "They are less than 1 km away.",
+		speak:     "You both speak %s.",
+		and:       " and ",
+		more:      "%d more",
+		decimal:   ".",
+		weekdays:  [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		languages: map[string]string{
+			"de": "German", "en": "English", "es": "Spanish", "fr": "French",
+			"it": "Italian", "ja": "Japanese", "nl": "Dutch", "pt": "Portuguese", "zh": "Chinese",
+		},
+	},
+	"pt": {
+		learn:     "Pode ensinar-te %s.",
+		teach:     "Podes ensinar-lhe %s.",
+		learnPair: "%s (%d/10, queres %d/10)",
+		teachPair: "%s (%d/10, quer %d/10)",
+		mutual:    "Podem ajudar-se um ao outro.",
+		none:      "Nenhum de vocês oferece uma competência que o outro procure.",
+		free:      "Estão ambos livres %s (%s), %s por semana.",
+		noOverlap: "Os vossos horários não coincidem esta semana.",
+		ratingOne: "Tem uma avaliação de %s/5 (1 avaliação).",
+		rating:    "Tem uma avaliação de %s/5 (%d avaliações).",
+		distance:  "Está a %d km.",
+		near:      "Está a menos de 1 km.",
+		speak:     "Ambos falam %s.",
+		and:       " e ",
+		more:      "mais %d",
+		decimal:   ",",
+		weekdays:  [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+		languages: map[string]string{
+			"de": "alemão", "en": "inglês", "es": "espanhol", "fr": "francês",
+			"it": "italiano", "ja": "japonês", "nl": "neerlandês", "pt": "português", "zh": "chinês",
+		},
+	},
+}
+
+// catalogFor returns the catalog of the locale's language, or of
+// DefaultLocale.
+func catalogFor(locale language.Tag) *catalog {
+	base, _ := locale.Base()
+	if c, ok := catalogs[base.String()]; ok {
+		return c
+	}
+	base, _ = DefaultLocale.Base()
+	return catalogs[base.String()]
+}
+
+// join joins at most limit items as "a, b and c", mentioning the rest as
+// "and N more".
+func (c *catalog) join(items []string, limit int) string {
+	if len(items) > limit {
+		items = append(items[:limit:limit], fmt.Sprintf(c.more, len(items)-limit))
+	}
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	default:
+		return strings.Join(items[:len(items)-1], ", ") + c.and + items[len(items)-1]
+	}
+}
+
+// number renders a one-decimal number, e.g. "4.5".
+func (c *catalog) number(f float64) string {
+	return strings.Replace(strconv.FormatFloat(f, 'f', 1, 64), ".", c.decimal, 1)
+}
+
+// distanceText rounds a distance to whole kilometres.
+func (c *catalog) distanceText(km float64) string {
+	if km < 1 {
+		return c.near
+	}
+	return fmt.Sprintf(c.distance, int(math.Round(km)))
+}
+
+// language names a base language tag.
+func (c *catalog) language(tag string) string {
+	if name, ok := c.languages[tag]; ok {
+		return name
+	}
+	return tag
+}
+
+// interval renders an interval as "Tue 18:00-20:00", naming the end's day
+// too when it falls after midnight on another local date.
+func (c *catalog) interval(interval scheduling.Interval, loc *time.Location) string {
+	start, end := interval.Start.In(loc), interval.End.In(loc)
+	out := c.weekdays[start.Weekday()] + start.Format(" 15:04") + "-"
+	switch {
+	case sameDate(start, end):
+		return out + end.Format("15:04")
+	case sameDate(start, end.Add(-time.Minute)) && end.Hour() == 0 && end.Minute() == 0:
+		return out + "24:00"
+	default:
+		return out + c.weekdays[end.Weekday()] + end.Format(" 15:04")
+	}
+}
+
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// formatDuration renders whole hours as "3h" and anything else as "2h30m".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours, minutes := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return fmt.Sprintf("%dh%02dm", hours, minutes)
+	}
+}
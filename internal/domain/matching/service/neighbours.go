@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/vectorsearch"
+)
+
+// SetUserIndex adds the nearest neighbours of a user's profile embedding to
+// the candidates of SimilarUsers, finding users who describe themselves
+// alike without sharing a skill category. It is not safe to call while
+// requests are served.
+func (s *Service) SetUserIndex(index vectorsearch.Index) {
+	s.userIndex = index
+}
+
+// neighbours returns up to k users whose profile embeddings are nearest the
+// user's, leaving out the user and anyone either of them blocked. The index
+// only widens the candidate pool, so its failures are logged, not returned.
+func (s *Service) neighbours(ctx context.Context, userID uuid.UUID, k int) []uuid.UUID {
+	if s.userIndex == nil {
+		return nil
+	}
+	vector, err := s.userIndex.Vector(ctx, userID)
+	if errors.Is(err, vectorsearch.ErrNotFound) {
+		return nil
+	}
+	var results []vectorsearch.Result
+	if err == nil {
+		results, err = s.userIndex.Search(ctx, vector, k, vectorsearch.Filter{
+			Exclude: []uuid.UUID{userID},
+			Viewer:  userID,
+		})
+	}
+	if err != nil {
+		s.logger.WarnContext(ctx, "nearest-neighbour search failed", "user_id", userID, "error", err)
+		return nil
+	}
+	ids := make([]uuid.UUID, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+// union appends the ids in extra missing from ids.
+func union(ids, extra []uuid.UUID) []uuid.UUID {
+	for _, id := range extra {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
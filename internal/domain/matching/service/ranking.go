@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/ranking"
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/repository"
+)
+
+// Assigner decides feature flags per user; the featureflag service
+// implements it.
+type Assigner interface {
+	EnabledFor(name, userID string) bool
+}
+
+// SetRanker orders FindMatches results by model for users the assigner
+// enables ranking.Flag for. Everyone else is the control arm, ordered by
+// score; both arms record the model version and their arm with each match. A
+// nil model turns ranking off. It is not safe to call while requests are
+// served.
+func (s *Service) SetRanker(model *ranking.Model, assigner Assigner) {
+	s.ranker = model
+	s.assigner = assigner
+}
+
+// variant is the user's experiment arm, or empty without a model.
+func (s *Service) variant(userID uuid.UUID) string {
+	switch {
+	case s.ranker == nil:
+		return ""
+	case s.assigner != nil && s.assigner.EnabledFor(ranking.Flag, userID.String()):
+		return ranking.VariantTreatment
+	default:
+		return ranking.VariantControl
+	}
+}
+
+// rank orders matches for the user's arm. The treatment arm needs features
+// of every match, so it loads their candidates up front.
+func (s *Service) rank(ctx context.Context, userID uuid.UUID, matches []Match) error {
+	variant := s.variant(userID)
+	if variant != ranking.VariantTreatment {
+		for i := range matches {
+			if variant != "" {
+				matches[i].ModelVersion = s.ranker.Version
+				matches[i].Variant = variant
+			}
+		}
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+		return nil
+	}
+
+	if err := s.attachCandidates(ctx, matches); err != nil {
+		return err
+	}
+	for i := range matches {
+		matches[i].ModelVersion = s.ranker.Version
+		matches[i].Variant = variant
+		matches[i].Relevance = s.ranker.Predict(matches[i].Features)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Relevance != matches[j].Relevance {
+			return matches[i].Relevance > matches[j].Relevance
+		}
+		return matches[i].Score > matches[j].Score
+	})
+	return nil
+}
+
+// attachCandidates loads the public profiles of candidates not attached yet
+// and computes the features of matches that have none. Matches whose
+// candidate was deleted are left without either.
+func (s *Service) attachCandidates(ctx context.Context, matches []Match) error {
+	var ids []uuid.UUID
+	for _, match := range matches {
+		if match.Candidate == nil {
+			ids = append(ids, match.CandidateID)
+		}
+	}
+	if len(ids) > 0 {
+		users, err := s.repo.GetUsers(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for i := range matches {
+			if user, ok := users[matches[i].CandidateID]; ok && matches[i].Candidate == nil {
+				matches[i].Candidate = &user
+			}
+		}
+	}
+
+	now := s.now()
+	for i := range matches {
+		if matches[i].Features == nil && matches[i].Candidate != nil {
+			matches[i].Features = s.features(matches[i], now)
+		}
+	}
+	return nil
+}
+
+// features describes a match to the ranking model.
+func (s *Service) features(match Match, now time.Time) ranking.Features {
+	candidate := ranking.Candidate{
+		Score:      match.Score,
+		SkillScore: match.skillScore,
+		Mutual:     match.IsMutual,
+		Pairs:      len(match.SkillMatches),
+	}
+	if match.SharedTime != nil && s.availability.Target > 0 {
+		candidate.Overlap = math.Min(1, float64(match.SharedTime.Weekly)/float64(s.availability.Target))
+	}
+	if user := match.Candidate; user != nil {
+		candidate.Rating = user.AverageRating
+		candidate.Sessions = user.TotalSessions
+		candidate.Verified = user.IsVerified
+		candidate.LastLoginAt = user.LastLoginAt
+	}
+	return ranking.NewFeatures(candidate, now)
+}
+
+// RecordProfileView attributes the user viewing other's profile to their
+// latest recent match, and reports whether there was one. Chats, bookings,
+// completed sessions and ratings are recorded by the database as they happen.
+func (s *Service) RecordProfileView(ctx context.Context, userID, otherID string) (bool, error) {
+	user, err := uuid.Parse(userID)
+	if err != nil {
+		return false, ErrInvalidUserID
+	}
+	other, err := uuid.Parse(otherID)
+	if err != nil {
+		return false, ErrInvalidUserID
+	}
+	if user == other {
+		return false, ErrSameUser
+	}
+	_, ok, err := s.repo.RecordFeedback(ctx, repository.Feedback{ActorID: user, OtherID: other, Event: ranking.EventProfileViewed})
+	return ok, err
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/language"
+)
+
+// RecommendationType selects what GetRecommendations returns.
+type RecommendationType string
+
+const (
+	RecommendPartners RecommendationType = "partners"
+	RecommendSkills   RecommendationType = "skills"
+	RecommendSessions RecommendationType = "sessions"
+)
+
+// Item types of recommendations.
+const (
+	ItemTypeUser    = "user"
+	ItemTypeSkill   = "skill"
+	ItemTypeSession = "session"
+)
+
+// sessionLength caps suggested session times.
+const sessionLength = time.Hour
+
+// RecommendParams describes a recommendation request.
+type RecommendParams struct {
+	UserID string
+	Limit  int
+	Type   RecommendationType
+	// Locale is the language partner and session reasons are rendered in;
+	// the zero value selects DefaultLocale.
+	Locale language.Tag
+}
+
+// Recommendation is an item suggested to the user.
+type Recommendation struct {
+	ItemID      uuid.UUID
+	ItemType    string
+	Title       string
+	Description string
+	Score       float64
+	// Reason is Reasons rendered in the requested locale when Reasons is set.
+	Reason string
+	// Reasons explain partner and session recommendations.
+	Reasons *Explanation
+}
+
+// Recommendations suggests partners (the user's best matches), skills to add
+// to their profile or times to meet partners, depending on params.Type.
+// Partners already contacted, blocked or skipped are not suggested. With a
+// cache, partners and skills are served from it; see cachedRecommendations.
+// Partner recommendations are not recorded as matches, as they may be
+// computed long before anyone sees them; session suggestions are.
+func (s *Service) Recommendations(ctx context.Context, params RecommendParams) ([]Recommendation, error) {
+	userID, err := uuid.Parse(params.UserID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+	limit := normalizeLimit(params.Limit)
+
+	var recommendations []Recommendation
+	switch params.Type {
+	case RecommendPartners, "":
+		recommendations, err = s.cachedRecommendations(ctx, userID, ItemTypeUser, limit)
+	case RecommendSkills:
+		recommendations, err = s.cachedRecommendations(ctx, userID, ItemTypeSkill, limit)
+	case RecommendSessions:
+		recommendations, err = s.sessionRecommendations(ctx, params.UserID, limit)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownRecommend, params.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if params.Locale != language.Und {
+		for i, r := range recommendations {
+			if r.Reasons != nil {
+				recommendations[i].Reason = r.Reasons.Text(params.Locale)
+			}
+		}
+	}
+	return recommendations, nil
+}
+
+// computeRecommendations computes partner or skill recommendations.
+func (s *Service) computeRecommendations(ctx context.Context, userID uuid.UUID, itemType string, limit int) ([]Recommendation, error) {
+	if itemType == ItemTypeSkill {
+		return s.skillRecommendations(ctx, userID, limit)
+	}
+	return s.partnerRecommendations(ctx, userID, limit)
+}
+
+func (s *Service) partnerRecommendations(ctx context.Context, userID uuid.UUID, limit int) ([]Recommendation, error) {
+	result, err := s.findMatches(ctx, FindParams{UserID: userID.String(), Limit: limit, excludeKnown: true})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(result.Matches))
+	for _, match := range result.Matches {
+		ids = append(ids, match.CandidateID)
+	}
+	users, err := s.repo.GetUsers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	recommendations := make([]Recommendation, 0, len(result.Matches))
+	for _, match := range result.Matches {
+		recommendation := Recommendation{
+			ItemID:   match.CandidateID,
+			ItemType: ItemTypeUser,
+			Score:    match.Score,
+			Reason:   match.Explanation,
+			Reasons:  &match.Reasons,
+		}
+		if user, ok := users[match.CandidateID]; ok {
+			recommendation.Title = user.DisplayName
+			recommendation.Description = user.Bio
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	return recommendations, nil
+}
+
+func (s *Service) skillRecommendations(ctx context.Context, userID uuid.UUID, limit int) ([]Recommendation, error) {
+	skills, err := s.repo.RecommendSkills(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	var maxPeers, maxPopularity int
+	for _, skill := range skills {
+		maxPeers = max(maxPeers, skill.Peers)
+		maxPopularity = max(maxPopularity, skill.Popularity)
+	}
+	recommendations := make([]Recommendation, 0, len(skills))
+	for _, skill := range skills {
+		recommendation := Recommendation{
+			ItemID:      skill.ID,
+			ItemType:    ItemTypeSkill,
+			Title:       skill.Name,
+			Description: skill.Description,
+		}
+		if skill.Peers > 0 {
+			recommendation.Score = float64(skill.Peers) / float64(maxPeers)
+			recommendation.Reason = peersReason(skill.Peers)
+		} else {
+			// Ranked after every skill peers list; only scored when the
+			// user's profile gave nothing to go on.
+			if maxPeers == 0 && maxPopularity > 0 {
+				recommendation.Score = float64(skill.Popularity) / float64(maxPopularity)
+			}
+			recommendation.Reason = "Popular on SkillSphere"
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	return recommendations, nil
+}
+
+// sessionRecommendations suggests a time with each partner sharing free time
+// in the coming week; ItemID is the partner, as sessions do not exist until
+// booked. Suggested times go stale by the hour, so sessions are not cached.
+func (s *Service) sessionRecommendations(ctx context.Context, userID string, limit int) ([]Recommendation, error) {
+	result, err := s.FindMatches(ctx, FindParams{UserID: userID, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	var recommendations []Recommendation
+	for _, match := range result.Matches {
+		if match.SharedTime == nil || match.SharedTime.Next == nil {
+			continue
+		}
+		next := *match.SharedTime.Next
+		if next.Duration() > sessionLength {
+			next.End = next.Start.Add(sessionLength)
+		}
+		loc := match.SharedTime.Location
+		if loc == nil {
+			loc = time.UTC
+		}
+		recommendation := Recommendation{
+			ItemID:      match.CandidateID,
+			ItemType:    ItemTypeSession,
+			Title:       "Session",
+			Description: fmt.Sprintf("%s-%s (%s)", next.Start.In(loc).Format("Mon 2 Jan 15:04"), next.End.In(loc).Format("15:04"), loc),
+			Score:       match.Score,
+			Reason:      match.Explanation,
+			Reasons:     &match.Reasons,
+		}
+		if match.Candidate != nil {
+			recommendation.Title = "Session with " + match.Candidate.DisplayName
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	return recommendations, nil
+}
+
+func peersReason(peers int) string {
+	if peers == 1 {
+		return "1 person who shares your skills lists it"
+	}
+	return fmt.Sprintf("%d people who share your skills list it", peers)
+}
@@ -2,57 +2,489 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/algorithms"
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/ranking"
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/repository"
+	"github.com/FACorreiaa/skillsphere-api/internal/embedding"
 	"github.com/FACorreiaa/skillsphere-api/internal/ontology"
+	"github.com/FACorreiaa/skillsphere-api/internal/scheduling"
+	"github.com/FACorreiaa/skillsphere-api/internal/vectorsearch"
 )
 
-// RecommendationRepository persists match results for auditing/caching.
-type RecommendationRepository interface {
-	SaveMatch(ctx context.Context, match Match) (*Match, error)
+const (
+	// DefaultLimit is used when a request does not set a limit.
+	DefaultLimit = 20
+	maxLimit     = 100
+	// candidatesPerResult is how many candidates are scored per requested
+	// result, so min-score filtering still leaves enough to return.
+	candidatesPerResult = 5
+	// DefaultAlgorithm is used when a request does not pick one.
+	DefaultAlgorithm = algorithms.Hybrid
+)
+
+var (
+	ErrInvalidUserID     = errors.New("invalid user id")
+	ErrSameUser          = errors.New("cannot match a user with themselves")
+	ErrUnknownAlgorithm  = algorithms.ErrUnknownAlgorithm
+	ErrUnknownRecommend  = errors.New("unknown recommendation type")
+	ErrInvalidMatchScore = errors.New("min match score must be between 0 and 1")
+	ErrTooManyCandidates = fmt.Errorf("at most %d candidates may be explained at once", maxLimit)
+)
+
+// algorithmIRIs maps algorithms to their ontology concepts.
+var algorithmIRIs = map[algorithms.Kind]string{
+	algorithms.Euclidean: ontology.ConceptMatchingAlgorithmEuclidean,
+	algorithms.Cosine:    ontology.ConceptMatchingAlgorithmCosine,
+	algorithms.Embedding: ontology.ConceptMatchingAlgorithmEmbedding,
+	algorithms.Hybrid:    ontology.ConceptMatchingAlgorithmHybrid,
 }
 
-// Match represents a computed recommendation.
+// Match is a scored pairing of the requester with a candidate.
 type Match struct {
-	ID           uuid.UUID
-	RequesterID  uuid.UUID
-	CandidateID  uuid.UUID
-	AlgorithmIRI string
-	Score        float64
-	Explanation  string
+	ID          uuid.UUID
+	RequesterID uuid.UUID
+	CandidateID uuid.UUID
+	Candidate   *repository.User
+	Algorithm   algorithms.Kind
+	Score       float64
+	// Explanation is Reasons rendered in DefaultLocale.
+	Explanation string
+	// Reasons attribute the score to its factors, for clients to render.
+	Reasons      Explanation
 	SkillMatches []ontology.SkillMatchEvent
+	// IsMutual is set when each user offers something the other wants.
+	IsMutual bool
+	// SharedTime is nil unless both users have an availability schedule.
+	SharedTime *SharedTime
+	// CandidateAvailability is the candidate's schedule, if they set one.
+	CandidateAvailability *scheduling.Schedule
+	// Features are what the ranking model sees, recorded with the match.
+	Features ranking.Features
+	// Relevance is the ranking model's prediction, set in the treatment arm.
+	Relevance float64
+	// ModelVersion and Variant name the ranking model and experiment arm
+	// that ordered the match, if a model is loaded.
+	ModelVersion string
+	Variant      string
+	CreatedAt    time.Time
+	// skillScore is the algorithm's score before availability is blended in.
+	skillScore float64
 }
 
-// Service coordinates match generation and ontology emission.
+// Service scores matches between users, records them in match_history and
+// emits them to the ontology.
 type Service struct {
-	repo    RecommendationRepository
-	emitter ontology.Emitter
+	repo         repository.RecommendationRepository
+	emitter      ontology.Emitter
+	logger       *slog.Logger
+	availability AvailabilityPolicy
+	embedder     *embedding.Embedder
+	userIndex    vectorsearch.Index
+	cache        repository.RecommendationCache
+	cachePolicy  CachePolicy
+	ranker       *ranking.Model
+	assigner     Assigner
+	skills       SkillExpander
+	now          func() time.Time
 }
 
 // NewService constructs the matching service.
-func NewService(repo RecommendationRepository, emitter ontology.Emitter) *Service {
+func NewService(repo repository.RecommendationRepository, emitter ontology.Emitter, logger *slog.Logger) *Service {
 	if emitter == nil {
 		emitter = ontology.NopEmitter{}
 	}
-	return &Service{repo: repo, emitter: emitter}
+	return &Service{
+		repo:         repo,
+		emitter:      emitter,
+		logger:       logger,
+		availability: DefaultAvailabilityPolicy(),
+		cachePolicy:  DefaultCachePolicy(),
+		now:          time.Now,
+	}
+}
+
+// FindParams describes a match search.
+type FindParams struct {
+	UserID    string
+	Algorithm algorithms.Kind
+	Limit     int
+	MinScore  float64
+	Filters   repository.Filters
+	// SkillLabels are skill names or alternative labels that, like
+	// Filters.SkillIDs, keep candidates offering one of the skills.
+	SkillLabels []string
+	// MinOverlap overrides the policy's minimum weekly overlap when set.
+	MinOverlap time.Duration
+	// excludeKnown drops partners already contacted, blocked or skipped, for
+	// partner recommendations.
+	excludeKnown bool
+}
+
+// FindResult holds the best matches and how they were chosen.
+type FindResult struct {
+	Matches   []Match
+	Algorithm algorithms.Kind
+	// TotalCandidates counts the users scored.
+	TotalCandidates int
+	// FilteredCount counts those scoring at least the minimum score.
+	FilteredCount int
+	ComputedAt    time.Time
+}
+
+// FindMatches scores candidates for the user and records the best ones.
+func (s *Service) FindMatches(ctx context.Context, params FindParams) (*FindResult, error) {
+	result, err := s.findMatches(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if result.Matches, err = s.record(ctx, result.Matches); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// findMatches scores candidates for the user without recording the matches.
+func (s *Service) findMatches(ctx context.Context, params FindParams) (*FindResult, error) {
+	userID, err := uuid.Parse(params.UserID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+	if params.MinScore < 0 || params.MinScore > 1 {
+		return nil, ErrInvalidMatchScore
+	}
+	alg, err := algorithm(params.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	limit := normalizeLimit(params.Limit)
+
+	filters := params.Filters
+	if filters.SkillIDs, err = s.filterSkills(ctx, filters.SkillIDs, params.SkillLabels); err != nil {
+		return nil, err
+	}
+
+	user, err := s.profile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	candidateIDs, err := s.repo.FindCandidates(ctx, repository.CandidateQuery{
+		UserID:       userID,
+		ExcludeKnown: params.excludeKnown,
+		Limit:        limit * candidatesPerResult,
+		Filters:      filters,
+	})
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := s.repo.GetProfiles(ctx, candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.schedules(ctx, append([]uuid.UUID{userID}, candidateIDs...))
+	if err != nil {
+		return nil, err
+	}
+	minOverlap := s.availability.MinOverlap
+	if params.MinOverlap > 0 {
+		minOverlap = params.MinOverlap
+	}
+
+	from := s.window()
+	result := &FindResult{Algorithm: alg.Kind(), TotalCandidates: len(profiles), ComputedAt: s.now().UTC()}
+	var matches []Match
+	for _, id := range candidateIDs {
+		candidate, ok := profiles[id]
+		if !ok {
+			continue
+		}
+		shared := s.sharedTime(scheduleOf(schedules, userID), scheduleOf(schedules, id), from)
+		if !admits(minOverlap, shared) {
+			continue
+		}
+		match := s.newMatch(alg, user, candidate, shared)
+		match.CandidateAvailability = scheduleOf(schedules, id)
+		if match.Score <= 0 || match.Score < params.MinScore {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	result.FilteredCount = len(matches)
+	if err := s.rank(ctx, userID, matches); err != nil {
+		return nil, err
+	}
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	result.Matches = matches
+	return result, nil
+}
+
+// ScoreParams names the two users to score.
+type ScoreParams struct {
+	UserID    string
+	OtherID   string
+	Algorithm algorithms.Kind
 }
 
-// RecordMatch writes a match and emits the ontology payload.
+// Score scores one pair of users and records the result.
+func (s *Service) Score(ctx context.Context, params ScoreParams) (*Match, error) {
+	userID, err := uuid.Parse(params.UserID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+	otherID, err := uuid.Parse(params.OtherID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+	if userID == otherID {
+		return nil, ErrSameUser
+	}
+	alg, err := algorithm(params.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := s.repo.GetProfiles(ctx, []uuid.UUID{userID, otherID})
+	if err != nil {
+		return nil, err
+	}
+	user, ok := profiles[userID]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	other, ok := profiles[otherID]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+
+	schedules, err := s.schedules(ctx, []uuid.UUID{userID, otherID})
+	if err != nil {
+		return nil, err
+	}
+	shared := s.sharedTime(scheduleOf(schedules, userID), scheduleOf(schedules, otherID), s.window())
+	match := s.newMatch(alg, user, other, shared)
+	match.CandidateAvailability = scheduleOf(schedules, otherID)
+
+	recorded, err := s.record(ctx, []Match{match})
+	if err != nil {
+		return nil, err
+	}
+	return &recorded[0], nil
+}
+
+// RecordMatch writes a computed match and emits the ontology payload.
 func (s *Service) RecordMatch(ctx context.Context, match Match) (*Match, error) {
-	saved, err := s.repo.SaveMatch(ctx, match)
+	recorded, err := s.record(ctx, []Match{match})
+	if err != nil {
+		return nil, err
+	}
+	return &recorded[0], nil
+}
+
+// SimilarParams describes a similar-user search.
+type SimilarParams struct {
+	UserID    string
+	Limit     int
+	Algorithm algorithms.Kind
+}
+
+// SimilarUser is a user with a profile like the requester's.
+type SimilarUser struct {
+	User         repository.User
+	Score        float64
+	CommonSkills []string
+}
+
+// SimilarUsers returns users who offer and want the same skills as the user,
+// drawn from users listing skills in the same categories and, with a user
+// index, those with the nearest profile embeddings. Similar users are not
+// recorded as matches.
+func (s *Service) SimilarUsers(ctx context.Context, params SimilarParams) ([]SimilarUser, error) {
+	userID, err := uuid.Parse(params.UserID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+	alg, err := algorithm(params.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	limit := normalizeLimit(params.Limit)
+
+	user, err := s.profile(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
-	event := ontology.NewMatchEvent(ontology.MatchEvent{
-		MatchID:      saved.ID,
-		RequesterID:  saved.RequesterID,
-		CandidateID:  saved.CandidateID,
-		AlgorithmIRI: saved.AlgorithmIRI,
-		Score:        saved.Score,
-		SkillMatches: saved.SkillMatches,
-		Explanation:  saved.Explanation,
+	candidateIDs, err := s.repo.FindCandidates(ctx, repository.CandidateQuery{
+		UserID:  userID,
+		Similar: true,
+		Limit:   limit * candidatesPerResult,
 	})
-	_ = s.emitter.Emit(ctx, event)
-	return saved, nil
+	if err != nil {
+		return nil, err
+	}
+	candidateIDs = union(candidateIDs, s.neighbours(ctx, userID, limit*candidatesPerResult))
+	profiles, err := s.repo.GetProfiles(ctx, candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var similar []SimilarUser
+	for _, id := range candidateIDs {
+		candidate, ok := profiles[id]
+		if !ok {
+			continue
+		}
+		result := algorithms.Similarity(alg, user, candidate)
+		if result.Score <= 0 {
+			continue
+		}
+		similar = append(similar, SimilarUser{
+			User:         repository.User{ID: id},
+			Score:        result.Score,
+			CommonSkills: result.CommonSkills,
+		})
+	}
+	sort.SliceStable(similar, func(i, j int) bool { return similar[i].Score > similar[j].Score })
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+
+	ids := make([]uuid.UUID, 0, len(similar))
+	for _, user := range similar {
+		ids = append(ids, user.User.ID)
+	}
+	users, err := s.repo.GetUsers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := similar[:0]
+	for _, user := range similar {
+		if found, ok := users[user.User.ID]; ok {
+			user.User = found
+			out = append(out, user)
+		}
+	}
+	return out, nil
+}
+
+// newMatch scores candidate for user, blends in shared availability and
+// explains the result.
+func (s *Service) newMatch(alg algorithms.Algorithm, user, candidate algorithms.Profile, shared *SharedTime) Match {
+	result := algorithms.Match(alg, user, candidate)
+	skillMatches := make([]ontology.SkillMatchEvent, 0, len(result.Pairs))
+	for _, pair := range result.Pairs {
+		skillMatches = append(skillMatches, ontology.SkillMatchEvent{
+			SkillName:        pair.SkillName,
+			UserProficiency:  pair.UserProficiency,
+			MatchProficiency: pair.MatchProficiency,
+			IsComplementary:  pair.IsComplementary,
+		})
+	}
+	skillShare, overlap := s.availability.split(result.Score, shared)
+	reasons := explain(result, skillShare, overlap, shared)
+	return Match{
+		RequesterID:  user.UserID,
+		CandidateID:  candidate.UserID,
+		Algorithm:    alg.Kind(),
+		Score:        skillShare + overlap,
+		skillScore:   result.Score,
+		Explanation:  reasons.Text(DefaultLocale),
+		Reasons:      reasons,
+		SkillMatches: skillMatches,
+		IsMutual:     result.Mutual,
+		SharedTime:   shared,
+	}
+}
+
+// record attaches the candidates' public profiles, completes the matches'
+// explanations, saves matches to match_history with their ranking features
+// and explanations, and emits one ontology event per match.
+func (s *Service) record(ctx context.Context, matches []Match) ([]Match, error) {
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	if err := s.attachCandidates(ctx, matches); err != nil {
+		return nil, err
+	}
+	if err := s.explainContext(ctx, matches); err != nil {
+		return nil, err
+	}
+	rows := make([]repository.Match, 0, len(matches))
+	for _, match := range matches {
+		explanation, err := json.Marshal(match.Reasons)
+		if err != nil {
+			return nil, fmt.Errorf("encode explanation: %w", err)
+		}
+		rows = append(rows, repository.Match{
+			RequesterID:  match.RequesterID,
+			CandidateID:  match.CandidateID,
+			Algorithm:    match.Algorithm,
+			Score:        match.Score,
+			Features:     match.Features,
+			ModelVersion: match.ModelVersion,
+			Variant:      match.Variant,
+			Explanation:  explanation,
+		})
+	}
+	saved, err := s.repo.SaveMatches(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range matches {
+		matches[i].ID = saved[i].ID
+		matches[i].CreatedAt = saved[i].CreatedAt
+		event := ontology.NewMatchEvent(ontology.MatchEvent{
+			MatchID:      matches[i].ID,
+			RequesterID:  matches[i].RequesterID,
+			CandidateID:  matches[i].CandidateID,
+			AlgorithmIRI: ontology.RankedAlgorithmIRI(algorithmIRIs[matches[i].Algorithm], matches[i].ModelVersion, matches[i].Variant),
+			Score:        matches[i].Score,
+			SkillMatches: matches[i].SkillMatches,
+			Explanation:  explanationJSON(matches[i]),
+		})
+		if err := s.emitter.Emit(ctx, event); err != nil {
+			s.logger.WarnContext(ctx, "failed to emit match event", "match_id", matches[i].ID, "error", err)
+		}
+	}
+	return matches, nil
+}
+
+func (s *Service) profile(ctx context.Context, userID uuid.UUID) (algorithms.Profile, error) {
+	profiles, err := s.repo.GetProfiles(ctx, []uuid.UUID{userID})
+	if err != nil {
+		return algorithms.Profile{}, err
+	}
+	profile, ok := profiles[userID]
+	if !ok {
+		return algorithms.Profile{}, repository.ErrUserNotFound
+	}
+	return profile, nil
+}
+
+func algorithm(kind algorithms.Kind) (algorithms.Algorithm, error) {
+	if kind == "" {
+		kind = DefaultAlgorithm
+	}
+	alg, err := algorithms.New(kind)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", err, kind)
+	}
+	return alg, nil
+}
+
+func normalizeLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	return min(limit, maxLimit)
 }
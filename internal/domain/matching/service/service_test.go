@@ -0,0 +1,874 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/algorithms"
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/ranking"
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/repository"
+	taxonomyrepo "github.com/FACorreiaa/skillsphere-api/internal/domain/taxonomy/repository"
+	"github.com/FACorreiaa/skillsphere-api/internal/embedding"
+	"github.com/FACorreiaa/skillsphere-api/internal/ontology"
+	"github.com/FACorreiaa/skillsphere-api/internal/vectorsearch"
+)
+
+type fakeMatchingRepo struct {
+	profiles  map[uuid.UUID]algorithms.Profile
+	order     []uuid.UUID
+	saved     []repository.Match
+	queries   []repository.CandidateQuery
+	skills    []repository.SkillRecommendation
+	schedules map[uuid.UUID]repository.Availability
+	users     map[uuid.UUID]repository.User
+	feedback  []repository.Feedback
+	// distances are kilometres between pairs, keyed from the requester.
+	distances map[[2]uuid.UUID]float64
+}
+
+func (r *fakeMatchingRepo) SaveMatches(_ context.Context, matches []repository.Match) ([]repository.Match, error) {
+	out := make([]repository.Match, 0, len(matches))
+	for _, match := range matches {
+		match.ID = uuid.New()
+		match.CreatedAt = time.Now()
+		out = append(out, match)
+	}
+	r.saved = append(r.saved, out...)
+	return out, nil
+}
+
+func (r *fakeMatchingRepo) GetProfiles(_ context.Context, ids []uuid.UUID) (map[uuid.UUID]algorithms.Profile, error) {
+	out := make(map[uuid.UUID]algorithms.Profile)
+	for _, id := range ids {
+		if profile, ok := r.profiles[id]; ok {
+			out[id] = profile
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeMatchingRepo) GetUsers(_ context.Context, ids []uuid.UUID) (map[uuid.UUID]repository.User, error) {
+	out := make(map[uuid.UUID]repository.User)
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			out[id] = user
+		} else if _, ok := r.profiles[id]; ok {
+			out[id] = repository.User{ID: id, DisplayName: "user " + id.String()[:4]}
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeMatchingRepo) GetDistances(_ context.Context, from uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]float64, error) {
+	out := make(map[uuid.UUID]float64)
+	for _, id := range ids {
+		if km, ok := r.distances[[2]uuid.UUID{from, id}]; ok {
+			out[id] = km
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeMatchingRepo) RecordFeedback(_ context.Context, feedback repository.Feedback) (uuid.UUID, bool, error) {
+	for _, match := range r.saved {
+		if match.RequesterID == feedback.ActorID && match.CandidateID == feedback.OtherID ||
+			match.RequesterID == feedback.OtherID && match.CandidateID == feedback.ActorID {
+			r.feedback = append(r.feedback, feedback)
+			return match.ID, true, nil
+		}
+	}
+	return uuid.Nil, false, nil
+}
+
+func (r *fakeMatchingRepo) GetExplanations(_ context.Context, requester uuid.UUID, ids []uuid.UUID) (map[uuid.UUID][]byte, error) {
+	out := make(map[uuid.UUID][]byte)
+	for _, match := range r.saved {
+		if match.RequesterID == requester && slices.Contains(ids, match.CandidateID) && match.Explanation != nil {
+			out[match.CandidateID] = match.Explanation
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeMatchingRepo) FindCandidates(_ context.Context, q repository.CandidateQuery) ([]uuid.UUID, error) {
+	r.queries = append(r.queries, q)
+	var ids []uuid.UUID
+	for _, id := range r.order {
+		if id != q.UserID && offersAny(r.profiles[id], q.Filters.SkillIDs) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// offersAny applies Filters.SkillIDs as FindMatchCandidates does.
+func offersAny(profile algorithms.Profile, skillIDs []uuid.UUID) bool {
+	if len(skillIDs) == 0 {
+		return true
+	}
+	for _, skill := range profile.Offered {
+		if slices.Contains(skillIDs, skill.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakeMatchingRepo) RecommendSkills(context.Context, uuid.UUID, int) ([]repository.SkillRecommendation, error) {
+	return r.skills, nil
+}
+
+func (r *fakeMatchingRepo) GetAvailability(_ context.Context, ids []uuid.UUID) (map[uuid.UUID]repository.Availability, error) {
+	out := make(map[uuid.UUID]repository.Availability)
+	for _, id := range ids {
+		if availability, ok := r.schedules[id]; ok {
+			out[id] = availability
+		}
+	}
+	return out, nil
+}
+
+type recordingEmitter struct {
+	events []ontology.Event
+}
+
+func (e *recordingEmitter) Emit(_ context.Context, event ontology.Event) error {
+	e.events = append(e.events, event)
+	return nil
+}
+
+var (
+	goSkill = algorithms.Skill{ID: uuid.New(), Name: "Go"}
+	spanish = algorithms.Skill{ID: uuid.New(), Name: "Spanish"}
+	guitar  = algorithms.Skill{ID: uuid.New(), Name: "Guitar"}
+)
+
+func level(skill algorithms.Skill, proficiency int) algorithms.Skill {
+	skill.Proficiency = proficiency
+	return skill
+}
+
+// community has a requester wanting Go and offering Spanish, a partner who
+// complements them both ways, a mentor who only teaches Go at a lower level
+// and a stranger with nothing in common.
+type community struct {
+	requester, partner, mentor, stranger uuid.UUID
+}
+
+func newTestService(t *testing.T) (*Service, *fakeMatchingRepo, *recordingEmitter, community) {
+	t.Helper()
+	c := community{requester: uuid.New(), partner: uuid.New(), mentor: uuid.New(), stranger: uuid.New()}
+	repo := &fakeMatchingRepo{
+		profiles: map[uuid.UUID]algorithms.Profile{
+			c.requester: {UserID: c.requester, Offered: []algorithms.Skill{level(spanish, 8)}, Wanted: []algorithms.Skill{level(goSkill, 7)}},
+			c.partner:   {UserID: c.partner, Offered: []algorithms.Skill{level(goSkill, 9)}, Wanted: []algorithms.Skill{level(spanish, 4)}},
+			c.mentor:    {UserID: c.mentor, Offered: []algorithms.Skill{level(goSkill, 5)}},
+			c.stranger:  {UserID: c.stranger, Offered: []algorithms.Skill{level(guitar, 9)}},
+		},
+		order: []uuid.UUID{c.mentor, c.stranger, c.partner},
+	}
+	emitter := &recordingEmitter{}
+	return NewService(repo, emitter, slog.New(slog.NewTextHandler(io.Discard, nil))), repo, emitter, c
+}
+
+func TestFindMatches_RanksRecordsAndEmits(t *testing.T) {
+	svc, repo, emitter, c := newTestService(t)
+
+	result, err := svc.FindMatches(context.Background(), FindParams{
+		UserID:    c.requester.String(),
+		Algorithm: algorithms.Euclidean,
+		Limit:     5,
+	})
+	if err != nil {
+		t.Fatalf("FindMatches: %v", err)
+	}
+	if result.TotalCandidates != 3 || result.FilteredCount != 2 || result.Algorithm != algorithms.Euclidean {
+		t.Errorf("result = %+v", result)
+	}
+	if len(result.Matches) != 2 || result.Matches[0].CandidateID != c.partner || result.Matches[1].CandidateID != c.mentor {
+		t.Fatalf("matches = %+v, want partner then mentor", result.Matches)
+	}
+
+	best := result.Matches[0]
+	if !best.IsMutual || best.Score != 1 || best.Candidate == nil || best.ID == uuid.Nil {
+		t.Errorf("best = %+v", best)
+	}
+	if !strings.Contains(best.Explanation, "They can teach you Go (9/10, you want 7/10)") ||
+		!strings.Contains(best.Explanation, "You can teach them Spanish (8/10, they want 4/10)") {
+		t.Errorf("explanation = %q", best.Explanation)
+	}
+	if result.Matches[1].IsMutual {
+		t.Error("the mentor wants nothing from the requester")
+	}
+
+	if len(repo.saved) != 2 || repo.saved[0].Algorithm != algorithms.Euclidean || repo.saved[0].RequesterID != c.requester {
+		t.Errorf("saved = %+v", repo.saved)
+	}
+	if len(emitter.events) != 2 {
+		t.Fatalf("emitted %d events, want 2", len(emitter.events))
+	}
+	if repo.queries[0].Limit != 5*candidatesPerResult || repo.queries[0].Similar {
+		t.Errorf("candidate query = %+v", repo.queries[0])
+	}
+}
+
+func TestFindMatches_MinScoreAndValidation(t *testing.T) {
+	svc, repo, _, c := newTestService(t)
+	ctx := context.Background()
+
+	result, err := svc.FindMatches(ctx, FindParams{UserID: c.requester.String(), MinScore: 0.99})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Matches) != 1 || result.Matches[0].CandidateID != c.partner || result.Algorithm != DefaultAlgorithm {
+		t.Errorf("result = %+v", result)
+	}
+
+	for _, tc := range []struct {
+		params FindParams
+		want   error
+	}{
+		{FindParams{UserID: "nope"}, ErrInvalidUserID},
+		{FindParams{UserID: c.requester.String(), MinScore: 2}, ErrInvalidMatchScore},
+		{FindParams{UserID: c.requester.String(), Algorithm: "astrology"}, ErrUnknownAlgorithm},
+		{FindParams{UserID: uuid.NewString()}, repository.ErrUserNotFound},
+	} {
+		if _, err := svc.FindMatches(ctx, tc.params); !errors.Is(err, tc.want) {
+			t.Errorf("FindMatches(%+v) = %v, want %v", tc.params, err, tc.want)
+		}
+	}
+	if len(repo.saved) != 1 {
+		t.Errorf("saved %d matches, want only the one above the minimum", len(repo.saved))
+	}
+}
+
+// fakeTaxonomy expands skills to their narrower skills and resolves labels.
+type fakeTaxonomy struct {
+	narrower map[uuid.UUID][]uuid.UUID
+	labels   map[string]uuid.UUID
+}
+
+func (f fakeTaxonomy) Expand(_ context.Context, ids []uuid.UUID, opts taxonomyrepo.ExpandOptions) ([]taxonomyrepo.Expansion, error) {
+	var out []taxonomyrepo.Expansion
+	for _, id := range ids {
+		out = append(out, taxonomyrepo.Expansion{SkillID: id, Relation: taxonomyrepo.RelationExact})
+		if opts.Narrower {
+			for _, narrower := range f.narrower[id] {
+				out = append(out, taxonomyrepo.Expansion{SkillID: narrower, Relation: taxonomyrepo.RelationNarrower, Depth: 1})
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f fakeTaxonomy) ExpandTerm(ctx context.Context, term string, opts taxonomyrepo.ExpandOptions) ([]taxonomyrepo.Expansion, error) {
+	id, ok := f.labels[taxonomyrepo.NormalizeLabel(term)]
+	if !ok {
+		return nil, nil
+	}
+	return f.Expand(ctx, []uuid.UUID{id}, opts)
+}
+
+func TestFindMatches_SkillFilterFollowsTaxonomy(t *testing.T) {
+	svc, repo, _, c := newTestService(t)
+	ctx := context.Background()
+	programming := uuid.New()
+	byProgramming := FindParams{UserID: c.requester.String(), Filters: repository.Filters{SkillIDs: []uuid.UUID{programming}}}
+
+	result, err := svc.FindMatches(ctx, byProgramming)
+	if err != nil {
+		t.Fatalf("FindMatches: %v", err)
+	}
+	if len(result.Matches) != 0 {
+		t.Fatalf("matches = %+v, want none offer Programming itself", result.Matches)
+	}
+
+	svc.SetSkillExpander(fakeTaxonomy{
+		narrower: map[uuid.UUID][]uuid.UUID{programming: {goSkill.ID}},
+		labels:   map[string]uuid.UUID{"golang": goSkill.ID},
+	})
+	for name, params := range map[string]FindParams{
+		"narrower":  byProgramming,
+		"alt label": {UserID: c.requester.String(), SkillLabels: []string{"Golang"}},
+	} {
+		result, err := svc.FindMatches(ctx, params)
+		if err != nil {
+			t.Fatalf("%s: FindMatches: %v", name, err)
+		}
+		if len(result.Matches) != 2 || result.Matches[0].CandidateID != c.partner || result.Matches[1].CandidateID != c.mentor {
+			t.Errorf("%s: matches = %+v, want the Go partner and mentor", name, result.Matches)
+		}
+		if query := repo.queries[len(repo.queries)-1]; !slices.Contains(query.Filters.SkillIDs, goSkill.ID) {
+			t.Errorf("%s: candidate query skills = %v, want Go included", name, query.Filters.SkillIDs)
+		}
+	}
+
+	if _, err := svc.FindMatches(ctx, FindParams{UserID: c.requester.String(), SkillLabels: []string{"Cobol"}}); !errors.Is(err, ErrUnknownSkillLabel) {
+		t.Errorf("err = %v, want ErrUnknownSkillLabel", err)
+	}
+}
+
+func TestScore(t *testing.T) {
+	svc, repo, emitter, c := newTestService(t)
+	ctx := context.Background()
+
+	match, err := svc.Score(ctx, ScoreParams{UserID: c.requester.String(), OtherID: c.stranger.String(), Algorithm: algorithms.Cosine})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match.Score != 0 || match.IsMutual || match.Explanation != "Neither of you offers a skill the other wants." {
+		t.Errorf("stranger = %+v", match)
+	}
+	if len(repo.saved) != 1 || len(emitter.events) != 1 {
+		t.Errorf("a scored pair is recorded even when it is a poor match")
+	}
+
+	if _, err := svc.Score(ctx, ScoreParams{UserID: c.requester.String(), OtherID: c.requester.String()}); !errors.Is(err, ErrSameUser) {
+		t.Errorf("self = %v", err)
+	}
+	if _, err := svc.Score(ctx, ScoreParams{UserID: c.requester.String(), OtherID: uuid.NewString()}); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("unknown = %v", err)
+	}
+}
+
+func TestSimilarUsers(t *testing.T) {
+	svc, repo, _, c := newTestService(t)
+
+	similar, err := svc.SimilarUsers(context.Background(), SimilarParams{UserID: c.partner.String(), Algorithm: algorithms.Euclidean})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(similar) != 1 || similar[0].User.ID != c.mentor || similar[0].CommonSkills[0] != "Go" {
+		t.Errorf("similar = %+v, want the mentor, who also offers Go", similar)
+	}
+	if !repo.queries[0].Similar || len(repo.saved) != 0 {
+		t.Errorf("similar users must query like-for-like and not be recorded")
+	}
+}
+
+func TestSimilarUsers_UserIndex(t *testing.T) {
+	svc, repo, _, c := newTestService(t)
+	ctx := context.Background()
+	// The mentor shares no skill category with the partner as far as the
+	// candidate query knows; only the embeddings bring them together.
+	repo.order = []uuid.UUID{c.stranger}
+
+	index := vectorsearch.NewExact(vectorsearch.Cosine)
+	for id, v := range map[uuid.UUID][]float32{c.partner: {1, 0}, c.mentor: {0.9, 0.1}, c.stranger: {0, 1}} {
+		if err := index.Put(vectorsearch.Item{ID: id, Vector: v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc.SetUserIndex(index)
+
+	similar, err := svc.SimilarUsers(ctx, SimilarParams{UserID: c.partner.String(), Algorithm: algorithms.Euclidean})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(similar) != 1 || similar[0].User.ID != c.mentor {
+		t.Errorf("similar = %+v, want the mentor found through the index", similar)
+	}
+
+	index.SetBlocks([]vectorsearch.Block{{Blocker: c.mentor, Blocked: c.partner}})
+	if similar, _ := svc.SimilarUsers(ctx, SimilarParams{UserID: c.partner.String(), Algorithm: algorithms.Euclidean}); len(similar) != 0 {
+		t.Errorf("similar = %+v, a blocked user must not be suggested", similar)
+	}
+	if _, err := svc.SimilarUsers(ctx, SimilarParams{UserID: c.requester.String()}); err != nil {
+		t.Errorf("a user without an embedding should fall back to the candidate query: %v", err)
+	}
+}
+
+func TestRecommendations(t *testing.T) {
+	svc, repo, _, c := newTestService(t)
+	ctx := context.Background()
+	repo.skills = []repository.SkillRecommendation{
+		{ID: guitar.ID, Name: "Guitar", Peers: 2},
+		{ID: uuid.New(), Name: "Chess", Popularity: 40},
+	}
+
+	partners, err := svc.Recommendations(ctx, RecommendParams{UserID: c.requester.String(), Type: RecommendPartners})
+	if err != nil || len(partners) != 2 || partners[0].ItemID != c.partner || partners[0].ItemType != ItemTypeUser {
+		t.Errorf("partners = %+v, %v", partners, err)
+	}
+
+	skills, err := svc.Recommendations(ctx, RecommendParams{UserID: c.requester.String(), Type: RecommendSkills})
+	if err != nil || len(skills) != 2 {
+		t.Fatalf("skills = %+v, %v", skills, err)
+	}
+	if skills[0].Score != 1 || skills[0].Reason != "2 people who share your skills list it" || skills[1].Score != 0 {
+		t.Errorf("skills = %+v", skills)
+	}
+
+	sessions, err := svc.Recommendations(ctx, RecommendParams{UserID: c.requester.String(), Type: RecommendSessions})
+	if err != nil || len(sessions) != 0 {
+		t.Errorf("sessions without schedules = %+v, %v", sessions, err)
+	}
+}
+
+type cacheKey struct {
+	user     uuid.UUID
+	itemType string
+}
+
+type fakeCache struct {
+	entries map[cacheKey]repository.CacheEntry
+	queue   map[uuid.UUID]bool
+	skips   map[uuid.UUID][]uuid.UUID
+	// failStore makes storing this user's recommendations fail.
+	failStore uuid.UUID
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{
+		entries: make(map[cacheKey]repository.CacheEntry),
+		queue:   make(map[uuid.UUID]bool),
+		skips:   make(map[uuid.UUID][]uuid.UUID),
+	}
+}
+
+func (c *fakeCache) CachedRecommendations(_ context.Context, user uuid.UUID, itemType string, limit int) (repository.CacheEntry, bool, error) {
+	entry, ok := c.entries[cacheKey{user, itemType}]
+	entry.Queued = c.queue[user]
+	if len(entry.Items) > limit {
+		entry.Items = entry.Items[:limit]
+	}
+	return entry, ok, nil
+}
+
+func (c *fakeCache) StoreRecommendations(_ context.Context, user uuid.UUID, itemType string, items []repository.CachedRecommendation, generatedAt time.Time) error {
+	if user == c.failStore {
+		return errors.New("store failed")
+	}
+	c.entries[cacheKey{user, itemType}] = repository.CacheEntry{Items: items, GeneratedAt: generatedAt}
+	return nil
+}
+
+func (c *fakeCache) EnqueueRefresh(_ context.Context, ids []uuid.UUID) error {
+	for _, id := range ids {
+		c.queue[id] = true
+	}
+	return nil
+}
+
+func (c *fakeCache) ClaimRefresh(_ context.Context, limit int, _ time.Duration) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for id := range c.queue {
+		if len(ids) == limit {
+			break
+		}
+		ids = append(ids, id)
+		delete(c.queue, id)
+	}
+	return ids, nil
+}
+
+func (c *fakeCache) SkipPartner(_ context.Context, user, skipped uuid.UUID) error {
+	c.skips[user] = append(c.skips[user], skipped)
+	return nil
+}
+
+func TestRecommendations_Cache(t *testing.T) {
+	svc, repo, emitter, c := newTestService(t)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	cache := newFakeCache()
+	svc.SetRecommendationCache(cache, CachePolicy{TTL: time.Hour, MaxStale: time.Hour})
+	params := RecommendParams{UserID: c.requester.String(), Type: RecommendPartners, Limit: 1}
+
+	missed, err := svc.Recommendations(ctx, params)
+	if err != nil || len(missed) != 1 || missed[0].ItemID != c.partner {
+		t.Fatalf("on a miss = %+v, %v", missed, err)
+	}
+	if len(repo.queries) != 1 || !repo.queries[0].ExcludeKnown {
+		t.Errorf("queries = %+v, partner recommendations must exclude known users", repo.queries)
+	}
+	stored := cache.entries[cacheKey{c.requester, ItemTypeUser}]
+	if len(stored.Items) != 2 || !stored.GeneratedAt.Equal(now) {
+		t.Errorf("stored = %+v, want every partner, not just the limit", stored)
+	}
+	if len(repo.saved) != 0 || len(emitter.events) != 0 {
+		t.Error("partner recommendations are not recorded as matches")
+	}
+
+	// Served from the cache: a change to the repository goes unnoticed.
+	repo.order = nil
+	fresh, err := svc.Recommendations(ctx, params)
+	if err != nil || len(fresh) != 1 || fresh[0].ItemID != c.partner || fresh[0].ItemType != ItemTypeUser || len(repo.queries) != 1 {
+		t.Errorf("fresh = %+v, %v after %d queries", fresh, err, len(repo.queries))
+	}
+	if fresh[0].Reason != missed[0].Reason || !strings.HasPrefix(fresh[0].Reason, "They can teach you") {
+		t.Errorf("cached reason = %q, want %q", fresh[0].Reason, missed[0].Reason)
+	}
+	pt := params
+	pt.Locale = Locale("pt")
+	if translated, err := svc.Recommendations(ctx, pt); err != nil || len(translated) != 1 ||
+		translated[0].Reason != missed[0].Reasons.Text(pt.Locale) || translated[0].Reason == missed[0].Reason {
+		t.Errorf("cached reason in pt = %+v, %v", translated, err)
+	}
+
+	now = now.Add(90 * time.Minute)
+	stale, err := svc.Recommendations(ctx, params)
+	if err != nil || len(stale) != 1 || !cache.queue[c.requester] || len(repo.queries) != 1 {
+		t.Errorf("stale = %+v, %v; queued %v", stale, err, cache.queue[c.requester])
+	}
+
+	now = now.Add(time.Hour)
+	expired, err := svc.Recommendations(ctx, params)
+	if err != nil || len(expired) != 0 || len(repo.queries) != 2 {
+		t.Errorf("past max stale = %+v, %v, want a recompute", expired, err)
+	}
+
+	cache.failStore = c.requester
+	repo.order = []uuid.UUID{c.partner}
+	now = now.Add(3 * time.Hour)
+	if uncached, err := svc.Recommendations(ctx, params); err != nil || len(uncached) != 1 {
+		t.Errorf("a failed store should still serve what was computed: %+v, %v", uncached, err)
+	}
+}
+
+func TestRecommendationRefresher(t *testing.T) {
+	svc, repo, _, c := newTestService(t)
+	ctx := context.Background()
+	cache := newFakeCache()
+	svc.SetRecommendationCache(cache, DefaultCachePolicy())
+	repo.skills = []repository.SkillRecommendation{{ID: guitar.ID, Name: "Guitar", Peers: 1}}
+	gone := uuid.New()
+	cache.queue[c.requester] = true
+	cache.queue[c.mentor] = true
+	cache.queue[gone] = true
+	cache.failStore = c.mentor
+
+	n, err := NewRecommendationRefresher(svc, 0, nil).Refresh(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("Refresh = %d, %v; want the requester only", n, err)
+	}
+	if len(cache.entries[cacheKey{c.requester, ItemTypeUser}].Items) != 2 || len(cache.entries[cacheKey{c.requester, ItemTypeSkill}].Items) != 1 {
+		t.Errorf("entries = %+v", cache.entries)
+	}
+	if !cache.queue[c.mentor] || cache.queue[gone] || cache.queue[c.requester] {
+		t.Errorf("queue = %v, want only the failed user requeued", cache.queue)
+	}
+
+	if err := svc.SkipPartner(ctx, c.requester.String(), c.partner.String()); err != nil || len(cache.skips[c.requester]) != 1 {
+		t.Errorf("SkipPartner = %v, skips %v", err, cache.skips)
+	}
+	if err := svc.SkipPartner(ctx, c.requester.String(), gone.String()); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("skipping an unknown user = %v", err)
+	}
+}
+
+// withSchedules gives the requester (Lisbon) and partner (New York) two hours
+// together on Tuesday evenings, while the mentor is only free on Wednesdays
+// and the stranger has no schedule. The clock is the Monday before the US
+// switches to summer time.
+func withSchedules(svc *Service, repo *fakeMatchingRepo, c community) {
+	svc.now = func() time.Time { return time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC) }
+	repo.schedules = map[uuid.UUID]repository.Availability{
+		c.requester: {Timezone: "Europe/Lisbon", WeeklySchedule: []byte(`{"tuesday":["18:00-20:00"],"wednesday":["07:00-08:00"]}`)},
+		c.partner:   {Timezone: "America/New_York", WeeklySchedule: []byte(`{"tuesday":["13:00-16:00"]}`)},
+		c.mentor:    {Timezone: "Europe/Lisbon", WeeklySchedule: []byte(`{"wednesday":["19:00-21:00"]}`)},
+	}
+}
+
+func TestFindMatches_Availability(t *testing.T) {
+	svc, repo, _, c := newTestService(t)
+	withSchedules(svc, repo, c)
+	ctx := context.Background()
+
+	result, err := svc.FindMatches(ctx, FindParams{UserID: c.requester.String(), Algorithm: algorithms.Euclidean})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Matches) != 2 {
+		t.Fatalf("matches = %+v", result.Matches)
+	}
+	partner, mentor := result.Matches[0], result.Matches[1]
+	if partner.SharedTime == nil || partner.SharedTime.Weekly != 2*time.Hour || partner.Score != 1 {
+		t.Errorf("partner = %+v, want two shared hours and a full score", partner)
+	}
+	if !strings.HasSuffix(partner.Explanation, "You are both free Tue 18:00-20:00 (Europe/Lisbon), 2h a week.") {
+		t.Errorf("partner explanation = %q", partner.Explanation)
+	}
+	if text := partner.Reasons.Text(Locale("pt")); !strings.HasSuffix(text, "Estão ambos livres ter 18:00-20:00 (Europe/Lisbon), 2h por semana.") {
+		t.Errorf("pt partner explanation = %q", text)
+	}
+	var overlap float64
+	for _, f := range mentor.Reasons.Factors {
+		if f.Kind == FactorAvailability {
+			overlap += f.Contribution
+		}
+	}
+	if overlap != 0 || len(mentor.Reasons.Factors) != 3 {
+		t.Errorf("mentor factors = %+v, want a pair, its gap and no overlap", mentor.Reasons.Factors)
+	}
+	if partner.CandidateAvailability == nil || partner.CandidateAvailability.Location.String() != "America/New_York" {
+		t.Errorf("candidate availability = %+v", partner.CandidateAvailability)
+	}
+
+	euclidean, _ := algorithms.New(algorithms.Euclidean)
+	skill := algorithms.Match(euclidean, repo.profiles[c.requester], repo.profiles[c.mentor]).Score
+	if want := 0.8 * skill; math.Abs(mentor.Score-want) > 1e-9 {
+		t.Errorf("mentor score = %v, want %v after the availability weight", mentor.Score, want)
+	}
+	if !strings.HasSuffix(mentor.Explanation, "Your schedules do not overlap this week.") {
+		t.Errorf("mentor explanation = %q", mentor.Explanation)
+	}
+
+	filtered, err := svc.FindMatches(ctx, FindParams{UserID: c.requester.String(), MinOverlap: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered.Matches) != 1 || filtered.Matches[0].CandidateID != c.partner {
+		t.Errorf("filtered = %+v, want only the partner", filtered.Matches)
+	}
+}
+
+func TestRecommendations_Sessions(t *testing.T) {
+	svc, repo, _, c := newTestService(t)
+	withSchedules(svc, repo, c)
+
+	sessions, err := svc.Recommendations(context.Background(), RecommendParams{UserID: c.requester.String(), Type: RecommendSessions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("sessions = %+v, want one with the partner", sessions)
+	}
+	session := sessions[0]
+	if session.ItemID != c.partner || session.ItemType != ItemTypeSession ||
+		session.Description != "Tue 3 Mar 18:00-19:00 (Europe/Lisbon)" || !strings.HasPrefix(session.Title, "Session with ") {
+		t.Errorf("session = %+v", session)
+	}
+}
+
+func TestGenerateEmbeddings(t *testing.T) {
+	svc, _, _, c := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := svc.GenerateEmbeddings(ctx, EmbedParams{SkillNames: []string{"Go"}}); !errors.Is(err, ErrEmbeddingsDisabled) {
+		t.Errorf("without an embedder = %v", err)
+	}
+	svc.SetEmbedder(embedding.NewEmbedder(embedding.NewLocal(), nil, 0))
+
+	named, err := svc.GenerateEmbeddings(ctx, EmbedParams{SkillNames: []string{"Golang", " ", "Golang", "Go"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(named) != 2 || named[0].SkillName != "Golang" || named[0].Model != embedding.LocalModel || len(named[0].Vector) != embedding.Dimensions {
+		t.Errorf("named = %+v", named)
+	}
+
+	own, err := svc.GenerateEmbeddings(ctx, EmbedParams{UserID: c.requester.String()})
+	if err != nil || len(own) != 2 || own[0].SkillName != "Spanish" || own[1].SkillName != "Go" {
+		t.Errorf("own skills = %+v, %v", own, err)
+	}
+
+	if _, err := svc.GenerateEmbeddings(ctx, EmbedParams{}); !errors.Is(err, ErrNoSkillNames) {
+		t.Errorf("empty = %v", err)
+	}
+	many := make([]string, maxEmbedNames+1)
+	for i := range many {
+		many[i] = fmt.Sprintf("skill %d", i)
+	}
+	if _, err := svc.GenerateEmbeddings(ctx, EmbedParams{SkillNames: many}); !errors.Is(err, ErrTooManySkillNames) {
+		t.Errorf("too many = %v", err)
+	}
+}
+
+type fakeAssigner map[string]bool
+
+func (a fakeAssigner) EnabledFor(name, userID string) bool {
+	return name == ranking.Flag && a[userID]
+}
+
+func TestFindMatches_LearnedRanking(t *testing.T) {
+	svc, repo, emitter, c := newTestService(t)
+	ctx := context.Background()
+	repo.users = map[uuid.UUID]repository.User{c.mentor: {ID: c.mentor, IsVerified: true}}
+	// The model only cares whether the candidate is verified.
+	model := &ranking.Model{
+		Version:  "v3",
+		Features: []string{ranking.FeatureVerified},
+		Mean:     []float64{0},
+		Scale:    []float64{1},
+		Weights:  []float64{4},
+		Bias:     -2,
+	}
+	svc.SetRanker(model, fakeAssigner{c.requester.String(): true})
+
+	result, err := svc.FindMatches(ctx, FindParams{UserID: c.requester.String(), Algorithm: algorithms.Euclidean})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Matches) != 2 || result.Matches[0].CandidateID != c.mentor || result.Matches[1].CandidateID != c.partner {
+		t.Fatalf("treatment matches = %+v, want the verified mentor first", result.Matches)
+	}
+	if best := result.Matches[0]; best.Relevance < 0.8 || best.Variant != ranking.VariantTreatment || best.ModelVersion != "v3" {
+		t.Errorf("best = %+v", best)
+	}
+	for _, saved := range repo.saved {
+		if saved.Features == nil || saved.ModelVersion != "v3" || saved.Variant != ranking.VariantTreatment {
+			t.Errorf("saved = %+v, want features, model and arm", saved)
+		}
+	}
+	if f := repo.saved[1].Features; f[ranking.FeatureMutual] != 1 || f[ranking.FeatureScore] != 1 || f[ranking.FeaturePairs] != 0.4 {
+		t.Errorf("partner features = %v", f)
+	}
+	payload, err := emitter.events[0].MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := ontology.ConceptMatchingAlgorithmEuclidean + "/v3/treatment"; !strings.Contains(string(payload), want) {
+		t.Errorf("event %s does not name %s", payload, want)
+	}
+
+	// The control arm keeps score order but records the model it was
+	// compared against.
+	svc.SetRanker(model, fakeAssigner{})
+	repo.saved = nil
+	result, err = svc.FindMatches(ctx, FindParams{UserID: c.requester.String(), Algorithm: algorithms.Euclidean})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Matches[0].CandidateID != c.partner || result.Matches[0].Relevance != 0 {
+		t.Fatalf("control matches = %+v, want score order", result.Matches)
+	}
+	if repo.saved[0].Variant != ranking.VariantControl || repo.saved[0].ModelVersion != "v3" || repo.saved[0].Features == nil {
+		t.Errorf("control saved = %+v", repo.saved[0])
+	}
+
+	svc.SetRanker(nil, nil)
+	repo.saved = nil
+	if _, err := svc.FindMatches(ctx, FindParams{UserID: c.requester.String()}); err != nil {
+		t.Fatal(err)
+	}
+	if repo.saved[0].Variant != "" || repo.saved[0].ModelVersion != "" || repo.saved[0].Features == nil {
+		t.Errorf("unranked saved = %+v, want features only", repo.saved[0])
+	}
+}
+
+func TestRecordFeedback(t *testing.T) {
+	svc, repo, _, c := newTestService(t)
+	ctx := context.Background()
+	if _, err := svc.Score(ctx, ScoreParams{UserID: c.requester.String(), OtherID: c.partner.String()}); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := svc.RecordProfileView(ctx, c.partner.String(), c.requester.String()); err != nil || !ok {
+		t.Fatalf("RecordProfileView = %v, %v", ok, err)
+	}
+	if ok, err := svc.RecordProfileView(ctx, c.requester.String(), c.mentor.String()); err != nil || ok {
+		t.Fatalf("RecordProfileView of an unmatched pair = %v, %v", ok, err)
+	}
+	if _, err := svc.RecordProfileView(ctx, c.requester.String(), c.requester.String()); !errors.Is(err, ErrSameUser) {
+		t.Fatalf("self view = %v", err)
+	}
+
+	want := []repository.Feedback{
+		{ActorID: c.partner, OtherID: c.requester, Event: ranking.EventProfileViewed},
+	}
+	if fmt.Sprint(repo.feedback) != fmt.Sprint(want) {
+		t.Errorf("feedback = %+v, want %+v", repo.feedback, want)
+	}
+}
+
+func TestScore_Explanation(t *testing.T) {
+	svc, repo, emitter, c := newTestService(t)
+	repo.users = map[uuid.UUID]repository.User{
+		c.requester: {ID: c.requester, Languages: []string{"pt-PT", "fr"}},
+		c.mentor:    {ID: c.mentor, AverageRating: 4.5, TotalReviews: 12, Languages: []string{"en", "pt-BR"}},
+	}
+	repo.distances = map[[2]uuid.UUID]float64{{c.requester, c.mentor}: 12.44}
+
+	match, err := svc.Score(context.Background(), ScoreParams{UserID: c.requester.String(), OtherID: c.mentor.String(), Algorithm: algorithms.Euclidean})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The mentor teaches Go at 5 where 7 is wanted: the pair would score 1
+	// at the wanted level and the gap takes 0.2 back.
+	want := []Factor{
+		{Kind: FactorComplementarity, Contribution: 1, Skill: "Go", Offered: 5, Wanted: 7},
+		{Kind: FactorProficiencyGap, Contribution: -0.2, Skill: "Go", Offered: 5, Wanted: 7},
+		{Kind: FactorRating, Rating: 4.5, Reviews: 12},
+		{Kind: FactorDistance, DistanceKm: 12.4},
+		{Kind: FactorLanguage, Languages: []string{"pt"}},
+	}
+	var total float64
+	for i, f := range match.Reasons.Factors {
+		total += f.Contribution
+		if i < len(want) && math.Abs(f.Contribution-want[i].Contribution) < 1e-9 {
+			f.Contribution = want[i].Contribution
+		}
+		if i >= len(want) || fmt.Sprint(f) != fmt.Sprint(want[i]) {
+			t.Errorf("factor %d = %+v", i, f)
+		}
+	}
+	if math.Abs(total-match.Score) > 1e-9 || math.Abs(match.Reasons.Score-0.8) > 1e-9 {
+		t.Errorf("contributions add up to %v, score %v", total, match.Score)
+	}
+
+	if want := "They can teach you Go (5/10, you want 7/10). They are rated 4.5/5 from 12 reviews. " +
+		"They are 12 km away. You both speak Portuguese."; match.Explanation != want {
+		t.Errorf("explanation = %q, want %q", match.Explanation, want)
+	}
+	if want := "Pode ensinar-te Go (5/10, queres 7/10). Tem uma avaliação de 4,5/5 (12 avaliações). " +
+		"Está a 12 km. Ambos falam português."; match.Reasons.Text(Locale("pt-BR,pt;q=0.9,en;q=0.8")) != want {
+		t.Errorf("pt explanation = %q, want %q", match.Reasons.Text(Locale("pt-BR")), want)
+	}
+
+	raw, _ := emitter.events[0].Properties()[ontology.PropExplanation].(string)
+	var emitted struct {
+		Text    string   `json:"text"`
+		Factors []Factor `json:"factors"`
+	}
+	if err := json.Unmarshal([]byte(raw), &emitted); err != nil || emitted.Text != match.Explanation || len(emitted.Factors) != len(want) {
+		t.Errorf("sk:explanation = %s (%v)", raw, err)
+	}
+	explanations, err := svc.MatchExplanations(context.Background(), c.requester.String(), []string{c.mentor.String(), c.partner.String()})
+	if err != nil || len(explanations) != 1 || explanations[c.mentor].Text(DefaultLocale) != match.Explanation {
+		t.Errorf("MatchExplanations = %+v, %v", explanations, err)
+	}
+	if _, err := svc.MatchExplanations(context.Background(), c.requester.String(), []string{"go"}); !errors.Is(err, ErrInvalidUserID) {
+		t.Errorf("MatchExplanations of a bad id = %v", err)
+	}
+
+	validator, err := ontology.DefaultValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := emitter.events[0].MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report, err := validator.ValidateDocument(payload); err != nil || !report.Conforms {
+		t.Errorf("event does not conform: %+v, %v", report, err)
+	}
+}
+
+func TestLocale(t *testing.T) {
+	for header, want := range map[string]string{
+		"":                      "en",
+		"pt-PT,pt;q=0.9":        "pt",
+		"de-DE,pt;q=0.5":        "pt",
+		"fr-FR,fr;q=0.9":        "en",
+		"en-GB,en;q=0.9,pt;q=1": "pt",
+		"not a language":        "en",
+	} {
+		if got := Locale(header).String(); got != want {
+			t.Errorf("Locale(%q) = %s, want %s", header, got, want)
+		}
+	}
+}
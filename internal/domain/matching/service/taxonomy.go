@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	taxonomyrepo "github.com/FACorreiaa/skillsphere-api/internal/domain/taxonomy/repository"
+)
+
+// ErrUnknownSkillLabel is returned when a skill filter names no skill.
+var ErrUnknownSkillLabel = errors.New("skill label names no skill")
+
+// SkillExpander widens skills along the skill taxonomy;
+// *taxonomyservice.Service implements it.
+type SkillExpander interface {
+	Expand(ctx context.Context, skillIDs []uuid.UUID, opts taxonomyrepo.ExpandOptions) ([]taxonomyrepo.Expansion, error)
+	ExpandTerm(ctx context.Context, term string, opts taxonomyrepo.ExpandOptions) ([]taxonomyrepo.Expansion, error)
+}
+
+// skillFilterExpansion admits narrower skills, so a filter on "Programming"
+// keeps candidates who offer Go.
+var skillFilterExpansion = taxonomyrepo.ExpandOptions{Narrower: true}
+
+// SetSkillExpander makes skill filters admit narrower skills, skills merged
+// into the filtered ones and skills named by an alternative label. It is not
+// safe to call while requests are served.
+func (s *Service) SetSkillExpander(expander SkillExpander) {
+	s.skills = expander
+}
+
+// filterSkills returns the skills a filter on ids and labels admits. Without
+// an expander only ids are admitted and labels cannot be resolved.
+func (s *Service) filterSkills(ctx context.Context, ids []uuid.UUID, labels []string) ([]uuid.UUID, error) {
+	if s.skills == nil {
+		if len(labels) > 0 {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownSkillLabel, labels[0])
+		}
+		return ids, nil
+	}
+
+	admitted := append([]uuid.UUID(nil), ids...)
+	if len(ids) > 0 {
+		expansions, err := s.skills.Expand(ctx, ids, skillFilterExpansion)
+		if err != nil {
+			return nil, fmt.Errorf("expand skill filter: %w", err)
+		}
+		admitted = union(admitted, skillIDs(expansions))
+	}
+	for _, label := range labels {
+		expansions, err := s.skills.ExpandTerm(ctx, label, skillFilterExpansion)
+		if err != nil {
+			return nil, fmt.Errorf("expand skill filter %q: %w", label, err)
+		}
+		if len(expansions) == 0 {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownSkillLabel, label)
+		}
+		admitted = union(admitted, skillIDs(expansions))
+	}
+	return admitted, nil
+}
+
+func skillIDs(expansions []taxonomyrepo.Expansion) []uuid.UUID {
+	ids := make([]uuid.UUID, len(expansions))
+	for i, expansion := range expansions {
+		ids[i] = expansion.SkillID
+	}
+	return ids
+}
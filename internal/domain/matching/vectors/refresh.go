@@ -0,0 +1,110 @@
+package vectors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/observability"
+)
+
+const defaultRefreshBatchSize = 500
+
+// Refresher rebuilds the vectors of users queued by user_skills writes.
+type Refresher struct {
+	db        *sql.DB
+	batchSize int
+	logger    *slog.Logger
+}
+
+// NewRefresher builds a refresher that rebuilds up to batchSize users per
+// statement; a non-positive batchSize uses the default.
+func NewRefresher(db *sql.DB, batchSize int, logger *slog.Logger) *Refresher {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	if batchSize <= 0 {
+		batchSize = defaultRefreshBatchSize
+	}
+	return &Refresher{db: db, batchSize: batchSize, logger: logger}
+}
+
+// Run drains the queue once per interval until ctx is cancelled.
+func (r *Refresher) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := r.Refresh(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("skill vector refresh failed", "error", err)
+		} else if n > 0 {
+			r.logger.Debug("skill vectors refreshed", "users", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh rebuilds every queued user in batches and reports how many were
+// rebuilt. Each batch claims, rebuilds and dequeues its users in one
+// statement, so a failed batch stays queued.
+func (r *Refresher) Refresh(ctx context.Context) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, errors.New("skill vector refresher uninitialized")
+	}
+
+	var total int64
+	for {
+		var n int64
+		if err := r.db.QueryRowContext(ctx, refreshSkillVectorsStmt, r.batchSize).Scan(&n); err != nil {
+			return total, fmt.Errorf("refresh skill vectors: %w", err)
+		}
+		total += n
+		observability.SkillVectorsRefreshedTotal.Add(float64(n))
+		if n < int64(r.batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// refreshSkillVectorsStmt skips queue rows locked by writers that have not
+// committed yet; their users are rebuilt on a later pass, once the write is
+// visible. Users left without skills lose their row.
+const refreshSkillVectorsStmt = `
+    -- name: RefreshUserSkillVectors
+    WITH claimed AS (
+        DELETE FROM user_skill_vector_queue
+        WHERE user_id IN (
+            SELECT user_id FROM user_skill_vector_queue
+            ORDER BY enqueued_at
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED)
+        RETURNING user_id
+    ), built AS (
+        SELECT c.user_id,
+               COALESCE(jsonb_object_agg(us.skill_id::text, COALESCE(us.proficiency, 0))
+                   FILTER (WHERE us.skill_type = 'offered'), '{}'::jsonb) AS offered,
+               COALESCE(jsonb_object_agg(us.skill_id::text, COALESCE(us.proficiency, 0))
+                   FILTER (WHERE us.skill_type = 'wanted'), '{}'::jsonb) AS wanted
+        FROM claimed c
+        LEFT JOIN user_skills us ON us.user_id = c.user_id
+        GROUP BY c.user_id
+    ), removed AS (
+        DELETE FROM user_skill_vectors v
+        USING built b
+        WHERE v.user_id = b.user_id AND b.offered = '{}'::jsonb AND b.wanted = '{}'::jsonb
+    ), upserted AS (
+        INSERT INTO user_skill_vectors (user_id, offered, wanted, refreshed_at)
+        SELECT user_id, offered, wanted, NOW() FROM built
+        WHERE offered <> '{}'::jsonb OR wanted <> '{}'::jsonb
+        ON CONFLICT (user_id) DO UPDATE
+        SET offered = EXCLUDED.offered, wanted = EXCLUDED.wanted, refreshed_at = EXCLUDED.refreshed_at
+    )
+    SELECT COUNT(*) FROM claimed`
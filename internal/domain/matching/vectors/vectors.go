@@ -0,0 +1,105 @@
+// Package vectors maintains user_skill_vectors, each user's offered and wanted
+// skills as sparse vectors keyed by skill ID, and loads them for the matcher.
+//
+// Writes to user_skills queue the user in user_skill_vector_queue; a Refresher
+// drains the queue and rebuilds only the queued users' rows.
+package vectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/algorithms"
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+)
+
+// Vector maps skill IDs to proficiency, 1-10, or 0 when the user did not set
+// one. It is stored as a JSON object keyed by skill ID, so the index of every
+// value is explicit and vectors of different users compare directly.
+type Vector map[uuid.UUID]int
+
+// Skills returns the vector as matcher skills in skill ID order, with unset
+// levels at algorithms.DefaultProficiency. Names and embeddings are left
+// empty; the ID is all Euclidean and cosine scoring need.
+func (v Vector) Skills() []algorithms.Skill {
+	skills := make([]algorithms.Skill, 0, len(v))
+	for id, proficiency := range v {
+		if proficiency == 0 {
+			proficiency = algorithms.DefaultProficiency
+		}
+		skills = append(skills, algorithms.Skill{ID: id, Proficiency: proficiency})
+	}
+	slices.SortFunc(skills, func(a, b algorithms.Skill) int { return slices.Compare(a.ID[:], b.ID[:]) })
+	return skills
+}
+
+// UserVectors is a user's row in user_skill_vectors.
+type UserVectors struct {
+	UserID      uuid.UUID
+	Offered     Vector
+	Wanted      Vector
+	RefreshedAt time.Time
+}
+
+// Profile converts the vectors into the profile the matching algorithms score.
+func (u UserVectors) Profile() algorithms.Profile {
+	return algorithms.Profile{UserID: u.UserID, Offered: u.Offered.Skills(), Wanted: u.Wanted.Skills()}
+}
+
+// Store reads user_skill_vectors.
+type Store struct {
+	db *db.SQL
+}
+
+// NewStore constructs a Store that reads from replicas; see db.SQL.
+func NewStore(db *db.SQL) *Store {
+	return &Store{db: db}
+}
+
+// Get returns the vectors of the users in userIDs that list any skill. Rows
+// may trail user_skills by up to one refresh interval.
+func (s *Store) Get(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]UserVectors, error) {
+	out := make(map[uuid.UUID]UserVectors, len(userIDs))
+	if len(userIDs) == 0 {
+		return out, nil
+	}
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		-- name: GetUserSkillVectors
+		SELECT user_id, offered, wanted, refreshed_at
+		FROM user_skill_vectors
+		WHERE user_id = ANY($1::uuid[])
+	`
+	rows, err := s.db.Read(ctx).QueryContext(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("load skill vectors: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			row             UserVectors
+			offered, wanted []byte
+		)
+		if err := rows.Scan(&row.UserID, &offered, &wanted, &row.RefreshedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(offered, &row.Offered); err != nil {
+			return nil, fmt.Errorf("decode offered vector of %s: %w", row.UserID, err)
+		}
+		if err := json.Unmarshal(wanted, &row.Wanted); err != nil {
+			return nil, fmt.Errorf("decode wanted vector of %s: %w", row.UserID, err)
+		}
+		out[row.UserID] = row
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,65 @@
+package vectors
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/algorithms"
+)
+
+func TestVector_JSONIsKeyedBySkillID(t *testing.T) {
+	goSkill, spanish := uuid.New(), uuid.New()
+	data := []byte(`{"` + goSkill.String() + `": 7, "` + spanish.String() + `": 0}`)
+
+	var v Vector
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	if len(v) != 2 || v[goSkill] != 7 || v[spanish] != 0 {
+		t.Errorf("vector = %v", v)
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Vector
+	if err := json.Unmarshal(encoded, &back); err != nil || back[goSkill] != 7 {
+		t.Errorf("round trip = %v, %v", back, err)
+	}
+}
+
+func TestProfile_ScoresLikeTheFullProfile(t *testing.T) {
+	goSkill, spanish, guitar := uuid.New(), uuid.New(), uuid.New()
+	learner := UserVectors{UserID: uuid.New(), Offered: Vector{spanish: 8}, Wanted: Vector{goSkill: 7, guitar: 0}}
+	teacher := UserVectors{UserID: uuid.New(), Offered: Vector{goSkill: 9, guitar: 3}, Wanted: Vector{spanish: 4}}
+
+	named := func(u UserVectors) algorithms.Profile {
+		p := u.Profile()
+		for _, side := range [][]algorithms.Skill{p.Offered, p.Wanted} {
+			for i := range side {
+				side[i].Name = side[i].ID.String()
+			}
+		}
+		return p
+	}
+
+	for _, kind := range []algorithms.Kind{algorithms.Euclidean, algorithms.Cosine} {
+		alg, err := algorithms.New(kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromVectors := algorithms.Match(alg, learner.Profile(), teacher.Profile())
+		full := algorithms.Match(alg, named(learner), named(teacher))
+		if fromVectors.Score != full.Score || fromVectors.Score <= 0 || !fromVectors.Mutual {
+			t.Errorf("%s: vectors score %v, full profile %v", kind, fromVectors.Score, full.Score)
+		}
+	}
+
+	skills := learner.Wanted.Skills()
+	if len(skills) != 2 || string(skills[0].ID[:]) > string(skills[1].ID[:]) {
+		t.Errorf("skills = %+v, want both in ID order", skills)
+	}
+}
@@ -0,0 +1,193 @@
+// Package repository stores skill exchange sessions and their ratings on
+// Postgres for the session service.
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/session/service"
+	"github.com/FACorreiaa/skillsphere-api/internal/ontology"
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+)
+
+// statuses maps SessionStatus concepts to the session_status labels written
+// for them; statusConcepts maps every label back. Pending and confirmed
+// sessions are both scheduled, and disputed ones have no concept.
+var (
+	statuses = map[string]string{
+		ontology.ConceptSessionStatusScheduled:  "pending",
+		ontology.ConceptSessionStatusInProgress: "in_progress",
+		ontology.ConceptSessionStatusCompleted:  "completed",
+		ontology.ConceptSessionStatusCancelled:  "cancelled",
+		ontology.ConceptSessionStatusNoShow:     "no_show",
+	}
+	statusConcepts = map[string]string{
+		"pending":     ontology.ConceptSessionStatusScheduled,
+		"confirmed":   ontology.ConceptSessionStatusScheduled,
+		"in_progress": ontology.ConceptSessionStatusInProgress,
+		"completed":   ontology.ConceptSessionStatusCompleted,
+		"cancelled":   ontology.ConceptSessionStatusCancelled,
+		"no_show":     ontology.ConceptSessionStatusNoShow,
+	}
+)
+
+// PostgresSessionRepository implements service.Repository on Postgres.
+type PostgresSessionRepository struct {
+	db *db.SQL
+}
+
+var _ service.Repository = (*PostgresSessionRepository)(nil)
+
+// NewPostgresSessionRepository constructs the repository. Reads go to
+// replicas; see db.SQL.
+func NewPostgresSessionRepository(db *db.SQL) *PostgresSessionRepository {
+	return &PostgresSessionRepository{db: db}
+}
+
+const sessionColumns = `id, initiator_id, partner_id, to_json(initiator_offers), to_json(partner_offers),
+		scheduled_start, scheduled_end, actual_start, actual_end, COALESCE(meeting_url, ''),
+		COALESCE(notes, ''), status::text, COALESCE(cancellation_reason, ''), created_at, is_premium`
+
+// CreateSession inserts a pending session.
+func (r *PostgresSessionRepository) CreateSession(ctx context.Context, session service.Session) (*service.Session, error) {
+	query := `
+		-- name: CreateSession
+		INSERT INTO sessions (initiator_id, partner_id, initiator_offers, partner_offers,
+			scheduled_start, scheduled_end, meeting_url, notes, is_premium)
+		VALUES ($1, $2, $3::text[], $4::text[], $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9)
+		RETURNING ` + sessionColumns
+	row := r.db.Write(ctx).QueryRowContext(ctx, query, session.InitiatorID, session.PartnerID,
+		nonNil(session.InitiatorOffers), nonNil(session.PartnerOffers), session.ScheduledStart, session.ScheduledEnd,
+		session.MeetingURL, session.Notes, session.IsPremium)
+	saved, err := scanSession(row)
+	if err != nil {
+		return nil, fmt.Errorf("create session: %w", err)
+	}
+	return saved, nil
+}
+
+// GetSession reads the session.
+func (r *PostgresSessionRepository) GetSession(ctx context.Context, id uuid.UUID) (*service.Session, error) {
+	query := `
+		-- name: GetSession
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE id = $1
+	`
+	session, err := scanSession(r.db.Read(ctx).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, service.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+	return session, nil
+}
+
+// UpdateSessionStatus only updates a session still in one of change.From.
+func (r *PostgresSessionRepository) UpdateSessionStatus(ctx context.Context, id uuid.UUID, change service.StatusChange) (*service.Session, error) {
+	status, ok := statuses[change.StatusIRI]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown status %q", service.ErrInvalidTransition, change.StatusIRI)
+	}
+	var from []string
+	for label, concept := range statusConcepts {
+		for _, allowed := range change.From {
+			if concept == allowed {
+				from = append(from, label)
+			}
+		}
+	}
+
+	query := `
+		-- name: UpdateSessionStatus
+		UPDATE sessions
+		SET status = $2::session_status,
+			actual_start = CASE WHEN $2 = 'in_progress' THEN $3 ELSE actual_start END,
+			actual_end = CASE WHEN $2 = 'completed' THEN $3 ELSE actual_end END,
+			cancellation_reason = CASE WHEN $2 = 'cancelled' THEN NULLIF($4, '') ELSE cancellation_reason END,
+			updated_at = NOW()
+		WHERE id = $1 AND status::text = ANY($5::text[])
+		RETURNING ` + sessionColumns
+	session, err := scanSession(r.db.Write(ctx).QueryRowContext(ctx, query, id, status, change.At, change.Reason, nonNil(from)))
+	if errors.Is(err, sql.ErrNoRows) {
+		// Either the session is gone or it moved on since it was read.
+		if _, err := r.GetSession(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, service.ErrInvalidTransition
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update session status: %w", err)
+	}
+	return session, nil
+}
+
+// SaveRating inserts into session_ratings, whose trigger records the rating
+// as match feedback.
+func (r *PostgresSessionRepository) SaveRating(ctx context.Context, rating service.Rating) (*service.Rating, error) {
+	query := `
+		-- name: SaveSessionRating
+		INSERT INTO session_ratings (session_id, reviewer_id, reviewee_id, score, comment)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		RETURNING id, created_at
+	`
+	saved := rating
+	err := r.db.Write(ctx).QueryRowContext(ctx, query, rating.SessionID, rating.ReviewerID, rating.RevieweeID, rating.Score, rating.Comment).
+		Scan(&saved.ID, &saved.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return nil, service.ErrAlreadyRated
+	}
+	if err != nil {
+		return nil, fmt.Errorf("save session rating: %w", err)
+	}
+	return &saved, nil
+}
+
+func scanSession(row *sql.Row) (*service.Session, error) {
+	var (
+		session                        service.Session
+		initiatorOffers, partnerOffers []byte
+		actualStart, actualEnd         sql.NullTime
+		status                         string
+	)
+	if err := row.Scan(&session.ID, &session.InitiatorID, &session.PartnerID, &initiatorOffers, &partnerOffers,
+		&session.ScheduledStart, &session.ScheduledEnd, &actualStart, &actualEnd, &session.MeetingURL,
+		&session.Notes, &status, &session.CancellationReason, &session.CreatedAt, &session.IsPremium); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(initiatorOffers, &session.InitiatorOffers); err != nil {
+		return nil, fmt.Errorf("decode initiator_offers: %w", err)
+	}
+	if err := json.Unmarshal(partnerOffers, &session.PartnerOffers); err != nil {
+		return nil, fmt.Errorf("decode partner_offers: %w", err)
+	}
+	session.ActualStart = timeOrZero(actualStart)
+	session.ActualEnd = timeOrZero(actualEnd)
+	session.StatusIRI = statusConcepts[status]
+	return &session, nil
+}
+
+func timeOrZero(t sql.NullTime) time.Time {
+	if t.Valid {
+		return t.Time
+	}
+	return time.Time{}
+}
+
+// nonNil keeps NOT NULL array columns from receiving NULL.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,25 +11,83 @@ import (
 	"github.com/FACorreiaa/skillsphere-api/internal/ontology"
 )
 
+var (
+	// ErrInvalidScore is returned when a rating is outside 1-5.
+	ErrInvalidScore = errors.New("rating score must be between 1 and 5")
+	// ErrSessionNotFound is returned by Repository.GetSession.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrNotParticipant is returned when the actor or reviewer is neither
+	// the initiator nor the partner of the session.
+	ErrNotParticipant = errors.New("user is not a participant of the session")
+	// ErrInvalidTransition is returned when the session's status does not
+	// allow the change, such as starting a cancelled session or rating one
+	// that has not completed.
+	ErrInvalidTransition = errors.New("session status does not allow this change")
+	// ErrAlreadyRated is returned by Repository.SaveRating when the reviewer
+	// rated the session before.
+	ErrAlreadyRated = errors.New("session already rated by this participant")
+)
+
+// transitions lists the statuses a session may move into each status from.
+// Completed, cancelled and no-show sessions are final.
+var transitions = map[string][]string{
+	ontology.ConceptSessionStatusInProgress: {ontology.ConceptSessionStatusScheduled},
+	ontology.ConceptSessionStatusCompleted:  {ontology.ConceptSessionStatusScheduled, ontology.ConceptSessionStatusInProgress},
+	ontology.ConceptSessionStatusCancelled:  {ontology.ConceptSessionStatusScheduled},
+	ontology.ConceptSessionStatusNoShow:     {ontology.ConceptSessionStatusScheduled, ontology.ConceptSessionStatusInProgress},
+}
+
 // Repository defines the persistence operations required by the service.
 type Repository interface {
 	CreateSession(ctx context.Context, session Session) (*Session, error)
+	// GetSession returns the session, or ErrSessionNotFound.
+	GetSession(ctx context.Context, id uuid.UUID) (*Session, error)
+	// UpdateSessionStatus applies change and returns the updated session. It
+	// sets ActualStart when the session starts, ActualEnd when it completes
+	// and CancellationReason when it is cancelled. It returns
+	// ErrInvalidTransition when the session is no longer in one of
+	// change.From, so concurrent changes cannot both apply.
+	UpdateSessionStatus(ctx context.Context, id uuid.UUID, change StatusChange) (*Session, error)
+	SaveRating(ctx context.Context, rating Rating) (*Rating, error)
 }
 
 // Session models the persisted session.
 type Session struct {
-	ID              uuid.UUID
-	InitiatorID     uuid.UUID
-	PartnerID       uuid.UUID
-	InitiatorOffers []string
-	PartnerOffers   []string
-	ScheduledStart  time.Time
-	ScheduledEnd    time.Time
-	MeetingURL      string
-	Notes           string
-	StatusIRI       string
-	CreatedAt       time.Time
-	IsPremium       bool
+	ID                 uuid.UUID
+	InitiatorID        uuid.UUID
+	PartnerID          uuid.UUID
+	InitiatorOffers    []string
+	PartnerOffers      []string
+	ScheduledStart     time.Time
+	ScheduledEnd       time.Time
+	ActualStart        time.Time
+	ActualEnd          time.Time
+	MeetingURL         string
+	Notes              string
+	StatusIRI          string
+	CancellationReason string
+	CreatedAt          time.Time
+	IsPremium          bool
+}
+
+// StatusChange moves a session into a SessionStatus concept.
+type StatusChange struct {
+	StatusIRI string
+	// From are the statuses the session must be in for the change to apply.
+	From   []string
+	At     time.Time
+	Reason string
+}
+
+// Rating is the score one participant gives the other after a session.
+type Rating struct {
+	ID         uuid.UUID
+	SessionID  uuid.UUID
+	ReviewerID uuid.UUID
+	RevieweeID uuid.UUID
+	Score      int
+	Comment    string
+	CreatedAt  time.Time
 }
 
 // Service coordinates session orchestration and ontology emission.
@@ -51,20 +111,107 @@ func (s *Service) CreateSession(ctx context.Context, session Session) (*Session,
 		return nil, err
 	}
 
-	event := ontology.NewSessionScheduledEvent(ontology.SessionEvent{
-		SessionID:       saved.ID,
-		InitiatorID:     saved.InitiatorID,
-		PartnerID:       saved.PartnerID,
-		InitiatorOffers: saved.InitiatorOffers,
-		PartnerOffers:   saved.PartnerOffers,
-		ScheduledStart:  saved.ScheduledStart,
-		ScheduledEnd:    saved.ScheduledEnd,
-		MeetingURL:      saved.MeetingURL,
-		StatusIRI:       saved.StatusIRI,
-		IsPremium:       saved.IsPremium,
-		Notes:           saved.Notes,
-	})
+	event := ontology.NewSessionScheduledEvent(sessionEvent(saved))
 	_ = s.emitter.Emit(ctx, event)
 
 	return saved, nil
 }
+
+// StartSession marks the session as in progress.
+func (s *Service) StartSession(ctx context.Context, id, actor uuid.UUID) (*Session, error) {
+	return s.transition(ctx, id, actor, StatusChange{StatusIRI: ontology.ConceptSessionStatusInProgress, At: time.Now()})
+}
+
+// CompleteSession marks the session as completed.
+func (s *Service) CompleteSession(ctx context.Context, id, actor uuid.UUID) (*Session, error) {
+	return s.transition(ctx, id, actor, StatusChange{StatusIRI: ontology.ConceptSessionStatusCompleted, At: time.Now()})
+}
+
+// CancelSession cancels the session on behalf of actor.
+func (s *Service) CancelSession(ctx context.Context, id, actor uuid.UUID, reason string) (*Session, error) {
+	return s.transition(ctx, id, actor, StatusChange{StatusIRI: ontology.ConceptSessionStatusCancelled, At: time.Now(), Reason: reason})
+}
+
+// MarkNoShow records that a participant did not attend; actor is the
+// participant reporting it.
+func (s *Service) MarkNoShow(ctx context.Context, id, actor uuid.UUID) (*Session, error) {
+	return s.transition(ctx, id, actor, StatusChange{StatusIRI: ontology.ConceptSessionStatusNoShow, At: time.Now()})
+}
+
+// RateSession stores a participant's rating of a completed session and emits
+// it as a SessionRated activity. The reviewee is the other participant.
+func (s *Service) RateSession(ctx context.Context, rating Rating) (*Rating, error) {
+	if rating.Score < 1 || rating.Score > 5 {
+		return nil, ErrInvalidScore
+	}
+	session, err := s.repo.GetSession(ctx, rating.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	switch rating.ReviewerID {
+	case session.InitiatorID:
+		rating.RevieweeID = session.PartnerID
+	case session.PartnerID:
+		rating.RevieweeID = session.InitiatorID
+	default:
+		return nil, ErrNotParticipant
+	}
+	if session.StatusIRI != ontology.ConceptSessionStatusCompleted {
+		return nil, ErrInvalidTransition
+	}
+	saved, err := s.repo.SaveRating(ctx, rating)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.emitter.Emit(ctx, ontology.NewSessionRatedEvent(ontology.RatingEvent{
+		RatingID:   saved.ID,
+		SessionID:  saved.SessionID,
+		ReviewerID: saved.ReviewerID,
+		RevieweeID: saved.RevieweeID,
+		Score:      saved.Score,
+		Comment:    saved.Comment,
+		CreatedAt:  saved.CreatedAt,
+	}))
+	return saved, nil
+}
+
+// transition applies change when actor takes part in the session and its
+// current status allows it.
+func (s *Service) transition(ctx context.Context, id, actor uuid.UUID, change StatusChange) (*Session, error) {
+	session, err := s.repo.GetSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if actor != session.InitiatorID && actor != session.PartnerID {
+		return nil, ErrNotParticipant
+	}
+	change.From = transitions[change.StatusIRI]
+	if !slices.Contains(change.From, session.StatusIRI) {
+		return nil, ErrInvalidTransition
+	}
+	saved, err := s.repo.UpdateSessionStatus(ctx, id, change)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.emitter.Emit(ctx, ontology.NewSessionStatusEvent(sessionEvent(saved), actor))
+	return saved, nil
+}
+
+func sessionEvent(session *Session) ontology.SessionEvent {
+	return ontology.SessionEvent{
+		SessionID:          session.ID,
+		InitiatorID:        session.InitiatorID,
+		PartnerID:          session.PartnerID,
+		InitiatorOffers:    session.InitiatorOffers,
+		PartnerOffers:      session.PartnerOffers,
+		ScheduledStart:     session.ScheduledStart,
+		ScheduledEnd:       session.ScheduledEnd,
+		ActualStart:        session.ActualStart,
+		ActualEnd:          session.ActualEnd,
+		MeetingURL:         session.MeetingURL,
+		StatusIRI:          session.StatusIRI,
+		IsPremium:          session.IsPremium,
+		Notes:              session.Notes,
+		CancellationReason: session.CancellationReason,
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/ontology"
+)
+
+type fakeSessionRepo struct {
+	sessions map[uuid.UUID]Session
+	ratings  []Rating
+}
+
+func (r *fakeSessionRepo) CreateSession(_ context.Context, session Session) (*Session, error) {
+	session.ID = uuid.New()
+	session.StatusIRI = ontology.ConceptSessionStatusScheduled
+	r.sessions[session.ID] = session
+	return &session, nil
+}
+
+func (r *fakeSessionRepo) GetSession(_ context.Context, id uuid.UUID) (*Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return &session, nil
+}
+
+func (r *fakeSessionRepo) UpdateSessionStatus(_ context.Context, id uuid.UUID, change StatusChange) (*Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if !slices.Contains(change.From, session.StatusIRI) {
+		return nil, ErrInvalidTransition
+	}
+	session.StatusIRI = change.StatusIRI
+	session.CancellationReason = change.Reason
+	r.sessions[id] = session
+	return &session, nil
+}
+
+func (r *fakeSessionRepo) SaveRating(_ context.Context, rating Rating) (*Rating, error) {
+	rating.ID = uuid.New()
+	rating.CreatedAt = time.Now()
+	r.ratings = append(r.ratings, rating)
+	return &rating, nil
+}
+
+type countingEmitter struct {
+	events int
+}
+
+func (e *countingEmitter) Emit(context.Context, ontology.Event) error {
+	e.events++
+	return nil
+}
+
+func newTestSession(t *testing.T) (*Service, *fakeSessionRepo, *countingEmitter, *Session) {
+	t.Helper()
+	repo := &fakeSessionRepo{sessions: map[uuid.UUID]Session{}}
+	emitter := &countingEmitter{}
+	svc := NewService(repo, emitter)
+	session, err := svc.CreateSession(context.Background(), Session{InitiatorID: uuid.New(), PartnerID: uuid.New()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc, repo, emitter, session
+}
+
+func TestTransitions(t *testing.T) {
+	svc, _, emitter, session := newTestSession(t)
+	ctx := context.Background()
+	actor := session.InitiatorID
+
+	if _, err := svc.StartSession(ctx, session.ID, uuid.New()); !errors.Is(err, ErrNotParticipant) {
+		t.Errorf("start by an outsider = %v, want ErrNotParticipant", err)
+	}
+	if _, err := svc.StartSession(ctx, session.ID, actor); err != nil {
+		t.Fatalf("start = %v", err)
+	}
+	if _, err := svc.StartSession(ctx, session.ID, actor); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("starting twice = %v, want ErrInvalidTransition", err)
+	}
+	if _, err := svc.CancelSession(ctx, session.ID, actor, "late"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("cancelling a started session = %v, want ErrInvalidTransition", err)
+	}
+	completed, err := svc.CompleteSession(ctx, session.ID, session.PartnerID)
+	if err != nil || completed.StatusIRI != ontology.ConceptSessionStatusCompleted {
+		t.Fatalf("complete = %+v, %v", completed, err)
+	}
+	for name, apply := range map[string]func() (*Session, error){
+		"complete": func() (*Session, error) { return svc.CompleteSession(ctx, session.ID, actor) },
+		"start":    func() (*Session, error) { return svc.StartSession(ctx, session.ID, actor) },
+		"no-show":  func() (*Session, error) { return svc.MarkNoShow(ctx, session.ID, actor) },
+	} {
+		if _, err := apply(); !errors.Is(err, ErrInvalidTransition) {
+			t.Errorf("%s after completion = %v, want ErrInvalidTransition", name, err)
+		}
+	}
+	if _, err := svc.StartSession(ctx, uuid.New(), actor); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("unknown session = %v", err)
+	}
+	// Scheduled, started and completed; rejected changes emit nothing.
+	if emitter.events != 3 {
+		t.Errorf("emitted %d events, want 3", emitter.events)
+	}
+}
+
+func TestCancelledSessionIsFinal(t *testing.T) {
+	svc, _, _, session := newTestSession(t)
+	ctx := context.Background()
+
+	cancelled, err := svc.CancelSession(ctx, session.ID, session.PartnerID, "ill")
+	if err != nil || cancelled.CancellationReason != "ill" {
+		t.Fatalf("cancel = %+v, %v", cancelled, err)
+	}
+	if _, err := svc.StartSession(ctx, session.ID, session.InitiatorID); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("start after cancel = %v, want ErrInvalidTransition", err)
+	}
+	if _, err := svc.CompleteSession(ctx, session.ID, session.InitiatorID); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("complete after cancel = %v, want ErrInvalidTransition", err)
+	}
+}
+
+func TestRateSession(t *testing.T) {
+	svc, repo, _, session := newTestSession(t)
+	ctx := context.Background()
+	rating := Rating{SessionID: session.ID, ReviewerID: session.InitiatorID, Score: 4}
+
+	if _, err := svc.RateSession(ctx, rating); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("rating a scheduled session = %v, want ErrInvalidTransition", err)
+	}
+	if _, err := svc.CompleteSession(ctx, session.ID, session.InitiatorID); err != nil {
+		t.Fatal(err)
+	}
+	outsider := rating
+	outsider.ReviewerID = uuid.New()
+	if _, err := svc.RateSession(ctx, outsider); !errors.Is(err, ErrNotParticipant) {
+		t.Errorf("rating by an outsider = %v, want ErrNotParticipant", err)
+	}
+	saved, err := svc.RateSession(ctx, rating)
+	if err != nil || saved.RevieweeID != session.PartnerID || len(repo.ratings) != 1 {
+		t.Errorf("rate = %+v, %v", saved, err)
+	}
+}
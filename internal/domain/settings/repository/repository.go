@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+const auditActionUpdateSettings = "platform_settings.update"
+
+// Change is a before/after pair recorded in the audit log.
+type Change struct {
+	Old *string `json:"old"`
+	New *string `json:"new"`
+}
+
+// UpdateParams describes a batch of setting writes made by an admin.
+type UpdateParams struct {
+	AdminID  uuid.UUID
+	ClientIP string
+	// Values are upserted; keys in Delete are removed.
+	Values map[string]string
+	Delete []string
+}
+
+// SettingsRepository persists platform settings.
+type SettingsRepository interface {
+	ListSettings(ctx context.Context) (map[string]string, error)
+	// UpdateSettings applies params atomically and records the effective changes in
+	// audit_logs. Unchanged keys are skipped; the applied changes are returned.
+	UpdateSettings(ctx context.Context, params UpdateParams) (map[string]Change, error)
+}
+
+// PostgresSettingsRepository stores settings in platform_settings.
+type PostgresSettingsRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresSettingsRepository creates a new settings repository
+func NewPostgresSettingsRepository(db *sql.DB) *PostgresSettingsRepository {
+	return &PostgresSettingsRepository{db: db}
+}
+
+// ListSettings returns every stored key and value.
+func (r *PostgresSettingsRepository) ListSettings(ctx context.Context) (map[string]string, error) {
+	query := `
+		-- name: ListPlatformSettings
+		SELECT key, value
+		FROM platform_settings
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, rows.Err()
+}
+
+// UpdateSettings implements SettingsRepository.
+func (r *PostgresSettingsRepository) UpdateSettings(ctx context.Context, params UpdateParams) (map[string]Change, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	lockQuery := `
+		-- name: LockPlatformSettings
+		SELECT key, value
+		FROM platform_settings
+		FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, lockQuery)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		current[key] = value
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]Change)
+
+	upsertQuery := `
+		-- name: UpsertPlatformSetting
+		INSERT INTO platform_settings (key, value, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (key) DO UPDATE
+		SET value = EXCLUDED.value, updated_at = NOW()
+	`
+	for _, key := range sortedKeys(params.Values) {
+		value := params.Values[key]
+		old, exists := current[key]
+		if exists && old == value {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, upsertQuery, key, value); err != nil {
+			return nil, fmt.Errorf("update setting %s: %w", key, err)
+		}
+		change := Change{New: &value}
+		if exists {
+			change.Old = &old
+		}
+		changes[key] = change
+	}
+
+	deleteQuery := `
+		-- name: DeletePlatformSetting
+		DELETE FROM platform_settings
+		WHERE key = $1
+	`
+	for _, key := range params.Delete {
+		old, exists := current[key]
+		if !exists {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, deleteQuery, key); err != nil {
+			return nil, fmt.Errorf("delete setting %s: %w", key, err)
+		}
+		changes[key] = Change{Old: &old}
+	}
+
+	if len(changes) == 0 {
+		return changes, nil
+	}
+
+	details, err := json.Marshal(map[string]any{"changes": changes})
+	if err != nil {
+		return nil, err
+	}
+
+	auditQuery := `
+		-- name: InsertSettingsAuditLog
+		INSERT INTO audit_logs (admin_id, action, target_type, target_id, details, client_ip)
+		VALUES ($1, $2, 'platform_settings', NULL, $3, NULLIF($4, ''))
+	`
+	if _, err := tx.ExecContext(ctx, auditQuery, params.AdminID, auditActionUpdateSettings, details, params.ClientIP); err != nil {
+		return nil, fmt.Errorf("write audit log: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/settings/repository"
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+)
+
+// ChangeChannel is the NOTIFY channel fired by the platform_settings trigger.
+const ChangeChannel = "platform_settings_changed"
+
+// Known setting keys. Anything else is stored under CustomPrefix.
+const (
+	KeyMaintenanceMode           = "maintenance_mode"
+	KeyMaintenanceMessage        = "maintenance_message"
+	KeyNewSignupsEnabled         = "new_signups_enabled"
+	KeyMaxSessionsPerDay         = "max_sessions_per_day"
+	KeyMinSessionRating          = "min_session_rating"
+	KeyAutoApproveCertifications = "auto_approve_certifications"
+	KeyPlatformFeePercentage     = "platform_fee_percentage"
+
+	CustomPrefix = "custom."
+)
+
+const (
+	defaultRefreshInterval   = time.Minute
+	maxMaintenanceMessage    = 500
+	maxCustomKeyLength       = 100 - len(CustomPrefix)
+	maxCustomValueLength     = 2000
+	defaultMaintenanceNotice = "SkillSphere is undergoing maintenance, please try again shortly"
+)
+
+var (
+	ErrInvalidSetting = errors.New("invalid platform setting")
+	ErrInvalidAdminID = errors.New("invalid admin id")
+)
+
+// Settings is the typed view of platform_settings.
+type Settings struct {
+	MaintenanceMode           bool
+	MaintenanceMessage        string
+	NewSignupsEnabled         bool
+	MaxSessionsPerDay         int
+	MinSessionRating          int
+	AutoApproveCertifications bool
+	PlatformFeePercentage     float64
+	Custom                    map[string]string
+}
+
+// Defaults returns the values used for keys that are missing or unparsable.
+func Defaults() Settings {
+	return Settings{
+		NewSignupsEnabled: true,
+		Custom:            map[string]string{},
+	}
+}
+
+// Validate checks every known key against its allowed range.
+func (s Settings) Validate() error {
+	var errs []error
+	fail := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s: %s", ErrInvalidSetting, key, fmt.Sprintf(format, args...)))
+	}
+
+	if len(s.MaintenanceMessage) > maxMaintenanceMessage {
+		fail(KeyMaintenanceMessage, "must be at most %d characters", maxMaintenanceMessage)
+	}
+	if s.MaxSessionsPerDay < 0 {
+		fail(KeyMaxSessionsPerDay, "must not be negative")
+	}
+	if s.MinSessionRating < 0 || s.MinSessionRating > 5 {
+		fail(KeyMinSessionRating, "must be between 0 and 5")
+	}
+	if s.PlatformFeePercentage < 0 || s.PlatformFeePercentage > 100 {
+		fail(KeyPlatformFeePercentage, "must be between 0 and 100")
+	}
+	for key, value := range s.Custom {
+		if key == "" || len(key) > maxCustomKeyLength {
+			fail(CustomPrefix+key, "key must be 1-%d characters", maxCustomKeyLength)
+		}
+		if len(value) > maxCustomValueLength {
+			fail(CustomPrefix+key, "value must be at most %d characters", maxCustomValueLength)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// values encodes s as platform_settings rows.
+func (s Settings) values() map[string]string {
+	values := map[string]string{
+		KeyMaintenanceMode:           strconv.FormatBool(s.MaintenanceMode),
+		KeyMaintenanceMessage:        s.MaintenanceMessage,
+		KeyNewSignupsEnabled:         strconv.FormatBool(s.NewSignupsEnabled),
+		KeyMaxSessionsPerDay:         strconv.Itoa(s.MaxSessionsPerDay),
+		KeyMinSessionRating:          strconv.Itoa(s.MinSessionRating),
+		KeyAutoApproveCertifications: strconv.FormatBool(s.AutoApproveCertifications),
+		KeyPlatformFeePercentage:     strconv.FormatFloat(s.PlatformFeePercentage, 'f', -1, 64),
+	}
+	for key, value := range s.Custom {
+		values[CustomPrefix+key] = value
+	}
+	return values
+}
+
+// parseSettings decodes stored rows. Invalid values keep their defaults and are
+// reported so a bad manual edit cannot take the platform down.
+func parseSettings(values map[string]string) (Settings, []error) {
+	settings := Defaults()
+	var errs []error
+
+	parseBool := func(key string, dst *bool) {
+		if raw, ok := values[key]; ok {
+			v, err := strconv.ParseBool(raw)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = v
+		}
+	}
+	parseInt := func(key string, dst *int) {
+		if raw, ok := values[key]; ok {
+			v, err := strconv.Atoi(raw)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = v
+		}
+	}
+
+	parseBool(KeyMaintenanceMode, &settings.MaintenanceMode)
+	parseBool(KeyNewSignupsEnabled, &settings.NewSignupsEnabled)
+	parseBool(KeyAutoApproveCertifications, &settings.AutoApproveCertifications)
+	parseInt(KeyMaxSessionsPerDay, &settings.MaxSessionsPerDay)
+	parseInt(KeyMinSessionRating, &settings.MinSessionRating)
+	settings.MaintenanceMessage = values[KeyMaintenanceMessage]
+	if raw, ok := values[KeyPlatformFeePercentage]; ok {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", KeyPlatformFeePercentage, err))
+		} else {
+			settings.PlatformFeePercentage = v
+		}
+	}
+
+	for key, value := range values {
+		if custom, ok := strings.CutPrefix(key, CustomPrefix); ok {
+			settings.Custom[custom] = value
+		}
+	}
+	return settings, errs
+}
+
+// Notifier delivers Postgres notifications; *db.DB implements it.
+type Notifier interface {
+	Listen(ctx context.Context, channel string, onConnect func(context.Context), fn func(db.Notification)) error
+}
+
+// Service serves platform settings from a cache kept current via NOTIFY.
+type Service struct {
+	repo            repository.SettingsRepository
+	logger          *slog.Logger
+	refreshInterval time.Duration
+
+	current atomic.Pointer[Settings]
+
+	mu        sync.Mutex
+	listeners []func(old, updated Settings)
+}
+
+// NewService constructs the settings service, starting from Defaults.
+func NewService(repo repository.SettingsRepository, logger *slog.Logger) *Service {
+	s := &Service{
+		repo:            repo,
+		logger:          logger,
+		refreshInterval: defaultRefreshInterval,
+	}
+	defaults := Defaults()
+	s.current.Store(&defaults)
+	return s
+}
+
+// Current returns the cached settings. Callers must not modify Custom.
+func (s *Service) Current() Settings {
+	return *s.current.Load()
+}
+
+// SignupsEnabled reports whether new accounts may be registered.
+func (s *Service) SignupsEnabled() bool {
+	return s.current.Load().NewSignupsEnabled
+}
+
+// Maintenance reports whether maintenance mode is on and the message to show.
+func (s *Service) Maintenance() (bool, string) {
+	current := s.current.Load()
+	if !current.MaintenanceMode {
+		return false, ""
+	}
+	if current.MaintenanceMessage == "" {
+		return true, defaultMaintenanceNotice
+	}
+	return true, current.MaintenanceMessage
+}
+
+// OnChange registers fn to be called whenever the cached settings change.
+func (s *Service) OnChange(fn func(old, updated Settings)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Refresh reloads the cache from the repository.
+func (s *Service) Refresh(ctx context.Context) error {
+	values, err := s.repo.ListSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("load platform settings: %w", err)
+	}
+	settings, parseErrs := parseSettings(values)
+	for _, err := range parseErrs {
+		s.logger.Warn("ignoring invalid platform setting", "error", err)
+	}
+	s.store(settings)
+	return nil
+}
+
+// Run keeps the cache current until ctx is cancelled.
+func (s *Service) Run(ctx context.Context, notifier Notifier) {
+	refresh := func(ctx context.Context) {
+		if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("failed to refresh platform settings", "error", err)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				refresh(ctx)
+			}
+		}
+	}()
+
+	if err := notifier.Listen(ctx, ChangeChannel, refresh, func(db.Notification) {
+		refresh(ctx)
+	}); err != nil {
+		s.logger.Error("platform settings listener stopped", "error", err)
+	}
+}
+
+// UpdateParams describes an admin update. Settings replaces the full set; custom
+// keys that are absent are deleted.
+type UpdateParams struct {
+	AdminID  string
+	ClientIP string
+	Settings Settings
+}
+
+// Update validates and stores the settings, auditing the keys that changed.
+func (s *Service) Update(ctx context.Context, params UpdateParams) (Settings, error) {
+	adminID, err := uuid.Parse(params.AdminID)
+	if err != nil {
+		return Settings{}, ErrInvalidAdminID
+	}
+	if params.Settings.Custom == nil {
+		params.Settings.Custom = map[string]string{}
+	}
+	if err := params.Settings.Validate(); err != nil {
+		return Settings{}, err
+	}
+
+	var deleted []string
+	for key := range s.Current().Custom {
+		if _, kept := params.Settings.Custom[key]; !kept {
+			deleted = append(deleted, CustomPrefix+key)
+		}
+	}
+
+	changes, err := s.repo.UpdateSettings(ctx, repository.UpdateParams{
+		AdminID:  adminID,
+		ClientIP: params.ClientIP,
+		Values:   params.Settings.values(),
+		Delete:   deleted,
+	})
+	if err != nil {
+		return Settings{}, err
+	}
+	if len(changes) > 0 {
+		s.logger.InfoContext(ctx, "platform settings updated", "admin_id", params.AdminID, "keys", len(changes))
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		return Settings{}, err
+	}
+	return s.Current(), nil
+}
+
+func (s *Service) store(settings Settings) {
+	old := s.current.Swap(&settings)
+	if old != nil && settingsEqual(*old, settings) {
+		return
+	}
+
+	s.mu.Lock()
+	listeners := append([]func(old, updated Settings){}, s.listeners...)
+	s.mu.Unlock()
+
+	previous := Defaults()
+	if old != nil {
+		previous = *old
+	}
+	for _, fn := range listeners {
+		fn(previous, settings)
+	}
+}
+
+func settingsEqual(a, b Settings) bool {
+	return maps.Equal(a.values(), b.values())
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/settings/repository"
+)
+
+type fakeSettingsRepo struct {
+	values  map[string]string
+	updates []repository.UpdateParams
+}
+
+func (r *fakeSettingsRepo) ListSettings(context.Context) (map[string]string, error) {
+	out := make(map[string]string, len(r.values))
+	for k, v := range r.values {
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (r *fakeSettingsRepo) UpdateSettings(_ context.Context, params repository.UpdateParams) (map[string]repository.Change, error) {
+	r.updates = append(r.updates, params)
+	changes := map[string]repository.Change{}
+	for k, v := range params.Values {
+		if r.values[k] != v {
+			value := v
+			changes[k] = repository.Change{New: &value}
+			r.values[k] = v
+		}
+	}
+	for _, k := range params.Delete {
+		delete(r.values, k)
+		changes[k] = repository.Change{}
+	}
+	return changes, nil
+}
+
+func newTestService(t *testing.T, values map[string]string) (*Service, *fakeSettingsRepo) {
+	t.Helper()
+	repo := &fakeSettingsRepo{values: values}
+	svc := NewService(repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := svc.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	return svc, repo
+}
+
+func TestRefresh_ParsesKnownKeysAndKeepsDefaultsForBadValues(t *testing.T) {
+	svc, _ := newTestService(t, map[string]string{
+		KeyMaintenanceMode:       "true",
+		KeyNewSignupsEnabled:     "not-a-bool",
+		KeyPlatformFeePercentage: "12.5",
+		CustomPrefix + "banner":  "hello",
+	})
+
+	current := svc.Current()
+	if !current.MaintenanceMode || current.PlatformFeePercentage != 12.5 {
+		t.Errorf("settings = %+v", current)
+	}
+	if !svc.SignupsEnabled() {
+		t.Error("unparsable new_signups_enabled should fall back to the default (true)")
+	}
+	if current.Custom["banner"] != "hello" {
+		t.Errorf("custom = %v", current.Custom)
+	}
+	if enabled, message := svc.Maintenance(); !enabled || message != defaultMaintenanceNotice {
+		t.Errorf("Maintenance() = %v, %q", enabled, message)
+	}
+}
+
+func TestUpdate_ValidatesAndNotifies(t *testing.T) {
+	svc, repo := newTestService(t, map[string]string{CustomPrefix + "old": "x"})
+	admin := uuid.NewString()
+
+	var notified []Settings
+	svc.OnChange(func(_, updated Settings) { notified = append(notified, updated) })
+
+	_, err := svc.Update(context.Background(), UpdateParams{
+		AdminID:  admin,
+		Settings: Settings{MinSessionRating: 9, PlatformFeePercentage: -1},
+	})
+	if !errors.Is(err, ErrInvalidSetting) {
+		t.Fatalf("err = %v, want ErrInvalidSetting", err)
+	}
+	if len(repo.updates) != 0 {
+		t.Fatal("invalid settings must not be written")
+	}
+
+	updated, err := svc.Update(context.Background(), UpdateParams{
+		AdminID:  admin,
+		ClientIP: "10.0.0.1",
+		Settings: Settings{NewSignupsEnabled: false, MaintenanceMode: true, MaintenanceMessage: "upgrading"},
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if !updated.MaintenanceMode || svc.SignupsEnabled() {
+		t.Errorf("updated = %+v", updated)
+	}
+	if got := repo.updates[0].Delete; len(got) != 1 || got[0] != CustomPrefix+"old" {
+		t.Errorf("custom keys absent from the update should be deleted, got %v", got)
+	}
+	if len(notified) != 1 || !notified[0].MaintenanceMode {
+		t.Errorf("notified = %+v", notified)
+	}
+
+	if _, err := svc.Update(context.Background(), UpdateParams{AdminID: "nope"}); !errors.Is(err, ErrInvalidAdminID) {
+		t.Errorf("err = %v, want ErrInvalidAdminID", err)
+	}
+}
@@ -0,0 +1,722 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+)
+
+const (
+	auditActionImportTaxonomy = "skill_taxonomy.import"
+	auditActionMergeSkills    = "skill.merge"
+)
+
+var (
+	// ErrSkillNotFound is returned when a skill does not exist.
+	ErrSkillNotFound = errors.New("skill not found")
+	// ErrTaxonomyCycle is returned when a write would make a skill broader than itself.
+	ErrTaxonomyCycle = errors.New("skos:broader relations must not form a cycle")
+	// ErrLabelTaken is returned when an alternative label already belongs to another skill.
+	ErrLabelTaken = errors.New("alternative label already belongs to another skill")
+)
+
+// Label is a language-tagged skos:altLabel; Language is empty when untagged.
+type Label struct {
+	Value    string
+	Language string
+}
+
+// Concept is a skill together with its taxonomy relations.
+type Concept struct {
+	ID       uuid.UUID
+	Name     string
+	Category string
+	Broader  []uuid.UUID
+	// Related lists every skill related to this one, in either direction.
+	Related   []uuid.UUID
+	AltLabels []Label
+}
+
+// ReplaceParams describes a taxonomy import by an admin.
+type ReplaceParams struct {
+	AdminID uuid.UUID
+	// Concepts have their broader, related and alternative labels replaced
+	// with the given sets; skills not listed are left untouched.
+	Concepts []Concept
+}
+
+// MergeParams describes merging a duplicate skill into the one that is kept.
+type MergeParams struct {
+	AdminID  uuid.UUID
+	SourceID uuid.UUID
+	TargetID uuid.UUID
+}
+
+// MergeResult counts the references moved from the source to the target.
+type MergeResult struct {
+	UserSkills int64
+	GigSkills  int64
+	Reviews    int64
+}
+
+// Relation says how an expanded skill relates to the one it was expanded from.
+type Relation string
+
+const (
+	RelationExact    Relation = "exact"
+	RelationNarrower Relation = "narrower"
+	RelationBroader  Relation = "broader"
+	RelationRelated  Relation = "related"
+)
+
+// ExpandOptions selects the directions a query is expanded in.
+type ExpandOptions struct {
+	Narrower bool
+	Broader  bool
+	Related  bool
+	// MaxDepth bounds how many broader/narrower hops are followed.
+	MaxDepth int
+}
+
+// Expansion is a skill reached while expanding a query.
+type Expansion struct {
+	SkillID  uuid.UUID
+	Name     string
+	Relation Relation
+	// Depth is the number of hops from the nearest seed skill; 0 for the seeds.
+	Depth int
+	// Labels are the skill's name followed by its alternative labels.
+	Labels []string
+}
+
+// TaxonomyRepository persists the skill taxonomy.
+type TaxonomyRepository interface {
+	ListConcepts(ctx context.Context) ([]Concept, error)
+	// ReplaceConcepts applies params atomically and records the import in audit_logs.
+	ReplaceConcepts(ctx context.Context, params ReplaceParams) error
+	// MergeSkills moves every reference to the source skill onto the target,
+	// records the source's name as an alternative label and deletes the source.
+	MergeSkills(ctx context.Context, params MergeParams) (*MergeResult, error)
+	// ResolveLabel returns the skills whose name or alternative label matches
+	// label case-insensitively.
+	ResolveLabel(ctx context.Context, label string) ([]uuid.UUID, error)
+	// Expand returns the seeds and the skills reachable from them. Ids of
+	// merged skills resolve to the skill they were merged into.
+	Expand(ctx context.Context, seeds []uuid.UUID, opts ExpandOptions) ([]Expansion, error)
+}
+
+// NormalizeLabel folds case and whitespace so that labels compare as users expect.
+func NormalizeLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+// PostgresTaxonomyRepository stores the taxonomy in skill_broader,
+// skill_related and skill_alt_labels.
+type PostgresTaxonomyRepository struct {
+	db *db.SQL
+}
+
+// NewPostgresTaxonomyRepository creates a new taxonomy repository. Reads go
+// to replicas; see db.SQL.
+func NewPostgresTaxonomyRepository(db *db.SQL) *PostgresTaxonomyRepository {
+	return &PostgresTaxonomyRepository{db: db}
+}
+
+// ListConcepts returns every skill with its relations, ordered by name.
+func (r *PostgresTaxonomyRepository) ListConcepts(ctx context.Context) ([]Concept, error) {
+	query := `
+		-- name: ListSkillConcepts
+		SELECT s.id, s.name, c.name
+		FROM skills s
+		JOIN skill_categories c ON c.id = s.category_id
+		ORDER BY s.name, s.id
+	`
+
+	reader := r.db.Read(ctx)
+	rows, err := reader.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var concepts []Concept
+	index := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var concept Concept
+		if err := rows.Scan(&concept.ID, &concept.Name, &concept.Category); err != nil {
+			return nil, err
+		}
+		index[concept.ID] = len(concepts)
+		concepts = append(concepts, concept)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	relationsQuery := `
+		-- name: ListSkillRelations
+		SELECT 'broader', skill_id, broader_id FROM skill_broader
+		UNION ALL
+		SELECT 'related', skill_id, related_id FROM skill_related
+		UNION ALL
+		SELECT 'related', related_id, skill_id FROM skill_related
+		ORDER BY 1, 2, 3
+	`
+	relRows, err := reader.QueryContext(ctx, relationsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer relRows.Close()
+
+	for relRows.Next() {
+		var (
+			kind           string
+			skillID, other uuid.UUID
+		)
+		if err := relRows.Scan(&kind, &skillID, &other); err != nil {
+			return nil, err
+		}
+		i, ok := index[skillID]
+		if !ok {
+			continue
+		}
+		if kind == "broader" {
+			concepts[i].Broader = append(concepts[i].Broader, other)
+		} else {
+			concepts[i].Related = append(concepts[i].Related, other)
+		}
+	}
+	if err := relRows.Err(); err != nil {
+		return nil, err
+	}
+
+	labelsQuery := `
+		-- name: ListSkillAltLabels
+		SELECT skill_id, label, language
+		FROM skill_alt_labels
+		ORDER BY skill_id, normalized_label
+	`
+	labelRows, err := reader.QueryContext(ctx, labelsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer labelRows.Close()
+
+	for labelRows.Next() {
+		var (
+			skillID uuid.UUID
+			label   Label
+		)
+		if err := labelRows.Scan(&skillID, &label.Value, &label.Language); err != nil {
+			return nil, err
+		}
+		if i, ok := index[skillID]; ok {
+			concepts[i].AltLabels = append(concepts[i].AltLabels, label)
+		}
+	}
+	return concepts, labelRows.Err()
+}
+
+// ReplaceConcepts implements TaxonomyRepository.
+func (r *PostgresTaxonomyRepository) ReplaceConcepts(ctx context.Context, params ReplaceParams) error {
+	tx, err := r.db.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Serialise taxonomy writers so the cycle check sees every edge.
+	if err := lockTaxonomy(ctx, tx); err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(params.Concepts))
+	for _, concept := range params.Concepts {
+		ids = append(ids, concept.ID.String())
+	}
+
+	var found int
+	countQuery := `
+		-- name: CountSkillsByID
+		SELECT COUNT(*) FROM skills WHERE id = ANY($1::uuid[])
+	`
+	if err := tx.QueryRowContext(ctx, countQuery, ids).Scan(&found); err != nil {
+		return err
+	}
+	if found != len(ids) {
+		return ErrSkillNotFound
+	}
+
+	clearQueries := []string{`
+		-- name: ClearSkillBroader
+		DELETE FROM skill_broader WHERE skill_id = ANY($1::uuid[])
+	`, `
+		-- name: ClearSkillRelated
+		DELETE FROM skill_related WHERE skill_id = ANY($1::uuid[]) OR related_id = ANY($1::uuid[])
+	`, `
+		-- name: ClearSkillAltLabels
+		DELETE FROM skill_alt_labels WHERE skill_id = ANY($1::uuid[])
+	`}
+	for _, query := range clearQueries {
+		if _, err := tx.ExecContext(ctx, query, ids); err != nil {
+			return err
+		}
+	}
+
+	broaderQuery := `
+		-- name: InsertSkillBroader
+		INSERT INTO skill_broader (skill_id, broader_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	relatedQuery := `
+		-- name: InsertSkillRelated
+		INSERT INTO skill_related (skill_id, related_id)
+		VALUES (LEAST($1::uuid, $2::uuid), GREATEST($1::uuid, $2::uuid))
+		ON CONFLICT DO NOTHING
+	`
+	labelQuery := `
+		-- name: InsertSkillAltLabel
+		INSERT INTO skill_alt_labels (skill_id, label, language, normalized_label)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, concept := range params.Concepts {
+		for _, broader := range concept.Broader {
+			if _, err := tx.ExecContext(ctx, broaderQuery, concept.ID, broader); err != nil {
+				return fmt.Errorf("add broader of %s: %w", concept.ID, relationError(err))
+			}
+		}
+		for _, related := range concept.Related {
+			if _, err := tx.ExecContext(ctx, relatedQuery, concept.ID, related); err != nil {
+				return fmt.Errorf("add related of %s: %w", concept.ID, relationError(err))
+			}
+		}
+		for _, label := range concept.AltLabels {
+			if _, err := tx.ExecContext(ctx, labelQuery,
+				concept.ID, label.Value, label.Language, NormalizeLabel(label.Value)); err != nil {
+				return fmt.Errorf("add label %q: %w", label.Value, relationError(err))
+			}
+		}
+	}
+
+	if err := checkAcyclic(ctx, tx, ids); err != nil {
+		return err
+	}
+
+	details, err := json.Marshal(map[string]any{"concepts": len(params.Concepts)})
+	if err != nil {
+		return err
+	}
+	if err := insertAuditLog(ctx, tx, params.AdminID, auditActionImportTaxonomy, "", details); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	r.db.MarkWrite(ctx)
+	return nil
+}
+
+// MergeSkills implements TaxonomyRepository.
+func (r *PostgresTaxonomyRepository) MergeSkills(ctx context.Context, params MergeParams) (*MergeResult, error) {
+	tx, err := r.db.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockTaxonomy(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	lockQuery := `
+		-- name: LockSkillsForMerge
+		SELECT id, name FROM skills
+		WHERE id IN ($1, $2)
+		ORDER BY id
+		FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, lockQuery, params.SourceID, params.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, 2)
+	for rows.Next() {
+		var (
+			id   uuid.UUID
+			name string
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		names[id] = name
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(names) != 2 {
+		return nil, ErrSkillNotFound
+	}
+
+	result := &MergeResult{}
+	exec := func(query string, count *int64) error {
+		res, err := tx.ExecContext(ctx, query, params.SourceID, params.TargetID)
+		if err != nil {
+			return err
+		}
+		if count != nil {
+			*count, _ = res.RowsAffected()
+		}
+		return nil
+	}
+
+	// A user who listed both keeps one row with the higher proficiency.
+	userSkillsQuery := `
+		-- name: MergeUserSkills
+		INSERT INTO user_skills (user_id, skill_id, skill_type, proficiency, created_at, updated_at)
+		SELECT user_id, $2::uuid, skill_type, proficiency, created_at, NOW()
+		FROM user_skills
+		WHERE skill_id = $1
+		ON CONFLICT (user_id, skill_id, skill_type) DO UPDATE
+		SET proficiency = GREATEST(user_skills.proficiency, EXCLUDED.proficiency),
+		    updated_at = NOW()
+	`
+	if err := exec(userSkillsQuery, &result.UserSkills); err != nil {
+		return nil, fmt.Errorf("merge user_skills: %w", err)
+	}
+
+	gigSkillsQuery := `
+		-- name: MergeGigSkills
+		INSERT INTO gig_skills (gig_id, skill_id)
+		SELECT gig_id, $2::uuid FROM gig_skills WHERE skill_id = $1
+		ON CONFLICT DO NOTHING
+	`
+	if err := exec(gigSkillsQuery, &result.GigSkills); err != nil {
+		return nil, fmt.Errorf("merge gig_skills: %w", err)
+	}
+
+	reviewsQuery := `
+		-- name: MergeReviewSkills
+		UPDATE reviews SET skill_id = $2 WHERE skill_id = $1
+	`
+	if err := exec(reviewsQuery, &result.Reviews); err != nil {
+		return nil, fmt.Errorf("merge reviews: %w", err)
+	}
+
+	// References that would otherwise be cascaded away or left dangling.
+	otherQueries := []string{`
+		-- name: MergeSkillTags
+		INSERT INTO skill_to_tags (skill_id, tag_id)
+		SELECT $2::uuid, tag_id FROM skill_to_tags WHERE skill_id = $1
+		ON CONFLICT DO NOTHING
+	`, `
+		-- name: MergeCertificationSkills
+		UPDATE certifications SET skill_id = $2 WHERE skill_id = $1
+	`, `
+		-- name: MergeSkillTrendMetrics
+		UPDATE skill_trend_metrics SET skill_id = $2 WHERE skill_id = $1
+	`, `
+		-- name: MergeWorkshopSkillIDs
+		UPDATE workshops
+		SET skill_ids = CASE WHEN $2 = ANY(skill_ids) THEN array_remove(skill_ids, $1)
+		                     ELSE array_replace(skill_ids, $1, $2) END
+		WHERE $1 = ANY(skill_ids)
+	`, `
+		-- name: MergeChallengeSkillIDs
+		UPDATE challenges
+		SET skill_ids = CASE WHEN $2 = ANY(skill_ids) THEN array_remove(skill_ids, $1)
+		                     ELSE array_replace(skill_ids, $1, $2) END
+		WHERE $1 = ANY(skill_ids)
+	`, `
+		-- name: MergeSearchDocumentSkillIDs
+		UPDATE user_search_documents
+		SET skill_ids = CASE WHEN $2 = ANY(skill_ids) THEN array_remove(skill_ids, $1)
+		                     ELSE array_replace(skill_ids, $1, $2) END
+		WHERE $1 = ANY(skill_ids)
+	`, `
+		-- name: MergeSkillBroader
+		INSERT INTO skill_broader (skill_id, broader_id)
+		SELECT $2::uuid, broader_id FROM skill_broader WHERE skill_id = $1 AND broader_id <> $2
+		UNION
+		SELECT skill_id, $2::uuid FROM skill_broader WHERE broader_id = $1 AND skill_id <> $2
+		ON CONFLICT DO NOTHING
+	`, `
+		-- name: MergeSkillRelated
+		INSERT INTO skill_related (skill_id, related_id)
+		SELECT LEAST($2::uuid, other), GREATEST($2::uuid, other)
+		FROM (
+			SELECT related_id AS other FROM skill_related WHERE skill_id = $1
+			UNION
+			SELECT skill_id FROM skill_related WHERE related_id = $1
+		) related
+		WHERE other <> $2
+		ON CONFLICT DO NOTHING
+	`, `
+		-- name: MergeSkillAltLabels
+		UPDATE skill_alt_labels SET skill_id = $2 WHERE skill_id = $1
+	`, `
+		-- name: RetargetSkillMerges
+		UPDATE skill_merges SET target_id = $2 WHERE target_id = $1
+	`}
+	for _, query := range otherQueries {
+		if err := exec(query, nil); err != nil {
+			return nil, fmt.Errorf("merge skill references: %w", err)
+		}
+	}
+
+	// Keep the duplicate's name as a synonym unless it is the target's own name.
+	sourceName := names[params.SourceID]
+	if NormalizeLabel(sourceName) != NormalizeLabel(names[params.TargetID]) {
+		labelQuery := `
+			-- name: AddMergedSkillLabel
+			INSERT INTO skill_alt_labels (skill_id, label, normalized_label)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (normalized_label) DO UPDATE SET skill_id = EXCLUDED.skill_id
+		`
+		if _, err := tx.ExecContext(ctx, labelQuery, params.TargetID, sourceName, NormalizeLabel(sourceName)); err != nil {
+			return nil, fmt.Errorf("add merged label: %w", err)
+		}
+	}
+
+	mergeQuery := `
+		-- name: InsertSkillMerge
+		INSERT INTO skill_merges (source_id, target_id, source_name, merged_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (source_id) DO UPDATE
+		SET target_id = EXCLUDED.target_id, merged_by = EXCLUDED.merged_by, merged_at = NOW()
+	`
+	if _, err := tx.ExecContext(ctx, mergeQuery, params.SourceID, params.TargetID, sourceName, params.AdminID); err != nil {
+		return nil, fmt.Errorf("record merge: %w", err)
+	}
+
+	deleteQuery := `
+		-- name: DeleteMergedSkill
+		DELETE FROM skills WHERE id = $1
+	`
+	if _, err := tx.ExecContext(ctx, deleteQuery, params.SourceID); err != nil {
+		return nil, fmt.Errorf("delete merged skill: %w", err)
+	}
+
+	countersQuery := `
+		-- name: RecountSkillUsers
+		UPDATE skills SET
+			users_offering_count = (SELECT COUNT(*) FROM user_skills WHERE skill_id = $1 AND skill_type = 'offered'),
+			users_wanting_count = (SELECT COUNT(*) FROM user_skills WHERE skill_id = $1 AND skill_type = 'wanted'),
+			updated_at = NOW()
+		WHERE id = $1
+	`
+	if _, err := tx.ExecContext(ctx, countersQuery, params.TargetID); err != nil {
+		return nil, fmt.Errorf("recount skill users: %w", err)
+	}
+
+	// Moving the source's edges can close a loop when the source sat below
+	// the target through another skill.
+	if err := checkAcyclic(ctx, tx, []string{params.TargetID.String()}); err != nil {
+		return nil, err
+	}
+
+	details, err := json.Marshal(map[string]any{
+		"source_id":   params.SourceID,
+		"source_name": sourceName,
+		"user_skills": result.UserSkills,
+		"gig_skills":  result.GigSkills,
+		"reviews":     result.Reviews,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := insertAuditLog(ctx, tx, params.AdminID, auditActionMergeSkills, params.TargetID.String(), details); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	r.db.MarkWrite(ctx)
+	return result, nil
+}
+
+// ResolveLabel implements TaxonomyRepository.
+func (r *PostgresTaxonomyRepository) ResolveLabel(ctx context.Context, label string) ([]uuid.UUID, error) {
+	query := `
+		-- name: ResolveSkillLabel
+		SELECT id FROM skills WHERE LOWER(name) = $1
+		UNION
+		SELECT skill_id FROM skill_alt_labels WHERE normalized_label = $1
+	`
+
+	rows, err := r.db.Read(ctx).QueryContext(ctx, query, NormalizeLabel(label))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Expand implements TaxonomyRepository.
+func (r *PostgresTaxonomyRepository) Expand(ctx context.Context, seeds []uuid.UUID, opts ExpandOptions) ([]Expansion, error) {
+	if len(seeds) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(seeds))
+	for _, id := range seeds {
+		ids = append(ids, id.String())
+	}
+
+	// Related skills are a single hop from the seeds; the hierarchy is walked
+	// up to $5 hops. UNION drops rows already reached at the same depth.
+	query := `
+		-- name: ExpandSkills
+		WITH RECURSIVE seeds AS (
+			SELECT DISTINCT COALESCE(m.target_id, s.id) AS id
+			FROM UNNEST($1::uuid[]) AS s(id)
+			LEFT JOIN skill_merges m ON m.source_id = s.id
+		),
+		down (id, depth) AS (
+			SELECT id, 0 FROM seeds WHERE $2
+			UNION
+			SELECT b.skill_id, d.depth + 1
+			FROM skill_broader b JOIN down d ON b.broader_id = d.id
+			WHERE d.depth < $5
+		),
+		up (id, depth) AS (
+			SELECT id, 0 FROM seeds WHERE $3
+			UNION
+			SELECT b.broader_id, u.depth + 1
+			FROM skill_broader b JOIN up u ON b.skill_id = u.id
+			WHERE u.depth < $5
+		),
+		reached (id, relation, depth) AS (
+			SELECT id, 'exact', 0 FROM seeds
+			UNION ALL
+			SELECT id, 'narrower', depth FROM down WHERE depth > 0
+			UNION ALL
+			SELECT id, 'broader', depth FROM up WHERE depth > 0
+			UNION ALL
+			SELECT CASE WHEN r.skill_id = s.id THEN r.related_id ELSE r.skill_id END, 'related', 1
+			FROM seeds s JOIN skill_related r ON s.id IN (r.skill_id, r.related_id)
+			WHERE $4
+		),
+		nearest AS (
+			SELECT DISTINCT ON (id) id, relation, depth
+			FROM reached
+			ORDER BY id, depth, CASE relation
+				WHEN 'exact' THEN 0 WHEN 'narrower' THEN 1 WHEN 'related' THEN 2 ELSE 3 END
+		)
+		SELECT n.id, sk.name, n.relation, n.depth,
+			(SELECT COALESCE(json_agg(a.label ORDER BY a.normalized_label), '[]')
+			 FROM skill_alt_labels a WHERE a.skill_id = n.id)
+		FROM nearest n
+		JOIN skills sk ON sk.id = n.id
+		ORDER BY n.depth, sk.name
+	`
+
+	rows, err := r.db.Read(ctx).QueryContext(ctx, query, ids, opts.Narrower, opts.Broader, opts.Related, opts.MaxDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expansions []Expansion
+	for rows.Next() {
+		var (
+			expansion Expansion
+			relation  string
+			rawLabels []byte
+			labels    []string
+		)
+		if err := rows.Scan(&expansion.SkillID, &expansion.Name, &relation, &expansion.Depth, &rawLabels); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(rawLabels, &labels); err != nil {
+			return nil, fmt.Errorf("decode labels of %s: %w", expansion.SkillID, err)
+		}
+		expansion.Relation = Relation(relation)
+		expansion.Labels = append([]string{expansion.Name}, labels...)
+		expansions = append(expansions, expansion)
+	}
+	return expansions, rows.Err()
+}
+
+// lockTaxonomy takes a transaction-scoped advisory lock shared by all taxonomy writers.
+func lockTaxonomy(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		-- name: LockSkillTaxonomy
+		SELECT pg_advisory_xact_lock(hashtext('skill_taxonomy'))
+	`)
+	return err
+}
+
+// checkAcyclic reports ErrTaxonomyCycle when any of the given skills can reach
+// itself through skos:broader.
+func checkAcyclic(ctx context.Context, tx *sql.Tx, ids []string) error {
+	query := `
+		-- name: FindSkillBroaderCycle
+		WITH RECURSIVE walk (start_id, id) AS (
+			SELECT skill_id, broader_id FROM skill_broader WHERE skill_id = ANY($1::uuid[])
+			UNION
+			SELECT w.start_id, b.broader_id
+			FROM walk w JOIN skill_broader b ON b.skill_id = w.id
+			WHERE w.id <> w.start_id
+		)
+		SELECT s.name FROM walk w JOIN skills s ON s.id = w.start_id
+		WHERE w.id = w.start_id
+		LIMIT 1
+	`
+	var name string
+	err := tx.QueryRowContext(ctx, query, ids).Scan(&name)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %q is broader than itself", ErrTaxonomyCycle, name)
+}
+
+func insertAuditLog(ctx context.Context, tx *sql.Tx, adminID uuid.UUID, action, targetID string, details []byte) error {
+	query := `
+		-- name: InsertSkillTaxonomyAuditLog
+		INSERT INTO audit_logs (admin_id, action, target_type, target_id, details)
+		VALUES ($1, $2, 'skill', NULLIF($3, ''), $4)
+	`
+	if _, err := tx.ExecContext(ctx, query, adminID, action, targetID, details); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+// relationError maps constraint violations on the taxonomy tables to domain errors.
+func relationError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case "23505":
+		return ErrLabelTaken
+	case "23503":
+		return ErrSkillNotFound
+	default:
+		return err
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/taxonomy/repository"
+)
+
+const (
+	// DefaultExpandDepth is how many broader/narrower hops Expand follows
+	// when the caller does not say.
+	DefaultExpandDepth = 3
+	maxExpandDepth     = 10
+)
+
+var (
+	ErrInvalidTaxonomy = errors.New("invalid skill taxonomy")
+	ErrUnknownConcept  = errors.New("taxonomy refers to unknown skills")
+	ErrAmbiguousLabel  = errors.New("label matches more than one skill")
+	ErrInvalidSkillID  = errors.New("invalid skill id")
+	ErrSameSkill       = errors.New("cannot merge a skill into itself")
+	ErrInvalidAdminID  = errors.New("invalid admin id")
+)
+
+// Service manages the SKOS skill taxonomy and expands skill queries along it.
+type Service struct {
+	repo   repository.TaxonomyRepository
+	logger *slog.Logger
+}
+
+// NewService constructs the taxonomy service.
+func NewService(repo repository.TaxonomyRepository, logger *slog.Logger) *Service {
+	return &Service{repo: repo, logger: logger}
+}
+
+// ImportParams describes a Turtle taxonomy uploaded by an admin.
+type ImportParams struct {
+	AdminID string
+	Turtle  []byte
+}
+
+// ImportResult summarises an applied import.
+type ImportResult struct {
+	Concepts  int
+	Broader   int
+	Related   int
+	AltLabels int
+}
+
+// Import replaces the relations of every skill described in params.Turtle.
+// See decodeTaxonomy for how concepts are matched to skills.
+func (s *Service) Import(ctx context.Context, params ImportParams) (*ImportResult, error) {
+	adminID, err := uuid.Parse(params.AdminID)
+	if err != nil {
+		return nil, ErrInvalidAdminID
+	}
+
+	existing, err := s.repo.ListConcepts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load skill taxonomy: %w", err)
+	}
+	concepts, err := decodeTaxonomy(params.Turtle, existing)
+	if err != nil {
+		return nil, err
+	}
+	if len(concepts) == 0 {
+		return nil, fmt.Errorf("%w: no skos concepts found", ErrInvalidTaxonomy)
+	}
+	if err := checkAcyclic(existing, concepts); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReplaceConcepts(ctx, repository.ReplaceParams{AdminID: adminID, Concepts: concepts}); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Concepts: len(concepts)}
+	for _, concept := range concepts {
+		result.Broader += len(concept.Broader)
+		result.Related += len(concept.Related)
+		result.AltLabels += len(concept.AltLabels)
+	}
+	s.logger.InfoContext(ctx, "skill taxonomy imported", "admin_id", params.AdminID, "concepts", result.Concepts)
+	return result, nil
+}
+
+// Export writes the whole taxonomy as Turtle.
+func (s *Service) Export(ctx context.Context, w io.Writer) error {
+	concepts, err := s.repo.ListConcepts(ctx)
+	if err != nil {
+		return fmt.Errorf("load skill taxonomy: %w", err)
+	}
+	return encodeTaxonomy(w, concepts)
+}
+
+// MergeParams describes merging a duplicate skill into another.
+type MergeParams struct {
+	AdminID  string
+	SourceID string
+	TargetID string
+}
+
+// Merge folds the source skill into the target: users, gigs and reviews move
+// over, the source's name becomes an alternative label and the source is deleted.
+func (s *Service) Merge(ctx context.Context, params MergeParams) (*repository.MergeResult, error) {
+	adminID, err := uuid.Parse(params.AdminID)
+	if err != nil {
+		return nil, ErrInvalidAdminID
+	}
+	sourceID, err := uuid.Parse(params.SourceID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: source %q", ErrInvalidSkillID, params.SourceID)
+	}
+	targetID, err := uuid.Parse(params.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: target %q", ErrInvalidSkillID, params.TargetID)
+	}
+	if sourceID == targetID {
+		return nil, ErrSameSkill
+	}
+
+	result, err := s.repo.MergeSkills(ctx, repository.MergeParams{
+		AdminID:  adminID,
+		SourceID: sourceID,
+		TargetID: targetID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "skills merged",
+		"admin_id", params.AdminID, "source_id", sourceID, "target_id", targetID,
+		"user_skills", result.UserSkills, "gig_skills", result.GigSkills, "reviews", result.Reviews)
+	return result, nil
+}
+
+// Expand returns the given skills and those related to them in the directions
+// opts selects, nearest first. Matching uses it to treat a "Go" offer as
+// meeting a "Programming" want and vice versa.
+func (s *Service) Expand(ctx context.Context, skillIDs []uuid.UUID, opts repository.ExpandOptions) ([]repository.Expansion, error) {
+	if len(skillIDs) == 0 {
+		return nil, nil
+	}
+	return s.repo.Expand(ctx, skillIDs, normalizeExpandOptions(opts))
+}
+
+// ExpandTerm resolves a search term against skill names and alternative
+// labels, then expands the matches like Expand. It returns nil when the term
+// names no skill, so search can fall back to free text.
+func (s *Service) ExpandTerm(ctx context.Context, term string, opts repository.ExpandOptions) ([]repository.Expansion, error) {
+	if repository.NormalizeLabel(term) == "" {
+		return nil, nil
+	}
+	ids, err := s.repo.ResolveLabel(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	return s.Expand(ctx, ids, opts)
+}
+
+// SearchTerms flattens expansions into the distinct labels a text search
+// should match, nearest skills first.
+func SearchTerms(expansions []repository.Expansion) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, expansion := range expansions {
+		for _, label := range expansion.Labels {
+			key := repository.NormalizeLabel(label)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			terms = append(terms, label)
+		}
+	}
+	return terms
+}
+
+func normalizeExpandOptions(opts repository.ExpandOptions) repository.ExpandOptions {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultExpandDepth
+	}
+	if opts.MaxDepth > maxExpandDepth {
+		opts.MaxDepth = maxExpandDepth
+	}
+	return opts
+}
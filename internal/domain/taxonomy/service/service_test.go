@@ -0,0 +1,251 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/taxonomy/repository"
+	"github.com/FACorreiaa/skillsphere-api/internal/ontology"
+)
+
+type fakeTaxonomyRepo struct {
+	concepts []repository.Concept
+	replaced []repository.ReplaceParams
+	merged   []repository.MergeParams
+	expanded []repository.ExpandOptions
+}
+
+func (r *fakeTaxonomyRepo) ListConcepts(context.Context) ([]repository.Concept, error) {
+	return slices.Clone(r.concepts), nil
+}
+
+func (r *fakeTaxonomyRepo) ReplaceConcepts(_ context.Context, params repository.ReplaceParams) error {
+	r.replaced = append(r.replaced, params)
+	return nil
+}
+
+func (r *fakeTaxonomyRepo) MergeSkills(_ context.Context, params repository.MergeParams) (*repository.MergeResult, error) {
+	r.merged = append(r.merged, params)
+	return &repository.MergeResult{UserSkills: 2}, nil
+}
+
+func (r *fakeTaxonomyRepo) ResolveLabel(_ context.Context, label string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, concept := range r.concepts {
+		if repository.NormalizeLabel(concept.Name) == repository.NormalizeLabel(label) {
+			ids = append(ids, concept.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeTaxonomyRepo) Expand(_ context.Context, seeds []uuid.UUID, opts repository.ExpandOptions) ([]repository.Expansion, error) {
+	r.expanded = append(r.expanded, opts)
+	var out []repository.Expansion
+	for _, id := range seeds {
+		out = append(out, repository.Expansion{SkillID: id, Relation: repository.RelationExact})
+	}
+	return out, nil
+}
+
+// catalogue is a small skill catalogue with one existing synonym.
+type catalogue struct {
+	programming, golang, python, ml repository.Concept
+}
+
+func newTestService(t *testing.T) (*Service, *fakeTaxonomyRepo, catalogue) {
+	t.Helper()
+	c := catalogue{
+		programming: repository.Concept{ID: uuid.New(), Name: "Programming"},
+		golang:      repository.Concept{ID: uuid.New(), Name: "Go"},
+		python:      repository.Concept{ID: uuid.New(), Name: "Python"},
+		ml: repository.Concept{ID: uuid.New(), Name: "Machine Learning",
+			AltLabels: []repository.Label{{Value: "ML"}}},
+	}
+	repo := &fakeTaxonomyRepo{concepts: []repository.Concept{c.golang, c.ml, c.programming, c.python}}
+	return NewService(repo, slog.New(slog.NewTextHandler(io.Discard, nil))), repo, c
+}
+
+func importTurtle(t *testing.T, svc *Service, turtle string) (*ImportResult, error) {
+	t.Helper()
+	return svc.Import(context.Background(), ImportParams{AdminID: uuid.NewString(), Turtle: []byte(turtle)})
+}
+
+const turtlePrefixes = `
+@prefix skos: <http://www.w3.org/2004/02/skos/core#> .
+@prefix ex: <https://example.org/skills/> .
+`
+
+func TestImport_ResolvesLabelsAndFoldsNarrower(t *testing.T) {
+	svc, repo, c := newTestService(t)
+
+	result, err := importTurtle(t, svc, turtlePrefixes+`
+ex:programming skos:prefLabel "programming" ;
+    skos:narrower ex:go , ex:python .
+ex:go skos:prefLabel "Go" ;
+    skos:altLabel "Golang" , "golang"@en , "GO" ;
+    skos:related ex:python .
+ex:python skos:prefLabel "Python" .
+`)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if *result != (ImportResult{Concepts: 3, Broader: 2, Related: 1, AltLabels: 1}) {
+		t.Errorf("result = %+v", *result)
+	}
+
+	got := map[uuid.UUID]repository.Concept{}
+	for _, concept := range repo.replaced[0].Concepts {
+		got[concept.ID] = concept
+	}
+	if goConcept := got[c.golang.ID]; !slices.Equal(goConcept.Broader, []uuid.UUID{c.programming.ID}) ||
+		!slices.Equal(goConcept.Related, []uuid.UUID{c.python.ID}) ||
+		!slices.Equal(goConcept.AltLabels, []repository.Label{{Value: "Golang"}}) {
+		t.Errorf("Go = %+v", goConcept)
+	}
+	if python := got[c.python.ID]; !slices.Equal(python.Broader, []uuid.UUID{c.programming.ID}) {
+		t.Errorf("Python broader = %v", python.Broader)
+	}
+	if programming, ok := got[c.programming.ID]; !ok || len(programming.Broader) != 0 {
+		t.Errorf("Programming = %+v, want it described with no broader skills", programming)
+	}
+	if _, ok := got[c.ml.ID]; ok {
+		t.Error("Machine Learning is not in the document and must be left untouched")
+	}
+}
+
+func TestImport_RejectsInvalidTaxonomies(t *testing.T) {
+	svc, repo, c := newTestService(t)
+	repo.concepts[2].Broader = []uuid.UUID{c.golang.ID} // Programming below Go
+
+	for _, tc := range []struct {
+		name   string
+		turtle string
+		want   error
+	}{
+		{"syntax", `ex:go skos:broader`, ErrInvalidTaxonomy},
+		{"unknown skill", turtlePrefixes + `ex:rust skos:prefLabel "Rust" .`, ErrUnknownConcept},
+		{"unknown skill IRI", turtlePrefixes + `<` + ontology.SkillIRI(uuid.New()) + `> skos:altLabel "x" .`, ErrUnknownConcept},
+		{"no label", turtlePrefixes + `ex:go skos:broader ex:programming .`, ErrUnknownConcept},
+		{"cycle through untouched skill", turtlePrefixes + `
+ex:go skos:prefLabel "Go" ; skos:broader <` + ontology.SkillIRI(c.programming.ID) + `> .`, repository.ErrTaxonomyCycle},
+		{"self", turtlePrefixes + `ex:go skos:prefLabel "Go" ; skos:related ex:go .`, repository.ErrTaxonomyCycle},
+		{"label names another skill", turtlePrefixes + `ex:go skos:prefLabel "Go" ; skos:altLabel "Python" .`, repository.ErrLabelTaken},
+		{"label held by untouched skill", turtlePrefixes + `ex:go skos:prefLabel "Go" ; skos:altLabel "ml" .`, repository.ErrLabelTaken},
+		{"literal broader", turtlePrefixes + `ex:go skos:prefLabel "Go" ; skos:broader "Programming" .`, ErrInvalidTaxonomy},
+		{"empty", turtlePrefixes, ErrInvalidTaxonomy},
+	} {
+		if _, err := importTurtle(t, svc, tc.turtle); !errors.Is(err, tc.want) {
+			t.Errorf("%s: Import = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+	if len(repo.replaced) != 0 {
+		t.Errorf("rejected imports wrote %d times", len(repo.replaced))
+	}
+
+	// Moving a synonym between two skills in the same import is fine.
+	if _, err := importTurtle(t, svc, turtlePrefixes+`
+ex:ml skos:prefLabel "Machine Learning" .
+ex:python skos:prefLabel "Python" ; skos:altLabel "ML" .`); err != nil {
+		t.Errorf("moving a label: %v", err)
+	}
+}
+
+func TestExport_RoundTrips(t *testing.T) {
+	svc, repo, c := newTestService(t)
+	repo.concepts[0].Broader = []uuid.UUID{c.programming.ID}
+	repo.concepts[0].Related = []uuid.UUID{c.python.ID}
+	repo.concepts[0].AltLabels = []repository.Label{{Value: "Golang"}, {Value: "Go-Sprache", Language: "de"}}
+	repo.concepts[3].Related = []uuid.UUID{c.golang.ID}
+
+	var buf bytes.Buffer
+	if err := svc.Export(context.Background(), &buf); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if !strings.Contains(buf.String(), `skos:prefLabel "Go" ;`) || !strings.Contains(buf.String(), `"Go-Sprache"@de`) {
+		t.Errorf("export does not abbreviate SKOS terms:\n%s", buf.String())
+	}
+
+	concepts, err := decodeTaxonomy(buf.Bytes(), repo.concepts)
+	if err != nil {
+		t.Fatalf("decode export: %v\n%s", err, buf.String())
+	}
+	want := slices.Clone(repo.concepts)
+	slices.SortFunc(want, func(a, b repository.Concept) int { return strings.Compare(a.Name, b.Name) })
+	if len(concepts) != len(want) {
+		t.Fatalf("decoded %d concepts, want %d", len(concepts), len(want))
+	}
+	for i := range want {
+		got := concepts[i]
+		if got.ID != want[i].ID || !slices.Equal(got.Broader, want[i].Broader) ||
+			!slices.Equal(got.Related, want[i].Related) || !slices.Equal(got.AltLabels, want[i].AltLabels) {
+			t.Errorf("concept %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestMerge_ValidatesIDs(t *testing.T) {
+	svc, repo, c := newTestService(t)
+	admin := uuid.NewString()
+
+	for _, params := range []struct {
+		params MergeParams
+		want   error
+	}{
+		{MergeParams{AdminID: "nope", SourceID: c.golang.ID.String(), TargetID: c.python.ID.String()}, ErrInvalidAdminID},
+		{MergeParams{AdminID: admin, SourceID: "nope", TargetID: c.python.ID.String()}, ErrInvalidSkillID},
+		{MergeParams{AdminID: admin, SourceID: c.golang.ID.String(), TargetID: c.golang.ID.String()}, ErrSameSkill},
+	} {
+		if _, err := svc.Merge(context.Background(), params.params); !errors.Is(err, params.want) {
+			t.Errorf("Merge(%+v) = %v, want %v", params.params, err, params.want)
+		}
+	}
+
+	result, err := svc.Merge(context.Background(), MergeParams{
+		AdminID: admin, SourceID: c.golang.ID.String(), TargetID: c.python.ID.String(),
+	})
+	if err != nil || result.UserSkills != 2 {
+		t.Fatalf("Merge = %+v, %v", result, err)
+	}
+	if len(repo.merged) != 1 || repo.merged[0].SourceID != c.golang.ID || repo.merged[0].TargetID != c.python.ID {
+		t.Errorf("repository saw %+v", repo.merged)
+	}
+}
+
+func TestExpandTerm(t *testing.T) {
+	svc, repo, c := newTestService(t)
+	ctx := context.Background()
+
+	expansions, err := svc.ExpandTerm(ctx, "  go ", repository.ExpandOptions{Narrower: true, MaxDepth: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expansions) != 1 || expansions[0].SkillID != c.golang.ID {
+		t.Errorf("expansions = %+v", expansions)
+	}
+	if repo.expanded[0].MaxDepth != maxExpandDepth {
+		t.Errorf("MaxDepth = %d, want it capped at %d", repo.expanded[0].MaxDepth, maxExpandDepth)
+	}
+
+	if expansions, err := svc.ExpandTerm(ctx, "underwater basket weaving", repository.ExpandOptions{}); err != nil || expansions != nil {
+		t.Errorf("unknown term = %v, %v; want nil so search falls back to text", expansions, err)
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	got := SearchTerms([]repository.Expansion{
+		{Labels: []string{"Go", "Golang"}},
+		{Labels: []string{"Programming", "golang", "Coding"}},
+	})
+	if want := []string{"Go", "Golang", "Programming", "Coding"}; !slices.Equal(got, want) {
+		t.Errorf("SearchTerms = %v, want %v", got, want)
+	}
+}
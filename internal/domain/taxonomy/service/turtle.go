@@ -0,0 +1,283 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/taxonomy/repository"
+	"github.com/FACorreiaa/skillsphere-api/internal/ontology"
+)
+
+var (
+	rdfType      = ontology.IRI(ontology.RDFNS + "type")
+	skosConcept  = ontology.IRI(ontology.SKOSNS + "Concept")
+	skosPref     = ontology.IRI(ontology.SKOSNS + "prefLabel")
+	skosAlt      = ontology.IRI(ontology.SKOSNS + "altLabel")
+	skosBroader  = ontology.IRI(ontology.SKOSNS + "broader")
+	skosNarrower = ontology.IRI(ontology.SKOSNS + "narrower")
+	skosRelated  = ontology.IRI(ontology.SKOSNS + "related")
+)
+
+// encodeTaxonomy writes one skos:Concept per skill, identified by its
+// catalogue IRI so that a re-import matches it exactly.
+func encodeTaxonomy(w io.Writer, concepts []repository.Concept) error {
+	var triples []ontology.Triple
+	for _, concept := range concepts {
+		subject := ontology.IRI(ontology.SkillIRI(concept.ID))
+		add := func(predicate, object ontology.Term) {
+			triples = append(triples, ontology.Triple{Subject: subject, Predicate: predicate, Object: object})
+		}
+		add(rdfType, skosConcept)
+		add(skosPref, ontology.Literal(concept.Name, ""))
+		for _, label := range concept.AltLabels {
+			add(skosAlt, labelTerm(label))
+		}
+		for _, id := range concept.Broader {
+			add(skosBroader, ontology.IRI(ontology.SkillIRI(id)))
+		}
+		for _, id := range concept.Related {
+			add(skosRelated, ontology.IRI(ontology.SkillIRI(id)))
+		}
+	}
+	return ontology.WriteTurtle(w, triples)
+}
+
+func labelTerm(label repository.Label) ontology.Term {
+	if label.Language != "" {
+		return ontology.LangLiteral(label.Value, label.Language)
+	}
+	return ontology.Literal(label.Value, "")
+}
+
+// decodeTaxonomy turns a Turtle document into the concepts whose relations it
+// replaces. Every node used with skos:prefLabel, altLabel, broader, narrower
+// or related is matched to a catalogue skill: skill IRIs by id, anything else
+// by its skos:prefLabel against skill names and existing alternative labels,
+// so taxonomies authored elsewhere can be imported. "A skos:narrower B" is
+// read as "B skos:broader A". Other statements are ignored.
+func decodeTaxonomy(data []byte, existing []repository.Concept) ([]repository.Concept, error) {
+	triples, err := ontology.ParseTurtle(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTaxonomy, err)
+	}
+
+	byID := make(map[uuid.UUID]*repository.Concept, len(existing))
+	byLabel := make(map[string][]uuid.UUID)
+	for i := range existing {
+		concept := &existing[i]
+		byID[concept.ID] = concept
+		byLabel[repository.NormalizeLabel(concept.Name)] = append(byLabel[repository.NormalizeLabel(concept.Name)], concept.ID)
+		for _, label := range concept.AltLabels {
+			key := repository.NormalizeLabel(label.Value)
+			if !slices.Contains(byLabel[key], concept.ID) {
+				byLabel[key] = append(byLabel[key], concept.ID)
+			}
+		}
+	}
+
+	prefLabels := make(map[ontology.Term]string)
+	var nodes []ontology.Term
+	described := make(map[ontology.Term]bool)
+	describe := func(node ontology.Term) {
+		if !described[node] {
+			described[node] = true
+			nodes = append(nodes, node)
+		}
+	}
+	for _, t := range triples {
+		switch t.Predicate {
+		case skosPref:
+			if t.Object.Kind != ontology.LiteralTerm {
+				return nil, fmt.Errorf("%w: skos:prefLabel of %s must be a literal", ErrInvalidTaxonomy, t.Subject.Value)
+			}
+			prefLabels[t.Subject] = t.Object.Value
+			describe(t.Subject)
+		case skosAlt, skosBroader, skosRelated:
+			describe(t.Subject)
+		case skosNarrower:
+			describe(t.Object)
+		}
+	}
+
+	// Resolve every described node up front so all unknown skills are reported together.
+	ids := make(map[ontology.Term]uuid.UUID, len(nodes))
+	var unknown, ambiguous []string
+	for _, node := range nodes {
+		if node.Kind == ontology.IRITerm {
+			if id, ok := ontology.ParseSkillIRI(node.Value); ok {
+				if _, exists := byID[id]; !exists {
+					unknown = append(unknown, node.Value)
+					continue
+				}
+				ids[node] = id
+				continue
+			}
+		}
+		label, ok := prefLabels[node]
+		if !ok {
+			unknown = append(unknown, node.Value)
+			continue
+		}
+		switch matches := byLabel[repository.NormalizeLabel(label)]; len(matches) {
+		case 0:
+			unknown = append(unknown, label)
+		case 1:
+			ids[node] = matches[0]
+		default:
+			ambiguous = append(ambiguous, label)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownConcept, strings.Join(unknown, ", "))
+	}
+	if len(ambiguous) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAmbiguousLabel, strings.Join(ambiguous, ", "))
+	}
+
+	drafts := make(map[uuid.UUID]*repository.Concept)
+	draft := func(node ontology.Term) *repository.Concept {
+		id := ids[node]
+		if drafts[id] == nil {
+			drafts[id] = &repository.Concept{ID: id, Name: byID[id].Name}
+		}
+		return drafts[id]
+	}
+	for _, node := range nodes {
+		draft(node)
+	}
+
+	labelOwner := make(map[string]uuid.UUID)
+	for _, t := range triples {
+		switch t.Predicate {
+		case skosBroader, skosNarrower, skosRelated:
+			if t.Object.Kind == ontology.LiteralTerm {
+				return nil, fmt.Errorf("%w: %s of %s must be a concept, not a literal",
+					ErrInvalidTaxonomy, compact(t.Predicate), t.Subject.Value)
+			}
+			object, ok := ids[t.Object]
+			if !ok {
+				// Objects that are never described still have to be skills.
+				if id, isSkill := ontology.ParseSkillIRI(t.Object.Value); isSkill && byID[id] != nil {
+					object = id
+				} else {
+					return nil, fmt.Errorf("%w: %s", ErrUnknownConcept, t.Object.Value)
+				}
+			}
+			subject := ids[t.Subject]
+			if t.Predicate == skosNarrower {
+				subject, object = object, subject
+			}
+			if subject == object {
+				return nil, fmt.Errorf("%w: %q refers to itself through %s", repository.ErrTaxonomyCycle,
+					byID[subject].Name, compact(t.Predicate))
+			}
+			concept := drafts[subject]
+			if t.Predicate == skosRelated {
+				concept.Related = appendUnique(concept.Related, object)
+			} else {
+				concept.Broader = appendUnique(concept.Broader, object)
+			}
+		case skosAlt:
+			if t.Object.Kind != ontology.LiteralTerm {
+				return nil, fmt.Errorf("%w: skos:altLabel of %s must be a literal", ErrInvalidTaxonomy, t.Subject.Value)
+			}
+			concept := draft(t.Subject)
+			key := repository.NormalizeLabel(t.Object.Value)
+			if key == "" || key == repository.NormalizeLabel(concept.Name) {
+				continue
+			}
+			if owner, taken := labelOwner[key]; taken {
+				if owner == concept.ID {
+					continue
+				}
+				return nil, fmt.Errorf("%w: %q", repository.ErrLabelTaken, t.Object.Value)
+			}
+			// A label may not name another skill, or resolving it would be ambiguous.
+			for _, id := range byLabel[key] {
+				if id != concept.ID && (drafts[id] == nil || repository.NormalizeLabel(byID[id].Name) == key) {
+					return nil, fmt.Errorf("%w: %q", repository.ErrLabelTaken, t.Object.Value)
+				}
+			}
+			labelOwner[key] = concept.ID
+			concept.AltLabels = append(concept.AltLabels, repository.Label{
+				Value:    strings.Join(strings.Fields(t.Object.Value), " "),
+				Language: t.Object.Language,
+			})
+		}
+	}
+
+	concepts := make([]repository.Concept, 0, len(drafts))
+	for _, concept := range drafts {
+		concepts = append(concepts, *concept)
+	}
+	sort.Slice(concepts, func(i, j int) bool {
+		if concepts[i].Name != concepts[j].Name {
+			return concepts[i].Name < concepts[j].Name
+		}
+		return concepts[i].ID.String() < concepts[j].ID.String()
+	})
+	return concepts, nil
+}
+
+// checkAcyclic rejects imports whose broader relations, combined with those of
+// the skills left untouched, would make a skill broader than itself.
+func checkAcyclic(existing, replaced []repository.Concept) error {
+	broader := make(map[uuid.UUID][]uuid.UUID, len(existing))
+	names := make(map[uuid.UUID]string, len(existing))
+	for _, concept := range existing {
+		broader[concept.ID] = concept.Broader
+		names[concept.ID] = concept.Name
+	}
+	for _, concept := range replaced {
+		broader[concept.ID] = concept.Broader
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[uuid.UUID]int, len(broader))
+	var visit func(id uuid.UUID) error
+	visit = func(id uuid.UUID) error {
+		switch state[id] {
+		case visiting:
+			return fmt.Errorf("%w: %q is broader than itself", repository.ErrTaxonomyCycle, names[id])
+		case done:
+			return nil
+		}
+		state[id] = visiting
+		for _, parent := range broader[id] {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		state[id] = done
+		return nil
+	}
+	for _, concept := range replaced {
+		if err := visit(concept.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendUnique(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	if slices.Contains(ids, id) {
+		return ids
+	}
+	return append(ids, id)
+}
+
+func compact(term ontology.Term) string {
+	if local, ok := strings.CutPrefix(term.Value, ontology.SKOSNS); ok {
+		return "skos:" + local
+	}
+	return term.Value
+}
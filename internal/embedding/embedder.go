@@ -0,0 +1,129 @@
+package embedding
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Cache holds vectors by Hash.
+type Cache interface {
+	Get(key string) ([]float32, bool)
+	Add(key string, vector []float32)
+}
+
+// MemoryCache is a least-recently-used Cache of a fixed number of vectors.
+type MemoryCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key    string
+	vector []float32
+}
+
+// NewMemoryCache returns a cache holding up to size vectors, about 3 KiB each.
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{size: max(size, 1), order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Get implements Cache.
+func (c *MemoryCache) Get(key string) ([]float32, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).vector, true
+}
+
+// Add implements Cache.
+func (c *MemoryCache) Add(key string, vector []float32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*cacheEntry).vector = vector
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, vector: vector})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Embedder batches requests to a provider and caches its vectors by content
+// hash. Callers must not modify returned vectors, which may be shared.
+type Embedder struct {
+	provider  Provider
+	cache     Cache
+	batchSize int
+}
+
+// NewEmbedder wraps provider. Batches are capped at the provider's MaxBatch;
+// a nil cache disables caching.
+func NewEmbedder(provider Provider, cache Cache, batchSize int) *Embedder {
+	if batchSize <= 0 || batchSize > provider.MaxBatch() {
+		batchSize = provider.MaxBatch()
+	}
+	return &Embedder{provider: provider, cache: cache, batchSize: batchSize}
+}
+
+// Model is the provider's model.
+func (e *Embedder) Model() string {
+	return e.provider.Model()
+}
+
+// Embed returns a vector per text, in order. Duplicate and cached texts are
+// not sent to the provider.
+func (e *Embedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	model := e.provider.Model()
+	out := make([][]float32, len(texts))
+	pending := make(map[string][]int)
+	var missing []string
+	for i, text := range texts {
+		key := Hash(model, text)
+		if e.cache != nil {
+			if vector, ok := e.cache.Get(key); ok {
+				out[i] = vector
+				continue
+			}
+		}
+		if _, seen := pending[key]; !seen {
+			missing = append(missing, text)
+		}
+		pending[key] = append(pending[key], i)
+	}
+
+	for start := 0; start < len(missing); start += e.batchSize {
+		batch := missing[start:min(start+e.batchSize, len(missing))]
+		vectors, err := e.provider.Embed(ctx, batch)
+		if err != nil {
+			return nil, fmt.Errorf("embed with %s: %w", model, err)
+		}
+		if len(vectors) != len(batch) {
+			return nil, fmt.Errorf("embed with %s: got %d vectors for %d texts", model, len(vectors), len(batch))
+		}
+		for i, vector := range vectors {
+			if len(vector) != Dimensions {
+				return nil, fmt.Errorf("%w: %s returned %d", ErrDimensions, model, len(vector))
+			}
+			key := Hash(model, batch[i])
+			for _, at := range pending[key] {
+				out[at] = vector
+			}
+			if e.cache != nil {
+				e.cache.Add(key, vector)
+			}
+		}
+	}
+	return out, nil
+}
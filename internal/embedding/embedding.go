@@ -0,0 +1,56 @@
+// Package embedding turns skill and profile text into the 768-dimensional
+// vectors stored in skills.embedding and users.embedding.
+//
+// A Provider computes vectors; Local does so offline and deterministically,
+// Gemini calls the Gemini API. An Embedder adds batching and a cache keyed by
+// content hash in front of any provider, and a Reembedder keeps the stored
+// vectors current as skills and profiles change.
+package embedding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+)
+
+// Dimensions is the length of every vector, matching the VECTOR(768) columns.
+const Dimensions = 768
+
+// ErrDimensions is returned when a provider returns vectors of another length.
+var ErrDimensions = errors.New("embedding has the wrong number of dimensions")
+
+// Provider computes embeddings.
+type Provider interface {
+	// Model names the model and version. Vectors from different models are
+	// not comparable, so stored vectors are recomputed when it changes.
+	Model() string
+	// MaxBatch is the most texts Embed accepts at once.
+	MaxBatch() int
+	// Embed returns one unit-length vector of Dimensions per text, in order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Hash identifies text embedded by model. It keys the cache and is stored as
+// embedding_hash, so unchanged content is never embedded twice.
+func Hash(model, text string) string {
+	sum := sha256.Sum256([]byte(model + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalize scales v to unit length in place; a zero vector is left as is.
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	norm := math.Sqrt(sum)
+	for i := range v {
+		v[i] = float32(float64(v[i]) / norm)
+	}
+	return v
+}
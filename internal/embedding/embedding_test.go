@@ -0,0 +1,150 @@
+package embedding
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func cosine(a, b []float32) float64 {
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
+
+func TestLocal_DeterministicUnitVectors(t *testing.T) {
+	ctx := context.Background()
+	vectors, err := NewLocal().Embed(ctx, []string{"Golang", "Go", "Watercolour painting", "golang", "  "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := NewLocal().Embed(ctx, []string{"Golang"})
+	if !slices.Equal(vectors[0], again[0]) || !slices.Equal(vectors[0], vectors[3]) {
+		t.Error("the same text, in any case, must embed to the same vector")
+	}
+	for i, v := range vectors[:4] {
+		if len(v) != Dimensions {
+			t.Fatalf("vector %d has %d dimensions", i, len(v))
+		}
+		if norm := math.Sqrt(cosine(v, v)); math.Abs(norm-1) > 1e-5 {
+			t.Errorf("vector %d has norm %v", i, norm)
+		}
+	}
+	if slices.ContainsFunc(vectors[4], func(x float32) bool { return x != 0 }) {
+		t.Error("blank text should embed to the zero vector")
+	}
+
+	related, unrelated := cosine(vectors[0], vectors[1]), cosine(vectors[0], vectors[2])
+	if related <= unrelated {
+		t.Errorf("Golang~Go = %v, Golang~Watercolour = %v; shared n-grams should count", related, unrelated)
+	}
+}
+
+// countingProvider records the batches it is asked to embed.
+type countingProvider struct {
+	Local
+	batches [][]string
+}
+
+func (p *countingProvider) MaxBatch() int { return 2 }
+
+func (p *countingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	p.batches = append(p.batches, texts)
+	return p.Local.Embed(ctx, texts)
+}
+
+func TestEmbedder_BatchesDeduplicatesAndCaches(t *testing.T) {
+	ctx := context.Background()
+	provider := &countingProvider{}
+	embedder := NewEmbedder(provider, NewMemoryCache(10), 50)
+
+	vectors, err := embedder.Embed(ctx, []string{"Go", "Rust", "Go", "Zig", "Elm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) != 5 || !slices.Equal(vectors[0], vectors[2]) {
+		t.Fatalf("vectors = %d, duplicates must share a vector", len(vectors))
+	}
+	if len(provider.batches) != 2 || len(provider.batches[0]) != 2 || len(provider.batches[1]) != 2 {
+		t.Errorf("batches = %v, want four distinct texts in batches of the provider's maximum", provider.batches)
+	}
+
+	if _, err := embedder.Embed(ctx, []string{"Rust", "Haskell"}); err != nil {
+		t.Fatal(err)
+	}
+	if last := provider.batches[len(provider.batches)-1]; len(provider.batches) != 3 || !slices.Equal(last, []string{"Haskell"}) {
+		t.Errorf("batches = %v, want only the uncached text sent", provider.batches)
+	}
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Add("a", []float32{1})
+	cache.Add("b", []float32{2})
+	cache.Get("a")
+	cache.Add("c", []float32{3})
+	if _, ok := cache.Get("b"); ok {
+		t.Error("b was least recently used and should be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("a was used recently and should be kept")
+	}
+}
+
+type fakeStore struct {
+	docs  map[Target][]Document
+	saved map[Target][]Embedded
+}
+
+func (s *fakeStore) Stale(_ context.Context, target Target, _ string, limit int) ([]Document, error) {
+	docs := s.docs[target]
+	n := min(limit, len(docs))
+	s.docs[target] = docs[n:]
+	return docs[:n], nil
+}
+
+func (s *fakeStore) Save(_ context.Context, target Target, _ string, embedded []Embedded) error {
+	s.saved[target] = append(s.saved[target], embedded...)
+	return nil
+}
+
+func TestReembedder_OnlyEmbedsChangedContent(t *testing.T) {
+	provider := &countingProvider{}
+	embedder := NewEmbedder(provider, nil, 0)
+	unchanged, changed, emptied := uuid.New(), uuid.New(), uuid.New()
+	store := &fakeStore{
+		docs: map[Target][]Document{
+			TargetSkills: {
+				{ID: unchanged, Content: "Go\nA programming language", Hash: Hash(LocalModel, "Go\nA programming language")},
+				{ID: changed, Content: "Spanish", Hash: Hash(LocalModel, "Castilian")},
+			},
+			TargetUsers: {{ID: emptied, Content: "", Hash: Hash(LocalModel, "Offers: Go")}},
+		},
+		saved: map[Target][]Embedded{},
+	}
+
+	n, err := NewReembedder(store, embedder, 1, nil).Reembed(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || len(provider.batches) != 1 || provider.batches[0][0] != "Spanish" {
+		t.Errorf("embedded %d via %v, want only Spanish", n, provider.batches)
+	}
+
+	skills := store.saved[TargetSkills]
+	if len(skills) != 2 || !skills[0].Unchanged || skills[0].Vector != nil {
+		t.Fatalf("skills = %+v, want the unchanged one kept", skills)
+	}
+	if skills[1].Unchanged || len(skills[1].Vector) != Dimensions || skills[1].Hash != Hash(LocalModel, "Spanish") {
+		t.Errorf("changed skill = %+v", skills[1])
+	}
+	users := store.saved[TargetUsers]
+	if len(users) != 1 || users[0].Unchanged || users[0].Vector != nil {
+		t.Errorf("users = %+v, want the emptied profile's embedding cleared", users)
+	}
+}
@@ -0,0 +1,149 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultGeminiModel produces 768-dimensional vectors natively.
+	DefaultGeminiModel   = "text-embedding-004"
+	DefaultGeminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+	// geminiMaxBatch is the API's limit on requests per batchEmbedContents call.
+	geminiMaxBatch = 100
+)
+
+// GeminiConfig configures the Gemini provider.
+type GeminiConfig struct {
+	APIKey string
+	// Model defaults to DefaultGeminiModel.
+	Model string
+	// BaseURL defaults to DefaultGeminiBaseURL.
+	BaseURL string
+	Timeout time.Duration
+	// Client overrides the HTTP client, mainly for tests.
+	Client *http.Client
+}
+
+// Gemini embeds text with the Gemini batchEmbedContents API.
+type Gemini struct {
+	apiKey  string
+	model   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewGemini builds a Gemini provider.
+func NewGemini(cfg GeminiConfig) (*Gemini, error) {
+	if cfg.APIKey == "" {
+		return nil, errors.New("gemini: api key required")
+	}
+	g := &Gemini{apiKey: cfg.APIKey, model: cfg.Model, baseURL: strings.TrimRight(cfg.BaseURL, "/"), client: cfg.Client}
+	if g.model == "" {
+		g.model = DefaultGeminiModel
+	}
+	if g.baseURL == "" {
+		g.baseURL = DefaultGeminiBaseURL
+	}
+	if g.client == nil {
+		timeout := cfg.Timeout
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+		g.client = &http.Client{Timeout: timeout}
+	}
+	return g, nil
+}
+
+// Model implements Provider.
+func (g *Gemini) Model() string { return "gemini/" + g.model }
+
+// MaxBatch implements Provider.
+func (g *Gemini) MaxBatch() int { return geminiMaxBatch }
+
+type geminiContent struct {
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiEmbedRequest struct {
+	Model                string        `json:"model"`
+	Content              geminiContent `json:"content"`
+	TaskType             string        `json:"taskType"`
+	OutputDimensionality int           `json:"outputDimensionality"`
+}
+
+type geminiBatchRequest struct {
+	Requests []geminiEmbedRequest `json:"requests"`
+}
+
+type geminiBatchResponse struct {
+	Embeddings []struct {
+		Values []float32 `json:"values"`
+	} `json:"embeddings"`
+}
+
+// Embed implements Provider. Vectors are normalised, as the API only
+// normalises them at their full size.
+func (g *Gemini) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) > geminiMaxBatch {
+		return nil, fmt.Errorf("gemini: %d texts exceed the batch limit of %d", len(texts), geminiMaxBatch)
+	}
+	body := geminiBatchRequest{Requests: make([]geminiEmbedRequest, len(texts))}
+	for i, text := range texts {
+		body.Requests[i] = geminiEmbedRequest{
+			Model:                "models/" + g.model,
+			Content:              geminiContent{Parts: []geminiPart{{Text: text}}},
+			TaskType:             "SEMANTIC_SIMILARITY",
+			OutputDimensionality: Dimensions,
+		}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/models/%s:batchEmbedContents", g.baseURL, g.model)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", g.apiKey)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gemini: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("gemini: %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+
+	var out geminiBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("gemini: decode response: %w", err)
+	}
+	if len(out.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("gemini: got %d embeddings for %d texts", len(out.Embeddings), len(texts))
+	}
+	vectors := make([][]float32, len(texts))
+	for i, e := range out.Embeddings {
+		if len(e.Values) != Dimensions {
+			return nil, fmt.Errorf("%w: gemini returned %d", ErrDimensions, len(e.Values))
+		}
+		vectors[i] = normalize(e.Values)
+	}
+	return vectors, nil
+}
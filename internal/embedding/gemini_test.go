@@ -0,0 +1,73 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGemini_BatchEmbedContents(t *testing.T) {
+	var got geminiBatchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/text-embedding-004:batchEmbedContents" || r.Header.Get("x-goog-api-key") != "key" {
+			http.Error(w, "unexpected request "+r.URL.Path, http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var resp geminiBatchResponse
+		for range got.Requests {
+			values := make([]float32, Dimensions)
+			values[0], values[1] = 3, 4
+			resp.Embeddings = append(resp.Embeddings, struct {
+				Values []float32 `json:"values"`
+			}{values})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	provider, err := NewGemini(GeminiConfig{APIKey: "key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.Model() != "gemini/text-embedding-004" {
+		t.Errorf("model = %q", provider.Model())
+	}
+
+	vectors, err := provider.Embed(context.Background(), []string{"Go", "Spanish"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Requests) != 2 || got.Requests[1].Content.Parts[0].Text != "Spanish" ||
+		got.Requests[0].Model != "models/text-embedding-004" || got.Requests[0].OutputDimensionality != Dimensions {
+		t.Errorf("request = %+v", got)
+	}
+	if len(vectors) != 2 || math.Abs(float64(vectors[0][0])-0.6) > 1e-6 || math.Abs(float64(vectors[0][1])-0.8) > 1e-6 {
+		t.Errorf("vectors should be normalised, got %v", vectors[0][:2])
+	}
+}
+
+func TestGemini_Errors(t *testing.T) {
+	if _, err := NewGemini(GeminiConfig{}); err == nil {
+		t.Error("a key is required")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"embeddings":[{"values":[1,2,3]}]}`))
+	}))
+	defer server.Close()
+	provider, _ := NewGemini(GeminiConfig{APIKey: "key", BaseURL: server.URL})
+	if _, err := provider.Embed(context.Background(), []string{"Go"}); !errors.Is(err, ErrDimensions) {
+		t.Errorf("short vector = %v, want ErrDimensions", err)
+	}
+	if _, err := provider.Embed(context.Background(), make([]string, geminiMaxBatch+1)); err == nil {
+		t.Error("oversized batches must be refused")
+	}
+}
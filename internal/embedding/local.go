@@ -0,0 +1,74 @@
+package embedding
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// LocalModel is the model name of Local vectors.
+const LocalModel = "local-ngram-v1"
+
+// Local embeds text without a network call by hashing words and character
+// n-grams into Dimensions buckets, a random projection of the sparse n-gram
+// counts. The same text always yields the same vector, so development and CI
+// get stable results without an API key. Similarity is lexical: "Golang" is
+// close to "Go", but "ML" is not close to "machine learning".
+type Local struct{}
+
+// NewLocal returns the offline provider.
+func NewLocal() Local {
+	return Local{}
+}
+
+// Model implements Provider.
+func (Local) Model() string { return LocalModel }
+
+// MaxBatch implements Provider; Local has no batch limit.
+func (Local) MaxBatch() int { return 1 << 16 }
+
+// Embed implements Provider. Text with no letters or digits embeds to the
+// zero vector.
+func (Local) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out[i] = localVector(text)
+	}
+	return out, nil
+}
+
+// localNGrams are the character n-gram lengths hashed for every word.
+var localNGrams = []int{2, 3, 4}
+
+func localVector(text string) []float32 {
+	v := make([]float32, Dimensions)
+	add := func(feature string, weight float32) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		// The top bit picks the sign so colliding features tend to cancel
+		// rather than pile up.
+		if sum>>63 == 1 {
+			weight = -weight
+		}
+		v[sum%Dimensions] += weight
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		add("w:"+word, 1)
+		runes := []rune(" " + word + " ")
+		for _, n := range localNGrams {
+			for i := 0; i+n <= len(runes); i++ {
+				add(string(rune('0'+n))+":"+string(runes[i:i+n]), 0.5)
+			}
+		}
+	}
+	return normalize(v)
+}
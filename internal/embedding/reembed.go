@@ -0,0 +1,151 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Target is a table whose rows carry an embedding.
+type Target string
+
+const (
+	TargetSkills Target = "skills"
+	TargetUsers  Target = "users"
+)
+
+// Targets are re-embedded in this order.
+var Targets = []Target{TargetSkills, TargetUsers}
+
+// Document is a row whose embedding may be out of date.
+type Document struct {
+	ID      uuid.UUID
+	Content string
+	// Hash is the stored embedding_hash, empty when never embedded.
+	Hash string
+}
+
+// Embedded is the new embedding state of a document.
+type Embedded struct {
+	ID   uuid.UUID
+	Hash string
+	// Vector is nil when the content is empty, clearing the embedding.
+	Vector []float32
+	// Unchanged keeps the stored vector, whose content hash still matches.
+	Unchanged bool
+}
+
+// Store reads and writes embeddings.
+type Store interface {
+	// Stale returns up to limit documents that changed since they were
+	// embedded, or were embedded by another model.
+	Stale(ctx context.Context, target Target, model string, limit int) ([]Document, error)
+	// Save stores embeddings and marks the documents current for model.
+	Save(ctx context.Context, target Target, model string, embedded []Embedded) error
+}
+
+// Reembedder keeps stored embeddings current. A change that leaves the
+// embedded text as it was, such as a login bumping users.updated_at, only
+// refreshes the bookkeeping.
+type Reembedder struct {
+	store     Store
+	embedder  *Embedder
+	batchSize int
+	logger    *slog.Logger
+}
+
+// NewReembedder builds the background job; batchSize rows are read per query.
+func NewReembedder(store Store, embedder *Embedder, batchSize int, logger *slog.Logger) *Reembedder {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	if batchSize <= 0 {
+		batchSize = 200
+	}
+	return &Reembedder{store: store, embedder: embedder, batchSize: batchSize, logger: logger}
+}
+
+// Run re-embeds once per interval until ctx is cancelled.
+func (r *Reembedder) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := r.Reembed(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("re-embedding failed", "error", err)
+		} else if n > 0 {
+			r.logger.Info("documents re-embedded", "count", n, "model", r.embedder.Model())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reembed processes every stale document and reports how many were sent to
+// the provider.
+func (r *Reembedder) Reembed(ctx context.Context) (int, error) {
+	if r == nil || r.store == nil || r.embedder == nil {
+		return 0, errors.New("reembedder uninitialized")
+	}
+	model := r.embedder.Model()
+
+	var total int
+	for _, target := range Targets {
+		for {
+			docs, err := r.store.Stale(ctx, target, model, r.batchSize)
+			if err != nil {
+				return total, err
+			}
+			n, err := r.embed(ctx, target, model, docs)
+			total += n
+			if err != nil {
+				return total, err
+			}
+			if len(docs) < r.batchSize {
+				break
+			}
+		}
+	}
+	return total, nil
+}
+
+func (r *Reembedder) embed(ctx context.Context, target Target, model string, docs []Document) (int, error) {
+	if len(docs) == 0 {
+		return 0, nil
+	}
+	embedded := make([]Embedded, len(docs))
+	var texts []string
+	var pending []int
+	for i, doc := range docs {
+		content := strings.TrimSpace(doc.Content)
+		hash := Hash(model, content)
+		embedded[i] = Embedded{ID: doc.ID, Hash: hash}
+		switch {
+		case hash == doc.Hash:
+			embedded[i].Unchanged = true
+		case content != "":
+			texts = append(texts, content)
+			pending = append(pending, i)
+		}
+	}
+
+	vectors, err := r.embedder.Embed(ctx, texts)
+	if err != nil {
+		return 0, err
+	}
+	for i, at := range pending {
+		embedded[at].Vector = vectors[i]
+	}
+	if err := r.store.Save(ctx, target, model, embedded); err != nil {
+		return 0, err
+	}
+	return len(texts), nil
+}
@@ -0,0 +1,132 @@
+package embedding
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PostgresStore implements Store over skills and users.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore constructs the store.
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// A skill is embedded from its name and description. A user is embedded from
+// the names of the skills they offer and want, and their bio; skill changes
+// are seen through user_skill_vectors.refreshed_at.
+var staleQueries = map[Target]string{
+	TargetSkills: `
+		-- name: StaleSkillEmbeddings
+		SELECT s.id, concat_ws(E'\n', s.name, NULLIF(s.description, '')), COALESCE(s.embedding_hash, '')
+		FROM skills s
+		WHERE s.embedded_at IS NULL
+		   OR s.embedding_model IS DISTINCT FROM $1
+		   OR s.updated_at > s.embedded_at
+		ORDER BY s.id
+		LIMIT $2
+	`,
+	TargetUsers: `
+		-- name: StaleUserEmbeddings
+		SELECT u.id,
+		       concat_ws(E'\n',
+		           'Offers: ' || (SELECT string_agg(s.name, ', ' ORDER BY s.name)
+		                          FROM user_skills us JOIN skills s ON s.id = us.skill_id
+		                          WHERE us.user_id = u.id AND us.skill_type = 'offered'),
+		           'Wants: ' || (SELECT string_agg(s.name, ', ' ORDER BY s.name)
+		                         FROM user_skills us JOIN skills s ON s.id = us.skill_id
+		                         WHERE us.user_id = u.id AND us.skill_type = 'wanted'),
+		           NULLIF(u.bio, '')),
+		       COALESCE(u.embedding_hash, '')
+		FROM users u
+		LEFT JOIN user_skill_vectors v ON v.user_id = u.id
+		WHERE u.deleted_at IS NULL
+		  AND (u.embedded_at IS NULL
+		       OR u.embedding_model IS DISTINCT FROM $1
+		       OR u.updated_at > u.embedded_at
+		       OR v.refreshed_at > u.embedded_at)
+		ORDER BY u.id
+		LIMIT $2
+	`,
+}
+
+// Stale implements Store.
+func (s *PostgresStore) Stale(ctx context.Context, target Target, model string, limit int) ([]Document, error) {
+	query, ok := staleQueries[target]
+	if !ok {
+		return nil, fmt.Errorf("unknown embedding target %q", target)
+	}
+	rows, err := s.db.QueryContext(ctx, query, model, limit)
+	if err != nil {
+		return nil, fmt.Errorf("load stale %s: %w", target, err)
+	}
+	defer rows.Close()
+
+	var docs []Document
+	for rows.Next() {
+		var doc Document
+		if err := rows.Scan(&doc.ID, &doc.Content, &doc.Hash); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+// Save implements Store. The target is a fixed table name, never user input.
+func (s *PostgresStore) Save(ctx context.Context, target Target, model string, embedded []Embedded) error {
+	if target != TargetSkills && target != TargetUsers {
+		return fmt.Errorf("unknown embedding target %q", target)
+	}
+	if len(embedded) == 0 {
+		return nil
+	}
+	ids := make([]string, len(embedded))
+	hashes := make([]string, len(embedded))
+	vectors := make([]string, len(embedded))
+	keep := make([]bool, len(embedded))
+	for i, e := range embedded {
+		ids[i] = e.ID.String()
+		hashes[i] = e.Hash
+		vectors[i] = vectorLiteral(e.Vector)
+		keep[i] = e.Unchanged
+	}
+
+	query := fmt.Sprintf(`
+		-- name: SaveEmbeddings
+		UPDATE %[1]s t SET
+			embedding = CASE WHEN e.keep THEN t.embedding ELSE NULLIF(e.vector, '')::vector END,
+			embedding_hash = e.hash,
+			embedding_model = $5,
+			embedded_at = NOW()
+		FROM UNNEST($1::uuid[], $2::text[], $3::text[], $4::bool[]) AS e(id, hash, vector, keep)
+		WHERE t.id = e.id
+	`, target)
+	if _, err := s.db.ExecContext(ctx, query, ids, hashes, vectors, keep, model); err != nil {
+		return fmt.Errorf("save %s embeddings: %w", target, err)
+	}
+	return nil
+}
+
+// vectorLiteral renders a vector in pgvector's text format; nil renders empty.
+func vectorLiteral(v []float32) string {
+	if v == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(x), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package ontology
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Activity is the PROV-O record of the state transition that produced an
+// event. The triple store replaces a resource on every upsert, but each
+// activity has its own IRI and stays in the graph, so the history of a
+// resource can be read back through prov:generated.
+type Activity struct {
+	// ID identifies the activity; a random one is assigned when zero.
+	ID uuid.UUID
+	// Type is one of the Activity* constants, e.g. ActivitySessionCancelled.
+	Type string
+	// Actor is the user who caused the transition; zero for platform actions.
+	Actor uuid.UUID
+	// At is when the transition happened; the current time when zero.
+	At time.Time
+	// Reason optionally explains the transition.
+	Reason string
+}
+
+func activityIRI(id uuid.UUID) string {
+	return "sk:Activity/" + id.String()
+}
+
+// SetActivity links the event to the activity through prov:wasGeneratedBy.
+func (e *Event) SetActivity(activity Activity) {
+	if activity.Type == "" {
+		return
+	}
+	if activity.ID == uuid.Nil {
+		activity.ID = uuid.New()
+	}
+	if activity.At.IsZero() {
+		activity.At = time.Now()
+	}
+
+	record := map[string]any{
+		"@id":           activityIRI(activity.ID),
+		"@type":         []string{activity.Type, ProvActivity},
+		ProvEndedAtTime: activity.At.UTC().Format(time.RFC3339Nano),
+	}
+	if e.ID != "" {
+		record[ProvGenerated] = e.ID
+	}
+	if activity.Actor != uuid.Nil {
+		record[ProvWasAssociatedWith] = userIRI(activity.Actor)
+	}
+	if activity.Reason != "" {
+		record[PropReason] = activity.Reason
+	}
+	e.Set(ProvWasGeneratedBy, record)
+}
@@ -0,0 +1,80 @@
+package ontology
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSetActivity_RecordsProvenance(t *testing.T) {
+	sessionID, actor, activityID := uuid.New(), uuid.New(), uuid.New()
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	evt := NewSessionEvent(SessionEvent{
+		SessionID:   sessionID,
+		InitiatorID: uuid.New(),
+		PartnerID:   actor,
+		StatusIRI:   ConceptSessionStatusCancelled,
+	}, Activity{ID: activityID, Type: ActivitySessionCancelled, Actor: actor, At: at, Reason: "sick"})
+
+	triples, err := evt.Triples()
+	if err != nil {
+		t.Fatalf("Triples: %v", err)
+	}
+
+	session := IRI(SkillSphereNS + "Session/" + sessionID.String())
+	activity := IRI(SkillSphereNS + "Activity/" + activityID.String())
+	for _, want := range []Triple{
+		{session, IRI(ProvNS + "wasGeneratedBy"), activity},
+		{activity, IRI(rdfType), IRI(SkillSphereNS + "SessionCancelled")},
+		{activity, IRI(rdfType), IRI(ProvNS + "Activity")},
+		{activity, IRI(ProvNS + "generated"), session},
+		{activity, IRI(ProvNS + "wasAssociatedWith"), IRI(SkillSphereNS + "User/" + actor.String())},
+		{activity, IRI(ProvNS + "endedAtTime"), Literal("2025-03-01T12:00:00Z", XSDNS+"dateTime")},
+		{activity, IRI(SkillSphereNS + "reason"), Literal("sick", "")},
+	} {
+		if !slices.Contains(triples, want) {
+			t.Errorf("missing triple %+v", want)
+		}
+	}
+}
+
+func TestNewSessionStatusEvent_PicksActivity(t *testing.T) {
+	cases := map[string]string{
+		ConceptSessionStatusInProgress: ActivitySessionStarted,
+		ConceptSessionStatusCompleted:  ActivitySessionCompleted,
+		ConceptSessionStatusNoShow:     ActivitySessionNoShow,
+		"sk:SessionStatusPostponed":    "",
+	}
+	for status, want := range cases {
+		evt := NewSessionStatusEvent(SessionEvent{SessionID: uuid.New(), StatusIRI: status}, uuid.New())
+		record, _ := evt.Properties()[ProvWasGeneratedBy].(map[string]any)
+		var got string
+		if types, ok := record["@type"].([]string); ok {
+			got = types[0]
+		}
+		if got != want {
+			t.Errorf("status %s: activity = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestDefaultValidator_RejectsActivityForWrongClass(t *testing.T) {
+	evt := NewSessionEvent(SessionEvent{
+		SessionID:   uuid.New(),
+		InitiatorID: uuid.New(),
+		PartnerID:   uuid.New(),
+	}, Activity{Type: ActivityUserRegistered})
+
+	report := validate(t, evt)
+	if report.Conforms {
+		t.Fatal("report conforms, want sh:class violation on prov:generated")
+	}
+	for _, result := range report.Results {
+		if result.Path != ProvNS+"generated" || result.Constraint != "sh:ClassConstraintComponent" {
+			t.Errorf("unexpected result %+v", result)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package ontology
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,40 +8,67 @@ import (
 	"github.com/FACorreiaa/skillsphere-api/internal/domain/auth/repository"
 )
 
-const (
-	userTypeIRI = "sk:User"
-)
-
 func userIRI(id uuid.UUID) string {
-	return fmt.Sprintf("sk:User/%s", id.String())
+	return ClassUser + "/" + id.String()
 }
 
-// NewUserRegisteredEvent builds a JSON-LD document for a created user.
-func NewUserRegisteredEvent(user *repository.User) Event {
+// NewUserEvent builds a JSON-LD snapshot of user produced by activity.
+func NewUserEvent(user *repository.User, activity Activity) Event {
 	if user == nil {
-		return NewEvent("", userTypeIRI)
+		return NewEvent("", ClassUser)
 	}
 
-	evt := NewEvent(userIRI(user.ID), userTypeIRI)
+	evt := NewEvent(userIRI(user.ID), ClassUser)
 	evt.SetTimestamp(user.CreatedAt)
-	evt.Set("schema:email", user.Email)
-	evt.Set("schema:name", user.DisplayName)
+	evt.Set(SchemaEmail, user.Email)
+	evt.Set(SchemaName, user.DisplayName)
 	if user.Username != "" {
-		evt.Set("schema:alternateName", user.Username)
+		evt.Set(SchemaAlternateName, user.Username)
 	}
 	if user.AvatarURL != nil && *user.AvatarURL != "" {
-		evt.Set("schema:image", *user.AvatarURL)
+		evt.Set(SchemaImage, *user.AvatarURL)
 	}
 	if !user.UpdatedAt.IsZero() && !user.UpdatedAt.Equal(user.CreatedAt) {
-		evt.Set("schema:dateModified", user.UpdatedAt.UTC().Format(time.RFC3339Nano))
+		evt.Set(SchemaDateModified, user.UpdatedAt.UTC().Format(time.RFC3339Nano))
 	}
 	if user.LastLoginAt != nil {
-		evt.Set("schema:lastReviewed", user.LastLoginAt.UTC().Format(time.RFC3339Nano))
+		evt.Set(SchemaLastReviewed, user.LastLoginAt.UTC().Format(time.RFC3339Nano))
 	}
 	if user.EmailVerifiedAt != nil {
-		evt.Set("sk:isVerified", true)
+		evt.Set(PropIsVerified, true)
 	}
-	evt.Set("sk:isActive", user.IsActive)
-	evt.Set("sk:role", user.Role)
+	evt.Set(PropIsActive, user.IsActive)
+	evt.Set(PropRole, user.Role)
+	evt.SetActivity(activity)
 	return evt
 }
+
+// NewUserRegisteredEvent builds a JSON-LD document for a created user.
+func NewUserRegisteredEvent(user *repository.User) Event {
+	if user == nil {
+		return NewUserEvent(nil, Activity{})
+	}
+	return NewUserEvent(user, Activity{Type: ActivityUserRegistered, Actor: user.ID, At: user.CreatedAt})
+}
+
+// NewUserLoggedInEvent records a successful sign-in at the given time.
+func NewUserLoggedInEvent(user *repository.User, at time.Time) Event {
+	if user == nil {
+		return NewUserEvent(nil, Activity{})
+	}
+	snapshot := *user
+	snapshot.LastLoginAt = &at
+	return NewUserEvent(&snapshot, Activity{Type: ActivityUserLoggedIn, Actor: user.ID, At: at})
+}
+
+// NewUserVerifiedEvent records that the user confirmed their email address.
+func NewUserVerifiedEvent(user *repository.User) Event {
+	if user == nil {
+		return NewUserEvent(nil, Activity{})
+	}
+	activity := Activity{Type: ActivityUserVerified, Actor: user.ID}
+	if user.EmailVerifiedAt != nil {
+		activity.At = *user.EmailVerifiedAt
+	}
+	return NewUserEvent(user, activity)
+}
@@ -0,0 +1,392 @@
+package ontology
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/auth/repository"
+)
+
+const defaultBackfillBatchSize = 500
+
+// firstBackfillKey sorts before every UUID primary key.
+const firstBackfillKey = "00000000-0000-0000-0000-000000000000"
+
+// ErrBackfillComplete is returned by Backfiller.Run for a source whose
+// checkpoint is already complete; Reset it to replay the source again.
+var ErrBackfillComplete = errors.New("backfill already complete")
+
+// BackfillSource pages through one table in primary key order and turns each
+// row into an event.
+type BackfillSource struct {
+	// Name identifies the source on the command line and in checkpoints.
+	Name string
+	// Type is the @type of the events, which also names their graph.
+	Type string
+	// query selects up to $2 rows whose key is greater than $1, ordered by key.
+	query string
+	// scan reads one row and returns its key and event.
+	scan func(rows *sql.Rows) (string, Event, error)
+}
+
+// BackfillSources lists the tables that can be replayed, in dependency order.
+var BackfillSources = []BackfillSource{
+	{Name: "users", Type: ClassUser, query: backfillUsersQuery, scan: scanBackfillUser},
+	{Name: "sessions", Type: ClassSession, query: backfillSessionsQuery, scan: scanBackfillSession},
+	{Name: "matches", Type: ClassMatch, query: backfillMatchesQuery, scan: scanBackfillMatch},
+}
+
+// LookupBackfillSource returns the source with the given name.
+func LookupBackfillSource(name string) (BackfillSource, bool) {
+	for _, source := range BackfillSources {
+		if source.Name == name {
+			return source, true
+		}
+	}
+	return BackfillSource{}, false
+}
+
+// BackfillOption customises a Backfiller.
+type BackfillOption func(*Backfiller)
+
+// WithBackfillBatchSize sets how many rows are read per page and checkpoint.
+func WithBackfillBatchSize(n int) BackfillOption {
+	return func(b *Backfiller) {
+		if n > 0 {
+			b.batchSize = n
+		}
+	}
+}
+
+// WithBackfillRate caps emitted events per second; zero means unlimited.
+func WithBackfillRate(perSecond float64) BackfillOption {
+	return func(b *Backfiller) {
+		if perSecond > 0 {
+			b.limiter = rate.NewLimiter(rate.Limit(perSecond), 1)
+		}
+	}
+}
+
+// WithBackfillLogger sets the logger used for progress and rejected events.
+func WithBackfillLogger(logger *slog.Logger) BackfillOption {
+	return func(b *Backfiller) {
+		b.logger = logger
+	}
+}
+
+// Backfiller replays existing rows as ontology events, for data that predates
+// event emission. Progress is checkpointed after every page in
+// ontology_backfill_checkpoints, so an interrupted run resumes where it stopped.
+type Backfiller struct {
+	db        *sql.DB
+	emitter   Emitter
+	batchSize int
+	limiter   *rate.Limiter
+	logger    *slog.Logger
+}
+
+// NewBackfiller builds a backfiller that hands events to emitter, usually an
+// OutboxEmitter or a DirectEmitter.
+func NewBackfiller(db *sql.DB, emitter Emitter, opts ...BackfillOption) *Backfiller {
+	b := &Backfiller{
+		db:        db,
+		emitter:   emitter,
+		batchSize: defaultBackfillBatchSize,
+		limiter:   rate.NewLimiter(rate.Inf, 1),
+		logger:    slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// BackfillResult summarises one Backfiller.Run call.
+type BackfillResult struct {
+	Emitted  int
+	Rejected int
+	// LastKey is the key of the last row read.
+	LastKey string
+}
+
+// Run emits an event for every row of source after its checkpoint and marks
+// the checkpoint complete once the table is exhausted. Events rejected by a
+// DirectEmitter are logged and skipped; any other emit error stops the run
+// with the checkpoint at the last finished page.
+func (b *Backfiller) Run(ctx context.Context, source BackfillSource) (BackfillResult, error) {
+	var result BackfillResult
+	if b == nil || b.db == nil || b.emitter == nil {
+		return result, errors.New("backfiller uninitialized")
+	}
+
+	cursor, completed, err := b.checkpoint(ctx, source.Name)
+	if err != nil {
+		return result, err
+	}
+	if completed {
+		return result, fmt.Errorf("%s: %w", source.Name, ErrBackfillComplete)
+	}
+	result.LastKey = cursor
+
+	for {
+		events, keys, err := b.page(ctx, source, cursor)
+		if err != nil {
+			return result, err
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		emitted := 0
+		for i, evt := range events {
+			if err := b.limiter.Wait(ctx); err != nil {
+				return result, err
+			}
+			var rejected *RejectedEventError
+			switch err := b.emitter.Emit(ctx, evt); {
+			case errors.As(err, &rejected):
+				result.Rejected++
+				b.logger.Warn("backfill event rejected",
+					"source", source.Name, "key", keys[i], "report", rejected.Report.String())
+			case err != nil:
+				return result, fmt.Errorf("emit %s %s: %w", source.Name, keys[i], err)
+			default:
+				emitted++
+			}
+		}
+
+		cursor = keys[len(keys)-1]
+		if err := b.saveCheckpoint(ctx, source.Name, cursor, emitted, false); err != nil {
+			return result, err
+		}
+		result.Emitted += emitted
+		result.LastKey = cursor
+		b.logger.Info("backfill progress", "source", source.Name, "emitted", result.Emitted, "last_key", cursor)
+
+		if len(events) < b.batchSize {
+			break
+		}
+	}
+
+	if err := b.saveCheckpoint(ctx, source.Name, cursor, 0, true); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// Reset deletes the checkpoint of source so the next run starts from the first row.
+func (b *Backfiller) Reset(ctx context.Context, source string) error {
+	if _, err := b.db.ExecContext(ctx, `
+        -- name: DeleteOntologyBackfillCheckpoint
+        DELETE FROM ontology_backfill_checkpoints WHERE source = $1`, source); err != nil {
+		return fmt.Errorf("reset checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (b *Backfiller) page(ctx context.Context, source BackfillSource, cursor string) ([]Event, []string, error) {
+	rows, err := b.db.QueryContext(ctx, source.query, cursor, b.batchSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("select %s: %w", source.Name, err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	var keys []string
+	for rows.Next() {
+		key, evt, err := source.scan(rows)
+		if err != nil {
+			return nil, nil, fmt.Errorf("scan %s: %w", source.Name, err)
+		}
+		events = append(events, evt)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterate %s: %w", source.Name, err)
+	}
+	return events, keys, nil
+}
+
+func (b *Backfiller) checkpoint(ctx context.Context, source string) (string, bool, error) {
+	var cursor string
+	var completedAt sql.NullTime
+	err := b.db.QueryRowContext(ctx, `
+        -- name: GetOntologyBackfillCheckpoint
+        SELECT last_key, completed_at FROM ontology_backfill_checkpoints WHERE source = $1`,
+		source).Scan(&cursor, &completedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return firstBackfillKey, false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("read checkpoint: %w", err)
+	}
+	return cursor, completedAt.Valid, nil
+}
+
+func (b *Backfiller) saveCheckpoint(ctx context.Context, source, cursor string, emitted int, completed bool) error {
+	if _, err := b.db.ExecContext(ctx, `
+        -- name: SaveOntologyBackfillCheckpoint
+        INSERT INTO ontology_backfill_checkpoints (source, last_key, events, completed_at)
+        VALUES ($1, $2, $3, CASE WHEN $4 THEN NOW() END)
+        ON CONFLICT (source) DO UPDATE
+        SET last_key = EXCLUDED.last_key,
+            events = ontology_backfill_checkpoints.events + EXCLUDED.events,
+            updated_at = NOW(),
+            completed_at = EXCLUDED.completed_at`,
+		source, cursor, emitted, completed); err != nil {
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// backfillActivity returns the activity recorded for a replayed row. Its ID is
+// derived from the resource and activity type, so replaying a row again
+// replaces the activity instead of adding a duplicate to the history.
+func backfillActivity(resource, typ string, actor uuid.UUID, at time.Time) Activity {
+	return Activity{
+		ID:    uuid.NewSHA1(uuid.NameSpaceURL, []byte(ExpandIRI(resource)+"#backfill/"+typ)),
+		Type:  typ,
+		Actor: actor,
+		At:    at,
+	}
+}
+
+const backfillUsersQuery = `
+    -- name: BackfillUsers
+    SELECT id, email, username, display_name, avatar_url, role,
+           is_active, email_verified_at, created_at, updated_at, last_login_at
+    FROM users
+    WHERE id > $1 AND deleted_at IS NULL
+    ORDER BY id
+    LIMIT $2`
+
+// scanBackfillUser records users as registered; later sign-ins and
+// verification are reflected in the snapshot only.
+func scanBackfillUser(rows *sql.Rows) (string, Event, error) {
+	var user repository.User
+	if err := rows.Scan(&user.ID, &user.Email, &user.Username, &user.DisplayName, &user.AvatarURL,
+		&user.Role, &user.IsActive, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt,
+		&user.LastLoginAt); err != nil {
+		return "", Event{}, err
+	}
+	activity := backfillActivity(userIRI(user.ID), ActivityUserRegistered, user.ID, user.CreatedAt)
+	return user.ID.String(), NewUserEvent(&user, activity), nil
+}
+
+const backfillSessionsQuery = `
+    -- name: BackfillSessions
+    SELECT id, initiator_id, partner_id, to_json(initiator_offers), to_json(partner_offers),
+           scheduled_start, scheduled_end, actual_start, actual_end, status::text,
+           COALESCE(meeting_url, ''), COALESCE(notes, ''), is_premium,
+           COALESCE(cancellation_reason, ''), created_at, updated_at
+    FROM sessions
+    WHERE id > $1
+    ORDER BY id
+    LIMIT $2`
+
+// sessionStatusConcepts maps session_status enum labels to SessionStatus concepts.
+var sessionStatusConcepts = map[string]string{
+	"scheduled":   ConceptSessionStatusScheduled,
+	"in_progress": ConceptSessionStatusInProgress,
+	"completed":   ConceptSessionStatusCompleted,
+	"cancelled":   ConceptSessionStatusCancelled,
+	"no_show":     ConceptSessionStatusNoShow,
+}
+
+// scanBackfillSession records the transition into the session's current
+// status; the actor is only known for scheduling.
+func scanBackfillSession(rows *sql.Rows) (string, Event, error) {
+	var payload SessionEvent
+	var initiatorOffers, partnerOffers []byte
+	var actualStart, actualEnd sql.NullTime
+	var status string
+	var createdAt, updatedAt time.Time
+	if err := rows.Scan(&payload.SessionID, &payload.InitiatorID, &payload.PartnerID,
+		&initiatorOffers, &partnerOffers, &payload.ScheduledStart, &payload.ScheduledEnd,
+		&actualStart, &actualEnd, &status, &payload.MeetingURL, &payload.Notes,
+		&payload.IsPremium, &payload.CancellationReason, &createdAt, &updatedAt); err != nil {
+		return "", Event{}, err
+	}
+	if err := json.Unmarshal(initiatorOffers, &payload.InitiatorOffers); err != nil {
+		return "", Event{}, fmt.Errorf("decode initiator_offers: %w", err)
+	}
+	if err := json.Unmarshal(partnerOffers, &payload.PartnerOffers); err != nil {
+		return "", Event{}, fmt.Errorf("decode partner_offers: %w", err)
+	}
+	payload.ActualStart = actualStart.Time
+	payload.ActualEnd = actualEnd.Time
+	payload.StatusIRI = sessionStatusConcepts[status]
+
+	var actor uuid.UUID
+	at := updatedAt
+	switch payload.StatusIRI {
+	case ConceptSessionStatusScheduled:
+		actor, at = payload.InitiatorID, createdAt
+	case ConceptSessionStatusInProgress:
+		at = timeOr(payload.ActualStart, updatedAt)
+	case ConceptSessionStatusCompleted:
+		at = timeOr(payload.ActualEnd, updatedAt)
+	}
+	activity := Activity{}
+	if typ := sessionActivities[payload.StatusIRI]; typ != "" {
+		activity = backfillActivity(sessionIRI(payload.SessionID), typ, actor, at)
+		if payload.StatusIRI == ConceptSessionStatusCancelled {
+			activity.Reason = payload.CancellationReason
+		}
+	}
+	return payload.SessionID.String(), NewSessionEvent(payload, activity), nil
+}
+
+const backfillMatchesQuery = `
+    -- name: BackfillMatches
+    SELECT id, user_id_a, user_id_b, algorithm_used, match_score::float8, created_at,
+        COALESCE(model_version, ''), COALESCE(variant, '')
+    FROM match_history
+    WHERE id > $1
+    ORDER BY id
+    LIMIT $2`
+
+// matchingAlgorithmConcepts maps algorithm_used values to MatchingAlgorithm concepts.
+var matchingAlgorithmConcepts = map[string]string{
+	"euclidean": ConceptMatchingAlgorithmEuclidean,
+	"cosine":    ConceptMatchingAlgorithmCosine,
+	"embedding": ConceptMatchingAlgorithmEmbedding,
+	"hybrid":    ConceptMatchingAlgorithmHybrid,
+}
+
+// scanBackfillMatch records matches as proposed by the platform, with the
+// ranking model and experiment arm that ordered them.
+func scanBackfillMatch(rows *sql.Rows) (string, Event, error) {
+	var payload MatchEvent
+	var algorithm, modelVersion, variant string
+	var createdAt time.Time
+	if err := rows.Scan(&payload.MatchID, &payload.RequesterID, &payload.CandidateID,
+		&algorithm, &payload.Score, &createdAt, &modelVersion, &variant); err != nil {
+		return "", Event{}, err
+	}
+	algorithm = strings.TrimPrefix(strings.ToLower(algorithm), "matching_algorithm_")
+	if concept, ok := matchingAlgorithmConcepts[algorithm]; ok {
+		payload.AlgorithmIRI = RankedAlgorithmIRI(concept, modelVersion, variant)
+	}
+
+	iri := ClassMatch + "/" + payload.MatchID.String()
+	activity := backfillActivity(iri, ActivityMatchProposed, uuid.Nil, createdAt)
+	return payload.MatchID.String(), newMatchEvent(payload, activity), nil
+}
+
+// timeOr returns t, or fallback when t is zero.
+func timeOr(t, fallback time.Time) time.Time {
+	if t.IsZero() {
+		return fallback
+	}
+	return t
+}
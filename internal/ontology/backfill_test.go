@@ -0,0 +1,182 @@
+package ontology
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDirectEmitter_RejectsNonConformingEvents(t *testing.T) {
+	validator, err := DefaultValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var inserted int
+	triple := tripleStoreFunc(func(context.Context, []byte) error { inserted++; return nil })
+	emitter := NewDirectEmitter("", nil, triple, validator)
+
+	if err := emitter.Emit(context.Background(), NewUserRegisteredEvent(testUser())); err != nil {
+		t.Fatalf("Emit valid event: %v", err)
+	}
+	invalid := NewEvent("sk:User/2", ClassUser)
+	invalid.Set(PropIsActive, "yes")
+	var rejected *RejectedEventError
+	if err := emitter.Emit(context.Background(), invalid); !errors.As(err, &rejected) || rejected.Report.Conforms {
+		t.Fatalf("Emit invalid event = %v, want a RejectedEventError", err)
+	}
+	if inserted != 1 {
+		t.Fatalf("inserted %d events, want only the valid one", inserted)
+	}
+}
+
+func TestBackfillActivity_IsStablePerResource(t *testing.T) {
+	at := time.Now()
+	a := backfillActivity("sk:User/1", ActivityUserRegistered, uuid.Nil, at)
+	b := backfillActivity("sk:User/1", ActivityUserRegistered, uuid.Nil, at.Add(time.Hour))
+	c := backfillActivity("sk:User/2", ActivityUserRegistered, uuid.Nil, at)
+	if a.ID != b.ID {
+		t.Error("replaying a row minted a new activity ID")
+	}
+	if a.ID == c.ID {
+		t.Error("two resources share a backfill activity ID")
+	}
+}
+
+type recordingEmitter struct {
+	events []Event
+	failAt int
+}
+
+func (r *recordingEmitter) Emit(_ context.Context, event Event) error {
+	if r.failAt > 0 && len(r.events)+1 == r.failAt {
+		return errors.New("sink unavailable")
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+// TestBackfiller_ResumesFromCheckpoint shadows the source tables with
+// temporary ones, like openOutboxTestDB.
+func TestBackfiller_ResumesFromCheckpoint(t *testing.T) {
+	db := openOutboxTestDB(t)
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		`CREATE TEMP TABLE ontology_backfill_checkpoints (
+			source TEXT PRIMARY KEY,
+			last_key TEXT NOT NULL,
+			events BIGINT NOT NULL DEFAULT 0,
+			started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			completed_at TIMESTAMPTZ
+		)`,
+		`CREATE TEMP TABLE users (
+			id UUID PRIMARY KEY,
+			email TEXT NOT NULL,
+			username TEXT NOT NULL,
+			display_name TEXT NOT NULL,
+			avatar_url TEXT,
+			role TEXT NOT NULL DEFAULT 'member',
+			is_active BOOLEAN NOT NULL DEFAULT true,
+			email_verified_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			last_login_at TIMESTAMPTZ,
+			deleted_at TIMESTAMPTZ
+		)`,
+		`CREATE TEMP TABLE sessions (
+			id UUID PRIMARY KEY,
+			initiator_id UUID NOT NULL,
+			partner_id UUID NOT NULL,
+			initiator_offers TEXT[] NOT NULL,
+			partner_offers TEXT[] NOT NULL,
+			scheduled_start TIMESTAMPTZ NOT NULL,
+			scheduled_end TIMESTAMPTZ NOT NULL,
+			actual_start TIMESTAMPTZ,
+			actual_end TIMESTAMPTZ,
+			status TEXT NOT NULL,
+			meeting_url TEXT,
+			notes TEXT,
+			is_premium BOOLEAN NOT NULL DEFAULT false,
+			cancellation_reason TEXT,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE TEMP TABLE match_history (
+			id UUID PRIMARY KEY,
+			user_id_a UUID NOT NULL,
+			user_id_b UUID NOT NULL,
+			algorithm_used VARCHAR(50) NOT NULL,
+			match_score NUMERIC(5, 4) NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			model_version TEXT,
+			variant VARCHAR(20)
+		)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("create temp table: %v", err)
+		}
+	}
+
+	users := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for i, id := range users {
+		if _, err := db.Exec(`INSERT INTO users (id, email, username, display_name) VALUES ($1, $2, $2, 'Test')`,
+			id, id.String()+"@example.com"); err != nil {
+			t.Fatalf("insert user %d: %v", i, err)
+		}
+	}
+	if _, err := db.Exec(`
+		INSERT INTO sessions (id, initiator_id, partner_id, initiator_offers, partner_offers,
+		                      scheduled_start, scheduled_end, status, cancellation_reason)
+		VALUES ($1, $2, $3, '{go}', '{rust}', NOW(), NOW() + INTERVAL '1 hour', 'cancelled', 'sick')`,
+		uuid.New(), users[0], users[1]); err != nil {
+		t.Fatalf("insert session: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO match_history (id, user_id_a, user_id_b, algorithm_used, match_score, model_version, variant)
+		VALUES ($1, $2, $3, 'cosine', 0.8125, NULL, NULL), ($4, $3, $2, 'hybrid', 0.7, 'v3', 'treatment')`,
+		uuid.New(), users[0], users[2], uuid.New()); err != nil {
+		t.Fatalf("insert match: %v", err)
+	}
+
+	usersSource, _ := LookupBackfillSource("users")
+	failing := &recordingEmitter{failAt: 3}
+	if _, err := NewBackfiller(db, failing, WithBackfillBatchSize(2)).Run(ctx, usersSource); err == nil {
+		t.Fatal("Run with a failing sink succeeded")
+	}
+
+	resumed := &recordingEmitter{}
+	backfiller := NewBackfiller(db, resumed, WithBackfillBatchSize(2))
+	result, err := backfiller.Run(ctx, usersSource)
+	if err != nil {
+		t.Fatalf("resumed Run: %v", err)
+	}
+	if result.Emitted != 1 || len(failing.events)+len(resumed.events) != len(users) {
+		t.Fatalf("resumed run = %+v after %d events, want the remaining user only", result, len(failing.events))
+	}
+	if _, err := backfiller.Run(ctx, usersSource); !errors.Is(err, ErrBackfillComplete) {
+		t.Fatalf("third Run = %v, want ErrBackfillComplete", err)
+	}
+
+	validator, err := DefaultValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	direct := NewDirectEmitter("", nil, tripleStoreFunc(func(context.Context, []byte) error { return nil }), validator)
+	backfiller = NewBackfiller(db, direct)
+	for _, source := range BackfillSources {
+		if err := backfiller.Reset(ctx, source.Name); err != nil {
+			t.Fatal(err)
+		}
+		result, err := backfiller.Run(ctx, source)
+		if err != nil {
+			t.Fatalf("Run %s: %v", source.Name, err)
+		}
+		if result.Emitted == 0 || result.Rejected != 0 {
+			t.Errorf("%s: %+v, want every row emitted and conforming", source.Name, result)
+		}
+	}
+}
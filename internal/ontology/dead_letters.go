@@ -0,0 +1,103 @@
+package ontology
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DeadLetter is an outbox row that exhausted its delivery attempts.
+type DeadLetter struct {
+	ID             string          `json:"id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	LastError      string          `json:"last_error"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeadLetteredAt time.Time       `json:"dead_lettered_at"`
+}
+
+// DeadLetterStore inspects, replays and purges dead-lettered outbox rows.
+type DeadLetterStore struct {
+	db *sql.DB
+}
+
+// NewDeadLetterStore builds a store backed by the provided sql.DB.
+func NewDeadLetterStore(db *sql.DB) *DeadLetterStore {
+	return &DeadLetterStore{db: db}
+}
+
+// List returns up to limit dead letters, oldest first.
+func (s *DeadLetterStore) List(ctx context.Context, limit int) ([]DeadLetter, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        -- name: ListOntologyDeadLetters
+        SELECT id, event_type, payload, attempts, COALESCE(last_error, ''), created_at, dead_lettered_at
+        FROM ontology_outbox
+        WHERE dead_lettered_at IS NOT NULL
+        ORDER BY dead_lettered_at
+        LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var letters []DeadLetter
+	for rows.Next() {
+		var letter DeadLetter
+		var payload []byte
+		if err := rows.Scan(&letter.ID, &letter.EventType, &payload, &letter.Attempts,
+			&letter.LastError, &letter.CreatedAt, &letter.DeadLetteredAt); err != nil {
+			return nil, fmt.Errorf("scan dead letter: %w", err)
+		}
+		letter.Payload = payload
+		letters = append(letters, letter)
+	}
+	return letters, rows.Err()
+}
+
+// Count returns the number of dead-lettered rows.
+func (s *DeadLetterStore) Count(ctx context.Context) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, `
+        -- name: CountOntologyDeadLetters
+        SELECT COUNT(*) FROM ontology_outbox WHERE dead_lettered_at IS NOT NULL`).Scan(&n)
+	return n, err
+}
+
+// Replay returns the given dead letters, or all of them when ids is empty, to the
+// pending queue with a fresh attempt budget. It reports how many rows were requeued.
+func (s *DeadLetterStore) Replay(ctx context.Context, ids ...string) (int64, error) {
+	query := `
+        -- name: ReplayOntologyDeadLetters
+        UPDATE ontology_outbox
+        SET dead_lettered_at = NULL, attempts = 0, next_attempt_at = NOW()
+        WHERE dead_lettered_at IS NOT NULL`
+	return s.exec(ctx, query, ids)
+}
+
+// Purge deletes the given dead letters, or all of them when ids is empty.
+func (s *DeadLetterStore) Purge(ctx context.Context, ids ...string) (int64, error) {
+	query := `
+        -- name: PurgeOntologyDeadLetters
+        DELETE FROM ontology_outbox
+        WHERE dead_lettered_at IS NOT NULL`
+	return s.exec(ctx, query, ids)
+}
+
+func (s *DeadLetterStore) exec(ctx context.Context, query string, ids []string) (int64, error) {
+	var (
+		result sql.Result
+		err    error
+	)
+	if len(ids) == 0 {
+		result, err = s.db.ExecContext(ctx, query)
+	} else {
+		result, err = s.db.ExecContext(ctx, query+` AND id = ANY($1::uuid[])`, ids)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -76,7 +76,7 @@ func (e Event) MarshalJSON() ([]byte, error) {
 		document["@type"] = e.Type
 	}
 	if !e.Timestamp.IsZero() {
-		document["schema:dateModified"] = e.Timestamp.UTC().Format(time.RFC3339Nano)
+		document[SchemaDateModified] = e.Timestamp.UTC().Format(time.RFC3339Nano)
 	}
 
 	for key, value := range e.props {
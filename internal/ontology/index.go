@@ -0,0 +1,551 @@
+package ontology
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strconv"
+	"sync"
+	"time"
+
+	generated "github.com/FACorreiaa/skillsphere-api/ontology"
+)
+
+// Vocabulary IRIs the index reasons over.
+const (
+	RDFSNS = "http://www.w3.org/2000/01/rdf-schema#"
+	OWLNS  = "http://www.w3.org/2002/07/owl#"
+	SKOSNS = "http://www.w3.org/2004/02/skos/core#"
+
+	rdfsSubClassOf        = RDFSNS + "subClassOf"
+	rdfsSubPropertyOf     = RDFSNS + "subPropertyOf"
+	owlTransitiveProperty = OWLNS + "TransitiveProperty"
+)
+
+// skosAxioms are the statements of the SKOS reference vocabulary that relate
+// the hierarchy properties, so that skos:broader links in the data can be
+// queried through skos:broaderTransitive.
+const skosAxioms = `
+@prefix skos: <http://www.w3.org/2004/02/skos/core#> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+
+skos:broader rdfs:subPropertyOf skos:broaderTransitive .
+skos:narrower rdfs:subPropertyOf skos:narrowerTransitive .
+skos:broaderTransitive a owl:TransitiveProperty .
+skos:narrowerTransitive a owl:TransitiveProperty .
+`
+
+// GraphIndex is an in-memory copy of the ontology graph that answers SPARQL
+// queries (see Query). It holds the generated vocabulary plus every event
+// applied to it, and upserts resources the way SPARQLStoreClient does: a new
+// snapshot replaces the triples about its @id and the blank nodes hanging
+// off it, while the activity records it links to accumulate.
+//
+// Queries see RDFS entailments of the vocabulary without them being stored:
+// rdf:type follows rdfs:subClassOf, properties follow rdfs:subPropertyOf, and
+// owl:TransitiveProperty properties (skos:broaderTransitive) are closed.
+type GraphIndex struct {
+	mu        sync.RWMutex
+	spo       termIndex
+	pos       termIndex
+	osp       termIndex
+	size      int
+	resources map[Term]indexedResource
+	documents int
+
+	schema rdfsSchema
+}
+
+type indexedResource struct {
+	version time.Time
+	triples []Triple
+}
+
+// NewGraphIndex builds an index holding the generated vocabulary.
+func NewGraphIndex() (*GraphIndex, error) {
+	return NewGraphIndexWithSchema(generated.Schemes)
+}
+
+// NewGraphIndexWithSchema builds an index over a custom Turtle vocabulary;
+// the SKOS hierarchy axioms are always included.
+func NewGraphIndexWithSchema(schemaTurtle []byte) (*GraphIndex, error) {
+	schema, err := ParseTurtle(schemaTurtle)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	axioms, err := ParseTurtle([]byte(skosAxioms))
+	if err != nil {
+		return nil, err
+	}
+	schema = append(schema, axioms...)
+
+	g := &GraphIndex{
+		spo:       make(termIndex),
+		pos:       make(termIndex),
+		osp:       make(termIndex),
+		resources: make(map[Term]indexedResource),
+		schema:    buildSchema(schema),
+	}
+	for _, t := range schema {
+		g.add(t)
+	}
+	return g, nil
+}
+
+// Len returns the number of stored (not entailed) triples.
+func (g *GraphIndex) Len() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.size
+}
+
+// Apply upserts the JSON-LD document into the index. version orders the
+// snapshots of one resource: a document older than the one already applied
+// for its @id is ignored, so replaying events is harmless. It reports whether
+// the document was applied.
+func (g *GraphIndex) Apply(payload []byte, version time.Time) (bool, error) {
+	var doc struct {
+		ID string `json:"@id"`
+	}
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return false, fmt.Errorf("decode JSON-LD document: %w", err)
+	}
+	triples, err := ToRDF(payload)
+	if err != nil {
+		return false, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Blank node labels restart at b0 for every document.
+	prefix := "d" + strconv.Itoa(g.documents) + "_"
+	g.documents++
+	for i := range triples {
+		triples[i].Subject = relabel(triples[i].Subject, prefix)
+		triples[i].Object = relabel(triples[i].Object, prefix)
+	}
+
+	if doc.ID == "" {
+		for _, t := range triples {
+			g.add(t)
+		}
+		return true, nil
+	}
+
+	subject := IRI(ExpandIRI(doc.ID))
+	previous, ok := g.resources[subject]
+	if ok && version.Before(previous.version) {
+		return false, nil
+	}
+	for _, t := range previous.triples {
+		g.remove(t)
+	}
+
+	var owned []Triple
+	for _, t := range triples {
+		if t.Subject == subject || t.Subject.Kind == BlankNodeTerm {
+			owned = append(owned, t)
+		}
+		g.add(t)
+	}
+	g.resources[subject] = indexedResource{version: version, triples: owned}
+	return true, nil
+}
+
+// Query evaluates a SPARQL SELECT query against the index.
+func (g *GraphIndex) Query(ctx context.Context, query string) (*QueryResult, error) {
+	parsed, err := parseSPARQL(query)
+	if err != nil {
+		return nil, err
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return parsed.evaluate(ctx, g)
+}
+
+func relabel(term Term, prefix string) Term {
+	if term.Kind == BlankNodeTerm {
+		term.Value = prefix + term.Value
+	}
+	return term
+}
+
+func (g *GraphIndex) add(t Triple) {
+	if g.spo.put(t.Subject, t.Predicate, t.Object) {
+		g.pos.put(t.Predicate, t.Object, t.Subject)
+		g.osp.put(t.Object, t.Subject, t.Predicate)
+		g.size++
+	}
+}
+
+func (g *GraphIndex) remove(t Triple) {
+	if g.spo.delete(t.Subject, t.Predicate, t.Object) {
+		g.pos.delete(t.Predicate, t.Object, t.Subject)
+		g.osp.delete(t.Object, t.Subject, t.Predicate)
+		g.size--
+	}
+}
+
+// termIndex is one permutation of the stored triples.
+type termIndex map[Term]map[Term]map[Term]struct{}
+
+func (ix termIndex) put(a, b, c Term) bool {
+	second, ok := ix[a]
+	if !ok {
+		second = make(map[Term]map[Term]struct{})
+		ix[a] = second
+	}
+	third, ok := second[b]
+	if !ok {
+		third = make(map[Term]struct{})
+		second[b] = third
+	}
+	if _, ok := third[c]; ok {
+		return false
+	}
+	third[c] = struct{}{}
+	return true
+}
+
+func (ix termIndex) delete(a, b, c Term) bool {
+	third, ok := ix[a][b]
+	if !ok {
+		return false
+	}
+	if _, ok := third[c]; !ok {
+		return false
+	}
+	delete(third, c)
+	if len(third) == 0 {
+		delete(ix[a], b)
+		if len(ix[a]) == 0 {
+			delete(ix, a)
+		}
+	}
+	return true
+}
+
+// anyTerm is the wildcard in match patterns.
+var anyTerm Term
+
+// stored yields the stored triples matching the pattern; anyTerm matches
+// every term in its position.
+func (g *GraphIndex) stored(s, p, o Term) iter.Seq[Triple] {
+	return func(yield func(Triple) bool) {
+		switch {
+		case s != anyTerm && p != anyTerm:
+			for obj := range g.spo[s][p] {
+				if o != anyTerm && obj != o {
+					continue
+				}
+				if !yield(Triple{s, p, obj}) {
+					return
+				}
+			}
+		case s != anyTerm:
+			for pred, objects := range g.spo[s] {
+				for obj := range objects {
+					if o != anyTerm && obj != o {
+						continue
+					}
+					if !yield(Triple{s, pred, obj}) {
+						return
+					}
+				}
+			}
+		case p != anyTerm && o != anyTerm:
+			for subj := range g.pos[p][o] {
+				if !yield(Triple{subj, p, o}) {
+					return
+				}
+			}
+		case p != anyTerm:
+			for obj, subjects := range g.pos[p] {
+				for subj := range subjects {
+					if !yield(Triple{subj, p, obj}) {
+						return
+					}
+				}
+			}
+		case o != anyTerm:
+			for subj, predicates := range g.osp[o] {
+				for pred := range predicates {
+					if !yield(Triple{subj, pred, o}) {
+						return
+					}
+				}
+			}
+		default:
+			for subj, predicates := range g.spo {
+				for pred, objects := range predicates {
+					for obj := range objects {
+						if !yield(Triple{subj, pred, obj}) {
+							return
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// match yields the stored and entailed triples matching the pattern, each once.
+func (g *GraphIndex) match(s, p, o Term) iter.Seq[Triple] {
+	switch {
+	case p == IRI(rdfType):
+		return g.matchType(s, o)
+	case g.schema.transitive[p]:
+		return g.matchTransitive(s, p, o)
+	case p != anyTerm:
+		return g.matchProperty(s, p, o)
+	default:
+		return g.matchAny(s, o)
+	}
+}
+
+// matchType answers rdf:type patterns through the subclass hierarchy.
+func (g *GraphIndex) matchType(s, class Term) iter.Seq[Triple] {
+	typ := IRI(rdfType)
+	return func(yield func(Triple) bool) {
+		seen := make(map[Triple]bool)
+		emit := func(t Triple) bool {
+			if seen[t] {
+				return true
+			}
+			seen[t] = true
+			return yield(t)
+		}
+		if class != anyTerm {
+			for _, sub := range g.schema.subClasses(class) {
+				for t := range g.stored(s, typ, sub) {
+					if !emit(Triple{t.Subject, typ, class}) {
+						return
+					}
+				}
+			}
+			return
+		}
+		for t := range g.stored(s, typ, anyTerm) {
+			for _, super := range g.schema.superClasses(t.Object) {
+				if !emit(Triple{t.Subject, typ, super}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// matchProperty answers patterns with a bound predicate through its subproperties.
+func (g *GraphIndex) matchProperty(s, p, o Term) iter.Seq[Triple] {
+	return func(yield func(Triple) bool) {
+		subs := g.schema.subProperties(p)
+		var seen map[Triple]bool
+		if len(subs) > 1 {
+			seen = make(map[Triple]bool)
+		}
+		for _, sub := range subs {
+			for t := range g.stored(s, sub, o) {
+				t.Predicate = p
+				if seen != nil {
+					if seen[t] {
+						continue
+					}
+					seen[t] = true
+				}
+				if !yield(t) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// matchTransitive walks the edges of a transitive property, including those
+// stated with its subproperties, from whichever end of the pattern is bound.
+func (g *GraphIndex) matchTransitive(s, p, o Term) iter.Seq[Triple] {
+	return func(yield func(Triple) bool) {
+		if s == anyTerm && o != anyTerm {
+			for reached := range g.closure(o, p, true) {
+				if !yield(Triple{reached, p, o}) {
+					return
+				}
+			}
+			return
+		}
+		var starts []Term
+		if s != anyTerm {
+			starts = []Term{s}
+		} else {
+			seen := make(map[Term]bool)
+			for t := range g.matchProperty(anyTerm, p, anyTerm) {
+				if !seen[t.Subject] {
+					seen[t.Subject] = true
+					starts = append(starts, t.Subject)
+				}
+			}
+		}
+		for _, start := range starts {
+			for reached := range g.closure(start, p, false) {
+				if o != anyTerm && reached != o {
+					continue
+				}
+				if !yield(Triple{start, p, reached}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// closure yields every node reachable from start over p, following edges
+// backwards when reverse is set.
+func (g *GraphIndex) closure(start, p Term, reverse bool) iter.Seq[Term] {
+	return func(yield func(Term) bool) {
+		seen := map[Term]bool{start: true}
+		queue := []Term{start}
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+			s, o := node, anyTerm
+			if reverse {
+				s, o = anyTerm, node
+			}
+			for t := range g.matchProperty(s, p, o) {
+				next := t.Object
+				if reverse {
+					next = t.Subject
+				}
+				if seen[next] {
+					continue
+				}
+				seen[next] = true
+				if !yield(next) {
+					return
+				}
+				queue = append(queue, next)
+			}
+		}
+	}
+}
+
+// matchAny answers patterns with a variable predicate: stored triples plus
+// their superproperties and the superclasses of stated types. Transitive
+// closures are only computed when the predicate is bound.
+func (g *GraphIndex) matchAny(s, o Term) iter.Seq[Triple] {
+	return func(yield func(Triple) bool) {
+		seen := make(map[Triple]bool)
+		emit := func(t Triple) bool {
+			if seen[t] {
+				return true
+			}
+			seen[t] = true
+			return yield(t)
+		}
+		for t := range g.stored(s, anyTerm, o) {
+			entailed := []Triple{t}
+			switch {
+			case t.Predicate == IRI(rdfType) && o == anyTerm:
+				entailed = entailed[:0]
+				for _, super := range g.schema.superClasses(t.Object) {
+					entailed = append(entailed, Triple{t.Subject, t.Predicate, super})
+				}
+			case t.Predicate != IRI(rdfType):
+				entailed = entailed[:0]
+				for _, super := range g.schema.superProperties(t.Predicate) {
+					entailed = append(entailed, Triple{t.Subject, super, t.Object})
+				}
+			}
+			for _, e := range entailed {
+				if !emit(e) {
+					return
+				}
+			}
+		}
+		if o == anyTerm {
+			return
+		}
+		// Instances of o's subclasses are typed o without stating it.
+		for t := range g.matchType(s, o) {
+			if !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+// rdfsSchema holds the reflexive-transitive closures of the class and
+// property hierarchies of the vocabulary.
+type rdfsSchema struct {
+	subClassesOf      map[Term][]Term
+	superClassesOf    map[Term][]Term
+	subPropertiesOf   map[Term][]Term
+	superPropertiesOf map[Term][]Term
+	transitive        map[Term]bool
+}
+
+func buildSchema(triples []Triple) rdfsSchema {
+	classes := make(map[Term][]Term)
+	properties := make(map[Term][]Term)
+	transitive := make(map[Term]bool)
+	for _, t := range triples {
+		if t.Object.Kind == BlankNodeTerm || t.Subject.Kind == BlankNodeTerm {
+			continue
+		}
+		switch {
+		case t.Predicate == IRI(rdfsSubClassOf):
+			classes[t.Subject] = append(classes[t.Subject], t.Object)
+		case t.Predicate == IRI(rdfsSubPropertyOf):
+			properties[t.Subject] = append(properties[t.Subject], t.Object)
+		case t.Predicate == IRI(rdfType) && t.Object == IRI(owlTransitiveProperty):
+			transitive[t.Subject] = true
+		}
+	}
+	s := rdfsSchema{transitive: transitive}
+	s.superClassesOf, s.subClassesOf = closeHierarchy(classes)
+	s.superPropertiesOf, s.subPropertiesOf = closeHierarchy(properties)
+	return s
+}
+
+// closeHierarchy returns, for every node of a subsumption graph, its
+// ancestors and descendants excluding itself.
+func closeHierarchy(parents map[Term][]Term) (ancestors, descendants map[Term][]Term) {
+	ancestors = make(map[Term][]Term)
+	descendants = make(map[Term][]Term)
+	for node := range parents {
+		seen := map[Term]bool{node: true}
+		queue := []Term{node}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, parent := range parents[current] {
+				if seen[parent] {
+					continue
+				}
+				seen[parent] = true
+				queue = append(queue, parent)
+				ancestors[node] = append(ancestors[node], parent)
+				descendants[parent] = append(descendants[parent], node)
+			}
+		}
+	}
+	return ancestors, descendants
+}
+
+func (s rdfsSchema) subClasses(class Term) []Term {
+	return append([]Term{class}, s.subClassesOf[class]...)
+}
+
+func (s rdfsSchema) superClasses(class Term) []Term {
+	return append([]Term{class}, s.superClassesOf[class]...)
+}
+
+func (s rdfsSchema) subProperties(property Term) []Term {
+	return append([]Term{property}, s.subPropertiesOf[property]...)
+}
+
+func (s rdfsSchema) superProperties(property Term) []Term {
+	return append([]Term{property}, s.superPropertiesOf[property]...)
+}
@@ -0,0 +1,194 @@
+package ontology
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/observability"
+)
+
+const (
+	defaultFeedInterval  = 5 * time.Second
+	defaultFeedBatchSize = 500
+	// defaultFeedOverlap re-reads recently delivered rows on every poll: rows
+	// whose delivery committed late can carry a delivered_at the cursor has
+	// already passed. GraphIndex.Apply ignores the replays.
+	defaultFeedOverlap = 30 * time.Second
+)
+
+// ErrIndexNotReady is returned by IndexFeed.Query until the first sync finishes.
+var ErrIndexNotReady = errors.New("ontology index is still loading")
+
+// IndexFeedOption customises an IndexFeed.
+type IndexFeedOption func(*IndexFeed)
+
+// WithFeedInterval sets how often the feed polls for newly delivered rows.
+func WithFeedInterval(interval time.Duration) IndexFeedOption {
+	return func(f *IndexFeed) {
+		if interval > 0 {
+			f.interval = interval
+		}
+	}
+}
+
+// WithFeedLogger sets the logger used for sync failures.
+func WithFeedLogger(logger *slog.Logger) IndexFeedOption {
+	return func(f *IndexFeed) {
+		f.logger = logger
+	}
+}
+
+// IndexFeed keeps a GraphIndex in step with the triple store by applying the
+// outbox rows the ontology worker delivered, archived ones included, in
+// delivery order. Quarantined and pending rows are never applied.
+type IndexFeed struct {
+	db        *sql.DB
+	index     *GraphIndex
+	interval  time.Duration
+	overlap   time.Duration
+	batchSize int
+	logger    *slog.Logger
+
+	ready  atomic.Bool
+	cursor feedCursor
+	// latest is the newest delivered_at applied so far.
+	latest time.Time
+}
+
+type feedCursor struct {
+	deliveredAt time.Time
+	id          uuid.UUID
+}
+
+// NewIndexFeed builds a feed that applies delivered events to index.
+func NewIndexFeed(db *sql.DB, index *GraphIndex, opts ...IndexFeedOption) *IndexFeed {
+	f := &IndexFeed{
+		db:        db,
+		index:     index,
+		interval:  defaultFeedInterval,
+		overlap:   defaultFeedOverlap,
+		batchSize: defaultFeedBatchSize,
+		logger:    slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Run loads every delivered event, then polls for new ones until ctx is cancelled.
+func (f *IndexFeed) Run(ctx context.Context) error {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := f.Sync(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			f.logger.Error("ontology index sync failed", "error", err)
+		case err == nil && !f.ready.Load():
+			f.ready.Store(true)
+			f.logger.Info("ontology index loaded", "events", n, "triples", f.index.Len())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Ready reports whether the first sync has finished.
+func (f *IndexFeed) Ready() bool {
+	return f.ready.Load()
+}
+
+// Query evaluates a SPARQL SELECT query once the index has been loaded.
+func (f *IndexFeed) Query(ctx context.Context, query string) (*QueryResult, error) {
+	if !f.Ready() {
+		return nil, ErrIndexNotReady
+	}
+	return f.index.Query(ctx, query)
+}
+
+// Sync applies the rows delivered since the previous call and reports how
+// many changed the index. It must not run concurrently with Run.
+func (f *IndexFeed) Sync(ctx context.Context) (int, error) {
+	if f == nil || f.db == nil || f.index == nil {
+		return 0, errors.New("ontology index feed uninitialized")
+	}
+
+	applied := 0
+	for {
+		rows, err := f.db.QueryContext(ctx, listDeliveredEventsQuery,
+			f.cursor.deliveredAt, f.cursor.id, f.batchSize)
+		if err != nil {
+			return applied, fmt.Errorf("list delivered events: %w", err)
+		}
+
+		read := 0
+		for rows.Next() {
+			var (
+				id          uuid.UUID
+				payload     []byte
+				deliveredAt time.Time
+			)
+			if err := rows.Scan(&id, &payload, &deliveredAt); err != nil {
+				rows.Close()
+				return applied, err
+			}
+			read++
+			f.cursor = feedCursor{deliveredAt: deliveredAt, id: id}
+			if deliveredAt.After(f.latest) {
+				f.latest = deliveredAt
+			}
+
+			ok, err := f.index.Apply(payload, deliveredAt)
+			if err != nil {
+				// Delivered rows passed validation, so this only happens for
+				// rows written before it was enabled; skip rather than stall.
+				f.logger.Warn("ontology index skipped event", "id", id, "error", err)
+				continue
+			}
+			if ok {
+				applied++
+			}
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return applied, err
+		}
+		rows.Close()
+
+		if read < f.batchSize {
+			break
+		}
+	}
+
+	if !f.latest.IsZero() {
+		f.cursor = feedCursor{deliveredAt: f.latest.Add(-f.overlap)}
+	}
+	observability.OntologyIndexTriples.Set(float64(f.index.Len()))
+	return applied, nil
+}
+
+const listDeliveredEventsQuery = `
+    -- name: ListDeliveredOntologyEvents
+    SELECT id, payload, delivered_at FROM (
+        SELECT id, payload, delivered_at FROM ontology_outbox_archive
+        WHERE delivered_at >= $1
+        UNION ALL
+        SELECT id, payload, delivered_at FROM ontology_outbox
+        WHERE delivered_at >= $1
+    ) delivered
+    WHERE (delivered_at, id) > ($1, $2)
+    ORDER BY delivered_at, id
+    LIMIT $3`
@@ -0,0 +1,239 @@
+package ontology
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newTestIndex(t *testing.T) *GraphIndex {
+	t.Helper()
+	index, err := NewGraphIndex()
+	if err != nil {
+		t.Fatalf("NewGraphIndex: %v", err)
+	}
+	return index
+}
+
+func apply(t *testing.T, index *GraphIndex, evt Event, version time.Time) {
+	t.Helper()
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := index.Apply(payload, version); err != nil {
+		t.Fatalf("Apply %s: %v", evt.ID, err)
+	}
+}
+
+func query(t *testing.T, index *GraphIndex, q string) *QueryResult {
+	t.Helper()
+	result, err := index.Query(context.Background(), q)
+	if err != nil {
+		t.Fatalf("Query: %v\n%s", err, q)
+	}
+	return result
+}
+
+// column returns the values bound to name, sorted.
+func column(result *QueryResult, name string) []string {
+	var values []string
+	for _, solution := range result.Solutions {
+		if term, ok := solution[name]; ok {
+			values = append(values, term.Value)
+		}
+	}
+	slices.Sort(values)
+	return values
+}
+
+// taughtInCategory finds users who offered a skill in a completed session,
+// on either side, whose category is sk:SkillCategoryTech or below it.
+const taughtInCategory = `
+SELECT DISTINCT ?user WHERE {
+  ?session sk:sessionStatus sk:SessionStatusCompleted .
+  {
+    ?session sk:initiatedBy ?user ; sk:initiatorOffers ?name .
+  } UNION {
+    ?session sk:hasParticipant ?user ; sk:initiatedBy ?initiator ; sk:partnerOffers ?name .
+    FILTER(?user != ?initiator)
+  }
+  ?skill schema:name ?name ; sk:skillCategory ?category .
+  OPTIONAL { ?category skos:broaderTransitive ?parent }
+  FILTER(?category = sk:SkillCategoryTech || ?parent = sk:SkillCategoryTech)
+}`
+
+func TestGraphIndex_UsersWhoTaughtInCategory(t *testing.T) {
+	index := newTestIndex(t)
+	now := time.Now()
+
+	// A subcategory two levels below Tech, as the skill taxonomy would add.
+	for _, concept := range []struct{ id, broader string }{
+		{"sk:SkillCategoryWeb", ConceptSkillCategoryTech},
+		{"sk:SkillCategoryFrontend", "sk:SkillCategoryWeb"},
+	} {
+		evt := NewEvent(concept.id, "skos:Concept")
+		evt.Set("skos:broader", map[string]any{"@id": concept.broader})
+		apply(t, index, evt, now)
+	}
+
+	skills := map[string]string{
+		"Go":      ConceptSkillCategoryTech,
+		"React":   "sk:SkillCategoryFrontend",
+		"Cooking": ConceptSkillCategoryLifeSkills,
+	}
+	for name, category := range skills {
+		apply(t, index, NewSkillEvent(SkillEvent{
+			SkillID: uuid.New(), Name: name, CategoryIRI: category, UpdatedAt: now,
+		}, Activity{Type: ActivitySkillCreated}), now)
+	}
+
+	gopher, reactDev, cook, learner := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	sessions := []SessionEvent{
+		{InitiatorID: gopher, PartnerID: learner, InitiatorOffers: []string{"Go"}, PartnerOffers: []string{"Cooking"},
+			StatusIRI: ConceptSessionStatusCompleted},
+		{InitiatorID: learner, PartnerID: reactDev, InitiatorOffers: []string{"Cooking"}, PartnerOffers: []string{"React"},
+			StatusIRI: ConceptSessionStatusCompleted},
+		// Scheduled sessions have not taught anything yet.
+		{InitiatorID: cook, PartnerID: learner, InitiatorOffers: []string{"Go"},
+			StatusIRI: ConceptSessionStatusScheduled},
+	}
+	for _, session := range sessions {
+		session.SessionID = uuid.New()
+		apply(t, index, NewSessionStatusEvent(session, session.InitiatorID), now)
+	}
+
+	got := column(query(t, index, taughtInCategory), "user")
+	want := []string{ExpandIRI(userIRI(gopher)), ExpandIRI(userIRI(reactDev))}
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Fatalf("users = %v, want %v", got, want)
+	}
+}
+
+func TestGraphIndex_RDFSEntailment(t *testing.T) {
+	index, err := NewGraphIndexWithSchema([]byte(`
+@prefix ex: <https://example.org/> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+ex:Mentor rdfs:subClassOf ex:Teacher .
+ex:Teacher rdfs:subClassOf ex:Person .
+ex:mentors rdfs:subPropertyOf ex:teaches .
+ex:teaches rdfs:subPropertyOf ex:knows .
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range []string{
+		`{"@id": "https://example.org/ada", "@type": "https://example.org/Mentor",
+		  "https://example.org/mentors": {"@id": "https://example.org/bob"}}`,
+		`{"@id": "https://example.org/bob", "@type": "https://example.org/Person"}`,
+	} {
+		if _, err := index.Apply([]byte(doc), time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	people := query(t, index, `PREFIX ex: <https://example.org/> SELECT ?p WHERE { ?p a ex:Person }`)
+	if got := column(people, "p"); !slices.Equal(got, []string{"https://example.org/ada", "https://example.org/bob"}) {
+		t.Errorf("people = %v", got)
+	}
+	knows := query(t, index, `PREFIX ex: <https://example.org/> SELECT ?a ?b WHERE { ?a ex:knows ?b }`)
+	if len(knows.Solutions) != 1 || knows.Solutions[0]["b"] != IRI("https://example.org/bob") {
+		t.Errorf("ex:knows = %v, want ada knows bob", knows.Solutions)
+	}
+	types := query(t, index, `PREFIX ex: <https://example.org/> SELECT ?type WHERE { ex:ada a ?type }`)
+	if got := column(types, "type"); !slices.Equal(got, []string{
+		"https://example.org/Mentor", "https://example.org/Person", "https://example.org/Teacher",
+	}) {
+		t.Errorf("types of ada = %v", got)
+	}
+	predicates := query(t, index, `PREFIX ex: <https://example.org/> SELECT ?p WHERE { ex:ada ?p ex:bob }`)
+	if got := column(predicates, "p"); !slices.Equal(got, []string{
+		"https://example.org/knows", "https://example.org/mentors", "https://example.org/teaches",
+	}) {
+		t.Errorf("predicates from ada to bob = %v", got)
+	}
+}
+
+func TestGraphIndex_UpsertsResources(t *testing.T) {
+	index := newTestIndex(t)
+	user := testUser()
+	registered := time.Now()
+
+	apply(t, index, NewUserRegisteredEvent(user), registered)
+	user.DisplayName = "Ada Lovelace"
+	apply(t, index, NewUserVerifiedEvent(user), registered.Add(time.Minute))
+
+	// A replay of the first event arrives late and must not win.
+	user.DisplayName = "Ada"
+	payload, _ := json.Marshal(NewUserRegisteredEvent(user))
+	applied, err := index.Apply(payload, registered)
+	if err != nil || applied {
+		t.Fatalf("stale Apply = %v, %v; want it ignored", applied, err)
+	}
+
+	names := query(t, index, `SELECT ?name WHERE { ?u a sk:User ; schema:name ?name }`)
+	if got := column(names, "name"); !slices.Equal(got, []string{"Ada Lovelace"}) {
+		t.Errorf("names = %v, want only the latest snapshot", got)
+	}
+	activities := query(t, index, `
+		SELECT ?activity WHERE { ?activity a prov:Activity ; prov:generated ?u . ?u a sk:User }`)
+	if len(activities.Solutions) != 2 {
+		t.Errorf("activities = %d, want both transitions kept", len(activities.Solutions))
+	}
+}
+
+func TestIndexFeed_AppliesDeliveredRowsInOrder(t *testing.T) {
+	db := openOutboxTestDB(t)
+	ctx := context.Background()
+
+	user := testUser()
+	registered, _ := json.Marshal(NewUserRegisteredEvent(user))
+	user.DisplayName = "Ada Lovelace"
+	verified, _ := json.Marshal(NewUserVerifiedEvent(user))
+	other := testUser()
+	other.ID = uuid.New()
+	pending, _ := json.Marshal(NewUserRegisteredEvent(other))
+
+	if _, err := db.Exec(`
+		INSERT INTO ontology_outbox_archive (id, event_type, payload, attempts, created_at, delivered_at)
+		VALUES ($1, $2, $3, 1, NOW() - INTERVAL '2 hours', NOW() - INTERVAL '1 hour')`,
+		uuid.New(), ActivityUserRegistered, registered); err != nil {
+		t.Fatalf("insert archived: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO ontology_outbox (id, event_type, payload, delivered_at)
+		VALUES ($1, $2, $3, NOW()), ($4, $5, $6, NULL)`,
+		uuid.New(), ActivityUserVerified, verified, uuid.New(), ActivityUserRegistered, pending); err != nil {
+		t.Fatalf("insert outbox: %v", err)
+	}
+
+	feed := NewIndexFeed(db, newTestIndex(t))
+	if _, err := feed.Query(ctx, `SELECT * WHERE { ?s ?p ?o }`); !errors.Is(err, ErrIndexNotReady) {
+		t.Fatalf("Query before sync = %v, want ErrIndexNotReady", err)
+	}
+	applied, err := feed.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if applied != 2 {
+		t.Fatalf("applied %d rows, want the two delivered ones", applied)
+	}
+	names := query(t, feed.index, `SELECT ?name WHERE { ?u a sk:User ; schema:name ?name }`)
+	if got := column(names, "name"); !slices.Equal(got, []string{"Ada Lovelace"}) {
+		t.Errorf("names = %v, want the latest snapshot and no pending user", got)
+	}
+
+	// The next sync re-reads the overlap window without regressing anything.
+	if _, err := feed.Sync(ctx); err != nil {
+		t.Fatalf("second Sync: %v", err)
+	}
+	if got := column(query(t, feed.index, `SELECT ?name WHERE { ?u schema:name ?name }`), "name"); !slices.Equal(got, []string{"Ada Lovelace"}) {
+		t.Errorf("names after resync = %v", got)
+	}
+}
@@ -0,0 +1,511 @@
+package ontology
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	generated "github.com/FACorreiaa/skillsphere-api/ontology"
+)
+
+// bundledContextJSON is the generated copy of DefaultContext so events can be
+// expanded without fetching it from ontology.skillsphere.dev.
+var bundledContextJSON = generated.Context
+
+var (
+	bundledContextDefinition = mustDecodeBundledContext()
+	bundledContext           = mustParseBundledContext()
+)
+
+func mustDecodeBundledContext() any {
+	var doc map[string]any
+	if err := decodeJSON(bundledContextJSON, &doc); err != nil {
+		panic(fmt.Sprintf("ontology: decode bundled context: %v", err))
+	}
+	return doc["@context"]
+}
+
+func mustParseBundledContext() *jsonldContext {
+	ctx, err := (&jsonldContext{terms: map[string]termDefinition{}}).merge(DefaultContext)
+	if err != nil {
+		panic(fmt.Sprintf("ontology: parse bundled context: %v", err))
+	}
+	return ctx
+}
+
+// termDefinition is the subset of a JSON-LD term definition this package honours.
+type termDefinition struct {
+	id string
+	// typ is "@id", "@vocab" or a datatype IRI used to coerce string values.
+	typ       string
+	container string
+	language  *string
+}
+
+// jsonldContext is an active context: term definitions plus @vocab and @language.
+type jsonldContext struct {
+	terms    map[string]termDefinition
+	vocab    string
+	language string
+}
+
+// merge processes a local context (IRI, object or array of both) on top of c.
+// Only DefaultContext may be referenced by IRI; it resolves to the bundled copy.
+func (c *jsonldContext) merge(local any) (*jsonldContext, error) {
+	out := &jsonldContext{terms: maps.Clone(c.terms), vocab: c.vocab, language: c.language}
+
+	switch v := local.(type) {
+	case nil:
+		return &jsonldContext{terms: map[string]termDefinition{}}, nil
+	case string:
+		if v != DefaultContext {
+			return nil, fmt.Errorf("remote context %q is not bundled", v)
+		}
+		return out.merge(bundledContextDefinition)
+	case []any:
+		var err error
+		for _, item := range v {
+			if out, err = out.merge(item); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case map[string]any:
+		for key, value := range v {
+			switch key {
+			case "@vocab":
+				vocab, _ := value.(string)
+				out.vocab = vocab
+			case "@language":
+				language, _ := value.(string)
+				out.language = strings.ToLower(language)
+			case "@version", "@base", "@protected", "@propagate":
+				// Accepted and ignored.
+			default:
+				if strings.HasPrefix(key, "@") {
+					return nil, fmt.Errorf("unsupported context keyword %s", key)
+				}
+				if value == nil {
+					delete(out.terms, key)
+					continue
+				}
+				def, err := parseTermDefinition(value)
+				if err != nil {
+					return nil, fmt.Errorf("term %s: %w", key, err)
+				}
+				out.terms[key] = def
+			}
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("invalid @context of type %T", local)
+	}
+}
+
+func parseTermDefinition(value any) (termDefinition, error) {
+	switch v := value.(type) {
+	case string:
+		return termDefinition{id: v}, nil
+	case map[string]any:
+		var def termDefinition
+		for key, raw := range v {
+			s, isString := raw.(string)
+			switch key {
+			case "@id":
+				def.id = s
+			case "@type":
+				def.typ = s
+			case "@container":
+				def.container = s
+			case "@language":
+				if raw == nil {
+					empty := ""
+					def.language = &empty
+				} else {
+					lang := strings.ToLower(s)
+					def.language = &lang
+				}
+				continue
+			default:
+				return def, fmt.Errorf("unsupported keyword %s", key)
+			}
+			if !isString {
+				return def, fmt.Errorf("%s must be a string", key)
+			}
+		}
+		return def, nil
+	default:
+		return termDefinition{}, fmt.Errorf("invalid definition of type %T", value)
+	}
+}
+
+// prefixes returns every term that maps directly to a namespace IRI.
+func (c *jsonldContext) prefixes() map[string]string {
+	out := make(map[string]string)
+	for term, def := range c.terms {
+		if !strings.Contains(term, ":") && (strings.HasSuffix(def.id, "/") || strings.HasSuffix(def.id, "#")) {
+			out[term] = def.id
+		}
+	}
+	return out
+}
+
+// expandIRI expands a term, compact IRI or absolute IRI. With vocab set, bare
+// terms resolve against term definitions and @vocab as property names do;
+// otherwise they are treated as (unsupported) relative IRIs and rejected.
+func (c *jsonldContext) expandIRI(value string, vocab bool) (string, bool) {
+	return c.expandIRIDepth(value, vocab, 0)
+}
+
+func (c *jsonldContext) expandIRIDepth(value string, vocab bool, depth int) (string, bool) {
+	if depth > 8 || value == "" {
+		return "", false
+	}
+	if strings.HasPrefix(value, "@") {
+		return value, true
+	}
+	if vocab {
+		if def, ok := c.terms[value]; ok && def.id != "" && def.id != value {
+			return c.expandIRIDepth(def.id, true, depth+1)
+		}
+	}
+	if prefix, suffix, ok := strings.Cut(value, ":"); ok {
+		if prefix == "_" || strings.HasPrefix(suffix, "//") {
+			return value, true
+		}
+		if def, known := c.terms[prefix]; known && def.id != "" {
+			ns, ok := c.expandIRIDepth(def.id, true, depth+1)
+			return ns + suffix, ok
+		}
+		return value, true
+	}
+	if vocab && c.vocab != "" {
+		return c.vocab + value, true
+	}
+	return "", false
+}
+
+// ExpandIRI turns a compact IRI such as "sk:User/42" into an absolute IRI using
+// the bundled context. Values that cannot be expanded are returned unchanged.
+func ExpandIRI(value string) string {
+	if iri, ok := bundledContext.expandIRI(value, false); ok {
+		return iri
+	}
+	return value
+}
+
+// ToRDF expands a JSON-LD document against its @context (DefaultContext is
+// served from the bundled copy) and returns its triples in document order.
+// Blank nodes are labelled b0, b1, … per call. Nested node objects, @list,
+// @value objects, @language and type coercion are supported; @graph,
+// @reverse and remote contexts other than DefaultContext are not.
+func ToRDF(document []byte) ([]Triple, error) {
+	var root any
+	if err := decodeJSON(document, &root); err != nil {
+		return nil, fmt.Errorf("decode JSON-LD: %w", err)
+	}
+
+	x := &rdfExpander{}
+	ctx := &jsonldContext{terms: map[string]termDefinition{}}
+	switch v := root.(type) {
+	case map[string]any:
+		if _, err := x.node(ctx, v); err != nil {
+			return nil, err
+		}
+	case []any:
+		for _, item := range v {
+			obj, ok := item.(map[string]any)
+			if !ok {
+				return nil, errors.New("top-level array must contain node objects")
+			}
+			if _, err := x.node(ctx, obj); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, errors.New("JSON-LD document must be an object or array")
+	}
+	return x.triples, nil
+}
+
+// Triples expands the event into RDF triples.
+func (e Event) Triples() ([]Triple, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return ToRDF(payload)
+}
+
+type rdfExpander struct {
+	triples []Triple
+	blank   int
+	// labels maps blank node labels in the document to generated ones so they
+	// cannot collide with nodes labelled by the expander.
+	labels map[string]Term
+}
+
+func (x *rdfExpander) newBlankNode() Term {
+	term := BlankNode("b" + strconv.Itoa(x.blank))
+	x.blank++
+	return term
+}
+
+func (x *rdfExpander) emit(s, p, o Term) {
+	x.triples = append(x.triples, Triple{Subject: s, Predicate: p, Object: o})
+}
+
+// node emits the triples of a node object and returns its subject term.
+func (x *rdfExpander) node(ctx *jsonldContext, obj map[string]any) (Term, error) {
+	if local, ok := obj["@context"]; ok {
+		var err error
+		if ctx, err = ctx.merge(local); err != nil {
+			return Term{}, err
+		}
+	}
+
+	subject := Term{}
+	if rawID, ok := obj["@id"]; ok {
+		id, isString := rawID.(string)
+		if !isString {
+			return Term{}, errors.New("@id must be a string")
+		}
+		iri, ok := ctx.expandIRI(id, false)
+		if !ok {
+			return Term{}, fmt.Errorf("cannot expand @id %q", id)
+		}
+		subject = x.resource(iri)
+	} else {
+		subject = x.newBlankNode()
+	}
+
+	if rawType, ok := obj["@type"]; ok {
+		for _, item := range asArray(rawType) {
+			typ, isString := item.(string)
+			if !isString {
+				return Term{}, errors.New("@type must be a string or array of strings")
+			}
+			iri, ok := ctx.expandIRI(typ, true)
+			if !ok {
+				return Term{}, fmt.Errorf("cannot expand @type %q", typ)
+			}
+			x.emit(subject, IRI(rdfType), x.resource(iri))
+		}
+	}
+
+	// Sorted keys keep output deterministic regardless of map iteration.
+	for _, key := range slices.Sorted(maps.Keys(obj)) {
+		switch key {
+		case "@context", "@id", "@type":
+			continue
+		case "@graph", "@reverse", "@included", "@nest":
+			return Term{}, fmt.Errorf("%s is not supported", key)
+		}
+		if strings.HasPrefix(key, "@") {
+			continue
+		}
+
+		predicate, ok := ctx.expandIRI(key, true)
+		if !ok || strings.HasPrefix(predicate, "_:") || !strings.Contains(predicate, ":") {
+			// JSON-LD drops properties that do not expand to an IRI.
+			continue
+		}
+		def := ctx.terms[key]
+
+		if def.container == "@list" {
+			head, err := x.list(ctx, def, asArray(obj[key]))
+			if err != nil {
+				return Term{}, fmt.Errorf("%s: %w", key, err)
+			}
+			x.emit(subject, IRI(predicate), head)
+			continue
+		}
+
+		for _, value := range asArray(obj[key]) {
+			object, ok, err := x.object(ctx, def, value)
+			if err != nil {
+				return Term{}, fmt.Errorf("%s: %w", key, err)
+			}
+			if ok {
+				x.emit(subject, IRI(predicate), object)
+			}
+		}
+	}
+	return subject, nil
+}
+
+// object converts a property value to an RDF term; ok is false for null values.
+func (x *rdfExpander) object(ctx *jsonldContext, def termDefinition, value any) (Term, bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return Term{}, false, nil
+	case string:
+		switch def.typ {
+		case "@id", "@vocab":
+			iri, ok := ctx.expandIRI(v, def.typ == "@vocab")
+			if !ok {
+				return Term{}, false, fmt.Errorf("cannot expand IRI %q", v)
+			}
+			return x.resource(iri), true, nil
+		case "":
+			language := ctx.language
+			if def.language != nil {
+				language = *def.language
+			}
+			if language != "" {
+				return LangLiteral(v, language), true, nil
+			}
+			return Literal(v, xsdString), true, nil
+		default:
+			datatype, _ := ctx.expandIRI(def.typ, true)
+			return Literal(v, datatype), true, nil
+		}
+	case bool:
+		datatype := xsdBoolean
+		if def.typ != "" && !strings.HasPrefix(def.typ, "@") {
+			datatype, _ = ctx.expandIRI(def.typ, true)
+		}
+		return Literal(strconv.FormatBool(v), datatype), true, nil
+	case json.Number:
+		datatype := ""
+		if def.typ != "" && !strings.HasPrefix(def.typ, "@") {
+			datatype, _ = ctx.expandIRI(def.typ, true)
+		}
+		return numberLiteral(v, datatype)
+	case map[string]any:
+		if raw, ok := v["@value"]; ok {
+			return valueObject(ctx, v, raw)
+		}
+		if list, ok := v["@list"]; ok {
+			head, err := x.list(ctx, def, asArray(list))
+			return head, err == nil, err
+		}
+		subject, err := x.node(ctx, v)
+		return subject, err == nil, err
+	default:
+		return Term{}, false, fmt.Errorf("unsupported value of type %T", value)
+	}
+}
+
+// list emits an rdf:List for items and returns its head.
+func (x *rdfExpander) list(ctx *jsonldContext, def termDefinition, items []any) (Term, error) {
+	def.container = ""
+	var terms []Term
+	for _, item := range items {
+		term, ok, err := x.object(ctx, def, item)
+		if err != nil {
+			return Term{}, err
+		}
+		if ok {
+			terms = append(terms, term)
+		}
+	}
+	if len(terms) == 0 {
+		return IRI(rdfNil), nil
+	}
+
+	head := x.newBlankNode()
+	node := head
+	for i, term := range terms {
+		x.emit(node, IRI(rdfFirst), term)
+		next := IRI(rdfNil)
+		if i < len(terms)-1 {
+			next = x.newBlankNode()
+		}
+		x.emit(node, IRI(rdfRest), next)
+		node = next
+	}
+	return head, nil
+}
+
+func valueObject(ctx *jsonldContext, obj map[string]any, raw any) (Term, bool, error) {
+	datatype := ""
+	if typ, ok := obj["@type"].(string); ok {
+		datatype, _ = ctx.expandIRI(typ, true)
+	}
+	switch v := raw.(type) {
+	case nil:
+		return Term{}, false, nil
+	case string:
+		if language, ok := obj["@language"].(string); ok && datatype == "" {
+			return LangLiteral(v, language), true, nil
+		}
+		return Literal(v, datatype), true, nil
+	case bool:
+		if datatype == "" {
+			datatype = xsdBoolean
+		}
+		return Literal(strconv.FormatBool(v), datatype), true, nil
+	case json.Number:
+		return numberLiteral(v, datatype)
+	default:
+		return Term{}, false, fmt.Errorf("invalid @value of type %T", raw)
+	}
+}
+
+// numberLiteral follows JSON-LD's RDF conversion: integral numbers become
+// xsd:integer and everything else a canonical xsd:double such as "1.5E0".
+func numberLiteral(n json.Number, datatype string) (Term, bool, error) {
+	f, err := n.Float64()
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return Term{}, false, fmt.Errorf("invalid number %s", n)
+	}
+	integral := f == math.Trunc(f) && math.Abs(f) < 1e21
+	if integral && datatype != xsdDouble {
+		if datatype == "" {
+			datatype = xsdInteger
+		}
+		return Literal(strconv.FormatFloat(f, 'f', -1, 64), datatype), true, nil
+	}
+	if datatype == "" {
+		datatype = xsdDouble
+	}
+	return Literal(canonicalDouble(f), datatype), true, nil
+}
+
+// canonicalDouble renders f in XSD canonical form, e.g. 1.5E0 or 1.0E-3.
+func canonicalDouble(f float64) string {
+	s := strconv.FormatFloat(f, 'E', -1, 64)
+	mantissa, exponent, _ := strings.Cut(s, "E")
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+	exp, _ := strconv.Atoi(exponent)
+	return mantissa + "E" + strconv.Itoa(exp)
+}
+
+// resource returns the term for an expanded IRI, relabelling blank nodes.
+func (x *rdfExpander) resource(iri string) Term {
+	label, ok := strings.CutPrefix(iri, "_:")
+	if !ok {
+		return IRI(iri)
+	}
+	if x.labels == nil {
+		x.labels = make(map[string]Term)
+	}
+	term, seen := x.labels[label]
+	if !seen {
+		term = x.newBlankNode()
+		x.labels[label] = term
+	}
+	return term
+}
+
+func asArray(value any) []any {
+	if items, ok := value.([]any); ok {
+		return items
+	}
+	return []any{value}
+}
+
+func decodeJSON(data []byte, out any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(out)
+}
@@ -0,0 +1,209 @@
+package ontology
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	generated "github.com/FACorreiaa/skillsphere-api/ontology"
+)
+
+func sampleEvent() Event {
+	evt := NewEvent("sk:Match/1", ClassMatch)
+	evt.SetTimestamp(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	evt.Set("sk:hasMatch", "sk:User/1")
+	evt.Set("sk:matchScore", 0.75)
+	evt.Set("sk:explanation", "Shares \"Go\"\nand RDF")
+	evt.Set("sk:skillMatches", []map[string]any{{
+		"sk:skillName":       "Go",
+		"sk:userProficiency": 4,
+		"sk:isComplementary": false,
+	}})
+	return evt
+}
+
+func TestEventTriples_NTriples(t *testing.T) {
+	triples, err := sampleEvent().Triples()
+	if err != nil {
+		t.Fatalf("Triples: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteNTriples(&buf, triples); err != nil {
+		t.Fatal(err)
+	}
+	want := `<https://ontology.skillsphere.dev/schema#Match/1> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <https://ontology.skillsphere.dev/schema#Match> .
+<https://ontology.skillsphere.dev/schema#Match/1> <https://schema.org/dateModified> "2025-03-01T12:00:00Z"^^<http://www.w3.org/2001/XMLSchema#dateTime> .
+<https://ontology.skillsphere.dev/schema#Match/1> <https://ontology.skillsphere.dev/schema#explanation> "Shares \"Go\"\nand RDF" .
+<https://ontology.skillsphere.dev/schema#Match/1> <https://ontology.skillsphere.dev/schema#hasMatch> <https://ontology.skillsphere.dev/schema#User/1> .
+<https://ontology.skillsphere.dev/schema#Match/1> <https://ontology.skillsphere.dev/schema#matchScore> "7.5E-1"^^<http://www.w3.org/2001/XMLSchema#double> .
+_:b0 <https://ontology.skillsphere.dev/schema#isComplementary> "false"^^<http://www.w3.org/2001/XMLSchema#boolean> .
+_:b0 <https://ontology.skillsphere.dev/schema#skillName> "Go" .
+_:b0 <https://ontology.skillsphere.dev/schema#userProficiency> "4"^^<http://www.w3.org/2001/XMLSchema#integer> .
+<https://ontology.skillsphere.dev/schema#Match/1> <https://ontology.skillsphere.dev/schema#skillMatches> _:b0 .
+`
+	if got := buf.String(); got != want {
+		t.Errorf("N-Triples mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	if err := WriteNQuads(&buf, "urn:graph:Match", triples[:1]); err != nil {
+		t.Fatal(err)
+	}
+	wantQuad := "<https://ontology.skillsphere.dev/schema#Match/1> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <https://ontology.skillsphere.dev/schema#Match> <urn:graph:Match> .\n"
+	if got := buf.String(); got != wantQuad {
+		t.Errorf("N-Quads = %q, want %q", got, wantQuad)
+	}
+}
+
+func TestEventTriples_Turtle(t *testing.T) {
+	evt := NewEvent("sk:User/1", ClassUser)
+	evt.Set("schema:name", "Ada")
+	evt.Set("sk:isActive", true)
+	evt.Set("sk:hasParticipant", []string{"sk:User/2", "sk:User/3"})
+	triples, err := evt.Triples()
+	if err != nil {
+		t.Fatalf("Triples: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTurtle(&buf, triples); err != nil {
+		t.Fatal(err)
+	}
+	want := `@prefix schema: <https://schema.org/> .
+@prefix sk: <https://ontology.skillsphere.dev/schema#> .
+
+<https://ontology.skillsphere.dev/schema#User/1> a sk:User ;
+  schema:name "Ada" ;
+  sk:hasParticipant <https://ontology.skillsphere.dev/schema#User/2> ,
+    <https://ontology.skillsphere.dev/schema#User/3> ;
+  sk:isActive true .
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Turtle mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToRDF_InlineContext(t *testing.T) {
+	doc := `{
+		"@context": [
+			"https://ontology.skillsphere.dev/generated.context.jsonld",
+			{"@vocab": "https://example.com/vocab#", "@language": "en",
+			 "steps": {"@id": "sk:steps", "@container": "@list"}}
+		],
+		"@id": "_:lesson",
+		"title": "Intro",
+		"level": {"@value": "2", "@type": "xsd:integer"},
+		"steps": ["one", "two"],
+		"unknown:thing": null
+	}`
+	triples, err := ToRDF([]byte(doc))
+	if err != nil {
+		t.Fatalf("ToRDF: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteNTriples(&buf, triples); err != nil {
+		t.Fatal(err)
+	}
+	want := `_:b0 <https://example.com/vocab#level> "2"^^<http://www.w3.org/2001/XMLSchema#integer> .
+_:b1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#first> "one"@en .
+_:b1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#rest> _:b2 .
+_:b2 <http://www.w3.org/1999/02/22-rdf-syntax-ns#first> "two"@en .
+_:b2 <http://www.w3.org/1999/02/22-rdf-syntax-ns#rest> <http://www.w3.org/1999/02/22-rdf-syntax-ns#nil> .
+_:b0 <https://ontology.skillsphere.dev/schema#steps> _:b1 .
+_:b0 <https://example.com/vocab#title> "Intro"@en .
+`
+	if got := buf.String(); got != want {
+		t.Errorf("N-Triples mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToRDF_RejectsUnbundledRemoteContext(t *testing.T) {
+	_, err := ToRDF([]byte(`{"@context": "https://example.com/other.jsonld", "@id": "urn:x"}`))
+	if err == nil || !strings.Contains(err.Error(), "not bundled") {
+		t.Fatalf("ToRDF error = %v, want a remote context error", err)
+	}
+}
+
+func TestBundledContextMatchesNamespaces(t *testing.T) {
+	prefixes := bundledContext.prefixes()
+	if prefixes["sk"] != SkillSphereNS || prefixes["schema"] != SchemaNS {
+		t.Errorf("bundled prefixes = %v", prefixes)
+	}
+	if got := ExpandIRI("sk:User/1"); got != SkillSphereNS+"User/1" {
+		t.Errorf("ExpandIRI = %q", got)
+	}
+}
+
+func TestGeneratedVocabularyDeclaresEventProperties(t *testing.T) {
+	triples, err := ParseTurtle(generated.Schemes)
+	if err != nil {
+		t.Fatalf("ParseTurtle(generated.ttl): %v", err)
+	}
+	graph := indexGraph(triples)
+
+	owl := "http://www.w3.org/2002/07/owl#"
+	cases := map[string]string{
+		PropHasParticipant:            owl + "ObjectProperty",
+		PropMatchScore:                owl + "DatatypeProperty",
+		ClassSession:                  owl + "Class",
+		ConceptSessionStatusScheduled: SkillSphereNS + "SessionStatusConcept",
+	}
+	for compact, typ := range cases {
+		subject := IRI(ExpandIRI(compact))
+		if !slices.Contains(graph.props[subject][rdfType], IRI(typ)) {
+			t.Errorf("%s is not declared as <%s>", compact, typ)
+		}
+	}
+	if got := graph.props[IRI(ExpandIRI(PropHasParticipant))]["http://www.w3.org/2000/01/rdf-schema#domain"]; !slices.Equal(got, []Term{IRI(ExpandIRI(ClassSession))}) {
+		t.Errorf("sk:hasParticipant domain = %v", got)
+	}
+}
+
+func TestCanonicalDouble(t *testing.T) {
+	cases := map[float64]string{
+		0.75:    "7.5E-1",
+		1.5:     "1.5E0",
+		1e25:    "1.0E25",
+		-0.001:  "-1.0E-3",
+		12345.6: "1.23456E4",
+	}
+	for in, want := range cases {
+		if got := canonicalDouble(in); got != want {
+			t.Errorf("canonicalDouble(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSPARQLStoreClient_SendsOneUpdate(t *testing.T) {
+	var updates []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		updates = append(updates, r.URL.Path+" "+r.Header.Get("Content-Type")+"\n"+string(raw))
+	}))
+	defer server.Close()
+
+	client, err := NewSPARQLStoreClient(SPARQLStoreConfig{UpdateEndpoint: server.URL + "/update"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Insert(context.Background(), []byte(`{"@context":"`+DefaultContext+`","@id":"sk:User/1","@type":"sk:User"}`)); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	want := "/update application/sparql-update\n" +
+		deleteSubjectUpdate(DefaultGraphBase+"User", SkillSphereNS+"User/1") + " ;\n" +
+		"INSERT DATA { GRAPH <https://ontology.skillsphere.dev/graph/User> {\n" +
+		"<https://ontology.skillsphere.dev/schema#User/1> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <https://ontology.skillsphere.dev/schema#User> .\n" +
+		"} }"
+	if len(updates) != 1 || updates[0] != want {
+		t.Errorf("requests = %q, want one update %q", updates, want)
+	}
+}
@@ -0,0 +1,9 @@
+package ontology
+
+// Namespaces used by the compact IRIs in emitted events. They match the
+// prefixes declared in the generated JSON-LD context.
+const (
+	SkillSphereNS = "https://ontology.skillsphere.dev/schema#"
+	SchemaNS      = "https://schema.org/"
+	ProvNS        = "http://www.w3.org/ns/prov#"
+)
@@ -11,9 +11,17 @@ import (
 	"github.com/google/uuid"
 )
 
+// OutboxChannel is the Postgres NOTIFY channel signalled on every outbox insert.
+const OutboxChannel = "ontology_outbox"
+
 const insertOutboxStmt = `
-	INSERT INTO ontology_outbox (id, event_type, payload, created_at)
-	VALUES ($1, $2, $3, $4)
+	-- name: InsertOntologyOutbox
+	WITH inserted AS (
+		INSERT INTO ontology_outbox (id, event_type, aggregate_id, payload, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+		RETURNING event_type
+	)
+	SELECT pg_notify('` + OutboxChannel + `', event_type) FROM inserted
 `
 
 // OutboxEmitter persists ontology events into a relational outbox table.
@@ -30,7 +38,8 @@ func NewOutboxEmitter(db *sql.DB, logger *slog.Logger) *OutboxEmitter {
 	}
 }
 
-// Emit marshals the event and inserts it into the outbox.
+// Emit marshals the event, inserts it into the outbox and notifies
+// OutboxChannel so listening workers pick it up without waiting to poll.
 func (e *OutboxEmitter) Emit(ctx context.Context, event Event) error {
 	if e == nil || e.db == nil {
 		return nil
@@ -46,6 +55,7 @@ func (e *OutboxEmitter) Emit(ctx context.Context, event Event) error {
 		insertOutboxStmt,
 		uuid.New(),
 		event.Type,
+		event.ID,
 		payload,
 		time.Now().UTC(),
 	); err != nil {
@@ -1,16 +1,47 @@
 package ontology
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
 	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+	"github.com/FACorreiaa/skillsphere-api/pkg/kafka"
+	"github.com/FACorreiaa/skillsphere-api/pkg/observability"
+)
+
+const (
+	defaultOutboxBatchSize   = 100
+	defaultOutboxConcurrency = 8
+	defaultOutboxLease       = time.Minute
+	defaultOutboxMaxAttempts = 10
+	defaultRetryBaseDelay    = 5 * time.Second
+	defaultRetryMaxDelay     = time.Hour
+
+	// maxStoredErrorLength keeps last_error readable and bounded.
+	maxStoredErrorLength = 2000
+)
+
+// Kafka record headers set on every published event.
+const (
+	HeaderEventID       = "event-id"
+	HeaderEventType     = "event-type"
+	HeaderJSONLDContext = "jsonld-context"
 )
 
 // KafkaProducer publishes envelopes to Kafka or any stream bus.
 type KafkaProducer interface {
-	Publish(ctx context.Context, topic string, payload []byte) error
+	Publish(ctx context.Context, topic string, msg kafka.Message) error
 }
 
 // TripleStoreClient ingests JSON-LD payloads into a graph database.
@@ -18,118 +49,535 @@ type TripleStoreClient interface {
 	Insert(ctx context.Context, payload []byte) error
 }
 
+// RetryPolicy controls how failed outbox rows are rescheduled.
+type RetryPolicy struct {
+	// MaxAttempts dead-letters a row after this many failed deliveries.
+	MaxAttempts int
+	// BaseDelay is the wait after the first failure; it doubles per attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between attempts.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: defaultOutboxMaxAttempts,
+		BaseDelay:   defaultRetryBaseDelay,
+		MaxDelay:    defaultRetryMaxDelay,
+	}
+}
+
+// Backoff returns the delay before the next attempt after attempts failures.
+func (r RetryPolicy) Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := r.BaseDelay
+	for i := 1; i < attempts && delay < r.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, r.MaxDelay)
+}
+
+// OutboxOption customises an OutboxProcessor.
+type OutboxOption func(*OutboxProcessor)
+
+// WithRetryPolicy overrides the retry policy; zero fields keep their defaults.
+func WithRetryPolicy(policy RetryPolicy) OutboxOption {
+	return func(p *OutboxProcessor) {
+		if policy.MaxAttempts > 0 {
+			p.retry.MaxAttempts = policy.MaxAttempts
+		}
+		if policy.BaseDelay > 0 {
+			p.retry.BaseDelay = policy.BaseDelay
+		}
+		if policy.MaxDelay > 0 {
+			p.retry.MaxDelay = policy.MaxDelay
+		}
+	}
+}
+
+// WithOutboxLogger sets the logger used for delivery failures.
+func WithOutboxLogger(logger *slog.Logger) OutboxOption {
+	return func(p *OutboxProcessor) {
+		p.logger = logger
+	}
+}
+
+// WithValidator validates each event against SHACL shapes before delivery.
+// Events that do not conform are quarantined with their validation report
+// instead of reaching Kafka or the triple store.
+func WithValidator(validator *Validator) OutboxOption {
+	return func(p *OutboxProcessor) {
+		p.validator = validator
+	}
+}
+
+// WithBatchSize sets how many rows one ProcessBatch call claims.
+func WithBatchSize(n int) OutboxOption {
+	return func(p *OutboxProcessor) {
+		if n > 0 {
+			p.batchSize = n
+		}
+	}
+}
+
+// WithConcurrency sets how many claimed rows are dispatched at once.
+func WithConcurrency(n int) OutboxOption {
+	return func(p *OutboxProcessor) {
+		if n > 0 {
+			p.concurrency = n
+		}
+	}
+}
+
+// WithLease sets how long claimed rows are reserved for this worker. It must
+// outlast the slowest dispatch, or another worker may deliver the row again.
+func WithLease(lease time.Duration) OutboxOption {
+	return func(p *OutboxProcessor) {
+		if lease > 0 {
+			p.lease = lease
+		}
+	}
+}
+
+// Notifier delivers Postgres notifications; *db.DB implements it.
+type Notifier interface {
+	Listen(ctx context.Context, channel string, onConnect func(context.Context), fn func(db.Notification)) error
+}
+
+// WithNotifier wakes Run as soon as rows are inserted instead of waiting for
+// the next poll.
+func WithNotifier(notifier Notifier) OutboxOption {
+	return func(p *OutboxProcessor) {
+		p.notifier = notifier
+	}
+}
+
+// WithWorkerID sets the claimed_by value recorded on leased rows.
+func WithWorkerID(id string) OutboxOption {
+	return func(p *OutboxProcessor) {
+		if id != "" {
+			p.workerID = id
+		}
+	}
+}
+
 // OutboxProcessor drains ontology events into downstream sinks.
 type OutboxProcessor struct {
-	db        *sql.DB
-	topic     string
-	kafka     KafkaProducer
-	triple    TripleStoreClient
-	batchSize int
+	db          *sql.DB
+	topic       string
+	kafka       KafkaProducer
+	triple      TripleStoreClient
+	validator   *Validator
+	notifier    Notifier
+	batchSize   int
+	concurrency int
+	lease       time.Duration
+	workerID    string
+	retry       RetryPolicy
+	logger      *slog.Logger
 }
 
 // NewOutboxProcessor creates a processor with the provided dependencies.
-func NewOutboxProcessor(db *sql.DB, topic string, kafka KafkaProducer, triple TripleStoreClient) *OutboxProcessor {
-	return &OutboxProcessor{
-		db:        db,
-		topic:     topic,
-		kafka:     kafka,
-		triple:    triple,
-		batchSize: 100,
+func NewOutboxProcessor(db *sql.DB, topic string, kafka KafkaProducer, triple TripleStoreClient, opts ...OutboxOption) *OutboxProcessor {
+	p := &OutboxProcessor{
+		db:          db,
+		topic:       topic,
+		kafka:       kafka,
+		triple:      triple,
+		batchSize:   defaultOutboxBatchSize,
+		concurrency: defaultOutboxConcurrency,
+		lease:       defaultOutboxLease,
+		workerID:    defaultWorkerID(),
+		retry:       DefaultRetryPolicy(),
+		logger:      slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	return p
 }
 
-// Run continuously drains the outbox with the supplied polling interval.
+// BatchResult summarises one ProcessBatch call.
+type BatchResult struct {
+	Delivered    int
+	Failed       int
+	DeadLettered int
+	Quarantined  int
+}
+
+// Run drains the outbox until it is empty, then sleeps until a notification
+// arrives (see WithNotifier) or the poll interval elapses, whichever is first.
+// The interval is a safety net for missed notifications and for rows whose
+// retry backoff expires. Batch errors are logged and retried on the next wake-up
+// rather than stopping the worker.
 func (p *OutboxProcessor) Run(ctx context.Context, interval time.Duration) error {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	// Bursts of notifications coalesce into one pending wake-up. Reconnecting
+	// also wakes Run, since rows may have arrived while disconnected.
+	wake := make(chan struct{}, 1)
+	signal := func() {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+	if p.notifier != nil {
+		go func() {
+			if err := p.notifier.Listen(ctx, OutboxChannel, func(context.Context) { signal() },
+				func(db.Notification) { signal() }); err != nil {
+				p.logger.Error("ontology outbox listener stopped; falling back to polling", "error", err)
+			}
+		}()
+	}
+
 	for {
-		if err := p.ProcessBatch(ctx); err != nil {
-			return err
+		result, err := p.ProcessBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			p.logger.Error("ontology outbox batch failed", "error", err)
+		}
+		if err == nil && result != (BatchResult{}) {
+			continue
 		}
 
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-ticker.C:
+		case <-wake:
 		}
 	}
 }
 
-// ProcessBatch reads the next batch and forwards it to every sink.
-func (p *OutboxProcessor) ProcessBatch(ctx context.Context) error {
+// outboxRow is a claimed outbox row and the outcome of dispatching it.
+type outboxRow struct {
+	id        string
+	eventType string
+	event     []byte
+	attempts  int
+	seq       int64
+	createdAt time.Time
+
+	report      ValidationReport
+	conforms    bool
+	dispatchErr error
+}
+
+// ProcessBatch leases the next batch of due rows, dispatches them concurrently
+// and settles each one: deliveries are marked, events that fail validation are
+// quarantined, and failures are rescheduled with backoff or dead-lettered.
+//
+// Only the oldest undelivered row of each aggregate (JSON-LD @id) is claimable,
+// so events about one resource reach the sinks in the order they were written
+// even across several workers. Dead-lettered and quarantined rows no longer
+// hold back the rows behind them.
+func (p *OutboxProcessor) ProcessBatch(ctx context.Context) (BatchResult, error) {
+	var result BatchResult
 	if p == nil || p.db == nil {
-		return errors.New("outbox processor uninitialized")
+		return result, errors.New("outbox processor uninitialized")
 	}
 
-	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{})
-	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+	batch, err := p.claim(ctx)
+	if err != nil || len(batch) == 0 {
+		return result, err
 	}
-	defer tx.Rollback()
 
-	rows, err := tx.QueryContext(ctx, `
-        SELECT id, event_type, payload
-        FROM ontology_outbox
-        WHERE delivered_at IS NULL
-        ORDER BY created_at
-        FOR UPDATE SKIP LOCKED
-        LIMIT $1`, p.batchSize)
-	if err != nil {
-		return fmt.Errorf("select outbox rows: %w", err)
+	sem := make(chan struct{}, p.concurrency)
+	var wg sync.WaitGroup
+	for i := range batch {
+		rec := &batch[i]
+		if rec.report, rec.conforms = p.validate(rec.event); !rec.conforms {
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			rec.dispatchErr = p.dispatch(ctx, rec.id, rec.eventType, rec.event)
+		}()
 	}
-	defer rows.Close()
+	wg.Wait()
 
-	type record struct {
-		id    string
-		event []byte
+	if ctx.Err() != nil {
+		// Shutting down: hand the rows back without charging an attempt. The
+		// context is already cancelled, so release them on a detached one.
+		p.release(context.WithoutCancel(ctx), batch)
+		return result, ctx.Err()
 	}
-	var batch []record
+	return p.settle(ctx, batch)
+}
 
+// claim leases up to batchSize due rows to this worker. The lease is committed
+// before dispatch, so no row locks are held during sink calls.
+func (p *OutboxProcessor) claim(ctx context.Context) ([]outboxRow, error) {
+	rows, err := p.db.QueryContext(ctx, `
+        -- name: ClaimOntologyOutbox
+        UPDATE ontology_outbox
+        SET claimed_by = $1, lease_until = NOW() + $2 * INTERVAL '1 millisecond'
+        WHERE id IN (
+            SELECT o.id
+            FROM ontology_outbox o
+            WHERE o.delivered_at IS NULL
+              AND o.dead_lettered_at IS NULL
+              AND o.quarantined_at IS NULL
+              AND o.next_attempt_at <= NOW()
+              AND (o.lease_until IS NULL OR o.lease_until < NOW())
+              AND NOT EXISTS (
+                  SELECT 1
+                  FROM ontology_outbox prior
+                  WHERE prior.aggregate_id = o.aggregate_id
+                    AND prior.seq < o.seq
+                    AND prior.delivered_at IS NULL
+                    AND prior.dead_lettered_at IS NULL
+                    AND prior.quarantined_at IS NULL)
+            ORDER BY o.seq
+            FOR UPDATE SKIP LOCKED
+            LIMIT $3)
+        RETURNING id, event_type, payload, attempts, seq, created_at`,
+		p.workerID, p.lease.Milliseconds(), p.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox rows: %w", err)
+	}
+	defer rows.Close()
+
+	var batch []outboxRow
 	for rows.Next() {
-		var id, eventType string
-		var payload []byte
-		if err := rows.Scan(&id, &eventType, &payload); err != nil {
-			return fmt.Errorf("scan outbox row: %w", err)
+		var rec outboxRow
+		if err := rows.Scan(&rec.id, &rec.eventType, &rec.event, &rec.attempts, &rec.seq, &rec.createdAt); err != nil {
+			return nil, fmt.Errorf("scan outbox row: %w", err)
 		}
-		batch = append(batch, record{id: id, event: payload})
+		batch = append(batch, rec)
 	}
 	if err := rows.Err(); err != nil {
-		return fmt.Errorf("iterate outbox rows: %w", err)
+		return nil, fmt.Errorf("iterate outbox rows: %w", err)
 	}
 
-	if len(batch) == 0 {
-		return tx.Commit()
+	// UPDATE ... RETURNING does not keep the subquery's order.
+	slices.SortFunc(batch, func(a, b outboxRow) int { return cmp.Compare(a.seq, b.seq) })
+	return batch, nil
+}
+
+// settle records the outcome of every dispatched row in one short
+// transaction. Rows whose lease was taken over by another worker are skipped.
+func (p *OutboxProcessor) settle(ctx context.Context, batch []outboxRow) (BatchResult, error) {
+	var result BatchResult
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return result, fmt.Errorf("begin tx: %w", err)
 	}
+	defer tx.Rollback()
 
 	for _, rec := range batch {
-		if err := p.dispatch(ctx, rec.event); err != nil {
-			return err
+		var (
+			res     sql.Result
+			err     error
+			outcome string
+		)
+		switch {
+		case !rec.conforms:
+			encoded, encErr := json.Marshal(rec.report)
+			if encErr != nil {
+				return result, fmt.Errorf("encode validation report: %w", encErr)
+			}
+			res, err = tx.ExecContext(ctx, `
+                -- name: MarkOntologyOutboxQuarantined
+                UPDATE ontology_outbox
+                SET quarantined_at = NOW(), validation_report = $3, claimed_by = NULL, lease_until = NULL
+                WHERE id = $1 AND claimed_by = $2`, rec.id, p.workerID, encoded)
+			outcome = "quarantined"
+		case rec.dispatchErr == nil:
+			res, err = tx.ExecContext(ctx, `
+                -- name: MarkOntologyOutboxDelivered
+                UPDATE ontology_outbox
+                SET delivered_at = NOW(), attempts = attempts + 1, last_error = NULL,
+                    claimed_by = NULL, lease_until = NULL
+                WHERE id = $1 AND claimed_by = $2`, rec.id, p.workerID)
+			outcome = "delivered"
+		default:
+			attempts := rec.attempts + 1
+			outcome = "failed"
+			if attempts >= p.retry.MaxAttempts {
+				outcome = "dead_lettered"
+			}
+			res, err = tx.ExecContext(ctx, `
+                -- name: MarkOntologyOutboxFailed
+                UPDATE ontology_outbox
+                SET attempts = $3,
+                    last_error = $4,
+                    next_attempt_at = NOW() + $5 * INTERVAL '1 millisecond',
+                    dead_lettered_at = CASE WHEN $6 THEN NOW() END,
+                    claimed_by = NULL,
+                    lease_until = NULL
+                WHERE id = $1 AND claimed_by = $2`,
+				rec.id, p.workerID, attempts, truncateError(rec.dispatchErr),
+				p.retry.Backoff(attempts).Milliseconds(), outcome == "dead_lettered")
+		}
+		if err != nil {
+			return result, fmt.Errorf("mark %s: %w", outcome, err)
 		}
-		if _, err := tx.ExecContext(ctx, `UPDATE ontology_outbox SET delivered_at = NOW() WHERE id = $1`, rec.id); err != nil {
-			return fmt.Errorf("mark delivered: %w", err)
+		if n, _ := res.RowsAffected(); n == 0 {
+			p.logger.Warn("ontology outbox lease lost before settling", "id", rec.id, "outcome", outcome)
+			continue
 		}
+
+		observability.OntologyOutboxEventsTotal.WithLabelValues(outcome).Inc()
+		attempts := rec.attempts + 1
+		switch outcome {
+		case "quarantined":
+			result.Quarantined++
+			p.logger.Warn("ontology event quarantined",
+				"id", rec.id, "event_type", rec.eventType, "violations", len(rec.report.Results), "report", rec.report.String())
+		case "delivered":
+			result.Delivered++
+			observability.OntologyOutboxDeliveryLag.Observe(time.Since(rec.createdAt).Seconds())
+		case "dead_lettered":
+			result.DeadLettered++
+			p.logger.Error("ontology event dead-lettered",
+				"id", rec.id, "event_type", rec.eventType, "attempts", attempts, "error", rec.dispatchErr)
+		default:
+			result.Failed++
+			p.logger.Warn("ontology event delivery failed",
+				"id", rec.id, "event_type", rec.eventType, "attempts", attempts,
+				"retry_in", p.retry.Backoff(attempts).String(), "error", rec.dispatchErr)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return BatchResult{}, fmt.Errorf("commit outbox batch: %w", err)
+	}
+	return result, nil
+}
+
+// release drops this worker's lease on the given rows so they can be claimed
+// again right away.
+func (p *OutboxProcessor) release(ctx context.Context, batch []outboxRow) {
+	ids := make([]string, len(batch))
+	for i, rec := range batch {
+		ids[i] = rec.id
 	}
+	if _, err := p.db.ExecContext(ctx, `
+        -- name: ReleaseOntologyOutbox
+        UPDATE ontology_outbox
+        SET claimed_by = NULL, lease_until = NULL
+        WHERE id = ANY($1::uuid[]) AND claimed_by = $2`, ids, p.workerID); err != nil {
+		p.logger.Warn("release ontology outbox leases", "error", err)
+	}
+}
+
+// Stats reports the outbox backlog for observability.RegisterOutboxStats.
+func (p *OutboxProcessor) Stats(ctx context.Context) (observability.OutboxStats, error) {
+	var stats observability.OutboxStats
+	var oldest float64
+	err := p.db.QueryRowContext(ctx, `
+        -- name: OntologyOutboxStats
+        SELECT COUNT(*) FILTER (WHERE dead_lettered_at IS NULL AND quarantined_at IS NULL),
+               COUNT(*) FILTER (WHERE dead_lettered_at IS NOT NULL),
+               COUNT(*) FILTER (WHERE quarantined_at IS NOT NULL),
+               COALESCE(EXTRACT(EPOCH FROM NOW() - MIN(created_at)
+                   FILTER (WHERE dead_lettered_at IS NULL AND quarantined_at IS NULL)), 0)::float8
+        FROM ontology_outbox
+        WHERE delivered_at IS NULL`).Scan(&stats.Pending, &stats.DeadLettered, &stats.Quarantined, &oldest)
+	if err != nil {
+		return stats, fmt.Errorf("outbox stats: %w", err)
+	}
+	stats.OldestPending = time.Duration(oldest * float64(time.Second))
+	return stats, nil
+}
 
-	return tx.Commit()
+// defaultWorkerID identifies this process in claimed_by.
+func defaultWorkerID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s/%d/%s", host, os.Getpid(), uuid.NewString()[:8])
 }
 
-func (p *OutboxProcessor) dispatch(ctx context.Context, payload []byte) error {
+// validate reports whether payload conforms to the configured shapes.
+func (p *OutboxProcessor) validate(payload []byte) (ValidationReport, bool) {
+	return validatePayload(p.validator, payload)
+}
+
+func (p *OutboxProcessor) dispatch(ctx context.Context, id, eventType string, payload []byte) error {
+	return deliver(ctx, p.topic, p.kafka, p.triple, id, eventType, payload)
+}
+
+// validatePayload reports whether payload conforms to validator's shapes; a nil
+// validator accepts everything. A payload that cannot be expanded to RDF never
+// will be, so it is reported as a violation rather than retried.
+func validatePayload(validator *Validator, payload []byte) (ValidationReport, bool) {
+	if validator == nil || len(payload) == 0 {
+		return ValidationReport{Conforms: true}, true
+	}
+	report, err := validator.ValidateDocument(payload)
+	if err != nil {
+		report = ValidationReport{Results: []ValidationResult{{
+			Constraint: "jsonld:ExpansionError",
+			Message:    err.Error(),
+		}}}
+	}
+	return report, report.Conforms
+}
+
+// deliver publishes payload to Kafka and upserts it into the triple store,
+// skipping whichever sink is not configured.
+func deliver(ctx context.Context, topic string, producer KafkaProducer, triple TripleStoreClient, id, eventType string, payload []byte) error {
 	if len(payload) == 0 {
 		return nil
 	}
 
-	if p.kafka != nil && p.topic != "" {
-		if err := p.kafka.Publish(ctx, p.topic, payload); err != nil {
+	if producer != nil && topic != "" {
+		if err := producer.Publish(ctx, topic, kafkaMessage(id, eventType, payload)); err != nil {
 			return fmt.Errorf("publish kafka: %w", err)
 		}
 	}
 
-	if p.triple != nil {
-		if err := p.triple.Insert(ctx, payload); err != nil {
+	if triple != nil {
+		if err := triple.Insert(ctx, payload); err != nil {
 			return fmt.Errorf("insert triple store: %w", err)
 		}
 	}
 
 	return nil
 }
+
+// kafkaMessage keys the record by the JSON-LD @id so every event about one
+// entity lands on the same partition and stays ordered. Events without an @id
+// fall back to the outbox row ID.
+func kafkaMessage(id, eventType string, payload []byte) kafka.Message {
+	var envelope struct {
+		ID      string          `json:"@id"`
+		Context json.RawMessage `json:"@context"`
+	}
+	_ = json.Unmarshal(payload, &envelope)
+
+	key := envelope.ID
+	if key == "" {
+		key = id
+	}
+
+	headers := []kafka.Header{
+		{Key: HeaderEventID, Value: []byte(id)},
+		{Key: HeaderEventType, Value: []byte(eventType)},
+	}
+	if len(envelope.Context) > 0 {
+		// A context IRI is sent bare; inline contexts are sent as JSON.
+		value := []byte(envelope.Context)
+		var iri string
+		if json.Unmarshal(envelope.Context, &iri) == nil {
+			value = []byte(iri)
+		}
+		headers = append(headers, kafka.Header{Key: HeaderJSONLDContext, Value: value})
+	}
+
+	return kafka.Message{Key: []byte(key), Value: payload, Headers: headers}
+}
+
+func truncateError(err error) string {
+	msg := err.Error()
+	if len(msg) > maxStoredErrorLength {
+		return msg[:maxStoredErrorLength]
+	}
+	return msg
+}
@@ -0,0 +1,450 @@
+package ontology
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, expected := range want {
+		if got := policy.Backoff(i + 1); got != expected {
+			t.Errorf("Backoff(%d) = %s, want %s", i+1, got, expected)
+		}
+	}
+}
+
+func TestKafkaMessage_KeysByEntityAndSetsHeaders(t *testing.T) {
+	msg := kafkaMessage("row-1", "UserUpdated", []byte(`{"@context":"https://example.com/ctx","@id":"urn:user:1"}`))
+	if string(msg.Key) != "urn:user:1" {
+		t.Errorf("key = %q, want the JSON-LD @id", msg.Key)
+	}
+	headers := map[string]string{}
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	want := map[string]string{
+		HeaderEventID:       "row-1",
+		HeaderEventType:     "UserUpdated",
+		HeaderJSONLDContext: "https://example.com/ctx",
+	}
+	for key, value := range want {
+		if headers[key] != value {
+			t.Errorf("header %s = %q, want %q", key, headers[key], value)
+		}
+	}
+
+	if msg := kafkaMessage("row-2", "Ping", []byte(`{"ok":true}`)); string(msg.Key) != "row-2" {
+		t.Errorf("key without @id = %q, want the outbox row ID", msg.Key)
+	}
+}
+
+type failingTripleStore struct {
+	poison []byte
+}
+
+func (f failingTripleStore) Insert(_ context.Context, payload []byte) error {
+	if bytes.Equal(payload, f.poison) {
+		return errors.New("triple store rejected payload")
+	}
+	return nil
+}
+
+type tripleStoreFunc func(ctx context.Context, payload []byte) error
+
+func (f tripleStoreFunc) Insert(ctx context.Context, payload []byte) error { return f(ctx, payload) }
+
+// openOutboxTestDB shadows ontology_outbox with a temporary table on a single
+// connection so the test never touches real outbox rows.
+//
+//	SKILLSPHERE_TEST_DATABASE_DSN=postgres://... go test ./internal/ontology
+func openOutboxTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("SKILLSPHERE_TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("SKILLSPHERE_TEST_DATABASE_DSN not set")
+	}
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`
+		CREATE TEMP TABLE ontology_outbox (
+			id UUID PRIMARY KEY,
+			event_type TEXT NOT NULL,
+			payload JSONB NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			delivered_at TIMESTAMPTZ,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			dead_lettered_at TIMESTAMPTZ,
+			quarantined_at TIMESTAMPTZ,
+			validation_report JSONB,
+			seq BIGSERIAL,
+			aggregate_id TEXT,
+			claimed_by TEXT,
+			lease_until TIMESTAMPTZ
+		)`); err != nil {
+		t.Fatalf("create temp outbox: %v", err)
+	}
+	if _, err := db.Exec(`
+		CREATE TEMP TABLE ontology_outbox_archive (
+			id UUID PRIMARY KEY,
+			event_type TEXT NOT NULL,
+			aggregate_id TEXT,
+			payload JSONB NOT NULL,
+			attempts INTEGER NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL,
+			delivered_at TIMESTAMPTZ NOT NULL,
+			archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`); err != nil {
+		t.Fatalf("create temp outbox archive: %v", err)
+	}
+	return db
+}
+
+func TestOutboxProcessor_RetriesAndDeadLetters(t *testing.T) {
+	db := openOutboxTestDB(t)
+	ctx := context.Background()
+
+	good, poison := uuid.NewString(), uuid.NewString()
+	poisonPayload := []byte(`{"poison": true}`)
+	for id, payload := range map[string]string{good: `{"ok": true}`, poison: string(poisonPayload)} {
+		if _, err := db.Exec(`INSERT INTO ontology_outbox (id, event_type, payload) VALUES ($1, 'test', $2)`, id, payload); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+
+	processor := NewOutboxProcessor(db, "", nil, failingTripleStore{poison: poisonPayload},
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+
+	result, err := processor.ProcessBatch(ctx)
+	if err != nil {
+		t.Fatalf("ProcessBatch: %v", err)
+	}
+	if result.Delivered != 1 || result.Failed != 1 {
+		t.Fatalf("first batch = %+v, want 1 delivered and 1 failed", result)
+	}
+
+	var attempts int
+	var lastError string
+	if err := db.QueryRow(`SELECT attempts, last_error FROM ontology_outbox WHERE id = $1`, poison).Scan(&attempts, &lastError); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 1 || lastError == "" {
+		t.Fatalf("poison row attempts=%d last_error=%q", attempts, lastError)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if result, err = processor.ProcessBatch(ctx); err != nil || result.DeadLettered != 1 {
+		t.Fatalf("second batch = %+v, %v; want the poison row dead-lettered", result, err)
+	}
+
+	store := NewDeadLetterStore(db)
+	letters, err := store.List(ctx, 10)
+	if err != nil || len(letters) != 1 || letters[0].ID != poison {
+		t.Fatalf("List = %+v, %v", letters, err)
+	}
+
+	if n, err := store.Replay(ctx, poison); err != nil || n != 1 {
+		t.Fatalf("Replay = %d, %v", n, err)
+	}
+	if n, _ := store.Count(ctx); n != 0 {
+		t.Fatalf("dead letters after replay = %d", n)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if _, err := processor.ProcessBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := processor.ProcessBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := store.Purge(ctx); err != nil || n != 1 {
+		t.Fatalf("Purge = %d, %v", n, err)
+	}
+}
+
+func TestOutboxProcessor_QuarantinesInvalidEvents(t *testing.T) {
+	db := openOutboxTestDB(t)
+	ctx := context.Background()
+
+	validator, err := DefaultValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := NewUserRegisteredEvent(testUser())
+	invalid := NewEvent("sk:User/2", ClassUser)
+	invalid.Set("sk:isActive", "yes")
+
+	ids := map[string]Event{uuid.NewString(): valid, uuid.NewString(): invalid}
+	var invalidID string
+	for id, evt := range ids {
+		payload, err := evt.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`INSERT INTO ontology_outbox (id, event_type, payload) VALUES ($1, 'UserRegistered', $2)`, id, payload); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		if evt.ID == invalid.ID {
+			invalidID = id
+		}
+	}
+
+	var inserted int
+	triple := tripleStoreFunc(func(context.Context, []byte) error { inserted++; return nil })
+	processor := NewOutboxProcessor(db, "", nil, triple, WithValidator(validator))
+
+	result, err := processor.ProcessBatch(ctx)
+	if err != nil {
+		t.Fatalf("ProcessBatch: %v", err)
+	}
+	if result.Delivered != 1 || result.Quarantined != 1 || inserted != 1 {
+		t.Fatalf("batch = %+v, inserted %d; want 1 delivered and 1 quarantined", result, inserted)
+	}
+
+	store := NewQuarantineStore(db)
+	events, err := store.List(ctx, 10)
+	if err != nil || len(events) != 1 || events[0].ID != invalidID {
+		t.Fatalf("List = %+v, %v", events, err)
+	}
+	if events[0].Report.Conforms || len(events[0].Report.Results) == 0 {
+		t.Fatalf("stored report = %+v, want violations", events[0].Report)
+	}
+
+	if result, err := processor.ProcessBatch(ctx); err != nil || result != (BatchResult{}) {
+		t.Fatalf("quarantined row was claimed again: %+v, %v", result, err)
+	}
+	if n, err := store.Release(ctx, invalidID); err != nil || n != 1 {
+		t.Fatalf("Release = %d, %v", n, err)
+	}
+	if result, err := processor.ProcessBatch(ctx); err != nil || result.Quarantined != 1 {
+		t.Fatalf("released row = %+v, %v; want it quarantined again", result, err)
+	}
+	if n, err := store.Purge(ctx); err != nil || n != 1 {
+		t.Fatalf("Purge = %d, %v", n, err)
+	}
+}
+
+func TestOutboxProcessor_KeepsAggregateOrder(t *testing.T) {
+	db := openOutboxTestDB(t)
+	ctx := context.Background()
+
+	// a1 and a2 describe the same resource; a1 fails once.
+	payloads := []string{
+		`{"@id":"sk:User/a","n":1}`,
+		`{"@id":"sk:User/a","n":2}`,
+		`{"@id":"sk:User/b","n":1}`,
+	}
+	for _, payload := range payloads {
+		if _, err := db.Exec(`INSERT INTO ontology_outbox (id, event_type, aggregate_id, payload) VALUES ($1, 'test', $2::jsonb->>'@id', $2)`,
+			uuid.NewString(), payload); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+
+	var mu sync.Mutex
+	var delivered []string
+	failed := false
+	triple := tripleStoreFunc(func(_ context.Context, payload []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if string(payload) == payloads[0] && !failed {
+			failed = true
+			return errors.New("unavailable")
+		}
+		delivered = append(delivered, string(payload))
+		return nil
+	})
+	processor := NewOutboxProcessor(db, "", nil, triple, WithConcurrency(4),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+
+	for range 4 {
+		if _, err := processor.ProcessBatch(ctx); err != nil {
+			t.Fatalf("ProcessBatch: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	var order []string
+	for _, payload := range delivered {
+		if strings.Contains(payload, "User/a") {
+			order = append(order, payload)
+		}
+	}
+	if len(delivered) != 3 || len(order) != 2 || order[0] != payloads[0] {
+		t.Fatalf("delivered %v, want a1 before a2 despite a1's failure", delivered)
+	}
+}
+
+func TestOutboxProcessor_SkipsLeasedRows(t *testing.T) {
+	db := openOutboxTestDB(t)
+	ctx := context.Background()
+
+	id := uuid.NewString()
+	if _, err := db.Exec(`
+		INSERT INTO ontology_outbox (id, event_type, payload, claimed_by, lease_until)
+		VALUES ($1, 'test', '{}', 'other-worker', NOW() + INTERVAL '1 hour')`, id); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	processor := NewOutboxProcessor(db, "", nil, nil, WithWorkerID("this-worker"))
+	if result, err := processor.ProcessBatch(ctx); err != nil || result != (BatchResult{}) {
+		t.Fatalf("leased row was claimed: %+v, %v", result, err)
+	}
+
+	if _, err := db.Exec(`UPDATE ontology_outbox SET lease_until = NOW() - INTERVAL '1 second' WHERE id = $1`, id); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := processor.ProcessBatch(ctx); err != nil || result.Delivered != 1 {
+		t.Fatalf("expired lease = %+v, %v; want the row taken over and delivered", result, err)
+	}
+}
+
+func TestOutboxRetention_ArchivesDeliveredRows(t *testing.T) {
+	db := openOutboxTestDB(t)
+	ctx := context.Background()
+
+	for _, delivered := range []string{"NOW() - INTERVAL '2 days'", "NOW()", "NULL"} {
+		if _, err := db.Exec(`INSERT INTO ontology_outbox (id, event_type, payload, delivered_at)
+			VALUES ($1, 'test', '{}', `+delivered+`)`, uuid.NewString()); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+
+	n, err := NewOutboxRetention(db, 24*time.Hour, true, nil).Prune(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("Prune = %d, %v; want the old delivered row only", n, err)
+	}
+	var remaining, archived int
+	if err := db.QueryRow(`SELECT (SELECT COUNT(*) FROM ontology_outbox), (SELECT COUNT(*) FROM ontology_outbox_archive)`).
+		Scan(&remaining, &archived); err != nil {
+		t.Fatal(err)
+	}
+	if remaining != 2 || archived != 1 {
+		t.Fatalf("remaining=%d archived=%d, want 2 and 1", remaining, archived)
+	}
+}
+
+// chanNotifier calls fn for every value sent on the channel.
+type chanNotifier chan struct{}
+
+func (n chanNotifier) Listen(ctx context.Context, channel string, _ func(context.Context), fn func(db.Notification)) error {
+	for {
+		select {
+		case <-n:
+			fn(db.Notification{Channel: channel})
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func TestOutboxProcessor_RunWakesOnNotification(t *testing.T) {
+	db := openOutboxTestDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	delivered := make(chan struct{}, 1)
+	triple := tripleStoreFunc(func(context.Context, []byte) error { delivered <- struct{}{}; return nil })
+	notifier := make(chanNotifier)
+	processor := NewOutboxProcessor(db, "", nil, triple, WithNotifier(notifier))
+
+	done := make(chan error, 1)
+	go func() { done <- processor.Run(ctx, time.Hour) }()
+
+	// Give Run time to find the outbox empty and go to sleep.
+	time.Sleep(50 * time.Millisecond)
+	if err := NewOutboxEmitter(db, nil).Emit(ctx, NewUserRegisteredEvent(testUser())); err != nil {
+		t.Fatalf("Emit: %v", err)
+	}
+	notifier <- struct{}{}
+
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not delivered after notification; Run kept waiting for the poll interval")
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run = %v, want context.Canceled", err)
+	}
+}
+
+func TestOutboxEmitter_Notifies(t *testing.T) {
+	sqlDB := openOutboxTestDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, os.Getenv("SKILLSPHERE_TEST_DATABASE_DSN"))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer conn.Close(context.Background())
+	if _, err := conn.Exec(ctx, "LISTEN "+OutboxChannel); err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	evt := NewUserRegisteredEvent(testUser())
+	if err := NewOutboxEmitter(sqlDB, nil).Emit(ctx, evt); err != nil {
+		t.Fatalf("Emit: %v", err)
+	}
+	n, err := conn.WaitForNotification(ctx)
+	if err != nil {
+		t.Fatalf("WaitForNotification: %v", err)
+	}
+	if n.Channel != OutboxChannel || n.Payload != evt.Type {
+		t.Fatalf("notification = %s %q, want %s %q", n.Channel, n.Payload, OutboxChannel, evt.Type)
+	}
+}
+
+func TestOutboxProcessor_Stats(t *testing.T) {
+	db := openOutboxTestDB(t)
+	ctx := context.Background()
+
+	for _, extra := range []string{
+		"created_at = NOW() - INTERVAL '1 minute'",
+		"dead_lettered_at = NOW()",
+		"quarantined_at = NOW()",
+		"delivered_at = NOW()",
+	} {
+		id := uuid.NewString()
+		if _, err := db.Exec(`INSERT INTO ontology_outbox (id, event_type, payload) VALUES ($1, 'test', '{}')`, id); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		if _, err := db.Exec(`UPDATE ontology_outbox SET `+extra+` WHERE id = $1`, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := NewOutboxProcessor(db, "", nil, nil).Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Pending != 1 || stats.DeadLettered != 1 || stats.Quarantined != 1 {
+		t.Errorf("stats = %+v, want one pending, dead-lettered and quarantined row", stats)
+	}
+	if stats.OldestPending < 50*time.Second {
+		t.Errorf("oldest pending = %s, want about a minute", stats.OldestPending)
+	}
+}
@@ -0,0 +1,108 @@
+package ontology
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// QuarantinedEvent is an outbox row that failed SHACL validation and was held
+// back from the sinks.
+type QuarantinedEvent struct {
+	ID            string           `json:"id"`
+	EventType     string           `json:"event_type"`
+	Payload       json.RawMessage  `json:"payload"`
+	Report        ValidationReport `json:"validation_report"`
+	CreatedAt     time.Time        `json:"created_at"`
+	QuarantinedAt time.Time        `json:"quarantined_at"`
+}
+
+// QuarantineStore inspects, releases and purges quarantined outbox rows.
+type QuarantineStore struct {
+	db *sql.DB
+}
+
+// NewQuarantineStore builds a store backed by the provided sql.DB.
+func NewQuarantineStore(db *sql.DB) *QuarantineStore {
+	return &QuarantineStore{db: db}
+}
+
+// List returns up to limit quarantined events, oldest first.
+func (s *QuarantineStore) List(ctx context.Context, limit int) ([]QuarantinedEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        -- name: ListOntologyQuarantine
+        SELECT id, event_type, payload, COALESCE(validation_report, '{}'::jsonb), created_at, quarantined_at
+        FROM ontology_outbox
+        WHERE quarantined_at IS NOT NULL
+        ORDER BY quarantined_at
+        LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list quarantine: %w", err)
+	}
+	defer rows.Close()
+
+	var events []QuarantinedEvent
+	for rows.Next() {
+		var event QuarantinedEvent
+		var payload, report []byte
+		if err := rows.Scan(&event.ID, &event.EventType, &payload, &report,
+			&event.CreatedAt, &event.QuarantinedAt); err != nil {
+			return nil, fmt.Errorf("scan quarantined event: %w", err)
+		}
+		event.Payload = payload
+		if err := json.Unmarshal(report, &event.Report); err != nil {
+			return nil, fmt.Errorf("decode validation report for %s: %w", event.ID, err)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// Count returns the number of quarantined rows.
+func (s *QuarantineStore) Count(ctx context.Context) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, `
+        -- name: CountOntologyQuarantine
+        SELECT COUNT(*) FROM ontology_outbox WHERE quarantined_at IS NOT NULL`).Scan(&n)
+	return n, err
+}
+
+// Release returns the given quarantined events, or all of them when ids is
+// empty, to the pending queue. They are validated again on the next batch, so
+// release after fixing the shapes or the payload. It reports how many rows
+// were requeued.
+func (s *QuarantineStore) Release(ctx context.Context, ids ...string) (int64, error) {
+	query := `
+        -- name: ReleaseOntologyQuarantine
+        UPDATE ontology_outbox
+        SET quarantined_at = NULL, validation_report = NULL, next_attempt_at = NOW()
+        WHERE quarantined_at IS NOT NULL`
+	return s.exec(ctx, query, ids)
+}
+
+// Purge deletes the given quarantined events, or all of them when ids is empty.
+func (s *QuarantineStore) Purge(ctx context.Context, ids ...string) (int64, error) {
+	query := `
+        -- name: PurgeOntologyQuarantine
+        DELETE FROM ontology_outbox
+        WHERE quarantined_at IS NOT NULL`
+	return s.exec(ctx, query, ids)
+}
+
+func (s *QuarantineStore) exec(ctx context.Context, query string, ids []string) (int64, error) {
+	var (
+		result sql.Result
+		err    error
+	)
+	if len(ids) == 0 {
+		result, err = s.db.ExecContext(ctx, query)
+	} else {
+		result, err = s.db.ExecContext(ctx, query+` AND id = ANY($1::uuid[])`, ids)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,296 @@
+package ontology
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Well-known vocabulary IRIs used when building triples.
+const (
+	RDFNS = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	XSDNS = "http://www.w3.org/2001/XMLSchema#"
+
+	rdfType       = RDFNS + "type"
+	rdfFirst      = RDFNS + "first"
+	rdfRest       = RDFNS + "rest"
+	rdfNil        = RDFNS + "nil"
+	rdfLangString = RDFNS + "langString"
+	xsdString     = XSDNS + "string"
+	xsdBoolean    = XSDNS + "boolean"
+	xsdInteger    = XSDNS + "integer"
+	xsdDouble     = XSDNS + "double"
+	xsdDecimal    = XSDNS + "decimal"
+	xsdDateTime   = XSDNS + "dateTime"
+	xsdAnyURI     = XSDNS + "anyURI"
+)
+
+// TermKind distinguishes the three kinds of RDF term.
+type TermKind uint8
+
+const (
+	IRITerm TermKind = iota
+	BlankNodeTerm
+	LiteralTerm
+)
+
+// Term is an RDF term. Literals always carry a datatype; language-tagged
+// strings use rdf:langString.
+type Term struct {
+	Kind     TermKind
+	Value    string
+	Datatype string
+	Language string
+}
+
+// IRI returns an IRI term.
+func IRI(value string) Term { return Term{Kind: IRITerm, Value: value} }
+
+// BlankNode returns a blank node term with the given label (without "_:").
+func BlankNode(label string) Term { return Term{Kind: BlankNodeTerm, Value: label} }
+
+// Literal returns a typed literal; an empty datatype means xsd:string.
+func Literal(value, datatype string) Term {
+	if datatype == "" {
+		datatype = xsdString
+	}
+	return Term{Kind: LiteralTerm, Value: value, Datatype: datatype}
+}
+
+// LangLiteral returns a language-tagged string.
+func LangLiteral(value, language string) Term {
+	return Term{Kind: LiteralTerm, Value: value, Datatype: rdfLangString, Language: strings.ToLower(language)}
+}
+
+// Triple is a single RDF statement.
+type Triple struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+}
+
+// Format is an RDF serialization, named by its media type.
+type Format string
+
+const (
+	FormatJSONLD   Format = "application/ld+json"
+	FormatNTriples Format = "application/n-triples"
+	FormatNQuads   Format = "application/n-quads"
+	FormatTurtle   Format = "text/turtle"
+)
+
+// ParseFormat accepts a media type or a short name ("jsonld", "ntriples", "nquads", "turtle").
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "jsonld", "json-ld", string(FormatJSONLD):
+		return FormatJSONLD, nil
+	case "ntriples", "n-triples", "nt", string(FormatNTriples):
+		return FormatNTriples, nil
+	case "nquads", "n-quads", "nq", string(FormatNQuads):
+		return FormatNQuads, nil
+	case "turtle", "ttl", string(FormatTurtle):
+		return FormatTurtle, nil
+	default:
+		return "", fmt.Errorf("unknown RDF format %q", name)
+	}
+}
+
+// WriteNTriples writes triples in N-Triples, one statement per line.
+func WriteNTriples(w io.Writer, triples []Triple) error {
+	return WriteNQuads(w, "", triples)
+}
+
+// WriteNQuads writes triples as N-Quads in graph; an empty graph writes the
+// default graph, which is plain N-Triples.
+func WriteNQuads(w io.Writer, graph string, triples []Triple) error {
+	if graph != "" && !validIRI(graph) {
+		return fmt.Errorf("invalid graph IRI %q", graph)
+	}
+	bw := bufio.NewWriter(w)
+	for _, t := range triples {
+		if err := checkTriple(t); err != nil {
+			return err
+		}
+		bw.WriteString(formatTerm(t.Subject))
+		bw.WriteByte(' ')
+		bw.WriteString(formatTerm(t.Predicate))
+		bw.WriteByte(' ')
+		bw.WriteString(formatTerm(t.Object))
+		if graph != "" {
+			bw.WriteString(" <" + graph + ">")
+		}
+		bw.WriteString(" .\n")
+	}
+	return bw.Flush()
+}
+
+// WriteTurtle writes triples as Turtle, grouping statements by subject and
+// abbreviating IRIs with the bundled context's prefixes where possible.
+func WriteTurtle(w io.Writer, triples []Triple) error {
+	for _, t := range triples {
+		if err := checkTriple(t); err != nil {
+			return err
+		}
+	}
+
+	prefixes := bundledContext.prefixes()
+	used := make(map[string]bool)
+	abbreviate := func(term Term) string {
+		if term.Kind == IRITerm {
+			if name, prefix, ok := compactIRI(term.Value, prefixes); ok {
+				used[prefix] = true
+				return name
+			}
+			return "<" + term.Value + ">"
+		}
+		if term.Kind == LiteralTerm && term.Language == "" {
+			switch term.Datatype {
+			case xsdString:
+				return quoteLiteral(term.Value)
+			case xsdInteger, xsdBoolean:
+				return term.Value
+			}
+			if name, prefix, ok := compactIRI(term.Datatype, prefixes); ok {
+				used[prefix] = true
+				return quoteLiteral(term.Value) + "^^" + name
+			}
+		}
+		return formatTerm(term)
+	}
+
+	// Group by subject, keeping first-seen order so output is stable.
+	var (
+		subjects []Term
+		bySubj   = make(map[Term][]Triple)
+	)
+	for _, t := range triples {
+		if _, seen := bySubj[t.Subject]; !seen {
+			subjects = append(subjects, t.Subject)
+		}
+		bySubj[t.Subject] = append(bySubj[t.Subject], t)
+	}
+
+	predicate := func(term Term) string {
+		if term.Value == rdfType {
+			return "a"
+		}
+		return abbreviate(term)
+	}
+
+	var body strings.Builder
+	for i, subject := range subjects {
+		if i > 0 {
+			body.WriteByte('\n')
+		}
+		body.WriteString(abbreviate(subject))
+		group := bySubj[subject]
+		for j, t := range group {
+			switch {
+			case j == 0:
+				body.WriteString(" " + predicate(t.Predicate) + " ")
+			case t.Predicate == group[j-1].Predicate:
+				body.WriteString(" ,\n    ")
+			default:
+				body.WriteString(" ;\n  " + predicate(t.Predicate) + " ")
+			}
+			body.WriteString(abbreviate(t.Object))
+		}
+		body.WriteString(" .\n")
+	}
+
+	bw := bufio.NewWriter(w)
+	names := make([]string, 0, len(used))
+	for prefix := range used {
+		names = append(names, prefix)
+	}
+	slices.Sort(names)
+	for _, prefix := range names {
+		fmt.Fprintf(bw, "@prefix %s: <%s> .\n", prefix, prefixes[prefix])
+	}
+	if len(names) > 0 {
+		bw.WriteByte('\n')
+	}
+	bw.WriteString(body.String())
+	return bw.Flush()
+}
+
+// turtleLocalName is a conservative subset of Turtle's PN_LOCAL that needs no escaping.
+var turtleLocalName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+func compactIRI(iri string, prefixes map[string]string) (name, prefix string, ok bool) {
+	for p, ns := range prefixes {
+		local, found := strings.CutPrefix(iri, ns)
+		if !found || !turtleLocalName.MatchString(local) {
+			continue
+		}
+		// Prefer the longest namespace, then the shortest prefix, for determinism.
+		if !ok || len(ns) > len(prefixes[prefix]) || (len(ns) == len(prefixes[prefix]) && p < prefix) {
+			name, prefix, ok = p+":"+local, p, true
+		}
+	}
+	return name, prefix, ok
+}
+
+func checkTriple(t Triple) error {
+	for _, term := range []Term{t.Subject, t.Predicate, t.Object} {
+		if term.Kind == IRITerm && !validIRI(term.Value) {
+			return fmt.Errorf("invalid IRI %q", term.Value)
+		}
+		if term.Kind == BlankNodeTerm && !turtleLocalName.MatchString(term.Value) {
+			return fmt.Errorf("invalid blank node label %q", term.Value)
+		}
+	}
+	if t.Subject.Kind == LiteralTerm || t.Predicate.Kind != IRITerm {
+		return fmt.Errorf("malformed triple %v", t)
+	}
+	return nil
+}
+
+func formatTerm(term Term) string {
+	switch term.Kind {
+	case IRITerm:
+		return "<" + term.Value + ">"
+	case BlankNodeTerm:
+		return "_:" + term.Value
+	default:
+		if term.Language != "" {
+			return quoteLiteral(term.Value) + "@" + term.Language
+		}
+		if term.Datatype == "" || term.Datatype == xsdString {
+			return quoteLiteral(term.Value)
+		}
+		return quoteLiteral(term.Value) + "^^<" + term.Datatype + ">"
+	}
+}
+
+// quoteLiteral escapes a string per the N-Triples STRING_LITERAL_QUOTE production.
+func quoteLiteral(value string) string {
+	var b strings.Builder
+	b.Grow(len(value) + 2)
+	b.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package ontology
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/observability"
+)
+
+const defaultRetentionBatchSize = 1000
+
+// OutboxRetention removes delivered outbox rows once they are older than a
+// cut-off, optionally moving them to ontology_outbox_archive first. Pending,
+// dead-lettered and quarantined rows are never touched.
+type OutboxRetention struct {
+	db        *sql.DB
+	maxAge    time.Duration
+	archive   bool
+	batchSize int
+	logger    *slog.Logger
+}
+
+// NewOutboxRetention builds a retention job for rows delivered more than maxAge ago.
+func NewOutboxRetention(db *sql.DB, maxAge time.Duration, archive bool, logger *slog.Logger) *OutboxRetention {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	return &OutboxRetention{
+		db:        db,
+		maxAge:    maxAge,
+		archive:   archive,
+		batchSize: defaultRetentionBatchSize,
+		logger:    logger,
+	}
+}
+
+// Run prunes once per interval until ctx is cancelled.
+func (r *OutboxRetention) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := r.Prune(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("ontology outbox retention failed", "error", err)
+		} else if n > 0 {
+			r.logger.Info("ontology outbox pruned", "rows", n, "archived", r.archive)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune removes every expired row in batches, so no single statement holds
+// locks on a large part of the table, and reports how many were removed.
+func (r *OutboxRetention) Prune(ctx context.Context) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, errors.New("outbox retention uninitialized")
+	}
+
+	query := pruneOutboxStmt
+	mode := "deleted"
+	if r.archive {
+		query = archiveOutboxStmt
+		mode = "archived"
+	}
+
+	var total int64
+	for {
+		n, err := r.pruneBatch(ctx, query)
+		if err != nil {
+			return total, err
+		}
+		total += n
+		observability.OntologyOutboxPrunedTotal.WithLabelValues(mode).Add(float64(n))
+		if n < int64(r.batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// pruneBatch runs one batch and returns how many rows left the outbox. In
+// archive mode that is the count of deleted rows, not of inserted ones, since
+// rows archived before are skipped by the insert but still deleted.
+func (r *OutboxRetention) pruneBatch(ctx context.Context, query string) (int64, error) {
+	if r.archive {
+		var n int64
+		if err := r.db.QueryRowContext(ctx, query, r.maxAge.Milliseconds(), r.batchSize).Scan(&n); err != nil {
+			return 0, fmt.Errorf("archive outbox: %w", err)
+		}
+		return n, nil
+	}
+	res, err := r.db.ExecContext(ctx, query, r.maxAge.Milliseconds(), r.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("prune outbox: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+const pruneOutboxStmt = `
+    -- name: PruneOntologyOutbox
+    DELETE FROM ontology_outbox
+    WHERE id IN (
+        SELECT id FROM ontology_outbox
+        WHERE delivered_at < NOW() - $1 * INTERVAL '1 millisecond'
+        ORDER BY delivered_at
+        LIMIT $2)`
+
+const archiveOutboxStmt = `
+    -- name: ArchiveOntologyOutbox
+    WITH pruned AS (
+        DELETE FROM ontology_outbox
+        WHERE id IN (
+            SELECT id FROM ontology_outbox
+            WHERE delivered_at < NOW() - $1 * INTERVAL '1 millisecond'
+            ORDER BY delivered_at
+            LIMIT $2)
+        RETURNING id, event_type, aggregate_id, payload, attempts, created_at, delivered_at),
+    archived AS (
+        INSERT INTO ontology_outbox_archive (id, event_type, aggregate_id, payload, attempts, created_at, delivered_at)
+        SELECT id, event_type, aggregate_id, payload, attempts, created_at, delivered_at FROM pruned
+        ON CONFLICT (id) DO NOTHING)
+    SELECT count(*) FROM pruned`
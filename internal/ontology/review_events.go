@@ -0,0 +1,54 @@
+package ontology
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewEvent describes a paid review request and its progress.
+type ReviewEvent struct {
+	ReviewID    uuid.UUID
+	RequesterID uuid.UUID
+	// ReviewerID is zero until a reviewer accepts the request.
+	ReviewerID  uuid.UUID
+	SkillID     uuid.UUID
+	Title       string
+	StatusIRI   string
+	TypeIRI     string
+	Deadline    time.Time
+	CompletedAt time.Time
+	UpdatedAt   time.Time
+	// DeclineReason doubles as the reason of the ReviewDeclined activity.
+	DeclineReason string
+}
+
+// NewReviewEvent builds a JSON-LD snapshot of a review produced by activity.
+func NewReviewEvent(payload ReviewEvent, activity Activity) Event {
+	updated := payload.UpdatedAt
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	evt := NewEvent(ClassReview+"/"+payload.ReviewID.String(), ClassReview)
+	evt.SetTimestamp(updated)
+	evt.Set(PropRequestedBy, userIRI(payload.RequesterID))
+	if payload.ReviewerID != uuid.Nil {
+		evt.Set(PropReviewedBy, userIRI(payload.ReviewerID))
+	}
+	evt.Set(PropSkill, skillIRI(payload.SkillID))
+	evt.Set(PropTitle, payload.Title)
+	if payload.StatusIRI != "" {
+		evt.Set(PropReviewStatus, payload.StatusIRI)
+	}
+	if payload.TypeIRI != "" {
+		evt.Set(PropReviewType, payload.TypeIRI)
+	}
+	if !payload.Deadline.IsZero() {
+		evt.Set(PropDeadline, payload.Deadline.UTC().Format(time.RFC3339Nano))
+	}
+	if !payload.CompletedAt.IsZero() {
+		evt.Set(PropCompletedAt, payload.CompletedAt.UTC().Format(time.RFC3339Nano))
+	}
+	evt.SetActivity(activity)
+	return evt
+}
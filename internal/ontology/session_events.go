@@ -2,18 +2,14 @@ package ontology
 
 import (
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-const (
-	sessionTypeIRI = "sk:Session"
-	matchTypeIRI   = "sk:Match"
-)
-
 func sessionIRI(id uuid.UUID) string {
-	return fmt.Sprintf("sk:Session/%s", id.String())
+	return ClassSession + "/" + id.String()
 }
 
 // SessionEvent models the data required to describe a session resource.
@@ -25,43 +21,117 @@ type SessionEvent struct {
 	PartnerOffers   []string
 	ScheduledStart  time.Time
 	ScheduledEnd    time.Time
+	ActualStart     time.Time
+	ActualEnd       time.Time
 	MeetingURL      string
 	StatusIRI       string
 	IsPremium       bool
 	Notes           string
+	// CancellationReason doubles as the reason of the SessionCancelled activity.
+	CancellationReason string
+}
+
+// sessionActivities maps each session status to the transition into it.
+var sessionActivities = map[string]string{
+	ConceptSessionStatusScheduled:  ActivitySessionScheduled,
+	ConceptSessionStatusInProgress: ActivitySessionStarted,
+	ConceptSessionStatusCompleted:  ActivitySessionCompleted,
+	ConceptSessionStatusCancelled:  ActivitySessionCancelled,
+	ConceptSessionStatusNoShow:     ActivitySessionNoShow,
 }
 
-// NewSessionScheduledEvent builds an ontology event for a session lifecycle change.
+// NewSessionScheduledEvent builds an ontology event for a newly booked session.
 func NewSessionScheduledEvent(payload SessionEvent) Event {
-	evt := NewEvent(sessionIRI(payload.SessionID), sessionTypeIRI)
+	return NewSessionEvent(payload, Activity{Type: ActivitySessionScheduled, Actor: payload.InitiatorID})
+}
+
+// NewSessionStatusEvent builds an ontology event for a session that moved
+// into payload.StatusIRI, recording the matching activity on behalf of actor.
+// Unknown statuses produce a snapshot without an activity.
+func NewSessionStatusEvent(payload SessionEvent, actor uuid.UUID) Event {
+	activity := Activity{Type: sessionActivities[payload.StatusIRI], Actor: actor}
+	switch payload.StatusIRI {
+	case ConceptSessionStatusInProgress:
+		activity.At = payload.ActualStart
+	case ConceptSessionStatusCompleted:
+		activity.At = payload.ActualEnd
+	case ConceptSessionStatusCancelled:
+		activity.Reason = payload.CancellationReason
+	}
+	return NewSessionEvent(payload, activity)
+}
+
+// NewSessionEvent builds a JSON-LD snapshot of a session produced by activity.
+func NewSessionEvent(payload SessionEvent, activity Activity) Event {
+	evt := NewEvent(sessionIRI(payload.SessionID), ClassSession)
 	evt.SetTimestamp(time.Now())
-	evt.Set("sk:initiatedBy", userIRI(payload.InitiatorID))
-	evt.Set("sk:hasParticipant", []string{
+	evt.Set(PropInitiatedBy, userIRI(payload.InitiatorID))
+	evt.Set(PropHasParticipant, []string{
 		userIRI(payload.InitiatorID),
 		userIRI(payload.PartnerID),
 	})
 	if payload.StatusIRI != "" {
-		evt.Set("sk:sessionStatus", payload.StatusIRI)
+		evt.Set(PropSessionStatus, payload.StatusIRI)
 	}
 	if !payload.ScheduledStart.IsZero() {
-		evt.Set("sk:scheduledStart", payload.ScheduledStart.UTC().Format(time.RFC3339Nano))
+		evt.Set(PropScheduledStart, payload.ScheduledStart.UTC().Format(time.RFC3339Nano))
 	}
 	if !payload.ScheduledEnd.IsZero() {
-		evt.Set("sk:scheduledEnd", payload.ScheduledEnd.UTC().Format(time.RFC3339Nano))
+		evt.Set(PropScheduledEnd, payload.ScheduledEnd.UTC().Format(time.RFC3339Nano))
+	}
+	if !payload.ActualStart.IsZero() {
+		evt.Set(PropActualStart, payload.ActualStart.UTC().Format(time.RFC3339Nano))
+	}
+	if !payload.ActualEnd.IsZero() {
+		evt.Set(PropActualEnd, payload.ActualEnd.UTC().Format(time.RFC3339Nano))
 	}
 	if payload.MeetingURL != "" {
-		evt.Set("sk:meetingUrl", payload.MeetingURL)
+		evt.Set(PropMeetingUrl, payload.MeetingURL)
 	}
 	if payload.Notes != "" {
-		evt.Set("sk:sessionNotes", payload.Notes)
+		evt.Set(PropSessionNotes, payload.Notes)
+	}
+	if payload.CancellationReason != "" {
+		evt.Set(PropCancellationReason, payload.CancellationReason)
 	}
 	if len(payload.InitiatorOffers) > 0 {
-		evt.Set("sk:initiatorOffers", payload.InitiatorOffers)
+		evt.Set(PropInitiatorOffers, payload.InitiatorOffers)
 	}
 	if len(payload.PartnerOffers) > 0 {
-		evt.Set("sk:partnerOffers", payload.PartnerOffers)
+		evt.Set(PropPartnerOffers, payload.PartnerOffers)
+	}
+	evt.Set(PropIsPremium, payload.IsPremium)
+	evt.SetActivity(activity)
+	return evt
+}
+
+// RatingEvent describes the score one session participant gave the other.
+type RatingEvent struct {
+	RatingID   uuid.UUID
+	SessionID  uuid.UUID
+	ReviewerID uuid.UUID
+	RevieweeID uuid.UUID
+	Score      int
+	Comment    string
+	CreatedAt  time.Time
+}
+
+// NewSessionRatedEvent builds a JSON-LD document for a session rating.
+func NewSessionRatedEvent(payload RatingEvent) Event {
+	created := payload.CreatedAt
+	if created.IsZero() {
+		created = time.Now()
+	}
+	evt := NewEvent(ClassRating+"/"+payload.RatingID.String(), ClassRating)
+	evt.SetTimestamp(created)
+	evt.Set(PropRatedSession, sessionIRI(payload.SessionID))
+	evt.Set(PropRatedBy, userIRI(payload.ReviewerID))
+	evt.Set(PropRatedUser, userIRI(payload.RevieweeID))
+	evt.Set(PropRatingScore, payload.Score)
+	if payload.Comment != "" {
+		evt.Set(PropComment, payload.Comment)
 	}
-	evt.Set("sk:isPremium", payload.IsPremium)
+	evt.SetActivity(Activity{Type: ActivitySessionRated, Actor: payload.ReviewerID, At: created})
 	return evt
 }
 
@@ -75,45 +145,73 @@ type SkillMatchEvent struct {
 
 // MatchEvent describes the output from the Matching service.
 type MatchEvent struct {
-	MatchID      uuid.UUID
-	RequesterID  uuid.UUID
-	CandidateID  uuid.UUID
+	MatchID     uuid.UUID
+	RequesterID uuid.UUID
+	CandidateID uuid.UUID
+	// AlgorithmIRI is a MatchingAlgorithm concept, or one refined by
+	// RankedAlgorithmIRI when a learned model ranked the match.
 	AlgorithmIRI string
 	Score        float64
 	SkillMatches []SkillMatchEvent
-	Explanation  string
+	// Explanation is emitted as sk:explanation. The matching service sets it
+	// to a JSON object holding the explanation's text and its factors.
+	Explanation string
+	// IsMutual is set when both users have accepted each other.
+	IsMutual bool
+}
+
+// RankedAlgorithmIRI refines an algorithm concept with the ranking model
+// version and experiment arm, e.g. sk:MatchingAlgorithmHybrid/v3/treatment.
+// Without a model version the concept is returned as is.
+func RankedAlgorithmIRI(algorithm, modelVersion, variant string) string {
+	if modelVersion == "" {
+		return algorithm
+	}
+	iri := algorithm + "/" + url.PathEscape(modelVersion)
+	if variant != "" {
+		iri += "/" + url.PathEscape(variant)
+	}
+	return iri
 }
 
 // NewMatchEvent builds a JSON-LD representation for a computed match.
 func NewMatchEvent(payload MatchEvent) Event {
+	return newMatchEvent(payload, Activity{Type: ActivityMatchProposed})
+}
+
+func newMatchEvent(payload MatchEvent, activity Activity) Event {
 	eventID := payload.MatchID.String()
 	if eventID == "" {
 		eventID = fmt.Sprintf("%s-%s-%s", payload.RequesterID, payload.CandidateID, time.Now().UTC().Format(time.RFC3339Nano))
 	}
-	evt := NewEvent(fmt.Sprintf("sk:Match/%s", eventID), matchTypeIRI)
+	evt := NewEvent(ClassMatch+"/"+eventID, ClassMatch)
 	evt.SetTimestamp(time.Now())
-	evt.Set("sk:hasMatch", userIRI(payload.RequesterID))
-	evt.Set("sk:matchTarget", userIRI(payload.CandidateID))
+	evt.Set(PropHasMatch, userIRI(payload.RequesterID))
+	evt.Set(PropMatchTarget, userIRI(payload.CandidateID))
 	if payload.AlgorithmIRI != "" {
-		evt.Set("sk:matchingAlgorithm", payload.AlgorithmIRI)
+		evt.Set(PropMatchingAlgorithm, payload.AlgorithmIRI)
 	}
 	if payload.Score > 0 {
-		evt.Set("sk:matchScore", payload.Score)
+		evt.Set(PropMatchScore, payload.Score)
 	}
 	if payload.Explanation != "" {
-		evt.Set("sk:explanation", payload.Explanation)
+		evt.Set(PropExplanation, payload.Explanation)
+	}
+	if payload.IsMutual {
+		evt.Set(PropIsMutual, true)
 	}
 	if len(payload.SkillMatches) > 0 {
 		matches := make([]map[string]any, 0, len(payload.SkillMatches))
 		for _, sm := range payload.SkillMatches {
 			matches = append(matches, map[string]any{
-				"sk:skillName":        sm.SkillName,
-				"sk:userProficiency":  sm.UserProficiency,
-				"sk:matchProficiency": sm.MatchProficiency,
-				"sk:isComplementary":  sm.IsComplementary,
+				PropSkillName:        sm.SkillName,
+				PropUserProficiency:  sm.UserProficiency,
+				PropMatchProficiency: sm.MatchProficiency,
+				PropIsComplementary:  sm.IsComplementary,
 			})
 		}
-		evt.Set("sk:skillMatches", matches)
+		evt.Set(PropSkillMatches, matches)
 	}
+	evt.SetActivity(activity)
 	return evt
 }
@@ -0,0 +1,443 @@
+package ontology
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	generated "github.com/FACorreiaa/skillsphere-api/ontology"
+)
+
+// SHACLNS is the SHACL vocabulary namespace.
+const SHACLNS = "http://www.w3.org/ns/shacl#"
+
+const (
+	shNodeShape    = SHACLNS + "NodeShape"
+	shTargetClass  = SHACLNS + "targetClass"
+	shProperty     = SHACLNS + "property"
+	shPath         = SHACLNS + "path"
+	shDatatype     = SHACLNS + "datatype"
+	shClass        = SHACLNS + "class"
+	shNodeKind     = SHACLNS + "nodeKind"
+	shMinCount     = SHACLNS + "minCount"
+	shMaxCount     = SHACLNS + "maxCount"
+	shIn           = SHACLNS + "in"
+	shPattern      = SHACLNS + "pattern"
+	shIRI          = SHACLNS + "IRI"
+	shBlankNode    = SHACLNS + "BlankNode"
+	shLiteral      = SHACLNS + "Literal"
+	shBlankOrIRI   = SHACLNS + "BlankNodeOrIRI"
+	shBlankOrLit   = SHACLNS + "BlankNodeOrLiteral"
+	shIRIOrLiteral = SHACLNS + "IRIOrLiteral"
+)
+
+// Annotation properties that carry no constraint and are skipped.
+var shaclAnnotations = map[string]bool{
+	SHACLNS + "name":        true,
+	SHACLNS + "description": true,
+	SHACLNS + "message":     true,
+	SHACLNS + "order":       true,
+	SHACLNS + "group":       true,
+}
+
+// ValidationReport is a SHACL validation report reduced to the fields the
+// outbox stores alongside a quarantined event.
+type ValidationReport struct {
+	Conforms bool               `json:"conforms"`
+	Results  []ValidationResult `json:"results,omitempty"`
+}
+
+// ValidationResult is one constraint violation. Nodes and values are written
+// in N-Triples syntax so IRIs, blank nodes and literals stay distinguishable.
+type ValidationResult struct {
+	FocusNode  string `json:"focusNode"`
+	Path       string `json:"resultPath,omitempty"`
+	Value      string `json:"value,omitempty"`
+	Constraint string `json:"sourceConstraintComponent"`
+	Shape      string `json:"sourceShape,omitempty"`
+	Message    string `json:"resultMessage"`
+}
+
+// String summarises the report in one line for logs.
+func (r ValidationReport) String() string {
+	if r.Conforms {
+		return "conforms"
+	}
+	if len(r.Results) == 0 {
+		return "does not conform"
+	}
+	first := r.Results[0]
+	summary := first.FocusNode
+	if first.Path != "" {
+		summary += " <" + first.Path + ">"
+	}
+	summary += ": " + first.Message
+	if len(r.Results) > 1 {
+		summary += fmt.Sprintf(" (and %d more)", len(r.Results)-1)
+	}
+	return summary
+}
+
+type nodeShape struct {
+	name          Term
+	targetClasses []string
+	properties    []propertyShape
+}
+
+type propertyShape struct {
+	path     string
+	datatype string
+	class    string
+	nodeKind string
+	minCount int
+	maxCount int // -1 when unbounded
+	in       []Term
+	pattern  *regexp.Regexp
+}
+
+// Validator checks data graphs against SHACL core node shapes. It supports
+// sh:targetClass and property shapes with a single-IRI sh:path and the
+// sh:datatype, sh:class, sh:nodeKind, sh:minCount, sh:maxCount, sh:in and
+// sh:pattern (without sh:flags) constraints; shapes using anything else are
+// rejected when loaded.
+type Validator struct {
+	shapes []nodeShape
+}
+
+var defaultValidator = sync.OnceValues(func() (*Validator, error) {
+	return NewValidator(generated.Shapes)
+})
+
+// DefaultValidator returns a validator for the generated ontology/shapes.ttl.
+func DefaultValidator() (*Validator, error) {
+	return defaultValidator()
+}
+
+// NewValidator loads the node shapes from a Turtle shapes graph.
+func NewValidator(shapesTurtle []byte) (*Validator, error) {
+	triples, err := ParseTurtle(shapesTurtle)
+	if err != nil {
+		return nil, fmt.Errorf("parse shapes: %w", err)
+	}
+	graph := indexGraph(triples)
+
+	var shapes []nodeShape
+	for _, subject := range graph.subjects {
+		props := graph.props[subject]
+		if !slices.Contains(props[rdfType], IRI(shNodeShape)) && len(props[shTargetClass]) == 0 {
+			continue
+		}
+		shape, err := parseNodeShape(graph, subject)
+		if err != nil {
+			return nil, err
+		}
+		shapes = append(shapes, shape)
+	}
+	if len(shapes) == 0 {
+		return nil, fmt.Errorf("parse shapes: no node shapes found")
+	}
+	return &Validator{shapes: shapes}, nil
+}
+
+func parseNodeShape(graph rdfGraph, subject Term) (nodeShape, error) {
+	shape := nodeShape{name: subject}
+	for _, predicate := range graph.predicates(subject) {
+		objects := graph.props[subject][predicate]
+		switch {
+		case predicate == rdfType || shaclAnnotations[predicate] || !strings.HasPrefix(predicate, SHACLNS):
+		case predicate == shTargetClass:
+			for _, class := range objects {
+				if class.Kind != IRITerm {
+					return shape, fmt.Errorf("shape %s: sh:targetClass must be an IRI", formatTerm(subject))
+				}
+				shape.targetClasses = append(shape.targetClasses, class.Value)
+			}
+		case predicate == shProperty:
+			for _, node := range objects {
+				prop, err := parsePropertyShape(graph, node)
+				if err != nil {
+					return shape, fmt.Errorf("shape %s: %w", formatTerm(subject), err)
+				}
+				shape.properties = append(shape.properties, prop)
+			}
+		default:
+			return shape, fmt.Errorf("shape %s: unsupported SHACL term <%s>", formatTerm(subject), predicate)
+		}
+	}
+	return shape, nil
+}
+
+func parsePropertyShape(graph rdfGraph, node Term) (propertyShape, error) {
+	prop := propertyShape{maxCount: -1}
+	single := func(predicate string) (Term, error) {
+		objects := graph.props[node][predicate]
+		if len(objects) != 1 {
+			return Term{}, fmt.Errorf("property shape %s: expected one <%s>, found %d", formatTerm(node), predicate, len(objects))
+		}
+		return objects[0], nil
+	}
+	iri := func(predicate string) (string, error) {
+		term, err := single(predicate)
+		if err == nil && term.Kind != IRITerm {
+			err = fmt.Errorf("property shape %s: <%s> must be an IRI", formatTerm(node), predicate)
+		}
+		return term.Value, err
+	}
+	count := func(predicate string) (int, error) {
+		term, err := single(predicate)
+		if err != nil {
+			return 0, err
+		}
+		n, convErr := strconv.Atoi(term.Value)
+		if term.Kind != LiteralTerm || convErr != nil || n < 0 {
+			return 0, fmt.Errorf("property shape %s: <%s> must be a non-negative integer", formatTerm(node), predicate)
+		}
+		return n, nil
+	}
+
+	var err error
+	for _, predicate := range graph.predicates(node) {
+		switch {
+		case shaclAnnotations[predicate] || !strings.HasPrefix(predicate, SHACLNS):
+		case predicate == shPath:
+			prop.path, err = iri(predicate)
+		case predicate == shDatatype:
+			prop.datatype, err = iri(predicate)
+		case predicate == shClass:
+			prop.class, err = iri(predicate)
+		case predicate == shNodeKind:
+			prop.nodeKind, err = iri(predicate)
+		case predicate == shMinCount:
+			prop.minCount, err = count(predicate)
+		case predicate == shMaxCount:
+			prop.maxCount, err = count(predicate)
+		case predicate == shIn:
+			var head Term
+			if head, err = single(predicate); err == nil {
+				prop.in, err = graph.list(head)
+			}
+		case predicate == shPattern:
+			var term Term
+			if term, err = single(predicate); err == nil {
+				if term.Kind != LiteralTerm {
+					err = fmt.Errorf("property shape %s: sh:pattern must be a literal", formatTerm(node))
+				} else if prop.pattern, err = regexp.Compile(term.Value); err != nil {
+					err = fmt.Errorf("property shape %s: sh:pattern: %w", formatTerm(node), err)
+				}
+			}
+		default:
+			err = fmt.Errorf("property shape %s: unsupported SHACL term <%s>", formatTerm(node), predicate)
+		}
+		if err != nil {
+			return prop, err
+		}
+	}
+	if prop.path == "" {
+		return prop, fmt.Errorf("property shape %s: sh:path is required and must be an IRI", formatTerm(node))
+	}
+	return prop, nil
+}
+
+// ValidateDocument expands a JSON-LD document and validates it.
+func (v *Validator) ValidateDocument(document []byte) (ValidationReport, error) {
+	triples, err := ToRDF(document)
+	if err != nil {
+		return ValidationReport{}, err
+	}
+	return v.Validate(triples), nil
+}
+
+// Validate checks every focus node in triples against the loaded shapes.
+//
+// Events reference other resources by IRI without repeating their rdf:type,
+// so sh:class also accepts an IRI minted under the class IRI: sk:User/42 is
+// treated as an instance of sk:User.
+func (v *Validator) Validate(triples []Triple) ValidationReport {
+	data := indexGraph(triples)
+	report := ValidationReport{Conforms: true}
+
+	for _, shape := range v.shapes {
+		for _, focus := range data.subjects {
+			if !shape.targets(data, focus) {
+				continue
+			}
+			for _, prop := range shape.properties {
+				for _, result := range prop.validate(data, focus) {
+					result.Shape = formatTerm(shape.name)
+					report.Results = append(report.Results, result)
+				}
+			}
+		}
+	}
+	report.Conforms = len(report.Results) == 0
+	return report
+}
+
+func (s nodeShape) targets(data rdfGraph, focus Term) bool {
+	for _, class := range s.targetClasses {
+		if slices.Contains(data.props[focus][rdfType], IRI(class)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p propertyShape) validate(data rdfGraph, focus Term) []ValidationResult {
+	var results []ValidationResult
+	fail := func(component string, value *Term, format string, args ...any) {
+		result := ValidationResult{
+			FocusNode:  formatTerm(focus),
+			Path:       p.path,
+			Constraint: "sh:" + component + "ConstraintComponent",
+			Message:    fmt.Sprintf(format, args...),
+		}
+		if value != nil {
+			result.Value = formatTerm(*value)
+		}
+		results = append(results, result)
+	}
+
+	values := data.props[focus][p.path]
+	if len(values) < p.minCount {
+		fail("MinCount", nil, "expected at least %d value(s), found %d", p.minCount, len(values))
+	}
+	if p.maxCount >= 0 && len(values) > p.maxCount {
+		fail("MaxCount", nil, "expected at most %d value(s), found %d", p.maxCount, len(values))
+	}
+
+	for _, value := range values {
+		if p.datatype != "" && (value.Kind != LiteralTerm || value.Datatype != p.datatype || !wellFormed(value.Value, value.Datatype)) {
+			fail("Datatype", &value, "value is not a well-formed <%s> literal", p.datatype)
+		}
+		if p.nodeKind != "" && !matchesNodeKind(value, p.nodeKind) {
+			fail("NodeKind", &value, "value does not have node kind <%s>", p.nodeKind)
+		}
+		if p.class != "" && !data.instanceOf(value, p.class) {
+			fail("Class", &value, "value is not an instance of <%s>", p.class)
+		}
+		if p.in != nil && !slices.Contains(p.in, value) {
+			fail("In", &value, "value is not one of the %d allowed values", len(p.in))
+		}
+		if p.pattern != nil && (value.Kind == BlankNodeTerm || !p.pattern.MatchString(value.Value)) {
+			fail("Pattern", &value, "value does not match %q", p.pattern)
+		}
+	}
+	return results
+}
+
+func matchesNodeKind(term Term, kind string) bool {
+	switch kind {
+	case shIRI:
+		return term.Kind == IRITerm
+	case shBlankNode:
+		return term.Kind == BlankNodeTerm
+	case shLiteral:
+		return term.Kind == LiteralTerm
+	case shBlankOrIRI:
+		return term.Kind != LiteralTerm
+	case shBlankOrLit:
+		return term.Kind != IRITerm
+	case shIRIOrLiteral:
+		return term.Kind != BlankNodeTerm
+	default:
+		return false
+	}
+}
+
+var (
+	xsdIntegerPattern = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	xsdDecimalPattern = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+)
+
+// wellFormed checks the lexical form of the datatypes events use; other
+// datatypes are accepted as-is.
+func wellFormed(value, datatype string) bool {
+	switch datatype {
+	case xsdBoolean:
+		return value == "true" || value == "false" || value == "1" || value == "0"
+	case xsdInteger:
+		return xsdIntegerPattern.MatchString(value)
+	case xsdDecimal:
+		return xsdDecimalPattern.MatchString(value)
+	case xsdDouble:
+		if value == "INF" || value == "-INF" || value == "NaN" {
+			return true
+		}
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil && !strings.ContainsAny(value, "xXpP_")
+	case xsdDateTime:
+		// The timezone is optional in xsd:dateTime.
+		if _, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return true
+		}
+		_, err := time.Parse("2006-01-02T15:04:05.999999999", value)
+		return err == nil
+	case xsdAnyURI:
+		_, err := url.Parse(value)
+		return err == nil
+	default:
+		return true
+	}
+}
+
+// rdfGraph indexes triples by subject and predicate, keeping first-seen order.
+type rdfGraph struct {
+	subjects []Term
+	props    map[Term]map[string][]Term
+	order    map[Term][]string
+}
+
+func indexGraph(triples []Triple) rdfGraph {
+	g := rdfGraph{
+		props: make(map[Term]map[string][]Term),
+		order: make(map[Term][]string),
+	}
+	for _, t := range triples {
+		byPredicate, ok := g.props[t.Subject]
+		if !ok {
+			byPredicate = make(map[string][]Term)
+			g.props[t.Subject] = byPredicate
+			g.subjects = append(g.subjects, t.Subject)
+		}
+		if _, ok := byPredicate[t.Predicate.Value]; !ok {
+			g.order[t.Subject] = append(g.order[t.Subject], t.Predicate.Value)
+		}
+		byPredicate[t.Predicate.Value] = append(byPredicate[t.Predicate.Value], t.Object)
+	}
+	return g
+}
+
+func (g rdfGraph) predicates(subject Term) []string {
+	return g.order[subject]
+}
+
+// list reads an RDF collection starting at head.
+func (g rdfGraph) list(head Term) ([]Term, error) {
+	items := []Term{}
+	seen := make(map[Term]bool)
+	for node := head; node != IRI(rdfNil); {
+		if seen[node] {
+			return nil, fmt.Errorf("cyclic RDF list at %s", formatTerm(node))
+		}
+		seen[node] = true
+		first, rest := g.props[node][rdfFirst], g.props[node][rdfRest]
+		if len(first) != 1 || len(rest) != 1 {
+			return nil, fmt.Errorf("malformed RDF list at %s", formatTerm(node))
+		}
+		items = append(items, first[0])
+		node = rest[0]
+	}
+	return items, nil
+}
+
+func (g rdfGraph) instanceOf(term Term, class string) bool {
+	if slices.Contains(g.props[term][rdfType], IRI(class)) {
+		return true
+	}
+	return term.Kind == IRITerm && strings.HasPrefix(term.Value, class+"/") && len(term.Value) > len(class)+1
+}
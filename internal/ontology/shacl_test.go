@@ -0,0 +1,258 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/auth/repository"
+)
+
+func testUser() *repository.User {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &repository.User{
+		ID:          uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		Email:       "ada@example.com",
+		DisplayName: "Ada",
+		Role:        "member",
+		IsActive:    true,
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+}
+
+func validate(t *testing.T, evt Event) ValidationReport {
+	t.Helper()
+	validator, err := DefaultValidator()
+	if err != nil {
+		t.Fatalf("DefaultValidator: %v", err)
+	}
+	payload, err := evt.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := validator.ValidateDocument(payload)
+	if err != nil {
+		t.Fatalf("ValidateDocument: %v", err)
+	}
+	return report
+}
+
+func TestDefaultValidator_BuildersConform(t *testing.T) {
+	initiator, partner := uuid.New(), uuid.New()
+	events := map[string]Event{
+		"user": NewUserRegisteredEvent(testUser()),
+		"session": NewSessionScheduledEvent(SessionEvent{
+			SessionID:      uuid.New(),
+			InitiatorID:    initiator,
+			PartnerID:      partner,
+			StatusIRI:      "sk:SessionStatusScheduled",
+			ScheduledStart: time.Now(),
+			MeetingURL:     "https://meet.example.com/abc",
+			PartnerOffers:  []string{"Go", "RDF"},
+		}),
+		"match": NewMatchEvent(MatchEvent{
+			MatchID:      uuid.New(),
+			RequesterID:  initiator,
+			CandidateID:  partner,
+			AlgorithmIRI: "sk:MatchingAlgorithmCosine",
+			Score:        0.8,
+			SkillMatches: []SkillMatchEvent{{SkillName: "Go", UserProficiency: 3}},
+		}),
+		"user login":       NewUserLoggedInEvent(testUser(), time.Now()),
+		"user deactivated": NewUserEvent(testUser(), Activity{Type: ActivityUserDeactivated, Actor: partner, Reason: "spam"}),
+		"session cancelled": NewSessionStatusEvent(SessionEvent{
+			SessionID:          uuid.New(),
+			InitiatorID:        initiator,
+			PartnerID:          partner,
+			StatusIRI:          ConceptSessionStatusCancelled,
+			CancellationReason: "sick",
+		}, partner),
+		"match accepted": NewMatchEvent(MatchEvent{MatchID: uuid.New(), RequesterID: initiator, CandidateID: partner}),
+		"rating": NewSessionRatedEvent(RatingEvent{
+			RatingID:   uuid.New(),
+			SessionID:  uuid.New(),
+			ReviewerID: initiator,
+			RevieweeID: partner,
+			Score:      5,
+			Comment:    "Great",
+		}),
+		"skill": NewSkillEvent(SkillEvent{
+			SkillID:     uuid.New(),
+			Name:        "Go",
+			CategoryIRI: ConceptSkillCategoryTech,
+			Tags:        []string{"backend"},
+		}, Activity{Type: ActivitySkillCreated, Actor: initiator}),
+		"user skill": NewUserSkillEvent(UserSkillEvent{UserID: initiator, SkillID: uuid.New(), IsOffered: true, Proficiency: 7},
+			Activity{Type: ActivityUserSkillAdded, Actor: initiator}),
+		"review": NewReviewEvent(ReviewEvent{
+			ReviewID:    uuid.New(),
+			RequesterID: initiator,
+			ReviewerID:  partner,
+			SkillID:     uuid.New(),
+			Title:       "API design",
+			StatusIRI:   ConceptReviewStatusAccepted,
+			TypeIRI:     ConceptReviewTypeCodeReview,
+		}, Activity{Type: ActivityReviewAccepted, Actor: partner}),
+	}
+	for name, evt := range events {
+		if report := validate(t, evt); !report.Conforms {
+			t.Errorf("%s event does not conform: %+v", name, report.Results)
+		}
+	}
+}
+
+func TestDefaultValidator_ReportsViolations(t *testing.T) {
+	evt := NewSessionScheduledEvent(SessionEvent{
+		SessionID:   uuid.New(),
+		InitiatorID: uuid.New(),
+		PartnerID:   uuid.New(),
+		StatusIRI:   "sk:SessionStatusPostponed",
+	})
+	evt.Set("sk:initiatedBy", "sk:Match/1")
+	evt.Set("sk:isPremium", []bool{true, false})
+	evt.Set("sk:scheduledStart", "next tuesday")
+
+	report := validate(t, evt)
+	if report.Conforms {
+		t.Fatal("report conforms, want violations")
+	}
+	got := map[string]string{}
+	for _, result := range report.Results {
+		got[strings.TrimPrefix(result.Path, SkillSphereNS)+" "+result.Constraint] = result.Value
+		if result.Shape != "<"+SkillSphereNS+"SessionShape>" || !strings.HasPrefix(result.FocusNode, "<"+SkillSphereNS+"Session/") {
+			t.Errorf("result %+v has wrong shape or focus node", result)
+		}
+	}
+	want := map[string]string{
+		"sessionStatus sh:InConstraintComponent":        "<" + SkillSphereNS + "SessionStatusPostponed>",
+		"initiatedBy sh:ClassConstraintComponent":       "<" + SkillSphereNS + "Match/1>",
+		"isPremium sh:MaxCountConstraintComponent":      "",
+		"scheduledStart sh:DatatypeConstraintComponent": `"next tuesday"^^<` + XSDNS + `dateTime>`,
+	}
+	for key, value := range want {
+		if v, ok := got[key]; !ok || v != value {
+			t.Errorf("missing %s (value %s); got %v", key, value, got)
+		}
+	}
+	if len(report.Results) != len(want) {
+		t.Errorf("got %d results, want %d: %+v", len(report.Results), len(want), report.Results)
+	}
+	if s := report.String(); !strings.Contains(s, "and 3 more") {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestDefaultValidator_MissingRequiredProperty(t *testing.T) {
+	evt := NewEvent("sk:User/1", ClassUser)
+	evt.Set("schema:email", "ada@example.com")
+
+	report := validate(t, evt)
+	var missing []string
+	for _, result := range report.Results {
+		if result.Constraint == "sh:MinCountConstraintComponent" {
+			missing = append(missing, result.Path)
+		}
+	}
+	want := []string{SchemaNS + "name", SkillSphereNS + "isActive", SkillSphereNS + "role"}
+	if strings.Join(missing, " ") != strings.Join(want, " ") {
+		t.Errorf("missing = %v, want %v", missing, want)
+	}
+}
+
+func TestNewValidator_RejectsUnsupportedConstraints(t *testing.T) {
+	shapes := `@prefix sh: <http://www.w3.org/ns/shacl#> .
+@prefix ex: <https://example.com/> .
+ex:Shape a sh:NodeShape ;
+  sh:targetClass ex:Thing ;
+  sh:property [ sh:path ex:name ; sh:minLength 2 ] .`
+	if _, err := NewValidator([]byte(shapes)); err == nil || !strings.Contains(err.Error(), "minLength") {
+		t.Fatalf("NewValidator error = %v, want unsupported sh:minLength", err)
+	}
+}
+
+func TestDefaultValidator_MatchingAlgorithmPattern(t *testing.T) {
+	for iri, conforms := range map[string]bool{
+		ConceptMatchingAlgorithmHybrid:                                        true,
+		RankedAlgorithmIRI(ConceptMatchingAlgorithmHybrid, "v3", "treatment"): true,
+		"sk:MatchingAlgorithmMagic":                                           false,
+		ConceptMatchingAlgorithmCosine + "//control":                          false,
+	} {
+		report := validate(t, NewMatchEvent(MatchEvent{
+			MatchID:      uuid.New(),
+			RequesterID:  uuid.New(),
+			CandidateID:  uuid.New(),
+			AlgorithmIRI: iri,
+		}))
+		if report.Conforms != conforms {
+			t.Errorf("%s: conforms = %v, want %v: %+v", iri, report.Conforms, conforms, report.Results)
+		}
+		for _, result := range report.Results {
+			if result.Constraint != "sh:PatternConstraintComponent" {
+				t.Errorf("%s: unexpected result %+v", iri, result)
+			}
+		}
+	}
+}
+
+func TestParseTurtle(t *testing.T) {
+	doc := `# comment
+@prefix ex: <https://example.com/> .
+PREFIX xsd: <http://www.w3.org/2001/XMLSchema#>
+
+ex:a a ex:Thing ;
+  ex:label "café"@EN , 'single' ;
+  ex:note """two
+lines""" ;
+  ex:count 3 ; ex:ratio -1.5 ; ex:big 1e3 ; ex:ok true ;
+  ex:when "2025-01-01T00:00:00Z"^^xsd:dateTime ;
+  ex:items ( ex:x "y" ) ;
+  ex:child [ ex:name "kid" ] ;
+  ex:ref _:n1 .
+_:n1 ex:back ex:a.
+`
+	triples, err := ParseTurtle([]byte(doc))
+	if err != nil {
+		t.Fatalf("ParseTurtle: %v", err)
+	}
+
+	var b strings.Builder
+	if err := WriteNTriples(&b, triples); err != nil {
+		t.Fatal(err)
+	}
+	want := `<https://example.com/a> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <https://example.com/Thing> .
+<https://example.com/a> <https://example.com/label> "café"@en .
+<https://example.com/a> <https://example.com/label> "single" .
+<https://example.com/a> <https://example.com/note> "two\nlines" .
+<https://example.com/a> <https://example.com/count> "3"^^<http://www.w3.org/2001/XMLSchema#integer> .
+<https://example.com/a> <https://example.com/ratio> "-1.5"^^<http://www.w3.org/2001/XMLSchema#decimal> .
+<https://example.com/a> <https://example.com/big> "1e3"^^<http://www.w3.org/2001/XMLSchema#double> .
+<https://example.com/a> <https://example.com/ok> "true"^^<http://www.w3.org/2001/XMLSchema#boolean> .
+<https://example.com/a> <https://example.com/when> "2025-01-01T00:00:00Z"^^<http://www.w3.org/2001/XMLSchema#dateTime> .
+_:t0 <http://www.w3.org/1999/02/22-rdf-syntax-ns#first> <https://example.com/x> .
+_:t0 <http://www.w3.org/1999/02/22-rdf-syntax-ns#rest> _:t1 .
+_:t1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#first> "y" .
+_:t1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#rest> <http://www.w3.org/1999/02/22-rdf-syntax-ns#nil> .
+<https://example.com/a> <https://example.com/items> _:t0 .
+_:t2 <https://example.com/name> "kid" .
+<https://example.com/a> <https://example.com/child> _:t2 .
+<https://example.com/a> <https://example.com/ref> _:t3 .
+_:t3 <https://example.com/back> <https://example.com/a> .
+`
+	if got := b.String(); got != want {
+		t.Errorf("N-Triples mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	for _, bad := range []string{
+		`ex:a ex:b ex:c .`,
+		`@prefix ex: <https://example.com/> . ex:a ex:b "open .`,
+		`@base <https://example.com/> .`,
+		`@prefix ex: <relative> .`,
+	} {
+		if _, err := ParseTurtle([]byte(bad)); err == nil {
+			t.Errorf("ParseTurtle(%q) succeeded, want error", bad)
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package ontology
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
-	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/kafka"
 )
 
 // LogProducer logs Kafka payloads.
@@ -17,41 +21,49 @@ func NewLogProducer(logger *slog.Logger) *LogProducer {
 }
 
 // Publish implements KafkaProducer.
-func (p *LogProducer) Publish(ctx context.Context, topic string, payload []byte) error {
+func (p *LogProducer) Publish(ctx context.Context, topic string, msg kafka.Message) error {
 	if p == nil || p.logger == nil {
 		return nil
 	}
-	p.logger.InfoContext(ctx, "kafka publish", "topic", topic, "payload", string(payload))
+	p.logger.InfoContext(ctx, "kafka publish", "topic", topic, "key", string(msg.Key), "payload", string(msg.Value))
 	return nil
 }
 
-// HTTPTripleStoreClient posts JSON-LD payloads to an HTTP endpoint.
-type HTTPTripleStoreClient struct {
-	endpoint string
-	client   *http.Client
+// RejectedEventError is returned by DirectEmitter for an event that does not
+// conform to the SHACL shapes.
+type RejectedEventError struct {
+	Report ValidationReport
 }
 
-func NewHTTPTripleStoreClient(endpoint string) *HTTPTripleStoreClient {
-	return &HTTPTripleStoreClient{
-		endpoint: endpoint,
-		client:   &http.Client{},
-	}
+func (e *RejectedEventError) Error() string {
+	return fmt.Sprintf("event does not conform to shapes: %d violation(s)", len(e.Report.Results))
 }
 
-// Insert implements TripleStoreClient.
-func (c *HTTPTripleStoreClient) Insert(ctx context.Context, payload []byte) error {
-	if c == nil || c.endpoint == "" {
-		return nil
-	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(payload))
+// DirectEmitter delivers events straight to Kafka and the triple store,
+// bypassing the outbox. There is no retry or quarantine, so it suits one-off
+// jobs such as backfills that can simply be re-run.
+type DirectEmitter struct {
+	topic     string
+	kafka     KafkaProducer
+	triple    TripleStoreClient
+	validator *Validator
+}
+
+// NewDirectEmitter builds an emitter for the given sinks; either may be nil.
+// When validator is set, events that do not conform are returned as a
+// *RejectedEventError instead of being delivered.
+func NewDirectEmitter(topic string, kafka KafkaProducer, triple TripleStoreClient, validator *Validator) *DirectEmitter {
+	return &DirectEmitter{topic: topic, kafka: kafka, triple: triple, validator: validator}
+}
+
+// Emit implements Emitter.
+func (e *DirectEmitter) Emit(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
 	if err != nil {
-		return err
+		return fmt.Errorf("marshal ontology event: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/ld+json")
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return err
+	if report, ok := validatePayload(e.validator, payload); !ok {
+		return &RejectedEventError{Report: report}
 	}
-	defer resp.Body.Close()
-	return nil
+	return deliver(ctx, e.topic, e.kafka, e.triple, uuid.NewString(), event.Type, payload)
 }
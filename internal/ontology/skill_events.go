@@ -0,0 +1,97 @@
+package ontology
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func skillIRI(id uuid.UUID) string {
+	return ClassSkill + "/" + id.String()
+}
+
+// SkillIRI returns the absolute IRI of a catalogue skill.
+func SkillIRI(id uuid.UUID) string {
+	return ExpandIRI(skillIRI(id))
+}
+
+// ParseSkillIRI returns the id of a catalogue skill IRI, compact or absolute.
+func ParseSkillIRI(iri string) (uuid.UUID, bool) {
+	rest, ok := strings.CutPrefix(ExpandIRI(iri), ExpandIRI(ClassSkill)+"/")
+	if !ok {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(rest)
+	return id, err == nil
+}
+
+// SkillEvent describes a skill in the catalogue.
+type SkillEvent struct {
+	SkillID     uuid.UUID
+	Name        string
+	Description string
+	// CategoryIRI is a SkillCategory concept, e.g. ConceptSkillCategoryTech.
+	CategoryIRI string
+	Tags        []string
+	UpdatedAt   time.Time
+}
+
+// NewSkillEvent builds a JSON-LD snapshot of a catalogue skill produced by activity.
+func NewSkillEvent(payload SkillEvent, activity Activity) Event {
+	updated := payload.UpdatedAt
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	evt := NewEvent(skillIRI(payload.SkillID), ClassSkill)
+	evt.SetTimestamp(updated)
+	evt.Set(SchemaName, payload.Name)
+	if payload.Description != "" {
+		evt.Set(PropDescription, payload.Description)
+	}
+	if payload.CategoryIRI != "" {
+		evt.Set(PropSkillCategory, payload.CategoryIRI)
+	}
+	if len(payload.Tags) > 0 {
+		evt.Set(PropTags, payload.Tags)
+	}
+	evt.SetActivity(activity)
+	return evt
+}
+
+// UserSkillEvent describes a skill a user offers or wants to learn.
+type UserSkillEvent struct {
+	UserID      uuid.UUID
+	SkillID     uuid.UUID
+	IsOffered   bool
+	Proficiency int
+	UpdatedAt   time.Time
+}
+
+// userSkillIRI is keyed like the user_skills primary key, so offering and
+// wanting the same skill are separate resources.
+func userSkillIRI(payload UserSkillEvent) string {
+	kind := "wanted"
+	if payload.IsOffered {
+		kind = "offered"
+	}
+	return ClassUserSkill + "/" + payload.UserID.String() + "/" + payload.SkillID.String() + "/" + kind
+}
+
+// NewUserSkillEvent builds a JSON-LD snapshot of a user's skill produced by activity.
+func NewUserSkillEvent(payload UserSkillEvent, activity Activity) Event {
+	updated := payload.UpdatedAt
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	evt := NewEvent(userSkillIRI(payload), ClassUserSkill)
+	evt.SetTimestamp(updated)
+	evt.Set(PropHeldBy, userIRI(payload.UserID))
+	evt.Set(PropSkill, skillIRI(payload.SkillID))
+	evt.Set(PropIsOffered, payload.IsOffered)
+	if payload.Proficiency > 0 {
+		evt.Set(PropProficiencyLevel, payload.Proficiency)
+	}
+	evt.SetActivity(activity)
+	return evt
+}
@@ -0,0 +1,1414 @@
+package ontology
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxQueryRows caps the solutions a query returns; a missing or larger LIMIT
+// is lowered to it.
+const MaxQueryRows = 10000
+
+// Binding maps variable names, without the leading '?', to terms.
+type Binding map[string]Term
+
+// QueryResult is the answer to a SELECT query. Solutions are unordered and
+// omit variables the query left unbound.
+type QueryResult struct {
+	Vars      []string
+	Solutions []Binding
+}
+
+// MarshalJSON renders the result in the SPARQL 1.1 Query Results JSON Format.
+func (r *QueryResult) MarshalJSON() ([]byte, error) {
+	type jsonTerm struct {
+		Type     string `json:"type"`
+		Value    string `json:"value"`
+		Datatype string `json:"datatype,omitempty"`
+		Language string `json:"xml:lang,omitempty"`
+	}
+	bindings := make([]map[string]jsonTerm, 0, len(r.Solutions))
+	for _, solution := range r.Solutions {
+		row := make(map[string]jsonTerm, len(solution))
+		for name, term := range solution {
+			switch term.Kind {
+			case IRITerm:
+				row[name] = jsonTerm{Type: "uri", Value: term.Value}
+			case BlankNodeTerm:
+				row[name] = jsonTerm{Type: "bnode", Value: term.Value}
+			default:
+				t := jsonTerm{Type: "literal", Value: term.Value, Language: term.Language}
+				if term.Datatype != xsdString && term.Datatype != rdfLangString {
+					t.Datatype = term.Datatype
+				}
+				row[name] = t
+			}
+		}
+		bindings = append(bindings, row)
+	}
+	vars := r.Vars
+	if vars == nil {
+		vars = []string{}
+	}
+	return json.Marshal(map[string]any{
+		"head":    map[string]any{"vars": vars},
+		"results": map[string]any{"bindings": bindings},
+	})
+}
+
+// sparqlQuery is a parsed SELECT query. The supported subset is PREFIX
+// declarations, SELECT [DISTINCT] with variables or *, basic graph patterns,
+// FILTER, OPTIONAL, UNION, nested groups, LIMIT and OFFSET.
+type sparqlQuery struct {
+	vars     []string
+	distinct bool
+	where    *groupPattern
+	limit    int
+	offset   int
+}
+
+// groupPattern is a { … } block: its elements are joined in order and its
+// filters apply to every solution of the block.
+type groupPattern struct {
+	elements []groupElement
+	filters  []sparqlExpr
+}
+
+// groupElement is exactly one of a basic graph pattern, an OPTIONAL group or
+// a UNION of groups (a nested group is a union of one).
+type groupElement struct {
+	triples  []triplePattern
+	optional *groupPattern
+	union    []*groupPattern
+}
+
+type triplePattern struct {
+	s, p, o patternTerm
+}
+
+// patternTerm is either a variable or a constant term.
+type patternTerm struct {
+	variable string
+	term     Term
+}
+
+// --- lexer ---
+
+type sparqlTokenKind uint8
+
+const (
+	tokEOF sparqlTokenKind = iota
+	tokIRI
+	tokPName
+	tokVar
+	tokBlank
+	tokString
+	tokLangTag
+	tokNumber
+	tokKeyword
+	tokPunct
+)
+
+type sparqlToken struct {
+	kind  sparqlTokenKind
+	value string
+	pos   int
+}
+
+func (t sparqlToken) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokIRI:
+		return "<" + t.value + ">"
+	case tokVar:
+		return "?" + t.value
+	default:
+		return strconv.Quote(t.value)
+	}
+}
+
+// QuerySyntaxError reports a query outside the supported SPARQL subset.
+type QuerySyntaxError struct {
+	Offset  int
+	Message string
+}
+
+func (e *QuerySyntaxError) Error() string {
+	return fmt.Sprintf("sparql: offset %d: %s", e.Offset, e.Message)
+}
+
+func sparqlErrorf(pos int, format string, args ...any) error {
+	return &QuerySyntaxError{Offset: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func lexSPARQL(src string) ([]sparqlToken, error) {
+	var tokens []sparqlToken
+	i := 0
+	for {
+		for i < len(src) {
+			if isTurtleSpace(src[i]) {
+				i++
+			} else if src[i] == '#' {
+				for i < len(src) && src[i] != '\n' {
+					i++
+				}
+			} else {
+				break
+			}
+		}
+		if i >= len(src) {
+			return append(tokens, sparqlToken{kind: tokEOF, pos: i}), nil
+		}
+
+		start := i
+		c := src[i]
+		switch {
+		case c == '<':
+			j := i + 1
+			for j < len(src) && src[j] != '>' && src[j] > 0x20 && !strings.ContainsRune(`<"{}|^`+"`"+`\`, rune(src[j])) {
+				j++
+			}
+			if j < len(src) && src[j] == '>' {
+				tokens = append(tokens, sparqlToken{kind: tokIRI, value: src[i+1 : j], pos: start})
+				i = j + 1
+				continue
+			}
+			if strings.HasPrefix(src[i:], "<=") {
+				tokens = append(tokens, sparqlToken{kind: tokPunct, value: "<=", pos: start})
+				i += 2
+				continue
+			}
+			tokens = append(tokens, sparqlToken{kind: tokPunct, value: "<", pos: start})
+			i++
+		case c == '?' || c == '$':
+			j := i + 1
+			for j < len(src) && isNameChar(src[j]) && src[j] != '-' {
+				j++
+			}
+			if j == i+1 {
+				return nil, sparqlErrorf(start, "empty variable name")
+			}
+			tokens = append(tokens, sparqlToken{kind: tokVar, value: src[i+1 : j], pos: start})
+			i = j
+		case c == '"' || c == '\'':
+			value, next, err := lexString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, sparqlToken{kind: tokString, value: value, pos: start})
+			i = next
+		case c == '@':
+			j := i + 1
+			for j < len(src) && (isASCIILetter(src[j]) || isASCIIDigit(src[j]) || src[j] == '-') {
+				j++
+			}
+			if j == i+1 {
+				return nil, sparqlErrorf(start, "empty language tag")
+			}
+			tokens = append(tokens, sparqlToken{kind: tokLangTag, value: strings.ToLower(src[i+1 : j]), pos: start})
+			i = j
+		case isASCIIDigit(c) || (c == '.' && i+1 < len(src) && isASCIIDigit(src[i+1])):
+			j := i
+			for j < len(src) && isASCIIDigit(src[j]) {
+				j++
+			}
+			if j+1 < len(src) && src[j] == '.' && isASCIIDigit(src[j+1]) {
+				j++
+				for j < len(src) && isASCIIDigit(src[j]) {
+					j++
+				}
+			}
+			if j < len(src) && (src[j] == 'e' || src[j] == 'E') {
+				k := j + 1
+				if k < len(src) && (src[k] == '+' || src[k] == '-') {
+					k++
+				}
+				if k < len(src) && isASCIIDigit(src[k]) {
+					for k < len(src) && isASCIIDigit(src[k]) {
+						k++
+					}
+					j = k
+				}
+			}
+			tokens = append(tokens, sparqlToken{kind: tokNumber, value: src[i:j], pos: start})
+			i = j
+		case c == '_' && i+1 < len(src) && src[i+1] == ':':
+			j := i + 2
+			for j < len(src) && isNameChar(src[j]) {
+				j++
+			}
+			tokens = append(tokens, sparqlToken{kind: tokBlank, value: src[i+2 : j], pos: start})
+			i = j
+		case isASCIILetter(c) || c == ':' || c >= 0x80:
+			j := i
+			for j < len(src) && isNameChar(src[j]) {
+				j++
+			}
+			if j < len(src) && src[j] == ':' {
+				j++
+				j = scanLocalName(src, j)
+				tokens = append(tokens, sparqlToken{kind: tokPName, value: src[i:j], pos: start})
+			} else {
+				tokens = append(tokens, sparqlToken{kind: tokKeyword, value: src[i:j], pos: start})
+			}
+			i = j
+		default:
+			for _, op := range []string{"^^", "&&", "||", "!=", ">=", "{", "}", "(", ")", ".", ",", ";", "*", "=", ">", "!", "+", "-", "/"} {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, sparqlToken{kind: tokPunct, value: op, pos: start})
+					i += len(op)
+					break
+				}
+			}
+			if i == start {
+				r, _ := utf8.DecodeRuneInString(src[i:])
+				return nil, sparqlErrorf(start, "unexpected character %q", r)
+			}
+		}
+	}
+}
+
+// scanLocalName reads the local part of a prefixed name. Unlike SPARQL it
+// accepts '/', since every resource IRI in the graph (sk:User/<uuid>) has one;
+// a trailing '.' ends the triple rather than belonging to the name.
+func scanLocalName(src string, i int) int {
+	start := i
+	for i < len(src) && (isNameChar(src[i]) || src[i] == '.' || src[i] == ':' || src[i] == '/') {
+		i++
+	}
+	for i > start && src[i-1] == '.' {
+		i--
+	}
+	return i
+}
+
+// lexString reads a short or long quoted string starting at i.
+func lexString(src string, i int) (string, int, error) {
+	quote := src[i]
+	long := strings.HasPrefix(src[i:], strings.Repeat(string(quote), 3))
+	j := i + 1
+	if long {
+		j = i + 3
+	}
+	var b strings.Builder
+	for j < len(src) {
+		c := src[j]
+		switch {
+		case long && strings.HasPrefix(src[j:], strings.Repeat(string(quote), 3)):
+			return b.String(), j + 3, nil
+		case !long && c == quote:
+			return b.String(), j + 1, nil
+		case !long && (c == '\n' || c == '\r'):
+			return "", 0, sparqlErrorf(j, "newline in string")
+		case c == '\\':
+			if j+1 >= len(src) {
+				return "", 0, sparqlErrorf(j, "unterminated escape")
+			}
+			j++
+			switch e := src[j]; e {
+			case 't':
+				b.WriteByte('\t')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case '"', '\'', '\\':
+				b.WriteByte(e)
+			case 'u', 'U':
+				n := 4
+				if e == 'U' {
+					n = 8
+				}
+				if j+n >= len(src) {
+					return "", 0, sparqlErrorf(j, "short unicode escape")
+				}
+				code, err := strconv.ParseUint(src[j+1:j+1+n], 16, 32)
+				if err != nil {
+					return "", 0, sparqlErrorf(j, "invalid unicode escape")
+				}
+				b.WriteRune(rune(code))
+				j += n
+			default:
+				return "", 0, sparqlErrorf(j, "invalid escape \\%c", e)
+			}
+			j++
+		default:
+			b.WriteByte(c)
+			j++
+		}
+	}
+	return "", 0, sparqlErrorf(i, "unterminated string")
+}
+
+// --- parser ---
+
+type sparqlParser struct {
+	tokens   []sparqlToken
+	pos      int
+	prefixes map[string]string
+	// mentioned lists the variables in order of appearance, for SELECT *.
+	mentioned []string
+}
+
+// parseSPARQL parses a SELECT query. Prefixes of the bundled JSON-LD context
+// (sk, schema, prov, skos, rdf, rdfs, owl, xsd) are predeclared.
+func parseSPARQL(query string) (*sparqlQuery, error) {
+	tokens, err := lexSPARQL(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &sparqlParser{tokens: tokens, prefixes: bundledContext.prefixes()}
+	return p.query()
+}
+
+func (p *sparqlParser) peek() sparqlToken { return p.tokens[p.pos] }
+
+func (p *sparqlParser) next() sparqlToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *sparqlParser) isKeyword(kw string) bool {
+	t := p.peek()
+	return t.kind == tokKeyword && strings.EqualFold(t.value, kw)
+}
+
+func (p *sparqlParser) isPunct(value string) bool {
+	t := p.peek()
+	return t.kind == tokPunct && t.value == value
+}
+
+func (p *sparqlParser) expectPunct(value string) error {
+	if !p.isPunct(value) {
+		t := p.peek()
+		return sparqlErrorf(t.pos, "expected %q, found %s", value, t)
+	}
+	p.next()
+	return nil
+}
+
+func (p *sparqlParser) mention(name string) {
+	if !strings.HasPrefix(name, "_:") && !slices.Contains(p.mentioned, name) {
+		p.mentioned = append(p.mentioned, name)
+	}
+}
+
+func (p *sparqlParser) query() (*sparqlQuery, error) {
+	for {
+		switch {
+		case p.isKeyword("PREFIX"):
+			p.next()
+			name := p.next()
+			if name.kind != tokPName || !strings.HasSuffix(name.value, ":") {
+				return nil, sparqlErrorf(name.pos, "expected a prefix name, found %s", name)
+			}
+			iri := p.next()
+			if iri.kind != tokIRI {
+				return nil, sparqlErrorf(iri.pos, "expected an IRI, found %s", iri)
+			}
+			p.prefixes[strings.TrimSuffix(name.value, ":")] = iri.value
+			continue
+		case p.isKeyword("BASE"):
+			return nil, sparqlErrorf(p.peek().pos, "BASE is not supported")
+		}
+		break
+	}
+
+	if !p.isKeyword("SELECT") {
+		t := p.peek()
+		return nil, sparqlErrorf(t.pos, "only SELECT queries are supported, found %s", t)
+	}
+	p.next()
+
+	q := &sparqlQuery{limit: -1}
+	if p.isKeyword("DISTINCT") || p.isKeyword("REDUCED") {
+		q.distinct = p.isKeyword("DISTINCT")
+		p.next()
+	}
+	star := false
+	if p.isPunct("*") {
+		p.next()
+		star = true
+	} else {
+		for p.peek().kind == tokVar {
+			q.vars = append(q.vars, p.next().value)
+		}
+		if len(q.vars) == 0 {
+			t := p.peek()
+			return nil, sparqlErrorf(t.pos, "expected variables or *, found %s", t)
+		}
+	}
+
+	if p.isKeyword("WHERE") {
+		p.next()
+	}
+	where, err := p.group()
+	if err != nil {
+		return nil, err
+	}
+	q.where = where
+	if star {
+		q.vars = p.mentioned
+	}
+
+	for {
+		switch {
+		case p.isKeyword("LIMIT"), p.isKeyword("OFFSET"):
+			keyword := strings.ToUpper(p.next().value)
+			t := p.next()
+			n, err := strconv.Atoi(t.value)
+			if t.kind != tokNumber || err != nil || n < 0 {
+				return nil, sparqlErrorf(t.pos, "%s expects a non-negative integer, found %s", keyword, t)
+			}
+			if keyword == "LIMIT" {
+				q.limit = n
+			} else {
+				q.offset = n
+			}
+			continue
+		case p.isKeyword("ORDER"), p.isKeyword("GROUP"), p.isKeyword("HAVING"):
+			return nil, sparqlErrorf(p.peek().pos, "%s is not supported", strings.ToUpper(p.peek().value))
+		}
+		break
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, sparqlErrorf(t.pos, "unexpected %s", t)
+	}
+	return q, nil
+}
+
+func (p *sparqlParser) group() (*groupPattern, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	g := &groupPattern{}
+	for {
+		switch {
+		case p.isPunct("}"):
+			p.next()
+			return g, nil
+		case p.isPunct("."):
+			p.next()
+		case p.isKeyword("OPTIONAL"):
+			p.next()
+			optional, err := p.group()
+			if err != nil {
+				return nil, err
+			}
+			g.elements = append(g.elements, groupElement{optional: optional})
+		case p.isKeyword("FILTER"):
+			p.next()
+			filter, err := p.constraint()
+			if err != nil {
+				return nil, err
+			}
+			g.filters = append(g.filters, filter)
+		case p.isPunct("{"):
+			branch, err := p.group()
+			if err != nil {
+				return nil, err
+			}
+			union := []*groupPattern{branch}
+			for p.isKeyword("UNION") {
+				p.next()
+				if branch, err = p.group(); err != nil {
+					return nil, err
+				}
+				union = append(union, branch)
+			}
+			g.elements = append(g.elements, groupElement{union: union})
+		case p.peek().kind == tokEOF:
+			return nil, sparqlErrorf(p.peek().pos, "unterminated group")
+		case p.peek().kind == tokKeyword && !p.isKeyword("a") && !p.isKeyword("true") && !p.isKeyword("false"):
+			return nil, sparqlErrorf(p.peek().pos, "%s is not supported", strings.ToUpper(p.peek().value))
+		default:
+			triples, err := p.triplesSameSubject()
+			if err != nil {
+				return nil, err
+			}
+			if n := len(g.elements); n > 0 && g.elements[n-1].triples != nil {
+				g.elements[n-1].triples = append(g.elements[n-1].triples, triples...)
+			} else {
+				g.elements = append(g.elements, groupElement{triples: triples})
+			}
+		}
+	}
+}
+
+func (p *sparqlParser) triplesSameSubject() ([]triplePattern, error) {
+	subject, err := p.varOrTerm()
+	if err != nil {
+		return nil, err
+	}
+	var triples []triplePattern
+	for {
+		verb, err := p.verb()
+		if err != nil {
+			return nil, err
+		}
+		for {
+			object, err := p.varOrTerm()
+			if err != nil {
+				return nil, err
+			}
+			triples = append(triples, triplePattern{s: subject, p: verb, o: object})
+			if !p.isPunct(",") {
+				break
+			}
+			p.next()
+		}
+		if !p.isPunct(";") {
+			return triples, nil
+		}
+		for p.isPunct(";") {
+			p.next()
+		}
+		if p.isPunct(".") || p.isPunct("}") {
+			return triples, nil
+		}
+	}
+}
+
+func (p *sparqlParser) verb() (patternTerm, error) {
+	if p.isKeyword("a") {
+		p.next()
+		return patternTerm{term: IRI(rdfType)}, nil
+	}
+	verb, err := p.varOrTerm()
+	if err != nil {
+		return patternTerm{}, err
+	}
+	if verb.variable == "" && verb.term.Kind != IRITerm {
+		return patternTerm{}, sparqlErrorf(p.tokens[p.pos-1].pos, "predicate must be an IRI or variable")
+	}
+	return verb, nil
+}
+
+func (p *sparqlParser) varOrTerm() (patternTerm, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokVar:
+		p.next()
+		p.mention(t.value)
+		return patternTerm{variable: t.value}, nil
+	case tokBlank:
+		// Blank nodes in patterns behave as variables that are never projected.
+		p.next()
+		return patternTerm{variable: "_:" + t.value}, nil
+	}
+	if t.kind == tokPunct && t.value == "[" {
+		return patternTerm{}, sparqlErrorf(t.pos, "blank node property lists are not supported")
+	}
+	term, err := p.term()
+	if err != nil {
+		return patternTerm{}, err
+	}
+	return patternTerm{term: term}, nil
+}
+
+// term reads an IRI, prefixed name or literal.
+func (p *sparqlParser) term() (Term, error) {
+	t := p.next()
+	switch t.kind {
+	case tokIRI:
+		if !validIRI(t.value) {
+			return Term{}, sparqlErrorf(t.pos, "invalid IRI %s", t)
+		}
+		return IRI(t.value), nil
+	case tokPName:
+		return p.expandPName(t)
+	case tokString:
+		switch next := p.peek(); {
+		case next.kind == tokLangTag:
+			p.next()
+			return LangLiteral(t.value, next.value), nil
+		case next.kind == tokPunct && next.value == "^^":
+			p.next()
+			datatype, err := p.term()
+			if err != nil {
+				return Term{}, err
+			}
+			if datatype.Kind != IRITerm {
+				return Term{}, sparqlErrorf(next.pos, "datatype must be an IRI")
+			}
+			return Literal(t.value, datatype.Value), nil
+		}
+		return Literal(t.value, xsdString), nil
+	case tokNumber:
+		return sparqlNumber(t.value), nil
+	case tokPunct:
+		if (t.value == "-" || t.value == "+") && p.peek().kind == tokNumber {
+			number := p.next().value
+			if t.value == "-" {
+				number = "-" + number
+			}
+			return sparqlNumber(number), nil
+		}
+	case tokKeyword:
+		switch strings.ToLower(t.value) {
+		case "true", "false":
+			return Literal(strings.ToLower(t.value), xsdBoolean), nil
+		}
+	}
+	return Term{}, sparqlErrorf(t.pos, "expected a term, found %s", t)
+}
+
+func (p *sparqlParser) expandPName(t sparqlToken) (Term, error) {
+	prefix, local, _ := strings.Cut(t.value, ":")
+	ns, ok := p.prefixes[prefix]
+	if !ok {
+		return Term{}, sparqlErrorf(t.pos, "undeclared prefix %q", prefix)
+	}
+	return IRI(ns + local), nil
+}
+
+func sparqlNumber(lexical string) Term {
+	switch {
+	case strings.ContainsAny(lexical, "eE"):
+		return Literal(lexical, xsdDouble)
+	case strings.Contains(lexical, "."):
+		return Literal(lexical, xsdDecimal)
+	default:
+		return Literal(strings.TrimPrefix(lexical, "+"), xsdInteger)
+	}
+}
+
+// constraint reads the argument of FILTER: a bracketed expression or a call.
+func (p *sparqlParser) constraint() (sparqlExpr, error) {
+	if p.isPunct("(") {
+		p.next()
+		expr, err := p.orExpr()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expectPunct(")")
+	}
+	if p.peek().kind == tokKeyword {
+		return p.call()
+	}
+	t := p.peek()
+	return nil, sparqlErrorf(t.pos, "expected a bracketed expression or function call, found %s", t)
+}
+
+func (p *sparqlParser) orExpr() (sparqlExpr, error) {
+	left, err := p.andExpr()
+	for err == nil && p.isPunct("||") {
+		p.next()
+		var right sparqlExpr
+		if right, err = p.andExpr(); err == nil {
+			left = binaryExpr{op: "||", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *sparqlParser) andExpr() (sparqlExpr, error) {
+	left, err := p.relationalExpr()
+	for err == nil && p.isPunct("&&") {
+		p.next()
+		var right sparqlExpr
+		if right, err = p.relationalExpr(); err == nil {
+			left = binaryExpr{op: "&&", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *sparqlParser) relationalExpr() (sparqlExpr, error) {
+	left, err := p.additiveExpr()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"=", "!=", "<", ">", "<=", ">="} {
+		if p.isPunct(op) {
+			p.next()
+			right, err := p.additiveExpr()
+			if err != nil {
+				return nil, err
+			}
+			return binaryExpr{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *sparqlParser) additiveExpr() (sparqlExpr, error) {
+	left, err := p.multiplicativeExpr()
+	for err == nil && (p.isPunct("+") || p.isPunct("-")) {
+		op := p.next().value
+		var right sparqlExpr
+		if right, err = p.multiplicativeExpr(); err == nil {
+			left = binaryExpr{op: op, left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *sparqlParser) multiplicativeExpr() (sparqlExpr, error) {
+	left, err := p.unaryExpr()
+	for err == nil && (p.isPunct("*") || p.isPunct("/")) {
+		op := p.next().value
+		var right sparqlExpr
+		if right, err = p.unaryExpr(); err == nil {
+			left = binaryExpr{op: op, left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *sparqlParser) unaryExpr() (sparqlExpr, error) {
+	if p.isPunct("!") || p.isPunct("-") || p.isPunct("+") {
+		op := p.next().value
+		operand, err := p.unaryExpr()
+		if err != nil {
+			return nil, err
+		}
+		return unaryExpr{op: op, operand: operand}, nil
+	}
+	return p.primaryExpr()
+}
+
+func (p *sparqlParser) primaryExpr() (sparqlExpr, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokPunct && t.value == "(":
+		p.next()
+		expr, err := p.orExpr()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expectPunct(")")
+	case t.kind == tokVar:
+		p.next()
+		return varExpr(t.value), nil
+	case t.kind == tokKeyword && !strings.EqualFold(t.value, "true") && !strings.EqualFold(t.value, "false"):
+		return p.call()
+	}
+	term, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	if p.isPunct("(") {
+		return nil, sparqlErrorf(t.pos, "extension functions are not supported")
+	}
+	return constExpr{term: term}, nil
+}
+
+// builtinArity lists the supported built-in functions with their minimum and
+// maximum number of arguments.
+var builtinArity = map[string][2]int{
+	"BOUND":     {1, 1},
+	"REGEX":     {2, 3},
+	"STR":       {1, 1},
+	"LANG":      {1, 1},
+	"DATATYPE":  {1, 1},
+	"LCASE":     {1, 1},
+	"UCASE":     {1, 1},
+	"CONTAINS":  {2, 2},
+	"STRSTARTS": {2, 2},
+	"STRENDS":   {2, 2},
+	"ISIRI":     {1, 1},
+	"ISURI":     {1, 1},
+	"ISBLANK":   {1, 1},
+	"ISLITERAL": {1, 1},
+	"SAMETERM":  {2, 2},
+}
+
+func (p *sparqlParser) call() (sparqlExpr, error) {
+	t := p.next()
+	name := strings.ToUpper(t.value)
+	arity, ok := builtinArity[name]
+	if !ok {
+		return nil, sparqlErrorf(t.pos, "unsupported function %s", name)
+	}
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	if name == "BOUND" {
+		v := p.next()
+		if v.kind != tokVar {
+			return nil, sparqlErrorf(v.pos, "BOUND expects a variable, found %s", v)
+		}
+		return boundExpr(v.value), p.expectPunct(")")
+	}
+
+	var args []sparqlExpr
+	for !p.isPunct(")") {
+		if len(args) > 0 {
+			if err := p.expectPunct(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.orExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.next()
+	if len(args) < arity[0] || len(args) > arity[1] {
+		return nil, sparqlErrorf(t.pos, "%s takes %d to %d arguments, got %d", name, arity[0], arity[1], len(args))
+	}
+
+	call := callExpr{name: name, args: args}
+	if name == "REGEX" {
+		// Constant patterns are compiled once, which also reports mistakes early.
+		pattern, okPattern := args[1].(constExpr)
+		flags := constExpr{term: Literal("", xsdString)}
+		okFlags := true
+		if len(args) == 3 {
+			flags, okFlags = args[2].(constExpr)
+		}
+		if okPattern && okFlags {
+			re, err := compileRegex(pattern.term, flags.term)
+			if err != nil {
+				return nil, sparqlErrorf(t.pos, "%v", err)
+			}
+			call.regex = re
+		}
+	}
+	return call, nil
+}
+
+// --- expressions ---
+
+var errExpression = errors.New("expression error")
+
+type sparqlExpr interface {
+	eval(b Binding) (Term, error)
+}
+
+type varExpr string
+
+func (v varExpr) eval(b Binding) (Term, error) {
+	term, ok := b[string(v)]
+	if !ok {
+		return Term{}, errExpression
+	}
+	return term, nil
+}
+
+type constExpr struct{ term Term }
+
+func (c constExpr) eval(Binding) (Term, error) { return c.term, nil }
+
+type boundExpr string
+
+func (v boundExpr) eval(b Binding) (Term, error) {
+	_, ok := b[string(v)]
+	return boolean(ok), nil
+}
+
+type unaryExpr struct {
+	op      string
+	operand sparqlExpr
+}
+
+func (u unaryExpr) eval(b Binding) (Term, error) {
+	value, err := u.operand.eval(b)
+	if err != nil {
+		return Term{}, err
+	}
+	if u.op == "!" {
+		truth, err := effectiveBoolean(value)
+		return boolean(!truth), err
+	}
+	n, integral, ok := numeric(value)
+	if !ok {
+		return Term{}, errExpression
+	}
+	if u.op == "-" {
+		n = -n
+	}
+	return number(n, integral), nil
+}
+
+type binaryExpr struct {
+	op          string
+	left, right sparqlExpr
+}
+
+func (e binaryExpr) eval(b Binding) (Term, error) {
+	switch e.op {
+	case "||", "&&":
+		// Errors only propagate when the other operand cannot decide the result.
+		l, lerr := evalBoolean(e.left, b)
+		r, rerr := evalBoolean(e.right, b)
+		decisive := e.op == "||"
+		if (lerr == nil && l == decisive) || (rerr == nil && r == decisive) {
+			return boolean(decisive), nil
+		}
+		if lerr != nil || rerr != nil {
+			return Term{}, errExpression
+		}
+		return boolean(!decisive), nil
+	}
+
+	l, err := e.left.eval(b)
+	if err != nil {
+		return Term{}, err
+	}
+	r, err := e.right.eval(b)
+	if err != nil {
+		return Term{}, err
+	}
+
+	switch e.op {
+	case "=", "!=":
+		equal := l == r
+		if c, err := compareTerms(l, r); err == nil {
+			equal = c == 0
+		}
+		return boolean(equal == (e.op == "=")), nil
+	case "<", ">", "<=", ">=":
+		c, err := compareTerms(l, r)
+		if err != nil {
+			return Term{}, err
+		}
+		switch e.op {
+		case "<":
+			return boolean(c < 0), nil
+		case ">":
+			return boolean(c > 0), nil
+		case "<=":
+			return boolean(c <= 0), nil
+		default:
+			return boolean(c >= 0), nil
+		}
+	}
+
+	ln, lint, lok := numeric(l)
+	rn, rint, rok := numeric(r)
+	if !lok || !rok {
+		return Term{}, errExpression
+	}
+	switch e.op {
+	case "+":
+		return number(ln+rn, lint && rint), nil
+	case "-":
+		return number(ln-rn, lint && rint), nil
+	case "*":
+		return number(ln*rn, lint && rint), nil
+	default:
+		if rn == 0 {
+			return Term{}, errExpression
+		}
+		return number(ln/rn, false), nil
+	}
+}
+
+type callExpr struct {
+	name  string
+	args  []sparqlExpr
+	regex *regexp.Regexp
+}
+
+func (c callExpr) eval(b Binding) (Term, error) {
+	args := make([]Term, len(c.args))
+	for i, arg := range c.args {
+		value, err := arg.eval(b)
+		if err != nil {
+			return Term{}, err
+		}
+		args[i] = value
+	}
+
+	switch c.name {
+	case "ISIRI", "ISURI":
+		return boolean(args[0].Kind == IRITerm), nil
+	case "ISBLANK":
+		return boolean(args[0].Kind == BlankNodeTerm), nil
+	case "ISLITERAL":
+		return boolean(args[0].Kind == LiteralTerm), nil
+	case "SAMETERM":
+		return boolean(args[0] == args[1]), nil
+	case "STR":
+		if args[0].Kind == BlankNodeTerm {
+			return Term{}, errExpression
+		}
+		return Literal(args[0].Value, xsdString), nil
+	case "LANG":
+		if args[0].Kind != LiteralTerm {
+			return Term{}, errExpression
+		}
+		return Literal(args[0].Language, xsdString), nil
+	case "DATATYPE":
+		if args[0].Kind != LiteralTerm {
+			return Term{}, errExpression
+		}
+		return IRI(args[0].Datatype), nil
+	}
+
+	for _, arg := range args {
+		if arg.Kind != LiteralTerm {
+			return Term{}, errExpression
+		}
+	}
+	switch c.name {
+	case "LCASE", "UCASE":
+		out := args[0]
+		if c.name == "LCASE" {
+			out.Value = strings.ToLower(out.Value)
+		} else {
+			out.Value = strings.ToUpper(out.Value)
+		}
+		return out, nil
+	case "CONTAINS":
+		return boolean(strings.Contains(args[0].Value, args[1].Value)), nil
+	case "STRSTARTS":
+		return boolean(strings.HasPrefix(args[0].Value, args[1].Value)), nil
+	case "STRENDS":
+		return boolean(strings.HasSuffix(args[0].Value, args[1].Value)), nil
+	default: // REGEX
+		re := c.regex
+		if re == nil {
+			flags := Literal("", xsdString)
+			if len(args) == 3 {
+				flags = args[2]
+			}
+			var err error
+			if re, err = compileRegex(args[1], flags); err != nil {
+				return Term{}, errExpression
+			}
+		}
+		return boolean(re.MatchString(args[0].Value)), nil
+	}
+}
+
+// compileRegex translates XPath regular expression flags to Go syntax.
+func compileRegex(pattern, flags Term) (*regexp.Regexp, error) {
+	if pattern.Kind != LiteralTerm || flags.Kind != LiteralTerm {
+		return nil, errors.New("REGEX pattern and flags must be literals")
+	}
+	var goFlags string
+	for _, f := range flags.Value {
+		switch f {
+		case 'i', 's', 'm':
+			goFlags += string(f)
+		case 'x':
+			return nil, errors.New("REGEX flag x is not supported")
+		default:
+			return nil, fmt.Errorf("invalid REGEX flag %q", f)
+		}
+	}
+	expr := pattern.Value
+	if goFlags != "" {
+		expr = "(?" + goFlags + ")" + expr
+	}
+	return regexp.Compile(expr)
+}
+
+func evalBoolean(expr sparqlExpr, b Binding) (bool, error) {
+	value, err := expr.eval(b)
+	if err != nil {
+		return false, err
+	}
+	return effectiveBoolean(value)
+}
+
+// effectiveBoolean computes the SPARQL effective boolean value of a term.
+func effectiveBoolean(t Term) (bool, error) {
+	if t.Kind != LiteralTerm {
+		return false, errExpression
+	}
+	if t.Datatype == xsdBoolean {
+		return t.Value == "true" || t.Value == "1", nil
+	}
+	if n, _, ok := numeric(t); ok {
+		return n != 0 && !math.IsNaN(n), nil
+	}
+	if t.Datatype == xsdString || t.Datatype == rdfLangString {
+		return t.Value != "", nil
+	}
+	return false, errExpression
+}
+
+func boolean(v bool) Term {
+	return Literal(strconv.FormatBool(v), xsdBoolean)
+}
+
+func number(v float64, integral bool) Term {
+	if integral && v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+		return Literal(strconv.FormatInt(int64(v), 10), xsdInteger)
+	}
+	return Literal(strconv.FormatFloat(v, 'g', -1, 64), xsdDouble)
+}
+
+// integerTypes are the XSD datatypes derived from xsd:integer.
+var integerTypes = map[string]bool{
+	xsdInteger:                   true,
+	XSDNS + "int":                true,
+	XSDNS + "long":               true,
+	XSDNS + "short":              true,
+	XSDNS + "byte":               true,
+	XSDNS + "nonNegativeInteger": true,
+	XSDNS + "positiveInteger":    true,
+	XSDNS + "nonPositiveInteger": true,
+	XSDNS + "negativeInteger":    true,
+	XSDNS + "unsignedInt":        true,
+	XSDNS + "unsignedLong":       true,
+}
+
+// numeric returns the value of a numeric literal and whether it is an integer.
+func numeric(t Term) (float64, bool, bool) {
+	if t.Kind != LiteralTerm {
+		return 0, false, false
+	}
+	integral := integerTypes[t.Datatype]
+	if !integral && t.Datatype != xsdDecimal && t.Datatype != xsdDouble && t.Datatype != XSDNS+"float" {
+		return 0, false, false
+	}
+	n, err := strconv.ParseFloat(t.Value, 64)
+	if err != nil {
+		return 0, false, false
+	}
+	return n, integral, true
+}
+
+// compareTerms orders two literals of comparable types: numbers, dateTimes,
+// booleans, and strings with the same language tag.
+func compareTerms(l, r Term) (int, error) {
+	if l.Kind != LiteralTerm || r.Kind != LiteralTerm {
+		return 0, errExpression
+	}
+	if ln, _, ok := numeric(l); ok {
+		rn, _, ok := numeric(r)
+		if !ok {
+			return 0, errExpression
+		}
+		switch {
+		case ln < rn:
+			return -1, nil
+		case ln > rn:
+			return 1, nil
+		}
+		return 0, nil
+	}
+	switch {
+	case l.Datatype == xsdDateTime && r.Datatype == xsdDateTime:
+		lt, lerr := time.Parse(time.RFC3339Nano, l.Value)
+		rt, rerr := time.Parse(time.RFC3339Nano, r.Value)
+		if lerr != nil || rerr != nil {
+			return 0, errExpression
+		}
+		return lt.Compare(rt), nil
+	case l.Datatype == xsdBoolean && r.Datatype == xsdBoolean:
+		lb, rb := l.Value == "true" || l.Value == "1", r.Value == "true" || r.Value == "1"
+		switch {
+		case lb == rb:
+			return 0, nil
+		case rb:
+			return -1, nil
+		}
+		return 1, nil
+	case l.Datatype == r.Datatype && l.Language == r.Language &&
+		(l.Datatype == xsdString || l.Datatype == rdfLangString):
+		return strings.Compare(l.Value, r.Value), nil
+	}
+	return 0, errExpression
+}
+
+// --- evaluation ---
+
+// queryCheckInterval is how many matched triples pass between context checks.
+const queryCheckInterval = 1024
+
+type queryEvaluator struct {
+	ctx   context.Context
+	graph *GraphIndex
+	steps int
+	err   error
+}
+
+func (q *sparqlQuery) evaluate(ctx context.Context, graph *GraphIndex) (*QueryResult, error) {
+	limit := q.limit
+	if limit < 0 || limit > MaxQueryRows {
+		limit = MaxQueryRows
+	}
+	result := &QueryResult{Vars: q.vars, Solutions: []Binding{}}
+	if limit == 0 {
+		return result, nil
+	}
+
+	e := &queryEvaluator{ctx: ctx, graph: graph}
+	seen := make(map[string]bool)
+	skipped := 0
+	e.group(q.where, Binding{}, func(b Binding) bool {
+		projected := make(Binding, len(q.vars))
+		var key strings.Builder
+		for _, name := range q.vars {
+			if term, ok := b[name]; ok {
+				projected[name] = term
+				key.WriteString(formatTerm(term))
+			}
+			key.WriteByte(0)
+		}
+		if q.distinct {
+			if seen[key.String()] {
+				return true
+			}
+			seen[key.String()] = true
+		}
+		if skipped < q.offset {
+			skipped++
+			return true
+		}
+		result.Solutions = append(result.Solutions, projected)
+		return len(result.Solutions) < limit
+	})
+	if e.err != nil {
+		return nil, e.err
+	}
+	return result, nil
+}
+
+// step counts a matched triple and reports whether evaluation may go on.
+func (e *queryEvaluator) step() bool {
+	if e.err != nil {
+		return false
+	}
+	e.steps++
+	if e.steps%queryCheckInterval == 0 {
+		e.err = e.ctx.Err()
+	}
+	return e.err == nil
+}
+
+// group streams the solutions of a group extending b; yield returns false to
+// stop, and so does group.
+func (e *queryEvaluator) group(g *groupPattern, b Binding, yield func(Binding) bool) bool {
+	return e.elements(g, 0, b, yield)
+}
+
+func (e *queryEvaluator) elements(g *groupPattern, i int, b Binding, yield func(Binding) bool) bool {
+	if i == len(g.elements) {
+		for _, filter := range g.filters {
+			if ok, err := evalBoolean(filter, b); err != nil || !ok {
+				return true
+			}
+		}
+		return yield(b)
+	}
+
+	next := func(extended Binding) bool { return e.elements(g, i+1, extended, yield) }
+	element := g.elements[i]
+	switch {
+	case element.optional != nil:
+		matched := false
+		if !e.group(element.optional, b, func(extended Binding) bool {
+			matched = true
+			return next(extended)
+		}) {
+			return false
+		}
+		return matched || next(b)
+	case element.union != nil:
+		for _, branch := range element.union {
+			if !e.group(branch, b, next) {
+				return false
+			}
+		}
+		return true
+	default:
+		return e.bgp(element.triples, b, next)
+	}
+}
+
+// bgp joins triple patterns, always matching the one with the most bound
+// positions next.
+func (e *queryEvaluator) bgp(patterns []triplePattern, b Binding, yield func(Binding) bool) bool {
+	if len(patterns) == 0 {
+		return yield(b)
+	}
+	best, bestScore := 0, -1
+	for i, tp := range patterns {
+		score := 0
+		if resolve(tp.s, b) != anyTerm {
+			score += 4
+		}
+		if resolve(tp.o, b) != anyTerm {
+			score += 2
+		}
+		if resolve(tp.p, b) != anyTerm {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	tp := patterns[best]
+	rest := slices.Concat(patterns[:best], patterns[best+1:])
+
+	for t := range e.graph.match(resolve(tp.s, b), resolve(tp.p, b), resolve(tp.o, b)) {
+		if !e.step() {
+			return false
+		}
+		extended, ok := unify(tp, t, b)
+		if !ok {
+			continue
+		}
+		if !e.bgp(rest, extended, yield) {
+			return false
+		}
+	}
+	return e.err == nil
+}
+
+func resolve(pt patternTerm, b Binding) Term {
+	if pt.variable == "" {
+		return pt.term
+	}
+	return b[pt.variable]
+}
+
+// unify binds the pattern's unbound variables to the triple, failing when a
+// variable repeated in the pattern would take two values.
+func unify(tp triplePattern, t Triple, b Binding) (Binding, bool) {
+	var extended Binding
+	for _, pair := range [3]struct {
+		pt    patternTerm
+		value Term
+	}{{tp.s, t.Subject}, {tp.p, t.Predicate}, {tp.o, t.Object}} {
+		if pair.pt.variable == "" {
+			continue
+		}
+		current := b
+		if extended != nil {
+			current = extended
+		}
+		if bound, ok := current[pair.pt.variable]; ok {
+			if bound != pair.value {
+				return nil, false
+			}
+			continue
+		}
+		if extended == nil {
+			extended = make(Binding, len(b)+3)
+			for k, v := range b {
+				extended[k] = v
+			}
+		}
+		extended[pair.pt.variable] = pair.value
+	}
+	if extended == nil {
+		return b, true
+	}
+	return extended, true
+}
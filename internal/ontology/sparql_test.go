@@ -0,0 +1,121 @@
+package ontology
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newExampleIndex(t *testing.T) *GraphIndex {
+	t.Helper()
+	index, err := NewGraphIndexWithSchema(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range []string{
+		`{"@id": "https://example.org/ada", "https://example.org/name": "Ada", "https://example.org/age": 36,
+		  "https://example.org/knows": {"@id": "https://example.org/bob"}}`,
+		`{"@id": "https://example.org/bob", "https://example.org/name": {"@value": "Bob", "@language": "en"},
+		  "https://example.org/age": 19}`,
+		`{"@id": "https://example.org/cy", "https://example.org/name": "Cy", "https://example.org/active": false}`,
+	} {
+		if _, err := index.Apply([]byte(doc), time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return index
+}
+
+func TestQuery_FiltersAndModifiers(t *testing.T) {
+	index := newExampleIndex(t)
+	const prefix = "PREFIX ex: <https://example.org/>\n"
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{`SELECT ?n WHERE { ?p ex:name ?n }`, []string{"Ada", "Bob", "Cy"}},
+		{`SELECT ?n WHERE { ?p ex:name ?n ; ex:age ?age . FILTER(?age >= 21) }`, []string{"Ada"}},
+		{`SELECT ?n WHERE { ?p ex:name ?n ; ex:age ?age . FILTER(?age * 2 > 40 && ?age < 40) }`, []string{"Ada"}},
+		{`SELECT ?n WHERE { ?p ex:name ?n . FILTER(regex(?n, "^b", "i")) }`, []string{"Bob"}},
+		{`SELECT ?n WHERE { ?p ex:name ?n . FILTER(lang(?n) = "en") }`, []string{"Bob"}},
+		{`SELECT ?n WHERE { ?p ex:name ?n . FILTER(!contains(?n, "y") || ?n = "Cy") }`, []string{"Ada", "Bob", "Cy"}},
+		{`SELECT ?n WHERE { ?p ex:name ?n . OPTIONAL { ?p ex:age ?age } FILTER(!bound(?age)) }`, []string{"Cy"}},
+		{`SELECT ?n WHERE { ?p ex:name ?n ; ex:active false }`, []string{"Cy"}},
+		{`SELECT ?n WHERE { { ?p ex:knows ?q } UNION { ?q ex:active ?a } ?q ex:name ?n }`, []string{"Bob", "Cy"}},
+		{`SELECT ?n WHERE { _:someone ex:knows ?q . ?q ex:name ?n }`, []string{"Bob"}},
+		{`SELECT ?n WHERE { ?p ex:name ?n . FILTER(isIRI(?p) && strstarts(str(?p), "https://example.org/a")) }`, []string{"Ada"}},
+	} {
+		got := column(query(t, index, prefix+tc.query), "n")
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s\n= %v, want %v", tc.query, got, tc.want)
+		}
+	}
+
+	optional := query(t, index, prefix+`SELECT * WHERE { ?p ex:name ?n OPTIONAL { ?p ex:age ?age } }`)
+	if !slices.Equal(optional.Vars, []string{"p", "n", "age"}) || len(optional.Solutions) != 3 {
+		t.Errorf("SELECT * = %v with %d solutions", optional.Vars, len(optional.Solutions))
+	}
+	limited := query(t, index, prefix+`SELECT ?p WHERE { ?p ex:name ?n } LIMIT 2 OFFSET 1`)
+	if len(limited.Solutions) != 2 {
+		t.Errorf("LIMIT 2 OFFSET 1 returned %d solutions", len(limited.Solutions))
+	}
+	distinct := query(t, index, prefix+`SELECT DISTINCT ?x WHERE { ?p ex:name ?n . OPTIONAL { ?p ex:missing ?x } }`)
+	if len(distinct.Solutions) != 1 {
+		t.Errorf("DISTINCT over an unbound variable returned %d solutions", len(distinct.Solutions))
+	}
+}
+
+func TestQuery_RejectsUnsupportedSyntax(t *testing.T) {
+	index := newExampleIndex(t)
+	for _, q := range []string{
+		`INSERT DATA { <https://example.org/a> <https://example.org/b> "c" }`,
+		`DELETE WHERE { ?s ?p ?o }`,
+		`CONSTRUCT { ?s ?p ?o } WHERE { ?s ?p ?o }`,
+		`SELECT ?s WHERE { ?s ?p ?o } ORDER BY ?s`,
+		`SELECT ?s WHERE { ?s unknown:p ?o }`,
+		`SELECT ?s WHERE { ?s ?p ?o . FILTER(ucase(?o) = "A" }`,
+		`SELECT ?s WHERE { ?s ?p ?o . FILTER(regex(?o, "(")) }`,
+		`SELECT ?s WHERE { ?s ?p ?o . MINUS { ?s ?p ?o } }`,
+		`SELECT WHERE { ?s ?p ?o }`,
+	} {
+		_, err := index.Query(context.Background(), q)
+		var syntax *QuerySyntaxError
+		if !errors.As(err, &syntax) {
+			t.Errorf("%s\n= %v, want a QuerySyntaxError", q, err)
+		}
+	}
+}
+
+func TestQuery_StopsWhenCancelled(t *testing.T) {
+	index := newTestIndex(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := index.Query(ctx, `SELECT * WHERE { ?a ?b ?c . ?d ?e ?f }`)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Query = %v, want context.Canceled", err)
+	}
+}
+
+func TestQueryResult_MarshalJSON(t *testing.T) {
+	result := &QueryResult{
+		Vars: []string{"s", "name", "missing"},
+		Solutions: []Binding{{
+			"s":    IRI("https://example.org/ada"),
+			"name": LangLiteral("Ada", "en"),
+		}},
+	}
+	body, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `{"head":{"vars":["s","name","missing"]},"results":{"bindings":[` +
+		`{"name":{"type":"literal","value":"Ada","xml:lang":"en"},"s":{"type":"uri","value":"https://example.org/ada"}}]}}`
+	if strings.TrimSpace(string(body)) != want {
+		t.Errorf("MarshalJSON =\n%s\nwant\n%s", body, want)
+	}
+}
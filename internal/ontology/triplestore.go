@@ -0,0 +1,212 @@
+package ontology
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultGraphBase prefixes the named graph that holds each entity type.
+	DefaultGraphBase = "https://ontology.skillsphere.dev/graph/"
+
+	defaultTripleStoreTimeout = 30 * time.Second
+	// maxErrorBodyLength bounds how much of a failed response is kept for logs.
+	maxErrorBodyLength = 512
+)
+
+// SPARQLStoreConfig configures a SPARQLStoreClient.
+type SPARQLStoreConfig struct {
+	// UpdateEndpoint accepts SPARQL 1.1 Update requests,
+	// e.g. http://localhost:3030/skillsphere/update.
+	UpdateEndpoint string
+	// GraphBase is joined with the local name of an event's @type to name its graph.
+	GraphBase string
+
+	// Username and Password enable basic auth; BearerToken takes precedence.
+	Username    string
+	Password    string
+	BearerToken string
+
+	Timeout time.Duration
+}
+
+// SPARQLStoreClient writes JSON-LD documents to an RDF store. Each resource is
+// upserted in one SPARQL Update request: triples previously stored for its @id
+// are removed and the new document's triples added atomically, so re-delivered
+// or updated events never accumulate and readers never see the resource
+// missing.
+type SPARQLStoreClient struct {
+	cfg    SPARQLStoreConfig
+	client *http.Client
+}
+
+// NewSPARQLStoreClient validates cfg and builds a client.
+func NewSPARQLStoreClient(cfg SPARQLStoreConfig) (*SPARQLStoreClient, error) {
+	if cfg.UpdateEndpoint == "" {
+		return nil, errors.New("triple store: update endpoint is required")
+	}
+	if _, err := url.ParseRequestURI(cfg.UpdateEndpoint); err != nil {
+		return nil, fmt.Errorf("triple store: update endpoint: %w", err)
+	}
+	if cfg.GraphBase == "" {
+		cfg.GraphBase = DefaultGraphBase
+	}
+	if !validIRI(cfg.GraphBase) {
+		return nil, fmt.Errorf("triple store: invalid graph base %q", cfg.GraphBase)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTripleStoreTimeout
+	}
+
+	return &SPARQLStoreClient{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// StoreStatusError reports a non-2xx response from the triple store.
+type StoreStatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *StoreStatusError) Error() string {
+	return fmt.Sprintf("triple store %s %s: %d %s: %s",
+		e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// Insert implements TripleStoreClient by upserting the document into the
+// named graph for its type. The document is expanded in-process with ToRDF.
+func (c *SPARQLStoreClient) Insert(ctx context.Context, payload []byte) error {
+	var doc struct {
+		ID   string          `json:"@id"`
+		Type json.RawMessage `json:"@type"`
+	}
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return fmt.Errorf("decode JSON-LD document: %w", err)
+	}
+
+	triples, err := ToRDF(payload)
+	if err != nil {
+		return err
+	}
+
+	graph := c.GraphFor(firstType(doc.Type))
+	var update strings.Builder
+	if doc.ID != "" {
+		subject := ExpandIRI(doc.ID)
+		if !validIRI(subject) {
+			return fmt.Errorf("triple store: invalid @id %q", doc.ID)
+		}
+		update.WriteString(deleteSubjectUpdate(graph, subject))
+		update.WriteString(" ;\n")
+	}
+	// N-Triples statements are valid SPARQL triples, blank node labels included.
+	fmt.Fprintf(&update, "INSERT DATA { GRAPH <%s> {\n", graph)
+	if err := WriteNTriples(&update, triples); err != nil {
+		return err
+	}
+	update.WriteString("} }")
+	return c.Update(ctx, update.String())
+}
+
+// GraphFor returns the named graph IRI for resources of the given type.
+func (c *SPARQLStoreClient) GraphFor(typ string) string {
+	if typ == "" {
+		return c.cfg.GraphBase + "untyped"
+	}
+	iri := ExpandIRI(typ)
+	local := iri[strings.LastIndexAny(iri, "#/:")+1:]
+	if local == "" || !validIRI(local) {
+		return c.cfg.GraphBase + "untyped"
+	}
+	return c.cfg.GraphBase + local
+}
+
+// Update sends a SPARQL 1.1 Update request.
+func (c *SPARQLStoreClient) Update(ctx context.Context, update string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.UpdateEndpoint, strings.NewReader(update))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/sparql-update")
+	return c.do(req)
+}
+
+// DropGraph deletes the named graph for resources of the given type, together
+// with the activity records stored in it.
+func (c *SPARQLStoreClient) DropGraph(ctx context.Context, typ string) error {
+	return c.Update(ctx, fmt.Sprintf("DROP SILENT GRAPH <%s>", c.GraphFor(typ)))
+}
+
+func (c *SPARQLStoreClient) do(req *http.Request) error {
+	switch {
+	case c.cfg.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+c.cfg.BearerToken)
+	case c.cfg.Username != "":
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength))
+	return &StoreStatusError{
+		Method:     req.Method,
+		URL:        req.URL.Redacted(),
+		StatusCode: resp.StatusCode,
+		Body:       strings.TrimSpace(string(body)),
+	}
+}
+
+// deleteSubjectUpdate removes every triple about subject in graph, including
+// blank nodes hanging directly off it.
+func deleteSubjectUpdate(graph, subject string) string {
+	return fmt.Sprintf(`DELETE { GRAPH <%[1]s> { <%[2]s> ?p ?o . ?o ?bp ?bo } }
+WHERE { GRAPH <%[1]s> { <%[2]s> ?p ?o . OPTIONAL { ?o ?bp ?bo . FILTER(isBlank(?o)) } } }`, graph, subject)
+}
+
+// firstType returns the first @type of a document, which may be a string or an array.
+func firstType(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return single
+	}
+	var many []string
+	if json.Unmarshal(raw, &many) == nil && len(many) > 0 {
+		return many[0]
+	}
+	return ""
+}
+
+// validIRI rejects characters that cannot appear inside a SPARQL IRIREF.
+func validIRI(iri string) bool {
+	if iri == "" {
+		return false
+	}
+	for _, r := range iri {
+		if r <= 0x20 || strings.ContainsRune(`<>"{}|^`+"`"+`\`, r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+package ontology
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeGraphStore is an httptest stand-in for a SPARQL store. It keeps the
+// N-Triples statements per named graph and subject, and understands the
+// subject DELETE and INSERT DATA issued by SPARQLStoreClient.
+type fakeGraphStore struct {
+	mu      sync.Mutex
+	graphs  map[string]map[string][]string
+	auth    []string
+	updates []string
+	status  int
+}
+
+var (
+	deleteSubjectPattern = regexp.MustCompile(`^DELETE \{ GRAPH <([^>]+)> \{ <([^>]+)> `)
+	insertDataPattern    = regexp.MustCompile(`(?s)INSERT DATA \{ GRAPH <([^>]+)> \{\n(.*)\} \}$`)
+)
+
+func newFakeGraphStore(t *testing.T) (*fakeGraphStore, *httptest.Server) {
+	store := &fakeGraphStore{graphs: make(map[string]map[string][]string)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /update", func(w http.ResponseWriter, r *http.Request) {
+		if !store.check(w, r, "application/sparql-update") {
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		insert := insertDataPattern.FindStringSubmatch(string(body))
+		if insert == nil {
+			http.Error(w, "unsupported update", http.StatusBadRequest)
+			return
+		}
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		store.updates = append(store.updates, string(body))
+		if match := deleteSubjectPattern.FindStringSubmatch(string(body)); match != nil {
+			delete(store.graphs[match[1]], match[2])
+		}
+		graph := insert[1]
+		if store.graphs[graph] == nil {
+			store.graphs[graph] = make(map[string][]string)
+		}
+		for _, line := range strings.Split(strings.TrimSpace(insert[2]), "\n") {
+			subject := strings.Trim(strings.Fields(line)[0], "<>")
+			store.graphs[graph][subject] = append(store.graphs[graph][subject], line)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return store, server
+}
+
+func (s *fakeGraphStore) check(w http.ResponseWriter, r *http.Request, contentType string) bool {
+	s.mu.Lock()
+	s.auth = append(s.auth, r.Header.Get("Authorization"))
+	status := s.status
+	s.mu.Unlock()
+
+	if status != 0 {
+		http.Error(w, "store unavailable", status)
+		return false
+	}
+	if got := r.Header.Get("Content-Type"); got != contentType {
+		http.Error(w, "unexpected content type "+got, http.StatusUnsupportedMediaType)
+		return false
+	}
+	return true
+}
+
+func newTestSPARQLClient(t *testing.T, server *httptest.Server, cfg SPARQLStoreConfig) *SPARQLStoreClient {
+	t.Helper()
+	cfg.UpdateEndpoint = server.URL + "/update"
+	client, err := NewSPARQLStoreClient(cfg)
+	if err != nil {
+		t.Fatalf("NewSPARQLStoreClient: %v", err)
+	}
+	return client
+}
+
+func TestSPARQLStoreClient_UpsertsIntoTypeGraph(t *testing.T) {
+	store, server := newFakeGraphStore(t)
+	client := newTestSPARQLClient(t, server, SPARQLStoreConfig{BearerToken: "token"})
+	ctx := context.Background()
+
+	document := `{"@context":"` + DefaultContext + `","@id":"sk:User/1","@type":"sk:User","schema:name":%q}`
+	for _, name := range []string{"Ada", "Ada Lovelace"} {
+		if err := client.Insert(ctx, []byte(fmt.Sprintf(document, name))); err != nil {
+			t.Fatalf("Insert: %v", err)
+		}
+	}
+
+	if len(store.updates) != 2 {
+		t.Fatalf("sent %d updates, want one per Insert", len(store.updates))
+	}
+	graph := DefaultGraphBase + "User"
+	triples := strings.Join(store.graphs[graph][SkillSphereNS+"User/1"], "\n")
+	if !strings.Contains(triples, `"Ada Lovelace"`) || strings.Contains(triples, `"Ada" `) {
+		t.Fatalf("graph %s holds %s, want only the latest document", graph, triples)
+	}
+	for _, auth := range store.auth {
+		if auth != "Bearer token" {
+			t.Errorf("Authorization = %q", auth)
+		}
+	}
+}
+
+func TestSPARQLStoreClient_GraphPerType(t *testing.T) {
+	_, server := newFakeGraphStore(t)
+	client := newTestSPARQLClient(t, server, SPARQLStoreConfig{GraphBase: "urn:graph:"})
+
+	cases := map[string]string{
+		"sk:Session":                   "urn:graph:Session",
+		"https://schema.org/Person":    "urn:graph:Person",
+		"":                             "urn:graph:untyped",
+		"https://example.com/ns#Skill": "urn:graph:Skill",
+	}
+	for typ, want := range cases {
+		if got := client.GraphFor(typ); got != want {
+			t.Errorf("GraphFor(%q) = %q, want %q", typ, got, want)
+		}
+	}
+}
+
+func TestSPARQLStoreClient_ReportsFailedStatus(t *testing.T) {
+	store, server := newFakeGraphStore(t)
+	store.status = http.StatusInternalServerError
+	client := newTestSPARQLClient(t, server, SPARQLStoreConfig{Username: "worker", Password: "pw"})
+
+	err := client.Insert(context.Background(), []byte(`{"@context":"`+DefaultContext+`","@id":"sk:User/1","@type":"sk:User"}`))
+	var statusErr *StoreStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("Insert error = %v, want a 500 StoreStatusError", err)
+	}
+	if len(store.auth) == 0 || store.auth[0] == "" {
+		t.Error("basic auth header not sent")
+	}
+}
+
+func TestSPARQLStoreClient_RejectsUnsafeIRIs(t *testing.T) {
+	store, server := newFakeGraphStore(t)
+	client := newTestSPARQLClient(t, server, SPARQLStoreConfig{})
+
+	err := client.Insert(context.Background(), []byte(`{"@id":"sk:User/1> } ; DROP ALL ; #","@type":"sk:User"}`))
+	if err == nil {
+		t.Fatal("Insert accepted an @id that would break out of the IRI")
+	}
+	if len(store.updates) != 0 {
+		t.Errorf("update sent for invalid @id: %v", store.updates)
+	}
+}
@@ -0,0 +1,538 @@
+package ontology
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseTurtle parses a Turtle document into triples. It covers the subset the
+// generated ontology files use: @prefix/PREFIX, IRIs, prefixed names, "a",
+// predicate and object lists, blank node property lists, collections, quoted
+// strings (short and long), language tags, datatypes, numbers and booleans.
+// @base and relative IRIs are not supported.
+func ParseTurtle(data []byte) ([]Triple, error) {
+	p := &turtleParser{
+		src:      string(data),
+		line:     1,
+		prefixes: make(map[string]string),
+		labels:   make(map[string]string),
+	}
+	if err := p.document(); err != nil {
+		return nil, err
+	}
+	return p.triples, nil
+}
+
+var iriScheme = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+
+type turtleParser struct {
+	src      string
+	pos      int
+	line     int
+	prefixes map[string]string
+	labels   map[string]string
+	blanks   int
+	triples  []Triple
+}
+
+func (p *turtleParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("turtle: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *turtleParser) document() error {
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return nil
+		}
+		if p.peekKeyword("@prefix") || p.peekKeyword("PREFIX") {
+			if err := p.prefix(); err != nil {
+				return err
+			}
+			continue
+		}
+		if p.peekKeyword("@base") || p.peekKeyword("BASE") {
+			return p.errorf("@base is not supported")
+		}
+		if err := p.statement(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *turtleParser) prefix() error {
+	sparqlStyle := p.src[p.pos] != '@'
+	if sparqlStyle {
+		p.pos += len("PREFIX")
+	} else {
+		p.pos += len("@prefix")
+	}
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != ':' && !isTurtleSpace(p.src[p.pos]) {
+		p.pos++
+	}
+	if !p.consume(':') {
+		return p.errorf("expected ':' after prefix name")
+	}
+	name := p.src[start : p.pos-1]
+	p.skipSpace()
+	iri, err := p.iriRef()
+	if err != nil {
+		return err
+	}
+	p.prefixes[name] = iri
+	p.skipSpace()
+	if !sparqlStyle && !p.consume('.') {
+		return p.errorf("expected '.' after @prefix")
+	}
+	return nil
+}
+
+func (p *turtleParser) statement() error {
+	p.skipSpace()
+	var subject Term
+	if p.peek() == '[' {
+		p.pos++
+		subject = p.newBlank()
+		p.skipSpace()
+		if p.peek() != ']' {
+			if err := p.predicateObjectList(subject); err != nil {
+				return err
+			}
+			p.skipSpace()
+		}
+		if !p.consume(']') {
+			return p.errorf("expected ']'")
+		}
+		p.skipSpace()
+		// "[ ... ] ." is a complete statement on its own.
+		if p.consume('.') {
+			return nil
+		}
+	} else {
+		var err error
+		if subject, err = p.subject(); err != nil {
+			return err
+		}
+	}
+	if err := p.predicateObjectList(subject); err != nil {
+		return err
+	}
+	p.skipSpace()
+	if !p.consume('.') {
+		return p.errorf("expected '.' at end of statement")
+	}
+	return nil
+}
+
+func (p *turtleParser) subject() (Term, error) {
+	switch c := p.peek(); {
+	case c == '<':
+		iri, err := p.iriRef()
+		return IRI(iri), err
+	case c == '(':
+		return p.collection()
+	case strings.HasPrefix(p.src[p.pos:], "_:"):
+		return p.blankLabel(), nil
+	default:
+		iri, err := p.prefixedName()
+		return IRI(iri), err
+	}
+}
+
+func (p *turtleParser) predicateObjectList(subject Term) error {
+	for {
+		p.skipSpace()
+		predicate, err := p.verb()
+		if err != nil {
+			return err
+		}
+		for {
+			p.skipSpace()
+			object, err := p.object()
+			if err != nil {
+				return err
+			}
+			p.triples = append(p.triples, Triple{Subject: subject, Predicate: predicate, Object: object})
+			p.skipSpace()
+			if !p.consume(',') {
+				break
+			}
+		}
+		p.skipSpace()
+		if !p.consume(';') {
+			return nil
+		}
+		// Repeated and trailing semicolons are allowed.
+		for {
+			p.skipSpace()
+			if !p.consume(';') {
+				break
+			}
+		}
+		p.skipSpace()
+		if c := p.peek(); c == '.' || c == ']' {
+			return nil
+		}
+	}
+}
+
+func (p *turtleParser) verb() (Term, error) {
+	if p.peek() == 'a' && p.pos+1 < len(p.src) && (isTurtleSpace(p.src[p.pos+1]) || p.src[p.pos+1] == '<') {
+		p.pos++
+		return IRI(rdfType), nil
+	}
+	if p.peek() == '<' {
+		iri, err := p.iriRef()
+		return IRI(iri), err
+	}
+	iri, err := p.prefixedName()
+	return IRI(iri), err
+}
+
+func (p *turtleParser) object() (Term, error) {
+	switch c := p.peek(); {
+	case c == '<':
+		iri, err := p.iriRef()
+		return IRI(iri), err
+	case c == '"' || c == '\'':
+		return p.literal()
+	case c == '[':
+		p.pos++
+		node := p.newBlank()
+		p.skipSpace()
+		if p.peek() != ']' {
+			if err := p.predicateObjectList(node); err != nil {
+				return Term{}, err
+			}
+			p.skipSpace()
+		}
+		if !p.consume(']') {
+			return Term{}, p.errorf("expected ']'")
+		}
+		return node, nil
+	case c == '(':
+		return p.collection()
+	case c == '+' || c == '-' || isASCIIDigit(c):
+		return p.number()
+	case strings.HasPrefix(p.src[p.pos:], "_:"):
+		return p.blankLabel(), nil
+	case p.peekKeyword("true") || p.peekKeyword("false"):
+		value := "true"
+		if p.src[p.pos] == 'f' {
+			value = "false"
+		}
+		p.pos += len(value)
+		return Literal(value, xsdBoolean), nil
+	default:
+		iri, err := p.prefixedName()
+		return IRI(iri), err
+	}
+}
+
+func (p *turtleParser) collection() (Term, error) {
+	p.pos++ // (
+	var items []Term
+	for {
+		p.skipSpace()
+		if p.consume(')') {
+			break
+		}
+		if p.pos >= len(p.src) {
+			return Term{}, p.errorf("unterminated collection")
+		}
+		item, err := p.object()
+		if err != nil {
+			return Term{}, err
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return IRI(rdfNil), nil
+	}
+	head := p.newBlank()
+	node := head
+	for i, item := range items {
+		p.triples = append(p.triples, Triple{Subject: node, Predicate: IRI(rdfFirst), Object: item})
+		next := IRI(rdfNil)
+		if i < len(items)-1 {
+			next = p.newBlank()
+		}
+		p.triples = append(p.triples, Triple{Subject: node, Predicate: IRI(rdfRest), Object: next})
+		node = next
+	}
+	return head, nil
+}
+
+func (p *turtleParser) literal() (Term, error) {
+	quote := p.src[p.pos]
+	long := strings.HasPrefix(p.src[p.pos:], strings.Repeat(string(quote), 3))
+	if long {
+		p.pos += 3
+	} else {
+		p.pos++
+	}
+
+	var b strings.Builder
+	for {
+		if p.pos >= len(p.src) {
+			return Term{}, p.errorf("unterminated string")
+		}
+		c := p.src[p.pos]
+		if long && strings.HasPrefix(p.src[p.pos:], strings.Repeat(string(quote), 3)) {
+			p.pos += 3
+			break
+		}
+		if !long && c == quote {
+			p.pos++
+			break
+		}
+		if !long && (c == '\n' || c == '\r') {
+			return Term{}, p.errorf("newline in short string")
+		}
+		if c == '\\' {
+			r, err := p.escape()
+			if err != nil {
+				return Term{}, err
+			}
+			b.WriteRune(r)
+			continue
+		}
+		if c == '\n' {
+			p.line++
+		}
+		b.WriteByte(c)
+		p.pos++
+	}
+	value := b.String()
+
+	if p.consume('@') {
+		start := p.pos
+		for p.pos < len(p.src) && (isASCIILetter(p.src[p.pos]) || p.src[p.pos] == '-' || (p.pos > start && isASCIIDigit(p.src[p.pos]))) {
+			p.pos++
+		}
+		if p.pos == start {
+			return Term{}, p.errorf("empty language tag")
+		}
+		return LangLiteral(value, p.src[start:p.pos]), nil
+	}
+	if strings.HasPrefix(p.src[p.pos:], "^^") {
+		p.pos += 2
+		var (
+			datatype string
+			err      error
+		)
+		if p.peek() == '<' {
+			datatype, err = p.iriRef()
+		} else {
+			datatype, err = p.prefixedName()
+		}
+		if err != nil {
+			return Term{}, err
+		}
+		return Literal(value, datatype), nil
+	}
+	return Literal(value, xsdString), nil
+}
+
+func (p *turtleParser) escape() (rune, error) {
+	if p.pos+1 >= len(p.src) {
+		return 0, p.errorf("truncated escape")
+	}
+	c := p.src[p.pos+1]
+	p.pos += 2
+	switch c {
+	case 't':
+		return '\t', nil
+	case 'b':
+		return '\b', nil
+	case 'n':
+		return '\n', nil
+	case 'r':
+		return '\r', nil
+	case 'f':
+		return '\f', nil
+	case '"', '\'', '\\':
+		return rune(c), nil
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.src) {
+			return 0, p.errorf("truncated \\%c escape", c)
+		}
+		code, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return 0, p.errorf("invalid \\%c escape", c)
+		}
+		p.pos += n
+		return rune(code), nil
+	default:
+		return 0, p.errorf("invalid escape \\%c", c)
+	}
+}
+
+func (p *turtleParser) number() (Term, error) {
+	start := p.pos
+	if c := p.peek(); c == '+' || c == '-' {
+		p.pos++
+	}
+	datatype := xsdInteger
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case isASCIIDigit(c):
+		case c == '.' && datatype == xsdInteger && p.pos+1 < len(p.src) && isASCIIDigit(p.src[p.pos+1]):
+			datatype = xsdDecimal
+		case (c == 'e' || c == 'E') && datatype != xsdDouble:
+			datatype = xsdDouble
+			if p.pos+1 < len(p.src) && (p.src[p.pos+1] == '+' || p.src[p.pos+1] == '-') {
+				p.pos++
+			}
+		default:
+			goto done
+		}
+		p.pos++
+	}
+done:
+	lexical := p.src[start:p.pos]
+	if strings.IndexFunc(lexical, unicode.IsDigit) < 0 {
+		return Term{}, p.errorf("invalid number %q", lexical)
+	}
+	return Literal(lexical, datatype), nil
+}
+
+func (p *turtleParser) iriRef() (string, error) {
+	if !p.consume('<') {
+		return "", p.errorf("expected '<'")
+	}
+	end := strings.IndexByte(p.src[p.pos:], '>')
+	if end < 0 {
+		return "", p.errorf("unterminated IRI")
+	}
+	iri := p.src[p.pos : p.pos+end]
+	p.pos += end + 1
+	if !validIRI(iri) || !iriScheme.MatchString(iri) {
+		return "", p.errorf("invalid or relative IRI <%s>", iri)
+	}
+	return iri, nil
+}
+
+func (p *turtleParser) prefixedName() (string, error) {
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != ':' && isNameChar(p.src[p.pos]) {
+		p.pos++
+	}
+	if !p.consume(':') {
+		p.pos = start
+		return "", p.errorf("unexpected %q", p.rest())
+	}
+	prefix := p.src[start : p.pos-1]
+	ns, ok := p.prefixes[prefix]
+	if !ok {
+		return "", p.errorf("undeclared prefix %q", prefix)
+	}
+	local := p.localName()
+	return ns + local, nil
+}
+
+// localName reads a PN_LOCAL; a trailing '.' ends the statement rather than
+// belonging to the name.
+func (p *turtleParser) localName() string {
+	start := p.pos
+	for p.pos < len(p.src) && (isNameChar(p.src[p.pos]) || p.src[p.pos] == '.' || p.src[p.pos] == ':') {
+		p.pos++
+	}
+	for p.pos > start && p.src[p.pos-1] == '.' {
+		p.pos--
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *turtleParser) blankLabel() Term {
+	p.pos += 2 // _:
+	name := p.localName()
+	label, ok := p.labels[name]
+	if !ok {
+		label = p.newBlank().Value
+		p.labels[name] = label
+	}
+	return BlankNode(label)
+}
+
+func (p *turtleParser) newBlank() Term {
+	label := "t" + strconv.Itoa(p.blanks)
+	p.blanks++
+	return BlankNode(label)
+}
+
+func (p *turtleParser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case isTurtleSpace(c):
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *turtleParser) peek() byte {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *turtleParser) consume(c byte) bool {
+	if p.peek() == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// peekKeyword reports whether kw starts at the cursor and is not the prefix
+// of a longer name.
+func (p *turtleParser) peekKeyword(kw string) bool {
+	if !strings.HasPrefix(p.src[p.pos:], kw) {
+		return false
+	}
+	next := p.pos + len(kw)
+	return next >= len(p.src) || (!isNameChar(p.src[next]) && p.src[next] != ':')
+}
+
+func (p *turtleParser) rest() string {
+	end := min(p.pos+20, len(p.src))
+	return p.src[p.pos:end]
+}
+
+func isTurtleSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isASCIIDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isNameChar accepts the ASCII subset of PN_CHARS plus any non-ASCII byte.
+func isNameChar(c byte) bool {
+	return isASCIILetter(c) || isASCIIDigit(c) || c == '_' || c == '-' || c >= 0x80
+}
@@ -0,0 +1,727 @@
+// Code generated by ontology/cmd/generate; DO NOT EDIT.
+
+package ontology
+
+// Classes mapped from proto messages.
+const (
+	ClassAdminNote               = "sk:AdminNote"
+	ClassAnnouncement            = "sk:Announcement"
+	ClassApplication             = "sk:Application"
+	ClassAssessmentQuestion      = "sk:AssessmentQuestion"
+	ClassAttachment              = "sk:Attachment"
+	ClassAttendee                = "sk:Attendee"
+	ClassAuditLog                = "sk:AuditLog"
+	ClassAvailability            = "sk:Availability"
+	ClassBadgeTemplate           = "sk:BadgeTemplate"
+	ClassCertification           = "sk:Certification"
+	ClassChallenge               = "sk:Challenge"
+	ClassChallengeEntry          = "sk:ChallengeEntry"
+	ClassChatMessage             = "sk:ChatMessage"
+	ClassConversation            = "sk:Conversation"
+	ClassDataPoint               = "sk:DataPoint"
+	ClassDispute                 = "sk:Dispute"
+	ClassEngagementMetrics       = "sk:EngagementMetrics"
+	ClassErrorDetail             = "sk:ErrorDetail"
+	ClassEscrowPayment           = "sk:EscrowPayment"
+	ClassFeatureFlag             = "sk:FeatureFlag"
+	ClassFeaturedUser            = "sk:FeaturedUser"
+	ClassFeedbackSection         = "sk:FeedbackSection"
+	ClassFlaggedContent          = "sk:FlaggedContent"
+	ClassFunnelStage             = "sk:FunnelStage"
+	ClassGeographicData          = "sk:GeographicData"
+	ClassGig                     = "sk:Gig"
+	ClassInvoice                 = "sk:Invoice"
+	ClassLeaderboardEntry        = "sk:LeaderboardEntry"
+	ClassLearningResource        = "sk:LearningResource"
+	ClassLocation                = "sk:Location"
+	ClassMatch                   = "sk:Match"
+	ClassMatchingMetadata        = "sk:MatchingMetadata"
+	ClassMessage                 = "sk:Message"
+	ClassMoney                   = "sk:Money"
+	ClassNotificationPreferences = "sk:NotificationPreferences"
+	ClassPageInfo                = "sk:PageInfo"
+	ClassPayment                 = "sk:Payment"
+	ClassPaymentMethodInfo       = "sk:PaymentMethodInfo"
+	ClassPlatformSettings        = "sk:PlatformSettings"
+	ClassPlatformStats           = "sk:PlatformStats"
+	ClassPrize                   = "sk:Prize"
+	ClassProgressInsight         = "sk:ProgressInsight"
+	ClassRating                  = "sk:Rating"
+	ClassReaction                = "sk:Reaction"
+	ClassRecommendation          = "sk:Recommendation"
+	ClassRegistration            = "sk:Registration"
+	ClassReport                  = "sk:Report"
+	ClassRetentionCohort         = "sk:RetentionCohort"
+	ClassRevenueBreakdown        = "sk:RevenueBreakdown"
+	ClassRevenueDataPoint        = "sk:RevenueDataPoint"
+	ClassReview                  = "sk:Review"
+	ClassReviewer                = "sk:Reviewer"
+	ClassRoadmapStep             = "sk:RoadmapStep"
+	ClassSearchFilters           = "sk:SearchFilters"
+	ClassSearchMetadata          = "sk:SearchMetadata"
+	ClassSearchQuery             = "sk:SearchQuery"
+	ClassSession                 = "sk:Session"
+	ClassSimilarUser             = "sk:SimilarUser"
+	ClassSkill                   = "sk:Skill"
+	ClassSkillEmbedding          = "sk:SkillEmbedding"
+	ClassSkillGap                = "sk:SkillGap"
+	ClassSkillMatch              = "sk:SkillMatch"
+	ClassSkillSearchResult       = "sk:SkillSearchResult"
+	ClassSkillStats              = "sk:SkillStats"
+	ClassSkillSuggestion         = "sk:SkillSuggestion"
+	ClassSkillTrend              = "sk:SkillTrend"
+	ClassSubscription            = "sk:Subscription"
+	ClassSubscriptionFeature     = "sk:SubscriptionFeature"
+	ClassSuggestion              = "sk:Suggestion"
+	ClassTimeSlot                = "sk:TimeSlot"
+	ClassTimeSlotPopularity      = "sk:TimeSlotPopularity"
+	ClassTopFeature              = "sk:TopFeature"
+	ClassTrendingSkill           = "sk:TrendingSkill"
+	ClassTypingIndicator         = "sk:TypingIndicator"
+	ClassUser                    = "sk:User"
+	ClassUserChallenge           = "sk:UserChallenge"
+	ClassUserEvent               = "sk:UserEvent"
+	ClassUserProfile             = "sk:UserProfile"
+	ClassUserSearchResult        = "sk:UserSearchResult"
+	ClassUserSkill               = "sk:UserSkill"
+	ClassVote                    = "sk:Vote"
+	ClassWinner                  = "sk:Winner"
+	ClassWorkSubmission          = "sk:WorkSubmission"
+	ClassWorkshop                = "sk:Workshop"
+)
+
+// Activity types recorded through prov:wasGeneratedBy.
+const (
+	ActivityMatchAccepted           = "sk:MatchAccepted"
+	ActivityMatchDeclined           = "sk:MatchDeclined"
+	ActivityMatchProposed           = "sk:MatchProposed"
+	ActivityReviewAccepted          = "sk:ReviewAccepted"
+	ActivityReviewCancelled         = "sk:ReviewCancelled"
+	ActivityReviewCompleted         = "sk:ReviewCompleted"
+	ActivityReviewDeclined          = "sk:ReviewDeclined"
+	ActivityReviewRequested         = "sk:ReviewRequested"
+	ActivityReviewRevisionRequested = "sk:ReviewRevisionRequested"
+	ActivityReviewStarted           = "sk:ReviewStarted"
+	ActivitySessionCancelled        = "sk:SessionCancelled"
+	ActivitySessionCompleted        = "sk:SessionCompleted"
+	ActivitySessionNoShow           = "sk:SessionNoShow"
+	ActivitySessionRated            = "sk:SessionRated"
+	ActivitySessionScheduled        = "sk:SessionScheduled"
+	ActivitySessionStarted          = "sk:SessionStarted"
+	ActivitySkillCreated            = "sk:SkillCreated"
+	ActivitySkillUpdated            = "sk:SkillUpdated"
+	ActivityUserDeactivated         = "sk:UserDeactivated"
+	ActivityUserLoggedIn            = "sk:UserLoggedIn"
+	ActivityUserReactivated         = "sk:UserReactivated"
+	ActivityUserRegistered          = "sk:UserRegistered"
+	ActivityUserRoleChanged         = "sk:UserRoleChanged"
+	ActivityUserSkillAdded          = "sk:UserSkillAdded"
+	ActivityUserSkillUpdated        = "sk:UserSkillUpdated"
+	ActivityUserVerified            = "sk:UserVerified"
+)
+
+// Properties in the sk: namespace.
+const (
+	PropAcceptedAt                    = "sk:acceptedAt"
+	PropAcquiredAt                    = "sk:acquiredAt"
+	PropAction                        = "sk:action"
+	PropActiveUsers                   = "sk:activeUsers"
+	PropActualEnd                     = "sk:actualEnd"
+	PropActualStart                   = "sk:actualStart"
+	PropAdminId                       = "sk:adminId"
+	PropAlgorithmUsed                 = "sk:algorithmUsed"
+	PropAmount                        = "sk:amount"
+	PropAnnouncementId                = "sk:announcementId"
+	PropApplicationCount              = "sk:applicationCount"
+	PropApplicationId                 = "sk:applicationId"
+	PropAppliedAt                     = "sk:appliedAt"
+	PropAppliedFilters                = "sk:appliedFilters"
+	PropAssignedAdminId               = "sk:assignedAdminId"
+	PropAssignedTo                    = "sk:assignedTo"
+	PropAttachmentId                  = "sk:attachmentId"
+	PropAttachments                   = "sk:attachments"
+	PropAttended                      = "sk:attended"
+	PropAutoApproveCertifications     = "sk:autoApproveCertifications"
+	PropAvailability                  = "sk:availability"
+	PropAvailableDays                 = "sk:availableDays"
+	PropAvatarUrl                     = "sk:avatarUrl"
+	PropAverageProficiency            = "sk:averageProficiency"
+	PropAverageRating                 = "sk:averageRating"
+	PropAverageReviewRating           = "sk:averageReviewRating"
+	PropAverageSessionDurationMinutes = "sk:averageSessionDurationMinutes"
+	PropAverageSessionRating          = "sk:averageSessionRating"
+	PropBadge                         = "sk:badge"
+	PropBadgeImageUrl                 = "sk:badgeImageUrl"
+	PropBannerImageUrl                = "sk:bannerImageUrl"
+	PropBio                           = "sk:bio"
+	PropBlockchain                    = "sk:blockchain"
+	PropBrand                         = "sk:brand"
+	PropBudget                        = "sk:budget"
+	PropCancelAtPeriodEnd             = "sk:cancelAtPeriodEnd"
+	PropCancellationReason            = "sk:cancellationReason"
+	PropCancelled                     = "sk:cancelled"
+	PropCancelledAt                   = "sk:cancelledAt"
+	PropCashPrize                     = "sk:cashPrize"
+	PropCategories                    = "sk:categories"
+	PropCategory                      = "sk:category"
+	PropCertificateUrl                = "sk:certificateUrl"
+	PropCertificationId               = "sk:certificationId"
+	PropCertifications                = "sk:certifications"
+	PropChallenge                     = "sk:challenge"
+	PropChallengeId                   = "sk:challengeId"
+	PropCity                          = "sk:city"
+	PropCode                          = "sk:code"
+	PropCohortDate                    = "sk:cohortDate"
+	PropComment                       = "sk:comment"
+	PropCommonSkills                  = "sk:commonSkills"
+	PropCompletedAt                   = "sk:completedAt"
+	PropCompletedSessions             = "sk:completedSessions"
+	PropComputedAt                    = "sk:computedAt"
+	PropContent                       = "sk:content"
+	PropContentId                     = "sk:contentId"
+	PropContentPreview                = "sk:contentPreview"
+	PropContentType                   = "sk:contentType"
+	PropContractAddress               = "sk:contractAddress"
+	PropConversationId                = "sk:conversationId"
+	PropConversionRate                = "sk:conversionRate"
+	PropCorrectAnswer                 = "sk:correctAnswer"
+	PropCountry                       = "sk:country"
+	PropCountryCode                   = "sk:countryCode"
+	PropCoverImageUrl                 = "sk:coverImageUrl"
+	PropCoverLetter                   = "sk:coverLetter"
+	PropCreatedAt                     = "sk:createdAt"
+	PropCreatorId                     = "sk:creatorId"
+	PropCurrencyCode                  = "sk:currencyCode"
+	PropCurrentParticipants           = "sk:currentParticipants"
+	PropCurrentPeriodEnd              = "sk:currentPeriodEnd"
+	PropCurrentPeriodStart            = "sk:currentPeriodStart"
+	PropDailyActiveUsers              = "sk:dailyActiveUsers"
+	PropDataPoints                    = "sk:dataPoints"
+	PropDay                           = "sk:day"
+	PropDeadline                      = "sk:deadline"
+	PropDeliverables                  = "sk:deliverables"
+	PropDemoUrl                       = "sk:demoUrl"
+	PropDepth                         = "sk:depth"
+	PropDescription                   = "sk:description"
+	PropDifficulty                    = "sk:difficulty"
+	PropDifficultyScore               = "sk:difficultyScore"
+	PropDisplayName                   = "sk:displayName"
+	PropDisputeId                     = "sk:disputeId"
+	PropDisputedUserId                = "sk:disputedUserId"
+	PropDisputingUserId               = "sk:disputingUserId"
+	PropDistanceKm                    = "sk:distanceKm"
+	PropDropOffRate                   = "sk:dropOffRate"
+	PropDueDate                       = "sk:dueDate"
+	PropDurationMinutes               = "sk:durationMinutes"
+	PropEmail                         = "sk:email"
+	PropEmailEnabled                  = "sk:emailEnabled"
+	PropEmoji                         = "sk:emoji"
+	PropEnabled                       = "sk:enabled"
+	PropEnabledForUsers               = "sk:enabledForUsers"
+	PropEndDate                       = "sk:endDate"
+	PropEndTime                       = "sk:endTime"
+	PropEntry                         = "sk:entry"
+	PropEntryCount                    = "sk:entryCount"
+	PropEntryId                       = "sk:entryId"
+	PropEscrowId                      = "sk:escrowId"
+	PropEstimatedHours                = "sk:estimatedHours"
+	PropEventName                     = "sk:eventName"
+	PropEvidence                      = "sk:evidence"
+	PropExpMonth                      = "sk:expMonth"
+	PropExpYear                       = "sk:expYear"
+	PropExperienceDescription         = "sk:experienceDescription"
+	PropExpertise                     = "sk:expertise"
+	PropExpiresAt                     = "sk:expiresAt"
+	PropExplanation                   = "sk:explanation"
+	PropFeatureName                   = "sk:featureName"
+	PropFeatureReason                 = "sk:featureReason"
+	PropFeedback                      = "sk:feedback"
+	PropFileName                      = "sk:fileName"
+	PropFileSizeBytes                 = "sk:fileSizeBytes"
+	PropFileUrl                       = "sk:fileUrl"
+	PropFilteredCount                 = "sk:filteredCount"
+	PropFlagCount                     = "sk:flagCount"
+	PropFlagId                        = "sk:flagId"
+	PropFlaggedAt                     = "sk:flaggedAt"
+	PropFormat                        = "sk:format"
+	PropFreelancerId                  = "sk:freelancerId"
+	PropGapSize                       = "sk:gapSize"
+	PropGeneratedAt                   = "sk:generatedAt"
+	PropGigId                         = "sk:gigId"
+	PropGigs                          = "sk:gigs"
+	PropGrowthRate                    = "sk:growthRate"
+	PropHasMatch                      = "sk:hasMatch"
+	PropHasParticipant                = "sk:hasParticipant"
+	PropHasSponsor                    = "sk:hasSponsor"
+	PropHasWon                        = "sk:hasWon"
+	PropHeldBy                        = "sk:heldBy"
+	PropHeldUntil                     = "sk:heldUntil"
+	PropHostId                        = "sk:hostId"
+	PropHour                          = "sk:hour"
+	PropHourlyRate                    = "sk:hourlyRate"
+	PropImageUrl                      = "sk:imageUrl"
+	PropImprovements                  = "sk:improvements"
+	PropInitialUsers                  = "sk:initialUsers"
+	PropInitiatedBy                   = "sk:initiatedBy"
+	PropInitiatorId                   = "sk:initiatorId"
+	PropInitiatorOffers               = "sk:initiatorOffers"
+	PropInvoiceId                     = "sk:invoiceId"
+	PropInvoiceUrl                    = "sk:invoiceUrl"
+	PropIsActive                      = "sk:isActive"
+	PropIsArchived                    = "sk:isArchived"
+	PropIsAvailable                   = "sk:isAvailable"
+	PropIsComplementary               = "sk:isComplementary"
+	PropIsDefault                     = "sk:isDefault"
+	PropIsDeleted                     = "sk:isDeleted"
+	PropIsFree                        = "sk:isFree"
+	PropIsHourly                      = "sk:isHourly"
+	PropIsMutual                      = "sk:isMutual"
+	PropIsOffered                     = "sk:isOffered"
+	PropIsPaid                        = "sk:isPaid"
+	PropIsPremium                     = "sk:isPremium"
+	PropIsRead                        = "sk:isRead"
+	PropIsTrending                    = "sk:isTrending"
+	PropIsTyping                      = "sk:isTyping"
+	PropIsVerified                    = "sk:isVerified"
+	PropIssuedAt                      = "sk:issuedAt"
+	PropIssuerId                      = "sk:issuerId"
+	PropIssuerName                    = "sk:issuerName"
+	PropItemId                        = "sk:itemId"
+	PropItemType                      = "sk:itemType"
+	PropJudgingCriteria               = "sk:judgingCriteria"
+	PropLastActiveAt                  = "sk:lastActiveAt"
+	PropLastFour                      = "sk:lastFour"
+	PropLastMessage                   = "sk:lastMessage"
+	PropLatitude                      = "sk:latitude"
+	PropLearningOutcomes              = "sk:learningOutcomes"
+	PropLocation                      = "sk:location"
+	PropLogId                         = "sk:logId"
+	PropLongitude                     = "sk:longitude"
+	PropMaintenanceMessage            = "sk:maintenanceMessage"
+	PropMaintenanceMode               = "sk:maintenanceMode"
+	PropMarketing                     = "sk:marketing"
+	PropMatchProficiency              = "sk:matchProficiency"
+	PropMatchScore                    = "sk:matchScore"
+	PropMatchTarget                   = "sk:matchTarget"
+	PropMatchedSkills                 = "sk:matchedSkills"
+	PropMatchingAlgorithm             = "sk:matchingAlgorithm"
+	PropMaterials                     = "sk:materials"
+	PropMaxDistanceKm                 = "sk:maxDistanceKm"
+	PropMaxParticipants               = "sk:maxParticipants"
+	PropMaxSessionsPerDay             = "sk:maxSessionsPerDay"
+	PropMeetingUrl                    = "sk:meetingUrl"
+	PropMessage                       = "sk:message"
+	PropMessageId                     = "sk:messageId"
+	PropMessages                      = "sk:messages"
+	PropMetric                        = "sk:metric"
+	PropMetricType                    = "sk:metricType"
+	PropMilestones                    = "sk:milestones"
+	PropMinProficiency                = "sk:minProficiency"
+	PropMinRating                     = "sk:minRating"
+	PropMinSessionRating              = "sk:minSessionRating"
+	PropMinSessions                   = "sk:minSessions"
+	PropMinSubscription               = "sk:minSubscription"
+	PropMinTier                       = "sk:minTier"
+	PropModelVersion                  = "sk:modelVersion"
+	PropMonthlyActiveUsers            = "sk:monthlyActiveUsers"
+	PropMonthlyAmount                 = "sk:monthlyAmount"
+	PropName                          = "sk:name"
+	PropNewMatches                    = "sk:newMatches"
+	PropNewSignupsEnabled             = "sk:newSignupsEnabled"
+	PropNewUsers                      = "sk:newUsers"
+	PropNextPageToken                 = "sk:nextPageToken"
+	PropNoteId                        = "sk:noteId"
+	PropNotes                         = "sk:notes"
+	PropOptions                       = "sk:options"
+	PropOrder                         = "sk:order"
+	PropPage                          = "sk:page"
+	PropPageSize                      = "sk:pageSize"
+	PropPaidAt                        = "sk:paidAt"
+	PropParticipantCount              = "sk:participantCount"
+	PropParticipantIds                = "sk:participantIds"
+	PropPartnerId                     = "sk:partnerId"
+	PropPartnerName                   = "sk:partnerName"
+	PropPartnerOffers                 = "sk:partnerOffers"
+	PropPayeeId                       = "sk:payeeId"
+	PropPayerId                       = "sk:payerId"
+	PropPaymentId                     = "sk:paymentId"
+	PropPaymentMethod                 = "sk:paymentMethod"
+	PropPaymentMethodId               = "sk:paymentMethodId"
+	PropPercentage                    = "sk:percentage"
+	PropPerks                         = "sk:perks"
+	PropPlatformFeePercentage         = "sk:platformFeePercentage"
+	PropPopularity                    = "sk:popularity"
+	PropPopularityScore               = "sk:popularityScore"
+	PropPremiumSubscribers            = "sk:premiumSubscribers"
+	PropPrerequisites                 = "sk:prerequisites"
+	PropPreviousPageToken             = "sk:previousPageToken"
+	PropPrice                         = "sk:price"
+	PropPriority                      = "sk:priority"
+	PropPrizeDescription              = "sk:prizeDescription"
+	PropPrizes                        = "sk:prizes"
+	PropProficiencyCategory           = "sk:proficiencyCategory"
+	PropProficiencyLevel              = "sk:proficiencyLevel"
+	PropProposedAmount                = "sk:proposedAmount"
+	PropPurpose                       = "sk:purpose"
+	PropPushEnabled                   = "sk:pushEnabled"
+	PropQueryId                       = "sk:queryId"
+	PropQueryTimeMs                   = "sk:queryTimeMs"
+	PropQuestion                      = "sk:question"
+	PropQuestionNumber                = "sk:questionNumber"
+	PropRank                          = "sk:rank"
+	PropRatedBy                       = "sk:ratedBy"
+	PropRatedSession                  = "sk:ratedSession"
+	PropRatedUser                     = "sk:ratedUser"
+	PropRating                        = "sk:rating"
+	PropRatingId                      = "sk:ratingId"
+	PropRatingScore                   = "sk:ratingScore"
+	PropReactions                     = "sk:reactions"
+	PropReadAt                        = "sk:readAt"
+	PropReason                        = "sk:reason"
+	PropRecipientId                   = "sk:recipientId"
+	PropRecordingUrl                  = "sk:recordingUrl"
+	PropRegisteredAt                  = "sk:registeredAt"
+	PropRegistrationId                = "sk:registrationId"
+	PropRelatedId                     = "sk:relatedId"
+	PropRelatedType                   = "sk:relatedType"
+	PropReleaseCondition              = "sk:releaseCondition"
+	PropReleasedAt                    = "sk:releasedAt"
+	PropRelevanceScore                = "sk:relevanceScore"
+	PropReportId                      = "sk:reportId"
+	PropReportType                    = "sk:reportType"
+	PropReportedUserId                = "sk:reportedUserId"
+	PropReporterId                    = "sk:reporterId"
+	PropRequestedAt                   = "sk:requestedAt"
+	PropRequestedBy                   = "sk:requestedBy"
+	PropRequesterComment              = "sk:requesterComment"
+	PropRequesterId                   = "sk:requesterId"
+	PropRequesterRating               = "sk:requesterRating"
+	PropRequiredProficiency           = "sk:requiredProficiency"
+	PropRequirements                  = "sk:requirements"
+	PropRequiresVerification          = "sk:requiresVerification"
+	PropResolvedAt                    = "sk:resolvedAt"
+	PropResources                     = "sk:resources"
+	PropRespondedAt                   = "sk:respondedAt"
+	PropResponseTimeHours             = "sk:responseTimeHours"
+	PropRetentionByPeriod             = "sk:retentionByPeriod"
+	PropRevenue                       = "sk:revenue"
+	PropReview                        = "sk:review"
+	PropReviewId                      = "sk:reviewId"
+	PropReviewStatus                  = "sk:reviewStatus"
+	PropReviewType                    = "sk:reviewType"
+	PropReviewTypes                   = "sk:reviewTypes"
+	PropReviewedBy                    = "sk:reviewedBy"
+	PropRevieweeId                    = "sk:revieweeId"
+	PropReviewerComment               = "sk:reviewerComment"
+	PropReviewerId                    = "sk:reviewerId"
+	PropReviewerRating                = "sk:reviewerRating"
+	PropReviews                       = "sk:reviews"
+	PropRevisionNumber                = "sk:revisionNumber"
+	PropRevokedAt                     = "sk:revokedAt"
+	PropRole                          = "sk:role"
+	PropRules                         = "sk:rules"
+	PropScheduledAt                   = "sk:scheduledAt"
+	PropScheduledEnd                  = "sk:scheduledEnd"
+	PropScheduledStart                = "sk:scheduledStart"
+	PropScore                         = "sk:score"
+	PropSections                      = "sk:sections"
+	PropSenderId                      = "sk:senderId"
+	PropSentAt                        = "sk:sentAt"
+	PropSessionCount                  = "sk:sessionCount"
+	PropSessionId                     = "sk:sessionId"
+	PropSessionNotes                  = "sk:sessionNotes"
+	PropSessionReminders              = "sk:sessionReminders"
+	PropSessionStatus                 = "sk:sessionStatus"
+	PropSessionsCount                 = "sk:sessionsCount"
+	PropSessionsLastPeriod            = "sk:sessionsLastPeriod"
+	PropSessionsPerUser               = "sk:sessionsPerUser"
+	PropSimilarityScore               = "sk:similarityScore"
+	PropSkill                         = "sk:skill"
+	PropSkillCategory                 = "sk:skillCategory"
+	PropSkillId                       = "sk:skillId"
+	PropSkillIds                      = "sk:skillIds"
+	PropSkillIdsOffered               = "sk:skillIdsOffered"
+	PropSkillIdsWanted                = "sk:skillIdsWanted"
+	PropSkillMatches                  = "sk:skillMatches"
+	PropSkillName                     = "sk:skillName"
+	PropSkills                        = "sk:skills"
+	PropSkillsOffered                 = "sk:skillsOffered"
+	PropSkillsWanted                  = "sk:skillsWanted"
+	PropSmsEnabled                    = "sk:smsEnabled"
+	PropSnippet                       = "sk:snippet"
+	PropSourceUrl                     = "sk:sourceUrl"
+	PropSpecificQuestions             = "sk:specificQuestions"
+	PropSponsorLogoUrl                = "sk:sponsorLogoUrl"
+	PropSponsorName                   = "sk:sponsorName"
+	PropStartDate                     = "sk:startDate"
+	PropStartTime                     = "sk:startTime"
+	PropStatus                        = "sk:status"
+	PropStickiness                    = "sk:stickiness"
+	PropStrengths                     = "sk:strengths"
+	PropStripeInvoiceId               = "sk:stripeInvoiceId"
+	PropStripePaymentIntentId         = "sk:stripePaymentIntentId"
+	PropStripeSubscriptionId          = "sk:stripeSubscriptionId"
+	PropSubmissionId                  = "sk:submissionId"
+	PropSubmittedAt                   = "sk:submittedAt"
+	PropSubscriptionId                = "sk:subscriptionId"
+	PropSubscriptionTier              = "sk:subscriptionTier"
+	PropSubscriptions                 = "sk:subscriptions"
+	PropTags                          = "sk:tags"
+	PropTargetId                      = "sk:targetId"
+	PropTargetType                    = "sk:targetType"
+	PropTemplateId                    = "sk:templateId"
+	PropText                          = "sk:text"
+	PropTicketPrice                   = "sk:ticketPrice"
+	PropTier                          = "sk:tier"
+	PropTimeSlots                     = "sk:timeSlots"
+	PropTimestamp                     = "sk:timestamp"
+	PropTimezone                      = "sk:timezone"
+	PropTitle                         = "sk:title"
+	PropTokenId                       = "sk:tokenId"
+	PropTopFeatures                   = "sk:topFeatures"
+	PropTopics                        = "sk:topics"
+	PropTotalCandidates               = "sk:totalCandidates"
+	PropTotalCertifications           = "sk:totalCertifications"
+	PropTotalCount                    = "sk:totalCount"
+	PropTotalGigs                     = "sk:totalGigs"
+	PropTotalMessagesSent             = "sk:totalMessagesSent"
+	PropTotalRevenue                  = "sk:totalRevenue"
+	PropTotalReviews                  = "sk:totalReviews"
+	PropTotalSearches                 = "sk:totalSearches"
+	PropTotalSessions                 = "sk:totalSessions"
+	PropTotalSkills                   = "sk:totalSkills"
+	PropTotalUsers                    = "sk:totalUsers"
+	PropTotalUsersOffering            = "sk:totalUsersOffering"
+	PropTotalUsersWanting             = "sk:totalUsersWanting"
+	PropTotalVotes                    = "sk:totalVotes"
+	PropTotalWorkshops                = "sk:totalWorkshops"
+	PropTransactionHash               = "sk:transactionHash"
+	PropTrend                         = "sk:trend"
+	PropTrendScore                    = "sk:trendScore"
+	PropType                          = "sk:type"
+	PropUnreadCount                   = "sk:unreadCount"
+	PropUpdatedAt                     = "sk:updatedAt"
+	PropUploadedAt                    = "sk:uploadedAt"
+	PropUrl                           = "sk:url"
+	PropUsageCount                    = "sk:usageCount"
+	PropUser                          = "sk:user"
+	PropUser1Proficiency              = "sk:user1Proficiency"
+	PropUser2Proficiency              = "sk:user2Proficiency"
+	PropUserCount                     = "sk:userCount"
+	PropUserEntry                     = "sk:userEntry"
+	PropUserId                        = "sk:userId"
+	PropUserProficiency               = "sk:userProficiency"
+	PropUserRank                      = "sk:userRank"
+	PropUserSatisfaction              = "sk:userSatisfaction"
+	PropUsername                      = "sk:username"
+	PropValue                         = "sk:value"
+	PropVector                        = "sk:vector"
+	PropVerifiedOnly                  = "sk:verifiedOnly"
+	PropVideoFeedbackUrl              = "sk:videoFeedbackUrl"
+	PropVoteCount                     = "sk:voteCount"
+	PropVoteId                        = "sk:voteId"
+	PropVotedAt                       = "sk:votedAt"
+	PropVoterId                       = "sk:voterId"
+	PropVotingEndDate                 = "sk:votingEndDate"
+	PropWeeklyActiveUsers             = "sk:weeklyActiveUsers"
+	PropWorkshopId                    = "sk:workshopId"
+	PropWorkshops                     = "sk:workshops"
+)
+
+// schema.org properties used by emitted events.
+const (
+	SchemaAlternateName = "schema:alternateName"
+	SchemaDateModified  = "schema:dateModified"
+	SchemaEmail         = "schema:email"
+	SchemaImage         = "schema:image"
+	SchemaLastReviewed  = "schema:lastReviewed"
+	SchemaName          = "schema:name"
+)
+
+// PROV-O terms used by activity records.
+const (
+	ProvActivity          = "prov:Activity"
+	ProvEndedAtTime       = "prov:endedAtTime"
+	ProvGenerated         = "prov:generated"
+	ProvWasAssociatedWith = "prov:wasAssociatedWith"
+	ProvWasGeneratedBy    = "prov:wasGeneratedBy"
+)
+
+// SKOS concepts generated from proto enum values.
+const (
+	ConceptModerationActionWarning            = "sk:ModerationActionWarning"
+	ConceptModerationActionContentRemoval     = "sk:ModerationActionContentRemoval"
+	ConceptModerationActionSuspension         = "sk:ModerationActionSuspension"
+	ConceptModerationActionBan                = "sk:ModerationActionBan"
+	ConceptModerationActionNoAction           = "sk:ModerationActionNoAction"
+	ConceptReportStatusPending                = "sk:ReportStatusPending"
+	ConceptReportStatusUnderReview            = "sk:ReportStatusUnderReview"
+	ConceptReportStatusResolved               = "sk:ReportStatusResolved"
+	ConceptReportStatusDismissed              = "sk:ReportStatusDismissed"
+	ConceptReportTypeHarassment               = "sk:ReportTypeHarassment"
+	ConceptReportTypeSpam                     = "sk:ReportTypeSpam"
+	ConceptReportTypeInappropriateContent     = "sk:ReportTypeInappropriateContent"
+	ConceptReportTypeFraud                    = "sk:ReportTypeFraud"
+	ConceptReportTypeCopyright                = "sk:ReportTypeCopyright"
+	ConceptReportTypeOther                    = "sk:ReportTypeOther"
+	ConceptDisputeStatusPending               = "sk:DisputeStatusPending"
+	ConceptDisputeStatusUnderReview           = "sk:DisputeStatusUnderReview"
+	ConceptDisputeStatusResolved              = "sk:DisputeStatusResolved"
+	ConceptDisputeStatusEscalated             = "sk:DisputeStatusEscalated"
+	ConceptAnnouncementPriorityLow            = "sk:AnnouncementPriorityLow"
+	ConceptAnnouncementPriorityMedium         = "sk:AnnouncementPriorityMedium"
+	ConceptAnnouncementPriorityHigh           = "sk:AnnouncementPriorityHigh"
+	ConceptAnnouncementPriorityCritical       = "sk:AnnouncementPriorityCritical"
+	ConceptAIModelGeminiPro                   = "sk:AIModelGeminiPro"
+	ConceptAIModelGeminiUltra                 = "sk:AIModelGeminiUltra"
+	ConceptAIModelGeminiEmbedding             = "sk:AIModelGeminiEmbedding"
+	ConceptDifficultyLevelBeginner            = "sk:DifficultyLevelBeginner"
+	ConceptDifficultyLevelIntermediate        = "sk:DifficultyLevelIntermediate"
+	ConceptDifficultyLevelAdvanced            = "sk:DifficultyLevelAdvanced"
+	ConceptTimeGranularityHourly              = "sk:TimeGranularityHourly"
+	ConceptTimeGranularityDaily               = "sk:TimeGranularityDaily"
+	ConceptTimeGranularityWeekly              = "sk:TimeGranularityWeekly"
+	ConceptTimeGranularityMonthly             = "sk:TimeGranularityMonthly"
+	ConceptMetricTypeCount                    = "sk:MetricTypeCount"
+	ConceptMetricTypeSum                      = "sk:MetricTypeSum"
+	ConceptMetricTypeAverage                  = "sk:MetricTypeAverage"
+	ConceptMetricTypePercentage               = "sk:MetricTypePercentage"
+	ConceptOAuthProviderOauthProviderGoogle   = "sk:OAuthProviderOauthProviderGoogle"
+	ConceptOAuthProviderOauthProviderGithub   = "sk:OAuthProviderOauthProviderGithub"
+	ConceptOAuthProviderOauthProviderLinkedin = "sk:OAuthProviderOauthProviderLinkedin"
+	ConceptOAuthProviderOauthProviderFacebook = "sk:OAuthProviderOauthProviderFacebook"
+	ConceptRoleIsModertor                     = "sk:RoleIsModertor"
+	ConceptRoleIsUser                         = "sk:RoleIsUser"
+	ConceptCertificationTypeSkillCompletion   = "sk:CertificationTypeSkillCompletion"
+	ConceptCertificationTypeExpertVerified    = "sk:CertificationTypeExpertVerified"
+	ConceptCertificationTypeMilestone         = "sk:CertificationTypeMilestone"
+	ConceptCertificationTypeAchievement       = "sk:CertificationTypeAchievement"
+	ConceptBlockchainNetworkPolygon           = "sk:BlockchainNetworkPolygon"
+	ConceptBlockchainNetworkEthereum          = "sk:BlockchainNetworkEthereum"
+	ConceptBlockchainNetworkSolana            = "sk:BlockchainNetworkSolana"
+	ConceptCertificationStatusPending         = "sk:CertificationStatusPending"
+	ConceptCertificationStatusIssued          = "sk:CertificationStatusIssued"
+	ConceptCertificationStatusRevoked         = "sk:CertificationStatusRevoked"
+	ConceptChallengeStatusUpcoming            = "sk:ChallengeStatusUpcoming"
+	ConceptChallengeStatusActive              = "sk:ChallengeStatusActive"
+	ConceptChallengeStatusVoting              = "sk:ChallengeStatusVoting"
+	ConceptChallengeStatusCompleted           = "sk:ChallengeStatusCompleted"
+	ConceptChallengeStatusCancelled           = "sk:ChallengeStatusCancelled"
+	ConceptChallengeTypeWeekly                = "sk:ChallengeTypeWeekly"
+	ConceptChallengeTypeMonthly               = "sk:ChallengeTypeMonthly"
+	ConceptChallengeTypeSpecialEvent          = "sk:ChallengeTypeSpecialEvent"
+	ConceptChallengeTypeSponsored             = "sk:ChallengeTypeSponsored"
+	ConceptEntryStatusDraft                   = "sk:EntryStatusDraft"
+	ConceptEntryStatusSubmitted               = "sk:EntryStatusSubmitted"
+	ConceptEntryStatusDisqualified            = "sk:EntryStatusDisqualified"
+	ConceptEntryStatusWinner                  = "sk:EntryStatusWinner"
+	ConceptMessageTypeText                    = "sk:MessageTypeText"
+	ConceptMessageTypeImage                   = "sk:MessageTypeImage"
+	ConceptMessageTypeFile                    = "sk:MessageTypeFile"
+	ConceptMessageTypeSystem                  = "sk:MessageTypeSystem"
+	ConceptSkillCategoryTech                  = "sk:SkillCategoryTech"
+	ConceptSkillCategoryLanguages             = "sk:SkillCategoryLanguages"
+	ConceptSkillCategoryCreative              = "sk:SkillCategoryCreative"
+	ConceptSkillCategoryProfessional          = "sk:SkillCategoryProfessional"
+	ConceptSkillCategoryHobbies               = "sk:SkillCategoryHobbies"
+	ConceptSkillCategoryFitness               = "sk:SkillCategoryFitness"
+	ConceptSkillCategoryAcademics             = "sk:SkillCategoryAcademics"
+	ConceptSkillCategoryLifeSkills            = "sk:SkillCategoryLifeSkills"
+	ConceptProficiencyLevelBeginner           = "sk:ProficiencyLevelBeginner"
+	ConceptProficiencyLevelIntermediate       = "sk:ProficiencyLevelIntermediate"
+	ConceptProficiencyLevelExpert             = "sk:ProficiencyLevelExpert"
+	ConceptUserStatusActive                   = "sk:UserStatusActive"
+	ConceptUserStatusSuspended                = "sk:UserStatusSuspended"
+	ConceptUserStatusBanned                   = "sk:UserStatusBanned"
+	ConceptUserStatusDeleted                  = "sk:UserStatusDeleted"
+	ConceptSessionStatusScheduled             = "sk:SessionStatusScheduled"
+	ConceptSessionStatusInProgress            = "sk:SessionStatusInProgress"
+	ConceptSessionStatusCompleted             = "sk:SessionStatusCompleted"
+	ConceptSessionStatusCancelled             = "sk:SessionStatusCancelled"
+	ConceptSessionStatusNoShow                = "sk:SessionStatusNoShow"
+	ConceptPaymentStatusPending               = "sk:PaymentStatusPending"
+	ConceptPaymentStatusCompleted             = "sk:PaymentStatusCompleted"
+	ConceptPaymentStatusFailed                = "sk:PaymentStatusFailed"
+	ConceptPaymentStatusRefunded              = "sk:PaymentStatusRefunded"
+	ConceptPaymentStatusHeldInEscrow          = "sk:PaymentStatusHeldInEscrow"
+	ConceptSubscriptionTierFree               = "sk:SubscriptionTierFree"
+	ConceptSubscriptionTierPremium            = "sk:SubscriptionTierPremium"
+	ConceptSubscriptionTierProfessional       = "sk:SubscriptionTierProfessional"
+	ConceptContentTypeCode                    = "sk:ContentTypeCode"
+	ConceptContentTypeDesign                  = "sk:ContentTypeDesign"
+	ConceptContentTypeVideo                   = "sk:ContentTypeVideo"
+	ConceptContentTypeDocument                = "sk:ContentTypeDocument"
+	ConceptContentTypeAudio                   = "sk:ContentTypeAudio"
+	ConceptChallengeDifficultyEasy            = "sk:ChallengeDifficultyEasy"
+	ConceptChallengeDifficultyMedium          = "sk:ChallengeDifficultyMedium"
+	ConceptChallengeDifficultyHard            = "sk:ChallengeDifficultyHard"
+	ConceptDayOfWeekMonday                    = "sk:DayOfWeekMonday"
+	ConceptDayOfWeekTuesday                   = "sk:DayOfWeekTuesday"
+	ConceptDayOfWeekWednesday                 = "sk:DayOfWeekWednesday"
+	ConceptDayOfWeekThursday                  = "sk:DayOfWeekThursday"
+	ConceptDayOfWeekFriday                    = "sk:DayOfWeekFriday"
+	ConceptDayOfWeekSaturday                  = "sk:DayOfWeekSaturday"
+	ConceptDayOfWeekSunday                    = "sk:DayOfWeekSunday"
+	ConceptGigStatusOpen                      = "sk:GigStatusOpen"
+	ConceptGigStatusInProgress                = "sk:GigStatusInProgress"
+	ConceptGigStatusSubmitted                 = "sk:GigStatusSubmitted"
+	ConceptGigStatusRevisionRequested         = "sk:GigStatusRevisionRequested"
+	ConceptGigStatusCompleted                 = "sk:GigStatusCompleted"
+	ConceptGigStatusDisputed                  = "sk:GigStatusDisputed"
+	ConceptGigStatusCancelled                 = "sk:GigStatusCancelled"
+	ConceptGigTypeTutoring                    = "sk:GigTypeTutoring"
+	ConceptGigTypeProject                     = "sk:GigTypeProject"
+	ConceptGigTypeConsulting                  = "sk:GigTypeConsulting"
+	ConceptGigTypeReview                      = "sk:GigTypeReview"
+	ConceptApplicationStatusPending           = "sk:ApplicationStatusPending"
+	ConceptApplicationStatusAccepted          = "sk:ApplicationStatusAccepted"
+	ConceptApplicationStatusRejected          = "sk:ApplicationStatusRejected"
+	ConceptApplicationStatusWithdrawn         = "sk:ApplicationStatusWithdrawn"
+	ConceptMatchingAlgorithmEuclidean         = "sk:MatchingAlgorithmEuclidean"
+	ConceptMatchingAlgorithmCosine            = "sk:MatchingAlgorithmCosine"
+	ConceptMatchingAlgorithmEmbedding         = "sk:MatchingAlgorithmEmbedding"
+	ConceptMatchingAlgorithmHybrid            = "sk:MatchingAlgorithmHybrid"
+	ConceptRecommendationTypePartners         = "sk:RecommendationTypePartners"
+	ConceptRecommendationTypeSkills           = "sk:RecommendationTypeSkills"
+	ConceptRecommendationTypeSessions         = "sk:RecommendationTypeSessions"
+	ConceptPaymentMethodCard                  = "sk:PaymentMethodCard"
+	ConceptPaymentMethodPaypal                = "sk:PaymentMethodPaypal"
+	ConceptPaymentMethodBankTransfer          = "sk:PaymentMethodBankTransfer"
+	ConceptPaymentMethodCrypto                = "sk:PaymentMethodCrypto"
+	ConceptPaymentPurposeSubscription         = "sk:PaymentPurposeSubscription"
+	ConceptPaymentPurposeWorkshop             = "sk:PaymentPurposeWorkshop"
+	ConceptPaymentPurposeGig                  = "sk:PaymentPurposeGig"
+	ConceptPaymentPurposeCertification        = "sk:PaymentPurposeCertification"
+	ConceptPaymentPurposePremiumChat          = "sk:PaymentPurposePremiumChat"
+	ConceptReviewStatusPending                = "sk:ReviewStatusPending"
+	ConceptReviewStatusAccepted               = "sk:ReviewStatusAccepted"
+	ConceptReviewStatusInProgress             = "sk:ReviewStatusInProgress"
+	ConceptReviewStatusCompleted              = "sk:ReviewStatusCompleted"
+	ConceptReviewStatusRevisionRequested      = "sk:ReviewStatusRevisionRequested"
+	ConceptReviewStatusDeclined               = "sk:ReviewStatusDeclined"
+	ConceptReviewStatusCancelled              = "sk:ReviewStatusCancelled"
+	ConceptReviewTypeCodeReview               = "sk:ReviewTypeCodeReview"
+	ConceptReviewTypeDesignReview             = "sk:ReviewTypeDesignReview"
+	ConceptReviewTypeWritingReview            = "sk:ReviewTypeWritingReview"
+	ConceptReviewTypeVideoReview              = "sk:ReviewTypeVideoReview"
+	ConceptReviewTypePortfolioReview          = "sk:ReviewTypePortfolioReview"
+	ConceptReviewTypeResumeReview             = "sk:ReviewTypeResumeReview"
+	ConceptReviewDepthQuick                   = "sk:ReviewDepthQuick"
+	ConceptReviewDepthStandard                = "sk:ReviewDepthStandard"
+	ConceptReviewDepthComprehensive           = "sk:ReviewDepthComprehensive"
+	ConceptSearchSortByRelevance              = "sk:SearchSortByRelevance"
+	ConceptSearchSortByRating                 = "sk:SearchSortByRating"
+	ConceptSearchSortBySessions               = "sk:SearchSortBySessions"
+	ConceptSearchSortByDistance               = "sk:SearchSortByDistance"
+	ConceptSearchSortByRecent                 = "sk:SearchSortByRecent"
+	ConceptWorkshopStatusDraft                = "sk:WorkshopStatusDraft"
+	ConceptWorkshopStatusPublished            = "sk:WorkshopStatusPublished"
+	ConceptWorkshopStatusScheduled            = "sk:WorkshopStatusScheduled"
+	ConceptWorkshopStatusLive                 = "sk:WorkshopStatusLive"
+	ConceptWorkshopStatusCompleted            = "sk:WorkshopStatusCompleted"
+	ConceptWorkshopStatusCancelled            = "sk:WorkshopStatusCancelled"
+	ConceptWorkshopFormatLecture              = "sk:WorkshopFormatLecture"
+	ConceptWorkshopFormatInteractive          = "sk:WorkshopFormatInteractive"
+	ConceptWorkshopFormatHandsOn              = "sk:WorkshopFormatHandsOn"
+	ConceptWorkshopFormatQAndA                = "sk:WorkshopFormatQAndA"
+)
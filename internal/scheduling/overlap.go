@@ -0,0 +1,118 @@
+package scheduling
+
+import (
+	"sort"
+	"time"
+)
+
+// Interval is a half-open time range [Start, End).
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration is the interval's length.
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+func (i Interval) clip(from, to time.Time) (Interval, bool) {
+	if i.Start.Before(from) {
+		i.Start = from
+	}
+	if i.End.After(to) {
+		i.End = to
+	}
+	return i, i.Start.Before(i.End)
+}
+
+// merge sorts intervals and joins those that overlap or touch.
+func merge(intervals []Interval) []Interval {
+	if len(intervals) == 0 {
+		return nil
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].Start.Before(intervals[j].Start) })
+	out := []Interval{intervals[0]}
+	for _, next := range intervals[1:] {
+		last := &out[len(out)-1]
+		if next.Start.After(last.End) {
+			out = append(out, next)
+			continue
+		}
+		if next.End.After(last.End) {
+			last.End = next.End
+		}
+	}
+	return out
+}
+
+// Intersect returns the ranges covered by both a and b, which must be sorted
+// and non-overlapping as returned by Schedule.Between.
+func Intersect(a, b []Interval) []Interval {
+	var out []Interval
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start, end := a[i].Start, a[i].End
+		if b[j].Start.After(start) {
+			start = b[j].Start
+		}
+		if b[j].End.Before(end) {
+			end = b[j].End
+		}
+		if start.Before(end) {
+			out = append(out, Interval{Start: start, End: end})
+		}
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
+
+// Overlap is the time two schedules share in a window.
+type Overlap struct {
+	Intervals []Interval
+	Total     time.Duration
+}
+
+// Minutes is the shared time in whole minutes.
+func (o Overlap) Minutes() int {
+	return int(o.Total / time.Minute)
+}
+
+// Longest returns up to n shared intervals, longest first and earliest among
+// equals.
+func (o Overlap) Longest(n int) []Interval {
+	out := append([]Interval(nil), o.Intervals...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Duration() > out[j].Duration() })
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// Next returns the first shared interval starting at or after t.
+func (o Overlap) Next(t time.Time) (Interval, bool) {
+	for _, interval := range o.Intervals {
+		if !interval.Start.Before(t) {
+			return interval, true
+		}
+	}
+	return Interval{}, false
+}
+
+// WeeklyOverlap returns the time a and b are both available in the week
+// starting at from.
+func WeeklyOverlap(a, b Schedule, from time.Time) Overlap {
+	return Between(a, b, from, from.Add(Week))
+}
+
+// Between returns the time a and b are both available in [from, to).
+func Between(a, b Schedule, from, to time.Time) Overlap {
+	overlap := Overlap{Intervals: Intersect(a.Between(from, to), b.Between(from, to))}
+	for _, interval := range overlap.Intervals {
+		overlap.Total += interval.Duration()
+	}
+	return overlap
+}
@@ -0,0 +1,47 @@
+package scheduling
+
+import (
+	"fmt"
+	"time"
+
+	commonv1 "github.com/FACorreiaa/skillsphere-proto/gen/go/common/v1"
+)
+
+// FromProto converts common.v1.Availability.
+func FromProto(availability *commonv1.Availability) (Schedule, error) {
+	if availability == nil {
+		return Schedule{}, fmt.Errorf("%w: no availability", ErrInvalidSchedule)
+	}
+	slots := make([]Slot, 0, len(availability.TimeSlots))
+	for _, ts := range availability.TimeSlots {
+		if ts.Day < commonv1.DayOfWeek_DAY_OF_WEEK_MONDAY || ts.Day > commonv1.DayOfWeek_DAY_OF_WEEK_SUNDAY {
+			return Schedule{}, fmt.Errorf("%w: invalid day %v", ErrInvalidSchedule, ts.Day)
+		}
+		slot, err := ParseSlot(time.Weekday(ts.Day%7), ts.StartTime, ts.EndTime)
+		if err != nil {
+			return Schedule{}, err
+		}
+		slots = append(slots, slot)
+	}
+	return NewSchedule(availability.Timezone, slots)
+}
+
+// ToProto renders s as common.v1.Availability in its own time zone.
+func ToProto(s Schedule) *commonv1.Availability {
+	out := &commonv1.Availability{}
+	if s.Location != nil {
+		out.Timezone = s.Location.String()
+	}
+	for _, slot := range s.Slots {
+		day := commonv1.DayOfWeek(slot.Day)
+		if slot.Day == time.Sunday {
+			day = commonv1.DayOfWeek_DAY_OF_WEEK_SUNDAY
+		}
+		out.TimeSlots = append(out.TimeSlots, &commonv1.TimeSlot{
+			Day:       day,
+			StartTime: FormatClock(slot.Start),
+			EndTime:   FormatClock(slot.End),
+		})
+	}
+	return out
+}
@@ -0,0 +1,256 @@
+// Package scheduling turns weekly availability, kept in each user's local
+// time, into concrete UTC intervals so two users' schedules can be compared.
+//
+// A schedule says "Mondays 09:00-12:00 in Europe/Lisbon". What that means in
+// UTC depends on the week because of daylight saving time, so schedules are
+// always resolved against a concrete window, by default the coming week.
+package scheduling
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Week is the length of the window schedules are compared over.
+const Week = 7 * 24 * time.Hour
+
+const minutesPerDay = 24 * 60
+
+// ErrInvalidSchedule is returned for schedules that cannot be parsed.
+var ErrInvalidSchedule = errors.New("invalid availability schedule")
+
+// Slot is a recurring weekly time range in local time. Start and End are
+// minutes after midnight; an End at or before Start runs past midnight into
+// the next day, so "22:00-02:00" is four hours.
+type Slot struct {
+	Day   time.Weekday
+	Start int
+	End   int
+}
+
+// Duration is the slot's nominal length, ignoring DST transitions.
+func (s Slot) Duration() time.Duration {
+	end := s.End
+	if end <= s.Start {
+		end += minutesPerDay
+	}
+	return time.Duration(end-s.Start) * time.Minute
+}
+
+// String renders the slot as in weekly_schedule, e.g. "09:00-12:00".
+func (s Slot) String() string {
+	return FormatClock(s.Start) + "-" + FormatClock(s.End)
+}
+
+// Schedule is a user's weekly availability in their time zone.
+type Schedule struct {
+	Location *time.Location
+	Slots    []Slot
+}
+
+// NewSchedule validates slots against an IANA time zone name.
+func NewSchedule(timezone string, slots []Slot) (Schedule, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "" {
+		return Schedule{}, fmt.Errorf("%w: unknown time zone %q", ErrInvalidSchedule, timezone)
+	}
+	for _, slot := range slots {
+		if slot.Day < time.Sunday || slot.Day > time.Saturday {
+			return Schedule{}, fmt.Errorf("%w: invalid weekday %d", ErrInvalidSchedule, slot.Day)
+		}
+		if slot.Start < 0 || slot.Start >= minutesPerDay || slot.End < 0 || slot.End > minutesPerDay {
+			return Schedule{}, fmt.Errorf("%w: %s on %s is out of range", ErrInvalidSchedule, slot, slot.Day)
+		}
+		if slot.Start == slot.End {
+			return Schedule{}, fmt.Errorf("%w: %s on %s is empty", ErrInvalidSchedule, slot, slot.Day)
+		}
+	}
+	sorted := append([]Slot(nil), slots...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Day != sorted[j].Day {
+			return weekdayIndex(sorted[i].Day) < weekdayIndex(sorted[j].Day)
+		}
+		return sorted[i].Start < sorted[j].Start
+	})
+	return Schedule{Location: loc, Slots: sorted}, nil
+}
+
+// ParseWeeklySchedule reads user_availability.weekly_schedule, a JSON object
+// keyed by lower-case English weekday with "HH:MM-HH:MM" ranges, e.g.
+// {"monday": ["09:00-12:00", "14:00-17:00"]}. A null schedule has no slots.
+func ParseWeeklySchedule(data []byte, timezone string) (Schedule, error) {
+	var raw map[string][]string
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return Schedule{}, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+	}
+	var slots []Slot
+	for key, ranges := range raw {
+		day, ok := ParseWeekday(key)
+		if !ok {
+			return Schedule{}, fmt.Errorf("%w: unknown weekday %q", ErrInvalidSchedule, key)
+		}
+		for _, r := range ranges {
+			start, end, ok := strings.Cut(r, "-")
+			if !ok {
+				return Schedule{}, fmt.Errorf("%w: range %q is not HH:MM-HH:MM", ErrInvalidSchedule, r)
+			}
+			slot, err := ParseSlot(day, strings.TrimSpace(start), strings.TrimSpace(end))
+			if err != nil {
+				return Schedule{}, err
+			}
+			slots = append(slots, slot)
+		}
+	}
+	return NewSchedule(timezone, slots)
+}
+
+// MarshalWeeklySchedule renders slots in the weekly_schedule format.
+func MarshalWeeklySchedule(s Schedule) ([]byte, error) {
+	raw := make(map[string][]string)
+	for _, slot := range s.Slots {
+		day := strings.ToLower(slot.Day.String())
+		raw[day] = append(raw[day], slot.String())
+	}
+	return json.Marshal(raw)
+}
+
+// ParseSlot parses a local "HH:MM" range on day. "24:00" is accepted as an end.
+func ParseSlot(day time.Weekday, start, end string) (Slot, error) {
+	from, err := ParseClock(start)
+	if err != nil || from == minutesPerDay {
+		return Slot{}, fmt.Errorf("%w: invalid start %q", ErrInvalidSchedule, start)
+	}
+	to, err := ParseClock(end)
+	if err != nil {
+		return Slot{}, fmt.Errorf("%w: invalid end %q", ErrInvalidSchedule, end)
+	}
+	if from == to {
+		return Slot{}, fmt.Errorf("%w: %s-%s is empty", ErrInvalidSchedule, start, end)
+	}
+	return Slot{Day: day, Start: from, End: to}, nil
+}
+
+// ParseClock parses "HH:MM" (24-hour) into minutes after midnight.
+func ParseClock(s string) (int, error) {
+	var h, m int
+	if len(s) != 5 || s[2] != ':' {
+		return 0, fmt.Errorf("%w: time %q is not HH:MM", ErrInvalidSchedule, s)
+	}
+	if _, err := fmt.Sscanf(s, "%02d:%02d", &h, &m); err != nil || h > 24 || m > 59 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("%w: time %q is not HH:MM", ErrInvalidSchedule, s)
+	}
+	return h*60 + m, nil
+}
+
+// FormatClock renders minutes after midnight as "HH:MM".
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// ParseWeekday accepts English weekday names in any case.
+func ParseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, day.String()) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// Between returns the schedule's occurrences within [from, to) as merged UTC
+// intervals in time order. Each occurrence is placed on its own local date, so
+// a slot keeps its wall-clock times across a DST change and may be an hour
+// shorter or longer on the night of the change.
+func (s Schedule) Between(from, to time.Time) []Interval {
+	if s.Location == nil || !to.After(from) {
+		return nil
+	}
+	// Slots of the previous local day can spill past midnight, and the local
+	// date at from can be a day either side of the UTC date.
+	first := from.In(s.Location).AddDate(0, 0, -2)
+	last := to.In(s.Location).AddDate(0, 0, 1)
+
+	var out []Interval
+	for date := dateOf(first); !date.After(last); date = date.AddDate(0, 0, 1) {
+		for _, slot := range s.Slots {
+			if slot.Day != date.Weekday() {
+				continue
+			}
+			start := atMinute(date, slot.Start, s.Location)
+			endDate := date
+			if slot.End <= slot.Start {
+				endDate = date.AddDate(0, 0, 1)
+			}
+			end := atMinute(endDate, slot.End, s.Location)
+			if interval, ok := (Interval{Start: start.UTC(), End: end.UTC()}).clip(from, to); ok {
+				out = append(out, interval)
+			}
+		}
+	}
+	return merge(out)
+}
+
+// UTC expresses the schedule as weekly UTC slots for the week starting at
+// from. Ranges crossing midnight UTC are split at it.
+func (s Schedule) UTC(from time.Time) Schedule {
+	utc := Schedule{Location: time.UTC}
+	for _, interval := range s.Between(from, from.Add(Week)) {
+		for start := interval.Start; start.Before(interval.End); {
+			midnight := dateOf(start).AddDate(0, 0, 1)
+			end := interval.End
+			if midnight.Before(end) {
+				end = midnight
+			}
+			slot := Slot{Day: start.Weekday(), Start: minuteOfDay(start), End: minuteOfDay(end)}
+			if slot.End == 0 {
+				slot.End = minutesPerDay
+			}
+			utc.Slots = append(utc.Slots, slot)
+			start = end
+		}
+	}
+	sort.Slice(utc.Slots, func(i, j int) bool {
+		if utc.Slots[i].Day != utc.Slots[j].Day {
+			return weekdayIndex(utc.Slots[i].Day) < weekdayIndex(utc.Slots[j].Day)
+		}
+		return utc.Slots[i].Start < utc.Slots[j].Start
+	})
+	// A slot cut by the window's edges comes back as two touching pieces.
+	merged := utc.Slots[:0]
+	for _, slot := range utc.Slots {
+		if n := len(merged); n > 0 && merged[n-1].Day == slot.Day && merged[n-1].End == slot.Start {
+			merged[n-1].End = slot.End
+			continue
+		}
+		merged = append(merged, slot)
+	}
+	utc.Slots = merged
+	return utc
+}
+
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// atMinute returns minute m of date's local day. Wall-clock times skipped by
+// a DST change resolve to the instant just after the gap.
+func atMinute(date time.Time, m int, loc *time.Location) time.Time {
+	y, mo, d := date.Date()
+	return time.Date(y, mo, d, m/60, m%60, 0, 0, loc)
+}
+
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+// weekdayIndex orders Monday first, like the proto DayOfWeek enum.
+func weekdayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
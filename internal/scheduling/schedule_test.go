@@ -0,0 +1,140 @@
+package scheduling
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func mustSchedule(t *testing.T, timezone, weekly string) Schedule {
+	t.Helper()
+	s, err := ParseWeeklySchedule([]byte(weekly), timezone)
+	if err != nil {
+		t.Fatalf("ParseWeeklySchedule(%s): %v", weekly, err)
+	}
+	return s
+}
+
+func monday(year int, month time.Month, day int) time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if t.Weekday() != time.Monday {
+		panic(t.Weekday())
+	}
+	return t
+}
+
+func TestWeeklyOverlap_FollowsDST(t *testing.T) {
+	lisbon := mustSchedule(t, "Europe/Lisbon", `{"monday": ["09:00-10:00", "14:00-18:00"]}`)
+	newYork := mustSchedule(t, "America/New_York", `{"Monday": ["05:00-06:00", "10:00-12:00"]}`)
+
+	for _, tc := range []struct {
+		name string
+		week time.Time
+		want int
+	}{
+		// Lisbon UTC+0, New York UTC-5: only the afternoon overlaps.
+		{"winter", monday(2026, time.January, 12), 120},
+		// New York moved to UTC-4 on March 8, Lisbon only on March 29, so
+		// New York's 05:00 lines up with Lisbon's 09:00 for three weeks.
+		{"between transitions", monday(2026, time.March, 16), 180},
+		// Both on summer time: back to the winter offsets.
+		{"summer", monday(2026, time.June, 8), 120},
+	} {
+		overlap := WeeklyOverlap(lisbon, newYork, tc.week)
+		if overlap.Minutes() != tc.want {
+			t.Errorf("%s: overlap = %d minutes (%v), want %d", tc.name, overlap.Minutes(), overlap.Intervals, tc.want)
+		}
+	}
+}
+
+func TestBetween_DSTNights(t *testing.T) {
+	berlin := mustSchedule(t, "Europe/Berlin", `{"sunday": ["01:00-04:00"]}`)
+
+	for _, tc := range []struct {
+		week time.Time
+		want time.Duration
+	}{
+		{monday(2026, time.March, 23), 2 * time.Hour},   // clocks skip 02:00-03:00 on the 29th
+		{monday(2026, time.October, 19), 4 * time.Hour}, // 02:00-03:00 happens twice on the 25th
+		{monday(2026, time.July, 6), 3 * time.Hour},
+	} {
+		intervals := berlin.Between(tc.week, tc.week.Add(Week))
+		if len(intervals) != 1 || intervals[0].Duration() != tc.want {
+			t.Errorf("week of %s: %v, want one interval of %v", tc.week.Format(time.DateOnly), intervals, tc.want)
+		}
+	}
+}
+
+func TestUTC_WrapsAcrossMidnightAndWeek(t *testing.T) {
+	tokyo := mustSchedule(t, "Asia/Tokyo", `{"monday": ["01:00-03:00"], "friday": ["23:00-01:00"]}`)
+
+	got := tokyo.UTC(monday(2026, time.May, 4)).Slots
+	want := []Slot{
+		{Day: time.Friday, Start: 14 * 60, End: 16 * 60},
+		{Day: time.Sunday, Start: 16 * 60, End: 18 * 60},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("UTC slots = %v, want %v", got, want)
+	}
+
+	// A window that does not start at midnight still sees each slot once.
+	if got := tokyo.UTC(monday(2026, time.May, 4).Add(15 * time.Hour)).Slots; !slices.Equal(got, want) {
+		t.Errorf("offset window = %v, want %v", got, want)
+	}
+}
+
+func TestOverlap_LongestAndNext(t *testing.T) {
+	a := mustSchedule(t, "UTC", `{"tuesday": ["18:00-21:00"], "saturday": ["10:00-11:00", "15:00-19:00"]}`)
+	b := mustSchedule(t, "UTC", `{"tuesday": ["19:00-22:00"], "saturday": ["09:00-16:00"]}`)
+	week := monday(2026, time.May, 4)
+
+	overlap := WeeklyOverlap(a, b, week)
+	if overlap.Minutes() != 240 {
+		t.Fatalf("overlap = %v", overlap)
+	}
+	longest := overlap.Longest(2)
+	if len(longest) != 2 || longest[0].Duration() != 2*time.Hour || longest[1].Duration() != time.Hour ||
+		longest[1].Start.Weekday() != time.Saturday || longest[1].Start.Hour() != 10 {
+		t.Errorf("longest = %v", longest)
+	}
+	next, ok := overlap.Next(week.AddDate(0, 0, 2))
+	if !ok || next.Start.Weekday() != time.Saturday || next.Start.Hour() != 10 {
+		t.Errorf("next = %v, %v", next, ok)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, weekly := range []string{
+		`{"funday": ["09:00-10:00"]}`,
+		`{"monday": ["9:00-10:00"]}`,
+		`{"monday": ["09:00-09:00"]}`,
+		`{"monday": ["24:00-01:00"]}`,
+		`{"monday": ["09:00"]}`,
+		`[]`,
+	} {
+		if _, err := ParseWeeklySchedule([]byte(weekly), "UTC"); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("%s: err = %v", weekly, err)
+		}
+	}
+	if _, err := ParseWeeklySchedule([]byte(`{}`), "Mars/Olympus"); !errors.Is(err, ErrInvalidSchedule) {
+		t.Errorf("unknown zone: %v", err)
+	}
+	if s, err := ParseWeeklySchedule([]byte(`null`), "UTC"); err != nil || len(s.Slots) != 0 {
+		t.Errorf("null = %v, %v", s, err)
+	}
+
+	s := mustSchedule(t, "Europe/Lisbon", `{"sunday": ["20:00-24:00"], "monday": ["22:00-02:00"]}`)
+	if d := s.Slots[0].Duration(); d != 4*time.Hour {
+		t.Errorf("overnight duration = %v", d)
+	}
+	data, err := MarshalWeeklySchedule(s)
+	if err != nil || string(data) != `{"monday":["22:00-02:00"],"sunday":["20:00-24:00"]}` {
+		t.Errorf("marshal = %s, %v", data, err)
+	}
+
+	back, err := FromProto(ToProto(s))
+	if err != nil || !slices.Equal(back.Slots, s.Slots) || back.Location.String() != "Europe/Lisbon" {
+		t.Errorf("proto round trip = %+v, %v", back, err)
+	}
+}
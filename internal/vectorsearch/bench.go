@@ -0,0 +1,98 @@
+package vectorsearch
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Query is one benchmark search.
+type Query struct {
+	Vector []float32
+	Filter Filter
+}
+
+// Report is how an index fared against exact search on the same queries.
+type Report struct {
+	Name    string
+	Queries int
+	K       int
+	// Recall is the mean share of the true k nearest neighbours returned.
+	Recall float64
+	Mean   time.Duration
+	P50    time.Duration
+	P95    time.Duration
+	P99    time.Duration
+}
+
+// String renders the report as one table row.
+func (r Report) String() string {
+	return fmt.Sprintf("%-24s recall@%d=%.3f  mean=%-10v p50=%-10v p95=%-10v p99=%v",
+		r.Name, r.K, r.Recall, r.Mean.Round(time.Microsecond), r.P50.Round(time.Microsecond),
+		r.P95.Round(time.Microsecond), r.P99.Round(time.Microsecond))
+}
+
+// GroundTruth runs the queries against an exact index.
+func GroundTruth(ctx context.Context, exact Index, queries []Query, k int) ([][]uuid.UUID, error) {
+	truth := make([][]uuid.UUID, len(queries))
+	for i, q := range queries {
+		results, err := exact.Search(ctx, q.Vector, k, q.Filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			truth[i] = append(truth[i], r.ID)
+		}
+	}
+	return truth, nil
+}
+
+// Evaluate times the queries against index and scores its recall against
+// truth, as returned by GroundTruth. Queries with no true neighbours do not
+// count towards recall.
+func Evaluate(ctx context.Context, name string, index Index, queries []Query, truth [][]uuid.UUID, k int) (Report, error) {
+	report := Report{Name: name, Queries: len(queries), K: k}
+	latencies := make([]time.Duration, 0, len(queries))
+	var recall float64
+	var scored int
+	for i, q := range queries {
+		start := time.Now()
+		results, err := index.Search(ctx, q.Vector, k, q.Filter)
+		latencies = append(latencies, time.Since(start))
+		if err != nil {
+			return report, fmt.Errorf("%s: %w", name, err)
+		}
+		if len(truth[i]) == 0 {
+			continue
+		}
+		var hits int
+		for _, r := range results {
+			if slices.Contains(truth[i], r.ID) {
+				hits++
+			}
+		}
+		recall += float64(hits) / float64(len(truth[i]))
+		scored++
+	}
+	if scored > 0 {
+		report.Recall = recall / float64(scored)
+	}
+	if len(latencies) == 0 {
+		return report, nil
+	}
+
+	slices.Sort(latencies)
+	var total time.Duration
+	for _, d := range latencies {
+		total += d
+	}
+	report.Mean = total / time.Duration(len(latencies))
+	percentile := func(p float64) time.Duration {
+		return latencies[min(len(latencies)-1, int(p*float64(len(latencies))))]
+	}
+	report.P50, report.P95, report.P99 = percentile(0.50), percentile(0.95), percentile(0.99)
+	return report, nil
+}
@@ -0,0 +1,333 @@
+package vectorsearch
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// HNSWConfig tunes the graph; zero fields take DefaultHNSWConfig's values.
+type HNSWConfig struct {
+	// M is the number of links kept per node on the upper layers; the bottom
+	// layer keeps 2M.
+	M int
+	// EfConstruction is the candidate list size while inserting. Larger
+	// values build a better graph more slowly.
+	EfConstruction int
+	// EfSearch is the candidate list size while searching, raised to k.
+	// Larger values trade latency for recall.
+	EfSearch int
+	// Seed makes level assignment, and so the graph, reproducible.
+	Seed uint64
+}
+
+// DefaultHNSWConfig suits up to a few hundred thousand 768-dimensional vectors.
+func DefaultHNSWConfig() HNSWConfig {
+	return HNSWConfig{M: 16, EfConstruction: 200, EfSearch: 64}
+}
+
+// node is a stored vector. Removed nodes stay in the graph, still linking
+// their neighbours, until the graph is rebuilt.
+type node struct {
+	id       uuid.UUID
+	vector   []float32
+	norm     float64
+	inactive bool
+	removed  bool
+
+	// index is the node's position in hnsw.nodes.
+	index int
+	// friends holds the node's links on each layer it is on.
+	friends [][]*node
+}
+
+// hnsw is a hierarchical navigable small world graph (Malkov and Yashunin,
+// 2016). Each node is on layer 0 and, with exponentially falling
+// probability, on higher layers; searches descend greedily from the sparse
+// top layer and widen into a best-first search on layer 0.
+type hnsw struct {
+	metric    Metric
+	cfg       HNSWConfig
+	levelMult float64
+	rng       *rand.Rand
+
+	nodes    []*node
+	entry    *node
+	maxLevel int
+	removed  int
+}
+
+func newHNSW(metric Metric, cfg HNSWConfig) *hnsw {
+	defaults := DefaultHNSWConfig()
+	if cfg.M < 2 {
+		cfg.M = defaults.M
+	}
+	if cfg.EfConstruction <= 0 {
+		cfg.EfConstruction = defaults.EfConstruction
+	}
+	if cfg.EfSearch <= 0 {
+		cfg.EfSearch = defaults.EfSearch
+	}
+	return &hnsw{
+		metric:    metric,
+		cfg:       cfg,
+		levelMult: 1 / math.Log(float64(cfg.M)),
+		rng:       rand.New(rand.NewPCG(cfg.Seed, cfg.Seed)),
+	}
+}
+
+func (h *hnsw) maxFriends(level int) int {
+	if level == 0 {
+		return 2 * h.cfg.M
+	}
+	return h.cfg.M
+}
+
+func (h *hnsw) insert(n *node) {
+	level := int(-math.Log(1-h.rng.Float64()) * h.levelMult)
+	n.index = len(h.nodes)
+	n.friends = make([][]*node, level+1)
+	h.nodes = append(h.nodes, n)
+	if h.entry == nil {
+		h.entry, h.maxLevel = n, level
+		return
+	}
+
+	entry := []candidate{{node: h.entry, dist: h.distance(n.vector, n.norm, h.entry)}}
+	for l := h.maxLevel; l > level; l-- {
+		entry = h.searchLayer(n.vector, n.norm, entry, 1, l, nil)
+	}
+	live := func(c *node) bool { return !c.removed }
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		found := h.searchLayer(n.vector, n.norm, entry, h.cfg.EfConstruction, l, live)
+		for _, c := range h.selectFriends(found, h.cfg.M) {
+			n.friends[l] = append(n.friends[l], c.node)
+			c.node.friends[l] = append(c.node.friends[l], n)
+			if len(c.node.friends[l]) > h.maxFriends(l) {
+				h.prune(c.node, l)
+			}
+		}
+		if len(found) > 0 {
+			entry = found
+		}
+	}
+	if level > h.maxLevel {
+		h.entry, h.maxLevel = n, level
+	}
+}
+
+// prune cuts a node's links on a layer back to the layer's maximum.
+func (h *hnsw) prune(n *node, level int) {
+	friends := make([]candidate, 0, len(n.friends[level]))
+	for _, f := range n.friends[level] {
+		if !f.removed {
+			friends = append(friends, candidate{node: f, dist: h.distance(n.vector, n.norm, f)})
+		}
+	}
+	slices.SortFunc(friends, byDistance)
+	kept := h.selectFriends(friends, h.maxFriends(level))
+	n.friends[level] = n.friends[level][:0]
+	for _, c := range kept {
+		n.friends[level] = append(n.friends[level], c.node)
+	}
+}
+
+// selectFriends picks up to m links from candidates sorted by distance,
+// preferring ones not already closer to a picked link than to the base node
+// so links spread in every direction, then topping up with the nearest.
+func (h *hnsw) selectFriends(candidates []candidate, m int) []candidate {
+	if len(candidates) <= m {
+		return candidates
+	}
+	picked := make([]candidate, 0, m)
+	skipped := make([]candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if len(picked) == m {
+			break
+		}
+		diverse := true
+		for _, p := range picked {
+			if h.distance(c.node.vector, c.node.norm, p.node) < c.dist {
+				diverse = false
+				break
+			}
+		}
+		if diverse {
+			picked = append(picked, c)
+		} else {
+			skipped = append(skipped, c)
+		}
+	}
+	for _, c := range skipped {
+		if len(picked) == m {
+			break
+		}
+		picked = append(picked, c)
+	}
+	return picked
+}
+
+// search returns the k nearest nodes that allow accepts.
+func (h *hnsw) search(query []float32, k int, allow func(*node) bool) []candidate {
+	if h.entry == nil {
+		return nil
+	}
+	qnorm := norm(query)
+	entry := []candidate{{node: h.entry, dist: h.distance(query, qnorm, h.entry)}}
+	for l := h.maxLevel; l > 0; l-- {
+		entry = h.searchLayer(query, qnorm, entry, 1, l, nil)
+	}
+	found := h.searchLayer(query, qnorm, entry, max(h.cfg.EfSearch, k), 0, allow)
+	return found[:min(k, len(found))]
+}
+
+// searchLayer is a best-first search of one layer from the entry points,
+// returning up to ef accepted nodes sorted by distance. Rejected nodes are
+// still walked through, so filters narrow the results without cutting the
+// graph apart.
+func (h *hnsw) searchLayer(query []float32, qnorm float64, entry []candidate, ef, level int, allow func(*node) bool) []candidate {
+	visited := make([]uint64, (len(h.nodes)+63)/64)
+	visit := func(n *node) bool {
+		word, bit := n.index/64, uint64(1)<<(n.index%64)
+		seen := visited[word]&bit != 0
+		visited[word] |= bit
+		return !seen
+	}
+
+	next := queue{}
+	nearest := queue{max: true}
+	for _, c := range entry {
+		if visit(c.node) {
+			next.push(c)
+			if allow == nil || allow(c.node) {
+				nearest.push(c)
+			}
+		}
+	}
+	for next.len() > 0 {
+		c := next.pop()
+		if nearest.len() >= ef && c.dist > nearest.top().dist {
+			break
+		}
+		if level >= len(c.node.friends) {
+			continue
+		}
+		for _, f := range c.node.friends[level] {
+			if !visit(f) {
+				continue
+			}
+			d := h.distance(query, qnorm, f)
+			if nearest.len() < ef || d < nearest.top().dist {
+				next.push(candidate{node: f, dist: d})
+				if allow == nil || allow(f) {
+					nearest.push(candidate{node: f, dist: d})
+					if nearest.len() > ef {
+						nearest.pop()
+					}
+				}
+			}
+		}
+	}
+	return nearest.sorted()
+}
+
+// rebuild drops removed nodes by inserting the live ones into a new graph.
+func (h *hnsw) rebuild() {
+	nodes := h.nodes
+	h.nodes, h.entry, h.maxLevel, h.removed = nil, nil, 0, 0
+	for _, n := range nodes {
+		if !n.removed {
+			h.insert(n)
+		}
+	}
+}
+
+func (h *hnsw) distance(query []float32, qnorm float64, n *node) float64 {
+	return distance(h.metric, query, qnorm, n)
+}
+
+// distance is Metric.Distance with the norms computed once.
+func distance(metric Metric, query []float32, qnorm float64, n *node) float64 {
+	if metric == Cosine {
+		return cosineDistance(dot(query, n.vector), qnorm, n.norm)
+	}
+	return metric.Distance(query, n.vector)
+}
+
+type candidate struct {
+	node *node
+	dist float64
+}
+
+func byDistance(a, b candidate) int {
+	switch {
+	case a.dist < b.dist:
+		return -1
+	case a.dist > b.dist:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// queue is a binary heap of candidates, nearest first or, with max set,
+// furthest first.
+type queue struct {
+	items []candidate
+	max   bool
+}
+
+func (q *queue) len() int { return len(q.items) }
+
+func (q *queue) top() candidate { return q.items[0] }
+
+func (q *queue) less(i, j int) bool {
+	if q.max {
+		return q.items[i].dist > q.items[j].dist
+	}
+	return q.items[i].dist < q.items[j].dist
+}
+
+func (q *queue) push(c candidate) {
+	q.items = append(q.items, c)
+	for i := len(q.items) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if !q.less(i, parent) {
+			break
+		}
+		q.items[i], q.items[parent] = q.items[parent], q.items[i]
+		i = parent
+	}
+}
+
+func (q *queue) pop() candidate {
+	top := q.items[0]
+	last := len(q.items) - 1
+	q.items[0] = q.items[last]
+	q.items = q.items[:last]
+	for i := 0; ; {
+		child := 2*i + 1
+		if child >= last {
+			break
+		}
+		if child+1 < last && q.less(child+1, child) {
+			child++
+		}
+		if !q.less(child, i) {
+			break
+		}
+		q.items[i], q.items[child] = q.items[child], q.items[i]
+		i = child
+	}
+	return top
+}
+
+// sorted returns the candidates nearest first, emptying the queue.
+func (q *queue) sorted() []candidate {
+	out := q.items
+	q.items = nil
+	slices.SortFunc(out, byDistance)
+	return out
+}
@@ -0,0 +1,193 @@
+package vectorsearch
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/embedding"
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+)
+
+// loadBatch is how many embeddings are fetched per query.
+const loadBatch = 1000
+
+// Loader keeps a Memory index in step with a table's embeddings. Each pass
+// reads the cheap row state first and only fetches vectors whose
+// embedding_hash changed, so an unchanged table costs one small scan.
+type Loader struct {
+	db     *db.SQL
+	index  *Memory
+	target embedding.Target
+	logger *slog.Logger
+	// hashes are the embedding hashes of the loaded vectors.
+	hashes map[uuid.UUID]string
+}
+
+// NewLoader feeds index from target, reading from a replica when one is
+// available.
+func NewLoader(db *db.SQL, index *Memory, target embedding.Target, logger *slog.Logger) *Loader {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	return &Loader{db: db, index: index, target: target, logger: logger, hashes: make(map[uuid.UUID]string)}
+}
+
+// Run loads once per interval until ctx is cancelled.
+func (l *Loader) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := l.Load(ctx); err != nil && ctx.Err() == nil {
+			l.logger.Error("vector index load failed", "target", l.target, "error", err)
+		} else if n > 0 {
+			l.logger.Info("vector index loaded", "target", l.target, "vectors", n, "size", l.index.Len())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// stateQueries list every row with an embedding. Rows embedded before
+// embedding_hash existed are identified by their vector instead.
+var stateQueries = map[embedding.Target]string{
+	embedding.TargetUsers: `
+		-- name: UserEmbeddingState
+		SELECT id, COALESCE(embedding_hash, md5(embedding::text)), is_active AND deleted_at IS NULL
+		FROM users
+		WHERE embedding IS NOT NULL
+	`,
+	embedding.TargetSkills: `
+		-- name: SkillEmbeddingState
+		SELECT id, COALESCE(embedding_hash, md5(embedding::text)), true
+		FROM skills
+		WHERE embedding IS NOT NULL
+	`,
+}
+
+// Load brings the index up to date and reports how many vectors it fetched.
+func (l *Loader) Load(ctx context.Context) (int, error) {
+	if l == nil || l.db == nil || l.index == nil {
+		return 0, errors.New("vector loader uninitialized")
+	}
+	query, ok := stateQueries[l.target]
+	if !ok {
+		return 0, fmt.Errorf("unknown embedding target %q", l.target)
+	}
+	reader := l.db.Read(ctx)
+	rows, err := reader.QueryContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("load %s embedding state: %w", l.target, err)
+	}
+	hashes := make(map[uuid.UUID]string)
+	active := make(map[uuid.UUID]bool)
+	var changed []string
+	for rows.Next() {
+		var id uuid.UUID
+		var hash string
+		var isActive bool
+		if err := rows.Scan(&id, &hash, &isActive); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		hashes[id], active[id] = hash, isActive
+		if l.hashes[id] != hash {
+			changed = append(changed, id.String())
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for id := range l.hashes {
+		if _, ok := hashes[id]; !ok {
+			l.index.Remove(id)
+			delete(l.hashes, id)
+		}
+	}
+	var loaded int
+	for start := 0; start < len(changed); start += loadBatch {
+		n, err := l.fetch(ctx, reader, changed[start:min(start+loadBatch, len(changed))], hashes, active)
+		loaded += n
+		if err != nil {
+			return loaded, err
+		}
+	}
+	for id, isActive := range active {
+		l.index.SetInactive(id, !isActive)
+	}
+
+	if l.target == embedding.TargetUsers {
+		blocks, err := l.blocks(ctx, reader)
+		if err != nil {
+			return loaded, err
+		}
+		l.index.SetBlocks(blocks)
+	}
+	return loaded, nil
+}
+
+// fetch loads the vectors of ids into the index. The target is a fixed table
+// name, never user input.
+func (l *Loader) fetch(ctx context.Context, reader *sql.DB, ids []string, hashes map[uuid.UUID]string, active map[uuid.UUID]bool) (int, error) {
+	query := fmt.Sprintf(`
+		-- name: LoadEmbeddings
+		SELECT id, embedding::text FROM %s WHERE id = ANY($1::uuid[]) AND embedding IS NOT NULL
+	`, l.target)
+	rows, err := reader.QueryContext(ctx, query, ids)
+	if err != nil {
+		return 0, fmt.Errorf("load %s embeddings: %w", l.target, err)
+	}
+	defer rows.Close()
+
+	var loaded int
+	for rows.Next() {
+		var id uuid.UUID
+		var text string
+		if err := rows.Scan(&id, &text); err != nil {
+			return loaded, err
+		}
+		vector, err := parseVector(text)
+		if err != nil {
+			return loaded, fmt.Errorf("%s %s: %w", l.target, id, err)
+		}
+		if err := l.index.Put(Item{ID: id, Vector: vector, Inactive: !active[id]}); err != nil {
+			return loaded, fmt.Errorf("%s %s: %w", l.target, id, err)
+		}
+		l.hashes[id] = hashes[id]
+		loaded++
+	}
+	return loaded, rows.Err()
+}
+
+func (l *Loader) blocks(ctx context.Context, reader *sql.DB) ([]Block, error) {
+	rows, err := reader.QueryContext(ctx, `
+		-- name: LoadUserBlocks
+		SELECT blocker_id, blocked_id FROM user_blocks
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("load user blocks: %w", err)
+	}
+	defer rows.Close()
+
+	var blocks []Block
+	for rows.Next() {
+		var b Block
+		if err := rows.Scan(&b.Blocker, &b.Blocked); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, rows.Err()
+}
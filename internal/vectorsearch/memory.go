@@ -0,0 +1,230 @@
+package vectorsearch
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Item is a row stored in a Memory index.
+type Item struct {
+	ID     uuid.UUID
+	Vector []float32
+	// Inactive rows are skipped unless Filter.IncludeInactive is set.
+	Inactive bool
+}
+
+// Block records that Blocker blocked Blocked. Blocks hide users from each
+// other in both directions.
+type Block struct {
+	Blocker uuid.UUID
+	Blocked uuid.UUID
+}
+
+// Memory is an Index held in process memory, searched through an HNSW graph
+// or, when built by NewExact, by comparing the query with every vector. It is
+// safe for concurrent use.
+type Memory struct {
+	metric Metric
+
+	mu     sync.RWMutex
+	dims   int
+	items  map[uuid.UUID]*node
+	blocks map[uuid.UUID]map[uuid.UUID]struct{}
+	graph  *hnsw
+}
+
+// NewHNSW builds an empty approximate index.
+func NewHNSW(metric Metric, cfg HNSWConfig) *Memory {
+	m := NewExact(metric)
+	m.graph = newHNSW(metric, cfg)
+	return m
+}
+
+// NewExact builds an empty index that scans every vector, which is exact and
+// serves as the ground truth for benchmarks.
+func NewExact(metric Metric) *Memory {
+	return &Memory{
+		metric: metric,
+		items:  make(map[uuid.UUID]*node),
+		blocks: make(map[uuid.UUID]map[uuid.UUID]struct{}),
+	}
+}
+
+// Metric implements Index.
+func (m *Memory) Metric() Metric {
+	return m.metric
+}
+
+// Len returns the number of stored vectors.
+func (m *Memory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.items)
+}
+
+// IDs returns the ids of the stored vectors in no particular order.
+func (m *Memory) IDs() []uuid.UUID {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ids := make([]uuid.UUID, 0, len(m.items))
+	for id := range m.items {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Put stores or replaces a vector. All vectors must have the same length.
+func (m *Memory) Put(item Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.dims == 0 {
+		m.dims = len(item.Vector)
+	}
+	if len(item.Vector) != m.dims || m.dims == 0 {
+		return ErrDimensions
+	}
+	if old, ok := m.items[item.ID]; ok {
+		m.remove(old)
+	}
+	n := &node{id: item.ID, vector: slices.Clone(item.Vector), inactive: item.Inactive}
+	n.norm = norm(n.vector)
+	m.items[item.ID] = n
+	if m.graph != nil {
+		m.graph.insert(n)
+	}
+	return nil
+}
+
+// SetInactive flags a stored row without re-inserting its vector.
+func (m *Memory) SetInactive(id uuid.UUID, inactive bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n, ok := m.items[id]; ok {
+		n.inactive = inactive
+	}
+}
+
+// Remove drops a vector; unknown ids are ignored.
+func (m *Memory) Remove(id uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n, ok := m.items[id]; ok {
+		m.remove(n)
+	}
+}
+
+func (m *Memory) remove(n *node) {
+	delete(m.items, n.id)
+	n.removed = true
+	if m.graph != nil {
+		m.graph.removed++
+		if m.graph.removed > len(m.graph.nodes)/2 {
+			m.graph.rebuild()
+		}
+	}
+}
+
+// SetBlocks replaces the known blocks.
+func (m *Memory) SetBlocks(blocks []Block) {
+	index := make(map[uuid.UUID]map[uuid.UUID]struct{}, len(blocks))
+	link := func(a, b uuid.UUID) {
+		if index[a] == nil {
+			index[a] = make(map[uuid.UUID]struct{})
+		}
+		index[a][b] = struct{}{}
+	}
+	for _, b := range blocks {
+		link(b.Blocker, b.Blocked)
+		link(b.Blocked, b.Blocker)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blocks = index
+}
+
+// SetEfSearch changes the candidate list size of later searches; it has no
+// effect on exact indexes.
+func (m *Memory) SetEfSearch(ef int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.graph != nil && ef > 0 {
+		m.graph.cfg.EfSearch = ef
+	}
+}
+
+// Search implements Index.
+func (m *Memory) Search(ctx context.Context, query []float32, k int, filter Filter) ([]Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if k <= 0 || len(m.items) == 0 {
+		return nil, nil
+	}
+	if len(query) != m.dims {
+		return nil, ErrDimensions
+	}
+
+	allow := m.allows(filter)
+	var found []candidate
+	if m.graph != nil {
+		found = m.graph.search(query, k, allow)
+	} else {
+		found = m.scan(query, k, allow)
+	}
+	results := make([]Result, len(found))
+	for i, c := range found {
+		results[i] = Result{ID: c.node.id, Distance: c.dist}
+	}
+	return results, nil
+}
+
+// Vector implements Index.
+func (m *Memory) Vector(_ context.Context, id uuid.UUID) ([]float32, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	n, ok := m.items[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return slices.Clone(n.vector), nil
+}
+
+func (m *Memory) allows(filter Filter) func(*node) bool {
+	blocked := m.blocks[filter.Viewer]
+	return func(n *node) bool {
+		if n.removed || (n.inactive && !filter.IncludeInactive) {
+			return false
+		}
+		if _, ok := blocked[n.id]; ok {
+			return false
+		}
+		return !slices.Contains(filter.Exclude, n.id)
+	}
+}
+
+// scan compares the query with every vector, keeping the k nearest.
+func (m *Memory) scan(query []float32, k int, allow func(*node) bool) []candidate {
+	qnorm := norm(query)
+	nearest := queue{max: true}
+	for _, n := range m.items {
+		if !allow(n) {
+			continue
+		}
+		d := distance(m.metric, query, qnorm, n)
+		if nearest.len() < k {
+			nearest.push(candidate{node: n, dist: d})
+		} else if d < nearest.top().dist {
+			nearest.pop()
+			nearest.push(candidate{node: n, dist: d})
+		}
+	}
+	return nearest.sorted()
+}
@@ -0,0 +1,173 @@
+package vectorsearch
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/embedding"
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+)
+
+// operators maps metrics to pgvector distance operators.
+var operators = map[Metric]string{
+	Cosine:       "<=>",
+	L2:           "<->",
+	InnerProduct: "<#>",
+}
+
+// Postgres searches a table's embedding column with pgvector. The migrations
+// index users.embedding and skills.embedding with vector_cosine_ops, so the
+// other metrics scan the table unless matching indexes are added.
+type Postgres struct {
+	db     *db.SQL
+	target embedding.Target
+	metric Metric
+	probes int
+}
+
+// NewPostgres searches target, on a replica when one is available. probes
+// sets ivfflat.probes for each query; zero keeps the server's setting.
+func NewPostgres(db *db.SQL, target embedding.Target, metric Metric, probes int) (*Postgres, error) {
+	if _, ok := operators[metric]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMetric, metric)
+	}
+	if target != embedding.TargetSkills && target != embedding.TargetUsers {
+		return nil, fmt.Errorf("unknown embedding target %q", target)
+	}
+	return &Postgres{db: db, target: target, metric: metric, probes: probes}, nil
+}
+
+// Metric implements Index.
+func (p *Postgres) Metric() Metric {
+	return p.metric
+}
+
+// searchQueries hold a %s for the distance operator. Filters are applied to
+// the rows the index scan yields, so a filter rejecting most rows near the
+// query can return fewer than k rows; raising probes compensates.
+var searchQueries = map[embedding.Target]string{
+	embedding.TargetUsers: `
+		-- name: SearchUserEmbeddings
+		SELECT u.id, u.embedding %[1]s $1::vector AS distance
+		FROM users u
+		WHERE u.embedding IS NOT NULL AND u.deleted_at IS NULL
+			AND u.id <> ALL($3::uuid[])
+			AND ($4 OR u.is_active)
+			AND ($5::uuid IS NULL OR NOT EXISTS (
+				SELECT 1 FROM user_blocks b
+				WHERE (b.blocker_id = $5 AND b.blocked_id = u.id)
+				   OR (b.blocker_id = u.id AND b.blocked_id = $5)
+			))
+		ORDER BY u.embedding %[1]s $1::vector
+		LIMIT $2
+	`,
+	embedding.TargetSkills: `
+		-- name: SearchSkillEmbeddings
+		SELECT s.id, s.embedding %[1]s $1::vector AS distance
+		FROM skills s
+		WHERE s.embedding IS NOT NULL AND s.id <> ALL($3::uuid[])
+		ORDER BY s.embedding %[1]s $1::vector
+		LIMIT $2
+	`,
+}
+
+// Search implements Index. Skills have no owner or activity, so only
+// Filter.Exclude applies to them.
+func (p *Postgres) Search(ctx context.Context, query []float32, k int, filter Filter) ([]Result, error) {
+	if len(query) != embedding.Dimensions {
+		return nil, ErrDimensions
+	}
+	if k <= 0 {
+		return nil, nil
+	}
+	var viewer *string
+	if filter.Viewer != uuid.Nil {
+		v := filter.Viewer.String()
+		viewer = &v
+	}
+	exclude := make([]string, len(filter.Exclude))
+	for i, id := range filter.Exclude {
+		exclude[i] = id.String()
+	}
+
+	tx, err := p.db.Read(ctx).BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if p.probes > 0 {
+		// is_local scopes the setting to this transaction.
+		if _, err := tx.ExecContext(ctx, `SELECT set_config('ivfflat.probes', $1, true)`, strconv.Itoa(p.probes)); err != nil {
+			return nil, fmt.Errorf("set ivfflat.probes: %w", err)
+		}
+	}
+
+	args := []any{vectorLiteral(query), k, exclude}
+	if p.target == embedding.TargetUsers {
+		args = append(args, filter.IncludeInactive, viewer)
+	}
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(searchQueries[p.target], operators[p.metric]), args...)
+	if err != nil {
+		return nil, fmt.Errorf("search %s embeddings: %w", p.target, err)
+	}
+	defer rows.Close()
+
+	var results []Result
+	for rows.Next() {
+		var r Result
+		if err := rows.Scan(&r.ID, &r.Distance); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, tx.Commit()
+}
+
+// Vector implements Index. The target is a fixed table name, never user input.
+func (p *Postgres) Vector(ctx context.Context, id uuid.UUID) ([]float32, error) {
+	query := fmt.Sprintf(`
+		-- name: GetEmbedding
+		SELECT embedding::text FROM %s WHERE id = $1 AND embedding IS NOT NULL
+	`, p.target)
+	var text string
+	if err := p.db.Read(ctx).QueryRowContext(ctx, query, id).Scan(&text); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("load %s embedding: %w", p.target, err)
+	}
+	return parseVector(text)
+}
+
+// parseVector reads pgvector's text format, which is a JSON array.
+func parseVector(text string) ([]float32, error) {
+	var v []float32
+	if err := json.Unmarshal([]byte(text), &v); err != nil {
+		return nil, fmt.Errorf("decode embedding: %w", err)
+	}
+	return v, nil
+}
+
+// vectorLiteral renders a vector in pgvector's text format.
+func vectorLiteral(v []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(x), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
@@ -0,0 +1,122 @@
+// Package vectorsearch finds the stored embeddings nearest to a query vector.
+//
+// Index is implemented by Postgres, which queries pgvector columns and their
+// IVFFLAT indexes, and by Memory, an HNSW graph (or an exact scan) for tests,
+// benchmarks and deployments small enough to keep every vector in memory.
+// Both apply the same Filter, so callers do not care which one they hold.
+package vectorsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// Metric is a distance between vectors; smaller is closer.
+type Metric string
+
+const (
+	// Cosine is 1 - cos(a, b), pgvector's <=> operator.
+	Cosine Metric = "cosine"
+	// L2 is the Euclidean distance, pgvector's <-> operator.
+	L2 Metric = "l2"
+	// InnerProduct is -a·b, pgvector's <#> operator.
+	InnerProduct Metric = "inner_product"
+)
+
+var (
+	ErrUnknownMetric = errors.New("unknown vector metric")
+	// ErrNotFound is returned by Index.Vector for rows without an embedding.
+	ErrNotFound = errors.New("no embedding stored")
+	// ErrDimensions is returned for vectors whose length differs from the index's.
+	ErrDimensions = errors.New("vector has the wrong number of dimensions")
+)
+
+// ParseMetric returns the metric named s.
+func ParseMetric(s string) (Metric, error) {
+	switch m := Metric(s); m {
+	case Cosine, L2, InnerProduct:
+		return m, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownMetric, s)
+	}
+}
+
+// Distance measures a and b, which must have the same length. A zero vector
+// is at cosine distance 1 from everything.
+func (m Metric) Distance(a, b []float32) float64 {
+	switch m {
+	case L2:
+		var sum float64
+		for i := range a {
+			d := float64(a[i]) - float64(b[i])
+			sum += d * d
+		}
+		return math.Sqrt(sum)
+	case InnerProduct:
+		return -dot(a, b)
+	default:
+		return cosineDistance(dot(a, b), norm(a), norm(b))
+	}
+}
+
+// Similarity turns a distance into a score that grows with similarity: the
+// cosine similarity, 1/(1+d) for L2 and the inner product itself.
+func (m Metric) Similarity(distance float64) float64 {
+	switch m {
+	case L2:
+		return 1 / (1 + distance)
+	case InnerProduct:
+		return -distance
+	default:
+		return 1 - distance
+	}
+}
+
+// Filter narrows a search. The zero Filter keeps every active row.
+type Filter struct {
+	// Exclude drops these ids, typically the searching user.
+	Exclude []uuid.UUID
+	// Viewer drops users who blocked the viewer or were blocked by them.
+	Viewer uuid.UUID
+	// IncludeInactive keeps deactivated users.
+	IncludeInactive bool
+}
+
+// Result is a row near the query.
+type Result struct {
+	ID       uuid.UUID
+	Distance float64
+}
+
+// Index answers k-nearest-neighbour queries over one table's embeddings.
+type Index interface {
+	Metric() Metric
+	// Search returns up to k rows passing filter, nearest first. Approximate
+	// indexes may miss some of the true neighbours.
+	Search(ctx context.Context, query []float32, k int, filter Filter) ([]Result, error)
+	// Vector returns the embedding stored for id, or ErrNotFound.
+	Vector(ctx context.Context, id uuid.UUID) ([]float32, error)
+}
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+func norm(v []float32) float64 {
+	return math.Sqrt(dot(v, v))
+}
+
+func cosineDistance(dot, normA, normB float64) float64 {
+	if normA == 0 || normB == 0 {
+		return 1
+	}
+	return 1 - dot/(normA*normB)
+}
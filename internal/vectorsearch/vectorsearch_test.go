@@ -0,0 +1,181 @@
+package vectorsearch
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func randomItems(n, dims int, seed uint64) []Item {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	items := make([]Item, n)
+	for i := range items {
+		v := make([]float32, dims)
+		for j := range v {
+			v[j] = float32(rng.NormFloat64())
+		}
+		items[i] = Item{ID: uuid.New(), Vector: v}
+	}
+	return items
+}
+
+func fill(t testing.TB, index *Memory, items []Item) {
+	for _, item := range items {
+		if err := index.Put(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMetric_Distance(t *testing.T) {
+	a, b := []float32{1, 0}, []float32{0, 2}
+	cases := []struct {
+		metric Metric
+		want   float64
+	}{
+		{Cosine, 1},
+		{L2, math.Sqrt(5)},
+		{InnerProduct, 0},
+	}
+	for _, c := range cases {
+		if got := c.metric.Distance(a, b); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("%s distance = %v, want %v", c.metric, got, c.want)
+		}
+	}
+	if got := Cosine.Distance([]float32{1, 1}, []float32{2, 2}); math.Abs(got) > 1e-9 {
+		t.Errorf("parallel vectors are at cosine distance %v", got)
+	}
+	if got := Cosine.Distance([]float32{0, 0}, a); got != 1 {
+		t.Errorf("zero vector is at cosine distance %v", got)
+	}
+	if _, err := ParseMetric("manhattan"); !errors.Is(err, ErrUnknownMetric) {
+		t.Errorf("ParseMetric(manhattan) = %v", err)
+	}
+}
+
+func TestHNSW_RecallAgainstExact(t *testing.T) {
+	ctx := context.Background()
+	items := randomItems(2000, 32, 1)
+	queries := randomItems(50, 32, 2)
+
+	for _, metric := range []Metric{Cosine, L2, InnerProduct} {
+		exact, approx := NewExact(metric), NewHNSW(metric, HNSWConfig{Seed: 7})
+		fill(t, exact, items)
+		fill(t, approx, items)
+
+		bench := make([]Query, len(queries))
+		for i, q := range queries {
+			bench[i] = Query{Vector: q.Vector}
+		}
+		truth, err := GroundTruth(ctx, exact, bench, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report, err := Evaluate(ctx, string(metric), approx, bench, truth, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Recall < 0.95 {
+			t.Errorf("%s: recall@10 = %.3f, want at least 0.95", metric, report.Recall)
+		}
+	}
+}
+
+func TestMemory_Filters(t *testing.T) {
+	ctx := context.Background()
+	items := randomItems(300, 8, 3)
+	viewer, self := items[0], items[1]
+
+	for name, index := range map[string]*Memory{"hnsw": NewHNSW(Cosine, HNSWConfig{Seed: 1}), "exact": NewExact(Cosine)} {
+		fill(t, index, items)
+		nearest, err := index.Search(ctx, self.Vector, 3, Filter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(nearest) != 3 || nearest[0].ID != self.ID || nearest[0].Distance > 1e-6 {
+			t.Fatalf("%s: a vector's nearest neighbour is itself, got %v", name, nearest)
+		}
+		inactive, blocker, blocked := nearest[1].ID, nearest[2].ID, items[2].ID
+		index.SetInactive(inactive, true)
+		index.SetBlocks([]Block{{Blocker: blocker, Blocked: viewer.ID}, {Blocker: viewer.ID, Blocked: blocked}})
+
+		results, err := index.Search(ctx, self.Vector, 50, Filter{Exclude: []uuid.UUID{self.ID}, Viewer: viewer.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 50 {
+			t.Errorf("%s: %d results, filters should not shrink a search with plenty of rows", name, len(results))
+		}
+		for _, r := range results {
+			switch r.ID {
+			case self.ID, inactive, blocker, blocked:
+				t.Errorf("%s: filtered row %s returned", name, r.ID)
+			}
+		}
+		for i := 1; i < len(results); i++ {
+			if results[i].Distance < results[i-1].Distance {
+				t.Fatalf("%s: results are not sorted by distance", name)
+			}
+		}
+
+		withInactive, _ := index.Search(ctx, self.Vector, 2, Filter{Exclude: []uuid.UUID{self.ID}, IncludeInactive: true})
+		if len(withInactive) == 0 || withInactive[0].ID != inactive {
+			t.Errorf("%s: IncludeInactive should keep %s, got %v", name, inactive, withInactive)
+		}
+	}
+}
+
+func TestMemory_PutReplacesAndRemoves(t *testing.T) {
+	ctx := context.Background()
+	index := NewHNSW(L2, HNSWConfig{M: 4, Seed: 1})
+	items := randomItems(200, 4, 5)
+	fill(t, index, items)
+
+	moved := items[10]
+	far := []float32{100, 100, 100, 100}
+	if err := index.Put(Item{ID: moved.ID, Vector: far}); err != nil {
+		t.Fatal(err)
+	}
+	if index.Len() != 200 {
+		t.Errorf("len = %d after replacing a vector", index.Len())
+	}
+	results, _ := index.Search(ctx, far, 1, Filter{})
+	if len(results) != 1 || results[0].ID != moved.ID {
+		t.Errorf("search near the new vector = %v, want %s", results, moved.ID)
+	}
+
+	for _, item := range items[:150] {
+		index.Remove(item.ID)
+	}
+	if index.Len() != 50 {
+		t.Fatalf("len = %d after removing 150 of 200", index.Len())
+	}
+	results, _ = index.Search(ctx, items[160].Vector, 50, Filter{})
+	if len(results) != 50 || results[0].ID != items[160].ID {
+		t.Errorf("after removals: %d results, nearest %v", len(results), results[0].ID)
+	}
+	if _, err := index.Vector(ctx, items[0].ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("removed vector = %v, want ErrNotFound", err)
+	}
+	if err := index.Put(Item{ID: uuid.New(), Vector: []float32{1}}); !errors.Is(err, ErrDimensions) {
+		t.Errorf("short vector = %v, want ErrDimensions", err)
+	}
+}
+
+func benchmarkSearch(b *testing.B, index *Memory) {
+	fill(b, index, randomItems(5000, 128, 1))
+	queries := randomItems(100, 128, 2)
+	ctx := context.Background()
+	for i := 0; b.Loop(); i++ {
+		if _, err := index.Search(ctx, queries[i%len(queries)].Vector, 10, Filter{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSearch_HNSW(b *testing.B)  { benchmarkSearch(b, NewHNSW(Cosine, HNSWConfig{Seed: 1})) }
+func BenchmarkSearch_Exact(b *testing.B) { benchmarkSearch(b, NewExact(Cosine)) }
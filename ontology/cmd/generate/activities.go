@@ -0,0 +1,60 @@
+package main
+
+const provActivity = "prov:Activity"
+
+// activityType is a state transition recorded as a prov:Activity. Event
+// builders link the resource they describe to the activity through
+// prov:wasGeneratedBy, so every transition stays in the graph after the
+// resource itself has been replaced by a newer snapshot.
+type activityType struct {
+	Name string
+	// Generates is the class of the resource the activity produces.
+	Generates string
+	Comment   string
+}
+
+var activityTypes = []activityType{
+	{Name: "UserRegistered", Generates: "sk:User", Comment: "A user created an account."},
+	{Name: "UserLoggedIn", Generates: "sk:User", Comment: "A user signed in."},
+	{Name: "UserVerified", Generates: "sk:User", Comment: "A user verified their email address."},
+	{Name: "UserRoleChanged", Generates: "sk:User", Comment: "A user was given a different role."},
+	{Name: "UserDeactivated", Generates: "sk:User", Comment: "A user account was deactivated."},
+	{Name: "UserReactivated", Generates: "sk:User", Comment: "A deactivated user account was reactivated."},
+
+	{Name: "SessionScheduled", Generates: "sk:Session", Comment: "A session was booked between two users."},
+	{Name: "SessionStarted", Generates: "sk:Session", Comment: "A session started."},
+	{Name: "SessionCompleted", Generates: "sk:Session", Comment: "A session finished."},
+	{Name: "SessionCancelled", Generates: "sk:Session", Comment: "A session was cancelled before it started."},
+	{Name: "SessionNoShow", Generates: "sk:Session", Comment: "A participant did not attend a session."},
+	{Name: "SessionRated", Generates: "sk:Rating", Comment: "A participant rated the other participant of a session."},
+
+	{Name: "MatchProposed", Generates: "sk:Match", Comment: "The matching service proposed a match."},
+	{Name: "MatchAccepted", Generates: "sk:Match", Comment: "The requester accepted a proposed match."},
+	{Name: "MatchDeclined", Generates: "sk:Match", Comment: "The requester declined a proposed match."},
+
+	{Name: "SkillCreated", Generates: "sk:Skill", Comment: "A skill was added to the catalogue."},
+	{Name: "SkillUpdated", Generates: "sk:Skill", Comment: "A catalogue skill was edited."},
+	{Name: "UserSkillAdded", Generates: "sk:UserSkill", Comment: "A user listed a skill they offer or want."},
+	{Name: "UserSkillUpdated", Generates: "sk:UserSkill", Comment: "A user changed the proficiency of a listed skill."},
+
+	{Name: "ReviewRequested", Generates: "sk:Review", Comment: "A user requested a review."},
+	{Name: "ReviewAccepted", Generates: "sk:Review", Comment: "A reviewer accepted a review request."},
+	{Name: "ReviewStarted", Generates: "sk:Review", Comment: "A reviewer started working on a review."},
+	{Name: "ReviewCompleted", Generates: "sk:Review", Comment: "A reviewer delivered a review."},
+	{Name: "ReviewRevisionRequested", Generates: "sk:Review", Comment: "The requester asked for changes to a delivered review."},
+	{Name: "ReviewDeclined", Generates: "sk:Review", Comment: "A reviewer declined a review request."},
+	{Name: "ReviewCancelled", Generates: "sk:Review", Comment: "The requester cancelled a review."},
+}
+
+// addActivities declares one prov:Activity subclass per activity type.
+func (v *vocabulary) addActivities() {
+	for _, activity := range activityTypes {
+		iri := "sk:" + activity.Name
+		v.classes[iri] = &classInfo{
+			IRI:        iri,
+			Label:      splitCamel(activity.Name),
+			Comment:    activity.Comment,
+			SubClassOf: provActivity,
+		}
+	}
+}
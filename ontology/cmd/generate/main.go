@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"google.golang.org/protobuf/proto"
@@ -24,6 +25,9 @@ func main() {
 		descriptorPath = flag.String("descriptor", "", "path to an existing FileDescriptorSet (optional)")
 		modulePath     = flag.String("module-path", ".", "path to the Buf module (if descriptor is not provided)")
 		outPath        = flag.String("out", defaultOutFile, "output TTL file path")
+		shapesPath     = flag.String("shapes", defaultShapesFile, "output SHACL shapes file path (empty to skip)")
+		contextPath    = flag.String("context", defaultContextFile, "output JSON-LD context file path (empty to skip)")
+		goPath         = flag.String("go-out", defaultGoFile, "output Go IRI constants file path (empty to skip)")
 	)
 	flag.Parse()
 
@@ -37,25 +41,65 @@ func main() {
 		die(fmt.Errorf("unmarshal descriptor set: %w", err))
 	}
 
-	enums := collectEnums(&set)
+	files := sortedFiles(&set)
+	enums := collectEnums(files)
 	if len(enums) == 0 {
 		die(errors.New("no enums found in descriptor set"))
 	}
+	vocab := buildVocabulary(files, enums)
 
 	var buf bytes.Buffer
 	writeHeader(&buf)
 	for _, enum := range enums {
 		writeEnum(&buf, enum)
 	}
+	writeVocabulary(&buf, vocab)
 
-	if err := os.MkdirAll(filepath.Dir(*outPath), 0o755); err != nil {
-		die(fmt.Errorf("create output directory: %w", err))
+	if err := writeFile(*outPath, buf.Bytes()); err != nil {
+		die(err)
+	}
+
+	if *shapesPath != "" {
+		var shapes bytes.Buffer
+		if err := writeShapes(&shapes, enums); err != nil {
+			die(err)
+		}
+		if err := writeFile(*shapesPath, shapes.Bytes()); err != nil {
+			die(err)
+		}
+	}
+
+	if *contextPath != "" {
+		var context bytes.Buffer
+		if err := writeContext(&context, vocab); err != nil {
+			die(err)
+		}
+		if err := writeFile(*contextPath, context.Bytes()); err != nil {
+			die(err)
+		}
 	}
-	if err := os.WriteFile(*outPath, buf.Bytes(), 0o644); err != nil {
-		die(fmt.Errorf("write file: %w", err))
+
+	if *goPath != "" {
+		var constants bytes.Buffer
+		if err := writeGoConstants(&constants, vocab, enums); err != nil {
+			die(err)
+		}
+		if err := writeFile(*goPath, constants.Bytes()); err != nil {
+			die(err)
+		}
 	}
 }
 
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+	return nil
+}
+
 func loadDescriptor(descriptorPath, modulePath string) ([]byte, error) {
 	if descriptorPath != "" {
 		return os.ReadFile(descriptorPath)
@@ -76,9 +120,10 @@ func loadDescriptor(descriptorPath, modulePath string) ([]byte, error) {
 }
 
 type enumInfo struct {
-	Name   string
-	Values []enumValue
-	File   string
+	Name     string
+	FullName string
+	Values   []enumValue
+	File     string
 }
 
 type enumValue struct {
@@ -88,11 +133,23 @@ type enumValue struct {
 	ConceptName string
 }
 
-func collectEnums(set *descriptorpb.FileDescriptorSet) []enumInfo {
+// sortedFiles orders the set by file name so output is stable regardless of
+// how the set was built.
+func sortedFiles(set *descriptorpb.FileDescriptorSet) []*descriptorpb.FileDescriptorProto {
+	files := slices.Clone(set.GetFile())
+	slices.SortStableFunc(files, func(a, b *descriptorpb.FileDescriptorProto) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	return files
+}
+
+func collectEnums(files []*descriptorpb.FileDescriptorProto) []enumInfo {
 	var enums []enumInfo
-	for _, file := range set.GetFile() {
+	for _, file := range files {
 		for _, enum := range file.GetEnumType() {
-			enums = append(enums, buildEnumInfo(file.GetName(), enum))
+			info := buildEnumInfo(file.GetName(), enum)
+			info.FullName = "." + file.GetPackage() + "." + enum.GetName()
+			enums = append(enums, info)
 		}
 	}
 	return enums
@@ -127,7 +184,10 @@ func writeHeader(buf *bytes.Buffer) {
 	buf.WriteString(fmt.Sprintf("@prefix sk: <%s> .\n", skillSphereNS))
 	buf.WriteString("@prefix skos: <http://www.w3.org/2004/02/skos/core#> .\n")
 	buf.WriteString("@prefix owl: <http://www.w3.org/2002/07/owl#> .\n")
-	buf.WriteString("@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .\n\n")
+	buf.WriteString("@prefix prov: <http://www.w3.org/ns/prov#> .\n")
+	buf.WriteString("@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .\n")
+	buf.WriteString("@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .\n")
+	buf.WriteString("@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .\n\n")
 }
 
 func writeEnum(buf *bytes.Buffer, enum enumInfo) {
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func field(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+	f := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(number),
+		Type:     typ.Enum(),
+		JsonName: proto.String(lowerFirst(snakeToPascal(name))),
+	}
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+	return f
+}
+
+// withRDFOptions appends the custom options as unknown fields, the way they
+// arrive when the extensions are not compiled into the generator.
+func withRDFOptions(f *descriptorpb.FieldDescriptorProto, property, rng string, ignore bool) *descriptorpb.FieldDescriptorProto {
+	var raw []byte
+	if property != "" {
+		raw = protowire.AppendTag(raw, optionRDFName, protowire.BytesType)
+		raw = protowire.AppendString(raw, property)
+	}
+	if rng != "" {
+		raw = protowire.AppendTag(raw, optionRDFRange, protowire.BytesType)
+		raw = protowire.AppendString(raw, rng)
+	}
+	if ignore {
+		raw = protowire.AppendTag(raw, optionRDFIgnore, protowire.VarintType)
+		raw = protowire.AppendVarint(raw, 1)
+	}
+	opts := &descriptorpb.FieldOptions{}
+	opts.ProtoReflect().SetUnknown(raw)
+	f.Options = opts
+	return f
+}
+
+func testFiles() []*descriptorpb.FileDescriptorProto {
+	const (
+		str = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		msg = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+		enm = descriptorpb.FieldDescriptorProto_TYPE_ENUM
+		dbl = descriptorpb.FieldDescriptorProto_TYPE_DOUBLE
+		i32 = descriptorpb.FieldDescriptorProto_TYPE_INT32
+	)
+	deprecated := field("legacy_code", 6, str, "")
+	deprecated.Options = &descriptorpb.FieldOptions{Deprecated: proto.Bool(true)}
+
+	return []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("demo/v1/demo.proto"),
+		Package: proto.String("demo.v1"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Level"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("LEVEL_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("LEVEL_HIGH"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Course"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("heading", 1, str, ""),
+					field("level", 2, enm, ".demo.v1.Level"),
+					field("starts_at", 3, msg, ".google.protobuf.Timestamp"),
+					withRDFOptions(field("teacher_id", 4, str, ""), "sk:taughtBy", "sk:Teacher", false),
+					withRDFOptions(field("internal_notes", 5, str, ""), "", "", true),
+					deprecated,
+					field("price", 7, dbl, ""),
+					field("lesson", 8, msg, ".demo.v1.Course.Lesson"),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name:  proto.String("Lesson"),
+					Field: []*descriptorpb.FieldDescriptorProto{field("heading", 1, str, "")},
+				}},
+			},
+			{
+				Name:  proto.String("Teacher"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("heading", 1, i32, "")},
+			},
+			{
+				Name:  proto.String("GetCourseRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("course_id", 1, str, "")},
+			},
+		},
+		SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+			{Path: []int32{4, 0}, LeadingComments: proto.String(" A course\n offered on the platform.\n")},
+			{Path: []int32{4, 0, 2, 0}, TrailingComments: proto.String(" Shown in listings.\n")},
+		}},
+	}}
+}
+
+func TestBuildVocabulary(t *testing.T) {
+	files := testFiles()
+	enums := collectEnums(files)
+	vocab := buildVocabulary(files, enums)
+
+	var ttl bytes.Buffer
+	writeVocabulary(&ttl, vocab)
+	out := ttl.String()
+
+	for _, want := range []string{
+		"sk:Course a owl:Class ;\n  rdfs:label \"Course\" ;\n  rdfs:comment \"A course offered on the platform.\" .",
+		"sk:CourseLesson a owl:Class ;\n  rdfs:label \"Course Lesson\" .",
+		"sk:level a owl:ObjectProperty ;\n  rdfs:domain sk:Course ;\n  rdfs:range sk:LevelConcept .",
+		"sk:startsAt a owl:DatatypeProperty ;\n  rdfs:domain sk:Course ;\n  rdfs:range xsd:dateTime .",
+		"sk:taughtBy a owl:ObjectProperty ;\n  rdfs:domain sk:Course ;\n  rdfs:range sk:Teacher .",
+		"sk:legacyCode a owl:DatatypeProperty ;\n  rdfs:domain sk:Course ;\n  rdfs:range xsd:string ;\n  owl:deprecated true .",
+		"sk:lesson a owl:ObjectProperty ;\n  rdfs:domain sk:Course ;\n  rdfs:range sk:CourseLesson .",
+		"sk:SessionCancelled a owl:Class ;\n  rdfs:subClassOf prov:Activity ;\n  rdfs:label \"Session Cancelled\" ;",
+		// heading is a string on Course and Lesson but an int32 on Teacher.
+		"sk:heading a owl:DatatypeProperty ;\n  rdfs:comment \"Shown in listings.\" ;\n  rdfs:domain [ a owl:Class ; owl:unionOf ( sk:Course sk:CourseLesson sk:Teacher ) ] ;\n  rdfs:range [ a rdfs:Datatype ; owl:unionOf ( xsd:int xsd:string ) ] .",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("vocabulary is missing:\n%s\n\ngot:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"internalNotes", "GetCourseRequest", "courseId"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("vocabulary should not mention %s", unwanted)
+		}
+	}
+
+	var ctx bytes.Buffer
+	if err := writeContext(&ctx, vocab); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Context map[string]any `json:"@context"`
+	}
+	if err := json.Unmarshal(ctx.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	coercions := map[string]string{
+		"sk:startsAt":          "xsd:dateTime",
+		"sk:taughtBy":          "@id",
+		"sk:price":             "xsd:double",
+		"sk:hasParticipant":    "@id",
+		"sk:meetingUrl":        "xsd:anyURI",
+		"schema:dateModified":  "xsd:dateTime",
+		"sk:sessionStatus":     "@id",
+		"sk:heading":           "",
+		"sk:matchingAlgorithm": "@id",
+		"sk:scheduledStart":    "xsd:dateTime",
+		"sk:isPremium":         "",
+		"schema:email":         "",
+		"sk:matchScore":        "xsd:double",
+		"prov:wasGeneratedBy":  "@id",
+		"prov:endedAtTime":     "xsd:dateTime",
+	}
+	for term, want := range coercions {
+		def, _ := doc.Context[term].(map[string]any)
+		if got, _ := def["@type"].(string); got != want {
+			t.Errorf("context %s @type = %q, want %q", term, got, want)
+		}
+	}
+	if doc.Context["sk"] != skillSphereNS {
+		t.Errorf("context sk prefix = %v", doc.Context["sk"])
+	}
+}
+
+func TestWriteGoConstants(t *testing.T) {
+	files := testFiles()
+	enums := collectEnums(files)
+
+	var buf bytes.Buffer
+	if err := writeGoConstants(&buf, buildVocabulary(files, enums), enums); err != nil {
+		t.Fatalf("writeGoConstants: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`ClassCourseLesson = "sk:CourseLesson"`,
+		`PropTaughtBy`,
+		`PropHasParticipant`,
+		`SchemaDateModified`,
+		`ConceptLevelHigh`,
+		`ActivitySessionCancelled`,
+		`ProvWasGeneratedBy`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("constants missing %s:\n%s", want, out)
+		}
+	}
+}
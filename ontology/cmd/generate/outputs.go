@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+const (
+	defaultContextFile = "ontology/generated.context.jsonld"
+	defaultGoFile      = "internal/ontology/vocabulary_gen.go"
+)
+
+// contextPrefixes are declared in the JSON-LD context in this order.
+var contextPrefixes = []struct{ Name, IRI string }{
+	{"sk", skillSphereNS},
+	{"schema", "https://schema.org/"},
+	{"rdf", "http://www.w3.org/1999/02/22-rdf-syntax-ns#"},
+	{"rdfs", "http://www.w3.org/2000/01/rdf-schema#"},
+	{"xsd", "http://www.w3.org/2001/XMLSchema#"},
+	{"owl", "http://www.w3.org/2002/07/owl#"},
+	{"skos", "http://www.w3.org/2004/02/skos/core#"},
+	{"prov", "http://www.w3.org/ns/prov#"},
+}
+
+// coercedDatatypes are the ranges whose plain JSON values need a datatype in
+// the context. Strings, booleans and integers already map to the right XSD
+// type, while doubles need it so that 1.0, marshalled as 1, stays a double.
+var coercedDatatypes = map[string]bool{
+	"xsd:dateTime": true,
+	"xsd:date":     true,
+	"xsd:duration": true,
+	"xsd:anyURI":   true,
+	"xsd:double":   true,
+}
+
+// contextTerms returns the term coercions for every property: "@id" for
+// object properties and a datatype for the coerced datatypes.
+func contextTerms(v *vocabulary) []struct{ Term, Type string } {
+	var terms []struct{ Term, Type string }
+	for _, prop := range v.sortedProperties() {
+		switch {
+		case prop.Kind == objectProperty:
+			terms = append(terms, struct{ Term, Type string }{prop.IRI, "@id"})
+		case prop.Kind == datatypeProperty && len(prop.Ranges) == 1 && coercedDatatypes[prop.Ranges[0]]:
+			terms = append(terms, struct{ Term, Type string }{prop.IRI, prop.Ranges[0]})
+		}
+	}
+	return terms
+}
+
+// writeContext renders the JSON-LD context that ontology.DefaultContext
+// names: the prefixes followed by one coercion per term.
+func writeContext(buf *bytes.Buffer, v *vocabulary) error {
+	quote := func(s string) string {
+		b, _ := json.Marshal(s)
+		return string(b)
+	}
+
+	var lines []string
+	for _, prefix := range contextPrefixes {
+		lines = append(lines, fmt.Sprintf("    %s: %s", quote(prefix.Name), quote(prefix.IRI)))
+	}
+	for _, term := range contextTerms(v) {
+		lines = append(lines, fmt.Sprintf("    %s: { \"@type\": %s }", quote(term.Term), quote(term.Type)))
+	}
+
+	buf.WriteString("{\n  \"@context\": {\n")
+	buf.WriteString(strings.Join(lines, ",\n"))
+	buf.WriteString("\n  }\n}\n")
+
+	if !json.Valid(buf.Bytes()) {
+		return fmt.Errorf("generated context is not valid JSON")
+	}
+	return nil
+}
+
+// writeGoConstants renders compact-IRI constants for classes, properties and
+// enum concepts so event builders do not spell IRIs by hand.
+func writeGoConstants(buf *bytes.Buffer, v *vocabulary, enums []enumInfo) error {
+	var src bytes.Buffer
+	src.WriteString("// Code generated by ontology/cmd/generate; DO NOT EDIT.\n\n")
+	src.WriteString("package ontology\n\n")
+
+	seen := make(map[string]string)
+	block := func(comment string, entries [][2]string) error {
+		src.WriteString("// " + comment + "\nconst (\n")
+		for _, entry := range entries {
+			name, iri := entry[0], entry[1]
+			if prev, dup := seen[name]; dup {
+				if prev == iri {
+					continue
+				}
+				return fmt.Errorf("constant %s maps to both %s and %s", name, prev, iri)
+			}
+			seen[name] = iri
+			src.WriteString(fmt.Sprintf("\t%s = %q\n", name, iri))
+		}
+		src.WriteString(")\n\n")
+		return nil
+	}
+
+	var classes, activities [][2]string
+	for _, class := range v.sortedClasses() {
+		local, ok := strings.CutPrefix(class.IRI, "sk:")
+		switch {
+		case !ok:
+		case class.SubClassOf == provActivity:
+			activities = append(activities, [2]string{"Activity" + local, class.IRI})
+		default:
+			classes = append(classes, [2]string{"Class" + local, class.IRI})
+		}
+	}
+	if err := block("Classes mapped from proto messages.", classes); err != nil {
+		return err
+	}
+	if err := block("Activity types recorded through prov:wasGeneratedBy.", activities); err != nil {
+		return err
+	}
+
+	var props, schemaProps [][2]string
+	provTerms := [][2]string{{"ProvActivity", provActivity}}
+	for _, prop := range v.sortedProperties() {
+		if local, ok := strings.CutPrefix(prop.IRI, "sk:"); ok {
+			props = append(props, [2]string{"Prop" + upperFirst(local), prop.IRI})
+		} else if local, ok := strings.CutPrefix(prop.IRI, "schema:"); ok {
+			schemaProps = append(schemaProps, [2]string{"Schema" + upperFirst(local), prop.IRI})
+		} else if local, ok := strings.CutPrefix(prop.IRI, "prov:"); ok {
+			provTerms = append(provTerms, [2]string{"Prov" + upperFirst(local), prop.IRI})
+		}
+	}
+	if err := block("Properties in the sk: namespace.", props); err != nil {
+		return err
+	}
+	if err := block("schema.org properties used by emitted events.", schemaProps); err != nil {
+		return err
+	}
+	if err := block("PROV-O terms used by activity records.", provTerms); err != nil {
+		return err
+	}
+
+	var concepts [][2]string
+	for _, enum := range enums {
+		for _, value := range enum.Values {
+			concepts = append(concepts, [2]string{"Concept" + strings.TrimPrefix(value.ConceptName, "sk:"), value.ConceptName})
+		}
+	}
+	if err := block("SKOS concepts generated from proto enum values.", concepts); err != nil {
+		return err
+	}
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("format Go constants: %w", err)
+	}
+	buf.Write(formatted)
+	return nil
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
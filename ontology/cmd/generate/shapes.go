@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const defaultShapesFile = "ontology/shapes.ttl"
+
+// nodeShape describes the SHACL shape for one class of emitted event. The
+// property list mirrors the builders in internal/ontology; keep them in sync.
+type nodeShape struct {
+	Class      string
+	Properties []propertyShape
+}
+
+type propertyShape struct {
+	Path     string
+	Datatype string
+	Class    string
+	NodeKind string
+	MinCount int
+	MaxCount int
+	// InScheme restricts values to the concepts generated for this enum.
+	InScheme string
+	// Refinable also admits IRIs minted under those concepts, such as
+	// sk:MatchingAlgorithmHybrid/v3/treatment, by matching a sh:pattern
+	// instead of sh:in.
+	Refinable bool
+}
+
+// generatedBy links every event to the activity that produced it.
+var generatedBy = propertyShape{Path: "prov:wasGeneratedBy", Class: provActivity, NodeKind: "sh:IRI", MaxCount: 1}
+
+var eventShapes = []nodeShape{
+	{
+		Class: "sk:User",
+		Properties: []propertyShape{
+			{Path: "schema:email", Datatype: "xsd:string", MinCount: 1, MaxCount: 1},
+			{Path: "schema:name", Datatype: "xsd:string", MinCount: 1, MaxCount: 1},
+			{Path: "schema:alternateName", Datatype: "xsd:string", MaxCount: 1},
+			{Path: "schema:image", NodeKind: "sh:IRI", MaxCount: 1},
+			{Path: "schema:dateModified", Datatype: "xsd:dateTime", MaxCount: 1},
+			{Path: "schema:lastReviewed", Datatype: "xsd:dateTime", MaxCount: 1},
+			{Path: "sk:isVerified", Datatype: "xsd:boolean", MaxCount: 1},
+			{Path: "sk:isActive", Datatype: "xsd:boolean", MinCount: 1, MaxCount: 1},
+			{Path: "sk:role", Datatype: "xsd:string", MinCount: 1, MaxCount: 1},
+			generatedBy,
+		},
+	},
+	{
+		Class: "sk:Session",
+		Properties: []propertyShape{
+			{Path: "schema:dateModified", Datatype: "xsd:dateTime", MinCount: 1, MaxCount: 1},
+			{Path: "sk:initiatedBy", Class: "sk:User", NodeKind: "sh:IRI", MinCount: 1, MaxCount: 1},
+			{Path: "sk:hasParticipant", Class: "sk:User", NodeKind: "sh:IRI", MinCount: 2, MaxCount: 2},
+			{Path: "sk:sessionStatus", NodeKind: "sh:IRI", MaxCount: 1, InScheme: "SessionStatus"},
+			{Path: "sk:scheduledStart", Datatype: "xsd:dateTime", MaxCount: 1},
+			{Path: "sk:scheduledEnd", Datatype: "xsd:dateTime", MaxCount: 1},
+			{Path: "sk:actualStart", Datatype: "xsd:dateTime", MaxCount: 1},
+			{Path: "sk:actualEnd", Datatype: "xsd:dateTime", MaxCount: 1},
+			{Path: "sk:meetingUrl", Datatype: "xsd:anyURI", MaxCount: 1},
+			{Path: "sk:sessionNotes", Datatype: "xsd:string", MaxCount: 1},
+			{Path: "sk:cancellationReason", Datatype: "xsd:string", MaxCount: 1},
+			{Path: "sk:initiatorOffers", Datatype: "xsd:string"},
+			{Path: "sk:partnerOffers", Datatype: "xsd:string"},
+			{Path: "sk:isPremium", Datatype: "xsd:boolean", MinCount: 1, MaxCount: 1},
+			generatedBy,
+		},
+	},
+	{
+		Class: "sk:Rating",
+		Properties: []propertyShape{
+			{Path: "schema:dateModified", Datatype: "xsd:dateTime", MinCount: 1, MaxCount: 1},
+			{Path: "sk:ratedSession", Class: "sk:Session", NodeKind: "sh:IRI", MinCount: 1, MaxCount: 1},
+			{Path: "sk:ratedBy", Class: "sk:User", NodeKind: "sh:IRI", MinCount: 1, MaxCount: 1},
+			{Path: "sk:ratedUser", Class: "sk:User", NodeKind: "sh:IRI", MinCount: 1, MaxCount: 1},
+			{Path: "sk:ratingScore", Datatype: "xsd:integer", MinCount: 1, MaxCount: 1},
+			{Path: "sk:comment", Datatype: "xsd:string", MaxCount: 1},
+			generatedBy,
+		},
+	},
+	{
+		Class: "sk:Match",
+		Properties: []propertyShape{
+			{Path: "schema:dateModified", Datatype: "xsd:dateTime", MinCount: 1, MaxCount: 1},
+			{Path: "sk:hasMatch", Class: "sk:User", NodeKind: "sh:IRI", MinCount: 1, MaxCount: 1},
+			{Path: "sk:matchTarget", Class: "sk:User", NodeKind: "sh:IRI", MinCount: 1, MaxCount: 1},
+			{Path: "sk:matchingAlgorithm", NodeKind: "sh:IRI", MaxCount: 1, InScheme: "MatchingAlgorithm", Refinable: true},
+			{Path: "sk:matchScore", Datatype: "xsd:double", MaxCount: 1},
+			{Path: "sk:explanation", Datatype: "xsd:string", MaxCount: 1},
+			{Path: "sk:isMutual", Datatype: "xsd:boolean", MaxCount: 1},
+			{Path: "sk:skillMatches", NodeKind: "sh:BlankNode"},
+			generatedBy,
+		},
+	},
+	{
+		Class: "sk:Skill",
+		Properties: []propertyShape{
+			{Path: "schema:dateModified", Datatype: "xsd:dateTime", MinCount: 1, MaxCount: 1},
+			{Path: "schema:name", Datatype: "xsd:string", MinCount: 1, MaxCount: 1},
+			{Path: "sk:description", Datatype: "xsd:string", MaxCount: 1},
+			{Path: "sk:skillCategory", NodeKind: "sh:IRI", MaxCount: 1, InScheme: "SkillCategory"},
+			{Path: "sk:tags", Datatype: "xsd:string"},
+			generatedBy,
+		},
+	},
+	{
+		Class: "sk:UserSkill",
+		Properties: []propertyShape{
+			{Path: "schema:dateModified", Datatype: "xsd:dateTime", MinCount: 1, MaxCount: 1},
+			{Path: "sk:heldBy", Class: "sk:User", NodeKind: "sh:IRI", MinCount: 1, MaxCount: 1},
+			{Path: "sk:skill", Class: "sk:Skill", NodeKind: "sh:IRI", MinCount: 1, MaxCount: 1},
+			{Path: "sk:isOffered", Datatype: "xsd:boolean", MinCount: 1, MaxCount: 1},
+			{Path: "sk:proficiencyLevel", Datatype: "xsd:integer", MaxCount: 1},
+			generatedBy,
+		},
+	},
+	{
+		Class: "sk:Review",
+		Properties: []propertyShape{
+			{Path: "schema:dateModified", Datatype: "xsd:dateTime", MinCount: 1, MaxCount: 1},
+			{Path: "sk:requestedBy", Class: "sk:User", NodeKind: "sh:IRI", MinCount: 1, MaxCount: 1},
+			{Path: "sk:reviewedBy", Class: "sk:User", NodeKind: "sh:IRI", MaxCount: 1},
+			{Path: "sk:skill", Class: "sk:Skill", NodeKind: "sh:IRI", MinCount: 1, MaxCount: 1},
+			{Path: "sk:title", Datatype: "xsd:string", MinCount: 1, MaxCount: 1},
+			{Path: "sk:reviewStatus", NodeKind: "sh:IRI", MaxCount: 1, InScheme: "ReviewStatus"},
+			{Path: "sk:reviewType", NodeKind: "sh:IRI", MaxCount: 1, InScheme: "ReviewType"},
+			{Path: "sk:deadline", Datatype: "xsd:dateTime", MaxCount: 1},
+			{Path: "sk:completedAt", Datatype: "xsd:dateTime", MaxCount: 1},
+			generatedBy,
+		},
+	},
+	{
+		Class: provActivity,
+		Properties: []propertyShape{
+			{Path: "prov:generated", NodeKind: "sh:IRI", MinCount: 1, MaxCount: 1},
+			{Path: "prov:endedAtTime", Datatype: "xsd:dateTime", MinCount: 1, MaxCount: 1},
+			// Activities without an agent were performed by the platform.
+			{Path: "prov:wasAssociatedWith", Class: "sk:User", NodeKind: "sh:IRI", MaxCount: 1},
+			{Path: "sk:reason", Datatype: "xsd:string", MaxCount: 1},
+		},
+	},
+}
+
+func writeShapes(buf *bytes.Buffer, enums []enumInfo) error {
+	byName := make(map[string]enumInfo, len(enums))
+	for _, enum := range enums {
+		byName[enum.Name] = enum
+	}
+
+	buf.WriteString("# Code generated by ontology/cmd/generate; DO NOT EDIT.\n")
+	buf.WriteString(fmt.Sprintf("@prefix sk: <%s> .\n", skillSphereNS))
+	buf.WriteString("@prefix schema: <https://schema.org/> .\n")
+	buf.WriteString("@prefix prov: <http://www.w3.org/ns/prov#> .\n")
+	buf.WriteString("@prefix sh: <http://www.w3.org/ns/shacl#> .\n")
+	buf.WriteString("@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .\n\n")
+
+	for _, shape := range eventShapes {
+		_, local, _ := strings.Cut(shape.Class, ":")
+		buf.WriteString(fmt.Sprintf("sk:%sShape a sh:NodeShape ;\n", local))
+		buf.WriteString(fmt.Sprintf("  sh:targetClass %s", shape.Class))
+		for _, prop := range shape.Properties {
+			buf.WriteString(" ;\n  sh:property [\n")
+			buf.WriteString(fmt.Sprintf("    sh:path %s", prop.Path))
+			if prop.Datatype != "" {
+				buf.WriteString(fmt.Sprintf(" ;\n    sh:datatype %s", prop.Datatype))
+			}
+			if prop.Class != "" {
+				buf.WriteString(fmt.Sprintf(" ;\n    sh:class %s", prop.Class))
+			}
+			if prop.NodeKind != "" {
+				buf.WriteString(fmt.Sprintf(" ;\n    sh:nodeKind %s", prop.NodeKind))
+			}
+			if prop.MinCount > 0 {
+				buf.WriteString(fmt.Sprintf(" ;\n    sh:minCount %d", prop.MinCount))
+			}
+			if prop.MaxCount > 0 {
+				buf.WriteString(fmt.Sprintf(" ;\n    sh:maxCount %d", prop.MaxCount))
+			}
+			if prop.InScheme != "" {
+				enum, ok := byName[prop.InScheme]
+				if !ok || len(enum.Values) == 0 {
+					return fmt.Errorf("shape %s: enum %s not found in descriptor set", shape.Class, prop.InScheme)
+				}
+				concepts := make([]string, 0, len(enum.Values))
+				for _, value := range enum.Values {
+					concepts = append(concepts, value.ConceptName)
+				}
+				if prop.Refinable {
+					buf.WriteString(fmt.Sprintf(" ;\n    sh:pattern %s", turtleString(refinementPattern(concepts))))
+				} else {
+					buf.WriteString(fmt.Sprintf(" ;\n    sh:in ( %s )", strings.Join(concepts, " ")))
+				}
+			}
+			buf.WriteString("\n  ]")
+		}
+		buf.WriteString(" .\n\n")
+	}
+
+	// Each activity type must generate a resource of the class it describes.
+	for _, activity := range activityTypes {
+		buf.WriteString(fmt.Sprintf("sk:%sShape a sh:NodeShape ;\n", activity.Name))
+		buf.WriteString(fmt.Sprintf("  sh:targetClass sk:%s ;\n", activity.Name))
+		buf.WriteString(fmt.Sprintf("  sh:property [\n    sh:path prov:generated ;\n    sh:class %s\n  ] .\n\n", activity.Generates))
+	}
+	return nil
+}
+
+// refinementPattern matches the expanded concept IRIs, optionally followed
+// by further path segments.
+func refinementPattern(concepts []string) string {
+	iris := make([]string, 0, len(concepts))
+	for _, concept := range concepts {
+		iris = append(iris, regexp.QuoteMeta(skillSphereNS+strings.TrimPrefix(concept, "sk:")))
+	}
+	return "^(" + strings.Join(iris, "|") + ")(/[^/]+)*$"
+}
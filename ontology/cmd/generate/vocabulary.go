@@ -0,0 +1,467 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Custom options read from message and field options. They are declared in
+// the proto repo as extensions, e.g.
+//
+//	extend google.protobuf.MessageOptions {
+//	  string rdf_class = 51000;
+//	  bool rdf_ignore = 51002;
+//	}
+//	extend google.protobuf.FieldOptions {
+//	  string rdf_property = 51000;
+//	  string rdf_range = 51001;
+//	  bool rdf_ignore = 51002;
+//	}
+//
+// and arrive here as unknown fields, so the generator does not need the
+// option definitions compiled in. Values are compact ("sk:hasParticipant")
+// or absolute IRIs.
+const (
+	optionRDFName   protowire.Number = 51000
+	optionRDFRange  protowire.Number = 51001
+	optionRDFIgnore protowire.Number = 51002
+)
+
+const xsdNS = "http://www.w3.org/2001/XMLSchema#"
+
+type rdfOptions struct {
+	Name   string
+	Range  string
+	Ignore bool
+}
+
+func readRDFOptions(opts proto.Message) rdfOptions {
+	var out rdfOptions
+	if opts == nil || !opts.ProtoReflect().IsValid() {
+		return out
+	}
+	raw := opts.ProtoReflect().GetUnknown()
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		if n < 0 {
+			return out
+		}
+		raw = raw[n:]
+		switch {
+		case typ == protowire.BytesType && (num == optionRDFName || num == optionRDFRange):
+			value, n := protowire.ConsumeBytes(raw)
+			if n < 0 {
+				return out
+			}
+			if num == optionRDFName {
+				out.Name = string(value)
+			} else {
+				out.Range = string(value)
+			}
+			raw = raw[n:]
+		case typ == protowire.VarintType && num == optionRDFIgnore:
+			value, n := protowire.ConsumeVarint(raw)
+			if n < 0 {
+				return out
+			}
+			out.Ignore = value != 0
+			raw = raw[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, raw)
+			if n < 0 {
+				return out
+			}
+			raw = raw[n:]
+		}
+	}
+	return out
+}
+
+type propertyKind int
+
+const (
+	datatypeProperty propertyKind = iota + 1
+	objectProperty
+	// mixedProperty is used when fields disagree on whether the value is a
+	// literal or a resource; no range is emitted.
+	mixedProperty
+)
+
+type classInfo struct {
+	IRI        string
+	Label      string
+	Comment    string
+	SubClassOf string
+	Deprecated bool
+}
+
+type propertyInfo struct {
+	IRI        string
+	Kind       propertyKind
+	Domains    []string
+	Ranges     []string
+	Comment    string
+	Deprecated bool
+	// fixed is set when the event vocabulary defines the range; proto fields
+	// then only contribute domains.
+	fixed bool
+}
+
+type vocabulary struct {
+	classes    map[string]*classInfo
+	properties map[string]*propertyInfo
+}
+
+// wellKnownRanges maps well-known message types to XSD datatypes.
+var wellKnownRanges = map[string]string{
+	".google.protobuf.Timestamp":   "xsd:dateTime",
+	".google.protobuf.Duration":    "xsd:duration",
+	".google.protobuf.StringValue": "xsd:string",
+	".google.protobuf.BoolValue":   "xsd:boolean",
+	".google.protobuf.Int32Value":  "xsd:int",
+	".google.protobuf.Int64Value":  "xsd:long",
+	".google.protobuf.UInt32Value": "xsd:unsignedInt",
+	".google.protobuf.UInt64Value": "xsd:unsignedLong",
+	".google.protobuf.FloatValue":  "xsd:float",
+	".google.protobuf.DoubleValue": "xsd:double",
+	".google.protobuf.BytesValue":  "xsd:base64Binary",
+}
+
+var scalarRanges = map[descriptorpb.FieldDescriptorProto_Type]string{
+	descriptorpb.FieldDescriptorProto_TYPE_STRING:   "xsd:string",
+	descriptorpb.FieldDescriptorProto_TYPE_BOOL:     "xsd:boolean",
+	descriptorpb.FieldDescriptorProto_TYPE_INT32:    "xsd:int",
+	descriptorpb.FieldDescriptorProto_TYPE_SINT32:   "xsd:int",
+	descriptorpb.FieldDescriptorProto_TYPE_SFIXED32: "xsd:int",
+	descriptorpb.FieldDescriptorProto_TYPE_INT64:    "xsd:long",
+	descriptorpb.FieldDescriptorProto_TYPE_SINT64:   "xsd:long",
+	descriptorpb.FieldDescriptorProto_TYPE_SFIXED64: "xsd:long",
+	descriptorpb.FieldDescriptorProto_TYPE_UINT32:   "xsd:unsignedInt",
+	descriptorpb.FieldDescriptorProto_TYPE_FIXED32:  "xsd:unsignedInt",
+	descriptorpb.FieldDescriptorProto_TYPE_UINT64:   "xsd:unsignedLong",
+	descriptorpb.FieldDescriptorProto_TYPE_FIXED64:  "xsd:unsignedLong",
+	descriptorpb.FieldDescriptorProto_TYPE_FLOAT:    "xsd:float",
+	descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:   "xsd:double",
+	descriptorpb.FieldDescriptorProto_TYPE_BYTES:    "xsd:base64Binary",
+}
+
+// mappedMessage is a message that becomes an OWL class.
+type mappedMessage struct {
+	msg     *descriptorpb.DescriptorProto
+	class   string
+	path    []int32
+	comment func(path []int32) string
+}
+
+// buildVocabulary maps proto messages to OWL classes and their fields to
+// properties, merged with the properties the event builders emit. Request and
+// response messages, map entries and well-known types are skipped.
+func buildVocabulary(files []*descriptorpb.FileDescriptorProto, enums []enumInfo) *vocabulary {
+	v := &vocabulary{
+		classes:    make(map[string]*classInfo),
+		properties: make(map[string]*propertyInfo),
+	}
+
+	enumClasses := make(map[string]string)
+	for _, enum := range enums {
+		enumClasses[enum.FullName] = fmt.Sprintf("sk:%sConcept", enum.Name)
+	}
+
+	// First pass: every mapped message gets a class so fields can refer to
+	// messages declared later or in other files.
+	var mapped []mappedMessage
+	messageClasses := make(map[string]string)
+	for _, file := range files {
+		pkg := file.GetPackage()
+		if strings.HasPrefix(pkg, "google.") || strings.HasPrefix(pkg, "buf.") {
+			continue
+		}
+		comments := sourceComments(file)
+		var walk func(msgs []*descriptorpb.DescriptorProto, prefix, fullPrefix string, path []int32, field int32)
+		walk = func(msgs []*descriptorpb.DescriptorProto, prefix, fullPrefix string, path []int32, field int32) {
+			for i, msg := range msgs {
+				name := msg.GetName()
+				msgPath := append(slices.Clone(path), field, int32(i))
+				if msg.GetOptions().GetMapEntry() || strings.HasSuffix(name, "Request") || strings.HasSuffix(name, "Response") {
+					continue
+				}
+				opts := readRDFOptions(msg.GetOptions())
+				if opts.Ignore {
+					continue
+				}
+				class := opts.Name
+				if class == "" {
+					class = "sk:" + prefix + name
+				}
+				messageClasses[fullPrefix+"."+name] = class
+				mapped = append(mapped, mappedMessage{msg: msg, class: class, path: msgPath, comment: comments})
+
+				info, ok := v.classes[class]
+				if !ok {
+					info = &classInfo{IRI: class, Label: splitCamel(prefix + name)}
+					v.classes[class] = info
+				}
+				if info.Comment == "" {
+					info.Comment = comments(msgPath)
+				}
+				info.Deprecated = info.Deprecated || msg.GetOptions().GetDeprecated()
+
+				walk(msg.GetNestedType(), prefix+name, fullPrefix+"."+name, msgPath, 3)
+			}
+		}
+		walk(file.GetMessageType(), "", "."+pkg, nil, 4)
+	}
+
+	v.addActivities()
+
+	// Event properties outside sk: (schema:email, …) are kept for the context
+	// and Go constants but not redefined in the Turtle output.
+	for _, shape := range eventShapes {
+		for _, prop := range shape.Properties {
+			v.addEventProperty(shape.Class, prop)
+		}
+	}
+
+	for _, m := range mapped {
+		for i, field := range m.msg.GetField() {
+			opts := readRDFOptions(field.GetOptions())
+			if opts.Ignore {
+				continue
+			}
+			kind, rng, ok := fieldRange(field, messageClasses, enumClasses)
+			if opts.Range != "" {
+				kind, rng, ok = objectProperty, opts.Range, true
+				if strings.HasPrefix(rng, "xsd:") || strings.HasPrefix(rng, xsdNS) {
+					kind = datatypeProperty
+				}
+			}
+			if !ok {
+				continue
+			}
+			iri := opts.Name
+			if iri == "" {
+				iri = "sk:" + lowerFirst(field.GetJsonName())
+				if field.GetJsonName() == "" {
+					iri = "sk:" + lowerFirst(snakeToPascal(field.GetName()))
+				}
+			}
+			fieldPath := append(slices.Clone(m.path), 2, int32(i))
+			v.addField(m.class, iri, kind, rng, m.comment(fieldPath), field.GetOptions().GetDeprecated())
+		}
+	}
+	return v
+}
+
+func fieldRange(field *descriptorpb.FieldDescriptorProto, messages, enums map[string]string) (propertyKind, string, bool) {
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+		if rng, ok := wellKnownRanges[field.GetTypeName()]; ok {
+			return datatypeProperty, rng, true
+		}
+		if class, ok := messages[field.GetTypeName()]; ok {
+			return objectProperty, class, true
+		}
+		// Maps, Struct/Any and request types have no useful RDF mapping.
+		return 0, "", false
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		if class, ok := enums[field.GetTypeName()]; ok {
+			return objectProperty, class, true
+		}
+		// Nested enums have no concept scheme; keep the value name.
+		return datatypeProperty, "xsd:string", true
+	case descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		return 0, "", false
+	default:
+		rng, ok := scalarRanges[field.GetType()]
+		return datatypeProperty, rng, ok
+	}
+}
+
+func (v *vocabulary) property(iri string) *propertyInfo {
+	prop, ok := v.properties[iri]
+	if !ok {
+		prop = &propertyInfo{IRI: iri}
+		v.properties[iri] = prop
+	}
+	return prop
+}
+
+func (v *vocabulary) addEventProperty(class string, shape propertyShape) {
+	prop := v.property(shape.Path)
+	prop.Domains = appendUnique(prop.Domains, class)
+
+	kind, rng := datatypeProperty, shape.Datatype
+	switch {
+	case shape.Class != "":
+		kind, rng = objectProperty, shape.Class
+	case shape.InScheme != "":
+		kind, rng = objectProperty, fmt.Sprintf("sk:%sConcept", shape.InScheme)
+	case shape.NodeKind == "sh:IRI" || shape.NodeKind == "sh:BlankNode":
+		kind = objectProperty
+	}
+	prop.Kind = kind
+	if rng != "" {
+		prop.Ranges = appendUnique(prop.Ranges, rng)
+		prop.fixed = true
+	}
+}
+
+func (v *vocabulary) addField(class, iri string, kind propertyKind, rng, comment string, deprecated bool) {
+	prop := v.property(iri)
+	prop.Domains = appendUnique(prop.Domains, class)
+	if prop.Comment == "" {
+		prop.Comment = comment
+	}
+	prop.Deprecated = prop.Deprecated || deprecated
+	if prop.fixed {
+		return
+	}
+	switch {
+	case prop.Kind == 0:
+		prop.Kind = kind
+	case prop.Kind != kind:
+		prop.Kind = mixedProperty
+	}
+	prop.Ranges = appendUnique(prop.Ranges, rng)
+}
+
+func (v *vocabulary) sortedClasses() []*classInfo {
+	classes := make([]*classInfo, 0, len(v.classes))
+	for _, class := range v.classes {
+		classes = append(classes, class)
+	}
+	slices.SortFunc(classes, func(a, b *classInfo) int { return strings.Compare(a.IRI, b.IRI) })
+	return classes
+}
+
+func (v *vocabulary) sortedProperties() []*propertyInfo {
+	props := make([]*propertyInfo, 0, len(v.properties))
+	for _, prop := range v.properties {
+		slices.Sort(prop.Domains)
+		slices.Sort(prop.Ranges)
+		props = append(props, prop)
+	}
+	slices.SortFunc(props, func(a, b *propertyInfo) int { return strings.Compare(a.IRI, b.IRI) })
+	return props
+}
+
+func writeVocabulary(buf *bytes.Buffer, v *vocabulary) {
+	buf.WriteString("# Classes mapped from proto messages and activity types\n")
+	for _, class := range v.sortedClasses() {
+		if !strings.HasPrefix(class.IRI, "sk:") {
+			continue
+		}
+		buf.WriteString(fmt.Sprintf("%s a owl:Class ;\n", turtleName(class.IRI)))
+		if class.SubClassOf != "" {
+			buf.WriteString(fmt.Sprintf("  rdfs:subClassOf %s ;\n", turtleName(class.SubClassOf)))
+		}
+		buf.WriteString(fmt.Sprintf("  rdfs:label %s", turtleString(class.Label)))
+		if class.Comment != "" {
+			buf.WriteString(fmt.Sprintf(" ;\n  rdfs:comment %s", turtleString(class.Comment)))
+		}
+		if class.Deprecated {
+			buf.WriteString(" ;\n  owl:deprecated true")
+		}
+		buf.WriteString(" .\n\n")
+	}
+
+	buf.WriteString("# Properties mapped from proto fields and emitted events\n")
+	for _, prop := range v.sortedProperties() {
+		if !strings.HasPrefix(prop.IRI, "sk:") {
+			continue
+		}
+		typ := "owl:DatatypeProperty"
+		switch prop.Kind {
+		case objectProperty:
+			typ = "owl:ObjectProperty"
+		case mixedProperty:
+			typ = "rdf:Property"
+		}
+		buf.WriteString(fmt.Sprintf("%s a %s", turtleName(prop.IRI), typ))
+		if prop.Comment != "" {
+			buf.WriteString(fmt.Sprintf(" ;\n  rdfs:comment %s", turtleString(prop.Comment)))
+		}
+		if len(prop.Domains) > 0 {
+			buf.WriteString(" ;\n  rdfs:domain " + unionOf("owl:Class", prop.Domains))
+		}
+		if len(prop.Ranges) > 0 && prop.Kind != mixedProperty {
+			rangeType := "rdfs:Datatype"
+			if prop.Kind == objectProperty {
+				rangeType = "owl:Class"
+			}
+			buf.WriteString(" ;\n  rdfs:range " + unionOf(rangeType, prop.Ranges))
+		}
+		if prop.Deprecated {
+			buf.WriteString(" ;\n  owl:deprecated true")
+		}
+		buf.WriteString(" .\n\n")
+	}
+}
+
+// unionOf returns the single member or an anonymous class of the given type
+// that is the union of all members. Several rdfs:domain or rdfs:range values
+// would otherwise mean their intersection.
+func unionOf(typ string, members []string) string {
+	if len(members) == 1 {
+		return turtleName(members[0])
+	}
+	names := make([]string, len(members))
+	for i, member := range members {
+		names[i] = turtleName(member)
+	}
+	return fmt.Sprintf("[ a %s ; owl:unionOf ( %s ) ]", typ, strings.Join(names, " "))
+}
+
+// turtleName writes compact IRIs as-is and absolute ones in angle brackets.
+func turtleName(iri string) string {
+	if strings.Contains(iri, "://") {
+		return "<" + iri + ">"
+	}
+	return iri
+}
+
+func turtleString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// sourceComments returns a lookup of leading (or trailing) comments by
+// descriptor path, collapsed onto one line. Descriptor sets built without
+// source info simply have no comments.
+func sourceComments(file *descriptorpb.FileDescriptorProto) func(path []int32) string {
+	comments := make(map[string]string)
+	for _, loc := range file.GetSourceCodeInfo().GetLocation() {
+		comment := loc.GetLeadingComments()
+		if strings.TrimSpace(comment) == "" {
+			comment = loc.GetTrailingComments()
+		}
+		if comment = strings.Join(strings.Fields(comment), " "); comment != "" {
+			comments[pathKey(loc.GetPath())] = comment
+		}
+	}
+	return func(path []int32) string { return comments[pathKey(path)] }
+}
+
+func pathKey(path []int32) string {
+	return fmt.Sprint(path)
+}
+
+func appendUnique(list []string, value string) []string {
+	if slices.Contains(list, value) {
+		return list
+	}
+	return append(list, value)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,27 @@
+// Package ontology embeds the files produced by ontology/cmd/generate so
+// services can load them without reading the filesystem at runtime.
+//
+// Regenerate them with:
+//
+//	go run ./ontology/cmd/generate
+package ontology
+
+import _ "embed"
+
+// Schemes is the generated vocabulary: SKOS concept schemes for protobuf
+// enums plus OWL classes and properties for messages and their fields.
+//
+//go:embed generated.ttl
+var Schemes []byte
+
+// Shapes is the generated SHACL shapes for emitted sk:User, sk:Session and
+// sk:Match events.
+//
+//go:embed shapes.ttl
+var Shapes []byte
+
+// Context is the generated JSON-LD context published at
+// https://ontology.skillsphere.dev/generated.context.jsonld.
+//
+//go:embed generated.context.jsonld
+var Context []byte
@@ -2,110 +2,355 @@ package config
 
 import (
 	"fmt"
-	_ "github.com/joho/godotenv"
 	"os"
-	"strconv"
+	"time"
+
+	_ "github.com/joho/godotenv"
 )
 
-// Config holds all application configuration
+// Environment names recognised by Validate.
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// Config holds all application configuration.
+//
+// Values are resolved in layers: Default, then the YAML file named by CONFIG_FILE,
+// then environment variables, then "<VAR>_FILE" secret files. Fields tagged
+// secret:"true" are redacted by WriteRedacted.
 type Config struct {
-	Server        ServerConfig
-	Database      DatabaseConfig
-	Auth          AuthConfig
-	Observability ObservabilityConfig
-	Profiling     ProfilingConfig
+	Environment   string              `yaml:"environment" env:"ENVIRONMENT"`
+	Server        ServerConfig        `yaml:"server"`
+	Database      DatabaseConfig      `yaml:"database"`
+	Auth          AuthConfig          `yaml:"auth"`
+	OAuth         OAuthConfig         `yaml:"oauth"`
+	Email         EmailConfig         `yaml:"email"`
+	Ontology      OntologyConfig      `yaml:"ontology"`
+	Matching      MatchingConfig      `yaml:"matching"`
+	Embedding     EmbeddingConfig     `yaml:"embedding"`
+	VectorSearch  VectorSearchConfig  `yaml:"vector_search"`
+	Observability ObservabilityConfig `yaml:"observability"`
+	Profiling     ProfilingConfig     `yaml:"profiling"`
 }
 
 type ServerConfig struct {
-	Host               string
-	Port               int
-	RateLimitPerSecond int
-	RateLimitBurst     int
+	Host               string        `yaml:"host" env:"SERVER_HOST"`
+	Port               int           `yaml:"port" env:"SERVER_PORT"`
+	RateLimitPerSecond int           `yaml:"rate_limit_per_second" env:"SERVER_RATE_LIMIT_PER_SECOND"`
+	RateLimitBurst     int           `yaml:"rate_limit_burst" env:"SERVER_RATE_LIMIT_BURST"`
+	ReadTimeout        time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout       time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout        time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	ShutdownTimeout    time.Duration `yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
 }
 
 type DatabaseConfig struct {
-	Host     string
-	Port     int
-	User     string
-	Password string
-	Database string
-	SSLMode  string
+	Host     string `yaml:"host" env:"DB_HOST"`
+	Port     int    `yaml:"port" env:"DB_PORT"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password string `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+	Database string `yaml:"name" env:"DB_NAME"`
+	SSLMode  string `yaml:"sslmode" env:"DB_SSLMODE"`
+
+	MaxConns        int32         `yaml:"max_conns" env:"DB_MAX_CONNS"`
+	MinConns        int32         `yaml:"min_conns" env:"DB_MIN_CONNS"`
+	MaxConnLifetime time.Duration `yaml:"max_conn_lifetime" env:"DB_MAX_CONN_LIFETIME"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time" env:"DB_MAX_CONN_IDLE_TIME"`
+
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold" env:"DB_SLOW_QUERY_THRESHOLD"`
+
+	// ReplicaURLs lists read-replica connection strings; reads use the primary when empty.
+	ReplicaURLs          []string      `yaml:"replica_urls" env:"DB_REPLICA_URLS" secret:"true"`
+	MaxReplicaLag        time.Duration `yaml:"max_replica_lag" env:"DB_MAX_REPLICA_LAG"`
+	ReadYourWritesWindow time.Duration `yaml:"read_your_writes_window" env:"DB_READ_YOUR_WRITES_WINDOW"`
 }
 
 type AuthConfig struct {
-	JWTSecret string
+	JWTSecret       string        `yaml:"jwt_secret" env:"JWT_SECRET" secret:"true"`
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl" env:"JWT_ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env:"JWT_REFRESH_TOKEN_TTL"`
+}
+
+type OAuthConfig struct {
+	GoogleClientID     string `yaml:"google_client_id" env:"GOOGLE_CLIENT_ID"`
+	GoogleClientSecret string `yaml:"google_client_secret" env:"GOOGLE_CLIENT_SECRET" secret:"true"`
+	AppleClientID      string `yaml:"apple_client_id" env:"APPLE_CLIENT_ID"`
+	AppleSecret        string `yaml:"apple_secret" env:"APPLE_SECRET" secret:"true"`
+	AppleTeamID        string `yaml:"apple_team_id" env:"APPLE_TEAM_ID"`
+	AppleKeyID         string `yaml:"apple_key_id" env:"APPLE_KEY_ID"`
+	CallbackURL        string `yaml:"callback_url" env:"OAUTH_CALLBACK_URL"`
+	SessionSecret      string `yaml:"session_secret" env:"SESSION_SECRET" secret:"true"`
+}
+
+type EmailConfig struct {
+	SMTPHost     string `yaml:"smtp_host" env:"SMTP_HOST"`
+	SMTPPort     int    `yaml:"smtp_port" env:"SMTP_PORT"`
+	SMTPUsername string `yaml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword string `yaml:"smtp_password" env:"SMTP_PASSWORD" secret:"true"`
+	FromEmail    string `yaml:"from_email" env:"FROM_EMAIL"`
+	FromName     string `yaml:"from_name" env:"FROM_NAME"`
+	FrontendURL  string `yaml:"frontend_url" env:"FRONTEND_URL"`
+}
+
+type OntologyConfig struct {
+	KafkaTopic string `yaml:"kafka_topic" env:"ONTOLOGY_KAFKA_TOPIC"`
+	// KafkaBrokers enables the Kafka producer; events are only logged when empty.
+	KafkaBrokers        []string      `yaml:"kafka_brokers" env:"ONTOLOGY_KAFKA_BROKERS"`
+	KafkaClientID       string        `yaml:"kafka_client_id" env:"ONTOLOGY_KAFKA_CLIENT_ID"`
+	KafkaAcks           string        `yaml:"kafka_acks" env:"ONTOLOGY_KAFKA_ACKS"`
+	KafkaCompression    string        `yaml:"kafka_compression" env:"ONTOLOGY_KAFKA_COMPRESSION"`
+	KafkaLinger         time.Duration `yaml:"kafka_linger" env:"ONTOLOGY_KAFKA_LINGER"`
+	KafkaBatchMaxBytes  int           `yaml:"kafka_batch_max_bytes" env:"ONTOLOGY_KAFKA_BATCH_MAX_BYTES"`
+	KafkaRequestTimeout time.Duration `yaml:"kafka_request_timeout" env:"ONTOLOGY_KAFKA_REQUEST_TIMEOUT"`
+	// TripleStoreUpdateEndpoint is the SPARQL Update endpoint; the triple store is skipped when empty.
+	TripleStoreUpdateEndpoint string        `yaml:"triplestore_update_endpoint" env:"ONTOLOGY_TRIPLESTORE_UPDATE_ENDPOINT"`
+	TripleStoreGraphBase      string        `yaml:"triplestore_graph_base" env:"ONTOLOGY_TRIPLESTORE_GRAPH_BASE"`
+	TripleStoreUsername       string        `yaml:"triplestore_username" env:"ONTOLOGY_TRIPLESTORE_USERNAME"`
+	TripleStorePassword       string        `yaml:"triplestore_password" env:"ONTOLOGY_TRIPLESTORE_PASSWORD" secret:"true"`
+	TripleStoreToken          string        `yaml:"triplestore_token" env:"ONTOLOGY_TRIPLESTORE_TOKEN" secret:"true"`
+	TripleStoreTimeout        time.Duration `yaml:"triplestore_timeout" env:"ONTOLOGY_TRIPLESTORE_TIMEOUT"`
+	PollInterval              time.Duration `yaml:"poll_interval" env:"ONTOLOGY_POLL_INTERVAL"`
+	// MaxAttempts dead-letters an outbox row after this many failed deliveries.
+	MaxAttempts    int           `yaml:"max_attempts" env:"ONTOLOGY_MAX_ATTEMPTS"`
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay" env:"ONTOLOGY_RETRY_BASE_DELAY"`
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay" env:"ONTOLOGY_RETRY_MAX_DELAY"`
+	// ValidateShapes quarantines events that fail the generated SHACL shapes.
+	ValidateShapes bool `yaml:"validate_shapes" env:"ONTOLOGY_VALIDATE_SHAPES"`
+	// BatchSize outbox rows are leased per poll and dispatched Concurrency at a time.
+	BatchSize   int `yaml:"batch_size" env:"ONTOLOGY_BATCH_SIZE"`
+	Concurrency int `yaml:"concurrency" env:"ONTOLOGY_CONCURRENCY"`
+	// Listen wakes the worker through LISTEN/NOTIFY; polling remains the fallback.
+	// Disable it behind transaction-pooling proxies that do not support LISTEN.
+	Listen bool `yaml:"listen" env:"ONTOLOGY_LISTEN"`
+	// LeaseDuration reserves claimed rows for one worker; it must outlast a dispatch.
+	LeaseDuration time.Duration `yaml:"lease_duration" env:"ONTOLOGY_LEASE_DURATION"`
+	// Retention removes delivered rows older than this; zero keeps them forever.
+	Retention         time.Duration `yaml:"retention" env:"ONTOLOGY_RETENTION"`
+	RetentionArchive  bool          `yaml:"retention_archive" env:"ONTOLOGY_RETENTION_ARCHIVE"`
+	RetentionInterval time.Duration `yaml:"retention_interval" env:"ONTOLOGY_RETENTION_INTERVAL"`
+	// QueryIndex keeps an in-memory copy of the delivered graph in the API for
+	// the admin SPARQL endpoint. It is rebuilt from ontology_outbox and its
+	// archive on start, so pair it with retention_archive or retention 0.
+	QueryIndex         bool          `yaml:"query_index" env:"ONTOLOGY_QUERY_INDEX"`
+	QueryIndexInterval time.Duration `yaml:"query_index_interval" env:"ONTOLOGY_QUERY_INDEX_INTERVAL"`
+	QueryTimeout       time.Duration `yaml:"query_timeout" env:"ONTOLOGY_QUERY_TIMEOUT"`
+}
+
+type MatchingConfig struct {
+	// AvailabilityWeight is the share of a match score taken by weekly
+	// schedule overlap; 0 reports overlap without changing scores.
+	AvailabilityWeight float64 `yaml:"availability_weight" env:"MATCHING_AVAILABILITY_WEIGHT"`
+	// TargetOverlap is the weekly overlap that earns the full availability score.
+	TargetOverlap time.Duration `yaml:"target_overlap" env:"MATCHING_TARGET_OVERLAP"`
+	// MinOverlap drops candidates sharing less free time; zero disables the filter.
+	MinOverlap  time.Duration `yaml:"min_overlap" env:"MATCHING_MIN_OVERLAP"`
+	SharedSlots int           `yaml:"shared_slots" env:"MATCHING_SHARED_SLOTS"`
+	// VectorRefreshInterval is how often user_skill_vectors catches up with
+	// user_skills writes, rebuilding up to VectorRefreshBatchSize users per statement.
+	VectorRefreshInterval  time.Duration `yaml:"vector_refresh_interval" env:"MATCHING_VECTOR_REFRESH_INTERVAL"`
+	VectorRefreshBatchSize int           `yaml:"vector_refresh_batch_size" env:"MATCHING_VECTOR_REFRESH_BATCH_SIZE"`
+	// RecommendationTTL is how long cached partner and skill recommendations
+	// are fresh; stale ones are served for RecommendationMaxStale more while
+	// they are recomputed.
+	RecommendationTTL      time.Duration `yaml:"recommendation_ttl" env:"MATCHING_RECOMMENDATION_TTL"`
+	RecommendationMaxStale time.Duration `yaml:"recommendation_max_stale" env:"MATCHING_RECOMMENDATION_MAX_STALE"`
+	// RecommendationSettle delays recomputing a user after a profile edit, so
+	// a burst of edits is recomputed once.
+	RecommendationSettle           time.Duration `yaml:"recommendation_settle" env:"MATCHING_RECOMMENDATION_SETTLE"`
+	RecommendationRefreshInterval  time.Duration `yaml:"recommendation_refresh_interval" env:"MATCHING_RECOMMENDATION_REFRESH_INTERVAL"`
+	RecommendationRefreshBatchSize int           `yaml:"recommendation_refresh_batch_size" env:"MATCHING_RECOMMENDATION_REFRESH_BATCH_SIZE"`
+	// RankingModel is a model written by cmd/ranktrain. When set, users in
+	// the matching_learned_ranking rollout get FindMatches re-ranked by it.
+	RankingModel string `yaml:"ranking_model" env:"MATCHING_RANKING_MODEL"`
+}
+
+type EmbeddingConfig struct {
+	// Provider is "local" (offline, deterministic) or "gemini".
+	Provider      string        `yaml:"provider" env:"EMBEDDING_PROVIDER"`
+	Model         string        `yaml:"model" env:"EMBEDDING_MODEL"`
+	GeminiAPIKey  string        `yaml:"gemini_api_key" env:"GEMINI_API_KEY" secret:"true"`
+	GeminiBaseURL string        `yaml:"gemini_base_url" env:"EMBEDDING_GEMINI_BASE_URL"`
+	Timeout       time.Duration `yaml:"timeout" env:"EMBEDDING_TIMEOUT"`
+	// BatchSize texts are sent per provider call, capped by the provider.
+	BatchSize int `yaml:"batch_size" env:"EMBEDDING_BATCH_SIZE"`
+	// CacheSize vectors are kept in memory by content hash.
+	CacheSize int `yaml:"cache_size" env:"EMBEDDING_CACHE_SIZE"`
+	// ReembedInterval is how often changed skills and profiles are re-embedded.
+	ReembedInterval time.Duration `yaml:"reembed_interval" env:"EMBEDDING_REEMBED_INTERVAL"`
+}
+
+type VectorSearchConfig struct {
+	// Backend is "postgres" (pgvector) or "memory", an HNSW graph loaded
+	// from Postgres every SyncInterval.
+	Backend string `yaml:"backend" env:"VECTOR_SEARCH_BACKEND"`
+	// Metric is "cosine", "l2" or "inner_product".
+	Metric string `yaml:"metric" env:"VECTOR_SEARCH_METRIC"`
+	// Probes is ivfflat.probes for pgvector queries; more probes trade
+	// latency for recall, 0 keeps the server setting.
+	Probes             int           `yaml:"probes" env:"VECTOR_SEARCH_PROBES"`
+	HNSWM              int           `yaml:"hnsw_m" env:"VECTOR_SEARCH_HNSW_M"`
+	HNSWEfConstruction int           `yaml:"hnsw_ef_construction" env:"VECTOR_SEARCH_HNSW_EF_CONSTRUCTION"`
+	HNSWEfSearch       int           `yaml:"hnsw_ef_search" env:"VECTOR_SEARCH_HNSW_EF_SEARCH"`
+	SyncInterval       time.Duration `yaml:"sync_interval" env:"VECTOR_SEARCH_SYNC_INTERVAL"`
 }
 
 type ObservabilityConfig struct {
-	MetricsEnabled bool
-	MetricsPort    int
+	MetricsEnabled bool `yaml:"metrics_enabled" env:"METRICS_ENABLED"`
+	MetricsPort    int  `yaml:"metrics_port" env:"METRICS_PORT"`
 }
 
 type ProfilingConfig struct {
-	Enabled bool
-	Port    int
+	Enabled bool `yaml:"enabled" env:"PPROF_ENABLED"`
+	Port    int  `yaml:"port" env:"PPROF_PORT"`
 }
 
-// Load reads configuration from environment variables
-func Load() (*Config, error) {
-	cfg := &Config{
+// Default returns the built-in configuration layer.
+func Default() *Config {
+	return &Config{
+		Environment: EnvDevelopment,
 		Server: ServerConfig{
-			Host:               getEnv("SERVER_HOST", "localhost"),
-			Port:               getEnvAsInt("SERVER_PORT", 8080),
-			RateLimitPerSecond: getEnvAsInt("SERVER_RATE_LIMIT_PER_SECOND", 100),
-			RateLimitBurst:     getEnvAsInt("SERVER_RATE_LIMIT_BURST", 200),
+			Host:               "localhost",
+			Port:               8080,
+			RateLimitPerSecond: 100,
+			RateLimitBurst:     200,
+			ReadTimeout:        15 * time.Second,
+			WriteTimeout:       15 * time.Second,
+			IdleTimeout:        60 * time.Second,
+			ShutdownTimeout:    30 * time.Second,
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnvAsInt("DB_PORT", 5438),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			Database: getEnv("DB_NAME", "skillsphere"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:     "localhost",
+			Port:     5438,
+			User:     "postgres",
+			Password: "postgres",
+			Database: "skillsphere",
+			SSLMode:  "disable",
+
+			MaxConns:        25,
+			MinConns:        5,
+			MaxConnLifetime: 5 * time.Minute,
+			MaxConnIdleTime: 10 * time.Minute,
+
+			SlowQueryThreshold: 200 * time.Millisecond,
+
+			MaxReplicaLag:        5 * time.Second,
+			ReadYourWritesWindow: 5 * time.Second,
 		},
 		Auth: AuthConfig{
-			JWTSecret: getEnv("JWT_SECRET", "changeme"),
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+		},
+		Email: EmailConfig{
+			FromName:    "SkillSphere",
+			FrontendURL: "http://localhost:3000",
+		},
+		Ontology: OntologyConfig{
+			KafkaTopic:          "skillsphere.ontology",
+			KafkaClientID:       "skillsphere-ontologyworker",
+			KafkaAcks:           "all",
+			KafkaCompression:    "gzip",
+			KafkaBatchMaxBytes:  1 << 20,
+			KafkaRequestTimeout: 10 * time.Second,
+			TripleStoreTimeout:  30 * time.Second,
+			PollInterval:        2 * time.Second,
+			MaxAttempts:         10,
+			RetryBaseDelay:      5 * time.Second,
+			RetryMaxDelay:       time.Hour,
+			ValidateShapes:      true,
+			Listen:              true,
+			BatchSize:           100,
+			Concurrency:         8,
+			LeaseDuration:       time.Minute,
+			Retention:           7 * 24 * time.Hour,
+			RetentionInterval:   time.Hour,
+			QueryIndexInterval:  5 * time.Second,
+			QueryTimeout:        10 * time.Second,
+		},
+		Matching: MatchingConfig{
+			AvailabilityWeight: 0.2,
+			TargetOverlap:      2 * time.Hour,
+			SharedSlots:        3,
+
+			VectorRefreshInterval:  5 * time.Second,
+			VectorRefreshBatchSize: 500,
+
+			RecommendationTTL:              6 * time.Hour,
+			RecommendationMaxStale:         24 * time.Hour,
+			RecommendationSettle:           5 * time.Second,
+			RecommendationRefreshInterval:  5 * time.Second,
+			RecommendationRefreshBatchSize: 50,
+		},
+		Embedding: EmbeddingConfig{
+			Provider:        "local",
+			Timeout:         30 * time.Second,
+			BatchSize:       100,
+			CacheSize:       10000,
+			ReembedInterval: time.Minute,
+		},
+		VectorSearch: VectorSearchConfig{
+			Backend:            "postgres",
+			Metric:             "cosine",
+			Probes:             10,
+			HNSWM:              16,
+			HNSWEfConstruction: 200,
+			HNSWEfSearch:       64,
+			SyncInterval:       time.Minute,
 		},
 		Observability: ObservabilityConfig{
-			MetricsEnabled: getEnvAsBool("METRICS_ENABLED", true),
-			MetricsPort:    getEnvAsInt("METRICS_PORT", 9090),
+			MetricsEnabled: true,
+			MetricsPort:    9090,
 		},
 		Profiling: ProfilingConfig{
-			Enabled: getEnvAsBool("PPROF_ENABLED", false),
-			Port:    getEnvAsInt("PPROF_PORT", 6060),
+			Enabled: false,
+			Port:    6060,
 		},
 	}
+}
 
-	return cfg, nil
+// Load resolves every configuration layer and validates the settings shared
+// by every binary.
+func Load() (*Config, error) {
+	return load((*Config).Validate)
 }
 
-// DSN returns the database connection string
-func (c *DatabaseConfig) DSN() string {
-	return fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		c.Host, c.Port, c.User, c.Password, c.Database, c.SSLMode,
-	)
+// LoadAPI is Load for the API server, which also needs the settings checked
+// by ValidateAPI.
+func LoadAPI() (*Config, error) {
+	return load((*Config).ValidateAPI)
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+func load(validate func(*Config) error) (*Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadYAML(path); err != nil {
+			return nil, err
+		}
 	}
-	return defaultValue
-}
 
-func getEnvAsInt(key string, defaultValue int) int {
-	valueStr := os.Getenv(key)
-	if value, err := strconv.Atoi(valueStr); err == nil {
-		return value
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
 	}
-	return defaultValue
-}
 
-func getEnvAsBool(key string, defaultValue bool) bool {
-	valueStr := os.Getenv(key)
-	if value, err := strconv.ParseBool(valueStr); err == nil {
-		return value
+	if err := validate(cfg); err != nil {
+		return nil, err
 	}
-	return defaultValue
+
+	return cfg, nil
+}
+
+// IsProduction reports whether insecure settings must be rejected.
+func (c *Config) IsProduction() bool {
+	return c.Environment == EnvProduction
+}
+
+// DSN returns the database connection string
+func (c *DatabaseConfig) DSN() string {
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		c.Host, c.Port, c.User, c.Password, c.Database, c.SSLMode,
+	)
 }
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const strongSecret = "0123456789abcdef0123456789abcdef-strong"
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoad_LayersDefaultsYAMLEnvAndSecretFiles(t *testing.T) {
+	configFile := writeFile(t, "config.yaml", `
+server:
+  port: 9000
+  read_timeout: 5s
+database:
+  host: yaml-host
+  max_conns: 40
+ontology:
+  kafka_topic: yaml.topic
+`)
+	secretFile := writeFile(t, "jwt", strongSecret+"\n")
+
+	t.Setenv("CONFIG_FILE", configFile)
+	t.Setenv("DB_HOST", "env-host")
+	t.Setenv("JWT_SECRET", "ignored-because-file-wins")
+	t.Setenv("JWT_SECRET_FILE", secretFile)
+	t.Setenv("DB_REPLICA_URLS", "postgres://a, postgres://b")
+	t.Setenv("MATCHING_AVAILABILITY_WEIGHT", "0.35")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.Server.Port != 9000 || cfg.Server.ReadTimeout != 5*time.Second {
+		t.Errorf("yaml layer not applied: port=%d read_timeout=%s", cfg.Server.Port, cfg.Server.ReadTimeout)
+	}
+	if cfg.Server.WriteTimeout != 15*time.Second {
+		t.Errorf("default write timeout lost: %s", cfg.Server.WriteTimeout)
+	}
+	if cfg.Database.Host != "env-host" {
+		t.Errorf("env should override yaml, got host %q", cfg.Database.Host)
+	}
+	if cfg.Database.MaxConns != 40 {
+		t.Errorf("max_conns = %d, want 40", cfg.Database.MaxConns)
+	}
+	if cfg.Auth.JWTSecret != strongSecret {
+		t.Errorf("secret file not applied, got %q", cfg.Auth.JWTSecret)
+	}
+	if len(cfg.Database.ReplicaURLs) != 2 || cfg.Database.ReplicaURLs[1] != "postgres://b" {
+		t.Errorf("replica urls = %v", cfg.Database.ReplicaURLs)
+	}
+	if cfg.Matching.AvailabilityWeight != 0.35 {
+		t.Errorf("availability weight = %v, want 0.35", cfg.Matching.AvailabilityWeight)
+	}
+	if cfg.Ontology.KafkaTopic != "yaml.topic" {
+		t.Errorf("kafka topic = %q", cfg.Ontology.KafkaTopic)
+	}
+}
+
+func TestLoad_RejectsMalformedEnv(t *testing.T) {
+	t.Setenv("JWT_SECRET", strongSecret)
+	t.Setenv("DB_PORT", "not-a-port")
+	t.Setenv("DB_SLOW_QUERY_THRESHOLD", "fast")
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected error for malformed env values")
+	}
+	for _, key := range []string{"DB_PORT", "DB_SLOW_QUERY_THRESHOLD"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error %q does not mention %s", err, key)
+		}
+	}
+}
+
+func TestValidate_RefusesInsecureProductionValues(t *testing.T) {
+	cfg := Default()
+	cfg.Environment = EnvProduction
+	cfg.Auth.JWTSecret = "changeme"
+
+	err := cfg.ValidateAPI()
+	if err == nil {
+		t.Fatal("expected production validation to fail")
+	}
+	for _, want := range []string{"auth.jwt_secret", "database.sslmode", "database.password"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+
+	cfg.Auth.JWTSecret = strongSecret
+	cfg.Database.SSLMode = "verify-full"
+	cfg.Database.Password = "a-real-password"
+	cfg.Email.FrontendURL = "https://skillsphere.dev"
+	if err := cfg.ValidateAPI(); err != nil {
+		t.Fatalf("secure production config rejected: %v", err)
+	}
+}
+
+func TestValidate_ScopesAuthToTheAPI(t *testing.T) {
+	cfg := Default()
+	cfg.Auth.JWTSecret = ""
+	cfg.Server.Port = 0
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("workers should not need API settings: %v", err)
+	}
+	err := cfg.ValidateAPI()
+	if err == nil {
+		t.Fatal("expected API validation to fail")
+	}
+	for _, want := range []string{"auth.jwt_secret", "server.port"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestWriteRedacted_HidesSecrets(t *testing.T) {
+	cfg := Default()
+	cfg.Auth.JWTSecret = strongSecret
+	cfg.Database.ReplicaURLs = []string{"postgres://user:pw@replica/db"}
+	cfg.Email.SMTPPassword = "smtp-password"
+
+	var buf bytes.Buffer
+	if err := cfg.WriteRedacted(&buf); err != nil {
+		t.Fatalf("WriteRedacted: %v", err)
+	}
+	out := buf.String()
+	for _, secret := range []string{strongSecret, "smtp-password", "user:pw", "password: postgres"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("output leaks %q:\n%s", secret, out)
+		}
+	}
+	if !strings.Contains(out, "slow_query_threshold: 200ms") {
+		t.Errorf("durations should print readably:\n%s", out)
+	}
+	if cfg.Auth.JWTSecret != strongSecret || cfg.Database.ReplicaURLs[0] == redacted {
+		t.Error("redaction mutated the original config")
+	}
+}
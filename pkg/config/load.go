@@ -0,0 +1,166 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v2"
+)
+
+const redacted = "[REDACTED]"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// loadYAML overlays the values present in the file at path onto c.
+func (c *Config) loadYAML(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv overlays environment variables onto cfg. For every env-tagged field,
+// "<VAR>_FILE" names a file whose trimmed contents take precedence over "<VAR>",
+// which is how mounted secrets (Docker, Kubernetes) are consumed.
+func applyEnv(cfg *Config) error {
+	var errs []error
+	walkFields(reflect.ValueOf(cfg).Elem(), func(field reflect.StructField, value reflect.Value) {
+		key := field.Tag.Get("env")
+		if key == "" {
+			return
+		}
+
+		raw, source, ok, err := lookupEnv(key)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		if !ok {
+			return
+		}
+		if err := setValue(value, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+func lookupEnv(key string) (value, source string, ok bool, err error) {
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", "", false, fmt.Errorf("%s_FILE: %w", key, err)
+		}
+		return strings.TrimSpace(string(data)), key + "_FILE", true, nil
+	}
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value, key, true, nil
+	}
+	return "", "", false, nil
+}
+
+func setValue(value reflect.Value, raw string) error {
+	if value.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		value.SetInt(int64(d))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		value.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		value.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		value.SetBool(b)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice type %s", value.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type %s", value.Type())
+	}
+	return nil
+}
+
+// walkFields calls fn for every leaf field of v, descending into nested structs.
+func walkFields(v reflect.Value, fn func(reflect.StructField, reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if value.Kind() == reflect.Struct && value.Type() != durationType {
+			walkFields(value, fn)
+			continue
+		}
+		fn(field, value)
+	}
+}
+
+// Redacted returns a copy of c with every secret-tagged value replaced.
+func (c *Config) Redacted() *Config {
+	out := *c
+	out.Database.ReplicaURLs = append([]string(nil), c.Database.ReplicaURLs...)
+	walkFields(reflect.ValueOf(&out).Elem(), func(field reflect.StructField, value reflect.Value) {
+		if field.Tag.Get("secret") != "true" {
+			return
+		}
+		switch value.Kind() {
+		case reflect.String:
+			if value.Len() > 0 {
+				value.SetString(redacted)
+			}
+		case reflect.Slice:
+			for i := 0; i < value.Len(); i++ {
+				value.Index(i).SetString(redacted)
+			}
+		}
+	})
+	return &out
+}
+
+// WriteRedacted writes the effective configuration as YAML with secrets redacted.
+func (c *Config) WriteRedacted(w io.Writer) error {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/kafka"
+)
+
+const minProductionSecretLength = 32
+
+// knownWeakSecrets are placeholders from defaults and .env.example that must never reach production.
+var knownWeakSecrets = []string{
+	"changeme",
+	"secret",
+	"your-super-secret-jwt-key-min-32-characters-long-please-change-this",
+	"your-session-secret-for-oauth-min-32-characters",
+}
+
+// Validate checks the settings every binary shares for missing or
+// out-of-range values. In production it also refuses unencrypted or
+// default-password database connections.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	switch c.Environment {
+	case EnvDevelopment, EnvTest, EnvStaging, EnvProduction:
+	default:
+		fail("environment: unknown value %q", c.Environment)
+	}
+
+	if c.Database.Host == "" || c.Database.User == "" || c.Database.Database == "" {
+		fail("database: host, user and name are required")
+	}
+	if c.Database.MaxConns <= 0 {
+		fail("database.max_conns: must be positive")
+	}
+	if c.Database.MinConns < 0 || c.Database.MinConns > c.Database.MaxConns {
+		fail("database.min_conns: must be between 0 and max_conns")
+	}
+
+	if _, err := kafka.ParseAcks(c.Ontology.KafkaAcks); err != nil {
+		fail("ontology.kafka_acks: %v", err)
+	}
+	if _, err := kafka.ParseCompression(c.Ontology.KafkaCompression); err != nil {
+		fail("ontology.kafka_compression: %v", err)
+	}
+	if c.Ontology.KafkaLinger < 0 || c.Ontology.KafkaBatchMaxBytes <= 0 || c.Ontology.KafkaRequestTimeout <= 0 {
+		fail("ontology: kafka_linger must not be negative; kafka_batch_max_bytes and kafka_request_timeout must be positive")
+	}
+	if c.Ontology.TripleStoreUpdateEndpoint != "" {
+		if _, err := url.ParseRequestURI(c.Ontology.TripleStoreUpdateEndpoint); err != nil {
+			fail("ontology.triplestore_update_endpoint: %v", err)
+		}
+	}
+	if c.Ontology.TripleStoreTimeout <= 0 {
+		fail("ontology.triplestore_timeout: must be positive")
+	}
+	if c.Ontology.PollInterval <= 0 {
+		fail("ontology.poll_interval: must be positive")
+	}
+	if c.Ontology.MaxAttempts <= 0 {
+		fail("ontology.max_attempts: must be positive")
+	}
+	if c.Ontology.RetryBaseDelay <= 0 || c.Ontology.RetryMaxDelay < c.Ontology.RetryBaseDelay {
+		fail("ontology: retry_base_delay must be positive and not exceed retry_max_delay")
+	}
+	if c.Ontology.BatchSize <= 0 || c.Ontology.Concurrency <= 0 {
+		fail("ontology: batch_size and concurrency must be positive")
+	}
+	if c.Ontology.LeaseDuration <= c.Ontology.TripleStoreTimeout || c.Ontology.LeaseDuration <= c.Ontology.KafkaRequestTimeout {
+		fail("ontology.lease_duration: must exceed triplestore_timeout and kafka_request_timeout")
+	}
+	if c.Ontology.Retention < 0 || (c.Ontology.Retention > 0 && c.Ontology.RetentionInterval <= 0) {
+		fail("ontology: retention must not be negative and needs a positive retention_interval")
+	}
+	if c.Ontology.QueryIndex && (c.Ontology.QueryIndexInterval <= 0 || c.Ontology.QueryTimeout <= 0) {
+		fail("ontology: query_index_interval and query_timeout must be positive when query_index is enabled")
+	}
+
+	if c.Matching.AvailabilityWeight < 0 || c.Matching.AvailabilityWeight > 1 {
+		fail("matching.availability_weight: must be between 0 and 1")
+	}
+	if c.Matching.TargetOverlap <= 0 || c.Matching.MinOverlap < 0 || c.Matching.SharedSlots < 0 {
+		fail("matching: target_overlap must be positive; min_overlap and shared_slots must not be negative")
+	}
+	if c.Matching.VectorRefreshInterval <= 0 || c.Matching.VectorRefreshBatchSize <= 0 {
+		fail("matching: vector_refresh_interval and vector_refresh_batch_size must be positive")
+	}
+	if c.Matching.RecommendationTTL <= 0 || c.Matching.RecommendationRefreshInterval <= 0 || c.Matching.RecommendationRefreshBatchSize <= 0 {
+		fail("matching: recommendation_ttl, recommendation_refresh_interval and recommendation_refresh_batch_size must be positive")
+	}
+	if c.Matching.RecommendationMaxStale < 0 || c.Matching.RecommendationSettle < 0 {
+		fail("matching: recommendation_max_stale and recommendation_settle must not be negative")
+	}
+
+	switch c.Embedding.Provider {
+	case "local":
+	case "gemini":
+		if c.Embedding.GeminiAPIKey == "" {
+			fail("embedding.gemini_api_key: required for the gemini provider")
+		}
+	default:
+		fail("embedding.provider: unknown value %q", c.Embedding.Provider)
+	}
+	if c.Embedding.Timeout <= 0 || c.Embedding.BatchSize <= 0 || c.Embedding.CacheSize <= 0 || c.Embedding.ReembedInterval <= 0 {
+		fail("embedding: timeout, batch_size, cache_size and reembed_interval must be positive")
+	}
+
+	switch c.VectorSearch.Backend {
+	case "postgres", "memory":
+	default:
+		fail("vector_search.backend: unknown value %q", c.VectorSearch.Backend)
+	}
+	switch c.VectorSearch.Metric {
+	case "cosine", "l2", "inner_product":
+	default:
+		fail("vector_search.metric: unknown value %q", c.VectorSearch.Metric)
+	}
+	if c.VectorSearch.Probes < 0 {
+		fail("vector_search.probes: must not be negative")
+	}
+	if c.VectorSearch.HNSWM < 2 || c.VectorSearch.HNSWEfConstruction <= 0 || c.VectorSearch.HNSWEfSearch <= 0 || c.VectorSearch.SyncInterval <= 0 {
+		fail("vector_search: hnsw_m must be at least 2; hnsw_ef_construction, hnsw_ef_search and sync_interval must be positive")
+	}
+
+	if c.IsProduction() {
+		errs = append(errs, c.validateProductionDatabase()...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateAPI runs Validate and also checks the settings only the API server
+// reads: the listener, authentication and email. In production it refuses
+// placeholder secrets and enabled profiling.
+func (c *Config) ValidateAPI() error {
+	errs := []error{c.Validate()}
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		fail("server.port: must be between 1 and 65535")
+	}
+	if c.Server.RateLimitPerSecond < 0 || c.Server.RateLimitBurst < 0 {
+		fail("server: rate limits must not be negative")
+	}
+	if c.Server.ReadTimeout <= 0 || c.Server.WriteTimeout <= 0 || c.Server.IdleTimeout <= 0 || c.Server.ShutdownTimeout <= 0 {
+		fail("server: timeouts must be positive")
+	}
+
+	if c.Auth.JWTSecret == "" {
+		fail("auth.jwt_secret: required")
+	}
+	if c.Auth.AccessTokenTTL <= 0 || c.Auth.RefreshTokenTTL <= 0 {
+		fail("auth: token TTLs must be positive")
+	}
+	if c.Auth.RefreshTokenTTL < c.Auth.AccessTokenTTL {
+		fail("auth.refresh_token_ttl: must not be shorter than access_token_ttl")
+	}
+
+	if c.Email.SMTPHost != "" && (c.Email.SMTPPort <= 0 || c.Email.FromEmail == "") {
+		fail("email: smtp_port and from_email are required when smtp_host is set")
+	}
+	if c.Email.FrontendURL != "" {
+		if _, err := url.ParseRequestURI(c.Email.FrontendURL); err != nil {
+			fail("email.frontend_url: %v", err)
+		}
+	}
+
+	if c.IsProduction() {
+		errs = append(errs, c.validateProductionAPI()...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Config) validateProductionDatabase() []error {
+	var errs []error
+	if c.Database.SSLMode == "disable" || c.Database.SSLMode == "allow" || c.Database.SSLMode == "prefer" {
+		errs = append(errs, fmt.Errorf("database.sslmode: %q is not allowed in production", c.Database.SSLMode))
+	}
+	if c.Database.Password == "" || c.Database.Password == "postgres" {
+		errs = append(errs, errors.New("database.password: default or empty password is not allowed in production"))
+	}
+	return errs
+}
+
+func (c *Config) validateProductionAPI() []error {
+	var errs []error
+	if weakSecret(c.Auth.JWTSecret) {
+		errs = append(errs, fmt.Errorf("auth.jwt_secret: must be at least %d characters and not a placeholder in production", minProductionSecretLength))
+	}
+	if c.hasOAuthProvider() && weakSecret(c.OAuth.SessionSecret) {
+		errs = append(errs, fmt.Errorf("oauth.session_secret: must be at least %d characters and not a placeholder in production", minProductionSecretLength))
+	}
+	if c.Profiling.Enabled {
+		errs = append(errs, errors.New("profiling.enabled: pprof must be disabled in production"))
+	}
+	if strings.HasPrefix(c.Email.FrontendURL, "http://") {
+		errs = append(errs, errors.New("email.frontend_url: must use https in production"))
+	}
+	return errs
+}
+
+func (c *Config) hasOAuthProvider() bool {
+	return c.OAuth.GoogleClientID != "" || c.OAuth.AppleClientID != ""
+}
+
+func weakSecret(secret string) bool {
+	if len(secret) < minProductionSecretLength {
+		return true
+	}
+	for _, known := range knownWeakSecrets {
+		if strings.EqualFold(secret, known) {
+			return true
+		}
+	}
+	return false
+}
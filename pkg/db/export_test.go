@@ -0,0 +1,13 @@
+package db
+
+import "database/sql"
+
+// NewRoutedSQL routes reads to a healthy replica handle until a write, for
+// tests outside the package.
+func NewRoutedSQL(primary, replicaDB *sql.DB) *SQL {
+	r := healthyReplica("replica-0", 0)
+	r.sql = replicaDB
+	d := newRoutingTestDB(r)
+	d.sql = &SQL{db: d, primary: primary}
+	return d.sql
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	listenInitialBackoff = time.Second
+	listenMaxBackoff     = 30 * time.Second
+)
+
+// Notification is a payload delivered on a Postgres NOTIFY channel.
+type Notification struct {
+	Channel string
+	Payload string
+}
+
+// Listen subscribes to a NOTIFY channel on a dedicated primary connection and calls
+// fn for every notification until ctx is cancelled. Lost connections are re-established
+// with exponential backoff. onConnect runs after every successful LISTEN so callers can
+// resynchronise state that changed while they were disconnected; it may be nil.
+func (d *DB) Listen(ctx context.Context, channel string, onConnect func(context.Context), fn func(Notification)) error {
+	backoff := listenInitialBackoff
+	for {
+		err := d.listenOnce(ctx, channel, func() {
+			backoff = listenInitialBackoff
+			if onConnect != nil {
+				onConnect(ctx)
+			}
+		}, fn)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		d.logger.Warn("notification listener disconnected", "channel", channel, "error", err, "retry_in", backoff.String())
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, listenMaxBackoff)
+	}
+}
+
+func (d *DB) listenOnce(ctx context.Context, channel string, connected func(), fn func(Notification)) error {
+	pooled, err := d.Pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire listener connection: %w", err)
+	}
+	// A LISTENing session must not be handed back to the pool.
+	conn := pooled.Hijack()
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return fmt.Errorf("listen %s: %w", channel, err)
+	}
+	connected()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+		fn(Notification{Channel: n.Channel, Payload: n.Payload})
+	}
+}
@@ -0,0 +1,35 @@
+-- +goose Up
+-- Percentage of users (bucketed by hashed user ID) that see an enabled flag.
+-- Defaults to 100 so flags that were already enabled stay fully on.
+ALTER TABLE feature_flags
+    ADD COLUMN IF NOT EXISTS rollout_percentage SMALLINT NOT NULL DEFAULT 100
+        CHECK (rollout_percentage BETWEEN 0 AND 100);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_feature_flags_changed() RETURNS trigger AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'feature_flags' THEN
+        PERFORM pg_notify('feature_flags_changed', COALESCE(NEW.name, OLD.name));
+    ELSE
+        PERFORM pg_notify('feature_flags_changed', COALESCE(NEW.feature_name, OLD.feature_name));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS feature_flags_changed ON feature_flags;
+CREATE TRIGGER feature_flags_changed
+    AFTER INSERT OR UPDATE OR DELETE ON feature_flags
+    FOR EACH ROW EXECUTE FUNCTION notify_feature_flags_changed();
+
+DROP TRIGGER IF EXISTS feature_flag_users_changed ON feature_flag_users;
+CREATE TRIGGER feature_flag_users_changed
+    AFTER INSERT OR UPDATE OR DELETE ON feature_flag_users
+    FOR EACH ROW EXECUTE FUNCTION notify_feature_flags_changed();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS feature_flag_users_changed ON feature_flag_users;
+DROP TRIGGER IF EXISTS feature_flags_changed ON feature_flags;
+DROP FUNCTION IF EXISTS notify_feature_flags_changed();
+ALTER TABLE feature_flags DROP COLUMN IF EXISTS rollout_percentage;
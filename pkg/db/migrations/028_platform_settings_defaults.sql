@@ -0,0 +1,28 @@
+-- +goose Up
+INSERT INTO platform_settings (key, value, description) VALUES
+    ('maintenance_mode', 'false', 'Enable to reject all non-admin requests with a maintenance message'),
+    ('maintenance_message', '', 'Message returned to clients while maintenance mode is on'),
+    ('new_signups_enabled', 'true', 'Disable to prevent new user registrations'),
+    ('max_sessions_per_day', '0', 'Maximum sessions a user may book per day (0 = unlimited)'),
+    ('min_session_rating', '0', 'Minimum average rating (0-5) required to host sessions'),
+    ('auto_approve_certifications', 'false', 'Approve uploaded certifications without manual review'),
+    ('platform_fee_percentage', '0', 'Fee charged on paid sessions, as a percentage (0-100)')
+ON CONFLICT (key) DO NOTHING;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_platform_settings_changed() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('platform_settings_changed', COALESCE(NEW.key, OLD.key));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS platform_settings_changed ON platform_settings;
+CREATE TRIGGER platform_settings_changed
+    AFTER INSERT OR UPDATE OR DELETE ON platform_settings
+    FOR EACH ROW EXECUTE FUNCTION notify_platform_settings_changed();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS platform_settings_changed ON platform_settings;
+DROP FUNCTION IF EXISTS notify_platform_settings_changed();
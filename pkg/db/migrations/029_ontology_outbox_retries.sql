@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TABLE ontology_outbox
+    ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_error TEXT,
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS dead_lettered_at TIMESTAMPTZ;
+
+-- Pending rows that are due, in delivery order.
+CREATE INDEX IF NOT EXISTS idx_ontology_outbox_pending
+    ON ontology_outbox (next_attempt_at, created_at)
+    WHERE delivered_at IS NULL AND dead_lettered_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_ontology_outbox_dead_lettered
+    ON ontology_outbox (dead_lettered_at)
+    WHERE dead_lettered_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_ontology_outbox_dead_lettered;
+DROP INDEX IF EXISTS idx_ontology_outbox_pending;
+ALTER TABLE ontology_outbox
+    DROP COLUMN IF EXISTS dead_lettered_at,
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS attempts;
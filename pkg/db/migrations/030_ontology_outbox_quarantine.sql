@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TABLE ontology_outbox
+    ADD COLUMN IF NOT EXISTS quarantined_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS validation_report JSONB;
+
+-- Quarantined rows are no longer pending.
+DROP INDEX IF EXISTS idx_ontology_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_ontology_outbox_pending
+    ON ontology_outbox (next_attempt_at, created_at)
+    WHERE delivered_at IS NULL AND dead_lettered_at IS NULL AND quarantined_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_ontology_outbox_quarantined
+    ON ontology_outbox (quarantined_at)
+    WHERE quarantined_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_ontology_outbox_quarantined;
+DROP INDEX IF EXISTS idx_ontology_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_ontology_outbox_pending
+    ON ontology_outbox (next_attempt_at, created_at)
+    WHERE delivered_at IS NULL AND dead_lettered_at IS NULL;
+ALTER TABLE ontology_outbox
+    DROP COLUMN IF EXISTS validation_report,
+    DROP COLUMN IF EXISTS quarantined_at;
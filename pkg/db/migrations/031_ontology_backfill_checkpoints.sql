@@ -0,0 +1,13 @@
+-- +goose Up
+-- Progress of `ontologyworker backfill`, one row per source table.
+CREATE TABLE IF NOT EXISTS ontology_backfill_checkpoints (
+    source TEXT PRIMARY KEY,
+    last_key TEXT NOT NULL,
+    events BIGINT NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS ontology_backfill_checkpoints;
@@ -0,0 +1,43 @@
+-- +goose Up
+-- seq gives a total order within an aggregate (created_at can tie), and
+-- aggregate_id is the JSON-LD @id whose events must be delivered in order.
+ALTER TABLE ontology_outbox
+    ADD COLUMN IF NOT EXISTS seq BIGSERIAL,
+    ADD COLUMN IF NOT EXISTS aggregate_id TEXT,
+    ADD COLUMN IF NOT EXISTS claimed_by TEXT,
+    ADD COLUMN IF NOT EXISTS lease_until TIMESTAMPTZ;
+
+UPDATE ontology_outbox SET aggregate_id = payload->>'@id' WHERE aggregate_id IS NULL;
+
+-- Undelivered rows per aggregate, for the "no earlier pending event" check.
+CREATE INDEX IF NOT EXISTS idx_ontology_outbox_aggregate_pending
+    ON ontology_outbox (aggregate_id, seq)
+    WHERE delivered_at IS NULL AND dead_lettered_at IS NULL AND quarantined_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_ontology_outbox_delivered
+    ON ontology_outbox (delivered_at)
+    WHERE delivered_at IS NOT NULL;
+
+-- Delivered rows moved out of ontology_outbox by the retention job.
+CREATE TABLE IF NOT EXISTS ontology_outbox_archive (
+    id UUID PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    aggregate_id TEXT,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    delivered_at TIMESTAMPTZ NOT NULL,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ontology_outbox_archive_delivered_at ON ontology_outbox_archive (delivered_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS ontology_outbox_archive;
+DROP INDEX IF EXISTS idx_ontology_outbox_delivered;
+DROP INDEX IF EXISTS idx_ontology_outbox_aggregate_pending;
+ALTER TABLE ontology_outbox
+    DROP COLUMN IF EXISTS lease_until,
+    DROP COLUMN IF EXISTS claimed_by,
+    DROP COLUMN IF EXISTS aggregate_id,
+    DROP COLUMN IF EXISTS seq;
@@ -0,0 +1,54 @@
+-- +goose Up
+-- SKOS taxonomy over the skills catalogue. skos:narrower is the inverse of
+-- skos:broader and is not stored separately.
+CREATE TABLE IF NOT EXISTS skill_broader (
+    skill_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+    broader_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (skill_id, broader_id),
+    CHECK (skill_id <> broader_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_skill_broader_broader_id ON skill_broader (broader_id);
+
+-- skos:related is symmetric, so each pair is stored once with the lower id first.
+CREATE TABLE IF NOT EXISTS skill_related (
+    skill_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+    related_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (skill_id, related_id),
+    CHECK (skill_id < related_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_skill_related_related_id ON skill_related (related_id);
+
+-- skos:altLabel synonyms. normalized_label is unique across the catalogue so
+-- that a synonym resolves to exactly one skill.
+CREATE TABLE IF NOT EXISTS skill_alt_labels (
+    skill_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+    label VARCHAR(100) NOT NULL,
+    language VARCHAR(35) NOT NULL DEFAULT '',
+    normalized_label VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_skill_alt_labels_skill_id ON skill_alt_labels (skill_id);
+CREATE INDEX IF NOT EXISTS idx_skills_lower_name ON skills (LOWER(name));
+
+-- Skills removed by an admin merge, so stale ids can still be resolved.
+CREATE TABLE IF NOT EXISTS skill_merges (
+    source_id UUID PRIMARY KEY,
+    target_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+    source_name VARCHAR(100) NOT NULL,
+    merged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    merged_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_skill_merges_target_id ON skill_merges (target_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS skill_merges;
+DROP INDEX IF EXISTS idx_skills_lower_name;
+DROP TABLE IF EXISTS skill_alt_labels;
+DROP TABLE IF EXISTS skill_related;
+DROP TABLE IF EXISTS skill_broader;
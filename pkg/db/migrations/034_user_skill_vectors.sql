@@ -0,0 +1,88 @@
+-- +goose Up
+-- The materialized view from 015 aggregated proficiencies without their skill,
+-- so positions in the arrays meant nothing, and it was never refreshed. A
+-- materialized view can only be refreshed whole, so vectors now live in a table
+-- rebuilt per user from a queue that user_skills writes feed.
+DROP MATERIALIZED VIEW IF EXISTS user_skill_vectors;
+
+-- offered and wanted are sparse vectors: objects from skill ID to proficiency
+-- (1-10, 0 when the user did not set one). Users without skills have no row.
+CREATE TABLE user_skill_vectors (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    offered JSONB NOT NULL DEFAULT '{}'::jsonb,
+    wanted JSONB NOT NULL DEFAULT '{}'::jsonb,
+    refreshed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- "Who offers skill X" lookups: offered ? 'skill-id'.
+CREATE INDEX idx_user_skill_vectors_offered ON user_skill_vectors USING GIN (offered);
+CREATE INDEX idx_user_skill_vectors_wanted ON user_skill_vectors USING GIN (wanted);
+
+-- One row per user whose vectors are stale; the primary key coalesces bursts
+-- of writes into a single rebuild.
+CREATE TABLE user_skill_vector_queue (
+    user_id UUID PRIMARY KEY,
+    enqueued_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_skill_vector_queue_enqueued_at ON user_skill_vector_queue (enqueued_at);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION enqueue_user_skill_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    -- DO UPDATE rather than DO NOTHING so the row stays locked until this
+    -- transaction commits: a refresh that claims it meanwhile would read the
+    -- user's skills before this write. enqueued_at keeps its original value so
+    -- busy users are not pushed to the back of the queue.
+    IF TG_OP <> 'DELETE' THEN
+        INSERT INTO user_skill_vector_queue (user_id) VALUES (NEW.user_id)
+        ON CONFLICT (user_id) DO UPDATE SET enqueued_at = user_skill_vector_queue.enqueued_at;
+    END IF;
+    IF TG_OP = 'DELETE' OR (TG_OP = 'UPDATE' AND OLD.user_id <> NEW.user_id) THEN
+        INSERT INTO user_skill_vector_queue (user_id) VALUES (OLD.user_id)
+        ON CONFLICT (user_id) DO UPDATE SET enqueued_at = user_skill_vector_queue.enqueued_at;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS user_skills_vector_refresh ON user_skills;
+CREATE TRIGGER user_skills_vector_refresh
+    AFTER INSERT OR UPDATE OF user_id, skill_id, skill_type, proficiency OR DELETE ON user_skills
+    FOR EACH ROW EXECUTE FUNCTION enqueue_user_skill_vector_refresh();
+
+-- Build every existing user's vectors on the first refresh.
+INSERT INTO user_skill_vector_queue (user_id)
+SELECT DISTINCT user_id FROM user_skills
+ON CONFLICT (user_id) DO NOTHING;
+
+-- +goose Down
+DROP TRIGGER IF EXISTS user_skills_vector_refresh ON user_skills;
+DROP FUNCTION IF EXISTS enqueue_user_skill_vector_refresh();
+DROP TABLE IF EXISTS user_skill_vector_queue;
+DROP TABLE IF EXISTS user_skill_vectors;
+
+CREATE MATERIALIZED VIEW user_skill_vectors AS
+WITH all_skills AS (
+  SELECT id, name, ROW_NUMBER() OVER (ORDER BY name) as rn
+  FROM skills
+),
+     user_skills_ranked AS (
+       SELECT
+         us.user_id,
+         ask.rn AS skill_index,
+         us.proficiency,
+         us.skill_type
+       FROM user_skills us
+              JOIN all_skills ask ON us.skill_id = ask.id
+     )
+SELECT
+  uv.user_id,
+  ARRAY_AGG(uv.proficiency) FILTER (WHERE uv.skill_type = 'offered') AS offered_vector,
+  ARRAY_AGG(uv.proficiency) FILTER (WHERE uv.skill_type = 'wanted') AS wanted_vector,
+  NULL::VECTOR(768) AS embedding_vector
+FROM user_skills_ranked uv
+GROUP BY uv.user_id;
+
+CREATE UNIQUE INDEX idx_user_skill_vectors_user_id ON user_skill_vectors (user_id);
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Bookkeeping for the re-embedding job. embedding_hash identifies the text and
+-- model the stored embedding was computed from, so rows whose updated_at moved
+-- without their text changing are not embedded again.
+ALTER TABLE skills
+    ADD COLUMN IF NOT EXISTS embedding_hash TEXT,
+    ADD COLUMN IF NOT EXISTS embedding_model TEXT,
+    ADD COLUMN IF NOT EXISTS embedded_at TIMESTAMPTZ;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS embedding_hash TEXT,
+    ADD COLUMN IF NOT EXISTS embedding_model TEXT,
+    ADD COLUMN IF NOT EXISTS embedded_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE users
+    DROP COLUMN IF EXISTS embedded_at,
+    DROP COLUMN IF EXISTS embedding_model,
+    DROP COLUMN IF EXISTS embedding_hash;
+
+ALTER TABLE skills
+    DROP COLUMN IF EXISTS embedded_at,
+    DROP COLUMN IF EXISTS embedding_model,
+    DROP COLUMN IF EXISTS embedding_hash;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- A block hides two users from each other in matching and similar-user
+-- search, whichever of them created it.
+CREATE TABLE user_blocks (
+    blocker_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX idx_user_blocks_blocked_id ON user_blocks (blocked_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_blocks;
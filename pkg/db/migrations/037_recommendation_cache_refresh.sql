@@ -0,0 +1,120 @@
+-- +goose Up
+-- GetRecommendations serves partner and skill recommendations from
+-- recommendation_cache. Rows keep what the response shows so a cache hit needs
+-- no scoring.
+ALTER TABLE recommendation_cache
+    ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+
+-- When each user's recommendations of a type were last computed. A computation
+-- that found nothing still has a row, so empty results are cached too.
+CREATE TABLE recommendation_cache_state (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_type VARCHAR(50) NOT NULL,
+    generated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, item_type)
+);
+
+-- One row per user whose recommendations are stale; the primary key coalesces
+-- bursts of profile edits into a single recompute.
+CREATE TABLE recommendation_refresh_queue (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    enqueued_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_recommendation_refresh_queue_enqueued_at ON recommendation_refresh_queue (enqueued_at);
+
+-- Partners a user passed on; they are not recommended to that user again.
+CREATE TABLE recommendation_skips (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    skipped_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    skipped_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, skipped_user_id),
+    CHECK (user_id <> skipped_user_id)
+);
+
+-- recommendation_excluded reports whether b must not be recommended to a as a
+-- partner: either blocked the other, a skipped b, or they already know each
+-- other through a conversation, a session or a match one of them acted on.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION recommendation_excluded(a UUID, b UUID) RETURNS BOOLEAN AS $$
+    SELECT EXISTS (
+               SELECT 1 FROM user_blocks
+               WHERE (blocker_id = a AND blocked_id = b) OR (blocker_id = b AND blocked_id = a))
+        OR EXISTS (
+               SELECT 1 FROM recommendation_skips
+               WHERE user_id = a AND skipped_user_id = b)
+        OR EXISTS (
+               SELECT 1 FROM conversations
+               WHERE (user_a_id = a AND user_b_id = b) OR (user_a_id = b AND user_b_id = a))
+        OR EXISTS (
+               SELECT 1 FROM sessions
+               WHERE (initiator_id = a AND partner_id = b) OR (initiator_id = b AND partner_id = a))
+        OR EXISTS (
+               SELECT 1 FROM match_history
+               WHERE interaction_initiated
+                 AND ((user_id_a = a AND user_id_b = b) OR (user_id_a = b AND user_id_b = a)));
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION enqueue_recommendation_refresh() RETURNS trigger AS $$
+DECLARE
+    changed UUID;
+BEGIN
+    -- users is keyed by id, the profile tables by user_id. DO UPDATE keeps the
+    -- row locked until this transaction commits, as for skill vectors, so a
+    -- recompute never reads the profile from before the edit.
+    IF TG_TABLE_NAME = 'users' THEN
+        changed := NEW.id;
+    ELSIF TG_OP = 'DELETE' THEN
+        changed := OLD.user_id;
+    ELSE
+        changed := NEW.user_id;
+    END IF;
+    INSERT INTO recommendation_refresh_queue (user_id)
+    SELECT changed WHERE EXISTS (SELECT 1 FROM users WHERE id = changed)
+    ON CONFLICT (user_id) DO UPDATE SET enqueued_at = recommendation_refresh_queue.enqueued_at;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS user_skills_recommendation_refresh ON user_skills;
+CREATE TRIGGER user_skills_recommendation_refresh
+    AFTER INSERT OR UPDATE OF skill_id, skill_type, proficiency OR DELETE ON user_skills
+    FOR EACH ROW EXECUTE FUNCTION enqueue_recommendation_refresh();
+
+DROP TRIGGER IF EXISTS user_availability_recommendation_refresh ON user_availability;
+CREATE TRIGGER user_availability_recommendation_refresh
+    AFTER INSERT OR UPDATE OF weekly_schedule, timezone OR DELETE ON user_availability
+    FOR EACH ROW EXECUTE FUNCTION enqueue_recommendation_refresh();
+
+DROP TRIGGER IF EXISTS users_location_recommendation_refresh ON users;
+CREATE TRIGGER users_location_recommendation_refresh
+    AFTER UPDATE OF location_geom ON users
+    FOR EACH ROW WHEN (OLD.location_geom IS DISTINCT FROM NEW.location_geom)
+    EXECUTE FUNCTION enqueue_recommendation_refresh();
+
+DROP TRIGGER IF EXISTS user_search_documents_recommendation_refresh ON user_search_documents;
+CREATE TRIGGER user_search_documents_recommendation_refresh
+    AFTER UPDATE OF location_geom ON user_search_documents
+    FOR EACH ROW WHEN (OLD.location_geom IS DISTINCT FROM NEW.location_geom)
+    EXECUTE FUNCTION enqueue_recommendation_refresh();
+
+-- Nothing wrote the cache before; drop whatever is there.
+DELETE FROM recommendation_cache;
+
+-- +goose Down
+DROP TRIGGER IF EXISTS user_search_documents_recommendation_refresh ON user_search_documents;
+DROP TRIGGER IF EXISTS users_location_recommendation_refresh ON users;
+DROP TRIGGER IF EXISTS user_availability_recommendation_refresh ON user_availability;
+DROP TRIGGER IF EXISTS user_skills_recommendation_refresh ON user_skills;
+DROP FUNCTION IF EXISTS enqueue_recommendation_refresh();
+DROP FUNCTION IF EXISTS recommendation_excluded(UUID, UUID);
+DROP TABLE IF EXISTS recommendation_skips;
+DROP TABLE IF EXISTS recommendation_refresh_queue;
+DROP TABLE IF EXISTS recommendation_cache_state;
+ALTER TABLE recommendation_cache
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS title;
@@ -0,0 +1,108 @@
+-- +goose Up
+-- What the ranking model saw when the match was shown, and which model and
+-- experiment arm ranked it. features is an object from feature name to value;
+-- model_version and variant are NULL when no model was loaded.
+ALTER TABLE match_history
+    ADD COLUMN IF NOT EXISTS features JSONB,
+    ADD COLUMN IF NOT EXISTS model_version TEXT,
+    ADD COLUMN IF NOT EXISTS variant VARCHAR(20);
+
+-- Implicit feedback on shown matches. The match_history row is the "shown"
+-- event; everything the pair did afterwards is attributed to it here.
+CREATE TABLE match_feedback (
+    id BIGSERIAL PRIMARY KEY,
+    match_id UUID NOT NULL REFERENCES match_history(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    event VARCHAR(50) NOT NULL
+        CHECK (event IN ('profile_viewed', 'chat_started', 'session_booked', 'session_completed', 'session_rated')),
+    session_id UUID REFERENCES sessions(id) ON DELETE SET NULL,
+    rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_match_feedback_match_id ON match_feedback (match_id);
+CREATE INDEX idx_match_feedback_created_at ON match_feedback (created_at);
+
+-- record_match_feedback attributes an event between actor and other to the
+-- latest match of the pair shown in the last 30 days, preferring matches
+-- shown to actor. Anything past a profile view marks the match as
+-- interaction_initiated; sessions are linked through session_id. Returns the
+-- match, or NULL when the pair was not matched recently.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION record_match_feedback(
+    actor UUID, other UUID, kind TEXT, session UUID DEFAULT NULL, score SMALLINT DEFAULT NULL
+) RETURNS UUID AS $$
+DECLARE
+    matched UUID;
+BEGIN
+    SELECT id INTO matched
+    FROM match_history
+    WHERE ((user_id_a = actor AND user_id_b = other) OR (user_id_a = other AND user_id_b = actor))
+      AND created_at > NOW() - INTERVAL '30 days'
+    ORDER BY (user_id_a = actor) DESC, created_at DESC
+    LIMIT 1;
+    IF matched IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO match_feedback (match_id, actor_id, event, session_id, rating)
+    VALUES (matched, actor, kind, session, score);
+    IF kind <> 'profile_viewed' THEN
+        UPDATE match_history
+        SET interaction_initiated = true, session_id = COALESCE(session, session_id)
+        WHERE id = matched;
+    END IF;
+    RETURN matched;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION record_match_feedback_from_activity() RETURNS trigger AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'conversations' THEN
+        PERFORM record_match_feedback(NEW.user_a_id, NEW.user_b_id, 'chat_started');
+    ELSIF TG_OP = 'INSERT' THEN
+        PERFORM record_match_feedback(NEW.initiator_id, NEW.partner_id, 'session_booked', NEW.id);
+    ELSE
+        PERFORM record_match_feedback(NEW.initiator_id, NEW.partner_id, 'session_completed', NEW.id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS conversations_match_feedback ON conversations;
+CREATE TRIGGER conversations_match_feedback
+    AFTER INSERT ON conversations
+    FOR EACH ROW EXECUTE FUNCTION record_match_feedback_from_activity();
+
+DROP TRIGGER IF EXISTS sessions_booked_match_feedback ON sessions;
+CREATE TRIGGER sessions_booked_match_feedback
+    AFTER INSERT ON sessions
+    FOR EACH ROW EXECUTE FUNCTION record_match_feedback_from_activity();
+
+DROP TRIGGER IF EXISTS sessions_completed_match_feedback ON sessions;
+CREATE TRIGGER sessions_completed_match_feedback
+    AFTER UPDATE OF status ON sessions
+    FOR EACH ROW WHEN (NEW.status = 'completed' AND OLD.status IS DISTINCT FROM 'completed')
+    EXECUTE FUNCTION record_match_feedback_from_activity();
+
+-- Users in the rollout see FindMatches re-ranked by the learned model; the
+-- rest are the control arm. Off until a model is trained.
+INSERT INTO feature_flags (name, description, is_enabled, rollout_percentage)
+VALUES ('matching_learned_ranking', 'Re-rank FindMatches with the learned ranking model (treatment arm)', false, 0)
+ON CONFLICT (name) DO NOTHING;
+
+-- +goose Down
+DELETE FROM feature_flags WHERE name = 'matching_learned_ranking';
+DROP TRIGGER IF EXISTS sessions_completed_match_feedback ON sessions;
+DROP TRIGGER IF EXISTS sessions_booked_match_feedback ON sessions;
+DROP TRIGGER IF EXISTS conversations_match_feedback ON conversations;
+DROP FUNCTION IF EXISTS record_match_feedback_from_activity();
+DROP FUNCTION IF EXISTS record_match_feedback(UUID, UUID, TEXT, UUID, SMALLINT);
+DROP TABLE IF EXISTS match_feedback;
+ALTER TABLE match_history
+    DROP COLUMN IF EXISTS variant,
+    DROP COLUMN IF EXISTS model_version,
+    DROP COLUMN IF EXISTS features;
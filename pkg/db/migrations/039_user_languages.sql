@@ -0,0 +1,7 @@
+-- +goose Up
+-- BCP 47 tags of the languages a user speaks, e.g. {pt-PT,en}. Match
+-- explanations mention the languages a pair shares.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS languages TEXT[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS languages;
@@ -0,0 +1,35 @@
+-- +goose Up
+-- The rating one participant gives the other after a session; the session
+-- service's Rating. Ratings are match feedback like the activity in 038, so a
+-- trigger records them rather than every writer.
+CREATE TABLE session_ratings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewee_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    score SMALLINT NOT NULL CHECK (score BETWEEN 1 AND 5),
+    comment TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (session_id, reviewer_id)
+);
+
+CREATE INDEX idx_session_ratings_reviewee_id ON session_ratings (reviewee_id);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION record_match_feedback_from_rating() RETURNS trigger AS $$
+BEGIN
+    PERFORM record_match_feedback(NEW.reviewer_id, NEW.reviewee_id, 'session_rated', NEW.session_id, NEW.score);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS session_ratings_match_feedback ON session_ratings;
+CREATE TRIGGER session_ratings_match_feedback
+    AFTER INSERT ON session_ratings
+    FOR EACH ROW EXECUTE FUNCTION record_match_feedback_from_rating();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS session_ratings_match_feedback ON session_ratings;
+DROP FUNCTION IF EXISTS record_match_feedback_from_rating();
+DROP TABLE IF EXISTS session_ratings;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- The structured explanation of each match, as JSON. GetMatchExplanations
+-- returns those of recorded matches; cached partner recommendations keep
+-- theirs so reasons are rendered in each reader's language.
+ALTER TABLE match_history ADD COLUMN IF NOT EXISTS explanation JSONB;
+ALTER TABLE recommendation_cache ADD COLUMN IF NOT EXISTS explanation JSONB;
+
+-- GetMatchExplanations looks up a requester's latest match with each candidate.
+CREATE INDEX IF NOT EXISTS idx_match_history_pair_created_at ON match_history (user_id_a, user_id_b, created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_match_history_pair_created_at;
+ALTER TABLE recommendation_cache DROP COLUMN IF EXISTS explanation;
+ALTER TABLE match_history DROP COLUMN IF EXISTS explanation;
@@ -6,22 +6,39 @@ import (
 	"embed"
 	"fmt"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/observability"
 )
 
 //go:embed migrations/*.sql
 var migrationFS embed.FS
 
-const defaultRetries = 5
+const (
+	defaultRetries = 5
+
+	primaryPoolName = "primary"
+)
 
-// DB wraps the pgxpool connection pool
+// DB wraps the primary pgxpool connection pool and any read replicas
 type DB struct {
 	Pool   *pgxpool.Pool
 	logger *slog.Logger
+	sql    *SQL
+
+	replicas        []*replica
+	nextReplica     atomic.Uint64
+	maxReplicaLag   time.Duration
+	stickyWindow    time.Duration
+	recentWrites    sync.Map // principal -> unix nanos of last write
+	stopMonitor     context.CancelFunc
+	monitorFinished chan struct{}
 }
 
 // Config holds database configuration
@@ -31,16 +48,74 @@ type Config struct {
 	MinConns        int32
 	MaxConnLifetime time.Duration
 	MaxConnIdleTime time.Duration
+
+	// SlowQueryThreshold is the latency above which queries are logged; zero uses the default.
+	SlowQueryThreshold time.Duration
+
+	// ReplicaDSNs lists read replicas; reads fall back to the primary when empty.
+	ReplicaDSNs []string
+	// MaxReplicaLag excludes replicas that are further behind the primary.
+	MaxReplicaLag time.Duration
+	// ReadYourWritesWindow pins a principal's reads to the primary after it writes.
+	ReadYourWritesWindow time.Duration
+	// ReplicaCheckInterval controls how often replica health and lag are sampled.
+	ReplicaCheckInterval time.Duration
 }
 
 // New creates a new database connection pool using pgxpool
 func New(cfg Config, logger *slog.Logger) (*DB, error) {
 	logger.Info("initializing database connection pool", "dsn_host", maskDSN(cfg.DSN))
 
+	pool, err := newPool(primaryPoolName, cfg.DSN, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &DB{
+		Pool:          pool,
+		logger:        logger,
+		maxReplicaLag: cfg.MaxReplicaLag,
+		stickyWindow:  cfg.ReadYourWritesWindow,
+	}
+	if db.maxReplicaLag <= 0 {
+		db.maxReplicaLag = defaultMaxReplicaLag
+	}
+	if db.stickyWindow <= 0 {
+		db.stickyWindow = defaultReadYourWritesWindow
+	}
+
+	// Wait for database to be ready
+	if !db.WaitForDB(context.Background()) {
+		pool.Close()
+		return nil, fmt.Errorf("database connection failed after retries")
+	}
+
+	for i, dsn := range cfg.ReplicaDSNs {
+		if dsn == "" {
+			continue
+		}
+		name := fmt.Sprintf("replica-%d", i)
+		replicaPool, err := newPool(name, dsn, cfg, logger)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		db.replicas = append(db.replicas, &replica{name: name, pool: replicaPool, sql: stdlib.OpenDBFromPool(replicaPool)})
+		logger.Info("read replica configured", "pool", name, "dsn_host", maskDSN(dsn))
+	}
+	db.sql = &SQL{db: db, primary: stdlib.OpenDBFromPool(pool)}
+	db.startReplicaMonitor(cfg.ReplicaCheckInterval)
+
+	logger.Info("database connection pool initialized successfully", "replicas", len(db.replicas))
+	return db, nil
+}
+
+// newPool builds a traced pool and registers its statistics with Prometheus.
+func newPool(name, dsn string, cfg Config, logger *slog.Logger) (*pgxpool.Pool, error) {
 	// Parse pool config
-	poolConfig, err := pgxpool.ParseConfig(cfg.DSN)
+	poolConfig, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse database config: %w", err)
+		return nil, fmt.Errorf("failed to parse database config for %s: %w", name, err)
 	}
 
 	// Set connection pool settings
@@ -48,26 +123,18 @@ func New(cfg Config, logger *slog.Logger) (*DB, error) {
 	poolConfig.MinConns = cfg.MinConns
 	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
 	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	poolConfig.ConnConfig.Tracer = NewQueryTracer(name, logger, cfg.SlowQueryThreshold)
 
 	// Create pool
 	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create database pool: %w", err)
-	}
-
-	db := &DB{
-		Pool:   pool,
-		logger: logger,
+		return nil, fmt.Errorf("failed to create database pool %s: %w", name, err)
 	}
 
-	// Wait for database to be ready
-	if !db.WaitForDB(context.Background()) {
-		pool.Close()
-		return nil, fmt.Errorf("database connection failed after retries")
+	if err := observability.RegisterPoolStats(name, pool.Stat); err != nil {
+		logger.Warn("failed to register pool metrics", "pool", name, "error", err)
 	}
-
-	logger.Info("database connection pool initialized successfully")
-	return db, nil
+	return pool, nil
 }
 
 // WaitForDB waits for the database connection pool to be available
@@ -144,8 +211,19 @@ func (d *DB) RunMigrations() error {
 	return nil
 }
 
-// Close closes the database connection pool
+// Close stops replica monitoring and closes every connection pool
 func (d *DB) Close() {
+	if d.stopMonitor != nil {
+		d.stopMonitor()
+		<-d.monitorFinished
+	}
+	if d.sql != nil {
+		d.sql.primary.Close()
+	}
+	for _, r := range d.replicas {
+		r.sql.Close()
+		r.pool.Close()
+	}
 	if d.Pool != nil {
 		d.Pool.Close()
 		d.logger.Info("database connection pool closed")
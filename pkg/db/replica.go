@@ -0,0 +1,280 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/interceptors"
+	"github.com/FACorreiaa/skillsphere-api/pkg/observability"
+)
+
+const (
+	defaultMaxReplicaLag        = 5 * time.Second
+	defaultReadYourWritesWindow = 5 * time.Second
+	defaultReplicaCheckInterval = 5 * time.Second
+	replicaCheckTimeout         = 2 * time.Second
+
+	routeReplica    = "replica"
+	routeNoReplicas = "no_replicas"
+	routeForced     = "forced_primary"
+	routeSticky     = "read_your_writes"
+	routeUnhealthy  = "replicas_unavailable"
+)
+
+// replicaLagQuery reports zero lag on a primary (so a second database can stand in
+// for a replica locally) and when the replica has replayed everything it received.
+const replicaLagQuery = `
+	-- name: ReplicaLag
+	SELECT CASE
+		WHEN NOT pg_is_in_recovery() THEN 0
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM NOW() - pg_last_xact_replay_timestamp()), 0)
+	END::float8
+`
+
+// Querier is the query surface shared by pgx pools, connections and transactions.
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+var _ Querier = (*pgxpool.Pool)(nil)
+
+type replica struct {
+	name    string
+	pool    *pgxpool.Pool
+	sql     *sql.DB
+	healthy atomic.Bool
+	checked atomic.Bool
+	lag     atomic.Int64 // nanoseconds
+}
+
+// ReplicaStatus describes the last observed state of a read replica.
+type ReplicaStatus struct {
+	Name    string
+	Healthy bool
+	Lag     time.Duration
+}
+
+type forcePrimaryKey struct{}
+
+// WithPrimary forces every read issued with the returned context to the primary pool.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forcePrimaryKey{}, true)
+}
+
+// Read returns a querier for read-only statements. It prefers a healthy replica within
+// the lag budget and falls back to the primary when none is available, when the
+// context forces the primary, or while the calling principal is inside its
+// read-your-writes window.
+func (d *DB) Read(ctx context.Context) Querier {
+	if r := d.route(ctx); r != nil {
+		return r.pool
+	}
+	return d.Pool
+}
+
+// Write returns a querier bound to the primary. Successful statements start the
+// read-your-writes window for the principal found in ctx.
+func (d *DB) Write(ctx context.Context) Querier {
+	return &primaryQuerier{db: d, principal: principalFromContext(ctx)}
+}
+
+// MarkWrite starts the read-your-writes window for the principal in ctx. Use it after
+// writes that bypass Write, such as explicit transactions on Pool.
+func (d *DB) MarkWrite(ctx context.Context) {
+	d.markWrite(principalFromContext(ctx))
+}
+
+// Replicas reports the last observed health and lag of each replica.
+func (d *DB) Replicas() []ReplicaStatus {
+	statuses := make([]ReplicaStatus, 0, len(d.replicas))
+	for _, r := range d.replicas {
+		statuses = append(statuses, ReplicaStatus{
+			Name:    r.name,
+			Healthy: r.healthy.Load(),
+			Lag:     time.Duration(r.lag.Load()),
+		})
+	}
+	return statuses
+}
+
+// route picks the replica for a read, or nil for the primary, and counts the
+// decision.
+func (d *DB) route(ctx context.Context) *replica {
+	r, reason := d.pickReplica(ctx)
+	if r == nil {
+		observability.DBReadRoutesTotal.WithLabelValues(primaryPoolName, reason).Inc()
+		return nil
+	}
+	observability.DBReadRoutesTotal.WithLabelValues(r.name, reason).Inc()
+	return r
+}
+
+func (d *DB) pickReplica(ctx context.Context) (*replica, string) {
+	if len(d.replicas) == 0 {
+		return nil, routeNoReplicas
+	}
+	if forced, _ := ctx.Value(forcePrimaryKey{}).(bool); forced {
+		return nil, routeForced
+	}
+	if d.isSticky(principalFromContext(ctx)) {
+		return nil, routeSticky
+	}
+
+	offset := int(d.nextReplica.Add(1) % uint64(len(d.replicas)))
+	for i := range d.replicas {
+		r := d.replicas[(offset+i)%len(d.replicas)]
+		if r.healthy.Load() && time.Duration(r.lag.Load()) <= d.maxReplicaLag {
+			return r, routeReplica
+		}
+	}
+	return nil, routeUnhealthy
+}
+
+func (d *DB) markWrite(principal string) {
+	if principal == "" || len(d.replicas) == 0 {
+		return
+	}
+	d.recentWrites.Store(principal, time.Now().UnixNano())
+}
+
+func (d *DB) isSticky(principal string) bool {
+	if principal == "" {
+		return false
+	}
+	value, ok := d.recentWrites.Load(principal)
+	if !ok {
+		return false
+	}
+	if time.Since(time.Unix(0, value.(int64))) < d.stickyWindow {
+		return true
+	}
+	d.recentWrites.CompareAndDelete(principal, value)
+	return false
+}
+
+// pruneWrites drops expired read-your-writes entries so the map stays bounded.
+func (d *DB) pruneWrites() {
+	cutoff := time.Now().Add(-d.stickyWindow).UnixNano()
+	d.recentWrites.Range(func(key, value any) bool {
+		if value.(int64) < cutoff {
+			d.recentWrites.CompareAndDelete(key, value)
+		}
+		return true
+	})
+}
+
+func (d *DB) startReplicaMonitor(interval time.Duration) {
+	if len(d.replicas) == 0 {
+		return
+	}
+	if interval <= 0 {
+		interval = defaultReplicaCheckInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.stopMonitor = cancel
+	d.monitorFinished = make(chan struct{})
+
+	// Check once synchronously so the first reads already see replica state.
+	d.checkReplicas(ctx)
+
+	go func() {
+		defer close(d.monitorFinished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.checkReplicas(ctx)
+				d.pruneWrites()
+			}
+		}
+	}()
+}
+
+func (d *DB) checkReplicas(ctx context.Context) {
+	for _, r := range d.replicas {
+		checkCtx, cancel := context.WithTimeout(ctx, replicaCheckTimeout)
+		var lagSeconds float64
+		err := r.pool.QueryRow(checkCtx, replicaLagQuery).Scan(&lagSeconds)
+		cancel()
+
+		wasHealthy := r.healthy.Load()
+		firstCheck := !r.checked.Swap(true)
+		if err != nil {
+			r.healthy.Store(false)
+			observability.DBReplicaHealthy.WithLabelValues(r.name).Set(0)
+			if wasHealthy || firstCheck {
+				d.logger.Warn("read replica health check failed", "pool", r.name, "error", err)
+			}
+			continue
+		}
+
+		lag := time.Duration(lagSeconds * float64(time.Second))
+		r.lag.Store(int64(lag))
+		r.healthy.Store(true)
+		observability.DBReplicaHealthy.WithLabelValues(r.name).Set(1)
+		observability.DBReplicaLagSeconds.WithLabelValues(r.name).Set(lagSeconds)
+		if !wasHealthy {
+			d.logger.Info("read replica healthy", "pool", r.name, "lag", lag.String())
+		}
+	}
+}
+
+func principalFromContext(ctx context.Context) string {
+	userID, _ := interceptors.GetUserIDFromContext(ctx)
+	return userID
+}
+
+// primaryQuerier runs statements on the primary and records the principal's write.
+type primaryQuerier struct {
+	db        *DB
+	principal string
+}
+
+func (q *primaryQuerier) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	tag, err := q.db.Pool.Exec(ctx, sql, arguments...)
+	if err == nil {
+		q.db.markWrite(q.principal)
+	}
+	return tag, err
+}
+
+func (q *primaryQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := q.db.Pool.Query(ctx, sql, args...)
+	if err == nil {
+		q.db.markWrite(q.principal)
+	}
+	return rows, err
+}
+
+func (q *primaryQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return primaryRow{Row: q.db.Pool.QueryRow(ctx, sql, args...), q: q}
+}
+
+// primaryRow records the write once Scan shows the statement succeeded, as
+// pgx reports a row's errors only from Scan.
+type primaryRow struct {
+	pgx.Row
+	q *primaryQuerier
+}
+
+func (r primaryRow) Scan(dest ...any) error {
+	err := r.Row.Scan(dest...)
+	if err == nil || errors.Is(err, pgx.ErrNoRows) {
+		r.q.db.markWrite(r.q.principal)
+	}
+	return err
+}
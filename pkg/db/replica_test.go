@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/interceptors"
+)
+
+func newRoutingTestDB(replicas ...*replica) *DB {
+	return &DB{
+		replicas:      replicas,
+		maxReplicaLag: time.Second,
+		stickyWindow:  time.Minute,
+	}
+}
+
+func healthyReplica(name string, lag time.Duration) *replica {
+	r := &replica{name: name}
+	r.healthy.Store(true)
+	r.lag.Store(int64(lag))
+	return r
+}
+
+func TestPickReplica_Routing(t *testing.T) {
+	userCtx := context.WithValue(context.Background(), interceptors.UserIDKey, "user-1")
+
+	t.Run("no replicas", func(t *testing.T) {
+		if r, reason := newRoutingTestDB().pickReplica(context.Background()); r != nil || reason != routeNoReplicas {
+			t.Fatalf("expected primary (%s), got %v (%s)", routeNoReplicas, r, reason)
+		}
+	})
+
+	t.Run("healthy replica", func(t *testing.T) {
+		d := newRoutingTestDB(healthyReplica("replica-0", 0))
+		if r, reason := d.pickReplica(userCtx); r == nil || reason != routeReplica {
+			t.Fatalf("expected replica, got %v (%s)", r, reason)
+		}
+	})
+
+	t.Run("forced primary", func(t *testing.T) {
+		d := newRoutingTestDB(healthyReplica("replica-0", 0))
+		if r, reason := d.pickReplica(WithPrimary(userCtx)); r != nil || reason != routeForced {
+			t.Fatalf("expected forced primary, got %v (%s)", r, reason)
+		}
+	})
+
+	t.Run("lagging and unhealthy replicas are skipped", func(t *testing.T) {
+		lagging := healthyReplica("replica-0", 10*time.Second)
+		down := &replica{name: "replica-1"}
+		ok := healthyReplica("replica-2", 100*time.Millisecond)
+		d := newRoutingTestDB(lagging, down, ok)
+		for i := 0; i < 6; i++ {
+			if r, _ := d.pickReplica(userCtx); r != ok {
+				t.Fatalf("expected replica-2, got %v", r)
+			}
+		}
+
+		d = newRoutingTestDB(lagging, down)
+		if r, reason := d.pickReplica(userCtx); r != nil || reason != routeUnhealthy {
+			t.Fatalf("expected primary fallback, got %v (%s)", r, reason)
+		}
+	})
+
+	t.Run("read your writes", func(t *testing.T) {
+		d := newRoutingTestDB(healthyReplica("replica-0", 0))
+		d.MarkWrite(userCtx)
+
+		if r, reason := d.pickReplica(userCtx); r != nil || reason != routeSticky {
+			t.Fatalf("expected sticky primary, got %v (%s)", r, reason)
+		}
+		otherCtx := context.WithValue(context.Background(), interceptors.UserIDKey, "user-2")
+		if r, _ := d.pickReplica(otherCtx); r == nil {
+			t.Fatalf("other principals should still use replicas")
+		}
+
+		d.stickyWindow = time.Nanosecond
+		time.Sleep(time.Millisecond)
+		if r, _ := d.pickReplica(userCtx); r == nil {
+			t.Fatalf("expected replica once the window expired")
+		}
+	})
+}
+
+// TestDB_ReplicaRouting runs against two real databases, one standing in for the replica:
+//
+//	SKILLSPHERE_TEST_PRIMARY_DSN=postgres://.../skillsphere_primary \
+//	SKILLSPHERE_TEST_REPLICA_DSN=postgres://.../skillsphere_replica go test ./pkg/db
+func TestDB_ReplicaRouting(t *testing.T) {
+	primaryDSN := os.Getenv("SKILLSPHERE_TEST_PRIMARY_DSN")
+	replicaDSN := os.Getenv("SKILLSPHERE_TEST_REPLICA_DSN")
+	if primaryDSN == "" || replicaDSN == "" {
+		t.Skip("SKILLSPHERE_TEST_PRIMARY_DSN and SKILLSPHERE_TEST_REPLICA_DSN not set")
+	}
+
+	d, err := New(Config{
+		DSN:                  primaryDSN,
+		MaxConns:             4,
+		ReplicaDSNs:          []string{replicaDSN},
+		ReadYourWritesWindow: time.Minute,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer d.Close()
+
+	ctx := context.WithValue(context.Background(), interceptors.UserIDKey, "user-1")
+	currentDB := func(q Querier) string {
+		var name string
+		if err := q.QueryRow(ctx, "SELECT current_database()").Scan(&name); err != nil {
+			t.Fatalf("current_database: %v", err)
+		}
+		return name
+	}
+
+	primaryName := currentDB(d.Pool)
+	if got := currentDB(d.Read(ctx)); got == primaryName {
+		t.Fatalf("expected read to hit the replica database, got %q", got)
+	}
+
+	if _, err := d.Write(ctx).Exec(ctx, "SELECT 1"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got := currentDB(d.Read(ctx)); got != primaryName {
+		t.Fatalf("expected read-your-writes to pin %q, got %q", primaryName, got)
+	}
+}
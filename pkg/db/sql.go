@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SQLQuerier is the query surface shared by *sql.DB, *sql.Conn and *sql.Tx.
+type SQLQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+var _ SQLQuerier = (*sql.DB)(nil)
+
+// SQL routes database/sql statements the way Read and Write route pgx ones,
+// for repositories built on database/sql.
+type SQL struct {
+	db      *DB
+	primary *sql.DB
+}
+
+// NewSQL wraps a database without replicas, so every statement runs on it.
+// Tools and tests that open their own *sql.DB use it.
+func NewSQL(primary *sql.DB) *SQL {
+	return &SQL{primary: primary}
+}
+
+// SQL returns the database/sql handles on the primary and replica pools.
+// They share the pools' connections and are closed with the DB.
+func (d *DB) SQL() *SQL {
+	return d.sql
+}
+
+// Primary returns the primary handle, for transactions and statements that
+// must not read stale data. Call MarkWrite after committing writes on it.
+func (s *SQL) Primary() *sql.DB {
+	return s.primary
+}
+
+// Read returns a handle for read-only statements, chosen as DB.Read chooses
+// a pool.
+func (s *SQL) Read(ctx context.Context) *sql.DB {
+	if s.db != nil {
+		if r := s.db.route(ctx); r != nil {
+			return r.sql
+		}
+	}
+	return s.primary
+}
+
+// Write returns a querier bound to the primary. Successful statements start
+// the read-your-writes window for the principal found in ctx.
+func (s *SQL) Write(ctx context.Context) SQLQuerier {
+	return &sqlPrimary{s: s, principal: principalFromContext(ctx)}
+}
+
+// MarkWrite starts the read-your-writes window for the principal in ctx, as
+// DB.MarkWrite does.
+func (s *SQL) MarkWrite(ctx context.Context) {
+	s.markWrite(principalFromContext(ctx))
+}
+
+func (s *SQL) markWrite(principal string) {
+	if s.db != nil {
+		s.db.markWrite(principal)
+	}
+}
+
+// sqlPrimary runs statements on the primary and records the principal's write.
+type sqlPrimary struct {
+	s         *SQL
+	principal string
+}
+
+func (q *sqlPrimary) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := q.s.primary.ExecContext(ctx, query, args...)
+	if err == nil {
+		q.s.markWrite(q.principal)
+	}
+	return result, err
+}
+
+func (q *sqlPrimary) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := q.s.primary.QueryContext(ctx, query, args...)
+	if err == nil {
+		q.s.markWrite(q.principal)
+	}
+	return rows, err
+}
+
+func (q *sqlPrimary) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	row := q.s.primary.QueryRowContext(ctx, query, args...)
+	if row.Err() == nil {
+		q.s.markWrite(q.principal)
+	}
+	return row
+}
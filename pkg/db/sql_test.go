@@ -0,0 +1,124 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/FACorreiaa/skillsphere-api/internal/domain/matching/repository"
+	"github.com/FACorreiaa/skillsphere-api/pkg/db"
+	"github.com/FACorreiaa/skillsphere-api/pkg/interceptors"
+)
+
+// statementLog records which database ran each statement, by the name the
+// sqlstub driver was opened with.
+type statementLog struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *statementLog) add(database, query string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	name := query
+	if i := strings.Index(query, "-- name: "); i >= 0 {
+		name = strings.Fields(query[i+len("-- name: "):])[0]
+	}
+	l.entries = append(l.entries, database+" "+name)
+}
+
+func (l *statementLog) take() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := l.entries
+	l.entries = nil
+	return out
+}
+
+var stubLog = &statementLog{}
+
+func init() {
+	sql.Register("sqlstub", stubDriver{})
+}
+
+// stubDriver answers every query with no rows and every exec with one
+// affected row.
+type stubDriver struct{}
+
+func (stubDriver) Open(name string) (driver.Conn, error) { return &stubConn{database: name}, nil }
+
+type stubConn struct{ database string }
+
+func (c *stubConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *stubConn) Close() error                        { return nil }
+func (c *stubConn) Begin() (driver.Tx, error)           { return c, nil }
+func (c *stubConn) Commit() error                       { return nil }
+func (c *stubConn) Rollback() error                     { return nil }
+
+func (c *stubConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c *stubConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	stubLog.add(c.database, query)
+	return stubRows{}, nil
+}
+
+func (c *stubConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	stubLog.add(c.database, query)
+	return driver.RowsAffected(1), nil
+}
+
+type stubRows struct{}
+
+func (stubRows) Columns() []string         { return nil }
+func (stubRows) Close() error              { return nil }
+func (stubRows) Next([]driver.Value) error { return io.EOF }
+
+func TestSQL_RepositoryReadYourWrites(t *testing.T) {
+	primary, err := sql.Open("sqlstub", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	replica, err := sql.Open("sqlstub", "replica")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		primary.Close()
+		replica.Close()
+	})
+
+	routed := db.NewRoutedSQL(primary, replica)
+	repo := repository.NewPostgresRecommendationRepository(routed)
+	cache := repository.NewPostgresRecommendationCache(routed)
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), interceptors.UserIDKey, userID.String())
+	other := context.WithValue(context.Background(), interceptors.UserIDKey, uuid.NewString())
+	ids := []uuid.UUID{uuid.New()}
+
+	if _, err := repo.GetUsers(ctx, ids); err != nil {
+		t.Fatal(err)
+	}
+	if got := stubLog.take(); len(got) != 1 || got[0] != "replica GetMatchUsers" {
+		t.Fatalf("read before a write ran as %v, want on the replica", got)
+	}
+
+	if err := cache.SkipPartner(ctx, userID, ids[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetUsers(ctx, ids); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetUsers(other, ids); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"primary SkipRecommendedPartner", "primary GetMatchUsers", "replica GetMatchUsers"}
+	if got := stubLog.take(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("statements = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/interceptors"
+	"github.com/FACorreiaa/skillsphere-api/pkg/observability"
+)
+
+const (
+	defaultSlowQueryThreshold = 200 * time.Millisecond
+	unnamedQuery              = "unnamed"
+	maxLoggedSQLLength        = 512
+)
+
+type queryNameKey struct{}
+
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	name  string
+	sql   string
+	start time.Time
+}
+
+// WithQueryName tags the context so metrics and slow-query logs report the statement under name.
+func WithQueryName(ctx context.Context, name string) context.Context {
+	if name == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, queryNameKey{}, name)
+}
+
+// QueryTracer records per-query latency histograms and logs statements slower than a threshold.
+type QueryTracer struct {
+	pool          string
+	logger        *slog.Logger
+	slowThreshold time.Duration
+}
+
+var _ pgx.QueryTracer = (*QueryTracer)(nil)
+
+// NewQueryTracer creates a tracer for the named pool.
+func NewQueryTracer(pool string, logger *slog.Logger, slowThreshold time.Duration) *QueryTracer {
+	if slowThreshold <= 0 {
+		slowThreshold = defaultSlowQueryThreshold
+	}
+	return &QueryTracer{
+		pool:          pool,
+		logger:        logger,
+		slowThreshold: slowThreshold,
+	}
+}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, &queryTrace{
+		name:  queryName(ctx, data.SQL),
+		sql:   data.SQL,
+		start: time.Now(),
+	})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	trace, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if !ok {
+		return
+	}
+
+	duration := time.Since(trace.start)
+	observability.DBQueryDuration.
+		WithLabelValues(t.pool, trace.name, queryStatus(data.Err)).
+		Observe(duration.Seconds())
+
+	if duration < t.slowThreshold {
+		return
+	}
+
+	observability.DBSlowQueriesTotal.WithLabelValues(t.pool, trace.name).Inc()
+	if t.logger == nil {
+		return
+	}
+
+	fields := []any{
+		"pool", t.pool,
+		"query", trace.name,
+		"duration", duration.String(),
+		"threshold", t.slowThreshold.String(),
+		"rows_affected", data.CommandTag.RowsAffected(),
+		"sql", truncateSQL(trace.sql),
+	}
+	if requestID, ok := interceptors.RequestIDFromContext(ctx); ok && requestID != "" {
+		fields = append(fields, "request_id", requestID)
+	}
+	if data.Err != nil {
+		fields = append(fields, "error", data.Err)
+	}
+	t.logger.WarnContext(ctx, "slow query", fields...)
+}
+
+// queryName resolves the metric label for a statement. An explicit name from the
+// context wins, then a sqlc-style "-- name: Foo" leading comment.
+func queryName(ctx context.Context, sql string) string {
+	if name, ok := ctx.Value(queryNameKey{}).(string); ok && name != "" {
+		return name
+	}
+
+	trimmed := strings.TrimSpace(sql)
+	if rest, ok := strings.CutPrefix(trimmed, "--"); ok {
+		line, _, _ := strings.Cut(rest, "\n")
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), "name:"); ok {
+			if fields := strings.Fields(name); len(fields) > 0 {
+				return fields[0]
+			}
+		}
+	}
+	return unnamedQuery
+}
+
+func queryStatus(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	default:
+		return "error"
+	}
+}
+
+func truncateSQL(sql string) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+	if len(sql) > maxLoggedSQLLength {
+		return sql[:maxLoggedSQLLength] + "..."
+	}
+	return sql
+}
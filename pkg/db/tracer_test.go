@@ -0,0 +1,55 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/FACorreiaa/skillsphere-api/pkg/interceptors"
+	"github.com/FACorreiaa/skillsphere-api/pkg/observability"
+)
+
+func TestQueryName(t *testing.T) {
+	cases := []struct {
+		name string
+		ctx  context.Context
+		sql  string
+		want string
+	}{
+		{"comment", context.Background(), "\n\t-- name: GetUserByID\n\tSELECT 1", "GetUserByID"},
+		{"context wins", WithQueryName(context.Background(), "Explicit"), "-- name: FromComment\nSELECT 1", "Explicit"},
+		{"plain comment", context.Background(), "-- fetch users\nSELECT 1", unnamedQuery},
+		{"no comment", context.Background(), "SELECT 1", unnamedQuery},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := queryName(tc.ctx, tc.sql); got != tc.want {
+				t.Fatalf("queryName() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQueryTracer_LogsSlowQueryWithRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	tracer := NewQueryTracer("test", logger, 1)
+
+	ctx := interceptors.ContextWithRequestID(context.Background(), "req-123")
+	ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "-- name: SlowOne\nSELECT pg_sleep(1)"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+
+	out := buf.String()
+	if !strings.Contains(out, `"msg":"slow query"`) || !strings.Contains(out, `"request_id":"req-123"`) {
+		t.Fatalf("expected slow query log with request id, got %s", out)
+	}
+	if got := testutil.ToFloat64(observability.DBSlowQueriesTotal.WithLabelValues("test", "SlowOne")); got != 1 {
+		t.Fatalf("expected slow query counter 1, got %v", got)
+	}
+}
@@ -172,6 +172,12 @@ func GetClaimsFromContext(ctx context.Context) (*Claims, error) {
 	return claims, nil
 }
 
+// ContextWithClaims returns a copy of ctx carrying claims, for callers outside an RPC.
+func ContextWithClaims(ctx context.Context, claims *Claims) context.Context {
+	ctx = context.WithValue(ctx, claimsKey, claims)
+	return context.WithValue(ctx, UserIDKey, claims.UserID)
+}
+
 // MustGetClaimsFromContext retrieves claims or panics (use in service with service interceptor)
 func MustGetClaimsFromContext(ctx context.Context) *Claims {
 	claims, err := GetClaimsFromContext(ctx)
@@ -2,6 +2,9 @@ package interceptors
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"connectrpc.com/connect"
@@ -37,3 +40,45 @@ func TestRateLimitInterceptor_ExceedsLimit(t *testing.T) {
 		t.Fatalf("expected resource exhausted, got %v", err)
 	}
 }
+
+func TestMaintenanceInterceptor_AllowsOnlyListedProcedures(t *testing.T) {
+	enabled := true
+	interceptor := NewMaintenanceInterceptor(func() (bool, string) {
+		return enabled, "back soon"
+	}, "/test.v1.AdminService/")
+
+	mux := http.NewServeMux()
+	for _, procedure := range []string{"/test.v1.AdminService/Get", "/test.v1.UserService/Get"} {
+		mux.Handle(procedure, connect.NewUnaryHandler(procedure,
+			func(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+				return connect.NewResponse(&emptypb.Empty{}), nil
+			},
+			connect.WithInterceptors(interceptor),
+		))
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	call := func(procedure string) error {
+		client := connect.NewClient[emptypb.Empty, emptypb.Empty](server.Client(), server.URL+procedure)
+		_, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+		return err
+	}
+
+	err := call("/test.v1.UserService/Get")
+	if connect.CodeOf(err) != connect.CodeUnavailable {
+		t.Fatalf("expected unavailable, got %v", err)
+	}
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || connectErr.Message() != "back soon" {
+		t.Fatalf("expected maintenance message, got %v", err)
+	}
+	if err := call("/test.v1.AdminService/Get"); err != nil {
+		t.Fatalf("admin procedure should stay reachable: %v", err)
+	}
+
+	enabled = false
+	if err := call("/test.v1.UserService/Get"); err != nil {
+		t.Fatalf("expected success outside maintenance, got %v", err)
+	}
+}
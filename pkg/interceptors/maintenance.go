@@ -0,0 +1,75 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// MaintenanceInterceptor rejects RPCs with CodeUnavailable while maintenance mode is on.
+type MaintenanceInterceptor struct {
+	state   func() (enabled bool, message string)
+	allowed []string
+}
+
+// NewMaintenanceInterceptor creates a maintenance interceptor. state is consulted on
+// every request. Allowed entries are full procedure names, or service prefixes ending
+// in "/" (e.g. "/skillsphere.admin.v1.AdminService/"), that stay reachable.
+func NewMaintenanceInterceptor(state func() (bool, string), allowed ...string) *MaintenanceInterceptor {
+	return &MaintenanceInterceptor{state: state, allowed: allowed}
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *MaintenanceInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		if err := i.check(req.Spec().Procedure); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+func (i *MaintenanceInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		return next(ctx, spec)
+	}
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *MaintenanceInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.check(conn.Spec().Procedure); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+func (i *MaintenanceInterceptor) check(procedure string) error {
+	if i == nil || i.state == nil {
+		return nil
+	}
+	enabled, message := i.state()
+	if !enabled || i.isAllowed(procedure) {
+		return nil
+	}
+	if message == "" {
+		message = "service is under maintenance"
+	}
+	return connect.NewError(connect.CodeUnavailable, errors.New(message))
+}
+
+func (i *MaintenanceInterceptor) isAllowed(procedure string) bool {
+	for _, allowed := range i.allowed {
+		if procedure == allowed || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(procedure, allowed)) {
+			return true
+		}
+	}
+	return false
+}
@@ -20,6 +20,11 @@ func RequestIDFromContext(ctx context.Context) (string, bool) {
 	return value, ok
 }
 
+// ContextWithRequestID returns a copy of ctx carrying the request ID, for callers outside an RPC.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
 // RequestIDInterceptor injects/propagates a request identifier for each RPC.
 type RequestIDInterceptor struct {
 	headerName string
@@ -73,5 +78,5 @@ func (i *RequestIDInterceptor) ensureRequestID(ctx context.Context, header http.
 	if requestID == "" {
 		requestID = uuid.New().String()
 	}
-	return requestID, context.WithValue(ctx, requestIDKey{}, requestID)
+	return requestID, ContextWithRequestID(ctx, requestID)
 }
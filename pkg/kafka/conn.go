@@ -0,0 +1,100 @@
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// maxResponseSize guards against reading garbage as a huge length prefix.
+const maxResponseSize = 100 << 20
+
+// brokerConn is a lazily dialled connection to one broker. Requests are
+// serialised, so at most one is in flight and responses arrive in order.
+type brokerConn struct {
+	addr    string
+	dialer  *net.Dialer
+	timeout time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func newBrokerConn(addr string, dialer *net.Dialer, timeout time.Duration) *brokerConn {
+	return &brokerConn{addr: addr, dialer: dialer, timeout: timeout}
+}
+
+// roundTrip writes a framed request and, when expectResponse is set, returns the
+// response body following the correlation ID. Any I/O failure drops the
+// connection so the next request redials.
+func (c *brokerConn) roundTrip(ctx context.Context, correlationID int32, request []byte, expectResponse bool) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		conn, err := c.dialer.DialContext(ctx, "tcp", c.addr)
+		if err != nil {
+			return nil, fmt.Errorf("kafka: dial %s: %w", c.addr, err)
+		}
+		c.conn = conn
+	}
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = c.conn.SetDeadline(deadline)
+	// Unblock reads and writes as soon as the caller gives up.
+	stop := context.AfterFunc(ctx, func() { _ = c.conn.SetDeadline(time.Unix(1, 0)) })
+	defer stop()
+
+	body, err := c.exchange(correlationID, request, expectResponse)
+	if err != nil {
+		c.conn.Close()
+		c.conn = nil
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("kafka: %s: %w", c.addr, err)
+	}
+	return body, nil
+}
+
+func (c *brokerConn) exchange(correlationID int32, request []byte, expectResponse bool) ([]byte, error) {
+	if _, err := c.conn.Write(request); err != nil {
+		return nil, err
+	}
+	if !expectResponse {
+		return nil, nil
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(c.conn, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n < 4 || n > maxResponseSize {
+		return nil, fmt.Errorf("invalid response size %d", n)
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(c.conn, body); err != nil {
+		return nil, err
+	}
+	if got := int32(binary.BigEndian.Uint32(body)); got != correlationID {
+		return nil, fmt.Errorf("correlation id mismatch: got %d, want %d", got, correlationID)
+	}
+	return body[4:], nil
+}
+
+func (c *brokerConn) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
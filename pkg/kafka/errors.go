@@ -0,0 +1,85 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrClosed is returned by Publish after Close.
+var ErrClosed = errors.New("kafka: producer closed")
+
+// Error is a broker-reported error code.
+type Error struct {
+	Code  int16
+	Topic string
+	// Partition is -1 for topic-level errors.
+	Partition int32
+}
+
+// Broker error codes the producer reacts to.
+const (
+	errCodeUnknownTopicOrPartition int16 = 3
+	errCodeLeaderNotAvailable      int16 = 5
+	errCodeNotLeaderOrFollower     int16 = 6
+	errCodeRequestTimedOut         int16 = 7
+	errCodeNetworkException        int16 = 13
+	errCodeNotEnoughReplicas       int16 = 19
+	errCodeNotEnoughReplicasAfter  int16 = 20
+	errCodeKafkaStorageError       int16 = 56
+	errCodeFencedLeaderEpoch       int16 = 74
+)
+
+var errorNames = map[int16]string{
+	2:                              "CORRUPT_MESSAGE",
+	errCodeUnknownTopicOrPartition: "UNKNOWN_TOPIC_OR_PARTITION",
+	errCodeLeaderNotAvailable:      "LEADER_NOT_AVAILABLE",
+	errCodeNotLeaderOrFollower:     "NOT_LEADER_OR_FOLLOWER",
+	errCodeRequestTimedOut:         "REQUEST_TIMED_OUT",
+	10:                             "MESSAGE_TOO_LARGE",
+	errCodeNetworkException:        "NETWORK_EXCEPTION",
+	17:                             "INVALID_TOPIC_EXCEPTION",
+	18:                             "RECORD_LIST_TOO_LARGE",
+	errCodeNotEnoughReplicas:       "NOT_ENOUGH_REPLICAS",
+	errCodeNotEnoughReplicasAfter:  "NOT_ENOUGH_REPLICAS_AFTER_APPEND",
+	21:                             "INVALID_REQUIRED_ACKS",
+	29:                             "TOPIC_AUTHORIZATION_FAILED",
+	35:                             "UNSUPPORTED_VERSION",
+	errCodeKafkaStorageError:       "KAFKA_STORAGE_ERROR",
+	errCodeFencedLeaderEpoch:       "FENCED_LEADER_EPOCH",
+	76:                             "UNSUPPORTED_COMPRESSION_TYPE",
+}
+
+func (e *Error) Error() string {
+	name, ok := errorNames[e.Code]
+	if !ok {
+		name = fmt.Sprintf("error code %d", e.Code)
+	}
+	if e.Partition < 0 {
+		return fmt.Sprintf("kafka: %s: %s", e.Topic, name)
+	}
+	return fmt.Sprintf("kafka: %s/%d: %s", e.Topic, e.Partition, name)
+}
+
+// Retriable reports whether the same request may succeed later, typically
+// after partition leadership settles.
+func (e *Error) Retriable() bool {
+	switch e.Code {
+	case errCodeUnknownTopicOrPartition, errCodeLeaderNotAvailable, errCodeNotLeaderOrFollower,
+		errCodeRequestTimedOut, errCodeNetworkException, errCodeNotEnoughReplicas,
+		errCodeNotEnoughReplicasAfter, errCodeKafkaStorageError, errCodeFencedLeaderEpoch:
+		return true
+	default:
+		return false
+	}
+}
+
+// staleMetadata reports whether the error means cached leadership is wrong.
+func (e *Error) staleMetadata() bool {
+	switch e.Code {
+	case errCodeUnknownTopicOrPartition, errCodeLeaderNotAvailable, errCodeNotLeaderOrFollower,
+		errCodeKafkaStorageError, errCodeFencedLeaderEpoch:
+		return true
+	default:
+		return false
+	}
+}
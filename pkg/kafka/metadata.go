@@ -0,0 +1,92 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+)
+
+// topicMetadata maps each partition of a topic to the address of its leader;
+// an empty address means the partition currently has no leader.
+type topicMetadata struct {
+	leaders []string
+}
+
+func encodeMetadataRequest(topic string) []byte {
+	var e encoder
+	e.int32(1)
+	e.string(topic)
+	e.bool(true) // allow_auto_topic_creation
+	return e.b
+}
+
+func decodeMetadataResponse(body []byte, topic string) (topicMetadata, error) {
+	d := decoder{b: body}
+	d.int32() // throttle_time_ms
+
+	brokers := make(map[int32]string)
+	for range d.arrayLen() {
+		nodeID := d.int32()
+		host := d.string()
+		port := d.int32()
+		d.string() // rack
+		brokers[nodeID] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.string() // cluster_id
+	d.int32()  // controller_id
+
+	var (
+		meta     topicMetadata
+		found    bool
+		topicErr int16
+	)
+	for range d.arrayLen() {
+		errCode := d.int16()
+		name := d.string()
+		d.bool() // is_internal
+
+		type partition struct {
+			index  int32
+			leader int32
+		}
+		var partitions []partition
+		for range d.arrayLen() {
+			d.int16() // partition error_code; a missing leader is reported as -1 below
+			p := partition{index: d.int32(), leader: d.int32()}
+			for range d.arrayLen() { // replica_nodes
+				d.int32()
+			}
+			for range d.arrayLen() { // isr_nodes
+				d.int32()
+			}
+			partitions = append(partitions, p)
+		}
+		if name != topic {
+			continue
+		}
+
+		found, topicErr = true, errCode
+		slices.SortFunc(partitions, func(a, b partition) int { return int(a.index - b.index) })
+		meta.leaders = make([]string, len(partitions))
+		for i, p := range partitions {
+			if int(p.index) != i {
+				return topicMetadata{}, fmt.Errorf("kafka: %s: partition %d missing from metadata", topic, i)
+			}
+			meta.leaders[i] = brokers[p.leader]
+		}
+	}
+	if d.err != nil {
+		return topicMetadata{}, fmt.Errorf("kafka: decode metadata: %w", d.err)
+	}
+	if !found {
+		return topicMetadata{}, &Error{Code: errCodeUnknownTopicOrPartition, Topic: topic, Partition: -1}
+	}
+	if topicErr != 0 {
+		return topicMetadata{}, &Error{Code: topicErr, Topic: topic, Partition: -1}
+	}
+	if len(meta.leaders) == 0 {
+		return topicMetadata{}, &Error{Code: errCodeLeaderNotAvailable, Topic: topic, Partition: -1}
+	}
+	return meta, nil
+}
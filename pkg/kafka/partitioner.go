@@ -0,0 +1,49 @@
+package kafka
+
+// partitionFor picks the partition for key the same way the Java client's
+// default partitioner does, so mixed-language producers agree on placement.
+// Keyless messages are spread with the supplied round-robin counter.
+func partitionFor(key []byte, partitions int, roundRobin uint32) int {
+	if key == nil {
+		return int(roundRobin % uint32(partitions))
+	}
+	return int(uint32(murmur2(key))&0x7fffffff) % partitions
+}
+
+// murmur2 is Kafka's variant of MurmurHash2 (seed 0x9747b28c).
+func murmur2(data []byte) int32 {
+	const (
+		seed uint32 = 0x9747b28c
+		m    uint32 = 0x5bd1e995
+		r           = 24
+	)
+
+	length := len(data)
+	h := seed ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := uint32(data[i]) | uint32(data[i+1])<<8 | uint32(data[i+2])<<16 | uint32(data[i+3])<<24
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := data[length&^3:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
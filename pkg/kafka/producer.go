@@ -0,0 +1,467 @@
+// Package kafka is a minimal Kafka producer speaking the wire protocol directly.
+//
+// It batches records per partition, supports gzip and zstd compression and the
+// usual acks levels, and partitions keyed messages like the Java client. It does
+// not retry: failed publishes are reported to the caller, which owns redelivery.
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Acks is the acknowledgement level the leader waits for before replying. The
+// zero value is AcksAll.
+type Acks int8
+
+const (
+	// AcksAll waits for every in-sync replica.
+	AcksAll Acks = iota
+	// AcksLeader waits for the partition leader to write the batch.
+	AcksLeader
+	// AcksNone does not wait for any acknowledgement; the broker sends no response.
+	AcksNone
+)
+
+// wire returns the acks value sent in produce requests.
+func (a Acks) wire() int16 {
+	switch a {
+	case AcksLeader:
+		return 1
+	case AcksNone:
+		return 0
+	default:
+		return -1
+	}
+}
+
+// ParseAcks maps "all"/"-1", "leader"/"1" and "none"/"0" to an Acks value.
+func ParseAcks(s string) (Acks, error) {
+	switch strings.ToLower(s) {
+	case "", "all", "-1":
+		return AcksAll, nil
+	case "leader", "1":
+		return AcksLeader, nil
+	case "none", "0":
+		return AcksNone, nil
+	default:
+		return 0, fmt.Errorf("kafka: invalid acks %q", s)
+	}
+}
+
+const (
+	defaultClientID       = "skillsphere"
+	defaultBatchMaxBytes  = 1 << 20
+	defaultRequestTimeout = 10 * time.Second
+	defaultMetadataMaxAge = 5 * time.Minute
+	partitionQueueSize    = 1024
+)
+
+// Config configures a Producer.
+type Config struct {
+	// Brokers are bootstrap addresses (host:port) used to discover the cluster.
+	Brokers  []string
+	ClientID string
+	Acks     Acks
+	// Compression is applied per record batch.
+	Compression Compression
+	// Linger delays sending a batch to let more messages join it. With zero,
+	// batches still form from messages published while a request is in flight.
+	Linger time.Duration
+	// BatchMaxBytes caps the uncompressed size of a batch.
+	BatchMaxBytes int
+	// RequestTimeout bounds each broker request and the broker-side ack wait.
+	RequestTimeout time.Duration
+	// MetadataMaxAge forces a periodic leadership refresh.
+	MetadataMaxAge time.Duration
+}
+
+// Producer publishes messages to Kafka. It is safe for concurrent use.
+type Producer struct {
+	cfg    Config
+	dialer *net.Dialer
+
+	correlationID atomic.Int32
+	roundRobin    atomic.Uint32
+	bootstrap     atomic.Uint32
+
+	mu       sync.Mutex
+	closed   bool
+	conns    map[string]*brokerConn
+	topics   map[string]cachedTopic
+	batchers map[topicPartition]*batcher
+	quit     chan struct{}
+	wg       sync.WaitGroup
+}
+
+type cachedTopic struct {
+	meta      topicMetadata
+	fetchedAt time.Time
+}
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+// NewProducer validates cfg and returns a Producer. Connections are opened on
+// first use.
+func NewProducer(cfg Config) (*Producer, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("kafka: at least one broker is required")
+	}
+	switch cfg.Acks {
+	case AcksNone, AcksLeader, AcksAll:
+	default:
+		return nil, fmt.Errorf("kafka: invalid acks %d", cfg.Acks)
+	}
+	if _, err := compress(cfg.Compression, nil); err != nil {
+		return nil, err
+	}
+	if cfg.ClientID == "" {
+		cfg.ClientID = defaultClientID
+	}
+	if cfg.BatchMaxBytes <= 0 {
+		cfg.BatchMaxBytes = defaultBatchMaxBytes
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = defaultRequestTimeout
+	}
+	if cfg.MetadataMaxAge <= 0 {
+		cfg.MetadataMaxAge = defaultMetadataMaxAge
+	}
+
+	return &Producer{
+		cfg:      cfg,
+		dialer:   &net.Dialer{Timeout: cfg.RequestTimeout, KeepAlive: 30 * time.Second},
+		conns:    make(map[string]*brokerConn),
+		topics:   make(map[string]cachedTopic),
+		batchers: make(map[topicPartition]*batcher),
+		quit:     make(chan struct{}),
+	}, nil
+}
+
+// Publish sends msg to topic and blocks until the batch containing it has been
+// acknowledged according to the configured Acks. If ctx ends first the message
+// may still be delivered.
+func (p *Producer) Publish(ctx context.Context, topic string, msg Message) error {
+	meta, err := p.metadata(ctx, topic)
+	if err != nil {
+		return err
+	}
+	partition := partitionFor(msg.Key, len(meta.leaders), p.roundRobin.Add(1))
+
+	b, err := p.batcher(topic, int32(partition))
+	if err != nil {
+		return err
+	}
+
+	req := &pendingMessage{msg: msg, done: make(chan error, 1)}
+	select {
+	case b.queue <- req:
+	case <-b.stopped:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-req.done:
+		return err
+	case <-b.stopped:
+		select {
+		case err := <-req.done:
+			return err
+		default:
+			return ErrClosed
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes queued messages and closes broker connections.
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.quit)
+	p.mu.Unlock()
+
+	p.wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, conn := range p.conns {
+		conn.close()
+	}
+	return nil
+}
+
+func (p *Producer) batcher(topic string, partition int32) (*batcher, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, ErrClosed
+	}
+
+	key := topicPartition{topic: topic, partition: partition}
+	if b, ok := p.batchers[key]; ok {
+		return b, nil
+	}
+	b := &batcher{
+		p:         p,
+		topic:     topic,
+		partition: partition,
+		queue:     make(chan *pendingMessage, partitionQueueSize),
+		stopped:   make(chan struct{}),
+	}
+	p.batchers[key] = b
+	p.wg.Add(1)
+	go b.run()
+	return b, nil
+}
+
+func (p *Producer) conn(addr string) *brokerConn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c, ok := p.conns[addr]
+	if !ok {
+		c = newBrokerConn(addr, p.dialer, p.cfg.RequestTimeout)
+		p.conns[addr] = c
+	}
+	return c
+}
+
+// metadata returns cached partition leadership for topic, refreshing it from
+// the bootstrap brokers when missing or older than MetadataMaxAge.
+func (p *Producer) metadata(ctx context.Context, topic string) (topicMetadata, error) {
+	p.mu.Lock()
+	cached, ok := p.topics[topic]
+	p.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < p.cfg.MetadataMaxAge {
+		return cached.meta, nil
+	}
+
+	var errs []error
+	start := p.bootstrap.Add(1)
+	for i := range p.cfg.Brokers {
+		addr := p.cfg.Brokers[(int(start)+i)%len(p.cfg.Brokers)]
+		id := p.correlationID.Add(1)
+		request := encodeRequest(apiKeyMetadata, metadataVersion, id, p.cfg.ClientID, encodeMetadataRequest(topic))
+		body, err := p.conn(addr).roundTrip(ctx, id, request, true)
+		if err != nil {
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		meta, err := decodeMetadataResponse(body, topic)
+		if err != nil {
+			return topicMetadata{}, err
+		}
+		p.mu.Lock()
+		p.topics[topic] = cachedTopic{meta: meta, fetchedAt: time.Now()}
+		p.mu.Unlock()
+		return meta, nil
+	}
+	return topicMetadata{}, fmt.Errorf("kafka: fetch metadata for %s: %w", topic, errors.Join(errs...))
+}
+
+func (p *Producer) invalidate(topic string) {
+	p.mu.Lock()
+	delete(p.topics, topic)
+	p.mu.Unlock()
+}
+
+// produce sends msgs as one record batch to the partition leader.
+func (p *Producer) produce(ctx context.Context, topic string, partition int32, msgs []Message) error {
+	meta, err := p.metadata(ctx, topic)
+	if err != nil {
+		return err
+	}
+	if int(partition) >= len(meta.leaders) {
+		p.invalidate(topic)
+		return &Error{Code: errCodeUnknownTopicOrPartition, Topic: topic, Partition: partition}
+	}
+	leader := meta.leaders[partition]
+	if leader == "" {
+		p.invalidate(topic)
+		return &Error{Code: errCodeLeaderNotAvailable, Topic: topic, Partition: partition}
+	}
+
+	records, err := encodeRecordBatch(msgs, p.cfg.Compression, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var e encoder
+	e.nullableString(nil) // transactional_id
+	e.int16(p.cfg.Acks.wire())
+	e.int32(int32(p.cfg.RequestTimeout.Milliseconds()))
+	e.int32(1)
+	e.string(topic)
+	e.int32(1)
+	e.int32(partition)
+	e.bytes(records)
+
+	id := p.correlationID.Add(1)
+	request := encodeRequest(apiKeyProduce, produceVersion, id, p.cfg.ClientID, e.b)
+	body, err := p.conn(leader).roundTrip(ctx, id, request, p.cfg.Acks != AcksNone)
+	if err != nil {
+		p.invalidate(topic)
+		return err
+	}
+	if p.cfg.Acks == AcksNone {
+		return nil
+	}
+
+	if errCode, err := decodeProduceResponse(body, topic, partition); err != nil {
+		return err
+	} else if errCode != 0 {
+		kerr := &Error{Code: errCode, Topic: topic, Partition: partition}
+		if kerr.staleMetadata() {
+			p.invalidate(topic)
+		}
+		return kerr
+	}
+	return nil
+}
+
+func decodeProduceResponse(body []byte, topic string, partition int32) (int16, error) {
+	d := decoder{b: body}
+	found, errCode := false, int16(0)
+	for range d.arrayLen() {
+		name := d.string()
+		for range d.arrayLen() {
+			index := d.int32()
+			code := d.int16()
+			d.int64() // base_offset
+			d.int64() // log_append_time_ms
+			d.int64() // log_start_offset
+			if name == topic && index == partition {
+				found, errCode = true, code
+			}
+		}
+	}
+	d.int32() // throttle_time_ms
+	if d.err != nil {
+		return 0, fmt.Errorf("kafka: decode produce response: %w", d.err)
+	}
+	if !found {
+		return 0, fmt.Errorf("kafka: produce response missing %s/%d", topic, partition)
+	}
+	return errCode, nil
+}
+
+type pendingMessage struct {
+	msg  Message
+	done chan error
+}
+
+// batcher owns one partition. It sends one request at a time, so batches are
+// appended in publish order and later messages queue up behind the in-flight one.
+type batcher struct {
+	p         *Producer
+	topic     string
+	partition int32
+	queue     chan *pendingMessage
+	stopped   chan struct{}
+}
+
+func (b *batcher) run() {
+	defer b.p.wg.Done()
+	defer close(b.stopped)
+
+	for {
+		select {
+		case first := <-b.queue:
+			b.send(b.collect(first))
+		case <-b.p.quit:
+			b.flush()
+			return
+		}
+	}
+}
+
+// collect gathers messages behind first until the batch is full, waiting up to
+// Linger for stragglers.
+func (b *batcher) collect(first *pendingMessage) []*pendingMessage {
+	batch := []*pendingMessage{first}
+	size := messageSize(first.msg)
+
+	if b.p.cfg.Linger > 0 {
+		timer := time.NewTimer(b.p.cfg.Linger)
+		defer timer.Stop()
+	linger:
+		for size < b.p.cfg.BatchMaxBytes {
+			select {
+			case next := <-b.queue:
+				batch = append(batch, next)
+				size += messageSize(next.msg)
+			case <-timer.C:
+				break linger
+			case <-b.p.quit:
+				break linger
+			}
+		}
+	}
+
+	for size < b.p.cfg.BatchMaxBytes {
+		select {
+		case next := <-b.queue:
+			batch = append(batch, next)
+			size += messageSize(next.msg)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// flush sends whatever is still queued when the producer closes.
+func (b *batcher) flush() {
+	for {
+		select {
+		case first := <-b.queue:
+			b.send(b.collect(first))
+		default:
+			return
+		}
+	}
+}
+
+func (b *batcher) send(batch []*pendingMessage) {
+	msgs := make([]Message, len(batch))
+	for i, pm := range batch {
+		msgs[i] = pm.msg
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.p.cfg.RequestTimeout)
+	err := b.p.produce(ctx, b.topic, b.partition, msgs)
+	cancel()
+
+	for _, pm := range batch {
+		pm.done <- err
+	}
+}
+
+// messageSize approximates the encoded size of m for batch limits.
+func messageSize(m Message) int {
+	size := len(m.Key) + len(m.Value) + 16
+	for _, h := range m.Headers {
+		size += len(h.Key) + len(h.Value) + 4
+	}
+	return size
+}
@@ -0,0 +1,432 @@
+package kafka
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// fakeBroker is a single-node in-process cluster that understands the Metadata
+// and Produce requests sent by Producer and records what it receives.
+type fakeBroker struct {
+	t          *testing.T
+	ln         net.Listener
+	partitions int
+
+	mu               sync.Mutex
+	records          map[int32][]Message
+	codecs           []Compression
+	acks             []int16
+	produceRequests  int
+	metadataRequests int
+	// produceErrors are returned, in order, by the next produce requests.
+	produceErrors []int16
+}
+
+func newFakeBroker(t *testing.T, partitions int) *fakeBroker {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	f := &fakeBroker{t: t, ln: ln, partitions: partitions, records: make(map[int32][]Message)}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeBroker) addr() string { return f.ln.Addr().String() }
+
+func (f *fakeBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		body := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return
+		}
+
+		d := decoder{b: body}
+		apiKey, apiVersion, correlationID := d.int16(), d.int16(), d.int32()
+		d.string() // client_id
+
+		var (
+			resp    []byte
+			respond = true
+		)
+		switch {
+		case apiKey == apiKeyMetadata && apiVersion == metadataVersion:
+			resp = f.metadata(&d)
+		case apiKey == apiKeyProduce && apiVersion == produceVersion:
+			resp, respond = f.produce(&d)
+		default:
+			f.t.Errorf("unexpected request api=%d version=%d", apiKey, apiVersion)
+			return
+		}
+		if !respond {
+			continue
+		}
+
+		e := encoder{b: make([]byte, 4)}
+		e.int32(correlationID)
+		e.b = append(e.b, resp...)
+		binary.BigEndian.PutUint32(e.b, uint32(len(e.b)-4))
+		if _, err := conn.Write(e.b); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeBroker) metadata(d *decoder) []byte {
+	var topics []string
+	for range d.arrayLen() {
+		topics = append(topics, d.string())
+	}
+
+	f.mu.Lock()
+	f.metadataRequests++
+	f.mu.Unlock()
+
+	host, portStr, _ := net.SplitHostPort(f.addr())
+	port, _ := strconv.Atoi(portStr)
+
+	var e encoder
+	e.int32(0) // throttle_time_ms
+	e.int32(1)
+	e.int32(0)
+	e.string(host)
+	e.int32(int32(port))
+	e.int16(-1) // rack
+	e.int16(-1) // cluster_id
+	e.int32(0)  // controller_id
+	e.int32(int32(len(topics)))
+	for _, topic := range topics {
+		e.int16(0)
+		e.string(topic)
+		e.bool(false)
+		e.int32(int32(f.partitions))
+		for i := range f.partitions {
+			e.int16(0)
+			e.int32(int32(i))
+			e.int32(0) // leader
+			e.int32(1) // replicas
+			e.int32(0)
+			e.int32(1) // isr
+			e.int32(0)
+		}
+	}
+	return e.b
+}
+
+func (f *fakeBroker) produce(d *decoder) ([]byte, bool) {
+	d.string() // transactional_id
+	acks := d.int16()
+	d.int32() // timeout_ms
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.produceRequests++
+	f.acks = append(f.acks, acks)
+
+	var errCode int16
+	if len(f.produceErrors) > 0 {
+		errCode, f.produceErrors = f.produceErrors[0], f.produceErrors[1:]
+	}
+
+	var e encoder
+	topics := d.arrayLen()
+	e.int32(int32(topics))
+	for range topics {
+		e.string(d.string())
+		partitions := d.arrayLen()
+		e.int32(int32(partitions))
+		for range partitions {
+			partition := d.int32()
+			batch := d.bytes()
+			baseOffset := int64(len(f.records[partition]))
+			if errCode == 0 {
+				msgs, codec := f.decodeRecordBatch(batch)
+				f.records[partition] = append(f.records[partition], msgs...)
+				f.codecs = append(f.codecs, codec)
+			}
+			e.int32(partition)
+			e.int16(errCode)
+			e.int64(baseOffset)
+			e.int64(-1) // log_append_time_ms
+			e.int64(0)  // log_start_offset
+		}
+	}
+	e.int32(0) // throttle_time_ms
+	if d.err != nil {
+		f.t.Errorf("decode produce request: %v", d.err)
+	}
+	return e.b, acks != AcksNone.wire()
+}
+
+func (f *fakeBroker) decodeRecordBatch(batch []byte) ([]Message, Compression) {
+	d := decoder{b: batch}
+	d.int64() // base offset
+	if length := d.int32(); int(length) != len(d.b) {
+		f.t.Errorf("batch length = %d, want %d", length, len(d.b))
+	}
+	d.int32() // leader epoch
+	if magic := d.int8(); magic != recordBatchMagic {
+		f.t.Errorf("magic = %d", magic)
+	}
+	crc := uint32(d.int32())
+	if got := crc32.Checksum(d.b, castagnoli); got != crc {
+		f.t.Errorf("crc = %x, want %x", crc, got)
+	}
+	codec := Compression(d.int16() & 0x7)
+	d.int32() // last offset delta
+	baseTime := d.int64()
+	d.int64() // max timestamp
+	d.int64() // producer id
+	d.int16() // producer epoch
+	d.int32() // base sequence
+	count := int(d.int32())
+
+	payload := d.b
+	switch codec {
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			f.t.Errorf("gzip: %v", err)
+			return nil, codec
+		}
+		payload, _ = io.ReadAll(r)
+	case CompressionZstd:
+		dec, _ := zstd.NewReader(nil)
+		defer dec.Close()
+		var err error
+		if payload, err = dec.DecodeAll(payload, nil); err != nil {
+			f.t.Errorf("zstd: %v", err)
+			return nil, codec
+		}
+	}
+
+	rd := decoder{b: payload}
+	msgs := make([]Message, 0, count)
+	for range count {
+		rd.varint() // length
+		rd.int8()   // attributes
+		m := Message{Time: time.UnixMilli(baseTime + rd.varint())}
+		rd.varint() // offset delta
+		m.Key = rd.varintBytes()
+		m.Value = rd.varintBytes()
+		for range rd.varint() {
+			m.Headers = append(m.Headers, Header{Key: string(rd.varintBytes()), Value: rd.varintBytes()})
+		}
+		msgs = append(msgs, m)
+	}
+	if d.err != nil || rd.err != nil {
+		f.t.Errorf("decode record batch: %v %v", d.err, rd.err)
+	}
+	return msgs, codec
+}
+
+func (f *fakeBroker) snapshot() (map[int32][]Message, []Compression, int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	records := make(map[int32][]Message, len(f.records))
+	for p, msgs := range f.records {
+		records[p] = append([]Message(nil), msgs...)
+	}
+	return records, append([]Compression(nil), f.codecs...), f.produceRequests, f.metadataRequests
+}
+
+func newTestProducer(t *testing.T, cfg Config) *Producer {
+	t.Helper()
+	p, err := NewProducer(cfg)
+	if err != nil {
+		t.Fatalf("NewProducer: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func TestProducer_PartitionsByKeyAndPreservesOrder(t *testing.T) {
+	for _, codec := range []Compression{CompressionNone, CompressionGzip, CompressionZstd} {
+		t.Run(codec.String(), func(t *testing.T) {
+			broker := newFakeBroker(t, 3)
+			producer := newTestProducer(t, Config{Brokers: []string{broker.addr()}, Acks: AcksAll, Compression: codec})
+
+			keys := []string{"urn:user:1", "urn:user:2", "urn:skill:go", "urn:skill:rdf"}
+			const perKey = 25
+
+			var wg sync.WaitGroup
+			errs := make(chan error, len(keys))
+			for _, key := range keys {
+				wg.Go(func() {
+					for i := range perKey {
+						err := producer.Publish(context.Background(), "ontology", Message{
+							Key:     []byte(key),
+							Value:   []byte(strconv.Itoa(i)),
+							Headers: []Header{{Key: "event-type", Value: []byte("UserUpdated")}},
+						})
+						if err != nil {
+							errs <- err
+							return
+						}
+					}
+				})
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				t.Fatalf("Publish: %v", err)
+			}
+
+			records, codecs, _, _ := broker.snapshot()
+			next := make(map[string]int)
+			total := 0
+			for partition, msgs := range records {
+				for _, m := range msgs {
+					key := string(m.Key)
+					if want := partitionFor(m.Key, 3, 0); int32(want) != partition {
+						t.Errorf("key %s landed on partition %d, want %d", key, partition, want)
+					}
+					if got := string(m.Value); got != strconv.Itoa(next[key]) {
+						t.Errorf("key %s: got value %s, want %d", key, got, next[key])
+					}
+					next[key]++
+					if len(m.Headers) != 1 || m.Headers[0].Key != "event-type" || string(m.Headers[0].Value) != "UserUpdated" {
+						t.Errorf("headers = %+v", m.Headers)
+					}
+					total++
+				}
+			}
+			if total != len(keys)*perKey {
+				t.Errorf("broker received %d records, want %d", total, len(keys)*perKey)
+			}
+			for _, c := range codecs {
+				if c != codec {
+					t.Errorf("batch codec = %s, want %s", c, codec)
+				}
+			}
+		})
+	}
+}
+
+func TestProducer_LingerBatchesConcurrentPublishes(t *testing.T) {
+	broker := newFakeBroker(t, 1)
+	producer := newTestProducer(t, Config{Brokers: []string{broker.addr()}, Linger: 200 * time.Millisecond})
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			if err := producer.Publish(context.Background(), "ontology", Message{Key: []byte("k"), Value: []byte{byte(i)}}); err != nil {
+				t.Errorf("Publish: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	records, _, produceRequests, _ := broker.snapshot()
+	if len(records[0]) != 10 {
+		t.Fatalf("broker received %d records, want 10", len(records[0]))
+	}
+	if produceRequests != 1 {
+		t.Errorf("produce requests = %d, want 1", produceRequests)
+	}
+}
+
+func TestProducer_ReportsBrokerErrorsAndRefreshesMetadata(t *testing.T) {
+	broker := newFakeBroker(t, 2)
+	broker.produceErrors = []int16{errCodeNotLeaderOrFollower}
+	producer := newTestProducer(t, Config{Brokers: []string{broker.addr()}})
+
+	err := producer.Publish(context.Background(), "ontology", Message{Key: []byte("k"), Value: []byte("v")})
+	var kerr *Error
+	if !errors.As(err, &kerr) || kerr.Code != errCodeNotLeaderOrFollower || !kerr.Retriable() {
+		t.Fatalf("Publish error = %v, want retriable NOT_LEADER_OR_FOLLOWER", err)
+	}
+
+	if err := producer.Publish(context.Background(), "ontology", Message{Key: []byte("k"), Value: []byte("v")}); err != nil {
+		t.Fatalf("second Publish: %v", err)
+	}
+	if _, _, _, metadataRequests := broker.snapshot(); metadataRequests != 2 {
+		t.Errorf("metadata requests = %d, want 2 after a leadership error", metadataRequests)
+	}
+}
+
+func TestProducer_AcksNoneDoesNotWaitForResponse(t *testing.T) {
+	broker := newFakeBroker(t, 1)
+	producer := newTestProducer(t, Config{Brokers: []string{broker.addr()}, Acks: AcksNone})
+
+	for i := range 3 {
+		if err := producer.Publish(context.Background(), "ontology", Message{Value: []byte(fmt.Sprint(i))}); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		records, _, _, _ := broker.snapshot()
+		if len(records[0]) == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("broker received %d records, want 3", len(records[0]))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	for _, acks := range broker.acks {
+		if acks != AcksNone.wire() {
+			t.Errorf("acks = %d, want 0", acks)
+		}
+	}
+}
+
+func TestProducer_CloseRejectsPublish(t *testing.T) {
+	broker := newFakeBroker(t, 1)
+	producer := newTestProducer(t, Config{Brokers: []string{broker.addr()}})
+	if err := producer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := producer.Publish(context.Background(), "ontology", Message{Value: []byte("v")}); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Publish after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestMurmur2MatchesJavaClient(t *testing.T) {
+	// Vectors from the Java client's Utils.murmur2 tests.
+	cases := map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+	}
+	for input, want := range cases {
+		if got := murmur2([]byte(input)); got != want {
+			t.Errorf("murmur2(%q) = %d, want %d", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// API keys and versions spoken by the producer. Produce v7 and Metadata v4 are
+// supported by every broker since Kafka 2.1 and are still accepted by 4.x.
+const (
+	apiKeyProduce  int16 = 0
+	apiKeyMetadata int16 = 3
+
+	produceVersion  int16 = 7
+	metadataVersion int16 = 4
+)
+
+var errShortBuffer = errors.New("kafka: short buffer")
+
+// encoder appends big-endian protocol primitives to a byte slice.
+type encoder struct {
+	b []byte
+}
+
+func (e *encoder) int8(v int8)   { e.b = append(e.b, byte(v)) }
+func (e *encoder) int16(v int16) { e.b = binary.BigEndian.AppendUint16(e.b, uint16(v)) }
+func (e *encoder) int32(v int32) { e.b = binary.BigEndian.AppendUint32(e.b, uint32(v)) }
+func (e *encoder) int64(v int64) { e.b = binary.BigEndian.AppendUint64(e.b, uint64(v)) }
+
+func (e *encoder) bool(v bool) {
+	if v {
+		e.int8(1)
+		return
+	}
+	e.int8(0)
+}
+
+func (e *encoder) string(s string) {
+	e.int16(int16(len(s)))
+	e.b = append(e.b, s...)
+}
+
+func (e *encoder) nullableString(s *string) {
+	if s == nil {
+		e.int16(-1)
+		return
+	}
+	e.string(*s)
+}
+
+func (e *encoder) bytes(b []byte) {
+	e.int32(int32(len(b)))
+	e.b = append(e.b, b...)
+}
+
+// varint writes a zig-zag encoded variable-length integer as used inside record batches.
+func (e *encoder) varint(v int64) { e.b = binary.AppendVarint(e.b, v) }
+
+// varintBytes writes a varint length prefix followed by b; nil encodes as -1.
+func (e *encoder) varintBytes(b []byte) {
+	if b == nil {
+		e.varint(-1)
+		return
+	}
+	e.varint(int64(len(b)))
+	e.b = append(e.b, b...)
+}
+
+// decoder reads protocol primitives, recording the first error and returning
+// zero values afterwards so callers can check err once at the end.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.b) < n {
+		d.err = errShortBuffer
+		return nil
+	}
+	out := d.b[:n]
+	d.b = d.b[n:]
+	return out
+}
+
+func (d *decoder) int8() int8 {
+	if b := d.take(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (d *decoder) int16() int16 {
+	if b := d.take(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *decoder) int32() int32 {
+	if b := d.take(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *decoder) int64() int64 {
+	if b := d.take(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (d *decoder) bool() bool { return d.int8() != 0 }
+
+func (d *decoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.take(int(n)))
+}
+
+func (d *decoder) bytes() []byte {
+	n := d.int32()
+	if n < 0 {
+		return nil
+	}
+	return d.take(int(n))
+}
+
+// arrayLen reads an array length, treating null arrays as empty.
+func (d *decoder) arrayLen() int {
+	n := d.int32()
+	if n < 0 || d.err != nil {
+		return 0
+	}
+	// Every element is at least one byte; anything larger is corrupt.
+	if int(n) > len(d.b) {
+		d.err = errShortBuffer
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = errShortBuffer
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) varintBytes() []byte {
+	n := d.varint()
+	if n < 0 {
+		return nil
+	}
+	return d.take(int(n))
+}
+
+// encodeRequest frames body with a v1 request header and the size prefix.
+func encodeRequest(apiKey, apiVersion int16, correlationID int32, clientID string, body []byte) []byte {
+	e := encoder{b: make([]byte, 4, 4+10+len(clientID)+len(body))}
+	e.int16(apiKey)
+	e.int16(apiVersion)
+	e.int32(correlationID)
+	e.string(clientID)
+	e.b = append(e.b, body...)
+	binary.BigEndian.PutUint32(e.b[:4], uint32(len(e.b)-4))
+	return e.b
+}
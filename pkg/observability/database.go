@@ -0,0 +1,152 @@
+package observability
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// DBQueryDuration tracks query latency per named statement
+	DBQueryDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "skillsphere_db_query_duration_seconds",
+			Help:    "Database query duration in seconds",
+			Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		},
+		[]string{"pool", "query", "status"},
+	)
+
+	// DBSlowQueriesTotal counts queries that exceeded the slow-query threshold
+	DBSlowQueriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "skillsphere_db_slow_queries_total",
+			Help: "Total number of database queries slower than the configured threshold",
+		},
+		[]string{"pool", "query"},
+	)
+
+	// DBReadRoutesTotal counts where read-only queries were routed and why
+	DBReadRoutesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "skillsphere_db_read_routes_total",
+			Help: "Total number of read-only query routings by target pool and reason",
+		},
+		[]string{"pool", "reason"},
+	)
+
+	// DBReplicaLagSeconds tracks the observed replication lag per replica
+	DBReplicaLagSeconds = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "skillsphere_db_replica_lag_seconds",
+			Help: "Observed replication lag of a read replica in seconds",
+		},
+		[]string{"pool"},
+	)
+
+	// DBReplicaHealthy reports whether a replica passed its last health check
+	DBReplicaHealthy = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "skillsphere_db_replica_healthy",
+			Help: "Whether a read replica passed its last health check (1) or not (0)",
+		},
+		[]string{"pool"},
+	)
+)
+
+// PoolStatsCollector exports pgxpool statistics as Prometheus metrics.
+type PoolStatsCollector struct {
+	stat func() *pgxpool.Stat
+
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	constructingConns    *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquireCount         *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	emptyAcquireCount    *prometheus.Desc
+	emptyAcquireWaitTime *prometheus.Desc
+	canceledAcquireCount *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*PoolStatsCollector)(nil)
+
+// NewPoolStatsCollector creates a collector that reads stat on every scrape.
+// The pool name is attached as a constant label so several pools can be exported side by side.
+func NewPoolStatsCollector(pool string, stat func() *pgxpool.Stat) *PoolStatsCollector {
+	labels := prometheus.Labels{"pool": pool}
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("skillsphere_db_pool_"+name, help, nil, labels)
+	}
+
+	return &PoolStatsCollector{
+		stat:                 stat,
+		acquiredConns:        desc("acquired_conns", "Number of currently acquired connections"),
+		idleConns:            desc("idle_conns", "Number of currently idle connections"),
+		constructingConns:    desc("constructing_conns", "Number of connections being established"),
+		totalConns:           desc("total_conns", "Total number of connections in the pool"),
+		maxConns:             desc("max_conns", "Maximum size of the pool"),
+		acquireCount:         desc("acquire_total", "Cumulative count of successful acquires"),
+		acquireDuration:      desc("acquire_duration_seconds_total", "Cumulative time spent acquiring connections"),
+		emptyAcquireCount:    desc("wait_total", "Cumulative count of acquires that waited for a connection"),
+		emptyAcquireWaitTime: desc("wait_duration_seconds_total", "Cumulative time spent waiting for a connection"),
+		canceledAcquireCount: desc("canceled_acquire_total", "Cumulative count of acquires canceled by context"),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *PoolStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.constructingConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquireCount
+	ch <- c.emptyAcquireWaitTime
+	ch <- c.canceledAcquireCount
+}
+
+// Collect implements prometheus.Collector.
+func (c *PoolStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.stat == nil {
+		return
+	}
+	s := c.stat()
+	if s == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.constructingConns, prometheus.GaugeValue, float64(s.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireCount, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireWaitTime, prometheus.CounterValue, s.EmptyAcquireWaitTime().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquireCount, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+}
+
+// RegisterPoolStats registers a pool collector with the default Prometheus registry.
+// Registering a pool name again replaces the earlier collector, so a pool
+// re-created under the same name is exported instead of the closed one.
+func RegisterPoolStats(pool string, stat func() *pgxpool.Stat) error {
+	return registerPoolStats(prometheus.DefaultRegisterer, pool, stat)
+}
+
+func registerPoolStats(registerer prometheus.Registerer, pool string, stat func() *pgxpool.Stat) error {
+	collector := NewPoolStatsCollector(pool, stat)
+	err := registerer.Register(collector)
+	var already prometheus.AlreadyRegisteredError
+	if !errors.As(err, &already) {
+		return err
+	}
+	registerer.Unregister(already.ExistingCollector)
+	return registerer.Register(collector)
+}
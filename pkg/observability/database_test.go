@@ -0,0 +1,28 @@
+package observability
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRegisterPoolStats_ReplacesCollector(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	var oldScrapes, newScrapes int
+	oldStat := func() *pgxpool.Stat { oldScrapes++; return nil }
+	newStat := func() *pgxpool.Stat { newScrapes++; return nil }
+
+	if err := registerPoolStats(registry, "primary", oldStat); err != nil {
+		t.Fatal(err)
+	}
+	if err := registerPoolStats(registry, "primary", newStat); err != nil {
+		t.Fatalf("re-registering a pool = %v", err)
+	}
+	if _, err := registry.Gather(); err != nil {
+		t.Fatal(err)
+	}
+	if oldScrapes != 0 || newScrapes != 1 {
+		t.Errorf("scrapes of the old pool = %d, of the new = %d; want the new pool exported", oldScrapes, newScrapes)
+	}
+}
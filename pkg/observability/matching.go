@@ -0,0 +1,32 @@
+package observability
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// SkillVectorsRefreshedTotal counts users whose skill vectors were rebuilt
+var SkillVectorsRefreshedTotal = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "skillsphere_skill_vectors_refreshed_total",
+		Help: "Total number of users whose user_skill_vectors row was rebuilt from the refresh queue",
+	},
+)
+
+// RecommendationCacheRequestsTotal counts recommendation requests by how the
+// cache answered: fresh, stale or miss
+var RecommendationCacheRequestsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "skillsphere_recommendation_cache_requests_total",
+		Help: "Total number of cached recommendation requests by item type and cache result",
+	},
+	[]string{"item_type", "result"},
+)
+
+// RecommendationsRefreshedTotal counts users whose cached recommendations were recomputed
+var RecommendationsRefreshedTotal = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "skillsphere_recommendations_refreshed_total",
+		Help: "Total number of users whose cached recommendations were recomputed from the refresh queue",
+	},
+)
@@ -0,0 +1,124 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// OntologyOutboxEventsTotal counts outbox dispatch outcomes
+	OntologyOutboxEventsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "skillsphere_ontology_outbox_events_total",
+			Help: "Total number of ontology outbox dispatch attempts by result",
+		},
+		[]string{"result"},
+	)
+
+	// OntologyOutboxPrunedTotal counts delivered outbox rows removed by the retention job
+	OntologyOutboxPrunedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "skillsphere_ontology_outbox_pruned_total",
+			Help: "Total number of delivered ontology outbox rows removed by retention, by mode",
+		},
+		[]string{"mode"},
+	)
+
+	// OntologyOutboxDeliveryLag tracks the time from enqueue to delivery
+	OntologyOutboxDeliveryLag = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "skillsphere_ontology_outbox_delivery_lag_seconds",
+			Help:    "Time between an ontology event being enqueued and delivered, in seconds",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300, 900, 3600},
+		},
+	)
+
+	// OntologyIndexTriples tracks the size of the in-process query index
+	OntologyIndexTriples = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "skillsphere_ontology_index_triples",
+			Help: "Number of triples stored in the in-process ontology query index",
+		},
+	)
+)
+
+// statsScrapeTimeout bounds the query run on each scrape.
+const statsScrapeTimeout = 5 * time.Second
+
+// OutboxStats is a snapshot of the ontology outbox backlog.
+type OutboxStats struct {
+	Pending      int64
+	DeadLettered int64
+	Quarantined  int64
+	// OldestPending is the age of the oldest pending row; zero when none are pending.
+	OldestPending time.Duration
+}
+
+// OutboxStatsCollector exports the outbox backlog as Prometheus gauges.
+// Delivery rate is rate(skillsphere_ontology_outbox_events_total{result="delivered"}).
+type OutboxStatsCollector struct {
+	stat func(ctx context.Context) (OutboxStats, error)
+
+	pending       *prometheus.Desc
+	deadLettered  *prometheus.Desc
+	quarantined   *prometheus.Desc
+	oldestPending *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*OutboxStatsCollector)(nil)
+
+// NewOutboxStatsCollector creates a collector that calls stat on every scrape.
+func NewOutboxStatsCollector(stat func(ctx context.Context) (OutboxStats, error)) *OutboxStatsCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("skillsphere_ontology_outbox_"+name, help, nil, nil)
+	}
+	return &OutboxStatsCollector{
+		stat:          stat,
+		pending:       desc("pending", "Number of ontology outbox rows waiting for delivery"),
+		deadLettered:  desc("dead_lettered", "Number of dead-lettered ontology outbox rows"),
+		quarantined:   desc("quarantined", "Number of quarantined ontology outbox rows"),
+		oldestPending: desc("oldest_pending_age_seconds", "Age of the oldest pending ontology outbox row in seconds"),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *OutboxStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.pending
+	ch <- c.deadLettered
+	ch <- c.quarantined
+	ch <- c.oldestPending
+}
+
+// Collect implements prometheus.Collector. Nothing is reported when the
+// query fails, so the gauges go stale rather than drop to zero.
+func (c *OutboxStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.stat == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), statsScrapeTimeout)
+	defer cancel()
+	s, err := c.stat(ctx)
+	if err != nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(s.Pending))
+	ch <- prometheus.MustNewConstMetric(c.deadLettered, prometheus.GaugeValue, float64(s.DeadLettered))
+	ch <- prometheus.MustNewConstMetric(c.quarantined, prometheus.GaugeValue, float64(s.Quarantined))
+	ch <- prometheus.MustNewConstMetric(c.oldestPending, prometheus.GaugeValue, s.OldestPending.Seconds())
+}
+
+// RegisterOutboxStats registers an outbox collector with the default Prometheus
+// registry. Registering twice is a no-op.
+func RegisterOutboxStats(stat func(ctx context.Context) (OutboxStats, error)) error {
+	err := prometheus.Register(NewOutboxStatsCollector(stat))
+	var already prometheus.AlreadyRegisteredError
+	if errors.As(err, &already) {
+		return nil
+	}
+	return err
+}